@@ -3,12 +3,14 @@ package main
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	api_handler "github.com/098765432m/grpc-kafka/api-gateway/internal/handler"
 	"github.com/098765432m/grpc-kafka/common/consts"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/image_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/loyalty_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/rating_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
@@ -16,6 +18,8 @@ import (
 	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
 	"github.com/098765432m/grpc-kafka/common/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/subosito/gotenv"
 	"go.uber.org/zap"
 )
 
@@ -23,8 +27,26 @@ func main() {
 	// Init logger
 	zap.ReplaceGlobals(zap.Must(zap.NewDevelopment()))
 
+	// JWT secret to verify signed in users
+	if err := gotenv.Load("../.env"); err != nil {
+		zap.S().Fatal("Error loading .env file: ", err)
+	}
+	viper.AutomaticEnv()
+
 	router := gin.Default()
 
+	// Client IP used by rate limits is read from X-Forwarded-For only when the request comes
+	// from one of these comma separated proxies, otherwise it is the connection address
+	var trustedProxies []string
+	for _, proxy := range strings.Split(viper.GetString("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		zap.S().Fatal("Invalid TRUSTED_PROXIES: ", err)
+	}
+
 	// CORS config
 	router.Use(common_middleware.CorsMiddleware())
 
@@ -57,6 +79,9 @@ func main() {
 	bookingConn := utils.NewGrpcClient(strconv.Itoa(consts.BOOKING_GRPC_PORT))
 	defer bookingConn.Close()
 
+	loyaltyConn := utils.NewGrpcClient(strconv.Itoa(consts.LOYALTY_GRPC_PORT))
+	defer loyaltyConn.Close()
+
 	hotelClient := hotel_pb.NewHotelServiceClient(hotelConn)
 	roomTypeClient := room_type_pb.NewRoomTypeServiceClient(roomTypeConn)
 	roomClient := room_pb.NewRoomServiceClient(roomConn)
@@ -64,8 +89,9 @@ func main() {
 	imageClient := image_pb.NewImageServiceClient(imageConn)
 	ratingClient := rating_pb.NewRatingServiceClient(ratingConn)
 	bookingClient := booking_pb.NewBookingServiceClient(bookingConn)
+	loyaltyClient := loyalty_pb.NewLoyaltyServiceClient(loyaltyConn)
 
-	userHandler := api_handler.NewUserHandler(userClient, imageClient, bookingClient)
+	userHandler := api_handler.NewUserHandler(userClient, imageClient, bookingClient, hotelClient)
 	userHandler.RegisterRoutes(api)
 
 	hotelHandler := api_handler.NewHotelHandler(&api_handler.HotelHandlerImpl{
@@ -79,7 +105,7 @@ func main() {
 	})
 	hotelHandler.RegisterRoutes(api)
 
-	roomTypeHandler := api_handler.NewRoomTypeHandler(roomTypeClient, roomClient)
+	roomTypeHandler := api_handler.NewRoomTypeHandler(roomTypeClient, roomClient, hotelClient)
 	roomTypeHandler.RegisterRoutes(api)
 
 	roomHandler := api_handler.NewRoomHandler(roomClient)
@@ -88,9 +114,30 @@ func main() {
 	imageHandler := api_handler.NewImageHandler(imageClient)
 	imageHandler.RegisterRoutes(api)
 
-	bookingHandler := api_handler.NewBookingHandler(bookingClient, roomClient)
+	bookingHandler := api_handler.NewBookingHandler(bookingClient, userClient)
 	bookingHandler.RegisterRoutes(api)
 
+	icalHandler := api_handler.NewIcalHandler(bookingClient, roomClient, hotelClient)
+	icalHandler.RegisterRoutes(api)
+
+	companyHandler := api_handler.NewCompanyHandler(userClient, bookingClient)
+	companyHandler.RegisterRoutes(api)
+
+	loyaltyHandler := api_handler.NewLoyaltyHandler(loyaltyClient, hotelClient)
+	loyaltyHandler.RegisterRoutes(api)
+
+	giftCardHandler := api_handler.NewGiftCardHandler(bookingClient)
+	giftCardHandler.RegisterRoutes(api)
+
+	amenityHandler := api_handler.NewAmenityHandler(hotelClient)
+	amenityHandler.RegisterRoutes(api)
+
+	hotelPolicyHandler := api_handler.NewHotelPolicyHandler(hotelClient)
+	hotelPolicyHandler.RegisterRoutes(api)
+
+	hotelChainHandler := api_handler.NewHotelChainHandler(hotelClient, roomTypeClient, roomClient, userClient, bookingClient)
+	hotelChainHandler.RegisterRoutes(api)
+
 	zap.S().Infoln("Running api-gateway on port ", consts.API_GATEWAY_PORT)
 
 	if err := router.Run(fmt.Sprintf(":%d", consts.API_GATEWAY_PORT)); err != nil {
@@ -0,0 +1,50 @@
+package api_dto
+
+import "github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+
+type HotelChainResponse struct {
+	Id          string          `json:"id"`
+	Name        string          `json:"name"`
+	Brand       string          `json:"brand,omitempty"`
+	Description string          `json:"description"`
+	CreateAt    string          `json:"create_at"`
+	Hotels      []HotelResponse `json:"hotels,omitempty"` // only set for a single chain
+}
+
+// Rooms still free between check_in and check_out in every hotel of the chain
+type ChainAvailabilityResponse struct {
+	CheckIn        string                   `json:"check_in"`
+	CheckOut       string                   `json:"check_out"`
+	TotalRooms     int32                    `json:"total_rooms"`
+	AvailableRooms int32                    `json:"available_rooms"`
+	Hotels         []ChainHotelAvailability `json:"hotels"`
+}
+
+type ChainHotelAvailability struct {
+	HotelId        string                      `json:"hotel_id"`
+	Name           string                      `json:"name"`
+	Status         string                      `json:"status"`
+	TotalRooms     int32                       `json:"total_rooms"`
+	AvailableRooms int32                       `json:"available_rooms"`
+	RoomTypes      []ChainRoomTypeAvailability `json:"room_types"`
+}
+
+type ChainRoomTypeAvailability struct {
+	Id                     string `json:"id"`
+	Name                   string `json:"name"`
+	Price                  uint32 `json:"price"`
+	NumberOfRooms          int32  `json:"number_of_rooms"`
+	NumberOfAvailableRooms int32  `json:"number_of_available_rooms"`
+}
+
+// Totals are the whole chain per period, each hotel only has its own total per period
+type ChainAnalyticsResponse struct {
+	Totals []*booking_pb.AnalyticsPeriod `json:"totals"`
+	Hotels []ChainHotelAnalytics         `json:"hotels"`
+}
+
+type ChainHotelAnalytics struct {
+	HotelId string                        `json:"hotel_id"`
+	Name    string                        `json:"name"`
+	Periods []*booking_pb.AnalyticsPeriod `json:"periods"`
+}
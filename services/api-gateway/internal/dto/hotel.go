@@ -7,9 +7,43 @@ type HotelImage struct {
 	Format   string `json:"format"`
 }
 
+type HotelLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
 type HotelResponse struct {
-	Id      string       `json:"id"`
-	Name    string       `json:"name"`
-	Address string       `json:"address"`
-	Images  []HotelImage `json:"images"`
+	Id          string         `json:"id"`
+	Name        string         `json:"name"`
+	Address     string         `json:"address"`
+	City        string         `json:"city"`
+	Description string         `json:"description"`
+	Location    *HotelLocation `json:"location,omitempty"`
+	Status      string         `json:"status"` // DRAFT, SUBMITTED, APPROVED, REJECTED or PUBLISHED
+	ChainId     string         `json:"chain_id,omitempty"`
+	Images      []HotelImage   `json:"images"`
+	Amenities   []Amenity      `json:"amenities"`
+	Policy      *HotelPolicy   `json:"policy"` // null when the hotel has no policy yet
+}
+
+type Amenity struct {
+	Id       string            `json:"id"`
+	Code     string            `json:"code"`
+	Category string            `json:"category"`
+	Labels   map[string]string `json:"labels"` // locale -> label
+}
+
+// Clock times are HH:MM local to the hotel
+type HotelPolicy struct {
+	CheckInFrom        string            `json:"check_in_from"`
+	CheckInUntil       string            `json:"check_in_until,omitempty"`
+	CheckOutUntil      string            `json:"check_out_until"`
+	PetsAllowed        bool              `json:"pets_allowed"`
+	PetFee             int               `json:"pet_fee"`
+	ChildrenAllowed    bool              `json:"children_allowed"`
+	ChildrenFreeMaxAge *int              `json:"children_free_max_age"`
+	ExtraBedAvailable  bool              `json:"extra_bed_available"`
+	ExtraBedFee        int               `json:"extra_bed_fee"`
+	PaymentMethods     []string          `json:"payment_methods"`
+	HouseRules         map[string]string `json:"house_rules"` // locale -> house rules
 }
@@ -0,0 +1,8 @@
+package api_dto
+
+// Page of a list, next_cursor is sent back as cursor for the next page and is empty on the last page
+type PageResponse[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	TotalCount int64  `json:"total_count"`
+}
@@ -14,9 +14,10 @@ type RatingUserResponse struct {
 }
 
 type RatingResponse struct {
-	Id      string             `json:"id"`
-	Rating  int                `json:"rating"`
-	HotelId string             `json:"hotel_id"`
-	User    RatingUserResponse `json:"user"`
-	Comment string             `json:"comment"`
+	Id       string             `json:"id"`
+	Rating   int                `json:"rating"`
+	HotelId  string             `json:"hotel_id"`
+	User     RatingUserResponse `json:"user"`
+	Comment  string             `json:"comment"`
+	CreateAt string             `json:"create_at"`
 }
@@ -8,12 +8,23 @@ type RoomTypeImage struct {
 	RoomTypeId string `json:"room_type_id"`
 }
 
+type RoomTypeBed struct {
+	BedType  string `json:"bed_type"`
+	Quantity int    `json:"quantity"`
+}
+
 type RoomTypeResponse struct {
-	Id      string          `json:"id"`
-	Name    string          `json:"name"`
-	Price   uint            `json:"price"`
-	HotelId string          `json:"hotel_id"`
-	Images  []RoomTypeImage `json:"images"`
+	Id             string          `json:"id"`
+	Name           string          `json:"name"`
+	Price          uint            `json:"price"`
+	HotelId        string          `json:"hotel_id"`
+	AreaSqm        int             `json:"area_sqm,omitempty"`
+	View           string          `json:"view"`
+	SmokingAllowed bool            `json:"smoking_allowed"`
+	MaxOccupancy   int             `json:"max_occupancy"`
+	Description    string          `json:"description,omitempty"`
+	Beds           []RoomTypeBed   `json:"beds"`
+	Images         []RoomTypeImage `json:"images"`
 }
 
 type GetNumberOfAvailableRoomsDtoResponse struct {
@@ -15,5 +15,6 @@ type UserResponse struct {
 	FullName    string     `json:"full_name"`
 	Role        string     `json:"role,omitempty"`
 	HotelId     string     `json:"hotelId,omitempty"`
+	ChainId     string     `json:"chainId,omitempty"`
 	Image       *UserImage `json:"image,omitempty"`
 }
@@ -0,0 +1,29 @@
+package api_handler
+
+import (
+	"context"
+
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Context that sends the signed in user as actor to the called service, guests are "anonymous"
+func actorContext(ctx *gin.Context) context.Context {
+	actor := "anonymous"
+	if userId := ctx.GetString(common_middleware.AUTH_USER_ID_KEY); userId != "" {
+		actor = "user:" + userId
+	}
+
+	return utils.WithActor(ctx, actor)
+}
+
+// Verified JWT of the signed in user for services that check the user themselves, nothing is sent for guests
+func userContext(ctx *gin.Context) context.Context {
+	return utils.WithUserToken(ctx, ctx.GetString(common_middleware.AUTH_TOKEN_KEY))
+}
+
+// Actor and verified JWT of the signed in user, for changes made by services that check the user themselves
+func userActorContext(ctx *gin.Context) context.Context {
+	return utils.WithUserToken(actorContext(ctx), ctx.GetString(common_middleware.AUTH_TOKEN_KEY))
+}
@@ -0,0 +1,246 @@
+package api_handler
+
+import (
+	"net/http"
+
+	api_dto "github.com/098765432m/grpc-kafka/api-gateway/internal/dto"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Amenity catalog is managed by admins, managers assign amenities to their hotel and its room types
+type AmenityHandler struct {
+	hotelClient hotel_pb.HotelServiceClient
+}
+
+func NewAmenityHandler(hotelClient hotel_pb.HotelServiceClient) *AmenityHandler {
+	return &AmenityHandler{
+		hotelClient: hotelClient,
+	}
+}
+
+func (ah *AmenityHandler) RegisterRoutes(router *gin.RouterGroup) {
+	amenityHandler := router.Group("/amenities")
+
+	amenityHandler.GET("", ah.GetAmenities)
+	amenityHandler.GET("/:id", ah.GetAmenityById)
+	amenityHandler.POST("", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), ah.CreateAmenity)
+	amenityHandler.PUT("/:id", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), ah.UpdateAmenity)
+	amenityHandler.DELETE("/:id", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), ah.DeleteAmenity)
+
+	hotelHandler := router.Group("/hotels")
+
+	hotelHandler.GET("/:id/amenities", ah.GetHotelAmenities)
+	hotelHandler.PUT("/:id/amenities", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(ah.hotelClient), ah.SetHotelAmenities)
+	hotelHandler.GET("/:id/room-types/:roomTypeId/amenities", ah.GetRoomTypeAmenities)
+	hotelHandler.PUT("/:id/room-types/:roomTypeId/amenities", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(ah.hotelClient), ah.SetRoomTypeAmenities)
+}
+
+type AmenityBody struct {
+	Code     string            `json:"code" binding:"required,max=50"`
+	Category string            `json:"category"`                        // GENERAL when empty
+	Labels   map[string]string `json:"labels" binding:"required,min=1"` // locale -> label
+}
+
+type AmenityCodesBody struct {
+	Codes []string `json:"codes"` // replaces all assigned amenities, empty removes them
+}
+
+// Catalog, of one category with ?category=
+func (ah *AmenityHandler) GetAmenities(ctx *gin.Context) {
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := ah.hotelClient.GetAmenities(ctx, &hotel_pb.GetAmenitiesRequest{
+		Category: ctx.Query("category"),
+		Page:     page,
+	})
+	if err != nil {
+		respondAmenityError(ctx, err, "Loi khong lay duoc danh sach tien ich")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(toAmenitiesDto(result.GetAmenities()), result.GetPage()), "Thanh cong"))
+}
+
+func (ah *AmenityHandler) GetAmenityById(ctx *gin.Context) {
+	result, err := ah.hotelClient.GetAmenityById(ctx, &hotel_pb.GetAmenityByIdRequest{
+		Id: ctx.Param("id"),
+	})
+	if err != nil {
+		respondAmenityError(ctx, err, "Loi khong lay duoc tien ich")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toAmenityDto(result.GetAmenity()), "Thanh cong"))
+}
+
+func (ah *AmenityHandler) CreateAmenity(ctx *gin.Context) {
+	var reqBody AmenityBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	result, err := ah.hotelClient.CreateAmenity(ctx, &hotel_pb.CreateAmenityRequest{
+		Code:     reqBody.Code,
+		Category: reqBody.Category,
+		Labels:   reqBody.Labels,
+	})
+	if err != nil {
+		respondAmenityError(ctx, err, "Loi khong tao duoc tien ich")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(toAmenityDto(result.GetAmenity()), "Tao tien ich thanh cong"))
+}
+
+func (ah *AmenityHandler) UpdateAmenity(ctx *gin.Context) {
+	var reqBody AmenityBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	result, err := ah.hotelClient.UpdateAmenity(ctx, &hotel_pb.UpdateAmenityRequest{
+		Id:       ctx.Param("id"),
+		Code:     reqBody.Code,
+		Category: reqBody.Category,
+		Labels:   reqBody.Labels,
+	})
+	if err != nil {
+		respondAmenityError(ctx, err, "Loi khong cap nhat duoc tien ich")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toAmenityDto(result.GetAmenity()), "Cap nhat tien ich thanh cong"))
+}
+
+func (ah *AmenityHandler) DeleteAmenity(ctx *gin.Context) {
+	_, err := ah.hotelClient.DeleteAmenity(ctx, &hotel_pb.DeleteAmenityRequest{
+		Id: ctx.Param("id"),
+	})
+	if err != nil {
+		respondAmenityError(ctx, err, "Loi khong xoa duoc tien ich")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Xoa tien ich thanh cong"))
+}
+
+func (ah *AmenityHandler) GetHotelAmenities(ctx *gin.Context) {
+	result, err := ah.hotelClient.GetHotelAmenities(ctx, &hotel_pb.GetHotelAmenitiesRequest{
+		HotelId: ctx.Param("id"),
+	})
+	if err != nil {
+		respondAmenityError(ctx, err, "Loi khong lay duoc tien ich cua khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toAmenitiesDto(result.GetAmenities()), "Thanh cong"))
+}
+
+func (ah *AmenityHandler) SetHotelAmenities(ctx *gin.Context) {
+	var reqBody AmenityCodesBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	result, err := ah.hotelClient.SetHotelAmenities(ctx, &hotel_pb.SetHotelAmenitiesRequest{
+		HotelId: ctx.Param("id"),
+		Codes:   reqBody.Codes,
+	})
+	if err != nil {
+		respondAmenityError(ctx, err, "Loi khong cap nhat duoc tien ich cua khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toAmenitiesDto(result.GetAmenities()), "Cap nhat tien ich thanh cong"))
+}
+
+func (ah *AmenityHandler) GetRoomTypeAmenities(ctx *gin.Context) {
+	result, err := ah.hotelClient.GetRoomTypeAmenities(ctx, &hotel_pb.GetRoomTypeAmenitiesRequest{
+		RoomTypeId: ctx.Param("roomTypeId"),
+	})
+	if err != nil {
+		respondAmenityError(ctx, err, "Loi khong lay duoc tien ich cua loai phong")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toAmenitiesDto(result.GetAmenities()), "Thanh cong"))
+}
+
+func (ah *AmenityHandler) SetRoomTypeAmenities(ctx *gin.Context) {
+	var reqBody AmenityCodesBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	result, err := ah.hotelClient.SetRoomTypeAmenities(ctx, &hotel_pb.SetRoomTypeAmenitiesRequest{
+		HotelId:    ctx.Param("id"),
+		RoomTypeId: ctx.Param("roomTypeId"),
+		Codes:      reqBody.Codes,
+	})
+	if err != nil {
+		respondAmenityError(ctx, err, "Loi khong cap nhat duoc tien ich cua loai phong")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toAmenitiesDto(result.GetAmenities()), "Cap nhat tien ich thanh cong"))
+}
+
+func respondAmenityError(ctx *gin.Context, err error, message string) {
+	st, ok := status.FromError(err)
+	if ok {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.NotFound:
+			ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.AlreadyExists:
+			ctx.JSON(http.StatusConflict, utils.ErrorApiResponse(st.Message()))
+			return
+		}
+	}
+
+	zap.S().Infoln(message, ": ", err)
+	ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse(message))
+}
+
+func toAmenityDto(amenity *hotel_pb.Amenity) api_dto.Amenity {
+	labels := amenity.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	return api_dto.Amenity{
+		Id:       amenity.GetId(),
+		Code:     amenity.GetCode(),
+		Category: amenity.GetCategory(),
+		Labels:   labels,
+	}
+}
+
+func toAmenitiesDto(amenities []*hotel_pb.Amenity) []api_dto.Amenity {
+	results := make([]api_dto.Amenity, 0, len(amenities))
+	for _, amenity := range amenities {
+		results = append(results, toAmenityDto(amenity))
+	}
+
+	return results
+}
@@ -1,41 +1,74 @@
 package api_handler
 
 import (
-	"fmt"
+	"context"
+	"crypto/subtle"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
-	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
 	"github.com/098765432m/grpc-kafka/common/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type BookingHandler struct {
 	bookingClient booking_pb.BookingServiceClient
-	roomClient    room_pb.RoomServiceClient
+	userClient    user_pb.UserServiceClient
 }
 
-func NewBookingHandler(bookingClient booking_pb.BookingServiceClient,
-	roomClient room_pb.RoomServiceClient) *BookingHandler {
+func NewBookingHandler(bookingClient booking_pb.BookingServiceClient, userClient user_pb.UserServiceClient) *BookingHandler {
 	return &BookingHandler{
 		bookingClient: bookingClient,
-		roomClient:    roomClient,
+		userClient:    userClient,
 	}
 }
 
 func (bh *BookingHandler) RegisterRoutes(router *gin.RouterGroup) {
 	bookingHandler := router.Group("/bookings")
 
-	bookingHandler.POST("/", bh.BookingRooms)
-	bookingHandler.DELETE("/", bh.DeleteBookingsById)
-	bookingHandler.POST("/delete", bh.DeleteBookingsByIds)
+	bookingHandler.POST("/", common_middleware.OptionalAuthMiddleware(), bh.BookingRooms)
+	bookingHandler.DELETE("/:id", common_middleware.AuthMiddleware(), bh.DeleteBookingsById)
+	bookingHandler.POST("/delete", common_middleware.AuthMiddleware(), bh.DeleteBookingsByIds)
+	bookingHandler.GET("/:id/history", common_middleware.AuthMiddleware(), bh.GetBookingHistory)
+
+	// Move a booking to another account, the recipient accepts or declines
+	bookingHandler.POST("/:id/transfer", common_middleware.AuthMiddleware(), bh.TransferBooking)
+	bookingHandler.GET("/transfers", common_middleware.AuthMiddleware(), bh.GetPendingBookingTransfers)
+	bookingHandler.POST("/transfers/:transferId/accept", common_middleware.AuthMiddleware(), bh.AcceptBookingTransfer)
+	bookingHandler.POST("/transfers/:transferId/decline", common_middleware.AuthMiddleware(), bh.DeclineBookingTransfer)
+
+	// Guests without an account find their booking by confirmation code and email
+	bookingHandler.POST("/lookup", common_middleware.RateLimitMiddleware(LOOKUP_BOOKING_RATE_LIMIT, time.Minute), bh.LookupBooking)
+	bookingHandler.PUT("/manage", common_middleware.ManageBookingMiddleware(), bh.ChangeManagedBookingDates)
+	bookingHandler.DELETE("/manage", common_middleware.ManageBookingMiddleware(), bh.CancelManagedBooking)
 }
 
 type BookedRooms struct {
 	RoomTypeBookedId string `json:"room_type_booked"`
 	NumberOfRooms    int    `json:"number_of_rooms"`
+	// Occupant of each room in order, optional
+	Guests []BookingGuest `json:"guests"`
+}
+
+type BookingGuest struct {
+	Name                 string           `json:"name"`
+	Email                string           `json:"email"`
+	Phone                string           `json:"phone"`
+	EstimatedArrivalTime string           `json:"estimated_arrival_time"` // HH:MM
+	SpecialRequests      []SpecialRequest `json:"special_requests"`
+}
+
+type SpecialRequest struct {
+	// HIGH_FLOOR, LOW_FLOOR, QUIET_ROOM, BABY_COT, EXTRA_BED, ACCESSIBLE_ROOM, EARLY_CHECK_IN, LATE_CHECK_IN or OTHER
+	Type string `json:"type"`
+	Note string `json:"note"`
 }
 
 type BookingRoomsRequest struct {
@@ -44,13 +77,12 @@ type BookingRoomsRequest struct {
 	CheckOutDate string        `json:"check_out_date" binding:"required"`
 	Total        int           `json:"total" binding:"required"`
 	// NumOfGuests int32 `json:"num_of_guests"`
-	UserId string `json:"user_id" binding:"required"`
-}
-
-// Param struct for Create Bookings
-type BookedRoomsReq struct {
-	RoomTypeId string
-	RoomIds    []string
+	// BEST_FIT (default) or GUEST_PREFERENCE
+	AssignmentStrategy string `json:"assignment_strategy"`
+	// Assign a higher room type at the original price when the requested one is sold out
+	AllowUpgrade bool `json:"allow_upgrade"`
+	// Loyalty points spent as a discount
+	RedeemPoints int `json:"redeem_points"`
 }
 
 func (bh *BookingHandler) BookingRooms(ctx *gin.Context) {
@@ -67,68 +99,63 @@ func (bh *BookingHandler) BookingRooms(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi khong dat duoc phong"))
 		return
 	}
-	// Save room Type id and room ids that assign to booking
-	bookedRoomsReq := make([]BookedRoomsReq, 0, len(bookingReq.BookedRooms))
 
-	// Check for available Rooms for Room Type
+	rooms := make([]*booking_pb.RoomRequest, 0, len(bookingReq.BookedRooms))
 	for _, bookedRoom := range bookingReq.BookedRooms {
+		guests := make([]*booking_pb.BookingGuest, 0, len(bookedRoom.Guests))
+		for _, guest := range bookedRoom.Guests {
+			specialRequests := make([]*booking_pb.SpecialRequestParam, 0, len(guest.SpecialRequests))
+			for _, specialRequest := range guest.SpecialRequests {
+				specialRequests = append(specialRequests, &booking_pb.SpecialRequestParam{
+					Type: specialRequest.Type,
+					Note: specialRequest.Note,
+				})
+			}
 
-		// Get already booked Rooms in range of time
-		roomsGrpcResult, err := bh.bookingClient.GetUnavailableRoomsByRoomTypeId(ctx, &booking_pb.GetUnavailableRoomsByRoomTypeIdRequest{
-			RoomTypeId: bookedRoom.RoomTypeBookedId,
-			CheckIn:    bookingReq.CheckInDate,
-			CheckOut:   bookingReq.CheckOutDate,
-		})
-		if err != nil {
-			zap.S().Infoln("Failed to get UNAVAILABLE Rooms: ", err)
-			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi khong dat duoc phong"))
-			return
+			guests = append(guests, &booking_pb.BookingGuest{
+				Name:                 guest.Name,
+				Email:                guest.Email,
+				Phone:                guest.Phone,
+				EstimatedArrivalTime: guest.EstimatedArrivalTime,
+				SpecialRequests:      specialRequests,
+			})
 		}
 
-		unavailableRooms := roomsGrpcResult.GetRoomIds()
-
-		roomIds, err := bh.roomClient.GetListOfRemainRooms(ctx, &room_pb.GetListOfRemainRoomsRequest{
+		rooms = append(rooms, &booking_pb.RoomRequest{
 			RoomTypeId:    bookedRoom.RoomTypeBookedId,
-			BookedRoomIds: unavailableRooms,
 			NumberOfRooms: int32(bookedRoom.NumberOfRooms),
-		})
-		if err != nil {
-			zap.S().Infoln("Failed to get AVAILABLE Rooms: ", err)
-			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi khong con phong trong"))
-			return
-		}
-
-		bookedRoomsReq = append(bookedRoomsReq, BookedRoomsReq{
-			RoomTypeId: bookedRoom.RoomTypeBookedId,
-			RoomIds:    roomIds.GetRoomIds(),
+			Guests:        guests,
 		})
 	}
 
-	// Create booking param for each room
-	var newBookings []*booking_pb.BookingParam
-	for _, bookedRoom := range bookedRoomsReq {
-		for i, roomId := range bookedRoom.RoomIds {
-			newBooking := &booking_pb.BookingParam{
-				CheckIn:    bookingReq.CheckInDate,
-				CheckOut:   bookingReq.CheckOutDate,
-				Total:      int32(bookingReq.Total),
-				RoomTypeId: bookedRoom.RoomTypeId,
-				RoomId:     roomId,
-				UserId:     bookingReq.UserId,
+	// Rooms are assigned by booking service, the same way as reservations from channels
+	_, err := bh.bookingClient.BookRooms(actorContext(ctx), &booking_pb.BookRoomsRequest{
+		CheckIn:            bookingReq.CheckInDate,
+		CheckOut:           bookingReq.CheckOutDate,
+		Total:              int32(bookingReq.Total),
+		UserId:             ctx.GetString(common_middleware.AUTH_USER_ID_KEY), // empty for guests without an account
+		Rooms:              rooms,
+		AssignmentStrategy: bookingReq.AssignmentStrategy,
+		AllowUpgrade:       bookingReq.AllowUpgrade,
+		RedeemPoints:       int32(bookingReq.RedeemPoints),
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi khong dat duoc phong"))
+				return
+			case codes.ResourceExhausted:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi khong con phong trong"))
+				return
+			case codes.FailedPrecondition:
+				// Company credit limit, loyalty points or unpublished hotel
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+				return
 			}
-
-			zap.L().Info("Check booked room", zap.Any(fmt.Sprintf("Booked room %d", i), newBooking))
-
-			newBookings = append(newBookings, newBooking)
-
 		}
-	}
 
-	// Create Bookings with many rooms
-	_, err := bh.bookingClient.CreateBookings(ctx, &booking_pb.NewBookingParam{
-		NewBookings: newBookings,
-	})
-	if err != nil {
 		zap.S().Info("Cannot booking rooms: ", err)
 		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Khong dat duoc phong"))
 		return
@@ -137,58 +164,33 @@ func (bh *BookingHandler) BookingRooms(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(nil, "Dat phong thanh cong"))
 }
 
-// TODO: Worker Pool to utilize waitGroup for performance
-// Worker Pool to process AVAILABLE Rooms
-// func (bh *BookingHandler) processBookedRooms(ctx context.Context, bookingReq *BookingRoomsRequest) ([]BookedRoomsReq, error) {
-// 	// Save room Type id and room ids that assign to booking
-// 	bookedRoomsReq := make([]BookedRoomsReq, 0, len(bookingReq.BookedRooms))
-
-// 	// Check for available Rooms for Room Type
-// 	for _, bookedRoom := range bookingReq.BookedRooms {
-
-// 		// Get already booked Rooms in range of time
-// 		roomsGrpcResult, err := bh.bookingClient.GetUnavailableRoomsByRoomTypeId(ctx, &booking_pb.GetUnavailableRoomsByRoomTypeIdRequest{
-// 			RoomTypeId: bookedRoom.RoomTypeBookedId,
-// 			CheckIn:    bookingReq.CheckInDate,
-// 			CheckOut:   bookingReq.CheckOutDate,
-// 		})
-// 		if err != nil {
-// 			zap.S().Infoln("Failed to get UNAVAILABLE Rooms: ", err)
-// 			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi khong dat duoc phong"))
-// 			return
-// 		}
-
-// 		unavailableRooms := roomsGrpcResult.GetRoomIds()
-
-// 		roomIds, err := bh.roomClient.GetListOfRemainRooms(ctx, &room_pb.GetListOfRemainRoomsRequest{
-// 			RoomTypeId:    bookedRoom.RoomTypeBookedId,
-// 			BookedRoomIds: unavailableRooms,
-// 			NumberOfRooms: int32(bookedRoom.NumberOfRooms),
-// 		})
-// 		if err != nil {
-// 			zap.S().Infoln("Failed to get AVAILABLE Rooms: ", err)
-// 			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi khong con phong trong"))
-// 			return
-// 		}
-
-// 		bookedRoomsReq = append(bookedRoomsReq, BookedRoomsReq{
-// 			RoomTypeId: bookedRoom.RoomTypeBookedId,
-// 			RoomIds:    roomIds.GetRoomIds(),
-// 		})
-// 	}
-// }
-
 func (bh *BookingHandler) DeleteBookingsById(ctx *gin.Context) {
 	var id pgtype.UUID
 	if err := id.Scan(ctx.Param("id")); err != nil {
 		zap.S().Infoln("Invalid UUID: ", err)
 		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi he thong"))
+		return
 	}
 
-	_, err := bh.bookingClient.DeleteBookingsById(ctx, &booking_pb.DeleteBookingByIdRequest{
+	_, err := bh.bookingClient.DeleteBookingsById(userActorContext(ctx), &booking_pb.DeleteBookingByIdRequest{
 		BookingId: id.String(),
+		Reason:    ctx.Query("reason"),
 	})
 	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			switch st.Code() {
+			case codes.NotFound:
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay booking"))
+				return
+			case codes.PermissionDenied:
+				ctx.JSON(http.StatusForbidden, utils.ErrorApiResponse(st.Message()))
+				return
+			case codes.FailedPrecondition:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+				return
+			}
+		}
+
 		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Khong xoa duoc booking"))
 		return
 	}
@@ -198,6 +200,7 @@ func (bh *BookingHandler) DeleteBookingsById(ctx *gin.Context) {
 
 type DeleteBookingsRequest struct {
 	BookingIds []string `json:"booking_ids"`
+	Reason     string   `json:"reason"`
 }
 
 func (bh *BookingHandler) DeleteBookingsByIds(ctx *gin.Context) {
@@ -207,13 +210,338 @@ func (bh *BookingHandler) DeleteBookingsByIds(ctx *gin.Context) {
 		return
 	}
 
-	_, err := bh.bookingClient.DeleteBookingsByIds(ctx, &booking_pb.DeleteBookingByIdsRequest{
+	_, err := bh.bookingClient.DeleteBookingsByIds(userActorContext(ctx), &booking_pb.DeleteBookingByIdsRequest{
 		BookingIds: req.BookingIds,
+		Reason:     req.Reason,
 	})
 	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			switch st.Code() {
+			case codes.NotFound:
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay booking"))
+				return
+			case codes.PermissionDenied:
+				ctx.JSON(http.StatusForbidden, utils.ErrorApiResponse(st.Message()))
+				return
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+				return
+			case codes.FailedPrecondition:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+				return
+			}
+		}
+
 		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Khong xoa duoc booking"))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Xoa bookings thanh cong"))
 }
+
+func (bh *BookingHandler) GetBookingHistory(ctx *gin.Context) {
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := bh.bookingClient.GetBookingHistory(userContext(ctx), &booking_pb.GetBookingHistoryRequest{
+		BookingId: ctx.Param("id"),
+		Page:      page,
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+				return
+			case codes.NotFound:
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay booking"))
+				return
+			case codes.PermissionDenied:
+				ctx.JSON(http.StatusForbidden, utils.ErrorApiResponse(st.Message()))
+				return
+			}
+		}
+
+		zap.S().Infoln("Failed to get booking history: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc lich su dat phong"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(result.GetEvents(), result.GetPage()), "Thanh cong"))
+}
+
+type TransferBookingRequest struct {
+	TargetUserId string `json:"target_user_id" binding:"required"`
+}
+
+// Owner of the booking or an admin offers it to another user
+func (bh *BookingHandler) TransferBooking(ctx *gin.Context) {
+	var req *TransferBookingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	transfer, err := bh.bookingClient.TransferBooking(userActorContext(ctx), &booking_pb.TransferBookingRequest{
+		BookingId:    ctx.Param("id"),
+		TargetUserId: req.TargetUserId,
+	})
+	if err != nil {
+		respondBookingTransferError(ctx, err, "Loi khong chuyen duoc booking")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(transfer, "Da gui yeu cau chuyen booking"))
+}
+
+// Transfers the signed in user received or sent that are waiting for an answer
+func (bh *BookingHandler) GetPendingBookingTransfers(ctx *gin.Context) {
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := bh.bookingClient.GetPendingBookingTransfers(userContext(ctx), &booking_pb.GetPendingBookingTransfersRequest{
+		Page: page,
+	})
+	if err != nil {
+		respondBookingTransferError(ctx, err, "Loi khong lay duoc yeu cau chuyen booking")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(result.GetTransfers(), result.GetPage()), "Thanh cong"))
+}
+
+func (bh *BookingHandler) AcceptBookingTransfer(ctx *gin.Context) {
+	bh.respondBookingTransfer(ctx, true)
+}
+
+func (bh *BookingHandler) DeclineBookingTransfer(ctx *gin.Context) {
+	bh.respondBookingTransfer(ctx, false)
+}
+
+func (bh *BookingHandler) respondBookingTransfer(ctx *gin.Context, accept bool) {
+	transfer, err := bh.bookingClient.RespondBookingTransfer(userActorContext(ctx), &booking_pb.RespondBookingTransferRequest{
+		TransferId: ctx.Param("transferId"),
+		Accept:     accept,
+	})
+	if err != nil {
+		respondBookingTransferError(ctx, err, "Loi khong tra loi duoc yeu cau chuyen booking")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(transfer, "Thanh cong"))
+}
+
+// Map error of booking transfer to http response, message is used for unexpected errors
+func respondBookingTransferError(ctx *gin.Context, err error, message string) {
+	st, ok := status.FromError(err)
+	if ok {
+		switch st.Code() {
+		case codes.InvalidArgument, codes.FailedPrecondition:
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.NotFound:
+			ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.PermissionDenied:
+			ctx.JSON(http.StatusForbidden, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.Unauthenticated:
+			ctx.JSON(http.StatusUnauthorized, utils.ErrorApiResponse(st.Message()))
+			return
+		}
+	}
+
+	zap.S().Infoln(message, ": ", err)
+	ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse(message))
+}
+
+// Lookups allowed per client IP each minute
+const LOOKUP_BOOKING_RATE_LIMIT = 5
+
+// Failed lookups are answered no sooner than this, unknown codes and wrong emails take the same time
+const LOOKUP_BOOKING_MIN_DURATION = 500 * time.Millisecond
+
+type LookupBookingRequest struct {
+	ConfirmationCode string `json:"confirmation_code" binding:"required"`
+	Email            string `json:"email" binding:"required"`
+}
+
+type LookupBookingResponse struct {
+	Booking     *booking_pb.Booking `json:"booking"`
+	ManageToken string              `json:"manage_token"`
+	ExpiresAt   time.Time           `json:"expires_at"`
+}
+
+func (bh *BookingHandler) LookupBooking(ctx *gin.Context) {
+	var req *LookupBookingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	// Unknown code and wrong email get the same answer after the same time,
+	// so codes and emails cannot be guessed one at a time
+	started := time.Now()
+	notFound := func() {
+		timer := time.NewTimer(LOOKUP_BOOKING_MIN_DURATION - time.Since(started))
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Request.Context().Done():
+		}
+
+		ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay dat phong"))
+	}
+
+	booking, err := bh.bookingClient.GetBookingByConfirmationCode(ctx, &booking_pb.GetBookingByConfirmationCodeRequest{
+		ConfirmationCode: req.ConfirmationCode,
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && (st.Code() == codes.NotFound || st.Code() == codes.InvalidArgument) {
+			notFound()
+			return
+		}
+
+		zap.S().Infoln("Failed to get booking by confirmation code: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong tim duoc dat phong"))
+		return
+	}
+
+	// Either the guest staying in the room or the user who booked it can manage the booking
+	emails := []string{booking.GetGuestEmail()}
+	if booking.GetUserId() != "" {
+		userResult, err := bh.userClient.GetUserById(ctx, &user_pb.GetUserByIdRequest{
+			Id: booking.GetUserId(),
+		})
+		if err != nil {
+			if st, ok := status.FromError(err); !ok || st.Code() != codes.NotFound {
+				zap.S().Infoln("Failed to get user of booking: ", err)
+				ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong tim duoc dat phong"))
+				return
+			}
+		} else {
+			emails = append(emails, userResult.GetUser().GetEmail())
+		}
+	}
+
+	if !matchesAnyEmail(req.Email, emails) {
+		notFound()
+		return
+	}
+
+	manageToken, err := common_middleware.NewManageBookingToken(booking.GetId())
+	if err != nil {
+		zap.S().Errorln("Failed to sign manage booking token: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong tim duoc dat phong"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(LookupBookingResponse{
+		Booking:     booking,
+		ManageToken: manageToken,
+		ExpiresAt:   time.Now().Add(common_middleware.MANAGE_BOOKING_TOKEN_TTL),
+	}, "Thanh cong"))
+}
+
+// Compare in constant time, empty emails never match
+func matchesAnyEmail(email string, emails []string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+
+	matched := false
+	for _, e := range emails {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e != "" && subtle.ConstantTimeCompare([]byte(email), []byte(e)) == 1 {
+			matched = true
+		}
+	}
+
+	return matched
+}
+
+// Actor of changes made with a manage booking token, the token is sent on for the booking service to check
+func manageBookingActorContext(ctx *gin.Context) context.Context {
+	actorCtx := utils.WithActor(ctx, "guest:"+ctx.GetString(common_middleware.MANAGE_BOOKING_ID_KEY))
+	return utils.WithManageBookingToken(actorCtx, ctx.GetString(common_middleware.MANAGE_BOOKING_TOKEN_KEY))
+}
+
+type ChangeManagedBookingDatesRequest struct {
+	CheckInDate  string `json:"check_in_date" binding:"required"`
+	CheckOutDate string `json:"check_out_date" binding:"required"`
+}
+
+func (bh *BookingHandler) ChangeManagedBookingDates(ctx *gin.Context) {
+	var req *ChangeManagedBookingDatesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	booking, err := bh.bookingClient.ChangeBookingDates(manageBookingActorContext(ctx), &booking_pb.ChangeBookingDatesRequest{
+		BookingId: ctx.GetString(common_middleware.MANAGE_BOOKING_ID_KEY),
+		CheckIn:   req.CheckInDate,
+		CheckOut:  req.CheckOutDate,
+		Reason:    "changed by guest",
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+				return
+			case codes.NotFound:
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay dat phong"))
+				return
+			case codes.ResourceExhausted:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi khong con phong trong vao ngay moi"))
+				return
+			case codes.FailedPrecondition:
+				// Company credit limit or a finished booking
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+				return
+			}
+		}
+
+		zap.S().Infoln("Failed to change booking dates: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong doi duoc ngay dat phong"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(booking, "Doi ngay dat phong thanh cong"))
+}
+
+func (bh *BookingHandler) CancelManagedBooking(ctx *gin.Context) {
+	reason := ctx.Query("reason")
+	if reason == "" {
+		reason = "cancelled by guest"
+	}
+
+	_, err := bh.bookingClient.DeleteBookingsById(manageBookingActorContext(ctx), &booking_pb.DeleteBookingByIdRequest{
+		BookingId: ctx.GetString(common_middleware.MANAGE_BOOKING_ID_KEY),
+		Reason:    reason,
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && (st.Code() == codes.NotFound || st.Code() == codes.InvalidArgument) {
+			ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay dat phong"))
+			return
+		}
+		if st, ok := status.FromError(err); ok && st.Code() == codes.FailedPrecondition {
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return
+		}
+
+		zap.S().Infoln("Failed to cancel booking: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong huy duoc dat phong"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Huy dat phong thanh cong"))
+}
@@ -0,0 +1,116 @@
+package api_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+func TestLookupBooking(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	viper.Set("MANAGE_BOOKING_SECRET_KEY", "manage-booking-test-secret")
+	t.Cleanup(func() { viper.Set("MANAGE_BOOKING_SECRET_KEY", "") })
+
+	booking := &booking_pb.Booking{
+		Id:               "booking-1",
+		ConfirmationCode: "AB12CD",
+		UserId:           "user-1",
+		GuestEmail:       "guest@example.com",
+	}
+	booker := &user_pb.User{Id: "user-1", Email: "booker@example.com"}
+
+	tests := []struct {
+		name     string
+		body     string
+		user     *user_pb.User
+		wantCode int
+	}{
+		{"guest email", `{"confirmation_code": "AB12CD", "email": "guest@example.com"}`, booker, http.StatusOK},
+		{"email of the user who booked", `{"confirmation_code": "AB12CD", "email": " Booker@Example.com "}`, booker, http.StatusOK},
+		{"guest email when the user is deleted", `{"confirmation_code": "AB12CD", "email": "guest@example.com"}`, nil, http.StatusOK},
+		{"wrong email", `{"confirmation_code": "AB12CD", "email": "someone@example.com"}`, booker, http.StatusNotFound},
+		{"unknown code", `{"confirmation_code": "ZZ99ZZ", "email": "guest@example.com"}`, booker, http.StatusNotFound},
+		{"missing email", `{"confirmation_code": "AB12CD"}`, booker, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bh := &BookingHandler{bookingClient: &fakeBookingClient{bookingByCode: booking}, userClient: &fakeUserClient{user: tt.user}}
+
+			router := gin.New()
+			router.POST("/bookings/lookup", bh.LookupBooking)
+
+			started := time.Now()
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/bookings/lookup", strings.NewReader(tt.body)))
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("LookupBooking() status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+
+			// Unknown codes and wrong emails cannot be told apart by the response time
+			if tt.wantCode == http.StatusNotFound && time.Since(started) < LOOKUP_BOOKING_MIN_DURATION {
+				t.Errorf("LookupBooking() answered not found after %v, want at least %v", time.Since(started), LOOKUP_BOOKING_MIN_DURATION)
+			}
+
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Result LookupBookingResponse `json:"result"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+
+			// The token unlocks the looked up booking only
+			manageRouter := gin.New()
+			manageBookingId := ""
+			manageRouter.POST("/bookings/manage", common_middleware.ManageBookingMiddleware(), func(ctx *gin.Context) {
+				manageBookingId = ctx.GetString(common_middleware.MANAGE_BOOKING_ID_KEY)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/bookings/manage", nil)
+			req.Header.Set(common_middleware.MANAGE_BOOKING_TOKEN_HEADER, body.Result.ManageToken)
+			manageRouter.ServeHTTP(httptest.NewRecorder(), req)
+
+			if manageBookingId != booking.GetId() {
+				t.Errorf("LookupBooking() token unlocks booking %q, want %q", manageBookingId, booking.GetId())
+			}
+		})
+	}
+}
+
+func TestMatchesAnyEmail(t *testing.T) {
+	tests := []struct {
+		name   string
+		email  string
+		emails []string
+		want   bool
+	}{
+		{"same email", "guest@example.com", []string{"guest@example.com"}, true},
+		{"case and spaces are ignored", " Guest@Example.COM", []string{"guest@example.com "}, true},
+		{"second email", "booker@example.com", []string{"guest@example.com", "booker@example.com"}, true},
+		{"other email", "someone@example.com", []string{"guest@example.com"}, false},
+		{"empty email never matches", "", []string{""}, false},
+		{"booking without guest email", "guest@example.com", []string{""}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAnyEmail(tt.email, tt.emails); got != tt.want {
+				t.Errorf("matchesAnyEmail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package api_handler
+
+import (
+	"net/http"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Corporate accounts, their members and negotiated rates are managed by admins
+type CompanyHandler struct {
+	userClient    user_pb.UserServiceClient
+	bookingClient booking_pb.BookingServiceClient
+}
+
+func NewCompanyHandler(userClient user_pb.UserServiceClient, bookingClient booking_pb.BookingServiceClient) *CompanyHandler {
+	return &CompanyHandler{
+		userClient:    userClient,
+		bookingClient: bookingClient,
+	}
+}
+
+func (ch *CompanyHandler) RegisterRoutes(router *gin.RouterGroup) {
+	companyHandler := router.Group("/companies", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin())
+
+	companyHandler.POST("", ch.CreateCompany)
+	companyHandler.GET("/:id", ch.GetCompanyById)
+	companyHandler.PUT("/:id", ch.UpdateCompanyById)
+	companyHandler.GET("/:id/invoice", ch.GetCompanyInvoice)
+	companyHandler.POST("/:id/settlements", ch.SettleCompanyBalance)
+
+	companyHandler.GET("/:id/members", ch.GetCompanyMembers)
+	companyHandler.POST("/:id/members", ch.AddCompanyMember)
+	companyHandler.DELETE("/:id/members/:userId", ch.RemoveCompanyMember)
+
+	companyHandler.GET("/:id/rates", ch.GetCompanyRates)
+	companyHandler.POST("/:id/rates", ch.CreateCompanyRate)
+	companyHandler.DELETE("/:id/rates/:rateId", ch.DeleteCompanyRate)
+}
+
+type CompanyBody struct {
+	Name           string `json:"name" binding:"required"`
+	BillingEmail   string `json:"billing_email" binding:"required"`
+	BillingAddress string `json:"billing_address"`
+	CreditLimit    int    `json:"credit_limit"`
+}
+
+func (ch *CompanyHandler) CreateCompany(ctx *gin.Context) {
+	var reqBody CompanyBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	company, err := ch.userClient.CreateCompany(ctx, &user_pb.CreateCompanyRequest{
+		Name:           reqBody.Name,
+		BillingEmail:   reqBody.BillingEmail,
+		BillingAddress: reqBody.BillingAddress,
+		CreditLimit:    int32(reqBody.CreditLimit),
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong tao duoc cong ty")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(company, "Tao cong ty thanh cong"))
+}
+
+func (ch *CompanyHandler) GetCompanyById(ctx *gin.Context) {
+	company, err := ch.userClient.GetCompanyById(ctx, &user_pb.GetCompanyByIdRequest{
+		Id: ctx.Param("id"),
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong lay duoc cong ty")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(company, "Thanh cong"))
+}
+
+func (ch *CompanyHandler) UpdateCompanyById(ctx *gin.Context) {
+	var reqBody CompanyBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	company, err := ch.userClient.UpdateCompanyById(ctx, &user_pb.UpdateCompanyByIdRequest{
+		Id:             ctx.Param("id"),
+		Name:           reqBody.Name,
+		BillingEmail:   reqBody.BillingEmail,
+		BillingAddress: reqBody.BillingAddress,
+		CreditLimit:    int32(reqBody.CreditLimit),
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong cap nhat duoc cong ty")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(company, "Cap nhat cong ty thanh cong"))
+}
+
+// Bookings billed to the company checking out in [start_date, end_date) with the total of every page
+func (ch *CompanyHandler) GetCompanyInvoice(ctx *gin.Context) {
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	company, err := ch.userClient.GetCompanyById(ctx, &user_pb.GetCompanyByIdRequest{
+		Id: ctx.Param("id"),
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong lay duoc cong ty")
+		return
+	}
+
+	result, err := ch.bookingClient.GetCompanyBookings(ctx, &booking_pb.GetCompanyBookingsRequest{
+		CompanyId: company.GetId(),
+		StartDate: ctx.Query("start_date"),
+		EndDate:   ctx.Query("end_date"),
+		Page:      page,
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+			return
+		}
+
+		zap.S().Infoln("Failed to get company bookings: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc hoa don cong ty"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(gin.H{
+		"company":  company,
+		"bookings": toPageResponse(result.GetBookings(), result.GetPage()),
+		"total":    result.GetTotal(),
+	}, "Thanh cong"))
+}
+
+type SettleCompanyBalanceBody struct {
+	Amount    int    `json:"amount" binding:"required"`
+	Reference string `json:"reference"`
+}
+
+// Payment received from the company, taken off its outstanding balance
+func (ch *CompanyHandler) SettleCompanyBalance(ctx *gin.Context) {
+	var reqBody SettleCompanyBalanceBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	company, err := ch.userClient.SettleCompanyBalance(ctx, &user_pb.SettleCompanyBalanceRequest{
+		CompanyId: ctx.Param("id"),
+		Amount:    int32(reqBody.Amount),
+		Reference: reqBody.Reference,
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong thanh toan duoc cong no cong ty")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(company, "Thanh toan cong no thanh cong"))
+}
+
+func (ch *CompanyHandler) GetCompanyMembers(ctx *gin.Context) {
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := ch.userClient.GetCompanyMembers(ctx, &user_pb.GetCompanyMembersRequest{
+		CompanyId: ctx.Param("id"),
+		Page:      page,
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong lay duoc thanh vien cong ty")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(result.GetMembers(), result.GetPage()), "Thanh cong"))
+}
+
+type AddCompanyMemberBody struct {
+	UserId string `json:"user_id" binding:"required"`
+}
+
+func (ch *CompanyHandler) AddCompanyMember(ctx *gin.Context) {
+	var reqBody AddCompanyMemberBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	_, err := ch.userClient.AddCompanyMember(ctx, &user_pb.AddCompanyMemberRequest{
+		CompanyId: ctx.Param("id"),
+		UserId:    reqBody.UserId,
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong them duoc thanh vien cong ty")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(nil, "Them thanh vien thanh cong"))
+}
+
+func (ch *CompanyHandler) RemoveCompanyMember(ctx *gin.Context) {
+	_, err := ch.userClient.RemoveCompanyMember(ctx, &user_pb.RemoveCompanyMemberRequest{
+		CompanyId: ctx.Param("id"),
+		UserId:    ctx.Param("userId"),
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong xoa duoc thanh vien cong ty")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Xoa thanh vien thanh cong"))
+}
+
+func (ch *CompanyHandler) GetCompanyRates(ctx *gin.Context) {
+	result, err := ch.userClient.GetCompanyRatesByCompanyId(ctx, &user_pb.GetCompanyRatesByCompanyIdRequest{
+		CompanyId: ctx.Param("id"),
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong lay duoc gia thoa thuan")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetRates(), "Thanh cong"))
+}
+
+type CreateCompanyRateBody struct {
+	HotelId    string `json:"hotel_id" binding:"required"`
+	RoomTypeId string `json:"room_type_id"` // Empty applies to every room type of the hotel
+	RateType   string `json:"rate_type" binding:"required"`
+	Value      int    `json:"value"`
+}
+
+func (ch *CompanyHandler) CreateCompanyRate(ctx *gin.Context) {
+	var reqBody CreateCompanyRateBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	rate, err := ch.userClient.CreateCompanyRate(ctx, &user_pb.CreateCompanyRateRequest{
+		CompanyId:  ctx.Param("id"),
+		HotelId:    reqBody.HotelId,
+		RoomTypeId: reqBody.RoomTypeId,
+		RateType:   reqBody.RateType,
+		Value:      int32(reqBody.Value),
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong tao duoc gia thoa thuan")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(rate, "Tao gia thoa thuan thanh cong"))
+}
+
+func (ch *CompanyHandler) DeleteCompanyRate(ctx *gin.Context) {
+	_, err := ch.userClient.DeleteCompanyRate(ctx, &user_pb.DeleteCompanyRateRequest{
+		Id:        ctx.Param("rateId"),
+		CompanyId: ctx.Param("id"),
+	})
+	if err != nil {
+		respondCompanyError(ctx, err, "Loi khong xoa duoc gia thoa thuan")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Xoa gia thoa thuan thanh cong"))
+}
+
+// Map error of user service to http response, message is used for unexpected errors
+func respondCompanyError(ctx *gin.Context, err error, message string) {
+	st, ok := status.FromError(err)
+	if ok {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.NotFound:
+			ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.AlreadyExists:
+			ctx.JSON(http.StatusConflict, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.FailedPrecondition:
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return
+		}
+	}
+
+	zap.S().Infoln(message, ": ", err)
+	ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse(message))
+}
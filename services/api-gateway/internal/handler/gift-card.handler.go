@@ -0,0 +1,182 @@
+package api_handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Balance checks allowed per client IP each minute, so codes cannot be guessed
+const GIFT_CARD_BALANCE_RATE_LIMIT = 10
+
+// Gift cards are issued and voided by admins, anyone holding a code can check it or pay a booking with it
+type GiftCardHandler struct {
+	bookingClient booking_pb.BookingServiceClient
+}
+
+func NewGiftCardHandler(bookingClient booking_pb.BookingServiceClient) *GiftCardHandler {
+	return &GiftCardHandler{
+		bookingClient: bookingClient,
+	}
+}
+
+func (gh *GiftCardHandler) RegisterRoutes(router *gin.RouterGroup) {
+	giftCardHandler := router.Group("/gift-cards")
+
+	giftCardHandler.POST("/balance", common_middleware.RateLimitMiddleware(GIFT_CARD_BALANCE_RATE_LIMIT, time.Minute), gh.GetGiftCardBalance)
+
+	giftCardHandler.POST("", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), gh.IssueGiftCard)
+	giftCardHandler.POST("/:id/void", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), gh.VoidGiftCard)
+	giftCardHandler.GET("/:id/transactions", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), gh.GetGiftCardTransactions)
+
+	router.POST("/bookings/:id/payments/gift-card", common_middleware.AuthMiddleware(), gh.PayBookingWithGiftCard)
+}
+
+type IssueGiftCardBody struct {
+	Amount    int    `json:"amount" binding:"required"`
+	ExpiresAt string `json:"expires_at"` // YYYY-MM-DD, one year from now when empty
+}
+
+func (gh *GiftCardHandler) IssueGiftCard(ctx *gin.Context) {
+	var reqBody IssueGiftCardBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	giftCard, err := gh.bookingClient.IssueGiftCard(actorContext(ctx), &booking_pb.IssueGiftCardRequest{
+		Amount:    int32(reqBody.Amount),
+		ExpiresAt: reqBody.ExpiresAt,
+	})
+	if err != nil {
+		respondGiftCardError(ctx, err, "Loi khong phat hanh duoc the qua tang")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(giftCard, "Phat hanh the qua tang thanh cong"))
+}
+
+type VoidGiftCardBody struct {
+	Note string `json:"note"`
+}
+
+func (gh *GiftCardHandler) VoidGiftCard(ctx *gin.Context) {
+	var reqBody VoidGiftCardBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	giftCard, err := gh.bookingClient.VoidGiftCard(actorContext(ctx), &booking_pb.VoidGiftCardRequest{
+		Id:   ctx.Param("id"),
+		Note: reqBody.Note,
+	})
+	if err != nil {
+		respondGiftCardError(ctx, err, "Loi khong huy duoc the qua tang")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(giftCard, "Huy the qua tang thanh cong"))
+}
+
+func (gh *GiftCardHandler) GetGiftCardTransactions(ctx *gin.Context) {
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := gh.bookingClient.GetGiftCardTransactions(ctx, &booking_pb.GetGiftCardTransactionsRequest{
+		GiftCardId: ctx.Param("id"),
+		Page:       page,
+	})
+	if err != nil {
+		respondGiftCardError(ctx, err, "Loi khong lay duoc lich su the qua tang")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(result.GetTransactions(), result.GetPage()), "Thanh cong"))
+}
+
+type GiftCardCodeBody struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// Code is sent in the body so it does not end up in access logs
+func (gh *GiftCardHandler) GetGiftCardBalance(ctx *gin.Context) {
+	var reqBody GiftCardCodeBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	giftCard, err := gh.bookingClient.GetGiftCardByCode(ctx, &booking_pb.GetGiftCardByCodeRequest{
+		Code: reqBody.Code,
+	})
+	if err != nil {
+		respondGiftCardError(ctx, err, "Loi khong lay duoc the qua tang")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(gin.H{
+		"balance":    giftCard.GetBalance(),
+		"status":     giftCard.GetStatus(),
+		"expires_at": giftCard.GetExpiresAt(),
+	}, "Thanh cong"))
+}
+
+type PayBookingWithGiftCardBody struct {
+	Code   string `json:"code" binding:"required"`
+	Amount int    `json:"amount"` // 0 pays as much as the card covers
+}
+
+func (gh *GiftCardHandler) PayBookingWithGiftCard(ctx *gin.Context) {
+	var reqBody PayBookingWithGiftCardBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	result, err := gh.bookingClient.PayBookingWithGiftCard(userActorContext(ctx), &booking_pb.PayBookingWithGiftCardRequest{
+		BookingId:    ctx.Param("id"),
+		GiftCardCode: reqBody.Code,
+		Amount:       int32(reqBody.Amount),
+	})
+	if err != nil {
+		respondGiftCardError(ctx, err, "Loi khong thanh toan duoc bang the qua tang")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result, "Thanh toan thanh cong"))
+}
+
+// Map error of gift card to http response, message is used for unexpected errors
+func respondGiftCardError(ctx *gin.Context, err error, message string) {
+	st, ok := status.FromError(err)
+	if ok {
+		switch st.Code() {
+		case codes.InvalidArgument, codes.FailedPrecondition:
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.NotFound:
+			ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.PermissionDenied:
+			ctx.JSON(http.StatusForbidden, utils.ErrorApiResponse(st.Message()))
+			return
+		}
+	}
+
+	zap.S().Infoln(message, ": ", err)
+	ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse(message))
+}
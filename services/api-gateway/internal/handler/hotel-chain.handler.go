@@ -0,0 +1,484 @@
+package api_handler
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	api_dto "github.com/098765432m/grpc-kafka/api-gateway/internal/dto"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Hotel chains are managed by admins, CHAIN_MANAGER users see bookings,
+// availability and analytics of every hotel of their chain
+type HotelChainHandler struct {
+	hotelClient    hotel_pb.HotelServiceClient
+	roomTypeClient room_type_pb.RoomTypeServiceClient
+	roomClient     room_pb.RoomServiceClient
+	userClient     user_pb.UserServiceClient
+	bookingClient  booking_pb.BookingServiceClient
+}
+
+func NewHotelChainHandler(hotelClient hotel_pb.HotelServiceClient, roomTypeClient room_type_pb.RoomTypeServiceClient, roomClient room_pb.RoomServiceClient, userClient user_pb.UserServiceClient, bookingClient booking_pb.BookingServiceClient) *HotelChainHandler {
+	return &HotelChainHandler{
+		hotelClient:    hotelClient,
+		roomTypeClient: roomTypeClient,
+		roomClient:     roomClient,
+		userClient:     userClient,
+		bookingClient:  bookingClient,
+	}
+}
+
+func (hch *HotelChainHandler) RegisterRoutes(router *gin.RouterGroup) {
+	chainHandler := router.Group("/chains", common_middleware.AuthMiddleware())
+
+	chainHandler.GET("", common_middleware.RequireAdmin(), hch.GetHotelChains)
+	chainHandler.POST("", common_middleware.RequireAdmin(), hch.CreateHotelChain)
+	chainHandler.GET("/:id", common_middleware.RequireChainManager(), hch.GetHotelChainById)
+	chainHandler.PUT("/:id", common_middleware.RequireAdmin(), hch.UpdateHotelChain)
+	chainHandler.DELETE("/:id", common_middleware.RequireAdmin(), hch.DeleteHotelChain)
+
+	chainHandler.PUT("/:id/hotels/:hotelId", common_middleware.RequireAdmin(), hch.AddHotelToChain)
+	chainHandler.DELETE("/:id/hotels/:hotelId", common_middleware.RequireAdmin(), hch.RemoveHotelFromChain)
+
+	// Aggregates of every hotel of the chain, hotel_id query narrows them to one hotel of the chain
+	chainHandler.GET("/:id/bookings", common_middleware.RequireChainManager(), hch.SearchChainBookings)
+	chainHandler.GET("/:id/availability", common_middleware.RequireChainManager(), hch.GetChainAvailability)
+	chainHandler.GET("/:id/analytics", common_middleware.RequireChainManager(), hch.GetChainAnalytics)
+}
+
+type HotelChainBody struct {
+	Name        string `json:"name" binding:"required"`
+	Brand       string `json:"brand"`
+	Description string `json:"description"`
+}
+
+func (hch *HotelChainHandler) GetHotelChains(ctx *gin.Context) {
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := hch.hotelClient.GetHotelChains(ctx, &hotel_pb.GetHotelChainsRequest{
+		Page: page,
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong lay duoc danh sach chuoi khach san")
+		return
+	}
+
+	chains := make([]api_dto.HotelChainResponse, 0, len(result.GetChains()))
+	for _, chain := range result.GetChains() {
+		chains = append(chains, toHotelChainDto(chain, nil))
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(chains, result.GetPage()), "Thanh cong"))
+}
+
+// Chain with all of its hotels
+func (hch *HotelChainHandler) GetHotelChainById(ctx *gin.Context) {
+	chain, err := hch.hotelClient.GetHotelChainById(ctx, &hotel_pb.GetHotelChainByIdRequest{
+		Id: ctx.Param("id"),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong lay duoc chuoi khach san")
+		return
+	}
+
+	hotels, err := hch.hotelClient.GetHotelsByChainId(ctx, &hotel_pb.GetHotelsByChainIdRequest{
+		ChainId: ctx.Param("id"),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong lay duoc khach san cua chuoi")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toHotelChainDto(chain.GetChain(), hotels.GetHotels()), "Thanh cong"))
+}
+
+func (hch *HotelChainHandler) CreateHotelChain(ctx *gin.Context) {
+	var reqBody HotelChainBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	result, err := hch.hotelClient.CreateHotelChain(actorContext(ctx), &hotel_pb.CreateHotelChainRequest{
+		Name:        reqBody.Name,
+		Brand:       reqBody.Brand,
+		Description: reqBody.Description,
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong tao duoc chuoi khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(toHotelChainDto(result.GetChain(), nil), "Tao chuoi khach san thanh cong"))
+}
+
+func (hch *HotelChainHandler) UpdateHotelChain(ctx *gin.Context) {
+	var reqBody HotelChainBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	result, err := hch.hotelClient.UpdateHotelChain(actorContext(ctx), &hotel_pb.UpdateHotelChainRequest{
+		Id:          ctx.Param("id"),
+		Name:        reqBody.Name,
+		Brand:       reqBody.Brand,
+		Description: reqBody.Description,
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong cap nhat duoc chuoi khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toHotelChainDto(result.GetChain(), nil), "Cap nhat chuoi khach san thanh cong"))
+}
+
+// Hotels of the deleted chain become independent
+func (hch *HotelChainHandler) DeleteHotelChain(ctx *gin.Context) {
+	if _, err := hch.hotelClient.DeleteHotelChain(actorContext(ctx), &hotel_pb.DeleteHotelChainRequest{
+		Id: ctx.Param("id"),
+	}); err != nil {
+		respondHotelError(ctx, err, "Loi khong xoa duoc chuoi khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Xoa chuoi khach san thanh cong"))
+}
+
+// Move the hotel into the chain, a hotel belongs to at most one chain
+func (hch *HotelChainHandler) AddHotelToChain(ctx *gin.Context) {
+	result, err := hch.hotelClient.SetHotelChain(actorContext(ctx), &hotel_pb.SetHotelChainRequest{
+		HotelId: ctx.Param("hotelId"),
+		ChainId: ctx.Param("id"),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong them duoc khach san vao chuoi")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetHotel(), "Them khach san vao chuoi thanh cong"))
+}
+
+func (hch *HotelChainHandler) RemoveHotelFromChain(ctx *gin.Context) {
+	hotel, err := hch.hotelClient.GetHotelById(userContext(ctx), &hotel_pb.GetHotelByIdRequest{
+		Id: ctx.Param("hotelId"),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong lay duoc khach san")
+		return
+	}
+
+	if hotel.GetHotel().GetChainId() != ctx.Param("id") {
+		ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khach san khong thuoc chuoi nay"))
+		return
+	}
+
+	result, err := hch.hotelClient.SetHotelChain(actorContext(ctx), &hotel_pb.SetHotelChainRequest{
+		HotelId: ctx.Param("hotelId"),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong xoa duoc khach san khoi chuoi")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetHotel(), "Xoa khach san khoi chuoi thanh cong"))
+}
+
+// Same filters as bookings of a hotel, in every hotel of the chain
+func (hch *HotelChainHandler) SearchChainBookings(ctx *gin.Context) {
+	hotels, ok := hch.getChainHotels(ctx)
+	if !ok {
+		return
+	}
+
+	if len(hotels) == 0 {
+		ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse([]*booking_pb.Booking{}, nil), "Thanh cong"))
+		return
+	}
+
+	searchBookings(ctx, hch.userClient, hch.bookingClient, &booking_pb.SearchBookingsRequest{
+		HotelIds: chainHotelIds(hotels),
+	})
+}
+
+// Rooms of every room type still free between check_in and check_out, per hotel and for the whole chain
+func (hch *HotelChainHandler) GetChainAvailability(ctx *gin.Context) {
+	checkIn := ctx.Query("check_in")
+	checkOut := ctx.Query("check_out")
+	if checkIn == "" || checkOut == "" {
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Can nhap ngay nhan phong va tra phong"))
+		return
+	}
+
+	hotels, ok := hch.getChainHotels(ctx)
+	if !ok {
+		return
+	}
+
+	// Room types of every hotel of the chain in one call
+	roomTypesByHotel := make(map[string][]*room_type_pb.GetRoomTypesByHotelIdRow, len(hotels))
+	roomTypeIds := []string{}
+	if len(hotels) > 0 {
+		roomTypes, err := hch.roomTypeClient.GetRoomTypesByHotelIds(ctx, &room_type_pb.GetRoomTypesByHotelIdsRequest{
+			HotelIds: chainHotelIds(hotels),
+		})
+		if err != nil {
+			respondHotelError(ctx, err, "Loi khong lay duoc danh sach loai phong cua chuoi")
+			return
+		}
+
+		for _, roomType := range roomTypes.GetRoomTypes() {
+			roomTypesByHotel[roomType.GetHotelId()] = append(roomTypesByHotel[roomType.GetHotelId()], roomType)
+			roomTypeIds = append(roomTypeIds, roomType.GetId())
+		}
+	}
+
+	numberOccupiedMap := make(map[string]int32)
+	if len(roomTypeIds) > 0 {
+		occupied, err := hch.bookingClient.GetNumberOfOccupiedRooms(ctx, &booking_pb.GetNumberOfOccupiedRoomsRequest{
+			RoomTypeIds: roomTypeIds,
+			CheckIn:     checkIn,
+			CheckOut:    checkOut,
+		})
+		if err != nil {
+			respondHotelError(ctx, err, "Loi khong lay duoc so phong da duoc book")
+			return
+		}
+
+		for _, result := range occupied.GetResults() {
+			numberOccupiedMap[result.GetRoomTypeId()] = result.GetNumberOfOccupiedRooms()
+		}
+	}
+
+	resp := api_dto.ChainAvailabilityResponse{
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+		Hotels:   make([]api_dto.ChainHotelAvailability, 0, len(hotels)),
+	}
+	for _, hotel := range hotels {
+		hotelAvailability := api_dto.ChainHotelAvailability{
+			HotelId:   hotel.GetId(),
+			Name:      hotel.GetName(),
+			Status:    hotel.GetStatus(),
+			RoomTypes: make([]api_dto.ChainRoomTypeAvailability, 0, len(roomTypesByHotel[hotel.GetId()])),
+		}
+
+		for _, roomType := range roomTypesByHotel[hotel.GetId()] {
+			numberOfRooms := int32(roomType.GetNumberOfRooms())
+			// Overbooked room types have no room left, not a negative number
+			available := max(numberOfRooms-numberOccupiedMap[roomType.GetId()], 0)
+
+			hotelAvailability.RoomTypes = append(hotelAvailability.RoomTypes, api_dto.ChainRoomTypeAvailability{
+				Id:                     roomType.GetId(),
+				Name:                   roomType.GetName(),
+				Price:                  roomType.GetPrice(),
+				NumberOfRooms:          numberOfRooms,
+				NumberOfAvailableRooms: available,
+			})
+			hotelAvailability.TotalRooms += numberOfRooms
+			hotelAvailability.AvailableRooms += available
+		}
+
+		resp.TotalRooms += hotelAvailability.TotalRooms
+		resp.AvailableRooms += hotelAvailability.AvailableRooms
+		resp.Hotels = append(resp.Hotels, hotelAvailability)
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(resp, "Thanh cong"))
+}
+
+// Same query as analytics of a hotel, for every hotel in one call. Each hotel keeps its own total per period,
+// totals of the chain add them up and recompute occupancy, ADR and RevPAR
+func (hch *HotelChainHandler) GetChainAnalytics(ctx *gin.Context) {
+	hotels, ok := hch.getChainHotels(ctx)
+	if !ok {
+		return
+	}
+
+	resp := api_dto.ChainAnalyticsResponse{
+		Totals: []*booking_pb.AnalyticsPeriod{},
+		Hotels: make([]api_dto.ChainHotelAnalytics, 0, len(hotels)),
+	}
+	totals := map[string]*booking_pb.AnalyticsPeriod{}
+
+	periodsByHotel, ok := hch.fetchChainAnalytics(ctx, hotels)
+	if !ok {
+		return
+	}
+
+	for _, hotel := range hotels {
+		hotelAnalytics := api_dto.ChainHotelAnalytics{
+			HotelId: hotel.GetId(),
+			Name:    hotel.GetName(),
+			Periods: []*booking_pb.AnalyticsPeriod{},
+		}
+
+		for _, period := range periodsByHotel[hotel.GetId()] {
+			// Room types are only meaningful inside their hotel
+			if period.GetRoomTypeId() != "" {
+				continue
+			}
+			hotelAnalytics.Periods = append(hotelAnalytics.Periods, period)
+
+			total, ok := totals[period.GetPeriodStart()]
+			if !ok {
+				total = &booking_pb.AnalyticsPeriod{PeriodStart: period.GetPeriodStart()}
+				totals[period.GetPeriodStart()] = total
+				resp.Totals = append(resp.Totals, total)
+			}
+			total.RoomNightsAvailable += period.GetRoomNightsAvailable()
+			total.RoomNightsSold += period.GetRoomNightsSold()
+			total.RoomRevenue += period.GetRoomRevenue()
+			total.BookingsCreated += period.GetBookingsCreated()
+			total.RoomNightsBooked += period.GetRoomNightsBooked()
+			total.BookedRevenue += period.GetBookedRevenue()
+		}
+
+		resp.Hotels = append(resp.Hotels, hotelAnalytics)
+	}
+
+	// Same formulas as analytics of a hotel
+	for _, total := range resp.Totals {
+		if total.RoomNightsAvailable > 0 {
+			total.OccupancyRate = float64(total.RoomNightsSold) * 100 / float64(total.RoomNightsAvailable)
+			total.RevPar = float64(total.RoomRevenue) / float64(total.RoomNightsAvailable)
+		}
+		if total.RoomNightsSold > 0 {
+			total.Adr = float64(total.RoomRevenue) / float64(total.RoomNightsSold)
+		}
+	}
+
+	slices.SortFunc(resp.Totals, func(a, b *booking_pb.AnalyticsPeriod) int {
+		return strings.Compare(a.GetPeriodStart(), b.GetPeriodStart())
+	})
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(resp, "Thanh cong"))
+}
+
+// Room counts then analytics of every hotel in one call each, by hotel id. Write the error response when failed
+func (hch *HotelChainHandler) fetchChainAnalytics(ctx *gin.Context, hotels []*hotel_pb.Hotel) (map[string][]*booking_pb.AnalyticsPeriod, bool) {
+	periodsByHotel := make(map[string][]*booking_pb.AnalyticsPeriod, len(hotels))
+	if len(hotels) == 0 {
+		return periodsByHotel, true
+	}
+
+	hotelIds := chainHotelIds(hotels)
+
+	roomCounts, err := hch.roomClient.GetNumberOfRoomsPerRoomTypeByHotelIds(ctx, &room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsRequest{
+		HotelIds: hotelIds,
+	})
+	if err != nil {
+		zap.S().Infoln("Failed to get number of rooms per room type: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc so luong phong"))
+		return nil, false
+	}
+
+	req := &booking_pb.GetHotelsAnalyticsRequest{
+		HotelIds:    hotelIds,
+		StartDate:   ctx.Query("start_date"),
+		EndDate:     ctx.Query("end_date"),
+		Granularity: ctx.DefaultQuery("granularity", "day"),
+	}
+	for _, roomCount := range roomCounts.GetResults() {
+		req.RoomCounts = append(req.RoomCounts, &booking_pb.RoomTypeRoomCount{
+			RoomTypeId:    roomCount.GetRoomTypeId(),
+			NumberOfRooms: roomCount.GetNumberOfRooms(),
+			HotelId:       roomCount.GetHotelId(),
+		})
+	}
+
+	result, err := hch.bookingClient.GetHotelsAnalytics(ctx, req)
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return nil, false
+		}
+
+		zap.S().Infoln("Failed to get chain analytics: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc thong ke"))
+		return nil, false
+	}
+
+	for _, hotel := range result.GetHotels() {
+		periodsByHotel[hotel.GetHotelId()] = hotel.GetPeriods()
+	}
+
+	return periodsByHotel, true
+}
+
+func chainHotelIds(hotels []*hotel_pb.Hotel) []string {
+	hotelIds := make([]string, 0, len(hotels))
+	for _, hotel := range hotels {
+		hotelIds = append(hotelIds, hotel.GetId())
+	}
+
+	return hotelIds
+}
+
+// Hotels of the chain in ":id" param, only the one in hotel_id query when given.
+// Write the error response when failed
+func (hch *HotelChainHandler) getChainHotels(ctx *gin.Context) ([]*hotel_pb.Hotel, bool) {
+	result, err := hch.hotelClient.GetHotelsByChainId(ctx, &hotel_pb.GetHotelsByChainIdRequest{
+		ChainId: ctx.Param("id"),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong lay duoc khach san cua chuoi")
+		return nil, false
+	}
+
+	hotelId := ctx.Query("hotel_id")
+	if hotelId == "" {
+		return result.GetHotels(), true
+	}
+
+	index := slices.IndexFunc(result.GetHotels(), func(hotel *hotel_pb.Hotel) bool {
+		return hotel.GetId() == hotelId
+	})
+	if index < 0 {
+		ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khach san khong thuoc chuoi nay"))
+		return nil, false
+	}
+
+	return result.GetHotels()[index : index+1], true
+}
+
+func toHotelChainDto(chain *hotel_pb.HotelChain, hotels []*hotel_pb.Hotel) api_dto.HotelChainResponse {
+	resp := api_dto.HotelChainResponse{
+		Id:          chain.GetId(),
+		Name:        chain.GetName(),
+		Brand:       chain.GetBrand(),
+		Description: chain.GetDescription(),
+		CreateAt:    chain.GetCreateAt(),
+	}
+
+	for _, hotel := range hotels {
+		resp.Hotels = append(resp.Hotels, api_dto.HotelResponse{
+			Id:          hotel.GetId(),
+			Name:        hotel.GetName(),
+			Address:     hotel.GetAddress(),
+			City:        hotel.GetCity(),
+			Description: hotel.GetDescription(),
+			Location:    toHotelLocation(hotel.GetLocation()),
+			Status:      hotel.GetStatus(),
+			ChainId:     hotel.GetChainId(),
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,219 @@
+package api_handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	api_dto "github.com/098765432m/grpc-kafka/api-gateway/internal/dto"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// More hotels than a page, every one of them must be counted
+const chainHotelCount = 120
+
+type fakeRoomTypeClient struct {
+	room_type_pb.RoomTypeServiceClient
+	calls int
+}
+
+// One room type of 2 rooms per hotel
+func (fc *fakeRoomTypeClient) GetRoomTypesByHotelIds(ctx context.Context, in *room_type_pb.GetRoomTypesByHotelIdsRequest, opts ...grpc.CallOption) (*room_type_pb.GetRoomTypesByHotelIdsResponse, error) {
+	fc.calls++
+
+	resp := &room_type_pb.GetRoomTypesByHotelIdsResponse{}
+	for _, hotelId := range in.GetHotelIds() {
+		resp.RoomTypes = append(resp.RoomTypes, &room_type_pb.GetRoomTypesByHotelIdRow{
+			Id:            "room-type-" + hotelId,
+			HotelId:       hotelId,
+			NumberOfRooms: 2,
+		})
+	}
+	return resp, nil
+}
+
+type fakeRoomClient struct {
+	room_pb.RoomServiceClient
+	calls int
+}
+
+func (fc *fakeRoomClient) GetNumberOfRoomsPerRoomTypeByHotelIds(ctx context.Context, in *room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsRequest, opts ...grpc.CallOption) (*room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsResponse, error) {
+	fc.calls++
+
+	resp := &room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsResponse{}
+	for _, hotelId := range in.GetHotelIds() {
+		resp.Results = append(resp.Results, &room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsRow{
+			RoomTypeId:    "room-type-" + hotelId,
+			HotelId:       hotelId,
+			NumberOfRooms: 2,
+		})
+	}
+	return resp, nil
+}
+
+type fakeBookingClient struct {
+	booking_pb.BookingServiceClient
+	occupiedCalls  int
+	analyticsCalls int
+	roomCounts     []*booking_pb.RoomTypeRoomCount
+	searchReq      *booking_pb.SearchBookingsRequest
+	searchErr      error
+	// Booking found by its confirmation code, NotFound when nil
+	bookingByCode *booking_pb.Booking
+}
+
+func (fc *fakeBookingClient) GetBookingByConfirmationCode(ctx context.Context, in *booking_pb.GetBookingByConfirmationCodeRequest, opts ...grpc.CallOption) (*booking_pb.Booking, error) {
+	if fc.bookingByCode == nil || fc.bookingByCode.GetConfirmationCode() != in.GetConfirmationCode() {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return fc.bookingByCode, nil
+}
+
+// One occupied room per room type
+func (fc *fakeBookingClient) GetNumberOfOccupiedRooms(ctx context.Context, in *booking_pb.GetNumberOfOccupiedRoomsRequest, opts ...grpc.CallOption) (*booking_pb.GetNumberOfOccupiedRoomsResponse, error) {
+	fc.occupiedCalls++
+
+	resp := &booking_pb.GetNumberOfOccupiedRoomsResponse{}
+	for _, roomTypeId := range in.GetRoomTypeIds() {
+		resp.Results = append(resp.Results, &booking_pb.ResultNumberOfOccupiedRooms{
+			RoomTypeId:            roomTypeId,
+			NumberOfOccupiedRooms: 1,
+		})
+	}
+	return resp, nil
+}
+
+// One night of each hotel with one of its 2 rooms sold
+func (fc *fakeBookingClient) GetHotelsAnalytics(ctx context.Context, in *booking_pb.GetHotelsAnalyticsRequest, opts ...grpc.CallOption) (*booking_pb.GetHotelsAnalyticsResponse, error) {
+	fc.analyticsCalls++
+	fc.roomCounts = in.GetRoomCounts()
+
+	resp := &booking_pb.GetHotelsAnalyticsResponse{}
+	for _, hotelId := range in.GetHotelIds() {
+		resp.Hotels = append(resp.Hotels, &booking_pb.HotelAnalytics{
+			HotelId: hotelId,
+			Periods: []*booking_pb.AnalyticsPeriod{
+				{PeriodStart: "2026-03-02", RoomNightsAvailable: 2, RoomNightsSold: 1, RoomRevenue: 100},
+				{PeriodStart: "2026-03-02", RoomTypeId: "room-type-" + hotelId, RoomNightsAvailable: 2, RoomNightsSold: 1, RoomRevenue: 100},
+			},
+		})
+	}
+	return resp, nil
+}
+
+func (fc *fakeBookingClient) SearchBookings(ctx context.Context, in *booking_pb.SearchBookingsRequest, opts ...grpc.CallOption) (*booking_pb.SearchBookingsResponse, error) {
+	fc.searchReq = in
+	if fc.searchErr != nil {
+		return nil, fc.searchErr
+	}
+	return &booking_pb.SearchBookingsResponse{Bookings: []*booking_pb.Booking{{Id: "booking-1"}}}, nil
+}
+
+func newChainHotelClient() *fakeHotelClient {
+	hotels := make([]*hotel_pb.Hotel, 0, chainHotelCount)
+	for i := range chainHotelCount {
+		hotels = append(hotels, &hotel_pb.Hotel{Id: fmt.Sprintf("hotel-%d", i), Name: fmt.Sprintf("Hotel %d", i)})
+	}
+
+	return &fakeHotelClient{chainHotels: hotels}
+}
+
+func TestGetChainAvailabilityInOneCall(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	roomTypeClient := &fakeRoomTypeClient{}
+	bookingClient := &fakeBookingClient{}
+	hch := &HotelChainHandler{hotelClient: newChainHotelClient(), roomTypeClient: roomTypeClient, bookingClient: bookingClient}
+
+	router := gin.New()
+	router.GET("/chains/:id/availability", hch.GetChainAvailability)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/chains/chain-1/availability?check_in=2026-03-02&check_out=2026-03-03", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GetChainAvailability() status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if roomTypeClient.calls != 1 || bookingClient.occupiedCalls != 1 {
+		t.Errorf("GetChainAvailability() calls = %d room types and %d occupied rooms, want 1 of each", roomTypeClient.calls, bookingClient.occupiedCalls)
+	}
+
+	var body struct {
+		Result api_dto.ChainAvailabilityResponse `json:"result"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(body.Result.Hotels) != chainHotelCount {
+		t.Errorf("GetChainAvailability() hotels = %d, want %d", len(body.Result.Hotels), chainHotelCount)
+	}
+	if body.Result.TotalRooms != 2*chainHotelCount || body.Result.AvailableRooms != chainHotelCount {
+		t.Errorf("GetChainAvailability() rooms = %d available of %d, want %d of %d", body.Result.AvailableRooms, body.Result.TotalRooms, chainHotelCount, 2*chainHotelCount)
+	}
+	for _, hotel := range body.Result.Hotels {
+		if len(hotel.RoomTypes) != 1 || hotel.RoomTypes[0].Id != "room-type-"+hotel.HotelId {
+			t.Errorf("GetChainAvailability() room types of %s = %v, want only its own", hotel.HotelId, hotel.RoomTypes)
+		}
+	}
+}
+
+func TestGetChainAnalyticsInOneCall(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	roomClient := &fakeRoomClient{}
+	bookingClient := &fakeBookingClient{}
+	hch := &HotelChainHandler{hotelClient: newChainHotelClient(), roomClient: roomClient, bookingClient: bookingClient}
+
+	router := gin.New()
+	router.GET("/chains/:id/analytics", hch.GetChainAnalytics)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/chains/chain-1/analytics?start_date=2026-03-02&end_date=2026-03-03", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GetChainAnalytics() status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	if roomClient.calls != 1 || bookingClient.analyticsCalls != 1 {
+		t.Errorf("GetChainAnalytics() calls = %d room counts and %d analytics, want 1 of each", roomClient.calls, bookingClient.analyticsCalls)
+	}
+	for _, roomCount := range bookingClient.roomCounts {
+		if roomCount.GetHotelId() == "" {
+			t.Errorf("GetChainAnalytics() room count of %s has no hotel", roomCount.GetRoomTypeId())
+		}
+	}
+
+	var body struct {
+		Result api_dto.ChainAnalyticsResponse `json:"result"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(body.Result.Hotels) != chainHotelCount {
+		t.Errorf("GetChainAnalytics() hotels = %d, want %d", len(body.Result.Hotels), chainHotelCount)
+	}
+	for _, hotel := range body.Result.Hotels {
+		if len(hotel.Periods) != 1 {
+			t.Errorf("GetChainAnalytics() periods of %s = %d, want only the hotel total", hotel.HotelId, len(hotel.Periods))
+		}
+	}
+
+	if len(body.Result.Totals) != 1 {
+		t.Fatalf("GetChainAnalytics() totals = %d, want 1", len(body.Result.Totals))
+	}
+	total := body.Result.Totals[0]
+	if total.GetRoomNightsAvailable() != 2*chainHotelCount || total.GetRoomNightsSold() != chainHotelCount || total.GetOccupancyRate() != 50 {
+		t.Errorf("GetChainAnalytics() total = %+v, want %d of %d room nights sold", total, chainHotelCount, 2*chainHotelCount)
+	}
+}
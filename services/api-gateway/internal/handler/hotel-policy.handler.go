@@ -0,0 +1,132 @@
+package api_handler
+
+import (
+	"net/http"
+
+	api_dto "github.com/098765432m/grpc-kafka/api-gateway/internal/dto"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Policy document of a hotel, edited by its manager
+type HotelPolicyHandler struct {
+	hotelClient hotel_pb.HotelServiceClient
+}
+
+func NewHotelPolicyHandler(hotelClient hotel_pb.HotelServiceClient) *HotelPolicyHandler {
+	return &HotelPolicyHandler{
+		hotelClient: hotelClient,
+	}
+}
+
+func (hph *HotelPolicyHandler) RegisterRoutes(router *gin.RouterGroup) {
+	hotelHandler := router.Group("/hotels")
+
+	hotelHandler.GET("/:id/policy", hph.GetHotelPolicy)
+	hotelHandler.PUT("/:id/policy", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hph.hotelClient), hph.SetHotelPolicy)
+}
+
+// Replaces the whole policy, clock times are HH:MM
+type HotelPolicyBody struct {
+	CheckInFrom        string            `json:"check_in_from" binding:"required"`
+	CheckInUntil       string            `json:"check_in_until"` // any time after check_in_from when empty
+	CheckOutUntil      string            `json:"check_out_until" binding:"required"`
+	PetsAllowed        bool              `json:"pets_allowed"`
+	PetFee             int               `json:"pet_fee" binding:"min=0"`
+	ChildrenAllowed    bool              `json:"children_allowed"`
+	ChildrenFreeMaxAge *int              `json:"children_free_max_age" binding:"omitempty,min=0"`
+	ExtraBedAvailable  bool              `json:"extra_bed_available"`
+	ExtraBedFee        int               `json:"extra_bed_fee" binding:"min=0"`
+	PaymentMethods     []string          `json:"payment_methods"`
+	HouseRules         map[string]string `json:"house_rules"` // locale -> house rules
+}
+
+func (hph *HotelPolicyHandler) GetHotelPolicy(ctx *gin.Context) {
+	result, err := hph.hotelClient.GetHotelPolicy(ctx, &hotel_pb.GetHotelPolicyRequest{
+		HotelId: ctx.Param("id"),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong lay duoc chinh sach khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toHotelPolicyDto(result.GetPolicy()), "Thanh cong"))
+}
+
+func (hph *HotelPolicyHandler) SetHotelPolicy(ctx *gin.Context) {
+	var reqBody HotelPolicyBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	var childrenFreeMaxAge *int32
+	if reqBody.ChildrenFreeMaxAge != nil {
+		age := int32(*reqBody.ChildrenFreeMaxAge)
+		childrenFreeMaxAge = &age
+	}
+
+	result, err := hph.hotelClient.SetHotelPolicy(ctx, &hotel_pb.SetHotelPolicyRequest{
+		HotelId: ctx.Param("id"),
+		Policy: &hotel_pb.HotelPolicy{
+			CheckInFrom:        reqBody.CheckInFrom,
+			CheckInUntil:       reqBody.CheckInUntil,
+			CheckOutUntil:      reqBody.CheckOutUntil,
+			PetsAllowed:        reqBody.PetsAllowed,
+			PetFee:             int32(reqBody.PetFee),
+			ChildrenAllowed:    reqBody.ChildrenAllowed,
+			ChildrenFreeMaxAge: childrenFreeMaxAge,
+			ExtraBedAvailable:  reqBody.ExtraBedAvailable,
+			ExtraBedFee:        int32(reqBody.ExtraBedFee),
+			PaymentMethods:     reqBody.PaymentMethods,
+			HouseRules:         reqBody.HouseRules,
+		},
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong cap nhat duoc chinh sach khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toHotelPolicyDto(result.GetPolicy()), "Cap nhat chinh sach thanh cong"))
+}
+
+// nil when the hotel has no policy
+func toHotelPolicyDto(policy *hotel_pb.HotelPolicy) *api_dto.HotelPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	var childrenFreeMaxAge *int
+	if policy.ChildrenFreeMaxAge != nil {
+		age := int(policy.GetChildrenFreeMaxAge())
+		childrenFreeMaxAge = &age
+	}
+
+	paymentMethods := policy.GetPaymentMethods()
+	if paymentMethods == nil {
+		paymentMethods = []string{}
+	}
+
+	houseRules := policy.GetHouseRules()
+	if houseRules == nil {
+		houseRules = map[string]string{}
+	}
+
+	return &api_dto.HotelPolicy{
+		CheckInFrom:        policy.GetCheckInFrom(),
+		CheckInUntil:       policy.GetCheckInUntil(),
+		CheckOutUntil:      policy.GetCheckOutUntil(),
+		PetsAllowed:        policy.GetPetsAllowed(),
+		PetFee:             int(policy.GetPetFee()),
+		ChildrenAllowed:    policy.GetChildrenAllowed(),
+		ChildrenFreeMaxAge: childrenFreeMaxAge,
+		ExtraBedAvailable:  policy.GetExtraBedAvailable(),
+		ExtraBedFee:        int(policy.GetExtraBedFee()),
+		PaymentMethods:     paymentMethods,
+		HouseRules:         houseRules,
+	}
+}
@@ -1,17 +1,25 @@
 package api_handler
 
 import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	api_dto "github.com/098765432m/grpc-kafka/api-gateway/internal/dto"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/image_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/pagination_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/rating_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/model"
 	"github.com/098765432m/grpc-kafka/common/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -55,21 +63,66 @@ func (hh *HotelHandler) RegisterRoutes(router *gin.RouterGroup) {
 	hotelHandler := router.Group("/hotels")
 
 	hotelHandler.GET("/", hh.GetAll)
+	hotelHandler.POST("", common_middleware.AuthMiddleware(), common_middleware.RequireRoles(model.MANAGER_ROLE, model.ADMIN_ROLE), hh.CreateHotel)
+	hotelHandler.GET("/review", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), hh.GetHotelsForReview)
 
-	hotelHandler.GET("/:id", hh.GetHotelById)
+	hotelHandler.GET("/:id", common_middleware.OptionalAuthMiddleware(), hh.GetHotelById)
+	hotelHandler.PUT("/:id", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.UpdateHotel)
+	hotelHandler.DELETE("/:id", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), hh.DeleteHotel)
 	hotelHandler.GET("/:id/room-types", hh.GetRoomTypesByHotelId)
 	hotelHandler.GET("/:id/rooms", hh.GetRoomsByHotelId)
 	hotelHandler.GET("/:id/ratings", hh.GetRatingsByHotelId)
 	hotelHandler.GET("/:id/available-room-types", hh.GetAvailableRoomTypes)
+	hotelHandler.GET("/:id/oversold-nights", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.GetOversoldNights)
+	hotelHandler.PUT("/:id/night-audit-time", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.SetHotelAuditTime)
+	hotelHandler.POST("/:id/night-audit", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.RunNightAudit)
+	hotelHandler.GET("/:id/night-audit-reports", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.GetNightAuditReports)
+	hotelHandler.GET("/:id/bookings", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.SearchBookings)
+	hotelHandler.GET("/:id/analytics", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.GetHotelAnalytics)
+	hotelHandler.GET("/:id/analytics/export", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.ExportHotelAnalytics)
+	hotelHandler.GET("/:id/channel-allotments", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.GetChannelAllotments)
+	hotelHandler.PUT("/:id/channel-allotments", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.SetChannelAllotment)
+	hotelHandler.DELETE("/:id/channel-allotments/:allotmentId", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.DeleteChannelAllotment)
+	hotelHandler.GET("/:id/special-requests", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.GetSpecialRequests)
+	hotelHandler.PUT("/:id/special-requests/:requestId/acknowledge", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(hh.hotelClient), hh.AcknowledgeSpecialRequest)
+
+	// Publication workflow, the creator of a draft submits and publishes it, ADMIN reviews it
+	hotelHandler.POST("/:id/submit", common_middleware.AuthMiddleware(), common_middleware.RequireRoles(model.MANAGER_ROLE, model.ADMIN_ROLE), hh.SubmitHotel)
+	hotelHandler.POST("/:id/approve", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), hh.ApproveHotel)
+	hotelHandler.POST("/:id/reject", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), hh.RejectHotel)
+	hotelHandler.POST("/:id/publish", common_middleware.AuthMiddleware(), common_middleware.RequireRoles(model.MANAGER_ROLE, model.ADMIN_ROLE), hh.PublishHotel)
+	hotelHandler.GET("/:id/status-history", common_middleware.AuthMiddleware(), common_middleware.RequireRoles(model.MANAGER_ROLE, model.ADMIN_ROLE), hh.GetHotelStatusHistory)
 
 	hotelHandler.GET("/filter", hh.FilterHotels)
+	hotelHandler.GET("/nearby", hh.SearchHotelsByLocation)
+	hotelHandler.GET("/search", hh.SearchHotels)
+	hotelHandler.GET("/autocomplete", hh.AutocompleteHotels)
 }
 
+// Published hotels only
 func (hh *HotelHandler) GetAll(ctx *gin.Context) {
+	hh.listHotels(ctx, "")
+}
+
+// Review queue of ADMIN, SUBMITTED hotels unless the status query asks for another one
+func (hh *HotelHandler) GetHotelsForReview(ctx *gin.Context) {
+	hh.listHotels(ctx, ctx.DefaultQuery("status", "SUBMITTED"))
+}
+
+// Page of the hotels in the publication status with their images, PUBLISHED when status is empty
+func (hh *HotelHandler) listHotels(ctx *gin.Context, status string) {
+
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
 
-	hotels, err := hh.hotelClient.GetAllHotels(ctx, &hotel_pb.GetAllHotelsRequest{})
+	hotels, err := hh.hotelClient.GetAllHotels(ctx, &hotel_pb.GetAllHotelsRequest{
+		Page:   page,
+		Status: strings.ToUpper(status),
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Failed to get all Hotels"))
+		respondHotelError(ctx, err, "Failed to get all Hotels")
 		return
 	}
 
@@ -96,9 +149,14 @@ func (hh *HotelHandler) GetAll(ctx *gin.Context) {
 
 		// Merge Hotel
 		resp := api_dto.HotelResponse{
-			Id:      hotel.Id,
-			Name:    hotel.Name,
-			Address: hotel.Address,
+			Id:          hotel.Id,
+			Name:        hotel.Name,
+			Address:     hotel.Address,
+			City:        hotel.City,
+			Description: hotel.Description,
+			Location:    toHotelLocation(hotel.GetLocation()),
+			Status:      hotel.GetStatus(),
+			ChainId:     hotel.GetChainId(),
 		}
 
 		// Merge Image into hotel
@@ -116,13 +174,14 @@ func (hh *HotelHandler) GetAll(ctx *gin.Context) {
 		responses = append(responses, resp)
 	}
 
-	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(responses, "Hotels retrieved successfully"))
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(responses, hotels.GetPage()), "Hotels retrieved successfully"))
 }
 
 func (hh *HotelHandler) GetHotelById(ctx *gin.Context) {
 
 	id := ctx.Param("id")
-	hotelGrpc, err := hh.hotelClient.GetHotelById(ctx, &hotel_pb.GetHotelByIdRequest{
+	// Unpublished hotels are only found by the users who can manage them
+	hotelGrpc, err := hh.hotelClient.GetHotelById(userContext(ctx), &hotel_pb.GetHotelByIdRequest{
 		Id: id,
 	})
 	hotel := hotelGrpc.GetHotel()
@@ -131,7 +190,7 @@ func (hh *HotelHandler) GetHotelById(ctx *gin.Context) {
 		if ok {
 			switch st.Code() {
 			case codes.NotFound:
-				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Khach san khong ton tai"))
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khach san khong ton tai"))
 				return
 			}
 		}
@@ -141,21 +200,51 @@ func (hh *HotelHandler) GetHotelById(ctx *gin.Context) {
 		return
 	}
 
-	images, err := hh.imageClient.GetImagesByHotelId(ctx, &image_pb.GetImagesByHotelIdRequest{HotelId: hotel.Id})
-	if err != nil {
-		zap.S().Info("Loi ko lay hinh anh khi tim khach san theo ID ", err)
+	// Gallery of the hotel page, every page of images
+	hotelImages := []*image_pb.HotelImage{}
+	for cursor := ""; ; {
+		images, err := hh.imageClient.GetImagesByHotelId(ctx, &image_pb.GetImagesByHotelIdRequest{
+			HotelId: hotel.Id,
+			Page: &pagination_pb.PageRequest{
+				PageSize: utils.MAX_PAGE_SIZE,
+				Cursor:   cursor,
+			},
+		})
+		if err != nil {
+			zap.S().Info("Loi ko lay hinh anh khi tim khach san theo ID ", err)
 
-		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi he thong khi lay hinh anh khach san"))
-		return
+			ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi he thong khi lay hinh anh khach san"))
+			return
+		}
+
+		hotelImages = append(hotelImages, images.GetImages()...)
+
+		cursor = images.GetPage().GetNextCursor()
+		if cursor == "" {
+			break
+		}
+	}
+
+	// The hotel page is still shown, without amenities, when they cannot be loaded
+	amenities, err := hh.hotelClient.GetHotelAmenities(ctx, &hotel_pb.GetHotelAmenitiesRequest{HotelId: hotel.Id})
+	if err != nil {
+		zap.S().Errorln("Loi ko lay tien ich khi tim khach san theo ID ", err)
 	}
 
 	resp := api_dto.HotelResponse{
-		Id:      hotel.GetId(),
-		Name:    hotel.GetName(),
-		Address: hotel.GetAddress(),
+		Id:          hotel.GetId(),
+		Name:        hotel.GetName(),
+		Address:     hotel.GetAddress(),
+		City:        hotel.GetCity(),
+		Description: hotel.GetDescription(),
+		Location:    toHotelLocation(hotel.GetLocation()),
+		Status:      hotel.GetStatus(),
+		ChainId:     hotel.GetChainId(),
+		Amenities:   toAmenitiesDto(amenities.GetAmenities()),
+		Policy:      toHotelPolicyDto(hotelGrpc.GetPolicy()),
 	}
 
-	for _, img := range images.GetImages() {
+	for _, img := range hotelImages {
 		resp.Images = append(resp.Images, api_dto.HotelImage{
 			Id:       img.GetId(),
 			Url:      img.GetUrl(),
@@ -170,11 +259,17 @@ func (hh *HotelHandler) GetHotelById(ctx *gin.Context) {
 func (hh *HotelHandler) GetRatingsByHotelId(ctx *gin.Context) {
 	id := ctx.Param("id")
 
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
 	ratingGrpcResult, err := hh.ratingClient.GetRatingsByHotelId(ctx, &rating_pb.GetRatingsByHotelIdRequest{
 		HotelId: id,
+		Page:    page,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi he thong"))
+		respondHotelError(ctx, err, "Loi he thong")
 		return
 	}
 
@@ -232,10 +327,11 @@ func (hh *HotelHandler) GetRatingsByHotelId(ctx *gin.Context) {
 		user := userMap[rating.GetUserId()] // Get User direct from map
 
 		ratingResult = append(ratingResult, api_dto.RatingResponse{
-			Id:      rating.Id,
-			Rating:  int(rating.Score),
-			Comment: rating.GetComment(),
-			HotelId: rating.GetHotelId(),
+			Id:       rating.Id,
+			Rating:   int(rating.Score),
+			Comment:  rating.GetComment(),
+			HotelId:  rating.GetHotelId(),
+			CreateAt: rating.GetCreateAt(),
 			User: api_dto.RatingUserResponse{
 				UserId:   user.UserId,
 				Username: user.Username,
@@ -244,17 +340,23 @@ func (hh *HotelHandler) GetRatingsByHotelId(ctx *gin.Context) {
 		})
 	}
 
-	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(ratingResult, "Lay binh luan thanh cong"))
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(ratingResult, ratingGrpcResult.GetPage()), "Lay binh luan thanh cong"))
 }
 
 func (hh *HotelHandler) GetRoomTypesByHotelId(ctx *gin.Context) {
 	hotelId := ctx.Param("id")
 
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
 	roomTypesGrpcResult, err := hh.roomTypeClient.GetRoomTypesByHotelId(ctx, &room_type_pb.GetRoomTypesByHotelIdRequest{
 		HotelId: hotelId,
+		Page:    page,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc danh sach loai phong"))
+		respondHotelError(ctx, err, "Loi khong lay duoc danh sach loai phong")
 		return
 	}
 
@@ -284,9 +386,16 @@ func (hh *HotelHandler) GetRoomTypesByHotelId(ctx *gin.Context) {
 	roomTypesResponse := make([]*api_dto.RoomTypeResponse, 0, len(roomTypeIds))
 	for _, roomType := range roomTypesGrpcResult.GetRoomTypes() {
 		roomTypeResponse := &api_dto.RoomTypeResponse{
-			Id:    roomType.Id,
-			Name:  roomType.Name,
-			Price: uint(roomType.Price),
+			Id:             roomType.Id,
+			Name:           roomType.Name,
+			Price:          uint(roomType.Price),
+			HotelId:        roomType.HotelId,
+			AreaSqm:        int(roomType.GetAreaSqm()),
+			View:           roomType.GetView(),
+			SmokingAllowed: roomType.GetSmokingAllowed(),
+			MaxOccupancy:   int(roomType.GetMaxOccupancy()),
+			Description:    roomType.GetDescription(),
+			Beds:           toRoomTypeBedsDto(roomType.GetBeds()),
 		}
 
 		if image, exists := imageMap[roomType.Id]; exists {
@@ -296,21 +405,27 @@ func (hh *HotelHandler) GetRoomTypesByHotelId(ctx *gin.Context) {
 		roomTypesResponse = append(roomTypesResponse, roomTypeResponse)
 	}
 
-	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(roomTypesResponse, "Lay danh sach loai phong thanh cong"))
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(roomTypesResponse, roomTypesGrpcResult.GetPage()), "Lay danh sach loai phong thanh cong"))
 }
 
 func (hh *HotelHandler) GetRoomsByHotelId(ctx *gin.Context) {
 	hotelId := ctx.Param("id")
 
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
 	roomsResult, err := hh.roomClient.GetRoomsByHotelId(ctx, &room_pb.GetRoomsByHotelIdRequest{
 		HotelId: hotelId,
+		Page:    page,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc danh sach phong"))
+		respondHotelError(ctx, err, "Loi khong lay duoc danh sach phong")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(roomsResult.Rooms, "Lay danh sach phong thanh cong"))
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(roomsResult.GetRooms(), roomsResult.GetPage()), "Lay danh sach phong thanh cong"))
 }
 
 func (hh *HotelHandler) GetAvailableRoomTypes(ctx *gin.Context) {
@@ -321,11 +436,17 @@ func (hh *HotelHandler) GetAvailableRoomTypes(ctx *gin.Context) {
 
 	zap.L().Info("Get Dates: ", zap.Any("check_in", checkIn), zap.Any("check_out", checkOut))
 
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
 	roomTypesGrpcResult, err := hh.roomTypeClient.GetRoomTypesByHotelId(ctx, &room_type_pb.GetRoomTypesByHotelIdRequest{
 		HotelId: hotelId,
+		Page:    page,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc danh sach loai phong bang khach san"))
+		respondHotelError(ctx, err, "Loi khong lay duoc danh sach loai phong bang khach san")
 		return
 	}
 
@@ -382,30 +503,11 @@ func (hh *HotelHandler) GetAvailableRoomTypes(ctx *gin.Context) {
 		roomTypes = append(roomTypes, tempRoomType)
 	}
 
-	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(roomTypes, "Thanh cong"))
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(roomTypes, roomTypesGrpcResult.GetPage()), "Thanh cong"))
 }
 
-/*
-Goal: Tra ve Hotel co phong AVAILABLE trong khung thoi gian CheckIn va CheckOut
-Address, va Ten Hotel, trong khung Price
-
-# Tra ve thong tin khach san va gia phong dau tien
-
-1. Tim kiem cac khach san trong khu vuc
--> Return hotelIds (LIMIT 20) /^
-
-2. Tra ve so luong phong per roomType bang HotelIds
--> Return [roomTypeId, So luong phong] /^
-
-3. Su dung cac hotel ids do tra ve cac phong da duoc booking trong khoang thoi gian check in check out
--> Return [roomTypeId, count (so luong phong da dat)] /^
-
-4. O Application, xem neu <so phong da dat> < <so phong> -> AVAILABLE
-
-5. Su dung roomTypeIds de xac dinh khach san con phong nao con trong
--> Return [hotelId, minPrice] (tra ve gia min cua nhung phong trong)
-*/
-
+// Hotels with a room free from check_in to check_out, filtered by name, address, price, area, amenities and room type.
+// The hotel service filters then pages, so total_count is the number of matching hotels
 func (hh *HotelHandler) FilterHotels(ctx *gin.Context) {
 	// Get Request Params
 	hotelName := ctx.Query("hotel_name")
@@ -434,90 +536,913 @@ func (hh *HotelHandler) FilterHotels(ctx *gin.Context) {
 		}
 	}
 
-	zap.L().Info("Check Request of Filter Hotels", zap.Any("hotelName", hotelName), zap.Any("check In", checkIn), zap.Any("Check Out", checkOut), zap.Any("Min Price", minPrice), zap.Any("Max Price", maxPrice))
+	center, radiusKm, boundingBox, err := parseGeoSearchQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Vi tri tim kiem khong hop le"))
+		return
+	}
+
+	amenities := parseAmenitiesQuery(ctx)
 
-	// Get hotels by address
-	resultHotelsByAddress, err := hh.hotelClient.GetHotelsByAddress(ctx, &hotel_pb.GetHotelsByAddressRequest{
-		HotelName: hotelName,
-		Address:   address,
+	minAreaSqm, guests, smokingAllowed, err := parseRoomTypeFilterQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Dieu kien loai phong khong hop le"))
+		return
+	}
+
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	hotelRows, err := hh.hotelClient.FilterHotels(ctx, &hotel_pb.FilterHotelsRequest{
+		HotelName:      hotelName,
+		Address:        address,
+		CheckIn:        checkIn,
+		CheckOut:       checkOut,
+		MinPrice:       int32(minPriceInt),
+		MaxPrice:       int32(maxPriceInt),
+		Center:         center,
+		RadiusKm:       radiusKm,
+		BoundingBox:    boundingBox,
+		Amenities:      amenities,
+		MinAreaSqm:     minAreaSqm,
+		View:           ctx.Query("view"),
+		SmokingAllowed: smokingAllowed,
+		Guests:         guests,
+		Page:           page,
 	})
 	if err != nil {
-		zap.S().Infoln("Failed to get Hotels by Address: ", err)
-		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi he thong"))
+		respondHotelError(ctx, err, "Loi he thong")
 		return
 	}
 
-	zap.L().Info("Filter Hotel By Address ", zap.Any("hotel", resultHotelsByAddress.HotelIds))
-	// GetNumber Of rooms to determine which rooms will be AVAILABLE
-	resultNumberOfRooms, err := hh.roomClient.GetNumberOfRoomsPerRoomTypeByHotelIds(ctx, &room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsRequest{
-		HotelIds: resultHotelsByAddress.GetHotelIds(),
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(hotelRows.GetFilterHotelRows(), hotelRows.GetPage()), "Thanh cong"))
+}
+
+// Hotels within radius_km of lat/lng, or inside min_lat/max_lat/min_lng/max_lng, nearest first
+func (hh *HotelHandler) SearchHotelsByLocation(ctx *gin.Context) {
+	center, radiusKm, boundingBox, err := parseGeoSearchQuery(ctx)
+	if err != nil || (center == nil && boundingBox == nil) {
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Vi tri tim kiem khong hop le"))
+		return
+	}
+
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := hh.hotelClient.SearchHotelsByLocation(ctx, &hotel_pb.SearchHotelsByLocationRequest{
+		Center:      center,
+		RadiusKm:    radiusKm,
+		BoundingBox: boundingBox,
+		Page:        page,
 	})
 	if err != nil {
-		zap.S().Infoln("Failed to get Number of Rooms Per RoomType By HotelIds: ", err)
-		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi he thong"))
+		respondHotelError(ctx, err, "Loi khong tim duoc khach san theo vi tri")
 		return
 	}
 
-	allRoomTypeIds := make([]string, 0, len(resultNumberOfRooms.Results))
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(result.GetHotels(), result.GetPage()), "Thanh cong"))
+}
 
-	// numberOfRoomsInRoomTypesMap := make(map[string]int)
-	for _, result := range resultNumberOfRooms.Results {
-		allRoomTypeIds = append(allRoomTypeIds, result.GetRoomTypeId())
-		// numberOfRoomsInRoomTypesMap[result.RoomTypeId] = int(result.NumberOfRooms)
+// Full text search over name, address, city and description with q, best match first
+func (hh *HotelHandler) SearchHotels(ctx *gin.Context) {
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
 	}
 
-	zap.S().Infoln("All Room TypeIds")
-	zap.S().Infoln(allRoomTypeIds)
+	result, err := hh.hotelClient.SearchHotels(ctx, &hotel_pb.SearchHotelsRequest{
+		Query: ctx.Query("q"),
+		Page:  page,
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong tim kiem duoc khach san")
+		return
+	}
 
-	// Get Number of rooms Occupied in booked time
-	resultNumberOfOccupiedRooms, err := hh.bookingClient.GetNumberOfOccupiedRooms(ctx, &booking_pb.GetNumberOfOccupiedRoomsRequest{
-		RoomTypeIds: allRoomTypeIds,
-		CheckIn:     checkIn,
-		CheckOut:    checkOut,
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(result.GetHotels(), result.GetPage()), "Thanh cong"))
+}
+
+// Hotels and cities starting with what the user typed in the search box (q)
+func (hh *HotelHandler) AutocompleteHotels(ctx *gin.Context) {
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := hh.hotelClient.AutocompleteHotels(ctx, &hotel_pb.AutocompleteHotelsRequest{
+		Prefix: ctx.Query("q"),
+		Page:   page,
 	})
 	if err != nil {
-		zap.S().Infoln("Failed to get Number of Occupied Rooms: ", err)
-		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi he thong"))
+		respondHotelError(ctx, err, "Loi khong goi y duoc khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(gin.H{
+		"hotels": toPageResponse(result.GetHotels(), result.GetPage()),
+		"cities": result.GetCities(),
+	}, "Thanh cong"))
+}
+
+// Return nights that have overbooked bookings without assigned room, so staff can walk or relocate guests
+func (hh *HotelHandler) GetOversoldNights(ctx *gin.Context) {
+	hotelId := ctx.Param("id")
+	startDate := ctx.Query("start_date")
+	endDate := ctx.Query("end_date")
+
+	result, err := hh.bookingClient.GetOversoldNights(ctx, &booking_pb.GetOversoldNightsRequest{
+		HotelId:   hotelId,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+				return
+			}
+		}
+
+		zap.S().Infoln("Failed to get oversold nights: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc danh sach dem overbooking"))
 		return
 	}
 
-	numberOfOccupiedRoomsInRoomTypesMap := make(map[string]int)
-	for _, result := range resultNumberOfOccupiedRooms.Results {
-		numberOfOccupiedRoomsInRoomTypesMap[result.RoomTypeId] = int(result.NumberOfOccupiedRooms)
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetOversoldNights(), "Thanh cong"))
+}
+
+// Coordinates are geocoded from the address when not given
+type HotelBody struct {
+	Name        string   `json:"name" binding:"required,max=255"`
+	Address     string   `json:"address" binding:"required"`
+	City        string   `json:"city" binding:"max=255"`
+	Description string   `json:"description"`
+	Latitude    *float64 `json:"latitude" binding:"omitempty,gte=-90,lte=90"`
+	Longitude   *float64 `json:"longitude" binding:"omitempty,gte=-180,lte=180"`
+}
+
+// Latitude and longitude must be given together
+func (hb HotelBody) location() (*hotel_pb.GeoPoint, bool) {
+	if hb.Latitude == nil && hb.Longitude == nil {
+		return nil, true
 	}
 
-	zap.L().Info("Nunber of Occupied Rooms", zap.Any("result", resultNumberOfOccupiedRooms))
+	if hb.Latitude == nil || hb.Longitude == nil {
+		return nil, false
+	}
 
-	// Determine which roomtype is available
-	// If occupied < total_rooms is AVAILABLE
-	availableRoomTypeIds := make([]string, 0, len(allRoomTypeIds))
+	return &hotel_pb.GeoPoint{
+		Latitude:  *hb.Latitude,
+		Longitude: *hb.Longitude,
+	}, true
+}
+
+func (hh *HotelHandler) CreateHotel(ctx *gin.Context) {
+	var reqBody HotelBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	location, ok := reqBody.location()
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Can nhap ca vi do va kinh do"))
+		return
+	}
 
-	for _, result := range resultNumberOfRooms.Results {
-		if int32(numberOfOccupiedRoomsInRoomTypesMap[result.RoomTypeId]) < result.NumberOfRooms {
-			availableRoomTypeIds = append(availableRoomTypeIds, result.RoomTypeId)
+	// The hotel is a draft of the signed in user until it is reviewed and published
+	result, err := hh.hotelClient.CreateHotel(actorContext(ctx), &hotel_pb.CreateHotelRequest{
+		Name:        reqBody.Name,
+		Address:     reqBody.Address,
+		City:        reqBody.City,
+		Description: reqBody.Description,
+		Location:    location,
+		CreatedBy:   ctx.GetString(common_middleware.AUTH_USER_ID_KEY),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong tao duoc khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(result.GetHotel(), "Tao ban nhap khach san thanh cong"))
+}
+
+func (hh *HotelHandler) UpdateHotel(ctx *gin.Context) {
+	var reqBody HotelBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	location, ok := reqBody.location()
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Can nhap ca vi do va kinh do"))
+		return
+	}
+
+	result, err := hh.hotelClient.UpdateHotel(ctx, &hotel_pb.UpdateHotelRequest{
+		Id:          ctx.Param("id"),
+		Name:        reqBody.Name,
+		Address:     reqBody.Address,
+		City:        reqBody.City,
+		Description: reqBody.Description,
+		Location:    location,
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong cap nhat duoc khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetHotel(), "Cap nhat thanh cong"))
+}
+
+// Bookings and images of the hotel are deleted with it
+func (hh *HotelHandler) DeleteHotel(ctx *gin.Context) {
+	_, err := hh.hotelClient.DeleteHotel(actorContext(ctx), &hotel_pb.DeleteHotelRequest{
+		Id: ctx.Param("id"),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong xoa duoc khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Xoa khach san thanh cong"))
+}
+
+type SetHotelAuditTimeBody struct {
+	AuditTime string `json:"audit_time" binding:"required"` // HH:MM
+	Timezone  string `json:"timezone"`                      // IANA name, empty keeps the current one
+}
+
+func (hh *HotelHandler) SetHotelAuditTime(ctx *gin.Context) {
+	hotelId := ctx.Param("id")
+
+	var reqBody SetHotelAuditTimeBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	_, err := hh.bookingClient.SetHotelAuditTime(ctx, &booking_pb.SetHotelAuditTimeRequest{
+		HotelId:   hotelId,
+		AuditTime: reqBody.AuditTime,
+		Timezone:  reqBody.Timezone,
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+				return
+			}
 		}
+
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong cap nhat duoc gio audit"))
+		return
 	}
 
-	zap.S().Infoln("Available Room Type Ids")
-	zap.S().Infoln(availableRoomTypeIds)
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Cap nhat thanh cong"))
+}
 
-	zap.L().Info("Check Before filter: ", zap.Any("RoomType IDs", availableRoomTypeIds), zap.Any("Min Price", minPrice), zap.Any("Max Price", maxPrice))
+type RunNightAuditBody struct {
+	BusinessDate string `json:"business_date" binding:"required"`
+}
 
-	// Get Hotel and Min Price through AVAILABLE Room Type
-	hotelRows, err := hh.hotelClient.FilterHotels(ctx, &hotel_pb.FilterHotelsRequest{
-		RoomTypeIds: availableRoomTypeIds,
-		MinPrice:    int32(minPriceInt),
-		MaxPrice:    int32(maxPriceInt),
+// Manually run the night audit, safe to run again for the same date
+func (hh *HotelHandler) RunNightAudit(ctx *gin.Context) {
+	hotelId := ctx.Param("id")
+
+	var reqBody RunNightAuditBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	result, err := hh.bookingClient.RunNightAudit(actorContext(ctx), &booking_pb.RunNightAuditRequest{
+		HotelId:      hotelId,
+		BusinessDate: reqBody.BusinessDate,
 	})
 	if err != nil {
-		zap.S().Infoln("Failed to FilterHotels: ", err)
-		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi he thong"))
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+				return
+			}
+		}
+
+		zap.S().Infoln("Failed to run night audit: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong chay duoc night audit"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetReport(), "Thanh cong"))
+}
+
+func (hh *HotelHandler) GetNightAuditReports(ctx *gin.Context) {
+	hotelId := ctx.Param("id")
+	startDate := ctx.Query("start_date")
+	endDate := ctx.Query("end_date")
+
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := hh.bookingClient.GetNightAuditReports(ctx, &booking_pb.GetNightAuditReportsRequest{
+		HotelId:   hotelId,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Page:      page,
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+				return
+			}
+		}
+
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc bao cao night audit"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(result.GetReports(), result.GetPage()), "Thanh cong"))
+}
+
+// Search bookings of the hotel for its managers and front desk
+func (hh *HotelHandler) SearchBookings(ctx *gin.Context) {
+	searchBookings(ctx, hh.userClient, hh.bookingClient, &booking_pb.SearchBookingsRequest{
+		HotelId: ctx.Param("id"),
+	})
+}
+
+// Fill the filters of req from the query, search bookings then write the page.
+// The hotels to search are set by the caller
+func searchBookings(ctx *gin.Context, userClient user_pb.UserServiceClient, bookingClient booking_pb.BookingServiceClient, req *booking_pb.SearchBookingsRequest) {
+
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
+	req.CheckInFrom = ctx.Query("check_in_from")
+	req.CheckInTo = ctx.Query("check_in_to")
+	req.CheckOutFrom = ctx.Query("check_out_from")
+	req.CheckOutTo = ctx.Query("check_out_to")
+	req.Status = ctx.Query("status")
+	req.ConfirmationCode = ctx.Query("confirmation_code")
+	req.RoomId = ctx.Query("room_id")
+	req.Page = page
+
+	// Guest name is stored in user service, find every matched user id first
+	if guestName := ctx.Query("guest_name"); guestName != "" {
+		userIds, err := searchAllUserIdsByFullName(ctx, userClient, guestName)
+		if err != nil {
+			zap.S().Infoln("Failed to search users by full name: ", err)
+			ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong tim duoc khach hang"))
+			return
+		}
+
+		if len(userIds) == 0 {
+			ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse([]*booking_pb.Booking{}, nil), "Thanh cong"))
+			return
+		}
+
+		req.UserIds = userIds
+		req.FilterByUserIds = true
+	}
+
+	result, err := bookingClient.SearchBookings(ctx, req)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+				return
+			}
+		}
+
+		zap.S().Infoln("Failed to search bookings: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong tim duoc dat phong"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(result.GetBookings(), result.GetPage()), "Thanh cong"))
+}
+
+// Walk every page of users matched by name, so the guest filter is not cut short
+func searchAllUserIdsByFullName(ctx *gin.Context, userClient user_pb.UserServiceClient, fullName string) ([]string, error) {
+
+	var userIds []string
+	afterId := ""
+	for {
+		users, err := userClient.SearchUserIdsByFullName(ctx, &user_pb.SearchUserIdsByFullNameRequest{
+			FullName: fullName,
+			AfterId:  afterId,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		userIds = append(userIds, users.GetIds()...)
+		if users.GetNextAfterId() == "" {
+			return userIds, nil
+		}
+		afterId = users.GetNextAfterId()
+	}
+}
+
+func (hh *HotelHandler) GetHotelAnalytics(ctx *gin.Context) {
+	periods, ok := hh.getHotelAnalytics(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(periods, "Thanh cong"))
+}
+
+// Same analytics as GetHotelAnalytics in a CSV file
+func (hh *HotelHandler) ExportHotelAnalytics(ctx *gin.Context) {
+	periods, ok := hh.getHotelAnalytics(ctx)
+	if !ok {
 		return
 	}
 
-	response := hotelRows.FilterHotelRows
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=analytics-%s.csv", ctx.Param("id")))
+
+	writer := csv.NewWriter(ctx.Writer)
+	writer.Write([]string{
+		"period_start", "room_type_id", "room_nights_available", "room_nights_sold", "room_revenue",
+		"occupancy_rate", "adr", "rev_par", "bookings_created", "room_nights_booked", "booked_revenue",
+	})
+	for _, period := range periods {
+		roomTypeId := period.GetRoomTypeId()
+		if roomTypeId == "" {
+			roomTypeId = "ALL"
+		}
+
+		writer.Write([]string{
+			period.GetPeriodStart(),
+			roomTypeId,
+			strconv.Itoa(int(period.GetRoomNightsAvailable())),
+			strconv.Itoa(int(period.GetRoomNightsSold())),
+			strconv.FormatInt(period.GetRoomRevenue(), 10),
+			strconv.FormatFloat(period.GetOccupancyRate(), 'f', 2, 64),
+			strconv.FormatFloat(period.GetAdr(), 'f', 2, 64),
+			strconv.FormatFloat(period.GetRevPar(), 'f', 2, 64),
+			strconv.Itoa(int(period.GetBookingsCreated())),
+			strconv.Itoa(int(period.GetRoomNightsBooked())),
+			strconv.FormatInt(period.GetBookedRevenue(), 10),
+		})
+	}
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		zap.S().Errorln("Failed to write analytics CSV: ", err)
+	}
+}
+
+func (hh *HotelHandler) getHotelAnalytics(ctx *gin.Context) ([]*booking_pb.AnalyticsPeriod, bool) {
+	return fetchHotelAnalytics(ctx, hh.roomClient, hh.bookingClient, ctx.Param("id"))
+}
+
+// Get room counts of the hotel then its analytics for the dates of the query, write the error response when failed
+func fetchHotelAnalytics(ctx *gin.Context, roomClient room_pb.RoomServiceClient, bookingClient booking_pb.BookingServiceClient, hotelId string) ([]*booking_pb.AnalyticsPeriod, bool) {
 
-	zap.S().Infoln(response)
+	roomCounts, err := roomClient.GetNumberOfRoomsPerRoomTypeByHotelIds(ctx, &room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsRequest{
+		HotelIds: []string{hotelId},
+	})
+	if err != nil {
+		zap.S().Infoln("Failed to get number of rooms per room type: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc so luong phong"))
+		return nil, false
+	}
+
+	req := &booking_pb.GetHotelAnalyticsRequest{
+		HotelId:     hotelId,
+		StartDate:   ctx.Query("start_date"),
+		EndDate:     ctx.Query("end_date"),
+		Granularity: ctx.DefaultQuery("granularity", "day"),
+	}
+	for _, roomCount := range roomCounts.GetResults() {
+		req.RoomCounts = append(req.RoomCounts, &booking_pb.RoomTypeRoomCount{
+			RoomTypeId:    roomCount.GetRoomTypeId(),
+			NumberOfRooms: roomCount.GetNumberOfRooms(),
+		})
+	}
+
+	result, err := bookingClient.GetHotelAnalytics(ctx, req)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+				return nil, false
+			}
+		}
+
+		zap.S().Infoln("Failed to get hotel analytics: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc thong ke"))
+		return nil, false
+	}
+
+	return result.GetPeriods(), true
+}
+
+func (hh *HotelHandler) GetChannelAllotments(ctx *gin.Context) {
+	result, err := hh.bookingClient.GetChannelAllotmentsByHotelId(ctx, &booking_pb.GetChannelAllotmentsByHotelIdRequest{
+		HotelId: ctx.Param("id"),
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+			return
+		}
+
+		zap.S().Infoln("Failed to get channel allotments: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc allotment"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetAllotments(), "Thanh cong"))
+}
+
+type SetChannelAllotmentBody struct {
+	Channel    string `json:"channel" binding:"required"`
+	RoomTypeId string `json:"room_type_id" binding:"required"`
+	Allotment  *int   `json:"allotment" binding:"required"` // Rooms the channel may sell per night
+}
+
+// Create or update the allotment of a room type on a channel
+func (hh *HotelHandler) SetChannelAllotment(ctx *gin.Context) {
+	hotelId := ctx.Param("id")
+
+	var reqBody SetChannelAllotmentBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	roomTypeResult, err := hh.roomTypeClient.GetRoomTypeById(ctx, &room_type_pb.GetRoomTypeByIdRequest{
+		Id: reqBody.RoomTypeId,
+	})
+	if err != nil || roomTypeResult.GetRoomType().GetHotelId() != hotelId {
+		zap.S().Infoln("Room Type is not found in Hotel: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loai phong khong ton tai"))
+		return
+	}
+
+	result, err := hh.bookingClient.SetChannelAllotment(ctx, &booking_pb.SetChannelAllotmentRequest{
+		Channel:    reqBody.Channel,
+		HotelId:    hotelId,
+		RoomTypeId: reqBody.RoomTypeId,
+		Allotment:  int32(*reqBody.Allotment),
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Allotment khong hop le"))
+			return
+		}
+
+		zap.S().Infoln("Failed to set channel allotment: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong cap nhat duoc allotment"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result, "Cap nhat thanh cong"))
+}
+
+func (hh *HotelHandler) DeleteChannelAllotment(ctx *gin.Context) {
+	_, err := hh.bookingClient.DeleteChannelAllotmentById(ctx, &booking_pb.DeleteChannelAllotmentByIdRequest{
+		Id:      ctx.Param("allotmentId"),
+		HotelId: ctx.Param("id"),
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+				return
+			case codes.NotFound:
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay allotment"))
+				return
+			}
+		}
+
+		zap.S().Infoln("Failed to delete channel allotment: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong xoa duoc allotment"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Xoa allotment thanh cong"))
+}
+
+// Special requests of bookings checking in within [start_date, end_date), ?unacknowledged=true for the ones not seen yet
+func (hh *HotelHandler) GetSpecialRequests(ctx *gin.Context) {
+	result, err := hh.bookingClient.GetSpecialRequestsByHotelId(ctx, &booking_pb.GetSpecialRequestsByHotelIdRequest{
+		HotelId:            ctx.Param("id"),
+		StartDate:          ctx.Query("start_date"),
+		EndDate:            ctx.Query("end_date"),
+		OnlyUnacknowledged: ctx.Query("unacknowledged") == "true",
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+			return
+		}
+
+		zap.S().Infoln("Failed to get special requests: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc yeu cau dac biet"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetSpecialRequests(), "Thanh cong"))
+}
+
+func (hh *HotelHandler) AcknowledgeSpecialRequest(ctx *gin.Context) {
+	_, err := hh.bookingClient.AcknowledgeSpecialRequest(actorContext(ctx), &booking_pb.AcknowledgeSpecialRequestRequest{
+		Id:      ctx.Param("requestId"),
+		HotelId: ctx.Param("id"),
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+				return
+			case codes.NotFound:
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay yeu cau dac biet"))
+				return
+			}
+		}
 
-	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(response, "Thanh cong"))
+		zap.S().Infoln("Failed to acknowledge special request: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong xac nhan duoc yeu cau dac biet"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Xac nhan thanh cong"))
+}
+
+// Comment of a review step, the body can be left out when there is none
+type HotelReviewBody struct {
+	Comment string `json:"comment" binding:"max=2000"`
+}
+
+// False when the response was already sent
+func bindHotelReviewBody(ctx *gin.Context) (HotelReviewBody, bool) {
+	var reqBody HotelReviewBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil && !errors.Is(err, io.EOF) {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return reqBody, false
+	}
+
+	return reqBody, true
+}
+
+// Creator of a draft or rejected hotel, or an admin, sends it to review
+func (hh *HotelHandler) SubmitHotel(ctx *gin.Context) {
+	reqBody, ok := bindHotelReviewBody(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := hh.hotelClient.SubmitHotel(userActorContext(ctx), &hotel_pb.SubmitHotelRequest{
+		Id:      ctx.Param("id"),
+		Comment: reqBody.Comment,
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong gui duyet duoc khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetHotel(), "Gui duyet thanh cong"))
+}
+
+func (hh *HotelHandler) ApproveHotel(ctx *gin.Context) {
+	reqBody, ok := bindHotelReviewBody(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := hh.hotelClient.ApproveHotel(userActorContext(ctx), &hotel_pb.ApproveHotelRequest{
+		Id:      ctx.Param("id"),
+		Comment: reqBody.Comment,
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong duyet duoc khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetHotel(), "Duyet khach san thanh cong"))
+}
+
+// The comment is required, it tells the manager what to fix before submitting again
+func (hh *HotelHandler) RejectHotel(ctx *gin.Context) {
+	reqBody, ok := bindHotelReviewBody(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := hh.hotelClient.RejectHotel(userActorContext(ctx), &hotel_pb.RejectHotelRequest{
+		Id:      ctx.Param("id"),
+		Comment: reqBody.Comment,
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong tu choi duoc khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetHotel(), "Tu choi khach san thanh cong"))
+}
+
+// Creator of an approved hotel, or an admin, shows it in public listing and search
+func (hh *HotelHandler) PublishHotel(ctx *gin.Context) {
+	result, err := hh.hotelClient.PublishHotel(userActorContext(ctx), &hotel_pb.PublishHotelRequest{
+		Id: ctx.Param("id"),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong cong khai duoc khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetHotel(), "Cong khai khach san thanh cong"))
+}
+
+// Transitions of the publication workflow, oldest first
+func (hh *HotelHandler) GetHotelStatusHistory(ctx *gin.Context) {
+	result, err := hh.hotelClient.GetHotelStatusHistory(userContext(ctx), &hotel_pb.GetHotelStatusHistoryRequest{
+		HotelId: ctx.Param("id"),
+	})
+	if err != nil {
+		respondHotelError(ctx, err, "Loi khong lay duoc lich su duyet khach san")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetEvents(), "Thanh cong"))
+}
+
+// Map error of hotel service to http response, message is used for unexpected errors
+func respondHotelError(ctx *gin.Context, err error, message string) {
+	st, ok := status.FromError(err)
+	if ok {
+		switch st.Code() {
+		case codes.InvalidArgument, codes.FailedPrecondition:
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.NotFound:
+			ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.PermissionDenied:
+			ctx.JSON(http.StatusForbidden, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.AlreadyExists:
+			ctx.JSON(http.StatusConflict, utils.ErrorApiResponse(st.Message()))
+			return
+		}
+	}
+
+	zap.S().Infoln(message, ": ", err)
+	ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse(message))
+}
+
+// Room type attributes from min_area, guests and smoking=true|false, missing ones do not filter
+func parseRoomTypeFilterQuery(ctx *gin.Context) (int32, int32, *bool, error) {
+	var minAreaSqm, guests int
+	var err error
+	if value := ctx.Query("min_area"); value != "" {
+		if minAreaSqm, err = strconv.Atoi(value); err != nil || minAreaSqm < 0 {
+			return 0, 0, nil, fmt.Errorf("invalid min_area: %s", value)
+		}
+	}
+
+	if value := ctx.Query("guests"); value != "" {
+		if guests, err = strconv.Atoi(value); err != nil || guests < 0 {
+			return 0, 0, nil, fmt.Errorf("invalid guests: %s", value)
+		}
+	}
+
+	var smokingAllowed *bool
+	if value := ctx.Query("smoking"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return 0, 0, nil, fmt.Errorf("invalid smoking: %s", value)
+		}
+		smokingAllowed = &parsed
+	}
+
+	return int32(minAreaSqm), int32(guests), smokingAllowed, nil
+}
+
+// Amenity codes from amenities=wifi,pool or repeated amenities=wifi&amenities=pool
+func parseAmenitiesQuery(ctx *gin.Context) []string {
+	amenities := make([]string, 0)
+	for _, value := range ctx.QueryArray("amenities") {
+		for _, code := range strings.Split(value, ",") {
+			if code = strings.TrimSpace(code); code != "" {
+				amenities = append(amenities, code)
+			}
+		}
+	}
+
+	return amenities
+}
+
+// Point and radius from lat, lng, radius_km and a bounding box from min_lat, max_lat, min_lng, max_lng.
+// Each part is nil when its query params are missing, a part given only half is an error
+func parseGeoSearchQuery(ctx *gin.Context) (*hotel_pb.GeoPoint, float64, *hotel_pb.GeoBoundingBox, error) {
+	values, err := parseFloatQueries(ctx, "lat", "lng", "radius_km", "min_lat", "max_lat", "min_lng", "max_lng")
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	var center *hotel_pb.GeoPoint
+	if values["lat"] != nil || values["lng"] != nil {
+		if values["lat"] == nil || values["lng"] == nil {
+			return nil, 0, nil, fmt.Errorf("lat and lng must be given together")
+		}
+		center = &hotel_pb.GeoPoint{
+			Latitude:  *values["lat"],
+			Longitude: *values["lng"],
+		}
+	}
+
+	var radiusKm float64
+	if values["radius_km"] != nil {
+		radiusKm = *values["radius_km"]
+	}
+
+	var boundingBox *hotel_pb.GeoBoundingBox
+	boxKeys := []string{"min_lat", "max_lat", "min_lng", "max_lng"}
+	givenBoxKeys := 0
+	for _, key := range boxKeys {
+		if values[key] != nil {
+			givenBoxKeys++
+		}
+	}
+	if givenBoxKeys > 0 {
+		if givenBoxKeys != len(boxKeys) {
+			return nil, 0, nil, fmt.Errorf("bounding box needs min_lat, max_lat, min_lng and max_lng")
+		}
+		boundingBox = &hotel_pb.GeoBoundingBox{
+			MinLatitude:  *values["min_lat"],
+			MaxLatitude:  *values["max_lat"],
+			MinLongitude: *values["min_lng"],
+			MaxLongitude: *values["max_lng"],
+		}
+	}
+
+	return center, radiusKm, boundingBox, nil
+}
+
+// Query params parsed as float, nil when missing
+func parseFloatQueries(ctx *gin.Context, keys ...string) (map[string]*float64, error) {
+	values := make(map[string]*float64, len(keys))
+	for _, key := range keys {
+		raw := ctx.Query(key)
+		if raw == "" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		values[key] = &value
+	}
+
+	return values, nil
+}
+
+func toHotelLocation(location *hotel_pb.GeoPoint) *api_dto.HotelLocation {
+	if location == nil {
+		return nil
+	}
+
+	return &api_dto.HotelLocation{
+		Latitude:  location.GetLatitude(),
+		Longitude: location.GetLongitude(),
+	}
 }
@@ -0,0 +1,365 @@
+package api_handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	api_dto "github.com/098765432m/grpc-kafka/api-gateway/internal/dto"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/image_pb"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Hotel client answering only the calls of the tested handlers, any other call panics
+type fakeHotelClient struct {
+	hotel_pb.HotelServiceClient
+	hotel        *hotel_pb.Hotel
+	hotelErr     error
+	amenities    []*hotel_pb.Amenity
+	amenitiesErr error
+	chainErr     error
+	chainHotels  []*hotel_pb.Hotel
+	updateReq    *hotel_pb.UpdateHotelRequest
+	writeErr     error // answer of update and delete
+}
+
+func (fc *fakeHotelClient) GetHotelById(ctx context.Context, in *hotel_pb.GetHotelByIdRequest, opts ...grpc.CallOption) (*hotel_pb.GetHotelByIdResponse, error) {
+	if fc.hotelErr != nil {
+		return nil, fc.hotelErr
+	}
+	return &hotel_pb.GetHotelByIdResponse{Hotel: fc.hotel}, nil
+}
+
+func (fc *fakeHotelClient) GetHotelAmenities(ctx context.Context, in *hotel_pb.GetHotelAmenitiesRequest, opts ...grpc.CallOption) (*hotel_pb.GetHotelAmenitiesResponse, error) {
+	if fc.amenitiesErr != nil {
+		return nil, fc.amenitiesErr
+	}
+	return &hotel_pb.GetHotelAmenitiesResponse{Amenities: fc.amenities}, nil
+}
+
+func (fc *fakeHotelClient) GetHotelChainById(ctx context.Context, in *hotel_pb.GetHotelChainByIdRequest, opts ...grpc.CallOption) (*hotel_pb.GetHotelChainByIdResponse, error) {
+	if fc.chainErr != nil {
+		return nil, fc.chainErr
+	}
+	return &hotel_pb.GetHotelChainByIdResponse{Chain: &hotel_pb.HotelChain{Id: in.GetId()}}, nil
+}
+
+func (fc *fakeHotelClient) GetHotelsByChainId(ctx context.Context, in *hotel_pb.GetHotelsByChainIdRequest, opts ...grpc.CallOption) (*hotel_pb.GetHotelsByChainIdResponse, error) {
+	if fc.chainErr != nil {
+		return nil, fc.chainErr
+	}
+	return &hotel_pb.GetHotelsByChainIdResponse{Hotels: fc.chainHotels}, nil
+}
+
+func (fc *fakeHotelClient) UpdateHotel(ctx context.Context, in *hotel_pb.UpdateHotelRequest, opts ...grpc.CallOption) (*hotel_pb.UpdateHotelResponse, error) {
+	fc.updateReq = in
+	if fc.writeErr != nil {
+		return nil, fc.writeErr
+	}
+	return &hotel_pb.UpdateHotelResponse{Hotel: &hotel_pb.Hotel{Id: in.GetId(), Name: in.GetName()}}, nil
+}
+
+func (fc *fakeHotelClient) DeleteHotel(ctx context.Context, in *hotel_pb.DeleteHotelRequest, opts ...grpc.CallOption) (*hotel_pb.DeleteHotelResponse, error) {
+	if fc.writeErr != nil {
+		return nil, fc.writeErr
+	}
+	return &hotel_pb.DeleteHotelResponse{}, nil
+}
+
+type fakeImageClient struct {
+	image_pb.ImageServiceClient
+}
+
+func (fc *fakeImageClient) GetImagesByHotelId(ctx context.Context, in *image_pb.GetImagesByHotelIdRequest, opts ...grpc.CallOption) (*image_pb.GetImagesByHotelIdResponse, error) {
+	return &image_pb.GetImagesByHotelIdResponse{}, nil
+}
+
+func TestGetHotelByIdAmenities(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hotel := &hotel_pb.Hotel{Id: "hotel-1", Name: "Hotel"}
+	wifi := &hotel_pb.Amenity{Id: "amenity-1", Code: "wifi", Category: "INTERNET"}
+
+	tests := []struct {
+		name          string
+		client        *fakeHotelClient
+		wantStatus    int
+		wantAmenities []string
+	}{
+		{
+			name:          "amenities are merged",
+			client:        &fakeHotelClient{hotel: hotel, amenities: []*hotel_pb.Amenity{wifi}},
+			wantStatus:    http.StatusOK,
+			wantAmenities: []string{"wifi"},
+		},
+		{
+			name:          "amenity failure leaves them empty",
+			client:        &fakeHotelClient{hotel: hotel, amenitiesErr: status.Error(codes.Unavailable, "down")},
+			wantStatus:    http.StatusOK,
+			wantAmenities: []string{},
+		},
+		{
+			name:       "missing or unpublished hotel",
+			client:     &fakeHotelClient{hotelErr: status.Error(codes.NotFound, "not found")},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hh := &HotelHandler{hotelClient: tt.client, imageClient: &fakeImageClient{}}
+
+			router := gin.New()
+			router.GET("/hotels/:id", hh.GetHotelById)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/hotels/hotel-1", nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("GetHotelById() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				Result api_dto.HotelResponse `json:"result"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+
+			if body.Result.Amenities == nil {
+				t.Fatalf("GetHotelById() amenities = null, want a list")
+			}
+			amenityCodes := make([]string, 0, len(body.Result.Amenities))
+			for _, amenity := range body.Result.Amenities {
+				amenityCodes = append(amenityCodes, amenity.Code)
+			}
+			if !slices.Equal(amenityCodes, tt.wantAmenities) {
+				t.Errorf("GetHotelById() amenities = %v, want %v", amenityCodes, tt.wantAmenities)
+			}
+		})
+	}
+}
+
+func TestParseAmenitiesQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{}},
+		{"amenities=wifi", []string{"wifi"}},
+		{"amenities=wifi,pool", []string{"wifi", "pool"}},
+		{"amenities=wifi&amenities=pool", []string{"wifi", "pool"}},
+		{"amenities=%20wifi%20,,pool,", []string{"wifi", "pool"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/hotels/filter?"+tt.query, nil)
+
+			if got := parseAmenitiesQuery(ctx); !slices.Equal(got, tt.want) {
+				t.Errorf("parseAmenitiesQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchBookings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		query     string
+		namePages [][]string
+		searchErr error
+		wantCode  int
+		// Nil when the booking service must not be called
+		wantReq *booking_pb.SearchBookingsRequest
+	}{
+		{
+			name:     "filters are forwarded for the hotel of the path",
+			query:    "?check_in_from=2026-03-01&check_in_to=2026-03-31&check_out_from=2026-03-02&check_out_to=2026-04-01&status=BOOKED&confirmation_code=ab12cd&room_id=room-1",
+			wantCode: http.StatusOK,
+			wantReq: &booking_pb.SearchBookingsRequest{
+				HotelId:          "hotel-1",
+				CheckInFrom:      "2026-03-01",
+				CheckInTo:        "2026-03-31",
+				CheckOutFrom:     "2026-03-02",
+				CheckOutTo:       "2026-04-01",
+				Status:           "BOOKED",
+				ConfirmationCode: "ab12cd",
+				RoomId:           "room-1",
+			},
+		},
+		{
+			name:      "guest name matches users of every page",
+			query:     "?guest_name=nguyen",
+			namePages: [][]string{{"user-1", "user-2"}, {"user-3"}},
+			wantCode:  http.StatusOK,
+			wantReq: &booking_pb.SearchBookingsRequest{
+				HotelId:         "hotel-1",
+				UserIds:         []string{"user-1", "user-2", "user-3"},
+				FilterByUserIds: true,
+			},
+		},
+		{
+			name:     "guest name matching nobody finds no booking",
+			query:    "?guest_name=nobody",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "page size is not a number",
+			query:    "?page_size=ten",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:      "invalid filter",
+			query:     "?status=UNKNOWN",
+			searchErr: status.Error(codes.InvalidArgument, "Trang thai dat phong khong hop le"),
+			wantCode:  http.StatusBadRequest,
+			wantReq:   &booking_pb.SearchBookingsRequest{HotelId: "hotel-1", Status: "UNKNOWN"},
+		},
+		{
+			name:      "booking service down",
+			searchErr: status.Error(codes.Unavailable, "down"),
+			wantCode:  http.StatusInternalServerError,
+			wantReq:   &booking_pb.SearchBookingsRequest{HotelId: "hotel-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookingClient := &fakeBookingClient{searchErr: tt.searchErr}
+			hh := &HotelHandler{userClient: &fakeUserClient{namePages: tt.namePages}, bookingClient: bookingClient}
+
+			router := gin.New()
+			router.GET("/hotels/:id/bookings", hh.SearchBookings)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/hotels/hotel-1/bookings"+tt.query, nil))
+
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("SearchBookings() status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+
+			got := bookingClient.searchReq
+			if (got == nil) != (tt.wantReq == nil) {
+				t.Fatalf("SearchBookings() sent %v, want %v", got, tt.wantReq)
+			}
+			if got == nil {
+				return
+			}
+
+			if got.GetHotelId() != tt.wantReq.GetHotelId() ||
+				got.GetCheckInFrom() != tt.wantReq.GetCheckInFrom() ||
+				got.GetCheckInTo() != tt.wantReq.GetCheckInTo() ||
+				got.GetCheckOutFrom() != tt.wantReq.GetCheckOutFrom() ||
+				got.GetCheckOutTo() != tt.wantReq.GetCheckOutTo() ||
+				got.GetStatus() != tt.wantReq.GetStatus() ||
+				got.GetConfirmationCode() != tt.wantReq.GetConfirmationCode() ||
+				got.GetRoomId() != tt.wantReq.GetRoomId() ||
+				got.GetFilterByUserIds() != tt.wantReq.GetFilterByUserIds() ||
+				!slices.Equal(got.GetUserIds(), tt.wantReq.GetUserIds()) {
+				t.Errorf("SearchBookings() sent %v, want %v", got, tt.wantReq)
+			}
+			if got.GetPage() == nil {
+				t.Errorf("SearchBookings() sent no page")
+			}
+		})
+	}
+}
+
+func TestUpdateHotel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		body         string
+		writeErr     error
+		wantStatus   int
+		wantLocation bool
+	}{
+		{"address geocoded", `{"name": "Hotel", "address": "1 Le Loi", "city": "Hue"}`, nil, http.StatusOK, false},
+		{"coordinates given", `{"name": "Hotel", "address": "1 Le Loi", "latitude": 16.46, "longitude": 107.59}`, nil, http.StatusOK, true},
+		{"missing name", `{"address": "1 Le Loi"}`, nil, http.StatusBadRequest, false},
+		{"latitude without longitude", `{"name": "Hotel", "address": "1 Le Loi", "latitude": 16.46}`, nil, http.StatusBadRequest, false},
+		{"latitude out of range", `{"name": "Hotel", "address": "1 Le Loi", "latitude": 91, "longitude": 107.59}`, nil, http.StatusBadRequest, false},
+		{"missing hotel", `{"name": "Hotel", "address": "1 Le Loi"}`, status.Error(codes.NotFound, "Khong tim thay khach san"), http.StatusNotFound, false},
+		{"invalid hotel id", `{"name": "Hotel", "address": "1 Le Loi"}`, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le"), http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeHotelClient{writeErr: tt.writeErr}
+			hh := &HotelHandler{hotelClient: client}
+
+			router := gin.New()
+			router.PUT("/hotels/:id", hh.UpdateHotel)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/hotels/hotel-1", strings.NewReader(tt.body)))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("UpdateHotel() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusBadRequest && tt.writeErr == nil {
+				if client.updateReq != nil {
+					t.Errorf("UpdateHotel() called the hotel service with an invalid body")
+				}
+				return
+			}
+
+			if client.updateReq.GetId() != "hotel-1" {
+				t.Errorf("UpdateHotel() hotel id = %q, want %q", client.updateReq.GetId(), "hotel-1")
+			}
+			if (client.updateReq.GetLocation() != nil) != tt.wantLocation {
+				t.Errorf("UpdateHotel() location = %v, want location %v", client.updateReq.GetLocation(), tt.wantLocation)
+			}
+		})
+	}
+}
+
+func TestDeleteHotel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		writeErr   error
+		wantStatus int
+	}{
+		{"deleted", nil, http.StatusOK},
+		{"missing hotel", status.Error(codes.NotFound, "Khong tim thay khach san"), http.StatusNotFound},
+		// Bookings or images could not be deleted, the hotel is kept
+		{"cascade failed", status.Error(codes.Internal, "Loi khong xoa duoc khach san"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hh := &HotelHandler{hotelClient: &fakeHotelClient{writeErr: tt.writeErr}}
+
+			router := gin.New()
+			router.DELETE("/hotels/:id", hh.DeleteHotel)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/hotels/hotel-1", nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("DeleteHotel() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package api_handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Availability sync with channels (Airbnb, Booking.com, ...) through iCalendar feeds
+type IcalHandler struct {
+	bookingClient booking_pb.BookingServiceClient
+	roomClient    room_pb.RoomServiceClient
+	hotelClient   hotel_pb.HotelServiceClient
+}
+
+func NewIcalHandler(bookingClient booking_pb.BookingServiceClient, roomClient room_pb.RoomServiceClient, hotelClient hotel_pb.HotelServiceClient) *IcalHandler {
+	return &IcalHandler{
+		bookingClient: bookingClient,
+		roomClient:    roomClient,
+		hotelClient:   hotelClient,
+	}
+}
+
+func (ih *IcalHandler) RegisterRoutes(router *gin.RouterGroup) {
+	// Public, protected by the signed token in the url
+	router.GET("/ical/rooms/:file", ih.ExportRoomIcal)
+
+	hotelHandler := router.Group("/hotels/:id/rooms/:roomId", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(ih.hotelClient))
+
+	hotelHandler.GET("/ical-url", ih.GetRoomIcalUrl)
+	hotelHandler.GET("/ical-feeds", ih.GetIcalFeedsByRoomId)
+	hotelHandler.POST("/ical-feeds", ih.CreateIcalFeed)
+	hotelHandler.DELETE("/ical-feeds/:feedId", ih.DeleteIcalFeedById)
+}
+
+// GET /ical/rooms/<room id>.ics?token=...
+func (ih *IcalHandler) ExportRoomIcal(ctx *gin.Context) {
+	roomId := strings.TrimSuffix(ctx.Param("file"), ".ics")
+
+	result, err := ih.bookingClient.ExportRoomIcal(ctx, &booking_pb.ExportRoomIcalRequest{
+		RoomId: roomId,
+		Token:  ctx.Query("token"),
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument, codes.PermissionDenied:
+				// Same response for unknown rooms and wrong tokens
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay lich"))
+				return
+			}
+		}
+
+		zap.S().Infoln("Failed to export room iCal: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong xuat duoc lich iCal"))
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s.ics", roomId))
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", result.GetCalendar())
+}
+
+// Url of the room calendar to paste in the channel extranet
+func (ih *IcalHandler) GetRoomIcalUrl(ctx *gin.Context) {
+	room, ok := ih.getHotelRoom(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := ih.bookingClient.GetRoomIcalToken(ctx, &booking_pb.GetRoomIcalTokenRequest{
+		RoomId: room.GetId(),
+	})
+	if err != nil {
+		zap.S().Infoln("Failed to get room iCal token: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong tao duoc iCal url"))
+		return
+	}
+
+	scheme := "http"
+	if ctx.Request.TLS != nil {
+		scheme = "https"
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(gin.H{
+		"url": fmt.Sprintf("%s://%s/api/ical/rooms/%s.ics?token=%s", scheme, ctx.Request.Host, room.GetId(), result.GetToken()),
+	}, "Thanh cong"))
+}
+
+func (ih *IcalHandler) GetIcalFeedsByRoomId(ctx *gin.Context) {
+	room, ok := ih.getHotelRoom(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := ih.bookingClient.GetIcalFeedsByRoomId(ctx, &booking_pb.GetIcalFeedsByRoomIdRequest{
+		RoomId: room.GetId(),
+	})
+	if err != nil {
+		zap.S().Infoln("Failed to get iCal feeds: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc iCal feed"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetFeeds(), "Thanh cong"))
+}
+
+type CreateIcalFeedBody struct {
+	Url string `json:"url" binding:"required"`
+}
+
+// Import an external .ics feed, its events block the room
+func (ih *IcalHandler) CreateIcalFeed(ctx *gin.Context) {
+	var reqBody CreateIcalFeedBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	room, ok := ih.getHotelRoom(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := ih.bookingClient.CreateIcalFeed(ctx, &booking_pb.CreateIcalFeedRequest{
+		RoomId:     room.GetId(),
+		RoomTypeId: room.GetRoomTypeId(),
+		HotelId:    room.GetHotelId(),
+		Url:        reqBody.Url,
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("iCal url khong hop le"))
+				return
+			}
+		}
+
+		zap.S().Infoln("Failed to create iCal feed: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong tao duoc iCal feed"))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(result, "Tao iCal feed thanh cong"))
+}
+
+func (ih *IcalHandler) DeleteIcalFeedById(ctx *gin.Context) {
+	room, ok := ih.getHotelRoom(ctx)
+	if !ok {
+		return
+	}
+
+	// Only feeds of the room can be deleted
+	feeds, err := ih.bookingClient.GetIcalFeedsByRoomId(ctx, &booking_pb.GetIcalFeedsByRoomIdRequest{
+		RoomId: room.GetId(),
+	})
+	if err != nil {
+		zap.S().Infoln("Failed to get iCal feeds: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong xoa duoc iCal feed"))
+		return
+	}
+
+	feedId := ctx.Param("feedId")
+	found := false
+	for _, feed := range feeds.GetFeeds() {
+		if feed.GetId() == feedId {
+			found = true
+			break
+		}
+	}
+	if !found {
+		ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay iCal feed"))
+		return
+	}
+
+	_, err = ih.bookingClient.DeleteIcalFeedById(ctx, &booking_pb.DeleteIcalFeedByIdRequest{
+		Id: feedId,
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound:
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Khong tim thay iCal feed"))
+				return
+			}
+		}
+
+		zap.S().Infoln("Failed to delete iCal feed: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong xoa duoc iCal feed"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Xoa iCal feed thanh cong"))
+}
+
+// Room of the path, write the error response when it is not a room of the hotel
+func (ih *IcalHandler) getHotelRoom(ctx *gin.Context) (*room_pb.Room, bool) {
+
+	result, err := ih.roomClient.GetRoomById(ctx, &room_pb.GetRoomByIdRequest{
+		Id: ctx.Param("roomId"),
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.NotFound, codes.InvalidArgument:
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Phong khong ton tai"))
+				return nil, false
+			}
+		}
+
+		zap.S().Infoln("Failed to get room: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc phong"))
+		return nil, false
+	}
+
+	if result.GetRoom().GetHotelId() != ctx.Param("id") {
+		ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Phong khong ton tai"))
+		return nil, false
+	}
+
+	return result.GetRoom(), true
+}
@@ -0,0 +1,173 @@
+package api_handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/loyalty_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type LoyaltyHandler struct {
+	loyaltyClient loyalty_pb.LoyaltyServiceClient
+	hotelClient   hotel_pb.HotelServiceClient
+}
+
+func NewLoyaltyHandler(loyaltyClient loyalty_pb.LoyaltyServiceClient, hotelClient hotel_pb.HotelServiceClient) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyClient: loyaltyClient,
+		hotelClient:   hotelClient,
+	}
+}
+
+func (lh *LoyaltyHandler) RegisterRoutes(router *gin.RouterGroup) {
+	memberHandler := router.Group("/loyalty/me", common_middleware.AuthMiddleware())
+
+	memberHandler.GET("", lh.GetMyLoyaltyAccount)
+	memberHandler.GET("/transactions", lh.GetMyLoyaltyTransactions)
+
+	adminHandler := router.Group("/loyalty/users/:userId", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin())
+
+	adminHandler.GET("", lh.GetLoyaltyAccount)
+	adminHandler.POST("/adjustments", lh.AdjustPoints)
+
+	hotelHandler := router.Group("/hotels/:id/loyalty-setting", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(lh.hotelClient))
+
+	hotelHandler.GET("", lh.GetHotelLoyaltySetting)
+	hotelHandler.PUT("", lh.SetHotelLoyaltySetting)
+}
+
+// Balance, tier and tier benefits of the signed in user
+func (lh *LoyaltyHandler) GetMyLoyaltyAccount(ctx *gin.Context) {
+	lh.getLoyaltyAccount(ctx, ctx.GetString(common_middleware.AUTH_USER_ID_KEY))
+}
+
+func (lh *LoyaltyHandler) GetLoyaltyAccount(ctx *gin.Context) {
+	lh.getLoyaltyAccount(ctx, ctx.Param("userId"))
+}
+
+func (lh *LoyaltyHandler) getLoyaltyAccount(ctx *gin.Context, userId string) {
+	account, err := lh.loyaltyClient.GetLoyaltyAccount(ctx, &loyalty_pb.GetLoyaltyAccountRequest{
+		UserId: userId,
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+			return
+		}
+
+		zap.S().Infoln("Failed to get loyalty account: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc tai khoan tich diem"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(account, "Thanh cong"))
+}
+
+func (lh *LoyaltyHandler) GetMyLoyaltyTransactions(ctx *gin.Context) {
+	size, _ := strconv.Atoi(ctx.DefaultQuery("size", "20"))
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+
+	result, err := lh.loyaltyClient.GetLoyaltyTransactions(ctx, &loyalty_pb.GetLoyaltyTransactionsRequest{
+		UserId: ctx.GetString(common_middleware.AUTH_USER_ID_KEY),
+		Size:   int32(size),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		zap.S().Infoln("Failed to get loyalty transactions: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc lich su tich diem"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetTransactions(), "Thanh cong"))
+}
+
+type AdjustPointsBody struct {
+	Points int    `json:"points" binding:"required"`
+	Note   string `json:"note" binding:"required"`
+}
+
+// Manual correction of points by an admin, negative points are taken away
+func (lh *LoyaltyHandler) AdjustPoints(ctx *gin.Context) {
+	var reqBody AdjustPointsBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	transaction, err := lh.loyaltyClient.AdjustPoints(actorContext(ctx), &loyalty_pb.AdjustPointsRequest{
+		UserId: ctx.Param("userId"),
+		Points: int32(reqBody.Points),
+		Note:   reqBody.Note,
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+				return
+			case codes.ResourceExhausted:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Khong du diem"))
+				return
+			}
+		}
+
+		zap.S().Infoln("Failed to adjust points: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong dieu chinh duoc diem"))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(transaction, "Dieu chinh diem thanh cong"))
+}
+
+func (lh *LoyaltyHandler) GetHotelLoyaltySetting(ctx *gin.Context) {
+	setting, err := lh.loyaltyClient.GetHotelLoyaltySetting(ctx, &loyalty_pb.GetHotelLoyaltySettingRequest{
+		HotelId: ctx.Param("id"),
+	})
+	if err != nil {
+		zap.S().Infoln("Failed to get hotel loyalty setting: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc cai dat tich diem"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(setting, "Thanh cong"))
+}
+
+type HotelLoyaltySettingBody struct {
+	// Points earned per 100 of the stay total
+	EarnPercent int `json:"earn_percent"`
+}
+
+func (lh *LoyaltyHandler) SetHotelLoyaltySetting(ctx *gin.Context) {
+	var reqBody HotelLoyaltySettingBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	setting, err := lh.loyaltyClient.SetHotelLoyaltySetting(ctx, &loyalty_pb.SetHotelLoyaltySettingRequest{
+		HotelId:     ctx.Param("id"),
+		EarnPercent: int32(reqBody.EarnPercent),
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Ti le tich diem khong hop le"))
+			return
+		}
+
+		zap.S().Infoln("Failed to set hotel loyalty setting: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong cap nhat duoc cai dat tich diem"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(setting, "Cap nhat cai dat tich diem thanh cong"))
+}
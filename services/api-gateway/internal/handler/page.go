@@ -0,0 +1,46 @@
+package api_handler
+
+import (
+	"net/http"
+	"strconv"
+
+	api_dto "github.com/098765432m/grpc-kafka/api-gateway/internal/dto"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/pagination_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Page of a list from the page_size, cursor, sort_by and sort_direction query params.
+// Responds 400 when page_size is not a number, the service validates the rest
+func bindPageRequest(ctx *gin.Context) (*pagination_pb.PageRequest, bool) {
+	page := &pagination_pb.PageRequest{
+		Cursor:        ctx.Query("cursor"),
+		SortBy:        ctx.Query("sort_by"),
+		SortDirection: ctx.Query("sort_direction"),
+	}
+
+	if pageSize := ctx.Query("page_size"); pageSize != "" {
+		size, err := strconv.ParseInt(pageSize, 10, 32)
+		if err != nil {
+			zap.S().Infoln("Invalid page size: ", err)
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Kich thuoc trang khong hop le"))
+			return nil, false
+		}
+		page.PageSize = int32(size)
+	}
+
+	return page, true
+}
+
+func toPageResponse[T any](items []T, page *pagination_pb.PageResponse) api_dto.PageResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	return api_dto.PageResponse[T]{
+		Items:      items,
+		NextCursor: page.GetNextCursor(),
+		TotalCount: page.GetTotalCount(),
+	}
+}
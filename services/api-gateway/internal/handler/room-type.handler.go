@@ -3,8 +3,11 @@ package api_handler
 import (
 	"net/http"
 
+	api_dto "github.com/098765432m/grpc-kafka/api-gateway/internal/dto"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
 	"github.com/098765432m/grpc-kafka/common/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -15,23 +18,35 @@ import (
 type RoomTypeHandler struct {
 	roomTypeClient room_type_pb.RoomTypeServiceClient
 	roomClient     room_pb.RoomServiceClient
+	hotelClient    hotel_pb.HotelServiceClient
 }
 
 func NewRoomTypeHandler(roomTypeClient room_type_pb.RoomTypeServiceClient,
-	roomClient room_pb.RoomServiceClient) *RoomTypeHandler {
+	roomClient room_pb.RoomServiceClient, hotelClient hotel_pb.HotelServiceClient) *RoomTypeHandler {
 	return &RoomTypeHandler{
 		roomTypeClient: roomTypeClient,
 		roomClient:     roomClient,
+		hotelClient:    hotelClient,
 	}
 }
 
 func (rth *RoomTypeHandler) RegisterRoutes(router *gin.RouterGroup) {
 	roomTypeHandler := router.Group("/room-types")
 
-	roomTypeHandler.POST("/", rth.CreateRoomType)
-
 	roomTypeHandler.GET("/:id", rth.GetRoomTypeById)
 	roomTypeHandler.GET("/:id/rooms", rth.GetRoomsByRoomTypeId)
+
+	roomTypeHandler.GET("/:id/overbooking-limits", rth.GetOverbookingLimitsByRoomTypeId)
+
+	roomTypeHandler.GET("/:id/upgrades", rth.GetUpgradeRoomTypesByRoomTypeId)
+
+	hotelHandler := router.Group("/hotels")
+
+	hotelHandler.POST("/:id/room-types", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(rth.hotelClient), rth.CreateRoomType)
+	hotelHandler.PUT("/:id/room-types/:roomTypeId", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(rth.hotelClient), rth.UpdateRoomType)
+	hotelHandler.POST("/:id/room-types/:roomTypeId/overbooking-limits", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(rth.hotelClient), rth.CreateOverbookingLimit)
+	hotelHandler.DELETE("/:id/overbooking-limits/:limitId", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(rth.hotelClient), rth.DeleteOverbookingLimitById)
+	hotelHandler.PUT("/:id/room-types/:roomTypeId/upgrade-rank", common_middleware.AuthMiddleware(), common_middleware.RequireHotelManager(rth.hotelClient), rth.SetRoomTypeUpgradeRank)
 }
 
 func (rth *RoomTypeHandler) GetRoomTypeById(ctx *gin.Context) {
@@ -60,44 +75,113 @@ func (rth *RoomTypeHandler) GetRoomTypeById(ctx *gin.Context) {
 func (rth *RoomTypeHandler) GetRoomsByRoomTypeId(ctx *gin.Context) {
 	roomTypeId := ctx.Param("id")
 
+	page, ok := bindPageRequest(ctx)
+	if !ok {
+		return
+	}
+
 	roomsResult, err := rth.roomClient.GetRoomsByRoomTypeId(ctx, &room_pb.GetRoomsByRoomTypeIdRequest{
 		RoomTypeId: roomTypeId,
+		Page:       page,
 	})
 	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc danh sach phong"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(roomsResult.Rooms, "Lay danh sach phong thanh cong"))
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(roomsResult.GetRooms(), roomsResult.GetPage()), "Lay danh sach phong thanh cong"))
 }
 
 // Create Room Type
 type CreateRoomTypeBody struct {
-	RoomTypeName string `json:"room_type_name"`
-	Price        int    `json:"price"`
-	HotelId      string `json:"hotel_id"`
+	RoomTypeName   string            `json:"room_type_name" binding:"required,max=255"`
+	Price          int               `json:"price" binding:"required,gt=0"`
+	AreaSqm        int               `json:"area_sqm" binding:"min=0"`
+	View           string            `json:"view"` // NONE when empty
+	SmokingAllowed bool              `json:"smoking_allowed"`
+	MaxOccupancy   int               `json:"max_occupancy" binding:"min=0"` // 2 when empty
+	Description    string            `json:"description"`
+	Beds           []RoomTypeBedBody `json:"beds" binding:"dive"`
+}
+
+type RoomTypeBedBody struct {
+	BedType  string `json:"bed_type" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// Replaces all attributes and beds of the room type
+type UpdateRoomTypeBody struct {
+	RoomTypeName   string            `json:"room_type_name" binding:"required,max=255"`
+	Price          int               `json:"price" binding:"required,gt=0"`
+	AreaSqm        int               `json:"area_sqm" binding:"min=0"`
+	View           string            `json:"view"`
+	SmokingAllowed bool              `json:"smoking_allowed"`
+	MaxOccupancy   int               `json:"max_occupancy" binding:"min=0"`
+	Description    string            `json:"description"`
+	Beds           []RoomTypeBedBody `json:"beds" binding:"dive"`
 }
 
+// Room type is created in the hotel of the manager
 func (rth *RoomTypeHandler) CreateRoomType(ctx *gin.Context) {
 
 	var reqBody CreateRoomTypeBody
-	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
 		zap.S().Infoln("Failed to get request body: ", err)
 		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi khong tao duoc loai phong"))
 		return
 	}
 
-	_, err := rth.roomTypeClient.CreateRoomType(ctx, &room_type_pb.CreateRoomTypeRequest{
-		Name:    reqBody.RoomTypeName,
-		Price:   int32(reqBody.Price),
-		HotelId: reqBody.HotelId,
+	result, err := rth.roomTypeClient.CreateRoomType(ctx, &room_type_pb.CreateRoomTypeRequest{
+		Name:           reqBody.RoomTypeName,
+		Price:          int32(reqBody.Price),
+		HotelId:        ctx.Param("id"),
+		AreaSqm:        int32(reqBody.AreaSqm),
+		View:           reqBody.View,
+		SmokingAllowed: reqBody.SmokingAllowed,
+		MaxOccupancy:   int32(reqBody.MaxOccupancy),
+		Description:    reqBody.Description,
+		Beds:           toRoomTypeBedsPb(reqBody.Beds),
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong tao duoc danh sach phong"))
+		respondRoomTypeError(ctx, err, "Loi khong tao duoc loai phong")
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(nil, "Tao thanh cong"))
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(toRoomTypeDto(result.GetRoomType()), "Tao thanh cong"))
+}
+
+// Room type must be one of the hotel of the manager
+func (rth *RoomTypeHandler) UpdateRoomType(ctx *gin.Context) {
+
+	var reqBody UpdateRoomTypeBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	result, err := rth.roomTypeClient.UpdateRoomType(ctx, &room_type_pb.UpdateRoomTypeRequest{
+		Id:             ctx.Param("roomTypeId"),
+		HotelId:        ctx.Param("id"),
+		Name:           reqBody.RoomTypeName,
+		Price:          int32(reqBody.Price),
+		AreaSqm:        int32(reqBody.AreaSqm),
+		View:           reqBody.View,
+		SmokingAllowed: reqBody.SmokingAllowed,
+		MaxOccupancy:   int32(reqBody.MaxOccupancy),
+		Description:    reqBody.Description,
+		Beds:           toRoomTypeBedsPb(reqBody.Beds),
+	})
+	if err != nil {
+		respondRoomTypeError(ctx, err, "Loi khong cap nhat duoc loai phong")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toRoomTypeDto(result.GetRoomType()), "Cap nhat loai phong thanh cong"))
 }
 
 func (rth *RoomTypeHandler) DeleteRoomTypeById(ctx *gin.Context) {
@@ -110,3 +194,167 @@ func (rth *RoomTypeHandler) DeleteRoomTypeById(ctx *gin.Context) {
 		return
 	}
 }
+
+// Create Overbooking Limit
+type CreateOverbookingLimitBody struct {
+	StartDate  string `json:"start_date" binding:"required"`
+	EndDate    string `json:"end_date" binding:"required"`
+	LimitType  string `json:"limit_type" binding:"required"`
+	LimitValue int    `json:"limit_value"`
+}
+
+func (rth *RoomTypeHandler) CreateOverbookingLimit(ctx *gin.Context) {
+	roomTypeId := ctx.Param("roomTypeId")
+
+	var reqBody CreateOverbookingLimitBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	_, err := rth.roomTypeClient.CreateOverbookingLimit(ctx, &room_type_pb.CreateOverbookingLimitRequest{
+		RoomTypeId: roomTypeId,
+		StartDate:  reqBody.StartDate,
+		EndDate:    reqBody.EndDate,
+		LimitType:  reqBody.LimitType,
+		LimitValue: int32(reqBody.LimitValue),
+		HotelId:    ctx.Param("id"),
+	})
+	if err != nil {
+		respondRoomTypeError(ctx, err, "Loi khong tao duoc gioi han overbooking")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.SuccessApiResponse(nil, "Tao thanh cong"))
+}
+
+func (rth *RoomTypeHandler) GetOverbookingLimitsByRoomTypeId(ctx *gin.Context) {
+	roomTypeId := ctx.Param("id")
+
+	result, err := rth.roomTypeClient.GetOverbookingLimitsByRoomTypeId(ctx, &room_type_pb.GetOverbookingLimitsByRoomTypeIdRequest{
+		RoomTypeId: roomTypeId,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc danh sach gioi han overbooking"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetLimits(), "Thanh cong"))
+}
+
+func (rth *RoomTypeHandler) DeleteOverbookingLimitById(ctx *gin.Context) {
+	limitId := ctx.Param("limitId")
+
+	_, err := rth.roomTypeClient.DeleteOverbookingLimitById(ctx, &room_type_pb.DeleteOverbookingLimitByIdRequest{
+		Id:      limitId,
+		HotelId: ctx.Param("id"),
+	})
+	if err != nil {
+		respondRoomTypeError(ctx, err, "Loi khong xoa duoc gioi han overbooking")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Xoa thanh cong"))
+}
+
+// Set Upgrade Rank, 0 removes room type from the upgrade ladder
+type SetRoomTypeUpgradeRankBody struct {
+	UpgradeRank int `json:"upgrade_rank"`
+}
+
+func (rth *RoomTypeHandler) SetRoomTypeUpgradeRank(ctx *gin.Context) {
+	id := ctx.Param("roomTypeId")
+
+	var reqBody SetRoomTypeUpgradeRankBody
+	if err := ctx.ShouldBindJSON(&reqBody); err != nil {
+		zap.S().Infoln("Failed to get request body: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	_, err := rth.roomTypeClient.SetRoomTypeUpgradeRank(ctx, &room_type_pb.SetRoomTypeUpgradeRankRequest{
+		Id:          id,
+		UpgradeRank: int32(reqBody.UpgradeRank),
+		HotelId:     ctx.Param("id"),
+	})
+	if err != nil {
+		respondRoomTypeError(ctx, err, "Loi khong cap nhat duoc thu hang nang cap")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Cap nhat thanh cong"))
+}
+
+func (rth *RoomTypeHandler) GetUpgradeRoomTypesByRoomTypeId(ctx *gin.Context) {
+	roomTypeId := ctx.Param("id")
+
+	result, err := rth.roomTypeClient.GetUpgradeRoomTypesByRoomTypeId(ctx, &room_type_pb.GetUpgradeRoomTypesByRoomTypeIdRequest{
+		RoomTypeId: roomTypeId,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc danh sach loai phong nang cap"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(result.GetRoomTypes(), "Thanh cong"))
+}
+
+func respondRoomTypeError(ctx *gin.Context, err error, message string) {
+	st, ok := status.FromError(err)
+	if ok {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.NotFound:
+			ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse(st.Message()))
+			return
+		case codes.AlreadyExists:
+			ctx.JSON(http.StatusConflict, utils.ErrorApiResponse(st.Message()))
+			return
+		}
+	}
+
+	zap.S().Infoln(message, ": ", err)
+	ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse(message))
+}
+
+func toRoomTypeBedsPb(beds []RoomTypeBedBody) []*room_type_pb.RoomTypeBed {
+	results := make([]*room_type_pb.RoomTypeBed, 0, len(beds))
+	for _, bed := range beds {
+		results = append(results, &room_type_pb.RoomTypeBed{
+			BedType:  bed.BedType,
+			Quantity: int32(bed.Quantity),
+		})
+	}
+
+	return results
+}
+
+func toRoomTypeBedsDto(beds []*room_type_pb.RoomTypeBed) []api_dto.RoomTypeBed {
+	results := make([]api_dto.RoomTypeBed, 0, len(beds))
+	for _, bed := range beds {
+		results = append(results, api_dto.RoomTypeBed{
+			BedType:  bed.GetBedType(),
+			Quantity: int(bed.GetQuantity()),
+		})
+	}
+
+	return results
+}
+
+func toRoomTypeDto(roomType *room_type_pb.RoomType) *api_dto.RoomTypeResponse {
+	return &api_dto.RoomTypeResponse{
+		Id:             roomType.GetId(),
+		Name:           roomType.GetName(),
+		Price:          uint(roomType.GetPrice()),
+		HotelId:        roomType.GetHotelId(),
+		AreaSqm:        int(roomType.GetAreaSqm()),
+		View:           roomType.GetView(),
+		SmokingAllowed: roomType.GetSmokingAllowed(),
+		MaxOccupancy:   int(roomType.GetMaxOccupancy()),
+		Description:    roomType.GetDescription(),
+		Beds:           toRoomTypeBedsDto(roomType.GetBeds()),
+	}
+}
@@ -2,12 +2,13 @@ package api_handler
 
 import (
 	"net/http"
-	"strconv"
 
 	api_dto "github.com/098765432m/grpc-kafka/api-gateway/internal/dto"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/image_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
 	"github.com/098765432m/grpc-kafka/common/model"
 	"github.com/098765432m/grpc-kafka/common/utils"
 	"github.com/gin-gonic/gin"
@@ -20,17 +21,20 @@ type UserHandler struct {
 	userClient    user_pb.UserServiceClient
 	imageClient   image_pb.ImageServiceClient
 	bookingClient booking_pb.BookingServiceClient
+	hotelClient   hotel_pb.HotelServiceClient
 }
 
 func NewUserHandler(
 	userClient user_pb.UserServiceClient,
 	imageClient image_pb.ImageServiceClient,
 	bookingClient booking_pb.BookingServiceClient,
+	hotelClient hotel_pb.HotelServiceClient,
 ) *UserHandler {
 	return &UserHandler{
 		userClient:    userClient,
 		imageClient:   imageClient,
 		bookingClient: bookingClient,
+		hotelClient:   hotelClient,
 	}
 }
 
@@ -40,7 +44,8 @@ func (uh *UserHandler) RegisterRoutes(router *gin.RouterGroup) {
 	userHandler.POST("/")
 
 	userHandler.GET("/:id", uh.GetUserById)
-	userHandler.PUT("/:id", uh.UpdateUserById)
+	userHandler.PUT("/:id", common_middleware.AuthMiddleware(), uh.UpdateUserById)
+	userHandler.PUT("/:id/role", common_middleware.AuthMiddleware(), common_middleware.RequireAdmin(), uh.SetUserRole)
 
 	userHandler.GET("/:id/bookings", uh.GetBookingsByUserId)
 
@@ -124,6 +129,11 @@ func (uh *UserHandler) GetUserById(ctx *gin.Context) {
 		resp.HotelId = user.HotelId
 	}
 
+	// Chain managers also get the chain they manage
+	if user.GetChainId() != "" {
+		resp.ChainId = user.GetChainId()
+	}
+
 	zap.S().Infoln("User: ", resp)
 
 	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(resp, "Thanh cong"))
@@ -136,12 +146,15 @@ type UpdateUserParams struct {
 	Email       string `json:"email"`
 	PhoneNumber string `json:"phone_number"`
 	FullName    string `json:"full_name"`
-	Role        string `json:"role,omitempty"`
-	HotelId     string `json:"hotel_id,omitempty"`
 }
 
+// Profile of the signed in user, only the user or ADMIN can update it
 func (uh *UserHandler) UpdateUserById(ctx *gin.Context) {
 	id := ctx.Param("id")
+	if ctx.GetString(common_middleware.AUTH_ROLE_KEY) != model.ADMIN_ROLE && ctx.GetString(common_middleware.AUTH_USER_ID_KEY) != id {
+		ctx.JSON(http.StatusForbidden, utils.ErrorApiResponse("Khong co quyen cap nhat tai khoan nay"))
+		return
+	}
 
 	updateReq := &UpdateUserParams{}
 	if err := ctx.ShouldBindJSON(updateReq); err != nil {
@@ -159,14 +172,15 @@ func (uh *UserHandler) UpdateUserById(ctx *gin.Context) {
 			Email:       updateReq.Email,
 			PhoneNumber: updateReq.PhoneNumber,
 			FullName:    updateReq.FullName,
-			Role:        updateReq.Role,
-			HotelId:     updateReq.HotelId,
 		},
 	})
 	if err != nil {
 		st, ok := status.FromError(err)
 		if ok {
 			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+				return
 			case codes.NotFound:
 				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Tai khoan khong ton tai de cap nhat"))
 				return
@@ -183,42 +197,121 @@ func (uh *UserHandler) UpdateUserById(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(nil, "Cap nhat tai khoan thanh cong"))
 }
 
+type SetUserRoleParams struct {
+	Role    string `json:"role" binding:"required"`
+	HotelId string `json:"hotel_id,omitempty"`
+	ChainId string `json:"chain_id,omitempty"`
+}
+
+// Role of the user with the hotel of a MANAGER or the chain of a CHAIN_MANAGER, the hotel or chain must exist
+func (uh *UserHandler) SetUserRole(ctx *gin.Context) {
+	req := &SetUserRoleParams{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		zap.S().Infoln("Cannot bind JSON Request: ", err)
+		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Request khong hop le"))
+		return
+	}
+
+	if req.HotelId != "" {
+		if _, err := uh.hotelClient.GetHotelById(userContext(ctx), &hotel_pb.GetHotelByIdRequest{
+			Id: req.HotelId,
+		}); err != nil {
+			switch status.Code(err) {
+			case codes.InvalidArgument, codes.NotFound:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Khach san khong ton tai"))
+				return
+			}
+
+			zap.S().Errorln("Failed to get Hotel of the role: ", err)
+			ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi he thong"))
+			return
+		}
+	}
+
+	if req.ChainId != "" {
+		if _, err := uh.hotelClient.GetHotelChainById(userContext(ctx), &hotel_pb.GetHotelChainByIdRequest{
+			Id: req.ChainId,
+		}); err != nil {
+			switch status.Code(err) {
+			case codes.InvalidArgument, codes.NotFound:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Chuoi khach san khong ton tai"))
+				return
+			}
+
+			zap.S().Errorln("Failed to get Hotel Chain of the role: ", err)
+			ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi he thong"))
+			return
+		}
+	}
+
+	result, err := uh.userClient.SetUserRole(ctx, &user_pb.SetUserRoleRequest{
+		UserId:  ctx.Param("id"),
+		Role:    req.Role,
+		HotelId: req.HotelId,
+		ChainId: req.ChainId,
+	})
+	if err != nil {
+		st, ok := status.FromError(err)
+		if ok {
+			switch st.Code() {
+			case codes.InvalidArgument:
+				ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+				return
+			case codes.NotFound:
+				ctx.JSON(http.StatusNotFound, utils.ErrorApiResponse("Tai khoan khong ton tai"))
+				return
+			}
+		}
+
+		zap.S().Errorln("Failed to set role of User: ", err)
+		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi he thong"))
+		return
+	}
+
+	user := result.GetUser()
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(api_dto.UserResponse{
+		Id:          user.GetId(),
+		Username:    user.GetUsername(),
+		Email:       user.GetEmail(),
+		PhoneNumber: user.GetPhoneNumber(),
+		FullName:    user.GetFullName(),
+		Role:        user.GetRole(),
+		HotelId:     user.GetHotelId(),
+		ChainId:     user.GetChainId(),
+	}, "Cap nhat vai tro thanh cong"))
+}
+
 // Return Bookings By UserId
 func (uh *UserHandler) GetBookingsByUserId(ctx *gin.Context) {
 	// Lay Request Param
 	userId := ctx.Param("id")
 	checkDateStart := ctx.Query("check_date_start")
 	checkDateEnd := ctx.Query("check_date_end")
-	size, err := strconv.Atoi(ctx.Query("size"))
-	if err != nil {
-		zap.S().Infoln("Failed to convert int of Size")
-		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi xay ra"))
-		return
-	}
-	offset, err := strconv.Atoi(ctx.Query("offset"))
-	if err != nil {
-		zap.S().Infoln("Failed to convert int of Offset")
-		ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse("Loi xay ra"))
+	page, ok := bindPageRequest(ctx)
+	if !ok {
 		return
 	}
 
-	zap.L().Info("Check Request of get bookings by userId", zap.Any("userId", userId), zap.Any("check Date Start", checkDateStart), zap.Any("Check Date End", checkDateEnd), zap.Any("Size", size), zap.Any("Offset", offset))
+	zap.L().Info("Check Request of get bookings by userId", zap.Any("userId", userId), zap.Any("check Date Start", checkDateStart), zap.Any("Check Date End", checkDateEnd), zap.Any("Page", page))
 
 	// Lay danh sach Bookings
 	resultBookingsByUserId, err := uh.bookingClient.GetBookingsByUserId(ctx, &booking_pb.GetBookingsByUserIdRequest{
 		UserId:         userId,
 		CheckDateStart: checkDateStart,
 		CheckDateEnd:   checkDateEnd,
-		Size:           int32(size),
-		Offset:         int32(offset),
+		Page:           page,
 	})
 	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			ctx.JSON(http.StatusBadRequest, utils.ErrorApiResponse(st.Message()))
+			return
+		}
 		zap.S().Infoln("Failed to get list of Bookings by User ID: ", err)
 		ctx.JSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi khong lay duoc danh sach dat phong"))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(resultBookingsByUserId.Bookings, "Thanh cong"))
+	ctx.JSON(http.StatusOK, utils.SuccessApiResponse(toPageResponse(resultBookingsByUserId.GetBookings(), resultBookingsByUserId.GetPage()), "Thanh cong"))
 }
 
 func (uh *UserHandler) DeleteUserById(ctx *gin.Context) {
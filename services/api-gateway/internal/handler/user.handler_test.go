@@ -0,0 +1,160 @@
+package api_handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/model"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// User client answering only the account updates, any other call panics
+type fakeUserClient struct {
+	user_pb.UserServiceClient
+	updateCalls int
+	setRoleReq  *user_pb.SetUserRoleRequest
+	// Ids of the users matched by name, one page per call
+	namePages [][]string
+	// User found by GetUserById, NotFound when nil
+	user *user_pb.User
+}
+
+func (fc *fakeUserClient) GetUserById(ctx context.Context, in *user_pb.GetUserByIdRequest, opts ...grpc.CallOption) (*user_pb.GetUserByIdResponse, error) {
+	if fc.user == nil || fc.user.GetId() != in.GetId() {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return &user_pb.GetUserByIdResponse{User: fc.user}, nil
+}
+
+func (fc *fakeUserClient) UpdateUserById(ctx context.Context, in *user_pb.UpdateUserByIdRequest, opts ...grpc.CallOption) (*user_pb.UpdateUserByIdResponse, error) {
+	fc.updateCalls++
+	return &user_pb.UpdateUserByIdResponse{}, nil
+}
+
+func (fc *fakeUserClient) SetUserRole(ctx context.Context, in *user_pb.SetUserRoleRequest, opts ...grpc.CallOption) (*user_pb.SetUserRoleResponse, error) {
+	fc.setRoleReq = in
+	return &user_pb.SetUserRoleResponse{User: &user_pb.User{Id: in.GetUserId(), Role: in.GetRole(), HotelId: in.GetHotelId(), ChainId: in.GetChainId()}}, nil
+}
+
+// Signed in user as set by AuthMiddleware
+// after_id is the index of the next page
+func (fc *fakeUserClient) SearchUserIdsByFullName(ctx context.Context, in *user_pb.SearchUserIdsByFullNameRequest, opts ...grpc.CallOption) (*user_pb.SearchUserIdsByFullNameResponse, error) {
+	if len(fc.namePages) == 0 {
+		return &user_pb.SearchUserIdsByFullNameResponse{}, nil
+	}
+
+	page := 0
+	if in.GetAfterId() != "" {
+		page, _ = strconv.Atoi(in.GetAfterId())
+	}
+
+	resp := &user_pb.SearchUserIdsByFullNameResponse{Ids: fc.namePages[page]}
+	if page+1 < len(fc.namePages) {
+		resp.NextAfterId = strconv.Itoa(page + 1)
+	}
+	return resp, nil
+}
+
+func signedInAs(userId string, role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(common_middleware.AUTH_USER_ID_KEY, userId)
+		ctx.Set(common_middleware.AUTH_ROLE_KEY, role)
+		ctx.Set(common_middleware.AUTH_TOKEN_KEY, "token-1")
+	}
+}
+
+func TestUpdateUserByIdOnlySelfOrAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		userId     string
+		role       string
+		wantStatus int
+		wantCalls  int
+	}{
+		{"the user", "user-1", model.GUEST_ROLE, http.StatusOK, 1},
+		{"admin", "admin-1", model.ADMIN_ROLE, http.StatusOK, 1},
+		{"another user", "user-2", model.GUEST_ROLE, http.StatusForbidden, 0},
+		{"manager of a hotel", "user-2", model.MANAGER_ROLE, http.StatusForbidden, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userClient := &fakeUserClient{}
+			uh := &UserHandler{userClient: userClient}
+
+			router := gin.New()
+			router.PUT("/users/:id", signedInAs(tt.userId, tt.role), uh.UpdateUserById)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/users/user-1", strings.NewReader(`{"full_name":"Nguyen Van A","role":"ADMIN"}`)))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("UpdateUserById() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if userClient.updateCalls != tt.wantCalls {
+				t.Errorf("UpdateUserById() calls = %d, want %d", userClient.updateCalls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSetUserRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	notFound := status.Error(codes.NotFound, "not found")
+
+	tests := []struct {
+		name        string
+		body        string
+		hotelClient *fakeHotelClient
+		wantStatus  int
+		wantRole    string
+	}{
+		{"manager of an existing hotel", `{"role":"MANAGER","hotel_id":"hotel-1"}`, &fakeHotelClient{hotel: &hotel_pb.Hotel{Id: "hotel-1"}}, http.StatusOK, model.MANAGER_ROLE},
+		{"manager of a missing hotel", `{"role":"MANAGER","hotel_id":"hotel-2"}`, &fakeHotelClient{hotelErr: notFound}, http.StatusBadRequest, ""},
+		{"chain manager of an existing chain", `{"role":"CHAIN_MANAGER","chain_id":"chain-1"}`, &fakeHotelClient{}, http.StatusOK, model.CHAIN_MANAGER_ROLE},
+		{"chain manager of a missing chain", `{"role":"CHAIN_MANAGER","chain_id":"chain-2"}`, &fakeHotelClient{chainErr: notFound}, http.StatusBadRequest, ""},
+		{"hotel service down", `{"role":"CHAIN_MANAGER","chain_id":"chain-1"}`, &fakeHotelClient{chainErr: status.Error(codes.Unavailable, "down")}, http.StatusInternalServerError, ""},
+		{"guest", `{"role":"GUEST"}`, &fakeHotelClient{}, http.StatusOK, model.GUEST_ROLE},
+		{"missing role", `{"hotel_id":"hotel-1"}`, &fakeHotelClient{}, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userClient := &fakeUserClient{}
+			uh := &UserHandler{userClient: userClient, hotelClient: tt.hotelClient}
+
+			router := gin.New()
+			router.PUT("/users/:id/role", signedInAs("admin-1", model.ADMIN_ROLE), uh.SetUserRole)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/users/user-1/role", strings.NewReader(tt.body)))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("SetUserRole() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+
+			if tt.wantRole == "" {
+				if userClient.setRoleReq != nil {
+					t.Errorf("SetUserRole() set the role of a rejected request")
+				}
+				return
+			}
+			if userClient.setRoleReq.GetUserId() != "user-1" || userClient.setRoleReq.GetRole() != tt.wantRole {
+				t.Errorf("SetUserRole() request = %v, want role %s of user-1", userClient.setRoleReq, tt.wantRole)
+			}
+		})
+	}
+}
@@ -4,12 +4,22 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
+	"strings"
+	"time"
 
 	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	booking_infrastructure "github.com/098765432m/grpc-kafka/booking/internal/infrastructure"
 	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
 	booking_handler "github.com/098765432m/grpc-kafka/booking/internal/interfaces"
 	"github.com/098765432m/grpc-kafka/common/consts"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/loyalty_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
 	"github.com/098765432m/grpc-kafka/common/utils"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/spf13/viper"
@@ -34,8 +44,49 @@ func main() {
 
 	repo := booking_repo.New(db)
 
+	producer, err := booking_infrastructure.NewKafkaProducer(strings.Split(viper.GetString("KAFKA_BROKERS"), ","))
+	if err != nil {
+		zap.S().Fatalln("Failed to create kafka producer: ", err)
+	}
+	defer producer.Close()
+
+	// dial grpc, rooms and room types are served by hotel service
+	hotelConn := utils.NewGrpcClient(strconv.Itoa(consts.HOTEL_GRPC_PORT))
+	defer hotelConn.Close()
+
+	roomClient := room_pb.NewRoomServiceClient(hotelConn)
+	roomTypeClient := room_type_pb.NewRoomTypeServiceClient(hotelConn)
+	hotelClient := hotel_pb.NewHotelServiceClient(hotelConn)
+
+	// Companies and their negotiated rates are served by user service
+	userConn := utils.NewGrpcClient(strconv.Itoa(consts.USER_GRPC_PORT))
+	defer userConn.Close()
+
+	userClient := user_pb.NewUserServiceClient(userConn)
+
+	// Tier benefits and points of members are served by loyalty service
+	loyaltyConn := utils.NewGrpcClient(strconv.Itoa(consts.LOYALTY_GRPC_PORT))
+	defer loyaltyConn.Close()
+
+	loyaltyClient := loyalty_pb.NewLoyaltyServiceClient(loyaltyConn)
+
+	// Channels to distribute inventory to, set CHANNEL_SIMULATOR_DIR to sell on a simulated channel in local end to end tests
+	var channels []booking_domain.Channel
+	if simulatorDir := viper.GetString("CHANNEL_SIMULATOR_DIR"); simulatorDir != "" {
+		simulatedChannel, err := booking_infrastructure.NewSimulatedChannel("SIMULATED", simulatorDir)
+		if err != nil {
+			zap.S().Fatalln("Failed to create simulated channel: ", err)
+		}
+		channels = append(channels, simulatedChannel)
+	}
+
 	// 3. Application
-	service := booking_service.NewBookingService(db, repo)
+	service := booking_service.NewBookingService(db, repo, producer, roomClient, roomTypeClient, userClient, loyaltyClient, hotelClient)
+
+	go service.StartNightAuditScheduler(ctx, time.Minute)
+	go service.StartDeletedBookingsPurge(ctx, time.Hour, time.Duration(viper.GetInt("BOOKING_RETENTION_DAYS"))*24*time.Hour)
+	go service.StartIcalImporter(ctx, 15*time.Minute)
+	go service.StartChannelSync(ctx, time.Minute, channels)
 
 	// 4. Server
 	handler := booking_handler.NewBookingGrpcHandler(service)
@@ -0,0 +1,349 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/loyalty_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrNoRoomsAvailable = errors.New("no rooms available")
+var ErrHotelNotPublished = errors.New("hotel is not published")
+
+// Violation of bookings_room_no_overlap, a concurrent booking took the room first
+func isRoomOverlapError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23P01"
+}
+
+type RoomRequest struct {
+	RoomTypeId    pgtype.UUID
+	NumberOfRooms int
+	// Occupant of each room in order, may be shorter than NumberOfRooms
+	Guests []BookingGuest
+}
+
+type BookRoomsParams struct {
+	CheckIn  pgtype.Date
+	CheckOut pgtype.Date
+	Total    int
+	UserId   pgtype.UUID // Invalid for reservations made on a channel
+	Rooms    []RoomRequest
+	// BEST_FIT (default) or GUEST_PREFERENCE
+	AssignmentStrategy string
+	// Assign a higher room type at the original price when the requested one is sold out
+	AllowUpgrade bool
+	// Empty is a direct booking, a channel can only sell rooms within its allotment
+	Source string
+	// Loyalty points spent as a discount on the bookings
+	RedeemPoints int
+}
+
+// Assign rooms to the requested room types then create the bookings,
+// direct bookings and channel reservations both go through here
+func (bs *BookingService) BookRooms(ctx context.Context, params *BookRoomsParams) ([]pgtype.UUID, error) {
+
+	if len(params.Rooms) == 0 {
+		zap.S().Infoln("Booked rooms are not found")
+		return nil, common_error.ErrBadRequest
+	}
+
+	if !params.CheckIn.Time.Before(params.CheckOut.Time) {
+		zap.S().Infoln("Check In date must be before Check Out date")
+		return nil, common_error.ErrBadRequest
+	}
+
+	// Members of a company book direct at its negotiated rates and the stay is billed to the company
+	var company *user_pb.GetCompanyByUserIdResponse
+	if params.UserId.Valid && params.Source == "" {
+		var err error
+		company, err = bs.getMemberCompany(ctx, params.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Other members get the discount of their loyalty tier and can spend points,
+	// without the loyalty service they still book at the public price unless they spend points
+	var loyaltyAccount *loyalty_pb.LoyaltyAccount
+	if company == nil && params.UserId.Valid && params.Source == "" {
+		var err error
+		loyaltyAccount, err = bs.getLoyaltyAccount(ctx, params.UserId)
+		if err != nil && params.RedeemPoints > 0 {
+			return nil, err
+		}
+	}
+
+	if params.RedeemPoints < 0 || (params.RedeemPoints > 0 && loyaltyAccount == nil) {
+		zap.S().Infoln("Points can only be redeemed on direct bookings paid by the member")
+		return nil, common_error.ErrBadRequest
+	}
+
+	nights := int(params.CheckOut.Time.Sub(params.CheckIn.Time).Hours() / 24)
+
+	var newBookings []NewBooking
+	for _, room := range params.Rooms {
+
+		if room.NumberOfRooms <= 0 {
+			zap.S().Infoln("Number of rooms must be positive")
+			return nil, common_error.ErrBadRequest
+		}
+
+		if len(room.Guests) > room.NumberOfRooms {
+			zap.S().Infoln("More guests than booked rooms")
+			return nil, common_error.ErrBadRequest
+		}
+
+		if params.Source != "" {
+			if err := bs.checkChannelAllotment(ctx, params.Source, room, params.CheckIn, params.CheckOut); err != nil {
+				return nil, err
+			}
+		}
+
+		roomTypeResult, err := bs.roomTypeClient.GetRoomTypeById(ctx, &room_type_pb.GetRoomTypeByIdRequest{
+			Id: room.RoomTypeId.String(),
+		})
+		if err != nil {
+			zap.S().Infoln("Failed to get Room Type: ", err)
+			return nil, err
+		}
+
+		var hotelId pgtype.UUID
+		if err := hotelId.Scan(roomTypeResult.GetRoomType().GetHotelId()); err != nil {
+			zap.S().Infoln("Invalid Hotel UUID of Room Type: ", err)
+			return nil, err
+		}
+
+		if err := bs.checkHotelPublished(ctx, hotelId); err != nil {
+			return nil, err
+		}
+
+		// Upgraded rooms keep the price of the requested room type
+		total := params.Total
+		if company != nil {
+			// Billed stays are priced by the server, never by the total sent by the client
+			negotiated, ok := negotiatedTotal(company.GetRates(), hotelId.String(), room.RoomTypeId.String(), int(roomTypeResult.GetRoomType().GetPrice()), nights)
+			if !ok {
+				zap.S().Infoln("Company has no rate for Room Type: ", room.RoomTypeId.String())
+				return nil, ErrNoCompanyRate
+			}
+			total = negotiated
+		}
+		if loyaltyAccount != nil {
+			total = total * (100 - int(loyaltyAccount.GetDiscountPercent())) / 100
+		}
+
+		guests := room.Guests
+		newBooking := func(roomTypeId pgtype.UUID, roomId pgtype.UUID, upgradedFromRoomTypeId pgtype.UUID) NewBooking {
+			var guest BookingGuest
+			if len(guests) > 0 {
+				guest, guests = guests[0], guests[1:]
+			}
+
+			return NewBooking{
+				CheckIn:                params.CheckIn,
+				CheckOut:               params.CheckOut,
+				Total:                  total,
+				RoomTypeId:             roomTypeId,
+				HotelId:                hotelId,
+				UserId:                 params.UserId,
+				RoomId:                 roomId,
+				UpgradedFromRoomTypeId: upgradedFromRoomTypeId,
+				Source:                 params.Source,
+				Guest:                  guest,
+			}
+		}
+
+		assignedRoomIds, err := bs.getRemainRoomIds(ctx, room.RoomTypeId, params, room.NumberOfRooms)
+		if err != nil {
+			zap.S().Infoln("Failed to get AVAILABLE Rooms: ", err)
+			return nil, err
+		}
+
+		shortage := room.NumberOfRooms - len(assignedRoomIds)
+
+		// Sold out, give the guest a higher room type at the original price
+		if shortage > 0 && params.AllowUpgrade {
+			upgradeResult, err := bs.roomTypeClient.GetUpgradeRoomTypesByRoomTypeId(ctx, &room_type_pb.GetUpgradeRoomTypesByRoomTypeIdRequest{
+				RoomTypeId: room.RoomTypeId.String(),
+			})
+			if err != nil {
+				zap.S().Infoln("Failed to get upgrade Room Types: ", err)
+				return nil, err
+			}
+
+			upgrades, err := upgradeRooms(upgradeResult.GetRoomTypes(), shortage, func(roomTypeId pgtype.UUID, numberOfRooms int) ([]pgtype.UUID, error) {
+				return bs.getRemainRoomIds(ctx, roomTypeId, params, numberOfRooms)
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, upgrade := range upgrades {
+				newBookings = append(newBookings, newBooking(upgrade.RoomTypeId, upgrade.RoomId, room.RoomTypeId))
+			}
+			shortage -= len(upgrades)
+		}
+
+		// Not enough rooms, try to overbook the rest within the allowance of room type
+		overbookingAllowance := 0
+		if shortage > 0 {
+			allowanceResult, err := bs.roomTypeClient.GetOverbookingAllowance(ctx, &room_type_pb.GetOverbookingAllowanceRequest{
+				RoomTypeId: room.RoomTypeId.String(),
+				CheckIn:    params.CheckIn.Time.Format("2006-01-02"),
+				CheckOut:   params.CheckOut.Time.Format("2006-01-02"),
+			})
+			if err != nil {
+				zap.S().Infoln("Failed to get overbooking allowance: ", err)
+				return nil, err
+			}
+
+			maxUnassignedBookings, err := bs.GetMaxUnassignedBookingsPerNight(ctx, room.RoomTypeId, params.CheckIn, params.CheckOut)
+			if err != nil {
+				return nil, err
+			}
+
+			overbookingAllowance = int(allowanceResult.GetAllowance())
+			if maxUnassignedBookings+shortage > overbookingAllowance {
+				zap.S().Infoln("There is not enough rooms AVAILABLE and overbooking allowance is exceeded")
+				return nil, ErrNoRoomsAvailable
+			}
+
+			for range shortage {
+				assignedRoomIds = append(assignedRoomIds, pgtype.UUID{})
+			}
+		}
+
+		for _, roomId := range assignedRoomIds {
+			booking := newBooking(room.RoomTypeId, roomId, pgtype.UUID{})
+			booking.OverbookingAllowance = overbookingAllowance
+			newBookings = append(newBookings, booking)
+		}
+	}
+
+	if company != nil {
+		return bs.createCompanyBookings(ctx, company, newBookings)
+	}
+
+	if params.RedeemPoints > 0 {
+		return bs.createBookingsWithPoints(ctx, params.UserId, params.RedeemPoints, newBookings)
+	}
+
+	return bs.CreateBookings(ctx, newBookings)
+}
+
+// Room of a higher room type given to a guest of a sold out room type
+type upgradeRoom struct {
+	RoomTypeId pgtype.UUID
+	RoomId     pgtype.UUID
+}
+
+// Walk the upgrade ladder nearest room type first and take free rooms until shortage rooms are found,
+// remainRoomIds returns at most numberOfRooms free rooms of a room type
+func upgradeRooms(upgradeRoomTypes []*room_type_pb.RoomType, shortage int, remainRoomIds func(roomTypeId pgtype.UUID, numberOfRooms int) ([]pgtype.UUID, error)) ([]upgradeRoom, error) {
+
+	var upgrades []upgradeRoom
+	for _, upgradeRoomType := range upgradeRoomTypes {
+		if shortage <= 0 {
+			break
+		}
+
+		var upgradeRoomTypeId pgtype.UUID
+		if err := upgradeRoomTypeId.Scan(upgradeRoomType.GetId()); err != nil {
+			zap.S().Infoln("Invalid upgrade Room Type UUID: ", err)
+			return nil, err
+		}
+
+		upgradeRoomIds, err := remainRoomIds(upgradeRoomTypeId, shortage)
+		if err != nil {
+			zap.S().Infoln("Failed to get AVAILABLE upgrade Rooms: ", err)
+			return nil, err
+		}
+
+		upgradeRoomIds = upgradeRoomIds[:min(len(upgradeRoomIds), shortage)]
+		for _, roomId := range upgradeRoomIds {
+			upgrades = append(upgrades, upgradeRoom{RoomTypeId: upgradeRoomTypeId, RoomId: roomId})
+		}
+		shortage -= len(upgradeRoomIds)
+	}
+
+	return upgrades, nil
+}
+
+// Only published hotels take bookings, the hotel service does not find other hotels for a caller without user.
+// ErrHotelNotPublished when the hotel is not published
+func (bs *BookingService) checkHotelPublished(ctx context.Context, hotelId pgtype.UUID) error {
+	if bs.hotelClient == nil {
+		return nil
+	}
+
+	result, err := bs.hotelClient.GetHotelById(ctx, &hotel_pb.GetHotelByIdRequest{
+		Id: hotelId.String(),
+	})
+	if status.Code(err) == codes.NotFound {
+		zap.S().Infoln("Hotel of booking is not found or not published: ", hotelId.String())
+		return ErrHotelNotPublished
+	}
+	if err != nil {
+		zap.S().Errorln("Failed to get Hotel of booking: ", err)
+		return err
+	}
+
+	if result.GetHotel().GetStatus() != booking_domain.HOTEL_STATUS_PUBLISHED {
+		zap.S().Infoln("Hotel of booking is not published: ", hotelId.String())
+		return ErrHotelNotPublished
+	}
+
+	return nil
+}
+
+// Return up to numberOfRooms free rooms of room type in range of the booking, may return less
+func (bs *BookingService) getRemainRoomIds(ctx context.Context, roomTypeId pgtype.UUID, params *BookRoomsParams, numberOfRooms int) ([]pgtype.UUID, error) {
+
+	// Already booked Rooms in range of time
+	unavailableRoomIds, err := bs.GetUnavailableRoomsByRoomTypeId(ctx, roomTypeId, params.CheckIn, params.CheckOut)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rooms booked right before or after the stay, so assigned rooms leave no gaps
+	adjacentBookedRooms, err := bs.GetAdjacentBookedRoomsByRoomTypeId(ctx, roomTypeId, params.CheckIn, params.CheckOut)
+	if err != nil {
+		return nil, err
+	}
+
+	adjacentRooms := make([]*room_pb.AdjacentRoom, 0, len(adjacentBookedRooms))
+	for _, adjacentRoom := range adjacentBookedRooms {
+		adjacentRooms = append(adjacentRooms, &room_pb.AdjacentRoom{
+			RoomId:          adjacentRoom.RoomID.String(),
+			TouchesCheckIn:  adjacentRoom.TouchesCheckIn,
+			TouchesCheckOut: adjacentRoom.TouchesCheckOut,
+		})
+	}
+
+	result, err := bs.roomClient.GetListOfRemainRooms(ctx, &room_pb.GetListOfRemainRoomsRequest{
+		RoomTypeId:         roomTypeId.String(),
+		BookedRoomIds:      utils.ToPgUuidString(unavailableRoomIds),
+		NumberOfRooms:      int32(numberOfRooms),
+		AllowPartial:       true,
+		AdjacentRooms:      adjacentRooms,
+		AssignmentStrategy: params.AssignmentStrategy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ToPgUuidArray(result.GetRoomIds())
+}
@@ -0,0 +1,175 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Hotel client answering only GetHotelById, any other call panics
+type fakeHotelClient struct {
+	hotel_pb.HotelServiceClient
+	hotel *hotel_pb.Hotel
+	err   error
+}
+
+func (fc *fakeHotelClient) GetHotelById(ctx context.Context, in *hotel_pb.GetHotelByIdRequest, opts ...grpc.CallOption) (*hotel_pb.GetHotelByIdResponse, error) {
+	if fc.err != nil {
+		return nil, fc.err
+	}
+	return &hotel_pb.GetHotelByIdResponse{Hotel: fc.hotel}, nil
+}
+
+func TestCheckHotelPublished(t *testing.T) {
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan("6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b"); err != nil {
+		t.Fatal(err)
+	}
+
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	tests := []struct {
+		name    string
+		client  *fakeHotelClient
+		wantErr error
+	}{
+		{"published", &fakeHotelClient{hotel: &hotel_pb.Hotel{Status: "PUBLISHED"}}, nil},
+		{"hidden by the hotel service", &fakeHotelClient{err: status.Error(codes.NotFound, "not found")}, ErrHotelNotPublished},
+		{"approved but not published", &fakeHotelClient{hotel: &hotel_pb.Hotel{Status: "APPROVED"}}, ErrHotelNotPublished},
+		{"draft", &fakeHotelClient{hotel: &hotel_pb.Hotel{Status: "DRAFT"}}, ErrHotelNotPublished},
+		{"hotel service down", &fakeHotelClient{err: unavailable}, unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := &BookingService{hotelClient: tt.client}
+
+			if err := bs.checkHotelPublished(context.Background(), hotelId); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkHotelPublished() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpgradeRooms(t *testing.T) {
+	uuid := func(s string) pgtype.UUID {
+		var id pgtype.UUID
+		if err := id.Scan(s); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	deluxe := uuid("11111111-1111-4111-8111-111111111111")
+	suite := uuid("22222222-2222-4222-8222-222222222222")
+	room1 := uuid("aaaaaaaa-0000-4000-8000-000000000001")
+	room2 := uuid("aaaaaaaa-0000-4000-8000-000000000002")
+	room3 := uuid("aaaaaaaa-0000-4000-8000-000000000003")
+
+	ladder := []*room_type_pb.RoomType{{Id: deluxe.String()}, {Id: suite.String()}}
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	tests := []struct {
+		name         string
+		roomTypes    []*room_type_pb.RoomType
+		shortage     int
+		freeRooms    map[pgtype.UUID][]pgtype.UUID
+		remainErr    error
+		want         []upgradeRoom
+		wantErr      bool
+		wantAskedFor []pgtype.UUID
+	}{
+		{
+			name:      "nearest upgrade first",
+			roomTypes: ladder,
+			shortage:  1,
+			freeRooms: map[pgtype.UUID][]pgtype.UUID{deluxe: {room1}, suite: {room2}},
+			want:      []upgradeRoom{{deluxe, room1}},
+			// The suite is not looked at once the deluxe filled the shortage
+			wantAskedFor: []pgtype.UUID{deluxe},
+		},
+		{
+			name:         "climbs the ladder when the nearest upgrade is sold out",
+			roomTypes:    ladder,
+			shortage:     2,
+			freeRooms:    map[pgtype.UUID][]pgtype.UUID{deluxe: {room1}, suite: {room2, room3}},
+			want:         []upgradeRoom{{deluxe, room1}, {suite, room2}},
+			wantAskedFor: []pgtype.UUID{deluxe, suite},
+		},
+		{
+			name:         "partial when the whole ladder is short",
+			roomTypes:    ladder,
+			shortage:     3,
+			freeRooms:    map[pgtype.UUID][]pgtype.UUID{suite: {room2}},
+			want:         []upgradeRoom{{suite, room2}},
+			wantAskedFor: []pgtype.UUID{deluxe, suite},
+		},
+		{
+			name:      "no upgrade room types",
+			roomTypes: nil,
+			shortage:  1,
+		},
+		{
+			name:      "no shortage",
+			roomTypes: ladder,
+			shortage:  0,
+			freeRooms: map[pgtype.UUID][]pgtype.UUID{deluxe: {room1}},
+		},
+		{
+			name:      "invalid upgrade room type id",
+			roomTypes: []*room_type_pb.RoomType{{Id: "not-a-uuid"}},
+			shortage:  1,
+			wantErr:   true,
+		},
+		{
+			name:         "failure to get free rooms",
+			roomTypes:    ladder,
+			shortage:     1,
+			remainErr:    unavailable,
+			wantErr:      true,
+			wantAskedFor: []pgtype.UUID{deluxe},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var askedFor []pgtype.UUID
+			remainRoomIds := func(roomTypeId pgtype.UUID, numberOfRooms int) ([]pgtype.UUID, error) {
+				askedFor = append(askedFor, roomTypeId)
+				if tt.remainErr != nil {
+					return nil, tt.remainErr
+				}
+				free := tt.freeRooms[roomTypeId]
+				return free[:min(len(free), numberOfRooms)], nil
+			}
+
+			got, err := upgradeRooms(tt.roomTypes, tt.shortage, remainRoomIds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("upgradeRooms() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("upgradeRooms() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("upgradeRooms()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+			if len(askedFor) != len(tt.wantAskedFor) {
+				t.Fatalf("asked free rooms of %v, want %v", askedFor, tt.wantAskedFor)
+			}
+			for i := range askedFor {
+				if askedFor[i] != tt.wantAskedFor[i] {
+					t.Errorf("asked free rooms of %v, want %v", askedFor, tt.wantAskedFor)
+				}
+			}
+		})
+	}
+}
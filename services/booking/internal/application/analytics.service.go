@@ -0,0 +1,234 @@
+package booking_service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Rollups of analytics, same names as postgres date_trunc
+const (
+	ANALYTICS_DAY   = "day"
+	ANALYTICS_WEEK  = "week"
+	ANALYTICS_MONTH = "month"
+)
+
+type HotelAnalyticsParams struct {
+	HotelId     pgtype.UUID
+	StartDate   pgtype.Date // First night of the range
+	EndDate     pgtype.Date // Exclusive
+	Granularity string
+	RoomCounts  map[string]int // Room type id -> number of rooms, from hotel service
+}
+
+// Same analytics for several hotels, e.g. every hotel of a chain
+type HotelsAnalyticsParams struct {
+	HotelIds    []pgtype.UUID
+	StartDate   pgtype.Date // First night of the range
+	EndDate     pgtype.Date // Exclusive
+	Granularity string
+	RoomCounts  map[string]map[string]int // Hotel id -> room type id -> number of rooms, from hotel service
+}
+
+// Metrics of a room type in a period, empty RoomTypeId is the whole hotel
+type AnalyticsPeriod struct {
+	PeriodStart         time.Time
+	RoomTypeId          string
+	RoomNightsAvailable int
+	RoomNightsSold      int
+	RoomRevenue         int64
+	OccupancyRate       float64 // Percent of available room nights sold
+	Adr                 float64 // Average daily rate: revenue / room nights sold
+	RevPar              float64 // Revenue per available room night
+	BookingsCreated     int     // Booking pace: bookings made in the period
+	RoomNightsBooked    int
+	BookedRevenue       int64
+}
+
+type analyticsKey struct {
+	periodStart time.Time
+	roomTypeId  string
+}
+
+// Occupancy, ADR, RevPAR and booking pace of a hotel per room type and per period
+func (bs *BookingService) GetHotelAnalytics(ctx context.Context, params *HotelAnalyticsParams) ([]AnalyticsPeriod, error) {
+
+	periodsByHotel, err := bs.GetHotelsAnalytics(ctx, &HotelsAnalyticsParams{
+		HotelIds:    []pgtype.UUID{params.HotelId},
+		StartDate:   params.StartDate,
+		EndDate:     params.EndDate,
+		Granularity: params.Granularity,
+		RoomCounts:  map[string]map[string]int{params.HotelId.String(): params.RoomCounts},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return periodsByHotel[params.HotelId.String()], nil
+}
+
+// Analytics of every hotel by hotel id, bookings of all the hotels are read in one query per metric
+func (bs *BookingService) GetHotelsAnalytics(ctx context.Context, params *HotelsAnalyticsParams) (map[string][]AnalyticsPeriod, error) {
+
+	params.Granularity = strings.ToLower(params.Granularity)
+	if params.Granularity == "" {
+		params.Granularity = ANALYTICS_DAY
+	}
+	if !slices.Contains([]string{ANALYTICS_DAY, ANALYTICS_WEEK, ANALYTICS_MONTH}, params.Granularity) {
+		zap.S().Infoln("Invalid analytics granularity: ", params.Granularity)
+		return nil, common_error.ErrBadRequest
+	}
+
+	if !params.StartDate.Time.Before(params.EndDate.Time) {
+		zap.S().Infoln("Start date must be before end date")
+		return nil, common_error.ErrBadRequest
+	}
+
+	if len(params.HotelIds) == 0 {
+		return map[string][]AnalyticsPeriod{}, nil
+	}
+
+	soldRows, err := bs.repo.GetRoomNightsSoldByPeriod(ctx, booking_repo.GetRoomNightsSoldByPeriodParams{
+		Granularity: params.Granularity,
+		StartDate:   params.StartDate,
+		EndDate:     params.EndDate,
+		HotelIds:    params.HotelIds,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get room nights sold: ", err)
+		return nil, err
+	}
+
+	paceRows, err := bs.repo.GetBookingPaceByPeriod(ctx, booking_repo.GetBookingPaceByPeriodParams{
+		Granularity: params.Granularity,
+		HotelIds:    params.HotelIds,
+		StartDate:   params.StartDate,
+		EndDate:     params.EndDate,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get booking pace: ", err)
+		return nil, err
+	}
+
+	return hotelsAnalyticsPeriods(params, soldRows, paceRows), nil
+}
+
+// Periods of each hotel from the rows of all the hotels
+func hotelsAnalyticsPeriods(params *HotelsAnalyticsParams, soldRows []booking_repo.GetRoomNightsSoldByPeriodRow, paceRows []booking_repo.GetBookingPaceByPeriodRow) map[string][]AnalyticsPeriod {
+
+	// Nights of the range that fall in each period
+	nightsPerPeriod := map[time.Time]int{}
+	periodStarts := []time.Time{}
+	for night := params.StartDate.Time; night.Before(params.EndDate.Time); night = night.AddDate(0, 0, 1) {
+		periodStart := truncateAnalyticsPeriod(night, params.Granularity)
+		if _, ok := nightsPerPeriod[periodStart]; !ok {
+			periodStarts = append(periodStarts, periodStart)
+		}
+		nightsPerPeriod[periodStart]++
+	}
+
+	periodsByHotel := make(map[string]map[analyticsKey]*AnalyticsPeriod, len(params.HotelIds))
+	getPeriod := func(hotelId string, periodStart time.Time, roomTypeId string) *AnalyticsPeriod {
+		periods, ok := periodsByHotel[hotelId]
+		if !ok {
+			periods = map[analyticsKey]*AnalyticsPeriod{}
+			periodsByHotel[hotelId] = periods
+		}
+
+		key := analyticsKey{periodStart: periodStart, roomTypeId: roomTypeId}
+		if period, ok := periods[key]; ok {
+			return period
+		}
+
+		rooms := 0
+		if roomTypeId != "" {
+			rooms = params.RoomCounts[hotelId][roomTypeId]
+		} else {
+			for _, roomTypeRooms := range params.RoomCounts[hotelId] {
+				rooms += roomTypeRooms
+			}
+		}
+
+		period := &AnalyticsPeriod{
+			PeriodStart:         periodStart,
+			RoomTypeId:          roomTypeId,
+			RoomNightsAvailable: rooms * nightsPerPeriod[periodStart],
+		}
+		periods[key] = period
+		return period
+	}
+
+	// Every period has a row for each hotel and each of its room types even without bookings
+	for _, hotelId := range params.HotelIds {
+		for _, periodStart := range periodStarts {
+			getPeriod(hotelId.String(), periodStart, "")
+			for roomTypeId := range params.RoomCounts[hotelId.String()] {
+				getPeriod(hotelId.String(), periodStart, roomTypeId)
+			}
+		}
+	}
+
+	for _, row := range soldRows {
+		for _, roomTypeId := range []string{"", row.RoomTypeID.String()} {
+			period := getPeriod(row.HotelID.String(), row.PeriodStart.Time, roomTypeId)
+			period.RoomNightsSold += int(row.RoomNightsSold)
+			period.RoomRevenue += row.RoomRevenue
+		}
+	}
+
+	for _, row := range paceRows {
+		for _, roomTypeId := range []string{"", row.RoomTypeID.String()} {
+			period := getPeriod(row.HotelID.String(), row.PeriodStart.Time, roomTypeId)
+			period.BookingsCreated += int(row.BookingsCreated)
+			period.RoomNightsBooked += int(row.RoomNightsBooked)
+			period.BookedRevenue += row.BookedRevenue
+		}
+	}
+
+	results := make(map[string][]AnalyticsPeriod, len(periodsByHotel))
+	for hotelId, periods := range periodsByHotel {
+		hotelResults := make([]AnalyticsPeriod, 0, len(periods))
+		for _, period := range periods {
+			if period.RoomNightsAvailable > 0 {
+				period.OccupancyRate = float64(period.RoomNightsSold) * 100 / float64(period.RoomNightsAvailable)
+				period.RevPar = float64(period.RoomRevenue) / float64(period.RoomNightsAvailable)
+			}
+			if period.RoomNightsSold > 0 {
+				period.Adr = float64(period.RoomRevenue) / float64(period.RoomNightsSold)
+			}
+
+			hotelResults = append(hotelResults, *period)
+		}
+
+		// By period, hotel total first then room types
+		slices.SortFunc(hotelResults, func(a, b AnalyticsPeriod) int {
+			if c := a.PeriodStart.Compare(b.PeriodStart); c != 0 {
+				return c
+			}
+			return strings.Compare(a.RoomTypeId, b.RoomTypeId)
+		})
+
+		results[hotelId] = hotelResults
+	}
+
+	return results
+}
+
+// Start of the period a date belongs to, weeks start on Monday like postgres
+func truncateAnalyticsPeriod(date time.Time, granularity string) time.Time {
+	switch granularity {
+	case ANALYTICS_WEEK:
+		offset := (int(date.Weekday()) + 6) % 7
+		return date.AddDate(0, 0, -offset)
+	case ANALYTICS_MONTH:
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	default:
+		return date
+	}
+}
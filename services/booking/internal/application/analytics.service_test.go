@@ -0,0 +1,175 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestHotelsAnalyticsPeriods(t *testing.T) {
+	uuid := func(b byte) pgtype.UUID {
+		return pgtype.UUID{Bytes: [16]byte{b}, Valid: true}
+	}
+	date := func(day int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+
+	hotelA, hotelB, hotelC := uuid(1), uuid(2), uuid(3)
+	roomTypeA, roomTypeB := uuid(11), uuid(12)
+
+	params := &HotelsAnalyticsParams{
+		HotelIds:    []pgtype.UUID{hotelA, hotelB, hotelC},
+		StartDate:   date(2),
+		EndDate:     date(4),
+		Granularity: ANALYTICS_DAY,
+		RoomCounts: map[string]map[string]int{
+			hotelA.String(): {roomTypeA.String(): 4},
+			hotelB.String(): {roomTypeB.String(): 10},
+		},
+	}
+	soldRows := []booking_repo.GetRoomNightsSoldByPeriodRow{
+		{PeriodStart: date(2), HotelID: hotelA, RoomTypeID: roomTypeA, RoomNightsSold: 2, RoomRevenue: 200},
+		{PeriodStart: date(2), HotelID: hotelB, RoomTypeID: roomTypeB, RoomNightsSold: 5, RoomRevenue: 1000},
+	}
+	paceRows := []booking_repo.GetBookingPaceByPeriodRow{
+		{PeriodStart: date(3), HotelID: hotelB, RoomTypeID: roomTypeB, BookingsCreated: 1, RoomNightsBooked: 3, BookedRevenue: 600},
+	}
+
+	got := hotelsAnalyticsPeriods(params, soldRows, paceRows)
+
+	if len(got) != 3 {
+		t.Fatalf("hotelsAnalyticsPeriods() hotels = %d, want 3", len(got))
+	}
+
+	// Hotel total then room type for each of the 2 nights
+	tests := []struct {
+		name    string
+		hotelId pgtype.UUID
+		index   int
+		want    AnalyticsPeriod
+	}{
+		{"sold night of hotel A", hotelA, 0, AnalyticsPeriod{PeriodStart: date(2).Time, RoomNightsAvailable: 4, RoomNightsSold: 2, RoomRevenue: 200, OccupancyRate: 50, Adr: 100, RevPar: 50}},
+		{"room type of hotel A", hotelA, 1, AnalyticsPeriod{PeriodStart: date(2).Time, RoomTypeId: roomTypeA.String(), RoomNightsAvailable: 4, RoomNightsSold: 2, RoomRevenue: 200, OccupancyRate: 50, Adr: 100, RevPar: 50}},
+		{"hotel A is not given bookings of hotel B", hotelA, 2, AnalyticsPeriod{PeriodStart: date(3).Time, RoomNightsAvailable: 4}},
+		{"sold night of hotel B", hotelB, 0, AnalyticsPeriod{PeriodStart: date(2).Time, RoomNightsAvailable: 10, RoomNightsSold: 5, RoomRevenue: 1000, OccupancyRate: 50, Adr: 200, RevPar: 100}},
+		{"booking pace of hotel B", hotelB, 2, AnalyticsPeriod{PeriodStart: date(3).Time, RoomNightsAvailable: 10, BookingsCreated: 1, RoomNightsBooked: 3, BookedRevenue: 600}},
+		{"hotel without rooms", hotelC, 1, AnalyticsPeriod{PeriodStart: date(3).Time}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periods := got[tt.hotelId.String()]
+			if tt.index >= len(periods) {
+				t.Fatalf("hotelsAnalyticsPeriods() periods = %d, want more than %d", len(periods), tt.index)
+			}
+			if periods[tt.index] != tt.want {
+				t.Errorf("hotelsAnalyticsPeriods()[%d] = %+v, want %+v", tt.index, periods[tt.index], tt.want)
+			}
+		})
+	}
+
+	if len(got[hotelC.String()]) != 2 {
+		t.Errorf("hotelsAnalyticsPeriods() periods of hotel C = %d, want one per night", len(got[hotelC.String()]))
+	}
+}
+
+func TestHotelsAnalyticsPeriodsMonthRollup(t *testing.T) {
+	hotelId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	roomTypeId := pgtype.UUID{Bytes: [16]byte{11}, Valid: true}
+	date := func(month time.Month, day int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(2026, month, day, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+
+	// 4 nights of March and 2 nights of April
+	params := &HotelsAnalyticsParams{
+		HotelIds:    []pgtype.UUID{hotelId},
+		StartDate:   date(time.March, 28),
+		EndDate:     date(time.April, 3),
+		Granularity: ANALYTICS_MONTH,
+		RoomCounts:  map[string]map[string]int{hotelId.String(): {roomTypeId.String(): 5}},
+	}
+	// A 3 night stay of 100 crossing the month, the query gives its nights 33, 33 and 34
+	soldRows := []booking_repo.GetRoomNightsSoldByPeriodRow{
+		{PeriodStart: date(time.March, 1), HotelID: hotelId, RoomTypeID: roomTypeId, RoomNightsSold: 2, RoomRevenue: 66},
+		{PeriodStart: date(time.April, 1), HotelID: hotelId, RoomTypeID: roomTypeId, RoomNightsSold: 1, RoomRevenue: 34},
+	}
+
+	periods := hotelsAnalyticsPeriods(params, soldRows, nil)[hotelId.String()]
+
+	want := []AnalyticsPeriod{
+		{PeriodStart: date(time.March, 1).Time, RoomNightsAvailable: 20, RoomNightsSold: 2, RoomRevenue: 66, OccupancyRate: 10, Adr: 33, RevPar: 3.3},
+		{PeriodStart: date(time.March, 1).Time, RoomTypeId: roomTypeId.String(), RoomNightsAvailable: 20, RoomNightsSold: 2, RoomRevenue: 66, OccupancyRate: 10, Adr: 33, RevPar: 3.3},
+		{PeriodStart: date(time.April, 1).Time, RoomNightsAvailable: 10, RoomNightsSold: 1, RoomRevenue: 34, OccupancyRate: 10, Adr: 34, RevPar: 3.4},
+		{PeriodStart: date(time.April, 1).Time, RoomTypeId: roomTypeId.String(), RoomNightsAvailable: 10, RoomNightsSold: 1, RoomRevenue: 34, OccupancyRate: 10, Adr: 34, RevPar: 3.4},
+	}
+
+	if len(periods) != len(want) {
+		t.Fatalf("hotelsAnalyticsPeriods() periods = %+v, want %+v", periods, want)
+	}
+	var revenue int64
+	for i := range want {
+		if periods[i] != want[i] {
+			t.Errorf("hotelsAnalyticsPeriods()[%d] = %+v, want %+v", i, periods[i], want[i])
+		}
+		if periods[i].RoomTypeId == "" {
+			revenue += periods[i].RoomRevenue
+		}
+	}
+	if revenue != 100 {
+		t.Errorf("hotelsAnalyticsPeriods() revenue = %d, want the booking total 100", revenue)
+	}
+}
+
+func TestTruncateAnalyticsPeriod(t *testing.T) {
+	tests := []struct {
+		name        string
+		date        time.Time
+		granularity string
+		want        time.Time
+	}{
+		{"day", time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC), ANALYTICS_DAY, time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC)},
+		{"week starts on Monday", time.Date(2026, time.March, 4, 0, 0, 0, 0, time.UTC), ANALYTICS_WEEK, time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)},
+		{"Sunday belongs to the week before", time.Date(2026, time.March, 8, 0, 0, 0, 0, time.UTC), ANALYTICS_WEEK, time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)},
+		{"week across months", time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), ANALYTICS_WEEK, time.Date(2026, time.March, 30, 0, 0, 0, 0, time.UTC)},
+		{"month", time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC), ANALYTICS_MONTH, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateAnalyticsPeriod(tt.date, tt.granularity); !got.Equal(tt.want) {
+				t.Errorf("truncateAnalyticsPeriod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetHotelsAnalyticsInvalidParams(t *testing.T) {
+	date := func(day int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	hotelIds := []pgtype.UUID{{Bytes: [16]byte{1}, Valid: true}}
+
+	tests := []struct {
+		name   string
+		params HotelsAnalyticsParams
+	}{
+		{"unknown granularity", HotelsAnalyticsParams{HotelIds: hotelIds, StartDate: date(2), EndDate: date(4), Granularity: "year"}},
+		{"empty range", HotelsAnalyticsParams{HotelIds: hotelIds, StartDate: date(4), EndDate: date(4)}},
+		{"end before start", HotelsAnalyticsParams{HotelIds: hotelIds, StartDate: date(4), EndDate: date(2)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := &BookingService{}
+
+			if _, err := bs.GetHotelsAnalytics(context.Background(), &tt.params); !errors.Is(err, common_error.ErrBadRequest) {
+				t.Errorf("GetHotelsAnalytics() error = %v, want %v", err, common_error.ErrBadRequest)
+			}
+		})
+	}
+}
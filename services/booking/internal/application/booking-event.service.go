@@ -0,0 +1,118 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const DEFAULT_BOOKING_RETENTION = 365 * 24 * time.Hour
+
+// Booking events are written by a trigger on bookings, it reads actor and reason of the change from the transaction
+func setBookingAuditContext(ctx context.Context, tx pgx.Tx, reason string) error {
+
+	_, err := tx.Exec(ctx, "SELECT set_config('booking.actor', $1, true), set_config('booking.reason', $2, true)",
+		utils.ActorFromContext(ctx), reason)
+	if err != nil {
+		zap.S().Errorln("Failed to set booking audit context: ", err)
+		return err
+	}
+
+	return nil
+}
+
+const bookingEventColumns = `id, booking_id, event_type, actor, changes, reason, create_at`
+
+// Sortable fields of the booking history
+var bookingEventSortColumns = map[string]utils.SortColumn{
+	"create_at": {Column: "create_at", Cast: "timestamp"},
+}
+
+// Events of a booking from creation, also for deleted bookings.
+// History of a purged booking is only readable by an admin, ErrBadRequest when the page is invalid
+func (bs *BookingService) GetBookingHistory(ctx context.Context, bookingId pgtype.UUID, requester *BookingRequester, pageParams utils.PageParams) ([]booking_repo.BookingEvent, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, bookingEventSortColumns, "create_at", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of Booking history: ", pageParams)
+		return nil, nil, err
+	}
+
+	owner, err := bs.repo.GetBookingOwnerById(ctx, bookingId)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		if !requester.IsAdmin {
+			zap.S().Infoln("Booking not found to read history")
+			return nil, nil, common_error.ErrNoRows
+		}
+	case err != nil:
+		zap.S().Errorln("Failed to get Booking owner: ", err)
+		return nil, nil, err
+	case !requester.canAccess(bookingId, owner.UserID, owner.HotelID):
+		zap.S().Infoln("Requester cannot read Booking history")
+		return nil, nil, ErrBookingAccessDenied
+	}
+
+	conditions := &utils.Conditions{}
+	conditions.Add("booking_id = $%d", bookingId)
+
+	events, pageInfo, err := utils.QueryPage(ctx, bs.conn, page, bookingEventColumns, "booking_events", conditions, scanBookingEvent,
+		func(event booking_repo.BookingEvent) (any, pgtype.UUID) {
+			return event.CreateAt, event.ID
+		})
+	if err != nil {
+		zap.S().Errorln("Failed to get Booking events: ", err)
+		return nil, nil, err
+	}
+
+	return events, pageInfo, nil
+}
+
+func scanBookingEvent(rows pgx.Rows) (booking_repo.BookingEvent, error) {
+	var e booking_repo.BookingEvent
+	err := rows.Scan(
+		&e.ID,
+		&e.BookingID,
+		&e.EventType,
+		&e.Actor,
+		&e.Changes,
+		&e.Reason,
+		&e.CreateAt,
+	)
+	return e, err
+}
+
+// Purge soft deleted bookings older than retention every interval
+func (bs *BookingService) StartDeletedBookingsPurge(ctx context.Context, interval time.Duration, retention time.Duration) {
+
+	if retention <= 0 {
+		retention = DEFAULT_BOOKING_RETENTION
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			purged, err := bs.repo.PurgeDeletedBookings(ctx, pgtype.Timestamp{Time: now.Add(-retention), Valid: true})
+			if err != nil {
+				zap.S().Errorln("Failed to purge deleted Bookings: ", err)
+				continue
+			}
+
+			if purged > 0 {
+				zap.S().Infoln("Purged deleted Bookings: ", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestGetBookingHistoryInvalidPage(t *testing.T) {
+	bookingId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+
+	tests := []struct {
+		name string
+		page utils.PageParams
+	}{
+		{"unknown sort", utils.PageParams{SortBy: "total"}},
+		{"unknown direction", utils.PageParams{SortDirection: "sideways"}},
+		{"invalid cursor", utils.PageParams{Cursor: "not-a-cursor"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := &BookingService{}
+
+			_, _, err := bs.GetBookingHistory(context.Background(), bookingId, &BookingRequester{IsAdmin: true}, tt.page)
+			if !errors.Is(err, common_error.ErrBadRequest) {
+				t.Errorf("GetBookingHistory() error = %v, want %v", err, common_error.ErrBadRequest)
+			}
+		})
+	}
+}
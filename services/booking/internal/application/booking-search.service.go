@@ -0,0 +1,135 @@
+package booking_service
+
+import (
+	"context"
+	"strings"
+
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	booking_repo_mapping "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const bookingColumns = `id, check_in, check_out, total, status, hotel_id, room_type_id, user_id, room_id, upgraded_from_room_type_id, confirmation_code, source, company_id, guest_name, guest_email, guest_phone, estimated_arrival_time, deleted_at, create_at, updated_at`
+
+// Sortable fields of a booking list and their column
+var bookingSortColumns = map[string]utils.SortColumn{
+	"check_in":   {Column: "check_in", Cast: "date"},
+	"check_out":  {Column: "check_out", Cast: "date"},
+	"created_at": {Column: "create_at", Cast: "timestamp"},
+	"total":      {Column: "total", Cast: "int"},
+}
+
+type SearchBookingsParams struct {
+	HotelIds         []pgtype.UUID // One hotel, or every hotel of a chain
+	CheckInFrom      pgtype.Date   // Arrival date range
+	CheckInTo        pgtype.Date
+	CheckOutFrom     pgtype.Date // Departure date range
+	CheckOutTo       pgtype.Date
+	Status           string
+	UserIds          []pgtype.UUID // Guests matched by name, nil is no filter
+	ConfirmationCode string
+	RoomId           pgtype.UUID
+	Page             utils.PageParams // sort by check_in (default), check_out, created_at, total
+}
+
+// Search bookings of the hotels with keyset pagination, ErrBadRequest when the page is invalid
+func (bs *BookingService) SearchBookings(ctx context.Context, params *SearchBookingsParams) ([]booking_domain.Booking, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(params.Page, bookingSortColumns, "check_in", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of bookings: ", params.Page)
+		return nil, nil, err
+	}
+
+	conditions := searchBookingsConditions(params)
+
+	bookings, pageInfo, err := utils.QueryPage(ctx, bs.conn, page, bookingColumns, "bookings", conditions, scanBooking, bookingSortKey(page))
+	if err != nil {
+		zap.S().Errorln("Failed to search bookings: ", err)
+		return nil, nil, err
+	}
+
+	return booking_repo_mapping.FromBookingsRepoToBookingsDomain(bookings), pageInfo, nil
+}
+
+// Filters of a booking search, hotels are always filtered and soft deleted bookings never found
+func searchBookingsConditions(params *SearchBookingsParams) *utils.Conditions {
+
+	conditions := &utils.Conditions{}
+	conditions.Add("hotel_id = ANY($%d::uuid[])", params.HotelIds)
+	conditions.Add("deleted_at IS NULL")
+
+	if params.CheckInFrom.Valid {
+		conditions.Add("check_in >= $%d", params.CheckInFrom)
+	}
+	if params.CheckInTo.Valid {
+		conditions.Add("check_in <= $%d", params.CheckInTo)
+	}
+	if params.CheckOutFrom.Valid {
+		conditions.Add("check_out >= $%d", params.CheckOutFrom)
+	}
+	if params.CheckOutTo.Valid {
+		conditions.Add("check_out <= $%d", params.CheckOutTo)
+	}
+	if params.Status != "" {
+		conditions.Add("status = $%d::BOOKING_STATUS", params.Status)
+	}
+	if params.UserIds != nil {
+		conditions.Add("user_id = ANY($%d::uuid[])", params.UserIds)
+	}
+	if params.ConfirmationCode != "" {
+		conditions.Add("confirmation_code = $%d", strings.ToUpper(params.ConfirmationCode))
+	}
+	if params.RoomId.Valid {
+		conditions.Add("room_id = $%d", params.RoomId)
+	}
+
+	return conditions
+}
+
+func scanBooking(rows pgx.Rows) (booking_repo.Booking, error) {
+	var b booking_repo.Booking
+	err := rows.Scan(
+		&b.ID,
+		&b.CheckIn,
+		&b.CheckOut,
+		&b.Total,
+		&b.Status,
+		&b.HotelID,
+		&b.RoomTypeID,
+		&b.UserID,
+		&b.RoomID,
+		&b.UpgradedFromRoomTypeID,
+		&b.ConfirmationCode,
+		&b.Source,
+		&b.CompanyID,
+		&b.GuestName,
+		&b.GuestEmail,
+		&b.GuestPhone,
+		&b.EstimatedArrivalTime,
+		&b.DeletedAt,
+		&b.CreateAt,
+		&b.UpdatedAt,
+	)
+	return b, err
+}
+
+// Sort value and id of a booking for the cursor of the next page
+func bookingSortKey(page *utils.Page) func(booking_repo.Booking) (any, pgtype.UUID) {
+	return func(b booking_repo.Booking) (any, pgtype.UUID) {
+		switch page.SortBy {
+		case "check_out":
+			return b.CheckOut, b.ID
+		case "created_at":
+			return b.CreateAt, b.ID
+		case "total":
+			return b.Total, b.ID
+		default:
+			return b.CheckIn, b.ID
+		}
+	}
+}
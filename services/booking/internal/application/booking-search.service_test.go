@@ -0,0 +1,72 @@
+package booking_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestSearchBookingsConditions(t *testing.T) {
+	var hotelId, roomId pgtype.UUID
+	if err := hotelId.Scan("6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := roomId.Scan("7a2d3c8f-4e5b-4f60-9bac-1d2e3f4a5b6c"); err != nil {
+		t.Fatal(err)
+	}
+	date := pgtype.Date{Time: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	tests := []struct {
+		name     string
+		params   SearchBookingsParams
+		want     string
+		wantArgs int
+	}{
+		{
+			name:     "hotel only",
+			params:   SearchBookingsParams{HotelIds: []pgtype.UUID{hotelId}},
+			want:     "hotel_id = ANY($1::uuid[]) AND deleted_at IS NULL",
+			wantArgs: 1,
+		},
+		{
+			name:     "arrivals of a day",
+			params:   SearchBookingsParams{HotelIds: []pgtype.UUID{hotelId}, CheckInFrom: date, CheckInTo: date},
+			want:     "hotel_id = ANY($1::uuid[]) AND deleted_at IS NULL AND check_in >= $2 AND check_in <= $3",
+			wantArgs: 3,
+		},
+		{
+			name:     "departures and status",
+			params:   SearchBookingsParams{HotelIds: []pgtype.UUID{hotelId}, CheckOutFrom: date, CheckOutTo: date, Status: "CHECK_IN"},
+			want:     "hotel_id = ANY($1::uuid[]) AND deleted_at IS NULL AND check_out >= $2 AND check_out <= $3 AND status = $4::BOOKING_STATUS",
+			wantArgs: 4,
+		},
+		{
+			name:     "guests matched by name, confirmation code and room",
+			params:   SearchBookingsParams{HotelIds: []pgtype.UUID{hotelId}, UserIds: []pgtype.UUID{}, ConfirmationCode: "ab12cd", RoomId: roomId},
+			want:     "hotel_id = ANY($1::uuid[]) AND deleted_at IS NULL AND user_id = ANY($2::uuid[]) AND confirmation_code = $3 AND room_id = $4",
+			wantArgs: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := searchBookingsConditions(&tt.params)
+
+			if got := conditions.String(); got != tt.want {
+				t.Errorf("searchBookingsConditions() = %q, want %q", got, tt.want)
+			}
+			if len(conditions.Args) != tt.wantArgs {
+				t.Errorf("searchBookingsConditions() args = %v, want %d", conditions.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSearchBookingsConditionsUppercaseConfirmationCode(t *testing.T) {
+	conditions := searchBookingsConditions(&SearchBookingsParams{ConfirmationCode: "ab12cd"})
+
+	if got := conditions.Args[len(conditions.Args)-1]; got != "AB12CD" {
+		t.Errorf("searchBookingsConditions() confirmation code = %v, want AB12CD", got)
+	}
+}
@@ -0,0 +1,271 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrTransferNotAllowed = errors.New("not allowed to transfer booking")
+var ErrBookingNotTransferable = errors.New("booking cannot be transferred")
+
+// Offer the booking to another user, the owner changes when the recipient accepts.
+// Only the owner or an admin can transfer, a new transfer cancels the pending one
+func (bs *BookingService) TransferBooking(ctx context.Context, bookingId pgtype.UUID, targetUserId pgtype.UUID, requesterId pgtype.UUID, isAdmin bool) (*booking_repo.BookingTransfer, error) {
+
+	toUserEmail, err := bs.getUserEmail(ctx, targetUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := bs.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin transfer booking transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := bs.repo.WithTx(tx)
+
+	booking, err := qtx.GetBookingById(ctx, bookingId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("No Booking to transfer")
+		return nil, common_error.ErrNoRows
+	}
+	if err != nil {
+		zap.S().Errorln("Cannot get Booking by id: ", err)
+		return nil, err
+	}
+
+	if !isAdmin && booking.UserID != requesterId {
+		zap.S().Infoln("Only the owner of the Booking can transfer it")
+		return nil, ErrTransferNotAllowed
+	}
+
+	// Channel reservations have no user to transfer from
+	if !booking.UserID.Valid {
+		zap.S().Infoln("Booking has no user to transfer from")
+		return nil, ErrBookingNotTransferable
+	}
+
+	if booking.UserID == targetUserId {
+		zap.S().Infoln("Booking already belongs to the target user")
+		return nil, common_error.ErrBadRequest
+	}
+
+	// Guests in house, paid ones included, keep their booking
+	if booking.Status != booking_repo.BookingStatusBOOKED {
+		zap.S().Infoln("Booking cannot be transferred in status: ", booking.Status)
+		return nil, ErrBookingNotTransferable
+	}
+
+	if _, err := qtx.CancelPendingBookingTransfer(ctx, booking.ID); err != nil {
+		zap.S().Errorln("Failed to cancel pending Booking Transfer: ", err)
+		return nil, err
+	}
+
+	actor := utils.ActorFromContext(ctx)
+	if actor == "" {
+		actor = "system"
+	}
+
+	transfer, err := qtx.CreateBookingTransfer(ctx, booking_repo.CreateBookingTransferParams{
+		BookingID:   booking.ID,
+		FromUserID:  booking.UserID,
+		ToUserID:    targetUserId,
+		RequestedBy: actor,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to create Booking Transfer: ", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit Booking Transfer: ", err)
+		return nil, err
+	}
+
+	fromUserEmail, err := bs.getUserEmail(ctx, booking.UserID)
+	if err != nil {
+		zap.S().Errorln("Failed to get email of Booking owner: ", err)
+	}
+
+	bs.producer.PublishBookingTransfer(ctx, toBookingTransferEvent(transfer, booking.ConfirmationCode, fromUserEmail, toUserEmail))
+
+	return &transfer, nil
+}
+
+// Recipient accepts or declines the transfer, accepting moves the booking to the recipient.
+// The change of owner is kept in the booking history
+func (bs *BookingService) RespondBookingTransfer(ctx context.Context, transferId pgtype.UUID, userId pgtype.UUID, accept bool) (*booking_repo.BookingTransfer, error) {
+
+	tx, err := bs.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin respond booking transfer transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	transferStatus := booking_repo.BookingTransferStatusDECLINED
+	if accept {
+		transferStatus = booking_repo.BookingTransferStatusACCEPTED
+	}
+
+	if err := setBookingAuditContext(ctx, tx, "transfer "+transferId.String()+" "+strings.ToLower(string(transferStatus))); err != nil {
+		return nil, err
+	}
+
+	qtx := bs.repo.WithTx(tx)
+
+	transfer, err := qtx.GetBookingTransferById(ctx, transferId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("No Booking Transfer to respond")
+		return nil, common_error.ErrNoRows
+	}
+	if err != nil {
+		zap.S().Errorln("Cannot get Booking Transfer by id: ", err)
+		return nil, err
+	}
+
+	if transfer.ToUserID != userId {
+		zap.S().Infoln("Only the recipient can respond to the Booking Transfer")
+		return nil, ErrTransferNotAllowed
+	}
+
+	transfer, err = qtx.RespondBookingTransfer(ctx, booking_repo.RespondBookingTransferParams{
+		Status: transferStatus,
+		ID:     transfer.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("Booking Transfer is no longer pending")
+		return nil, ErrBookingNotTransferable
+	}
+	if err != nil {
+		zap.S().Errorln("Failed to respond Booking Transfer: ", err)
+		return nil, err
+	}
+
+	// A cancelled booking can still be declined, accepting it fails below
+	booking, err := qtx.GetBookingById(ctx, transfer.BookingID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Errorln("Cannot get Booking by id: ", err)
+		return nil, err
+	}
+
+	if accept {
+		transferred, err := qtx.TransferBookingOwner(ctx, booking_repo.TransferBookingOwnerParams{
+			ToUserID:   transfer.ToUserID,
+			ID:         transfer.BookingID,
+			FromUserID: transfer.FromUserID,
+		})
+		if err != nil {
+			zap.S().Errorln("Failed to transfer Booking owner: ", err)
+			return nil, err
+		}
+
+		// The owner changed or the guest arrived since the transfer was requested
+		if transferred == 0 {
+			zap.S().Infoln("Booking can no longer be transferred")
+			return nil, ErrBookingNotTransferable
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit Booking Transfer response: ", err)
+		return nil, err
+	}
+
+	fromUserEmail, err := bs.getUserEmail(ctx, transfer.FromUserID)
+	if err != nil {
+		zap.S().Errorln("Failed to get email of Booking sender: ", err)
+	}
+
+	toUserEmail, err := bs.getUserEmail(ctx, transfer.ToUserID)
+	if err != nil {
+		zap.S().Errorln("Failed to get email of Booking recipient: ", err)
+	}
+
+	bs.producer.PublishBookingTransfer(ctx, toBookingTransferEvent(transfer, booking.ConfirmationCode, fromUserEmail, toUserEmail))
+
+	return &transfer, nil
+}
+
+const bookingTransferColumns = `id, booking_id, from_user_id, to_user_id, status, requested_by, responded_at, create_at`
+
+// Sortable fields of the booking transfers, newest first by default
+var bookingTransferSortColumns = map[string]utils.SortColumn{
+	"create_at": {Column: "create_at", Cast: "timestamp", DefaultDesc: true},
+}
+
+// Pending transfers the user received or sent, ErrBadRequest when the page is invalid
+func (bs *BookingService) GetPendingBookingTransfers(ctx context.Context, userId pgtype.UUID, pageParams utils.PageParams) ([]booking_repo.BookingTransfer, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, bookingTransferSortColumns, "create_at", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of Booking Transfers: ", pageParams)
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	conditions.Add("status = 'PENDING'")
+	conditions.Add("(to_user_id = $%d OR from_user_id = $%d)", userId, userId)
+
+	transfers, pageInfo, err := utils.QueryPage(ctx, bs.conn, page, bookingTransferColumns, "booking_transfers", conditions,
+		func(rows pgx.Rows) (booking_repo.BookingTransfer, error) {
+			var t booking_repo.BookingTransfer
+			err := rows.Scan(&t.ID, &t.BookingID, &t.FromUserID, &t.ToUserID, &t.Status, &t.RequestedBy, &t.RespondedAt, &t.CreateAt)
+			return t, err
+		},
+		func(t booking_repo.BookingTransfer) (any, pgtype.UUID) {
+			return t.CreateAt, t.ID
+		})
+	if err != nil {
+		zap.S().Errorln("Failed to get pending Booking Transfers: ", err)
+		return nil, nil, err
+	}
+
+	return transfers, pageInfo, nil
+}
+
+// Email of the user, unknown users are a bad request
+func (bs *BookingService) getUserEmail(ctx context.Context, userId pgtype.UUID) (string, error) {
+
+	result, err := bs.userClient.GetUserById(ctx, &user_pb.GetUserByIdRequest{
+		Id: userId.String(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			zap.S().Infoln("User not found: ", userId.String())
+			return "", common_error.ErrBadRequest
+		}
+
+		zap.S().Errorln("Failed to get User by id: ", err)
+		return "", err
+	}
+
+	return result.GetUser().GetEmail(), nil
+}
+
+func toBookingTransferEvent(transfer booking_repo.BookingTransfer, confirmationCode string, fromUserEmail string, toUserEmail string) booking_domain.BookingTransferEvent {
+	return booking_domain.BookingTransferEvent{
+		TransferId:       transfer.ID.String(),
+		BookingId:        transfer.BookingID.String(),
+		ConfirmationCode: confirmationCode,
+		FromUserId:       transfer.FromUserID.String(),
+		FromUserEmail:    fromUserEmail,
+		ToUserId:         transfer.ToUserID.String(),
+		ToUserEmail:      toUserEmail,
+		Status:           string(transfer.Status),
+	}
+}
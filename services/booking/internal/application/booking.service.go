@@ -2,27 +2,57 @@ package booking_service
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	booking_infrastructure "github.com/098765432m/grpc-kafka/booking/internal/infrastructure"
 	booking_repo_mapping "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository"
 	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
 	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/loyalty_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
 type BookingService struct {
-	conn *pgxpool.Pool
-	repo *booking_repo.Queries
+	conn           *pgxpool.Pool
+	repo           *booking_repo.Queries
+	producer       *booking_infrastructure.KafkaProducer
+	roomClient     room_pb.RoomServiceClient
+	roomTypeClient room_type_pb.RoomTypeServiceClient
+	userClient     user_pb.UserServiceClient
+	loyaltyClient  loyalty_pb.LoyaltyServiceClient
+	hotelClient    hotel_pb.HotelServiceClient
 }
 
-func NewBookingService(conn *pgxpool.Pool, repo *booking_repo.Queries) *BookingService {
+func NewBookingService(conn *pgxpool.Pool,
+	repo *booking_repo.Queries,
+	producer *booking_infrastructure.KafkaProducer,
+	roomClient room_pb.RoomServiceClient,
+	roomTypeClient room_type_pb.RoomTypeServiceClient,
+	userClient user_pb.UserServiceClient,
+	loyaltyClient loyalty_pb.LoyaltyServiceClient,
+	hotelClient hotel_pb.HotelServiceClient) *BookingService {
 	return &BookingService{
-		conn: conn,
-		repo: repo,
+		conn:           conn,
+		repo:           repo,
+		producer:       producer,
+		roomClient:     roomClient,
+		roomTypeClient: roomTypeClient,
+		userClient:     userClient,
+		loyaltyClient:  loyaltyClient,
+		hotelClient:    hotelClient,
 	}
 }
 
@@ -38,58 +68,99 @@ func (bs *BookingService) GetBookingById(ctx context.Context, id pgtype.UUID) (*
 	return &booking, nil
 }
 
+// Booking of the confirmation code, the code is case insensitive
+func (bs *BookingService) GetBookingByConfirmationCode(ctx context.Context, confirmationCode string) (*booking_domain.Booking, error) {
+
+	booking, err := bs.repo.GetBookingByConfirmationCode(ctx, strings.TrimSpace(confirmationCode))
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("No Booking of confirmation code")
+		return nil, common_error.ErrNoRows
+	}
+	if err != nil {
+		zap.S().Errorln("Cannot get Booking by confirmation code: ", err)
+		return nil, err
+	}
+
+	result := booking_repo_mapping.FromBookingRepoToBookingDomain(booking)
+	return &result, nil
+}
+
 type GetBookingsByUserIdParams struct {
 	UserId         pgtype.UUID
 	CheckDateStart pgtype.Date
 	CheckDateEnd   pgtype.Date
-	Size           int
-	Offset         int
+	Page           utils.PageParams // sort by check_in (default), check_out, created_at, total
 }
 
-func (bs *BookingService) GetBookingsByUserId(ctx context.Context, params *GetBookingsByUserIdParams) ([]booking_domain.Booking, error) {
+// Bookings checking in within the date range when both dates are given, ErrBadRequest when the page is invalid
+func (bs *BookingService) GetBookingsByUserId(ctx context.Context, params *GetBookingsByUserIdParams) ([]booking_domain.Booking, *utils.PageInfo, error) {
 
-	bookings, err := bs.repo.GetBookingsByUserId(ctx, booking_repo.GetBookingsByUserIdParams{
-		UserID:         params.UserId,
-		CheckDateStart: params.CheckDateStart,
-		CheckDateEnd:   params.CheckDateEnd,
-		Size:           int32(params.Size),
-		NumberOfOffset: int32(params.Offset),
-	})
+	page, err := utils.NewPage(params.Page, bookingSortColumns, "check_in", "id")
 	if err != nil {
-		zap.S().Errorln("Failed to Get Bookings by User Id: ", err)
-		return nil, err
+		zap.S().Infoln("Invalid page of bookings: ", params.Page)
+		return nil, nil, err
 	}
 
-	result := booking_repo_mapping.FromBookingsRepoToBookingsDomain(bookings)
-
-	return result, nil
-}
+	conditions := &utils.Conditions{}
+	conditions.Add("user_id = $%d", params.UserId)
+	conditions.Add("deleted_at IS NULL")
+	if params.CheckDateStart.Valid && params.CheckDateEnd.Valid {
+		conditions.Add("check_in BETWEEN $%d AND $%d", params.CheckDateStart, params.CheckDateEnd)
+	}
 
-func (bs *BookingService) CreateBooking(ctx context.Context, bookingParams *booking_repo.CreateBookingParams) error {
-	if err := bs.repo.CreateBooking(ctx, *bookingParams); err != nil {
-		zap.S().Errorln("Cannot create booking")
-		return err
+	bookings, pageInfo, err := utils.QueryPage(ctx, bs.conn, page, bookingColumns, "bookings", conditions, scanBooking, bookingSortKey(page))
+	if err != nil {
+		zap.S().Errorln("Failed to Get Bookings by User Id: ", err)
+		return nil, nil, err
 	}
 
-	return nil
+	return booking_repo_mapping.FromBookingsRepoToBookingsDomain(bookings), pageInfo, nil
 }
 
+const CONFIRMATION_CODE_LENGTH = 13
+
 type NewBooking struct {
+	Id         pgtype.UUID // Invalid lets the database generate the id
 	CheckIn    pgtype.Date
 	CheckOut   pgtype.Date
 	Total      int
 	RoomTypeId pgtype.UUID
-	UserId     pgtype.UUID
-	RoomId     pgtype.UUID
+	HotelId    pgtype.UUID
+	UserId     pgtype.UUID // Invalid for reservations made on a channel
+	RoomId     pgtype.UUID // Invalid RoomId means an overbooked booking without assigned room
+	// Requested room type when the guest got a complimentary upgrade, Invalid means not upgraded
+	UpgradedFromRoomTypeId pgtype.UUID
+	Source                 string      // Empty is a direct booking
+	CompanyId              pgtype.UUID // Invalid when the guest pays
+	Guest                  BookingGuest
+	// Rooms of the room type that may be oversold per night, only checked when RoomId is Invalid
+	OverbookingAllowance int
+}
+
+// 13 characters of the gift card alphabet from a crypto random source, 5 bits each (65 bits)
+func newConfirmationCode() (string, error) {
+
+	random := make([]byte, CONFIRMATION_CODE_LENGTH)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, 0, CONFIRMATION_CODE_LENGTH)
+	for _, b := range random {
+		code = append(code, GIFT_CARD_CODE_ALPHABET[int(b)%len(GIFT_CARD_CODE_ALPHABET)])
+	}
+
+	return string(code), nil
 }
 
-func (bs *BookingService) CreateBookings(ctx context.Context, newBookingParams []NewBooking) error {
+// Create all bookings in one transaction, return their ids
+func (bs *BookingService) CreateBookings(ctx context.Context, newBookingParams []NewBooking) ([]pgtype.UUID, error) {
 
-	stmt := `INSERT INTO bookings (check_in, check_out, total, room_type_id, user_id, room_id) VALUES `
+	stmt := `INSERT INTO bookings (id, check_in, check_out, total, room_type_id, hotel_id, user_id, room_id, upgraded_from_room_type_id, source, company_id, guest_name, guest_email, guest_phone, estimated_arrival_time, confirmation_code) VALUES `
 
 	if len(newBookingParams) == 0 {
 		zap.S().Infoln("No New Booking to create")
-		return common_error.ErrBadRequest
+		return nil, common_error.ErrBadRequest
 	}
 
 	args := []any{}
@@ -97,46 +168,483 @@ func (bs *BookingService) CreateBookings(ctx context.Context, newBookingParams [
 
 	for index, param := range newBookingParams {
 
-		n := index * 6
-		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6))
+		if err := validateSpecialRequests(param.Guest.SpecialRequests); err != nil {
+			return nil, err
+		}
 
-		args = append(args, param.CheckIn, param.CheckOut, param.Total, param.RoomTypeId, param.UserId, param.RoomId)
+		source := param.Source
+		if source == "" {
+			source = booking_domain.DIRECT_BOOKING_SOURCE
+		}
+
+		confirmationCode, err := newConfirmationCode()
+		if err != nil {
+			zap.S().Errorln("Failed to generate confirmation code: ", err)
+			return nil, err
+		}
+
+		n := index * 16
+		placeholders = append(placeholders, fmt.Sprintf("(COALESCE($%d::uuid, gen_random_uuid()), $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11, n+12, n+13, n+14, n+15, n+16))
+
+		args = append(args, param.Id, param.CheckIn, param.CheckOut, param.Total, param.RoomTypeId, param.HotelId, param.UserId, param.RoomId, param.UpgradedFromRoomTypeId, source, param.CompanyId,
+			param.Guest.Name, param.Guest.Email, param.Guest.Phone, param.Guest.EstimatedArrivalTime, confirmationCode)
 	}
 
 	stmt += strings.Join(placeholders, ", ")
-	stmt += ";"
+	stmt += " RETURNING id, check_in, check_out, total, hotel_id, room_type_id, user_id, room_id, upgraded_from_room_type_id, source, company_id, guest_name, guest_email, guest_phone, estimated_arrival_time;"
 
 	zap.S().Info("Create Booking with statment: ", stmt)
 
-	_, err := bs.conn.Exec(ctx, stmt, args...)
+	tx, err := bs.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin create bookings transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setBookingAuditContext(ctx, tx, ""); err != nil {
+		return nil, err
+	}
+
+	qtx := bs.repo.WithTx(tx)
+	if err := checkOverbookingAllowance(ctx, qtx, newBookingParams); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, stmt, args...)
 	if err != nil {
+		if isRoomOverlapError(err) {
+			zap.S().Infoln("Room was booked by another booking")
+			return nil, ErrNoRoomsAvailable
+		}
 		zap.S().Errorln("Cannot create bookings: ", err)
-		return err
+		return nil, err
+	}
+	defer rows.Close()
+
+	var createdBookings []booking_repo.Booking
+	for rows.Next() {
+		var b booking_repo.Booking
+		if err := rows.Scan(&b.ID, &b.CheckIn, &b.CheckOut, &b.Total, &b.HotelID, &b.RoomTypeID, &b.UserID, &b.RoomID, &b.UpgradedFromRoomTypeID, &b.Source, &b.CompanyID,
+			&b.GuestName, &b.GuestEmail, &b.GuestPhone, &b.EstimatedArrivalTime); err != nil {
+			zap.S().Errorln("Cannot scan created booking: ", err)
+			return nil, err
+		}
+
+		createdBookings = append(createdBookings, b)
+	}
+	if err := rows.Err(); err != nil {
+		if isRoomOverlapError(err) {
+			zap.S().Infoln("Room was booked by another booking")
+			return nil, ErrNoRoomsAvailable
+		}
+		zap.S().Errorln("Cannot create bookings: ", err)
+		return nil, err
+	}
+	rows.Close()
+
+	// Rows of a multi row INSERT are returned in the order of its VALUES
+	ids := make([]pgtype.UUID, 0, len(createdBookings))
+	events := make([]booking_domain.BookingCreatedEvent, 0, len(createdBookings))
+	for index, b := range createdBookings {
+
+		specialRequests := make([]booking_domain.SpecialRequest, 0, len(newBookingParams[index].Guest.SpecialRequests))
+		for _, specialRequest := range newBookingParams[index].Guest.SpecialRequests {
+			if err := qtx.CreateSpecialRequest(ctx, booking_repo.CreateSpecialRequestParams{
+				BookingID: b.ID,
+				HotelID:   b.HotelID,
+				Type:      specialRequest.Type,
+				Note:      specialRequest.Note,
+			}); err != nil {
+				zap.S().Errorln("Cannot create special request: ", err)
+				return nil, err
+			}
+
+			specialRequests = append(specialRequests, booking_domain.SpecialRequest{
+				Type: string(specialRequest.Type),
+				Note: specialRequest.Note,
+			})
+		}
+
+		ids = append(ids, b.ID)
+		events = append(events, booking_domain.BookingCreatedEvent{
+			BookingId:              b.ID.String(),
+			UserId:                 b.UserID.String(),
+			HotelId:                b.HotelID.String(),
+			RoomTypeId:             b.RoomTypeID.String(),
+			RoomId:                 b.RoomID.String(),
+			CheckIn:                b.CheckIn.Time,
+			CheckOut:               b.CheckOut.Time,
+			Total:                  int(b.Total),
+			IsUpgraded:             b.UpgradedFromRoomTypeID.Valid,
+			UpgradedFromRoomTypeId: b.UpgradedFromRoomTypeID.String(),
+			Source:                 b.Source,
+			CompanyId:              b.CompanyID.String(),
+			GuestName:              b.GuestName.String,
+			GuestEmail:             b.GuestEmail.String,
+			GuestPhone:             b.GuestPhone.String,
+			EstimatedArrivalTime:   booking_repo_mapping.FromPgTimeToClock(b.EstimatedArrivalTime),
+			SpecialRequests:        specialRequests,
+		})
 	}
 
-	return nil
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit created bookings: ", err)
+		return nil, err
+	}
+
+	// Notify other services after bookings are saved
+	if bs.producer != nil {
+		policies := bs.getHotelPolicies(ctx, events)
+		for _, event := range events {
+			event.HotelPolicy = policies[event.HotelId]
+			bs.producer.PublishBookingCreated(ctx, event)
+		}
+	}
+
+	return ids, nil
+}
+
+// Policies of the booked hotels for confirmation emails, keyed by hotel id.
+// A hotel without policy or whose policy cannot be loaded is left out, the booking is confirmed anyway
+func (bs *BookingService) getHotelPolicies(ctx context.Context, events []booking_domain.BookingCreatedEvent) map[string]*booking_domain.HotelPolicy {
+
+	policies := make(map[string]*booking_domain.HotelPolicy)
+	if bs.hotelClient == nil {
+		return policies
+	}
+
+	for _, event := range events {
+		if _, ok := policies[event.HotelId]; ok {
+			continue
+		}
+
+		result, err := bs.hotelClient.GetHotelPolicy(ctx, &hotel_pb.GetHotelPolicyRequest{
+			HotelId: event.HotelId,
+		})
+		if err != nil {
+			zap.S().Infoln("Cannot get Hotel Policy for booking confirmation: ", err)
+			policies[event.HotelId] = nil
+			continue
+		}
+
+		policies[event.HotelId] = toHotelPolicyDomain(result.GetPolicy())
+	}
+
+	return policies
+}
+
+func toHotelPolicyDomain(policy *hotel_pb.HotelPolicy) *booking_domain.HotelPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	var childrenFreeMaxAge *int
+	if policy.ChildrenFreeMaxAge != nil {
+		age := int(policy.GetChildrenFreeMaxAge())
+		childrenFreeMaxAge = &age
+	}
+
+	return &booking_domain.HotelPolicy{
+		CheckInFrom:        policy.GetCheckInFrom(),
+		CheckInUntil:       policy.GetCheckInUntil(),
+		CheckOutUntil:      policy.GetCheckOutUntil(),
+		PetsAllowed:        policy.GetPetsAllowed(),
+		PetFee:             int(policy.GetPetFee()),
+		ChildrenAllowed:    policy.GetChildrenAllowed(),
+		ChildrenFreeMaxAge: childrenFreeMaxAge,
+		ExtraBedAvailable:  policy.GetExtraBedAvailable(),
+		ExtraBedFee:        int(policy.GetExtraBedFee()),
+		PaymentMethods:     policy.GetPaymentMethods(),
+		HouseRules:         policy.GetHouseRules(),
+	}
 }
 
-func (bs *BookingService) DeleteBookingById(ctx context.Context, id pgtype.UUID) error {
+// Cancel a booking, it is soft deleted and the reason is kept in its history
+var ErrBookingAccessDenied = errors.New("not allowed to access booking")
+
+// Caller of an operation on bookings, checked against the owner and the hotel of each booking
+type BookingRequester struct {
+	UserId  pgtype.UUID
+	IsAdmin bool
+	// Hotels managed by the caller
+	HotelIds []pgtype.UUID
+	// Booking unlocked by a manage booking token, for guests without an account
+	ManageBookingId pgtype.UUID
+}
+
+// The booking user, a manager of its hotel, an admin or the holder of its manage booking token
+func (br *BookingRequester) canAccess(bookingId pgtype.UUID, userId pgtype.UUID, hotelId pgtype.UUID) bool {
+	if br.IsAdmin {
+		return true
+	}
+
+	if br.UserId.Valid && br.UserId == userId {
+		return true
+	}
+
+	if br.ManageBookingId.Valid && br.ManageBookingId == bookingId {
+		return true
+	}
+
+	return slices.Contains(br.HotelIds, hotelId)
+}
+
+func (bs *BookingService) DeleteBookingById(ctx context.Context, id pgtype.UUID, reason string, requester *BookingRequester) error {
+
+	return bs.DeleteBookingsByIds(ctx, []pgtype.UUID{id}, reason, requester)
+}
+
+// Cancel bookings, a nil requester is an internal call (hotel removal, channel cancellation) that skips the access check
+func (bs *BookingService) DeleteBookingsByIds(ctx context.Context, ids []pgtype.UUID, reason string, requester *BookingRequester) error {
+
+	tx, err := bs.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin delete bookings transaction: ", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setBookingAuditContext(ctx, tx, reason); err != nil {
+		return err
+	}
+
+	qtx := bs.repo.WithTx(tx)
+
+	lockedBookings, err := qtx.LockBookingsByIds(ctx, ids)
+	if err != nil {
+		zap.S().Errorln("Cannot lock Bookings by ids: ", err)
+		return err
+	}
+
+	if len(lockedBookings) == 0 {
+		zap.S().Infoln("No Booking to delete")
+		return common_error.ErrNoRows
+	}
+
+	if requester != nil {
+		for _, booking := range lockedBookings {
+			if !requester.canAccess(booking.ID, booking.UserID, booking.HotelID) {
+				zap.S().Infoln("Requester cannot cancel Booking: ", booking.ID.String())
+				return ErrBookingAccessDenied
+			}
+		}
+	}
+
+	cancellableIds, err := cancellableBookingIds(lockedBookings, requester == nil)
+	if err != nil {
+		return err
+	}
+
+	if len(cancellableIds) == 0 {
+		zap.S().Infoln("No Booking to delete")
+		return common_error.ErrNoRows
+	}
+
+	// Credit held by company billed bookings is released once they are cancelled
+	billedTotals, err := qtx.GetCompanyBilledTotals(ctx, cancellableIds)
+	if err != nil {
+		zap.S().Errorln("Cannot get Company billed totals: ", err)
+		return err
+	}
+
+	deleted, err := qtx.DeleteBookingsByIds(ctx, cancellableIds)
+	if err != nil {
+		zap.S().Errorln("Cannot delete Bookings By Ids: ", err)
+		return err
+	}
+
+	if deleted == 0 {
+		zap.S().Infoln("No Booking to delete")
+		return common_error.ErrNoRows
+	}
+
+	if err := refundBookingPayments(ctx, qtx, cancellableIds); err != nil {
+		return err
+	}
 
-	if err := bs.repo.DeleteBookingById(ctx, id); err != nil {
-		zap.S().Errorln("Cannot delete Booking")
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit deleted bookings: ", err)
 		return err
 	}
 
+	bs.releaseCompanyCredit(ctx, billedTotals)
+
+	// Points spent by members on the cancelled bookings
+	var memberBookingIds []pgtype.UUID
+	for _, booking := range lockedBookings {
+		if booking.UserID.Valid && slices.Contains(cancellableIds, booking.ID) {
+			memberBookingIds = append(memberBookingIds, booking.ID)
+		}
+	}
+	bs.cancelRedemptions(ctx, memberBookingIds)
+
 	return nil
 }
 
-func (bs *BookingService) DeleteBookingsByIds(ctx context.Context, ids []pgtype.UUID) error {
+// Only stays not started yet are cancelled, guests in house (paid ones included), checked out and
+// no show stays are not cancelled nor refunded. Internal calls skip them and requesters are told
+func cancellableBookingIds(bookings []booking_repo.LockBookingsByIdsRow, skipOthers bool) ([]pgtype.UUID, error) {
+	var cancellableIds []pgtype.UUID
+	for _, booking := range bookings {
+		if booking.Status == booking_repo.BookingStatusBOOKED {
+			cancellableIds = append(cancellableIds, booking.ID)
+			continue
+		}
+
+		if !skipOthers {
+			zap.S().Infoln("Booking cannot be cancelled in status: ", booking.Status)
+			return nil, ErrBookingNotCancellable
+		}
+	}
+
+	return cancellableIds, nil
+}
+
+// Cancel the upcoming bookings of a hotel that is being removed, a hotel without them is not an error
+func (bs *BookingService) DeleteBookingsByHotelId(ctx context.Context, hotelId pgtype.UUID, reason string) error {
 
-	if err := bs.repo.DeleteBookingsByIds(ctx, ids); err != nil {
-		zap.S().Errorln("Cannot delete Bookings By Ids")
+	ids, err := bs.repo.GetBookingIdsByHotelId(ctx, hotelId)
+	if err != nil {
+		zap.S().Errorln("Cannot get Booking ids by Hotel id: ", err)
+		return err
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// Bookings deleted in the meantime leave nothing to do
+	if err := bs.DeleteBookingsByIds(ctx, ids, reason, nil); err != nil && !errors.Is(err, common_error.ErrNoRows) {
 		return err
 	}
 
 	return nil
 }
 
+var ErrBookingFinished = errors.New("booking is checked out or no show")
+var ErrBookingNotCancellable = errors.New("booking is in house or finished")
+
+// Lock a booking that is not checked out or no show, a finished booking is told apart from a missing one
+func lockActiveBooking(ctx context.Context, qtx *booking_repo.Queries, id pgtype.UUID) (booking_repo.Booking, error) {
+
+	booking, err := qtx.LockBookingById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := qtx.GetBookingById(ctx, id); err == nil {
+			zap.S().Infoln("Booking is finished: ", id.String())
+			return booking, ErrBookingFinished
+		}
+
+		zap.S().Infoln("Booking not found: ", id.String())
+		return booking, common_error.ErrNoRows
+	}
+	if err != nil {
+		zap.S().Errorln("Cannot lock Booking by id: ", err)
+		return booking, err
+	}
+
+	return booking, nil
+}
+
+// Move the stay of a booking to new dates in the same room, total is scaled to the new number of nights.
+// Cancelled, checked out and no show bookings cannot be changed
+func (bs *BookingService) ChangeBookingDates(ctx context.Context, id pgtype.UUID, checkIn pgtype.Date, checkOut pgtype.Date, reason string) (*booking_domain.Booking, error) {
+
+	if !checkIn.Time.Before(checkOut.Time) {
+		zap.S().Infoln("Check In date must be before Check Out date")
+		return nil, common_error.ErrBadRequest
+	}
+
+	tx, err := bs.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin change booking dates transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setBookingAuditContext(ctx, tx, reason); err != nil {
+		return nil, err
+	}
+
+	qtx := bs.repo.WithTx(tx)
+
+	// Locked so concurrent changes, payments and cancellations see the new dates
+	booking, err := lockActiveBooking(ctx, qtx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Overbooked bookings hold no room, their new dates would have to fit the overbooking allowance again
+	if !booking.RoomID.Valid {
+		zap.S().Infoln("Cannot change dates of a booking without an assigned room")
+		return nil, ErrNoRoomsAvailable
+	}
+
+	conflicts, err := qtx.CountRoomConflicts(ctx, booking_repo.CountRoomConflictsParams{
+		RoomID:    booking.RoomID,
+		BookingID: booking.ID,
+		CheckIn:   checkIn,
+		CheckOut:  checkOut,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to count Room conflicts: ", err)
+		return nil, err
+	}
+
+	if conflicts > 0 {
+		zap.S().Infoln("Room is not AVAILABLE on the new dates")
+		return nil, ErrNoRoomsAvailable
+	}
+
+	nights := int(booking.CheckOut.Time.Sub(booking.CheckIn.Time).Hours() / 24)
+	newNights := int(checkOut.Time.Sub(checkIn.Time).Hours() / 24)
+
+	total := booking.Total
+	if nights > 0 {
+		total = int32(int(booking.Total) * newNights / nights)
+	}
+
+	if _, err := qtx.UpdateBookingDates(ctx, booking_repo.UpdateBookingDatesParams{
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+		Total:    total,
+		ID:       booking.ID,
+	}); err != nil {
+		if isRoomOverlapError(err) {
+			zap.S().Infoln("Room was booked by another booking")
+			return nil, ErrNoRoomsAvailable
+		}
+		zap.S().Errorln("Cannot update Booking dates: ", err)
+		return nil, err
+	}
+
+	// Company is charged for the extra nights or credited for the removed ones
+	var charged int
+	if booking.CompanyID.Valid {
+		charged = int(total - booking.Total)
+		if err := bs.chargeCompanyCredit(ctx, booking.CompanyID, charged); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit changed booking dates: ", err)
+		if charged != 0 {
+			if err := bs.chargeCompanyCredit(ctx, booking.CompanyID, -charged); err != nil {
+				zap.S().Errorln("Failed to revert Company credit of changed booking dates: ", err)
+			}
+		}
+		return nil, err
+	}
+
+	booking.CheckIn = checkIn
+	booking.CheckOut = checkOut
+	booking.Total = total
+
+	result := booking_repo_mapping.FromBookingRepoToBookingDomain(booking)
+	return &result, nil
+}
+
 // Return number of Occupied rooms for each Room Type in a range of time
 func (bs *BookingService) GetNumberOfOccupiedRooms(ctx context.Context, roomTypeIds []pgtype.UUID, checkIn pgtype.Date, checkOut pgtype.Date) ([]booking_repo.GetNumberOfOccupiedRoomsRow, error) {
 	result, err := bs.repo.GetNumberOfOccupiedRooms(ctx, booking_repo.GetNumberOfOccupiedRoomsParams{
@@ -182,3 +690,103 @@ func (bs *BookingService) GetUnavailableRoomsByRoomTypeId(ctx context.Context, r
 
 	return roomIds, nil
 }
+
+// Check the overbooking allowance again inside the transaction of the new bookings,
+// the room type stays locked until commit so concurrent bookings cannot both take the last oversold room
+func checkOverbookingAllowance(ctx context.Context, qtx *booking_repo.Queries, newBookings []NewBooking) error {
+
+	stays := map[string]NewBooking{}
+	numberOfUnassignedBookings := map[string]int{}
+	for _, newBooking := range newBookings {
+		if newBooking.RoomId.Valid {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%s", newBooking.RoomTypeId.String(), newBooking.CheckIn.Time.Format("2006-01-02"), newBooking.CheckOut.Time.Format("2006-01-02"))
+		if _, ok := stays[key]; !ok {
+			stays[key] = newBooking
+		}
+		numberOfUnassignedBookings[key]++
+	}
+
+	// Keys start with the room type id, locking in sorted order avoids deadlocks
+	keys := make([]string, 0, len(stays))
+	for key := range stays {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		stay := stays[key]
+
+		if err := qtx.LockRoomTypeOverbooking(ctx, stay.RoomTypeId); err != nil {
+			zap.S().Errorln("Failed to lock Room Type for overbooking: ", err)
+			return err
+		}
+
+		maxUnassignedBookings, err := qtx.GetMaxUnassignedBookingsPerNight(ctx, booking_repo.GetMaxUnassignedBookingsPerNightParams{
+			CheckIn:    stay.CheckIn,
+			CheckOut:   stay.CheckOut,
+			RoomTypeID: stay.RoomTypeId,
+		})
+		if err != nil {
+			zap.S().Errorln("Failed to get max unassigned bookings per night: ", err)
+			return err
+		}
+
+		if int(maxUnassignedBookings)+numberOfUnassignedBookings[key] > stay.OverbookingAllowance {
+			zap.S().Infoln("Overbooking allowance is exceeded by concurrent bookings")
+			return ErrNoRoomsAvailable
+		}
+	}
+
+	return nil
+}
+
+// Return the highest number of unassigned (overbooked) bookings on any night in range of time
+func (bs *BookingService) GetMaxUnassignedBookingsPerNight(ctx context.Context, roomTypeId pgtype.UUID, checkIn pgtype.Date, checkOut pgtype.Date) (int, error) {
+
+	result, err := bs.repo.GetMaxUnassignedBookingsPerNight(ctx, booking_repo.GetMaxUnassignedBookingsPerNightParams{
+		CheckIn:    checkIn,
+		CheckOut:   checkOut,
+		RoomTypeID: roomTypeId,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get max unassigned bookings per night: ", err)
+		return 0, err
+	}
+
+	return int(result), nil
+}
+
+// Return nights of a hotel that still have bookings without assigned room
+func (bs *BookingService) GetOversoldNights(ctx context.Context, hotelId pgtype.UUID, startDate pgtype.Date, endDate pgtype.Date) ([]booking_repo.GetOversoldNightsRow, error) {
+
+	result, err := bs.repo.GetOversoldNights(ctx, booking_repo.GetOversoldNightsParams{
+		StartDate: startDate,
+		EndDate:   endDate,
+		HotelID:   hotelId,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get oversold nights: ", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Return rooms that have a booking right before or right after the new stay
+func (bs *BookingService) GetAdjacentBookedRoomsByRoomTypeId(ctx context.Context, roomTypeId pgtype.UUID, checkIn pgtype.Date, checkOut pgtype.Date) ([]booking_repo.GetAdjacentBookedRoomsByRoomTypeIdRow, error) {
+
+	result, err := bs.repo.GetAdjacentBookedRoomsByRoomTypeId(ctx, booking_repo.GetAdjacentBookedRoomsByRoomTypeIdParams{
+		CheckIn:    checkIn,
+		CheckOut:   checkOut,
+		RoomTypeID: roomTypeId,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get adjacent booked rooms by Room Type Id: ", err)
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package booking_service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestBookingRequesterCanAccess(t *testing.T) {
+	uuid := func(b byte) pgtype.UUID {
+		return pgtype.UUID{Bytes: [16]byte{b}, Valid: true}
+	}
+
+	bookingId, otherBookingId := uuid(1), uuid(2)
+	guestId, otherUserId := uuid(11), uuid(12)
+	hotelId, otherHotelId := uuid(21), uuid(22)
+
+	tests := []struct {
+		name      string
+		requester BookingRequester
+		userId    pgtype.UUID
+		want      bool
+	}{
+		{"admin", BookingRequester{IsAdmin: true}, guestId, true},
+		{"booking user", BookingRequester{UserId: guestId}, guestId, true},
+		{"another user", BookingRequester{UserId: otherUserId}, guestId, false},
+		{"manager of the hotel", BookingRequester{UserId: otherUserId, HotelIds: []pgtype.UUID{otherHotelId, hotelId}}, guestId, true},
+		{"manager of another hotel", BookingRequester{UserId: otherUserId, HotelIds: []pgtype.UUID{otherHotelId}}, guestId, false},
+		{"manage booking token of the booking", BookingRequester{ManageBookingId: bookingId}, pgtype.UUID{}, true},
+		{"manage booking token of another booking", BookingRequester{ManageBookingId: otherBookingId}, pgtype.UUID{}, false},
+		// A booking made on a channel has no user, an anonymous requester must not match it
+		{"anonymous requester on a booking without user", BookingRequester{}, pgtype.UUID{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.requester.canAccess(bookingId, tt.userId, hotelId); got != tt.want {
+				t.Errorf("canAccess() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancellableBookingIds(t *testing.T) {
+	booking := func(b byte, bookingStatus booking_repo.BookingStatus) booking_repo.LockBookingsByIdsRow {
+		return booking_repo.LockBookingsByIdsRow{ID: pgtype.UUID{Bytes: [16]byte{b}, Valid: true}, Status: bookingStatus}
+	}
+
+	upcoming := booking(1, booking_repo.BookingStatusBOOKED)
+	checkedInPaid := booking(2, booking_repo.BookingStatusPAID)
+	inHouse := booking(3, booking_repo.BookingStatusCHECKIN)
+	checkedOut := booking(4, booking_repo.BookingStatusCHECKOUT)
+	noShow := booking(5, booking_repo.BookingStatusNOSHOW)
+
+	tests := []struct {
+		name       string
+		bookings   []booking_repo.LockBookingsByIdsRow
+		skipOthers bool
+		want       []pgtype.UUID
+		wantErr    error
+	}{
+		{"upcoming stay", []booking_repo.LockBookingsByIdsRow{upcoming}, false, []pgtype.UUID{upcoming.ID}, nil},
+		// Paid once checked in, the guest is in house and is not refunded
+		{"checked in and paid", []booking_repo.LockBookingsByIdsRow{checkedInPaid}, false, nil, ErrBookingNotCancellable},
+		{"in house", []booking_repo.LockBookingsByIdsRow{upcoming, inHouse}, false, nil, ErrBookingNotCancellable},
+		{"checked out", []booking_repo.LockBookingsByIdsRow{checkedOut}, false, nil, ErrBookingNotCancellable},
+		{"no show", []booking_repo.LockBookingsByIdsRow{noShow}, false, nil, ErrBookingNotCancellable},
+		{"internal call skips stays that started", []booking_repo.LockBookingsByIdsRow{upcoming, checkedInPaid, inHouse, checkedOut, noShow}, true, []pgtype.UUID{upcoming.ID}, nil},
+		{"internal call with nothing to cancel", []booking_repo.LockBookingsByIdsRow{checkedInPaid}, true, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cancellableBookingIds(tt.bookings, tt.skipOthers)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("cancellableBookingIds() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("cancellableBookingIds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
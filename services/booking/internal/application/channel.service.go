@@ -0,0 +1,383 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+// Nights from today pushed to channels
+const CHANNEL_AVAILABILITY_DAYS = 90
+
+type SetChannelAllotmentParams struct {
+	Channel    string
+	HotelId    pgtype.UUID
+	RoomTypeId pgtype.UUID
+	Allotment  int
+}
+
+// Set the rooms of a room type a channel may sell per night
+func (bs *BookingService) SetChannelAllotment(ctx context.Context, params *SetChannelAllotmentParams) (*booking_repo.ChannelAllotment, error) {
+
+	channel := strings.ToUpper(strings.TrimSpace(params.Channel))
+	if channel == "" || channel == booking_domain.DIRECT_BOOKING_SOURCE {
+		zap.S().Infoln("Invalid channel: ", params.Channel)
+		return nil, common_error.ErrBadRequest
+	}
+
+	if params.Allotment < 0 {
+		zap.S().Infoln("Allotment must not be negative")
+		return nil, common_error.ErrBadRequest
+	}
+
+	allotment, err := bs.repo.UpsertChannelAllotment(ctx, booking_repo.UpsertChannelAllotmentParams{
+		Channel:    channel,
+		HotelID:    params.HotelId,
+		RoomTypeID: params.RoomTypeId,
+		Allotment:  int32(params.Allotment),
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to set channel allotment: ", err)
+		return nil, err
+	}
+
+	return &allotment, nil
+}
+
+func (bs *BookingService) GetChannelAllotmentsByHotelId(ctx context.Context, hotelId pgtype.UUID) ([]booking_repo.ChannelAllotment, error) {
+
+	allotments, err := bs.repo.GetChannelAllotmentsByHotelId(ctx, hotelId)
+	if err != nil {
+		zap.S().Errorln("Failed to get channel allotments: ", err)
+		return nil, err
+	}
+
+	return allotments, nil
+}
+
+func (bs *BookingService) DeleteChannelAllotmentById(ctx context.Context, id pgtype.UUID, hotelId pgtype.UUID) error {
+
+	deleted, err := bs.repo.DeleteChannelAllotmentById(ctx, booking_repo.DeleteChannelAllotmentByIdParams{
+		ID:      id,
+		HotelID: hotelId,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to delete channel allotment: ", err)
+		return err
+	}
+
+	if deleted == 0 {
+		return common_error.ErrNoRows
+	}
+
+	return nil
+}
+
+// A channel can not sell a room type without allotment, nor more rooms than its allotment on any night
+func (bs *BookingService) checkChannelAllotment(ctx context.Context, channel string, room RoomRequest, checkIn pgtype.Date, checkOut pgtype.Date) error {
+
+	allotment, err := bs.repo.GetChannelAllotment(ctx, booking_repo.GetChannelAllotmentParams{
+		Channel:    channel,
+		RoomTypeID: room.RoomTypeId,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("No allotment of Room Type on channel: ", channel)
+		return ErrNoRoomsAvailable
+	}
+	if err != nil {
+		zap.S().Errorln("Failed to get channel allotment: ", err)
+		return err
+	}
+
+	soldNights, err := bs.repo.GetChannelRoomsSoldPerNight(ctx, booking_repo.GetChannelRoomsSoldPerNightParams{
+		StartDate:   checkIn,
+		EndDate:     checkOut,
+		Source:      channel,
+		RoomTypeIds: []pgtype.UUID{room.RoomTypeId},
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get rooms sold by channel: ", err)
+		return err
+	}
+
+	if room.NumberOfRooms > channelAllotmentLeft(int(allotment.Allotment), soldNights) {
+		zap.S().Infoln("Allotment of channel is exceeded: ", channel)
+		return ErrNoRoomsAvailable
+	}
+
+	return nil
+}
+
+// Rooms the channel can still sell on every night of a stay, the night it sold the most decides
+func channelAllotmentLeft(allotment int, soldNights []booking_repo.GetChannelRoomsSoldPerNightRow) int {
+
+	maxSold := 0
+	for _, night := range soldNights {
+		maxSold = max(maxSold, int(night.NumberOfRoomsSold))
+	}
+
+	return allotment - maxSold
+}
+
+// Pull reservations of every channel then push availability and rates every interval
+func (bs *BookingService) StartChannelSync(ctx context.Context, interval time.Duration, channels []booking_domain.Channel) {
+
+	if len(channels) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, channel := range channels {
+				bs.syncChannel(ctx, channel)
+			}
+		}
+	}
+}
+
+func (bs *BookingService) syncChannel(ctx context.Context, channel booking_domain.Channel) {
+
+	reservations, err := channel.PullReservations(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to pull reservations of channel: ", channel.Name(), err)
+	}
+
+	for _, reservation := range reservations {
+		if err := bs.processChannelReservation(ctx, channel.Name(), reservation); err != nil {
+			zap.S().Errorln("Failed to process reservation of channel: ", channel.Name(), reservation.ExternalId, err)
+		}
+	}
+
+	// Pushed after the pull so new reservations are not sold again
+	if err := bs.pushChannelInventory(ctx, channel); err != nil {
+		zap.S().Errorln("Failed to push inventory to channel: ", channel.Name(), err)
+	}
+}
+
+// Book a new reservation like a direct booking or cancel its bookings, a reservation is only booked once
+func (bs *BookingService) processChannelReservation(ctx context.Context, channel string, reservation booking_domain.ChannelReservation) error {
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(utils.ACTOR_METADATA_KEY, "channel:"+channel))
+
+	existing, err := bs.repo.GetChannelReservation(ctx, booking_repo.GetChannelReservationParams{
+		Channel:    channel,
+		ExternalID: reservation.ExternalId,
+	})
+	if err == nil {
+		if !reservation.Cancelled || existing.Status != booking_repo.ChannelReservationStatusACCEPTED {
+			return nil
+		}
+
+		err := bs.DeleteBookingsByIds(ctx, existing.BookingIds, "cancelled on channel "+channel, nil)
+		if err != nil && !errors.Is(err, common_error.ErrNoRows) {
+			return err
+		}
+
+		return bs.repo.SetChannelReservationStatus(ctx, booking_repo.SetChannelReservationStatusParams{
+			Status: booking_repo.ChannelReservationStatusCANCELLED,
+			ID:     existing.ID,
+		})
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	// Cancelled before it was ever pulled
+	if reservation.Cancelled {
+		return nil
+	}
+
+	var hotelId, roomTypeId pgtype.UUID
+	if err := hotelId.Scan(reservation.HotelId); err != nil {
+		return err
+	}
+	if err := roomTypeId.Scan(reservation.RoomTypeId); err != nil {
+		return err
+	}
+
+	// Channels send one guest name for the whole reservation
+	var guests []BookingGuest
+	if reservation.GuestName != "" {
+		for range reservation.NumberOfRooms {
+			guests = append(guests, BookingGuest{
+				Name: pgtype.Text{String: reservation.GuestName, Valid: true},
+			})
+		}
+	}
+
+	status := booking_repo.ChannelReservationStatusACCEPTED
+	rejectReason := pgtype.Text{}
+
+	bookingIds, err := bs.BookRooms(ctx, &BookRoomsParams{
+		CheckIn:  pgtype.Date{Time: reservation.CheckIn, Valid: true},
+		CheckOut: pgtype.Date{Time: reservation.CheckOut, Valid: true},
+		Total:    reservation.Total,
+		Rooms: []RoomRequest{
+			{RoomTypeId: roomTypeId, NumberOfRooms: reservation.NumberOfRooms, Guests: guests},
+		},
+		Source: channel,
+	})
+	if err != nil {
+		// Other errors are retried on the next pull
+		if !errors.Is(err, ErrNoRoomsAvailable) && !errors.Is(err, ErrHotelNotPublished) && !errors.Is(err, common_error.ErrBadRequest) {
+			return err
+		}
+
+		zap.S().Infoln("Rejected reservation of channel: ", channel, reservation.ExternalId, err)
+		status = booking_repo.ChannelReservationStatusREJECTED
+		rejectReason = pgtype.Text{String: err.Error(), Valid: true}
+	}
+
+	if bookingIds == nil {
+		bookingIds = []pgtype.UUID{}
+	}
+
+	return bs.repo.CreateChannelReservation(ctx, booking_repo.CreateChannelReservationParams{
+		Channel:    channel,
+		ExternalID: reservation.ExternalId,
+		HotelID:    hotelId,
+		Status:     status,
+		BookingIds: bookingIds,
+		Error:      rejectReason,
+	})
+}
+
+type channelNightKey struct {
+	roomTypeId string
+	night      time.Time
+}
+
+// Push what the channel can still sell: free rooms capped by what is left of its allotment
+func (bs *BookingService) pushChannelInventory(ctx context.Context, channel booking_domain.Channel) error {
+
+	allotments, err := bs.repo.GetChannelAllotmentsByChannel(ctx, channel.Name())
+	if err != nil {
+		return err
+	}
+
+	if len(allotments) == 0 {
+		return nil
+	}
+
+	hotelIds := []string{}
+	roomTypeIds := make([]pgtype.UUID, 0, len(allotments))
+	for _, allotment := range allotments {
+		if hotelId := allotment.HotelID.String(); len(hotelIds) == 0 || hotelIds[len(hotelIds)-1] != hotelId {
+			hotelIds = append(hotelIds, hotelId)
+		}
+		roomTypeIds = append(roomTypeIds, allotment.RoomTypeID)
+	}
+
+	roomCountsResult, err := bs.roomClient.GetNumberOfRoomsPerRoomTypeByHotelIds(ctx, &room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsRequest{
+		HotelIds: hotelIds,
+	})
+	if err != nil {
+		return err
+	}
+
+	roomCounts := map[string]int{}
+	for _, row := range roomCountsResult.GetResults() {
+		roomCounts[row.GetRoomTypeId()] = int(row.GetNumberOfRooms())
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	startDate := pgtype.Date{Time: today, Valid: true}
+	endDate := pgtype.Date{Time: today.AddDate(0, 0, CHANNEL_AVAILABILITY_DAYS), Valid: true}
+
+	occupiedRows, err := bs.repo.GetOccupiedRoomsPerNight(ctx, booking_repo.GetOccupiedRoomsPerNightParams{
+		StartDate:   startDate,
+		EndDate:     endDate,
+		RoomTypeIds: roomTypeIds,
+	})
+	if err != nil {
+		return err
+	}
+
+	soldRows, err := bs.repo.GetChannelRoomsSoldPerNight(ctx, booking_repo.GetChannelRoomsSoldPerNightParams{
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Source:      channel.Name(),
+		RoomTypeIds: roomTypeIds,
+	})
+	if err != nil {
+		return err
+	}
+
+	occupied := map[channelNightKey]int{}
+	for _, row := range occupiedRows {
+		occupied[channelNightKey{roomTypeId: row.RoomTypeID.String(), night: row.Night.Time}] = int(row.NumberOfOccupiedRooms)
+	}
+
+	sold := map[channelNightKey]int{}
+	for _, row := range soldRows {
+		sold[channelNightKey{roomTypeId: row.RoomTypeID.String(), night: row.Night.Time}] = int(row.NumberOfRoomsSold)
+	}
+
+	availabilities := channelAvailabilities(allotments, roomCounts, occupied, sold, startDate.Time, endDate.Time)
+
+	rates := make([]booking_domain.ChannelRate, 0, len(allotments))
+	for _, allotment := range allotments {
+		roomTypeId := allotment.RoomTypeID.String()
+
+		roomTypeResult, err := bs.roomTypeClient.GetRoomTypeById(ctx, &room_type_pb.GetRoomTypeByIdRequest{
+			Id: roomTypeId,
+		})
+		if err != nil {
+			return err
+		}
+
+		rates = append(rates, booking_domain.ChannelRate{
+			HotelId:    allotment.HotelID.String(),
+			RoomTypeId: roomTypeId,
+			Price:      int(roomTypeResult.GetRoomType().GetPrice()),
+		})
+	}
+
+	if err := channel.PushAvailability(ctx, availabilities); err != nil {
+		return err
+	}
+
+	return channel.PushRates(ctx, rates)
+}
+
+// What the channel can sell each night of [startDate, endDate): free rooms capped by what is left of its allotment, never negative
+func channelAvailabilities(allotments []booking_repo.ChannelAllotment, roomCounts map[string]int, occupied map[channelNightKey]int, sold map[channelNightKey]int, startDate time.Time, endDate time.Time) []booking_domain.ChannelAvailability {
+
+	availabilities := make([]booking_domain.ChannelAvailability, 0, len(allotments)*CHANNEL_AVAILABILITY_DAYS)
+	for _, allotment := range allotments {
+		roomTypeId := allotment.RoomTypeID.String()
+
+		for night := startDate; night.Before(endDate); night = night.AddDate(0, 0, 1) {
+			key := channelNightKey{roomTypeId: roomTypeId, night: night}
+
+			available := min(roomCounts[roomTypeId]-occupied[key], int(allotment.Allotment)-sold[key])
+
+			availabilities = append(availabilities, booking_domain.ChannelAvailability{
+				HotelId:    allotment.HotelID.String(),
+				RoomTypeId: roomTypeId,
+				Date:       night,
+				Available:  max(available, 0),
+			})
+		}
+	}
+
+	return availabilities
+}
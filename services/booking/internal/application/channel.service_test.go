@@ -0,0 +1,107 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestSetChannelAllotmentInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		params SetChannelAllotmentParams
+	}{
+		{"no channel", SetChannelAllotmentParams{Channel: "  ", Allotment: 2}},
+		{"direct bookings are not a channel", SetChannelAllotmentParams{Channel: "direct", Allotment: 2}},
+		{"negative allotment", SetChannelAllotmentParams{Channel: "BOOKING_COM", Allotment: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := &BookingService{}
+
+			if _, err := bs.SetChannelAllotment(context.Background(), &tt.params); !errors.Is(err, common_error.ErrBadRequest) {
+				t.Errorf("SetChannelAllotment() error = %v, want %v", err, common_error.ErrBadRequest)
+			}
+		})
+	}
+}
+
+func TestChannelAllotmentLeft(t *testing.T) {
+	night := func(day int, sold int32) booking_repo.GetChannelRoomsSoldPerNightRow {
+		return booking_repo.GetChannelRoomsSoldPerNightRow{
+			Night:             pgtype.Date{Time: time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC), Valid: true},
+			NumberOfRoomsSold: sold,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		allotment  int
+		soldNights []booking_repo.GetChannelRoomsSoldPerNightRow
+		want       int
+	}{
+		{"nothing sold", 5, nil, 5},
+		{"busiest night decides", 5, []booking_repo.GetChannelRoomsSoldPerNightRow{night(2, 1), night(3, 4), night(4, 2)}, 1},
+		{"allotment sold out", 3, []booking_repo.GetChannelRoomsSoldPerNightRow{night(2, 3)}, 0},
+		// The allotment was lowered below what the channel already sold
+		{"allotment lowered after sales", 2, []booking_repo.GetChannelRoomsSoldPerNightRow{night(2, 3)}, -1},
+		{"no allotment", 0, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := channelAllotmentLeft(tt.allotment, tt.soldNights); got != tt.want {
+				t.Errorf("channelAllotmentLeft() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChannelAvailabilities(t *testing.T) {
+	hotelId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	roomTypeA := pgtype.UUID{Bytes: [16]byte{11}, Valid: true}
+	roomTypeB := pgtype.UUID{Bytes: [16]byte{12}, Valid: true}
+	day := func(day int) time.Time {
+		return time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	allotments := []booking_repo.ChannelAllotment{
+		{HotelID: hotelId, RoomTypeID: roomTypeA, Allotment: 3},
+		{HotelID: hotelId, RoomTypeID: roomTypeB, Allotment: 10},
+	}
+	roomCounts := map[string]int{roomTypeA.String(): 10, roomTypeB.String(): 4}
+	occupied := map[channelNightKey]int{
+		{roomTypeId: roomTypeA.String(), night: day(3)}: 9,
+		{roomTypeId: roomTypeB.String(), night: day(2)}: 1,
+		// Overbooked night
+		{roomTypeId: roomTypeB.String(), night: day(3)}: 5,
+	}
+	sold := map[channelNightKey]int{
+		{roomTypeId: roomTypeA.String(), night: day(2)}: 2,
+	}
+
+	got := channelAvailabilities(allotments, roomCounts, occupied, sold, day(2), day(4))
+
+	want := []booking_domain.ChannelAvailability{
+		{HotelId: hotelId.String(), RoomTypeId: roomTypeA.String(), Date: day(2), Available: 1}, // rest of the allotment
+		{HotelId: hotelId.String(), RoomTypeId: roomTypeA.String(), Date: day(3), Available: 1}, // free rooms
+		{HotelId: hotelId.String(), RoomTypeId: roomTypeB.String(), Date: day(2), Available: 3}, // free rooms under a larger allotment
+		{HotelId: hotelId.String(), RoomTypeId: roomTypeB.String(), Date: day(3), Available: 0}, // never negative
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("channelAvailabilities() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("channelAvailabilities()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	booking_repo_mapping "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrCreditLimitExceeded = errors.New("company credit limit exceeded")
+var ErrNoCompanyRate = errors.New("company has no rate for the room type")
+
+// Company the user books for with its negotiated rates, nil when the user is not a member of any company
+func (bs *BookingService) getMemberCompany(ctx context.Context, userId pgtype.UUID) (*user_pb.GetCompanyByUserIdResponse, error) {
+
+	result, err := bs.userClient.GetCompanyByUserId(ctx, &user_pb.GetCompanyByUserIdRequest{
+		UserId: userId.String(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+
+		zap.S().Infoln("Failed to get Company of User: ", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Total of the stay at the negotiated rate, a rate of the room type wins over a rate of the whole hotel.
+// Return false when the company has no rate for the room type
+func negotiatedTotal(rates []*user_pb.CompanyRate, hotelId string, roomTypeId string, price int, nights int) (int, bool) {
+
+	var matched *user_pb.CompanyRate
+	for _, rate := range rates {
+		if rate.GetHotelId() != hotelId {
+			continue
+		}
+
+		if rate.GetRoomTypeId() == roomTypeId {
+			matched = rate
+			break
+		}
+
+		if rate.GetRoomTypeId() == "" {
+			matched = rate
+		}
+	}
+
+	if matched == nil {
+		return 0, false
+	}
+
+	switch matched.GetRateType() {
+	case "FIXED_PRICE":
+		return int(matched.GetValue()) * nights, true
+	case "DISCOUNT_PERCENT":
+		return price * (100 - int(matched.GetValue())) / 100 * nights, true
+	}
+
+	return 0, false
+}
+
+// Bill the bookings to the company, the credit is held before they are created
+func (bs *BookingService) createCompanyBookings(ctx context.Context, company *user_pb.GetCompanyByUserIdResponse, newBookings []NewBooking) ([]pgtype.UUID, error) {
+
+	var companyId pgtype.UUID
+	if err := companyId.Scan(company.GetCompany().GetId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, err
+	}
+
+	billed := 0
+	for index := range newBookings {
+		newBookings[index].CompanyId = companyId
+		billed += newBookings[index].Total
+	}
+
+	// Hold the credit first so concurrent bookings cannot exceed the limit together
+	if err := bs.chargeCompanyCredit(ctx, companyId, billed); err != nil {
+		return nil, err
+	}
+
+	ids, err := bs.CreateBookings(ctx, newBookings)
+	if err != nil {
+		if err := bs.chargeCompanyCredit(ctx, companyId, -billed); err != nil {
+			zap.S().Errorln("Failed to release Company credit of failed bookings: ", err)
+		}
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Add amount to outstanding balance of the company, a negative amount releases credit
+func (bs *BookingService) chargeCompanyCredit(ctx context.Context, companyId pgtype.UUID, amount int) error {
+
+	if amount == 0 {
+		return nil
+	}
+
+	_, err := bs.userClient.ChargeCompanyCredit(ctx, &user_pb.ChargeCompanyCreditRequest{
+		CompanyId: companyId.String(),
+		Amount:    int32(amount),
+	})
+	if err != nil {
+		if status.Code(err) == codes.ResourceExhausted {
+			zap.S().Infoln("Company credit limit exceeded")
+			return ErrCreditLimitExceeded
+		}
+
+		zap.S().Errorln("Failed to charge Company credit: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// Release credit held by bookings of each company, called after the bookings are cancelled
+func (bs *BookingService) releaseCompanyCredit(ctx context.Context, billedTotals []booking_repo.GetCompanyBilledTotalsRow) {
+
+	for _, billedTotal := range billedTotals {
+		if err := bs.chargeCompanyCredit(ctx, billedTotal.CompanyID, -int(billedTotal.Total)); err != nil {
+			zap.S().Errorln("Failed to release Company credit of cancelled bookings: ", err)
+		}
+	}
+}
+
+// Bookings billed to the company checking out in range, with the sum of every page for the invoice
+func (bs *BookingService) GetCompanyBookings(ctx context.Context, companyId pgtype.UUID, startDate pgtype.Date, endDate pgtype.Date, pageParams utils.PageParams) ([]booking_domain.Booking, int64, *utils.PageInfo, error) {
+
+	if !startDate.Time.Before(endDate.Time) {
+		zap.S().Infoln("Start date must be before End date")
+		return nil, 0, nil, common_error.ErrBadRequest
+	}
+
+	page, err := utils.NewPage(pageParams, bookingSortColumns, "check_out", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of Company bookings: ", pageParams)
+		return nil, 0, nil, err
+	}
+
+	total, err := bs.repo.GetCompanyBookingsTotal(ctx, booking_repo.GetCompanyBookingsTotalParams{
+		CompanyID: companyId,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get total of Company bookings: ", err)
+		return nil, 0, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	conditions.Add("company_id = $%d", companyId)
+	conditions.Add("deleted_at IS NULL")
+	conditions.Add("check_out >= $%d AND check_out < $%d", startDate, endDate)
+
+	bookings, pageInfo, err := utils.QueryPage(ctx, bs.conn, page, bookingColumns, "bookings", conditions, scanBooking, bookingSortKey(page))
+	if err != nil {
+		zap.S().Errorln("Failed to get Bookings by Company Id: ", err)
+		return nil, 0, nil, err
+	}
+
+	return booking_repo_mapping.FromBookingsRepoToBookingsDomain(bookings), total, pageInfo, nil
+}
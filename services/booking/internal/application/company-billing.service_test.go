@@ -0,0 +1,160 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// User client answering only the company calls, any other call panics
+type fakeUserClient struct {
+	user_pb.UserServiceClient
+	company   *user_pb.GetCompanyByUserIdResponse
+	err       error
+	charges   []int32
+	chargeErr error
+}
+
+func (fc *fakeUserClient) GetCompanyByUserId(ctx context.Context, in *user_pb.GetCompanyByUserIdRequest, opts ...grpc.CallOption) (*user_pb.GetCompanyByUserIdResponse, error) {
+	if fc.err != nil {
+		return nil, fc.err
+	}
+	return fc.company, nil
+}
+
+func (fc *fakeUserClient) ChargeCompanyCredit(ctx context.Context, in *user_pb.ChargeCompanyCreditRequest, opts ...grpc.CallOption) (*user_pb.ChargeCompanyCreditResponse, error) {
+	fc.charges = append(fc.charges, in.GetAmount())
+	if fc.chargeErr != nil {
+		return nil, fc.chargeErr
+	}
+	return &user_pb.ChargeCompanyCreditResponse{}, nil
+}
+
+func TestNegotiatedTotal(t *testing.T) {
+	hotelRate := &user_pb.CompanyRate{HotelId: "hotel-1", RateType: "DISCOUNT_PERCENT", Value: 10}
+	roomTypeRate := &user_pb.CompanyRate{HotelId: "hotel-1", RoomTypeId: "suite", RateType: "FIXED_PRICE", Value: 150}
+	otherHotelRate := &user_pb.CompanyRate{HotelId: "hotel-2", RoomTypeId: "suite", RateType: "FIXED_PRICE", Value: 50}
+
+	tests := []struct {
+		name       string
+		rates      []*user_pb.CompanyRate
+		roomTypeId string
+		price      int
+		nights     int
+		want       int
+		wantOk     bool
+	}{
+		{"discount of the hotel", []*user_pb.CompanyRate{hotelRate}, "standard", 100, 3, 270, true},
+		{"fixed price of the room type", []*user_pb.CompanyRate{roomTypeRate}, "suite", 200, 2, 300, true},
+		{"room type rate wins over hotel rate listed first", []*user_pb.CompanyRate{hotelRate, roomTypeRate}, "suite", 200, 1, 150, true},
+		{"room type rate wins over hotel rate listed last", []*user_pb.CompanyRate{roomTypeRate, hotelRate}, "suite", 200, 1, 150, true},
+		{"hotel rate for another room type", []*user_pb.CompanyRate{roomTypeRate, hotelRate}, "standard", 100, 1, 90, true},
+		{"rate of another hotel", []*user_pb.CompanyRate{otherHotelRate}, "suite", 200, 1, 0, false},
+		{"no rate", nil, "suite", 200, 1, 0, false},
+		{"unknown rate type", []*user_pb.CompanyRate{{HotelId: "hotel-1", RateType: "CASHBACK", Value: 10}}, "suite", 200, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := negotiatedTotal(tt.rates, "hotel-1", tt.roomTypeId, tt.price, tt.nights)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("negotiatedTotal() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestGetMemberCompany(t *testing.T) {
+	userId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	company := &user_pb.GetCompanyByUserIdResponse{Company: &user_pb.Company{Id: "company-1"}}
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	tests := []struct {
+		name    string
+		client  *fakeUserClient
+		want    *user_pb.GetCompanyByUserIdResponse
+		wantErr error
+	}{
+		{"member", &fakeUserClient{company: company}, company, nil},
+		{"not a member", &fakeUserClient{err: status.Error(codes.NotFound, "not found")}, nil, nil},
+		// The booking must not fall back to the public price when membership is unknown
+		{"user service down", &fakeUserClient{err: unavailable}, nil, unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := &BookingService{userClient: tt.client}
+
+			got, err := bs.getMemberCompany(context.Background(), userId)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("getMemberCompany() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getMemberCompany() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChargeCompanyCredit(t *testing.T) {
+	companyId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	unavailable := status.Error(codes.Unavailable, "down")
+
+	tests := []struct {
+		name        string
+		amount      int
+		chargeErr   error
+		wantErr     error
+		wantCharges int
+	}{
+		{"hold credit", 300, nil, nil, 1},
+		{"release credit", -300, nil, nil, 1},
+		{"nothing to charge", 0, nil, nil, 0},
+		{"credit limit exceeded", 300, status.Error(codes.ResourceExhausted, "limit"), ErrCreditLimitExceeded, 1},
+		{"user service down", 300, unavailable, unavailable, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeUserClient{chargeErr: tt.chargeErr}
+			bs := &BookingService{userClient: client}
+
+			if err := bs.chargeCompanyCredit(context.Background(), companyId, tt.amount); !errors.Is(err, tt.wantErr) {
+				t.Errorf("chargeCompanyCredit() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(client.charges) != tt.wantCharges {
+				t.Fatalf("chargeCompanyCredit() calls = %d, want %d", len(client.charges), tt.wantCharges)
+			}
+			if tt.wantCharges > 0 && int(client.charges[0]) != tt.amount {
+				t.Errorf("chargeCompanyCredit() charged %d, want %d", client.charges[0], tt.amount)
+			}
+		})
+	}
+}
+
+func TestCreateCompanyBookingsOverCreditLimit(t *testing.T) {
+	client := &fakeUserClient{chargeErr: status.Error(codes.ResourceExhausted, "limit")}
+	bs := &BookingService{userClient: client}
+	company := &user_pb.GetCompanyByUserIdResponse{Company: &user_pb.Company{Id: "6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b"}}
+
+	newBookings := []NewBooking{{Total: 200}, {Total: 100}}
+
+	// Rejected before any booking is created
+	if _, err := bs.createCompanyBookings(context.Background(), company, newBookings); !errors.Is(err, ErrCreditLimitExceeded) {
+		t.Fatalf("createCompanyBookings() error = %v, want %v", err, ErrCreditLimitExceeded)
+	}
+	if len(client.charges) != 1 || client.charges[0] != 300 {
+		t.Errorf("createCompanyBookings() charges = %v, want the whole stay [300] once", client.charges)
+	}
+	for _, newBooking := range newBookings {
+		if newBooking.CompanyId.String() != company.GetCompany().GetId() {
+			t.Errorf("createCompanyBookings() booking company = %s, want %s", newBooking.CompanyId.String(), company.GetCompany().GetId())
+		}
+	}
+}
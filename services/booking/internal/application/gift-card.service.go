@@ -0,0 +1,428 @@
+package booking_service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const GIFT_CARD_VALIDITY = 365 * 24 * time.Hour
+
+// Without 0/O and 1/I so codes can be read out over the phone
+const GIFT_CARD_CODE_ALPHABET = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+var ErrGiftCardNotUsable = errors.New("gift card is voided, expired or empty")
+var ErrCompanyBilledBooking = errors.New("booking is billed to a company")
+
+type PayBookingResult struct {
+	Paid            int
+	Outstanding     int
+	GiftCardBalance int
+	BookingStatus   string
+}
+
+// XXXX-XXXX-XXXX-XXXX from a crypto random source
+func newGiftCardCode() (string, error) {
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	var code strings.Builder
+	for index, b := range random {
+		if index > 0 && index%4 == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(GIFT_CARD_CODE_ALPHABET[int(b)%len(GIFT_CARD_CODE_ALPHABET)])
+	}
+
+	return code.String(), nil
+}
+
+func giftCardActor(ctx context.Context) string {
+	actor := utils.ActorFromContext(ctx)
+	if actor == "" {
+		actor = "system"
+	}
+
+	return actor
+}
+
+// Issue a card with a new unique code, the issued balance is the first entry of its ledger
+func (bs *BookingService) IssueGiftCard(ctx context.Context, amount int, expiresAt pgtype.Timestamp) (*booking_repo.GiftCard, error) {
+
+	if amount <= 0 {
+		zap.S().Infoln("Gift card amount must be positive")
+		return nil, common_error.ErrBadRequest
+	}
+
+	if !expiresAt.Valid {
+		expiresAt = pgtype.Timestamp{Time: time.Now().Add(GIFT_CARD_VALIDITY), Valid: true}
+	}
+
+	if !expiresAt.Time.After(time.Now()) {
+		zap.S().Infoln("Gift card must expire in the future")
+		return nil, common_error.ErrBadRequest
+	}
+
+	// A collision of random codes is unlikely, retry a few times before giving up
+	for attempt := 0; attempt < 3; attempt++ {
+		code, err := newGiftCardCode()
+		if err != nil {
+			zap.S().Errorln("Failed to generate gift card code: ", err)
+			return nil, err
+		}
+
+		giftCard, err := bs.issueGiftCard(ctx, code, amount, expiresAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				zap.S().Infoln("Duplicated gift card code, retrying")
+				continue
+			}
+			return nil, err
+		}
+
+		return giftCard, nil
+	}
+
+	zap.S().Errorln("Failed to generate a unique gift card code")
+	return nil, common_error.ErrDuplicateRecord
+}
+
+func (bs *BookingService) issueGiftCard(ctx context.Context, code string, amount int, expiresAt pgtype.Timestamp) (*booking_repo.GiftCard, error) {
+
+	tx, err := bs.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin issue gift card transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := bs.repo.WithTx(tx)
+
+	actor := giftCardActor(ctx)
+
+	giftCard, err := qtx.CreateGiftCard(ctx, booking_repo.CreateGiftCardParams{
+		Code:           code,
+		InitialBalance: int32(amount),
+		ExpiresAt:      expiresAt,
+		IssuedBy:       actor,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to create Gift Card: ", err)
+		return nil, err
+	}
+
+	if err := qtx.CreateGiftCardTransaction(ctx, booking_repo.CreateGiftCardTransactionParams{
+		GiftCardID: giftCard.ID,
+		Type:       booking_repo.GiftCardTransactionTypeISSUE,
+		Amount:     int32(amount),
+		Actor:      actor,
+	}); err != nil {
+		zap.S().Errorln("Failed to create Gift Card issue transaction: ", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit issued Gift Card: ", err)
+		return nil, err
+	}
+
+	return &giftCard, nil
+}
+
+// Void an active card, its remaining balance is written off in the ledger
+func (bs *BookingService) VoidGiftCard(ctx context.Context, id pgtype.UUID, note pgtype.Text) (*booking_repo.GiftCard, error) {
+
+	tx, err := bs.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin void gift card transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := bs.repo.WithTx(tx)
+
+	// Lock so a concurrent redemption cannot change the balance written off
+	giftCard, err := qtx.LockGiftCardById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("No Gift Card to void")
+		return nil, common_error.ErrNoRows
+	}
+	if err != nil {
+		zap.S().Errorln("Cannot get Gift Card by id: ", err)
+		return nil, err
+	}
+
+	if giftCard.Status != booking_repo.GiftCardStatusACTIVE {
+		zap.S().Infoln("Gift Card is already voided")
+		return nil, ErrGiftCardNotUsable
+	}
+
+	voided, err := qtx.VoidGiftCard(ctx, giftCard.ID)
+	if err != nil {
+		zap.S().Errorln("Failed to void Gift Card: ", err)
+		return nil, err
+	}
+
+	if giftCard.Balance > 0 {
+		if err := qtx.CreateGiftCardTransaction(ctx, booking_repo.CreateGiftCardTransactionParams{
+			GiftCardID: giftCard.ID,
+			Type:       booking_repo.GiftCardTransactionTypeVOID,
+			Amount:     -giftCard.Balance,
+			Actor:      giftCardActor(ctx),
+			Note:       note,
+		}); err != nil {
+			zap.S().Errorln("Failed to create Gift Card void transaction: ", err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit voided Gift Card: ", err)
+		return nil, err
+	}
+
+	return &voided, nil
+}
+
+// Balance check by code, the code is case insensitive
+func (bs *BookingService) GetGiftCardByCode(ctx context.Context, code string) (*booking_repo.GiftCard, error) {
+
+	giftCard, err := bs.repo.GetGiftCardByCode(ctx, strings.TrimSpace(code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("No Gift Card of code")
+		return nil, common_error.ErrNoRows
+	}
+	if err != nil {
+		zap.S().Errorln("Cannot get Gift Card by code: ", err)
+		return nil, err
+	}
+
+	return &giftCard, nil
+}
+
+const giftCardTransactionColumns = `id, gift_card_id, type, amount, booking_id, actor, note, create_at`
+
+// Sortable fields of the gift card transactions
+var giftCardTransactionSortColumns = map[string]utils.SortColumn{
+	"create_at": {Column: "create_at", Cast: "timestamp"},
+}
+
+// ErrBadRequest when the page is invalid
+func (bs *BookingService) GetGiftCardTransactions(ctx context.Context, giftCardId pgtype.UUID, pageParams utils.PageParams) ([]booking_repo.GiftCardTransaction, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, giftCardTransactionSortColumns, "create_at", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of Gift Card transactions: ", pageParams)
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	conditions.Add("gift_card_id = $%d", giftCardId)
+
+	transactions, pageInfo, err := utils.QueryPage(ctx, bs.conn, page, giftCardTransactionColumns, "gift_card_transactions", conditions,
+		func(rows pgx.Rows) (booking_repo.GiftCardTransaction, error) {
+			var t booking_repo.GiftCardTransaction
+			err := rows.Scan(&t.ID, &t.GiftCardID, &t.Type, &t.Amount, &t.BookingID, &t.Actor, &t.Note, &t.CreateAt)
+			return t, err
+		},
+		func(t booking_repo.GiftCardTransaction) (any, pgtype.UUID) {
+			return t.CreateAt, t.ID
+		})
+	if err != nil {
+		zap.S().Errorln("Failed to get Gift Card transactions: ", err)
+		return nil, nil, err
+	}
+
+	return transactions, pageInfo, nil
+}
+
+// Pay the outstanding amount of a booking with a gift card, partially when the card does not cover it.
+// Amount 0 takes as much as the card and the booking allow. The requester must own or manage the booking, nil is an internal call
+func (bs *BookingService) PayBookingWithGiftCard(ctx context.Context, bookingId pgtype.UUID, code string, amount int, requester *BookingRequester) (*PayBookingResult, error) {
+
+	if amount < 0 {
+		zap.S().Infoln("Payment amount must not be negative")
+		return nil, common_error.ErrBadRequest
+	}
+
+	tx, err := bs.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin gift card payment transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setBookingAuditContext(ctx, tx, "paid with gift card"); err != nil {
+		return nil, err
+	}
+
+	qtx := bs.repo.WithTx(tx)
+
+	booking, err := lockActiveBooking(ctx, qtx, bookingId)
+	if err != nil {
+		return nil, err
+	}
+
+	if requester != nil && !requester.canAccess(booking.ID, booking.UserID, booking.HotelID) {
+		zap.S().Infoln("Requester cannot pay Booking: ", booking.ID.String())
+		return nil, ErrBookingAccessDenied
+	}
+
+	// The company pays its billed stays on its invoice
+	if booking.CompanyID.Valid {
+		zap.S().Infoln("Booking is billed to a Company")
+		return nil, ErrCompanyBilledBooking
+	}
+
+	paidTotal, err := qtx.GetBookingPaidTotal(ctx, booking.ID)
+	if err != nil {
+		zap.S().Errorln("Failed to get Booking paid total: ", err)
+		return nil, err
+	}
+
+	outstanding := int(booking.Total - paidTotal)
+	if outstanding <= 0 {
+		zap.S().Infoln("Booking is already paid")
+		return nil, common_error.ErrBadRequest
+	}
+
+	// Unknown codes are not told apart from unusable cards
+	giftCard, err := qtx.GetGiftCardByCode(ctx, strings.TrimSpace(code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("No Gift Card of code")
+		return nil, ErrGiftCardNotUsable
+	}
+	if err != nil {
+		zap.S().Errorln("Cannot get Gift Card by code: ", err)
+		return nil, err
+	}
+
+	charge := outstanding
+	if amount > 0 && amount < charge {
+		charge = amount
+	}
+	if int(giftCard.Balance) < charge {
+		charge = int(giftCard.Balance)
+	}
+
+	if charge <= 0 {
+		zap.S().Infoln("Gift Card has no balance")
+		return nil, ErrGiftCardNotUsable
+	}
+
+	giftCard, err = qtx.RedeemGiftCard(ctx, booking_repo.RedeemGiftCardParams{
+		Amount: int32(charge),
+		ID:     giftCard.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("Gift Card is voided, expired or short of balance")
+		return nil, ErrGiftCardNotUsable
+	}
+	if err != nil {
+		zap.S().Errorln("Failed to redeem Gift Card: ", err)
+		return nil, err
+	}
+
+	if err := qtx.CreateGiftCardTransaction(ctx, booking_repo.CreateGiftCardTransactionParams{
+		GiftCardID: giftCard.ID,
+		Type:       booking_repo.GiftCardTransactionTypeREDEEM,
+		Amount:     int32(-charge),
+		BookingID:  booking.ID,
+		Actor:      giftCardActor(ctx),
+	}); err != nil {
+		zap.S().Errorln("Failed to create Gift Card redeem transaction: ", err)
+		return nil, err
+	}
+
+	if err := qtx.CreateBookingPayment(ctx, booking_repo.CreateBookingPaymentParams{
+		BookingID:  booking.ID,
+		Method:     booking_repo.PaymentMethodGIFTCARD,
+		Amount:     int32(charge),
+		GiftCardID: giftCard.ID,
+	}); err != nil {
+		zap.S().Errorln("Failed to create Booking payment: ", err)
+		return nil, err
+	}
+
+	bookingStatus := booking.Status
+	if charge == outstanding {
+		paid, err := qtx.SetBookingPaid(ctx, booking.ID)
+		if err != nil {
+			zap.S().Errorln("Failed to set Booking paid: ", err)
+			return nil, err
+		}
+
+		if paid > 0 {
+			bookingStatus = booking_repo.BookingStatusPAID
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit gift card payment: ", err)
+		return nil, err
+	}
+
+	return &PayBookingResult{
+		Paid:            charge,
+		Outstanding:     outstanding - charge,
+		GiftCardBalance: int(giftCard.Balance),
+		BookingStatus:   string(bookingStatus),
+	}, nil
+}
+
+// Gift card payments of cancelled bookings go back to their card, in the cancelling transaction
+func refundBookingPayments(ctx context.Context, qtx *booking_repo.Queries, bookingIds []pgtype.UUID) error {
+
+	payments, err := qtx.RefundBookingPaymentsByBookingIds(ctx, bookingIds)
+	if err != nil {
+		zap.S().Errorln("Failed to refund Booking payments: ", err)
+		return err
+	}
+
+	for _, payment := range payments {
+		if payment.Method != booking_repo.PaymentMethodGIFTCARD {
+			continue
+		}
+
+		if _, err := qtx.CreditGiftCard(ctx, booking_repo.CreditGiftCardParams{
+			Amount: payment.Amount,
+			ID:     payment.GiftCardID,
+		}); err != nil {
+			// The balance of a voided card is gone, the payment is not given back to it
+			if errors.Is(err, pgx.ErrNoRows) {
+				zap.S().Infoln("Gift Card is voided, payment is not credited back: ", payment.GiftCardID.String())
+				continue
+			}
+
+			zap.S().Errorln("Failed to credit Gift Card: ", err)
+			return err
+		}
+
+		if err := qtx.CreateGiftCardTransaction(ctx, booking_repo.CreateGiftCardTransactionParams{
+			GiftCardID: payment.GiftCardID,
+			Type:       booking_repo.GiftCardTransactionTypeREFUND,
+			Amount:     payment.Amount,
+			BookingID:  payment.BookingID,
+			Actor:      giftCardActor(ctx),
+		}); err != nil {
+			zap.S().Errorln("Failed to create Gift Card refund transaction: ", err)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package booking_service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	booking_infrastructure "github.com/098765432m/grpc-kafka/booking/internal/infrastructure"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidIcalToken = errors.New("invalid ical token")
+
+// Past nights kept in the exported feed
+const ICAL_EXPORT_PAST_DAYS = 30
+
+type CreateIcalFeedParams struct {
+	RoomId     pgtype.UUID
+	RoomTypeId pgtype.UUID
+	HotelId    pgtype.UUID
+	Url        string
+}
+
+// Register an external .ics feed of a room, it is imported on the next sync
+func (bs *BookingService) CreateIcalFeed(ctx context.Context, params *CreateIcalFeedParams) (*booking_repo.IcalFeed, error) {
+
+	feedUrl, err := booking_infrastructure.ValidateIcalFeedUrl(ctx, params.Url)
+	if err != nil {
+		zap.S().Infoln("Invalid iCal feed url: ", params.Url)
+		return nil, common_error.ErrBadRequest
+	}
+
+	feed, err := bs.repo.CreateIcalFeed(ctx, booking_repo.CreateIcalFeedParams{
+		RoomID:     params.RoomId,
+		RoomTypeID: params.RoomTypeId,
+		HotelID:    params.HotelId,
+		Url:        feedUrl,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to create iCal feed: ", err)
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+func (bs *BookingService) GetIcalFeedsByRoomId(ctx context.Context, roomId pgtype.UUID) ([]booking_repo.IcalFeed, error) {
+
+	feeds, err := bs.repo.GetIcalFeedsByRoomId(ctx, roomId)
+	if err != nil {
+		zap.S().Errorln("Failed to get iCal feeds: ", err)
+		return nil, err
+	}
+
+	return feeds, nil
+}
+
+// Delete a feed with all of its blocks
+func (bs *BookingService) DeleteIcalFeedById(ctx context.Context, id pgtype.UUID) error {
+
+	deleted, err := bs.repo.DeleteIcalFeedById(ctx, id)
+	if err != nil {
+		zap.S().Errorln("Failed to delete iCal feed: ", err)
+		return err
+	}
+
+	if deleted == 0 {
+		return common_error.ErrNoRows
+	}
+
+	return nil
+}
+
+// Token of the exported feed url, signed so room ids cannot be guessed into feeds
+func (bs *BookingService) GetRoomIcalToken(roomId pgtype.UUID) (string, error) {
+
+	secretKey := viper.GetString("ICAL_SECRET_KEY")
+	if secretKey == "" {
+		zap.S().Errorln("Failed to get ICAL SECRET KEY from env")
+		return "", common_error.ErrInternalServer
+	}
+
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(roomId.String()))
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// .ics of booked and blocked nights of a room
+func (bs *BookingService) ExportRoomIcal(ctx context.Context, roomId pgtype.UUID, token string) ([]byte, error) {
+
+	expectedToken, err := bs.GetRoomIcalToken(roomId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal([]byte(token), []byte(expectedToken)) {
+		zap.S().Infoln("Invalid iCal token of Room: ", roomId.String())
+		return nil, ErrInvalidIcalToken
+	}
+
+	fromDate := pgtype.Date{Time: time.Now().UTC().AddDate(0, 0, -ICAL_EXPORT_PAST_DAYS), Valid: true}
+
+	bookings, err := bs.repo.GetBookedNightsByRoomId(ctx, booking_repo.GetBookedNightsByRoomIdParams{
+		RoomID:   roomId,
+		FromDate: fromDate,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get booked nights of Room: ", err)
+		return nil, err
+	}
+
+	blocks, err := bs.repo.GetRoomBlocksByRoomId(ctx, booking_repo.GetRoomBlocksByRoomIdParams{
+		RoomID:   roomId,
+		FromDate: fromDate,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get blocks of Room: ", err)
+		return nil, err
+	}
+
+	events := make([]booking_infrastructure.IcalEvent, 0, len(bookings)+len(blocks))
+	for _, booking := range bookings {
+		events = append(events, booking_infrastructure.IcalEvent{
+			Uid:     "booking-" + booking.ID.String() + "@grpc-kafka",
+			Start:   booking.CheckIn.Time,
+			End:     booking.CheckOut.Time,
+			Summary: "Booked",
+		})
+	}
+	for _, block := range blocks {
+		events = append(events, booking_infrastructure.IcalEvent{
+			Uid:     "block-" + block.ID.String() + "@grpc-kafka",
+			Start:   block.StartDate.Time,
+			End:     block.EndDate.Time,
+			Summary: "Blocked",
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := booking_infrastructure.WriteIcalCalendar(&buf, "Room "+roomId.String(), events); err != nil {
+		zap.S().Errorln("Failed to write iCal: ", err)
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Pull all feeds every interval
+func (bs *BookingService) StartIcalImporter(ctx context.Context, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			feeds, err := bs.repo.GetIcalFeeds(ctx)
+			if err != nil {
+				zap.S().Errorln("Failed to get iCal feeds: ", err)
+				continue
+			}
+
+			for _, feed := range feeds {
+				bs.syncIcalFeed(ctx, feed)
+			}
+		}
+	}
+}
+
+// Import VEVENTs of a feed as room blocks, a failed fetch keeps the blocks of the last sync.
+// Skipped VEVENTs are reported as the feed error while the rest of the feed is imported
+func (bs *BookingService) syncIcalFeed(ctx context.Context, feed booking_repo.IcalFeed) {
+
+	skipped, syncErr := bs.importIcalFeed(ctx, feed)
+	if syncErr != nil {
+		zap.S().Errorln("Failed to import iCal feed: ", feed.ID.String(), syncErr)
+	}
+
+	lastError := pgtype.Text{}
+	switch {
+	case syncErr != nil:
+		lastError = pgtype.Text{String: syncErr.Error(), Valid: true}
+	case len(skipped) > 0:
+		zap.S().Infoln("Skipped invalid VEVENTs of iCal feed: ", feed.ID.String(), len(skipped))
+		lastError = pgtype.Text{String: icalSkippedReport(skipped), Valid: true}
+	}
+
+	if err := bs.repo.SetIcalFeedSyncResult(ctx, booking_repo.SetIcalFeedSyncResultParams{
+		LastError: lastError,
+		ID:        feed.ID,
+	}); err != nil {
+		zap.S().Errorln("Failed to set iCal feed sync result: ", err)
+	}
+}
+
+// Reported skipped VEVENTs, the rest are only counted
+const ICAL_SKIPPED_REPORT_SIZE = 5
+
+func icalSkippedReport(skipped []booking_infrastructure.IcalSkippedEvent) string {
+
+	reasons := make([]string, 0, ICAL_SKIPPED_REPORT_SIZE)
+	for _, event := range skipped[:min(len(skipped), ICAL_SKIPPED_REPORT_SIZE)] {
+		reasons = append(reasons, fmt.Sprintf("%q: %s", event.Uid, event.Reason))
+	}
+
+	return fmt.Sprintf("skipped %d invalid VEVENT: %s", len(skipped), strings.Join(reasons, "; "))
+}
+
+func (bs *BookingService) importIcalFeed(ctx context.Context, feed booking_repo.IcalFeed) ([]booking_infrastructure.IcalSkippedEvent, error) {
+
+	content, err := booking_infrastructure.FetchIcalEvents(ctx, feed.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := bs.conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := bs.repo.WithTx(tx)
+
+	uids := make([]string, 0, len(content.Events)+len(content.Skipped))
+	for _, event := range content.Events {
+		if err := qtx.UpsertRoomBlock(ctx, booking_repo.UpsertRoomBlockParams{
+			FeedID:     feed.ID,
+			RoomID:     feed.RoomID,
+			RoomTypeID: feed.RoomTypeID,
+			HotelID:    feed.HotelID,
+			Uid:        event.Uid,
+			StartDate:  pgtype.Date{Time: event.Start, Valid: true},
+			EndDate:    pgtype.Date{Time: event.End, Valid: true},
+			Summary:    event.Summary,
+		}); err != nil {
+			return nil, err
+		}
+
+		uids = append(uids, event.Uid)
+	}
+
+	// A skipped VEVENT keeps the block of its last valid version
+	for _, event := range content.Skipped {
+		if event.Uid != "" {
+			uids = append(uids, event.Uid)
+		}
+	}
+
+	if _, err := qtx.DeleteRoomBlocksNotInUids(ctx, booking_repo.DeleteRoomBlocksNotInUidsParams{
+		FeedID: feed.ID,
+		Uids:   uids,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return content.Skipped, nil
+}
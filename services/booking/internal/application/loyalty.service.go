@@ -0,0 +1,101 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/loyalty_pb"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrInsufficientPoints = errors.New("insufficient loyalty points")
+
+// Tier and balance of the member, its tier benefits apply to the price
+func (bs *BookingService) getLoyaltyAccount(ctx context.Context, userId pgtype.UUID) (*loyalty_pb.LoyaltyAccount, error) {
+
+	account, err := bs.loyaltyClient.GetLoyaltyAccount(ctx, &loyalty_pb.GetLoyaltyAccountRequest{
+		UserId: userId.String(),
+	})
+	if err != nil {
+		zap.S().Infoln("Failed to get Loyalty Account: ", err)
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// Spend points as a discount taken off the bookings in order. Each discounted booking redeems its own points,
+// so cancelling it gives back only its share, and every redemption is given back if the bookings fail
+func (bs *BookingService) createBookingsWithPoints(ctx context.Context, userId pgtype.UUID, points int, newBookings []NewBooking) ([]pgtype.UUID, error) {
+
+	total := 0
+	for _, newBooking := range newBookings {
+		total += newBooking.Total
+	}
+
+	if points > total {
+		zap.S().Infoln("Redeemed points are more than the total")
+		return nil, common_error.ErrBadRequest
+	}
+
+	var redeemedBookingIds []pgtype.UUID
+	remain := points
+	for index := range newBookings {
+		discount := min(remain, newBookings[index].Total)
+		if discount == 0 {
+			continue
+		}
+
+		// The id is chosen before the booking exists so its redemption can name it
+		bookingId := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+
+		if _, err := bs.loyaltyClient.RedeemPoints(ctx, &loyalty_pb.RedeemPointsRequest{
+			UserId:    userId.String(),
+			Points:    int32(discount),
+			Note:      "booking discount",
+			BookingId: bookingId.String(),
+		}); err != nil {
+			bs.cancelRedemptions(ctx, redeemedBookingIds)
+
+			if status.Code(err) == codes.ResourceExhausted {
+				zap.S().Infoln("Member does not have enough points")
+				return nil, ErrInsufficientPoints
+			}
+
+			zap.S().Errorln("Failed to redeem points: ", err)
+			return nil, err
+		}
+		redeemedBookingIds = append(redeemedBookingIds, bookingId)
+
+		newBookings[index].Id = bookingId
+		newBookings[index].Total -= discount
+		remain -= discount
+	}
+
+	ids, err := bs.CreateBookings(ctx, newBookings)
+	if err != nil {
+		bs.cancelRedemptions(ctx, redeemedBookingIds)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Give back points redeemed on the bookings, bookings that redeemed nothing are skipped.
+// Runs after the bookings are gone, so it is not cut short when the request ends
+func (bs *BookingService) cancelRedemptions(ctx context.Context, bookingIds []pgtype.UUID) {
+
+	ctx = context.WithoutCancel(ctx)
+	for _, bookingId := range bookingIds {
+		if _, err := bs.loyaltyClient.CancelRedemption(ctx, &loyalty_pb.CancelRedemptionRequest{
+			BookingId: bookingId.String(),
+		}); err != nil && status.Code(err) != codes.NotFound {
+			zap.S().Errorln("Failed to give back redeemed points of Booking: ", bookingId.String(), err)
+		}
+	}
+}
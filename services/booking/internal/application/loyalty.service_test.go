@@ -0,0 +1,83 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/loyalty_pb"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Loyalty client holding a balance of points, any call other than redemptions panics
+type fakeLoyaltyClient struct {
+	loyalty_pb.LoyaltyServiceClient
+	balance   int32
+	redeemed  map[string]int32 // Booking id -> points
+	cancelled []string
+}
+
+func (fc *fakeLoyaltyClient) RedeemPoints(ctx context.Context, in *loyalty_pb.RedeemPointsRequest, opts ...grpc.CallOption) (*loyalty_pb.LoyaltyTransaction, error) {
+	if in.GetPoints() > fc.balance {
+		return nil, status.Error(codes.ResourceExhausted, "insufficient points")
+	}
+
+	fc.balance -= in.GetPoints()
+	if fc.redeemed == nil {
+		fc.redeemed = map[string]int32{}
+	}
+	fc.redeemed[in.GetBookingId()] = in.GetPoints()
+	return &loyalty_pb.LoyaltyTransaction{}, nil
+}
+
+func (fc *fakeLoyaltyClient) CancelRedemption(ctx context.Context, in *loyalty_pb.CancelRedemptionRequest, opts ...grpc.CallOption) (*loyalty_pb.CancelRedemptionResponse, error) {
+	fc.cancelled = append(fc.cancelled, in.GetBookingId())
+	return &loyalty_pb.CancelRedemptionResponse{}, nil
+}
+
+func TestCreateBookingsWithPointsRejected(t *testing.T) {
+	userId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+
+	tests := []struct {
+		name          string
+		balance       int32
+		points        int
+		totals        []int
+		wantErr       error
+		wantCancelled int
+	}{
+		{"more points than the total", 1000, 301, []int{200, 100}, common_error.ErrBadRequest, 0},
+		// The first booking redeemed its share before the second one ran out of points
+		{"balance runs out on the second booking", 250, 300, []int{200, 100}, ErrInsufficientPoints, 1},
+		{"not enough points for the first booking", 50, 100, []int{200, 100}, ErrInsufficientPoints, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLoyaltyClient{balance: tt.balance}
+			bs := &BookingService{loyaltyClient: client}
+
+			newBookings := make([]NewBooking, 0, len(tt.totals))
+			for _, total := range tt.totals {
+				newBookings = append(newBookings, NewBooking{Total: total})
+			}
+
+			if _, err := bs.createBookingsWithPoints(context.Background(), userId, tt.points, newBookings); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("createBookingsWithPoints() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if len(client.cancelled) != tt.wantCancelled {
+				t.Fatalf("createBookingsWithPoints() gave back %d redemptions, want %d", len(client.cancelled), tt.wantCancelled)
+			}
+			for _, bookingId := range client.cancelled {
+				if _, ok := client.redeemed[bookingId]; !ok {
+					t.Errorf("createBookingsWithPoints() gave back booking %s that redeemed nothing", bookingId)
+				}
+			}
+		})
+	}
+}
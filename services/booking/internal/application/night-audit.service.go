@@ -0,0 +1,242 @@
+package booking_service
+
+import (
+	"context"
+	"time"
+
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Set time of day the hotel closes its business date, an empty timezone keeps the current one
+func (bs *BookingService) SetHotelAuditTime(ctx context.Context, hotelId pgtype.UUID, auditTime pgtype.Time, timezone string) error {
+
+	if _, err := time.LoadLocation(timezone); err != nil {
+		zap.S().Infoln("Invalid Hotel timezone: ", timezone)
+		return common_error.ErrBadRequest
+	}
+
+	err := bs.repo.UpsertHotelAuditSetting(ctx, booking_repo.UpsertHotelAuditSettingParams{
+		HotelID:   hotelId,
+		AuditTime: auditTime,
+		Timezone:  pgtype.Text{String: timezone, Valid: timezone != ""},
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to set Hotel audit time: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// Close business date of a hotel: mark no-shows, check out past-due stays, post room night revenue and write the report.
+// Running it again for the same date changes nothing
+func (bs *BookingService) RunNightAudit(ctx context.Context, hotelId pgtype.UUID, businessDate pgtype.Date) (*booking_repo.NightAuditReport, error) {
+
+	tx, err := bs.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin night audit transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := setBookingAuditContext(ctx, tx, "night audit"); err != nil {
+		return nil, err
+	}
+
+	qtx := bs.repo.WithTx(tx)
+
+	noShows, err := qtx.MarkNoShowBookings(ctx, booking_repo.MarkNoShowBookingsParams{
+		HotelID:      hotelId,
+		BusinessDate: businessDate,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to mark no-show bookings: ", err)
+		return nil, err
+	}
+
+	checkOuts, err := qtx.CheckOutPastDueBookings(ctx, booking_repo.CheckOutPastDueBookingsParams{
+		HotelID:      hotelId,
+		BusinessDate: businessDate,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to check out past due bookings: ", err)
+		return nil, err
+	}
+
+	events := make([]booking_domain.BookingStatusChangedEvent, 0, len(noShows)+len(checkOuts))
+	for _, b := range noShows {
+		events = append(events, booking_domain.BookingStatusChangedEvent{
+			BookingId: b.ID.String(),
+			UserId:    b.UserID.String(),
+			HotelId:   b.HotelID.String(),
+			CheckIn:   b.CheckIn.Time,
+			CheckOut:  b.CheckOut.Time,
+			Total:     int(b.Total),
+			Status:    string(b.Status),
+		})
+	}
+	for _, b := range checkOuts {
+		events = append(events, booking_domain.BookingStatusChangedEvent{
+			BookingId: b.ID.String(),
+			UserId:    b.UserID.String(),
+			HotelId:   b.HotelID.String(),
+			CheckIn:   b.CheckIn.Time,
+			CheckOut:  b.CheckOut.Time,
+			Total:     int(b.Total),
+			Status:    string(b.Status),
+		})
+	}
+
+	if _, err := qtx.PostRoomNightRevenues(ctx, booking_repo.PostRoomNightRevenuesParams{
+		BusinessDate: businessDate,
+		HotelID:      hotelId,
+	}); err != nil {
+		zap.S().Errorln("Failed to post room night revenues: ", err)
+		return nil, err
+	}
+
+	report, err := qtx.UpsertNightAuditReport(ctx, booking_repo.UpsertNightAuditReportParams{
+		HotelID:      hotelId,
+		BusinessDate: businessDate,
+		NoShows:      int32(len(noShows)),
+		CheckOuts:    int32(len(checkOuts)),
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to write night audit report: ", err)
+		return nil, err
+	}
+
+	if err := qtx.SetHotelLastAuditDate(ctx, booking_repo.SetHotelLastAuditDateParams{
+		HotelID:       hotelId,
+		LastAuditDate: businessDate,
+	}); err != nil {
+		zap.S().Errorln("Failed to set Hotel last audit date: ", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit night audit: ", err)
+		return nil, err
+	}
+
+	// Loyalty credits points of checked out stays from these events
+	if bs.producer != nil {
+		for _, event := range events {
+			bs.producer.PublishBookingStatusChanged(ctx, event)
+		}
+	}
+
+	return &report, nil
+}
+
+const nightAuditReportColumns = `id, hotel_id, business_date, no_shows, check_outs, room_nights, room_revenue, create_at, updated_at`
+
+// Sortable fields of the night audit reports
+var nightAuditReportSortColumns = map[string]utils.SortColumn{
+	"business_date": {Column: "business_date", Cast: "date"},
+}
+
+// Reports of the business dates in [startDate, endDate], ErrBadRequest when the page is invalid
+func (bs *BookingService) GetNightAuditReports(ctx context.Context, hotelId pgtype.UUID, startDate pgtype.Date, endDate pgtype.Date, pageParams utils.PageParams) ([]booking_repo.NightAuditReport, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, nightAuditReportSortColumns, "business_date", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of night audit reports: ", pageParams)
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	conditions.Add("hotel_id = $%d", hotelId)
+	conditions.Add("business_date >= $%d AND business_date <= $%d", startDate, endDate)
+
+	reports, pageInfo, err := utils.QueryPage(ctx, bs.conn, page, nightAuditReportColumns, "night_audit_reports", conditions,
+		func(rows pgx.Rows) (booking_repo.NightAuditReport, error) {
+			var r booking_repo.NightAuditReport
+			err := rows.Scan(&r.ID, &r.HotelID, &r.BusinessDate, &r.NoShows, &r.CheckOuts, &r.RoomNights, &r.RoomRevenue, &r.CreateAt, &r.UpdatedAt)
+			return r, err
+		},
+		func(r booking_repo.NightAuditReport) (any, pgtype.UUID) {
+			return r.BusinessDate, r.ID
+		})
+	if err != nil {
+		zap.S().Errorln("Failed to get night audit reports: ", err)
+		return nil, nil, err
+	}
+
+	return reports, pageInfo, nil
+}
+
+// Check every interval which hotels passed their audit time and close their business date
+func (bs *BookingService) StartNightAuditScheduler(ctx context.Context, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			bs.runDueNightAudits(ctx, now)
+		}
+	}
+}
+
+func (bs *BookingService) runDueNightAudits(ctx context.Context, now time.Time) {
+
+	settings, err := bs.repo.GetHotelAuditSettings(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to get Hotel audit settings: ", err)
+		return
+	}
+
+	for _, setting := range settings {
+
+		location, err := time.LoadLocation(setting.Timezone)
+		if err != nil {
+			zap.S().Errorln("Invalid timezone of Hotel: ", setting.HotelID.String(), setting.Timezone)
+			location = time.UTC
+		}
+
+		businessDate := lastClosedBusinessDate(now, location, setting.AuditTime)
+
+		// Already audited
+		if setting.LastAuditDate.Valid && !setting.LastAuditDate.Time.Before(businessDate) {
+			continue
+		}
+
+		if _, err := bs.RunNightAudit(ctx, setting.HotelID, pgtype.Date{Time: businessDate, Valid: true}); err != nil {
+			zap.S().Errorln("Night audit failed for Hotel: ", setting.HotelID.String(), err)
+			continue
+		}
+
+		zap.S().Infoln("Night audit done for Hotel: ", setting.HotelID.String())
+	}
+}
+
+// Return the business date closed by the latest audit time at or before now, in the hotel timezone.
+// An audit time before noon closes the day before (02:00 closes yesterday), so arrivals of the new day are not no-shows
+func lastClosedBusinessDate(now time.Time, location *time.Location, auditTime pgtype.Time) time.Time {
+
+	local := now.In(location)
+	auditAt := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location).
+		Add(time.Duration(auditTime.Microseconds) * time.Microsecond)
+
+	// Audit time of today is not reached yet, the last audit was yesterday
+	if auditAt.After(local) {
+		auditAt = auditAt.AddDate(0, 0, -1)
+	}
+
+	businessDate := time.Date(auditAt.Year(), auditAt.Month(), auditAt.Day(), 0, 0, 0, 0, time.UTC)
+	if time.Duration(auditTime.Microseconds)*time.Microsecond < 12*time.Hour {
+		businessDate = businessDate.AddDate(0, 0, -1)
+	}
+
+	return businessDate
+}
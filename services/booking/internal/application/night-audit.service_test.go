@@ -0,0 +1,44 @@
+package booking_service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestLastClosedBusinessDate(t *testing.T) {
+	vietnam := time.FixedZone("ICT", 7*60*60)
+	auditAt := func(hour int) pgtype.Time {
+		return pgtype.Time{Microseconds: (time.Duration(hour) * time.Hour).Microseconds(), Valid: true}
+	}
+	date := func(day int) time.Time {
+		return time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		location  *time.Location
+		auditTime pgtype.Time
+		want      time.Time
+	}{
+		{"evening audit closes today", time.Date(2026, time.March, 10, 23, 30, 0, 0, time.UTC), time.UTC, auditAt(23), date(10)},
+		{"before evening audit yesterday is the last closed", time.Date(2026, time.March, 10, 22, 0, 0, 0, time.UTC), time.UTC, auditAt(23), date(9)},
+		{"after midnight the missed evening audit is still yesterday", time.Date(2026, time.March, 11, 0, 30, 0, 0, time.UTC), time.UTC, auditAt(23), date(10)},
+		{"early morning audit closes yesterday", time.Date(2026, time.March, 10, 2, 30, 0, 0, time.UTC), time.UTC, auditAt(2), date(9)},
+		{"before early morning audit the day before yesterday is the last closed", time.Date(2026, time.March, 10, 1, 0, 0, 0, time.UTC), time.UTC, auditAt(2), date(8)},
+		{"uses the hotel timezone", time.Date(2026, time.March, 10, 16, 30, 0, 0, time.UTC), vietnam, auditAt(23), date(10)},
+		{"hotel day ends before the server day", time.Date(2026, time.March, 10, 17, 30, 0, 0, time.UTC), vietnam, auditAt(23), date(10)},
+		{"hotel audit time not reached in the hotel timezone", time.Date(2026, time.March, 10, 15, 30, 0, 0, time.UTC), vietnam, auditAt(23), date(9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lastClosedBusinessDate(tt.now, tt.location, tt.auditTime)
+			if !got.Equal(tt.want) {
+				t.Errorf("lastClosedBusinessDate() = %s, want %s", got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+			}
+		})
+	}
+}
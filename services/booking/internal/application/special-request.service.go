@@ -0,0 +1,108 @@
+package booking_service
+
+import (
+	"context"
+
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Occupant of one booked room, all fields are optional
+type BookingGuest struct {
+	Name                 pgtype.Text
+	Email                pgtype.Text
+	Phone                pgtype.Text
+	EstimatedArrivalTime pgtype.Time
+	SpecialRequests      []SpecialRequest
+}
+
+type SpecialRequest struct {
+	Type booking_repo.SpecialRequestType
+	Note string // Free text, required for OTHER
+}
+
+var specialRequestTypes = map[booking_repo.SpecialRequestType]bool{
+	booking_repo.SpecialRequestTypeHIGHFLOOR:      true,
+	booking_repo.SpecialRequestTypeLOWFLOOR:       true,
+	booking_repo.SpecialRequestTypeQUIETROOM:      true,
+	booking_repo.SpecialRequestTypeBABYCOT:        true,
+	booking_repo.SpecialRequestTypeEXTRABED:       true,
+	booking_repo.SpecialRequestTypeACCESSIBLEROOM: true,
+	booking_repo.SpecialRequestTypeEARLYCHECKIN:   true,
+	booking_repo.SpecialRequestTypeLATECHECKIN:    true,
+	booking_repo.SpecialRequestTypeOTHER:          true,
+}
+
+func validateSpecialRequests(specialRequests []SpecialRequest) error {
+	for _, specialRequest := range specialRequests {
+		if !specialRequestTypes[specialRequest.Type] {
+			zap.S().Infoln("Invalid special request type: ", specialRequest.Type)
+			return common_error.ErrBadRequest
+		}
+
+		if specialRequest.Type == booking_repo.SpecialRequestTypeOTHER && specialRequest.Note == "" {
+			zap.S().Infoln("Special request OTHER must have a note")
+			return common_error.ErrBadRequest
+		}
+	}
+
+	return nil
+}
+
+type GetSpecialRequestsByHotelIdParams struct {
+	HotelId            pgtype.UUID
+	StartDate          pgtype.Date
+	EndDate            pgtype.Date
+	OnlyUnacknowledged bool
+}
+
+// Special requests of bookings checking in within the range, for staff to prepare the rooms
+func (bs *BookingService) GetSpecialRequestsByHotelId(ctx context.Context, params *GetSpecialRequestsByHotelIdParams) ([]booking_repo.GetSpecialRequestsByHotelIdRow, error) {
+
+	if !params.StartDate.Time.Before(params.EndDate.Time) {
+		zap.S().Infoln("Start date must be before End date")
+		return nil, common_error.ErrBadRequest
+	}
+
+	specialRequests, err := bs.repo.GetSpecialRequestsByHotelId(ctx, booking_repo.GetSpecialRequestsByHotelIdParams{
+		HotelID:            params.HotelId,
+		StartDate:          params.StartDate,
+		EndDate:            params.EndDate,
+		OnlyUnacknowledged: params.OnlyUnacknowledged,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get Special Requests by Hotel Id: ", err)
+		return nil, err
+	}
+
+	return specialRequests, nil
+}
+
+// Mark the special request as seen by hotel staff, the actor is kept as acknowledged by
+func (bs *BookingService) AcknowledgeSpecialRequest(ctx context.Context, id pgtype.UUID, hotelId pgtype.UUID) error {
+
+	actor := utils.ActorFromContext(ctx)
+	if actor == "" {
+		actor = "system"
+	}
+
+	acknowledged, err := bs.repo.AcknowledgeSpecialRequest(ctx, booking_repo.AcknowledgeSpecialRequestParams{
+		AcknowledgedBy: actor,
+		ID:             id,
+		HotelID:        hotelId,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to acknowledge Special Request: ", err)
+		return err
+	}
+
+	if acknowledged == 0 {
+		zap.S().Infoln("No Special Request to acknowledge")
+		return common_error.ErrNoRows
+	}
+
+	return nil
+}
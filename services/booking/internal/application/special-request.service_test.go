@@ -0,0 +1,61 @@
+package booking_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestValidateSpecialRequests(t *testing.T) {
+	tests := []struct {
+		name            string
+		specialRequests []SpecialRequest
+		wantErr         error
+	}{
+		{"no special request", nil, nil},
+		{"structured requests", []SpecialRequest{{Type: booking_repo.SpecialRequestTypeHIGHFLOOR}, {Type: booking_repo.SpecialRequestTypeBABYCOT, Note: "for a 1 year old"}}, nil},
+		{"free text", []SpecialRequest{{Type: booking_repo.SpecialRequestTypeOTHER, Note: "Anniversary cake"}}, nil},
+		{"free text without note", []SpecialRequest{{Type: booking_repo.SpecialRequestTypeOTHER}}, common_error.ErrBadRequest},
+		{"unknown type", []SpecialRequest{{Type: booking_repo.SpecialRequestTypeHIGHFLOOR}, {Type: "SEA_VIEW"}}, common_error.ErrBadRequest},
+		{"lowercase type", []SpecialRequest{{Type: "high_floor"}}, common_error.ErrBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSpecialRequests(tt.specialRequests); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateSpecialRequests() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetSpecialRequestsByHotelIdInvalidRange(t *testing.T) {
+	date := func(day int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+
+	tests := []struct {
+		name      string
+		startDate pgtype.Date
+		endDate   pgtype.Date
+	}{
+		{"empty range", date(2), date(2)},
+		{"end before start", date(4), date(2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := &BookingService{}
+
+			_, err := bs.GetSpecialRequestsByHotelId(context.Background(), &GetSpecialRequestsByHotelIdParams{StartDate: tt.startDate, EndDate: tt.endDate})
+			if !errors.Is(err, common_error.ErrBadRequest) {
+				t.Errorf("GetSpecialRequestsByHotelId() error = %v, want %v", err, common_error.ErrBadRequest)
+			}
+		})
+	}
+}
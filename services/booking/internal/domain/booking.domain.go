@@ -3,15 +3,90 @@ package booking_domain
 import "time"
 
 type Booking struct {
-	Id         string
-	CheckIn    time.Time
-	CheckOut   time.Time
-	Total      int
-	Status     string
-	HotelId    string
-	RoomTypeId string
-	RoomId     string
-	UserId     string
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	Id                     string
+	CheckIn                time.Time
+	CheckOut               time.Time
+	Total                  int
+	Status                 string
+	HotelId                string
+	RoomTypeId             string
+	RoomId                 string
+	UserId                 string
+	UpgradedFromRoomTypeId string // Requested room type when the guest got a complimentary upgrade
+	ConfirmationCode       string
+	Source                 string // DIRECT or the channel the booking was made on
+	CompanyId              string // Company the booking is billed to, empty when the guest pays
+	GuestName              string // Occupant of the room, empty when it is the booking user
+	GuestEmail             string
+	GuestPhone             string
+	EstimatedArrivalTime   string // HH:MM
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+}
+
+type BookingCreatedEvent struct {
+	BookingId              string    `json:"booking_id"`
+	UserId                 string    `json:"user_id"`
+	HotelId                string    `json:"hotel_id"`
+	RoomTypeId             string    `json:"room_type_id"`
+	RoomId                 string    `json:"room_id"`
+	CheckIn                time.Time `json:"check_in"`
+	CheckOut               time.Time `json:"check_out"`
+	Total                  int       `json:"total"`
+	IsUpgraded             bool      `json:"is_upgraded"`
+	UpgradedFromRoomTypeId string    `json:"upgraded_from_room_type_id"`
+	Source                 string    `json:"source"`
+	CompanyId              string    `json:"company_id"`
+	GuestName              string    `json:"guest_name"`
+	GuestEmail             string    `json:"guest_email"`
+	GuestPhone             string    `json:"guest_phone"`
+	EstimatedArrivalTime   string    `json:"estimated_arrival_time"`
+	// Requests staff should prepare the room for
+	SpecialRequests []SpecialRequest `json:"special_requests"`
+	// nil when the hotel has no policy
+	HotelPolicy *HotelPolicy `json:"hotel_policy"`
+}
+
+// Policy of the booked hotel, clock times are HH:MM local to the hotel
+type HotelPolicy struct {
+	CheckInFrom        string            `json:"check_in_from"`
+	CheckInUntil       string            `json:"check_in_until"`
+	CheckOutUntil      string            `json:"check_out_until"`
+	PetsAllowed        bool              `json:"pets_allowed"`
+	PetFee             int               `json:"pet_fee"`
+	ChildrenAllowed    bool              `json:"children_allowed"`
+	ChildrenFreeMaxAge *int              `json:"children_free_max_age"`
+	ExtraBedAvailable  bool              `json:"extra_bed_available"`
+	ExtraBedFee        int               `json:"extra_bed_fee"`
+	PaymentMethods     []string          `json:"payment_methods"`
+	HouseRules         map[string]string `json:"house_rules"` // locale -> house rules
+}
+
+// Published when the night audit moves a booking to CHECK_OUT or NO_SHOW
+type BookingStatusChangedEvent struct {
+	BookingId string    `json:"booking_id"`
+	UserId    string    `json:"user_id"`
+	HotelId   string    `json:"hotel_id"`
+	CheckIn   time.Time `json:"check_in"`
+	CheckOut  time.Time `json:"check_out"`
+	Total     int       `json:"total"`
+	Status    string    `json:"status"`
+}
+
+// Published when a transfer is requested and when the recipient answers, both users are notified
+type BookingTransferEvent struct {
+	TransferId       string `json:"transfer_id"`
+	BookingId        string `json:"booking_id"`
+	ConfirmationCode string `json:"confirmation_code"`
+	FromUserId       string `json:"from_user_id"`
+	FromUserEmail    string `json:"from_user_email"`
+	ToUserId         string `json:"to_user_id"`
+	ToUserEmail      string `json:"to_user_email"`
+	Status           string `json:"status"`
+}
+
+// HIGH_FLOOR, BABY_COT, ... or OTHER with free text in note
+type SpecialRequest struct {
+	Type string `json:"type"`
+	Note string `json:"note"`
 }
@@ -0,0 +1,49 @@
+package booking_domain
+
+import (
+	"context"
+	"time"
+)
+
+// Source of bookings made directly with the hotel
+const DIRECT_BOOKING_SOURCE = "DIRECT"
+
+// Publication status of the hotels that take bookings, from the hotel service
+const HOTEL_STATUS_PUBLISHED = "PUBLISHED"
+
+// Online travel agency (or any other distribution channel) the hotel sells rooms on
+type Channel interface {
+	// Name is saved as source of the bookings made on the channel, upper case like allotments
+	Name() string
+	PushAvailability(ctx context.Context, availabilities []ChannelAvailability) error
+	PushRates(ctx context.Context, rates []ChannelRate) error
+	// New, changed and cancelled reservations, the same reservation may be returned again
+	PullReservations(ctx context.Context) ([]ChannelReservation, error)
+}
+
+// Rooms of a room type the channel can still sell on a night
+type ChannelAvailability struct {
+	HotelId    string    `json:"hotel_id"`
+	RoomTypeId string    `json:"room_type_id"`
+	Date       time.Time `json:"date"`
+	Available  int       `json:"available"`
+}
+
+// Price of a room type per night
+type ChannelRate struct {
+	HotelId    string `json:"hotel_id"`
+	RoomTypeId string `json:"room_type_id"`
+	Price      int    `json:"price"`
+}
+
+type ChannelReservation struct {
+	ExternalId    string    `json:"external_id"` // Reservation id on the channel
+	HotelId       string    `json:"hotel_id"`
+	RoomTypeId    string    `json:"room_type_id"`
+	NumberOfRooms int       `json:"number_of_rooms"`
+	CheckIn       time.Time `json:"check_in"`
+	CheckOut      time.Time `json:"check_out"`
+	Total         int       `json:"total"`
+	GuestName     string    `json:"guest_name"`
+	Cancelled     bool      `json:"cancelled"`
+}
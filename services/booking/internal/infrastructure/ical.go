@@ -0,0 +1,610 @@
+package booking_infrastructure
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const ICAL_DATE_FORMAT = "20060102"
+const icalDateTimeFormat = "20060102T150405"
+
+// Feeds bigger than this are rejected
+const MAX_ICAL_FEED_SIZE = 5 << 20
+
+// Recurring events are expanded this far ahead, capped at MAX_ICAL_OCCURRENCES per event
+const ICAL_RECURRENCE_HORIZON_DAYS = 730
+const MAX_ICAL_OCCURRENCES = 1000
+
+var ErrIcalFeedUrlNotAllowed = errors.New("ical feed url not allowed")
+
+// VEVENT of an iCalendar (RFC 5545) feed, dates are whole nights [Start, End)
+type IcalEvent struct {
+	Uid     string
+	Start   time.Time
+	End     time.Time
+	Summary string
+}
+
+// VEVENT that could not be read, the rest of the feed is still imported
+type IcalSkippedEvent struct {
+	Uid    string
+	Reason string
+}
+
+type IcalFeedContent struct {
+	Events  []IcalEvent
+	Skipped []IcalSkippedEvent
+}
+
+// Addresses outside the special purpose ranges not covered by the net/netip checks
+var icalBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// Feeds are only fetched over https from public addresses, checked again on every connection
+// so a host cannot be pointed at an internal address after it is registered
+var icalHttpClient = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: checkIcalDialAddress,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if req.URL.Scheme != "https" {
+			return ErrIcalFeedUrlNotAllowed
+		}
+		if len(via) >= 5 {
+			return errors.New("too many ical feed redirects")
+		}
+		return nil
+	},
+}
+
+// Normalized url of an https feed whose host only resolves to public addresses
+func ValidateIcalFeedUrl(ctx context.Context, rawUrl string) (string, error) {
+
+	feedUrl, err := url.Parse(rawUrl)
+	if err != nil || feedUrl.Scheme != "https" || feedUrl.Hostname() == "" || feedUrl.User != nil {
+		return "", ErrIcalFeedUrlNotAllowed
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, feedUrl.Hostname())
+	if err != nil || len(addresses) == 0 {
+		return "", ErrIcalFeedUrlNotAllowed
+	}
+
+	for _, address := range addresses {
+		if !isPublicIcalAddress(address.IP) {
+			return "", ErrIcalFeedUrlNotAllowed
+		}
+	}
+
+	return feedUrl.String(), nil
+}
+
+func checkIcalDialAddress(network string, address string, _ syscall.RawConn) error {
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !isPublicIcalAddress(net.ParseIP(host)) {
+		return ErrIcalFeedUrlNotAllowed
+	}
+
+	return nil
+}
+
+func isPublicIcalAddress(ip net.IP) bool {
+
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range icalBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Download and parse an external .ics feed
+func FetchIcalEvents(ctx context.Context, feedUrl string) (*IcalFeedContent, error) {
+
+	if _, err := ValidateIcalFeedUrl(ctx, feedUrl); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := icalHttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ical feed returned status %d", res.StatusCode)
+	}
+
+	return ParseIcalEvents(io.LimitReader(res.Body, MAX_ICAL_FEED_SIZE), time.Now().UTC())
+}
+
+// Properties of one VEVENT before recurrence expansion
+type icalVevent struct {
+	uid          string
+	summary      string
+	status       string
+	rrule        string
+	start        time.Time
+	end          time.Time
+	recurrenceId time.Time
+	exdates      []time.Time
+	// First property that could not be read
+	err error
+}
+
+// Read VEVENTs with UID, DTSTART, DTEND, SUMMARY, STATUS, RRULE, EXDATE and RECURRENCE-ID,
+// other components and properties are ignored. Cancelled events and occurrences that ended
+// before now are left out, invalid VEVENTs are skipped and reported
+func ParseIcalEvents(r io.Reader, now time.Time) (*IcalFeedContent, error) {
+
+	lines, err := unfoldIcalLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	content := &IcalFeedContent{}
+
+	var vevents []icalVevent
+	var current *icalVevent
+	// Components nested in the VEVENT, like VALARM
+	nested := 0
+	for _, line := range lines {
+		name, params, value := splitIcalLine(line)
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = &icalVevent{}
+			nested = 0
+		case current == nil:
+			continue
+		case name == "BEGIN":
+			nested++
+		case name == "END" && nested > 0:
+			nested--
+		case nested > 0:
+			continue
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			switch {
+			case current.err != nil:
+				content.Skipped = append(content.Skipped, IcalSkippedEvent{Uid: current.uid, Reason: current.err.Error()})
+			case current.uid == "":
+				content.Skipped = append(content.Skipped, IcalSkippedEvent{Reason: "missing UID"})
+			case current.start.IsZero():
+				content.Skipped = append(content.Skipped, IcalSkippedEvent{Uid: current.uid, Reason: "missing DTSTART"})
+			default:
+				// Event without DTEND lasts one day
+				if current.end.IsZero() || !current.end.After(current.start) {
+					current.end = current.start.AddDate(0, 0, 1)
+				}
+				vevents = append(vevents, *current)
+			}
+			current = nil
+		case name == "UID":
+			current.uid = value
+		case name == "SUMMARY":
+			current.summary = unescapeIcalText(value)
+		case name == "STATUS":
+			current.status = strings.ToUpper(value)
+		case name == "RRULE":
+			current.rrule = value
+		case name == "DTSTART", name == "DTEND", name == "RECURRENCE-ID", name == "EXDATE":
+			for _, dateValue := range strings.Split(value, ",") {
+				date, err := parseIcalDate(params, dateValue)
+				if err != nil {
+					if current.err == nil {
+						current.err = fmt.Errorf("invalid %s %q: %w", name, dateValue, err)
+					}
+					break
+				}
+
+				switch name {
+				case "DTSTART":
+					current.start = date
+				case "DTEND":
+					current.end = date
+				case "RECURRENCE-ID":
+					current.recurrenceId = date
+				case "EXDATE":
+					current.exdates = append(current.exdates, date)
+				}
+			}
+		}
+	}
+
+	// Changed occurrences of recurring events, by UID and original date
+	overrides := map[string]icalVevent{}
+	for _, vevent := range vevents {
+		if !vevent.recurrenceId.IsZero() {
+			overrides[icalOccurrenceUid(vevent.uid, vevent.recurrenceId)] = vevent
+		}
+	}
+
+	addEvent := func(uid string, vevent icalVevent) {
+		if vevent.status == "CANCELLED" || !vevent.end.After(now) {
+			return
+		}
+		content.Events = append(content.Events, IcalEvent{
+			Uid:     uid,
+			Start:   vevent.start,
+			End:     vevent.end,
+			Summary: vevent.summary,
+		})
+	}
+
+	horizon := now.AddDate(0, 0, ICAL_RECURRENCE_HORIZON_DAYS)
+	for _, vevent := range vevents {
+		switch {
+		case !vevent.recurrenceId.IsZero():
+			continue
+		case vevent.rrule == "":
+			addEvent(vevent.uid, vevent)
+			continue
+		case vevent.status == "CANCELLED":
+			continue
+		}
+
+		starts, err := expandIcalRecurrence(vevent.rrule, vevent.start, vevent.exdates, horizon)
+		if err != nil {
+			content.Skipped = append(content.Skipped, IcalSkippedEvent{Uid: vevent.uid, Reason: err.Error()})
+			continue
+		}
+
+		nights := int(vevent.end.Sub(vevent.start).Hours() / 24)
+		for _, start := range starts {
+			uid := icalOccurrenceUid(vevent.uid, start)
+			if override, ok := overrides[uid]; ok {
+				delete(overrides, uid)
+				addEvent(uid, override)
+				continue
+			}
+
+			occurrence := vevent
+			occurrence.start = start
+			occurrence.end = start.AddDate(0, 0, nights)
+			addEvent(uid, occurrence)
+		}
+	}
+
+	// Changed occurrences moved off the rule or without their recurring event
+	for _, vevent := range vevents {
+		if vevent.recurrenceId.IsZero() {
+			continue
+		}
+
+		uid := icalOccurrenceUid(vevent.uid, vevent.recurrenceId)
+		if override, ok := overrides[uid]; ok {
+			delete(overrides, uid)
+			addEvent(uid, override)
+		}
+	}
+
+	return content, nil
+}
+
+func icalOccurrenceUid(uid string, date time.Time) string {
+	return uid + "#" + date.Format(ICAL_DATE_FORMAT)
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Start dates of a RRULE with FREQ, INTERVAL, COUNT, UNTIL and weekly BYDAY, up to the horizon.
+// Other rule parts are rejected instead of being imported wrong
+func expandIcalRecurrence(rule string, start time.Time, exdates []time.Time, horizon time.Time) ([]time.Time, error) {
+
+	parts := map[string]string{}
+	for _, part := range strings.Split(rule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		parts[strings.ToUpper(key)] = strings.ToUpper(value)
+	}
+
+	freq := parts["FREQ"]
+
+	interval := 1
+	count := 0
+	until := horizon
+	var weekdays []time.Weekday
+	for key, value := range parts {
+		switch key {
+		case "FREQ", "WKST":
+		case "INTERVAL":
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 {
+				return nil, fmt.Errorf("invalid RRULE INTERVAL %q", value)
+			}
+			interval = parsed
+		case "COUNT":
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 {
+				return nil, fmt.Errorf("invalid RRULE COUNT %q", value)
+			}
+			count = parsed
+		case "UNTIL":
+			parsed, err := parseIcalDate(map[string]string{}, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RRULE UNTIL %q", value)
+			}
+			if parsed.Before(until) {
+				until = parsed
+			}
+		case "BYDAY":
+			if freq != "WEEKLY" {
+				return nil, fmt.Errorf("unsupported RRULE BYDAY with FREQ %s", freq)
+			}
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := icalWeekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("unsupported RRULE BYDAY %q", day)
+				}
+				weekdays = append(weekdays, weekday)
+			}
+			slices.Sort(weekdays)
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part %s", key)
+		}
+	}
+
+	// First day of the n-th period and its candidate start dates in order
+	var period func(n int) (time.Time, []time.Time)
+	switch freq {
+	case "DAILY":
+		period = func(n int) (time.Time, []time.Time) {
+			date := start.AddDate(0, 0, n*interval)
+			return date, []time.Time{date}
+		}
+	case "WEEKLY":
+		period = func(n int) (time.Time, []time.Time) {
+			if len(weekdays) == 0 {
+				date := start.AddDate(0, 0, 7*n*interval)
+				return date, []time.Time{date}
+			}
+
+			weekStart := start.AddDate(0, 0, -int(start.Weekday())+7*n*interval)
+			dates := make([]time.Time, 0, len(weekdays))
+			for _, weekday := range weekdays {
+				if date := weekStart.AddDate(0, 0, int(weekday)); !date.Before(start) {
+					dates = append(dates, date)
+				}
+			}
+			return weekStart, dates
+		}
+	case "MONTHLY":
+		period = func(n int) (time.Time, []time.Time) {
+			monthStart := time.Date(start.Year(), start.Month()+time.Month(n*interval), 1, 0, 0, 0, 0, time.UTC)
+			// Months without the day of the start date are skipped
+			date := monthStart.AddDate(0, 0, start.Day()-1)
+			if date.Month() != monthStart.Month() {
+				return monthStart, nil
+			}
+			return monthStart, []time.Time{date}
+		}
+	case "YEARLY":
+		period = func(n int) (time.Time, []time.Time) {
+			yearStart := time.Date(start.Year()+n*interval, time.January, 1, 0, 0, 0, 0, time.UTC)
+			date := time.Date(yearStart.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+			if date.Day() != start.Day() {
+				return yearStart, nil
+			}
+			return yearStart, []time.Time{date}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported RRULE FREQ %q", freq)
+	}
+
+	var starts []time.Time
+	generated := 0
+	for n := 0; ; n++ {
+		periodStart, dates := period(n)
+		if periodStart.After(until) {
+			return starts, nil
+		}
+
+		for _, date := range dates {
+			// COUNT includes excluded dates
+			if date.After(until) || (count > 0 && generated == count) || len(starts) == MAX_ICAL_OCCURRENCES {
+				return starts, nil
+			}
+			generated++
+
+			if !slices.ContainsFunc(exdates, date.Equal) {
+				starts = append(starts, date)
+			}
+		}
+	}
+}
+
+// Write a VCALENDAR with one all-day VEVENT per event
+func WriteIcalCalendar(w io.Writer, calendarName string, events []IcalEvent) error {
+
+	stamp := time.Now().UTC().Format(icalDateTimeFormat) + "Z"
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//grpc-kafka//Booking//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + escapeIcalText(calendarName),
+	}
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.Uid,
+			"DTSTAMP:"+stamp,
+			"DTSTART;VALUE=DATE:"+event.Start.Format(ICAL_DATE_FORMAT),
+			"DTEND;VALUE=DATE:"+event.End.Format(ICAL_DATE_FORMAT),
+			"SUMMARY:"+escapeIcalText(event.Summary),
+			"TRANSP:OPAQUE",
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	bw := bufio.NewWriter(w)
+	for _, line := range lines {
+		if _, err := bw.WriteString(foldIcalLine(line) + "\r\n"); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Join continuation lines (starting with space or tab) to the previous line
+func unfoldIcalLines(r io.Reader) ([]string, error) {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MAX_ICAL_FEED_SIZE)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// "DTSTART;VALUE=DATE:20250101" -> "DTSTART", {"VALUE": "DATE"}, "20250101"
+func splitIcalLine(line string) (string, map[string]string, string) {
+
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+
+	params := map[string]string{}
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// Date or date-time, date-time is reduced to the night it falls in
+func parseIcalDate(params map[string]string, value string) (time.Time, error) {
+
+	if params["VALUE"] == "DATE" || len(value) == len(ICAL_DATE_FORMAT) {
+		return time.Parse(ICAL_DATE_FORMAT, value)
+	}
+
+	var date time.Time
+	var err error
+	if strings.HasSuffix(value, "Z") {
+		date, err = time.Parse(icalDateTimeFormat, strings.TrimSuffix(value, "Z"))
+	} else {
+		location := time.UTC
+		if tzid, ok := params["TZID"]; ok {
+			if loaded, err := time.LoadLocation(tzid); err == nil {
+				location = loaded
+			}
+		}
+		date, err = time.ParseInLocation(icalDateTimeFormat, value, location)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// Lines longer than 75 octets are split, continuation lines start with a space
+func foldIcalLine(line string) string {
+
+	if len(line) <= 75 {
+		return line
+	}
+
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		// Do not cut a multi-byte character
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line)
+
+	return b.String()
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+var icalTextUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func escapeIcalText(text string) string {
+	return icalTextEscaper.Replace(text)
+}
+
+func unescapeIcalText(text string) string {
+	return icalTextUnescaper.Replace(text)
+}
@@ -0,0 +1,100 @@
+package booking_infrastructure
+
+import (
+	"context"
+	"encoding/json"
+
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	"github.com/098765432m/grpc-kafka/common/consts"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+)
+
+type KafkaProducer struct {
+	cl *kgo.Client
+}
+
+func NewKafkaProducer(brokers []string) (*KafkaProducer, error) {
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(brokers...),
+	}
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KafkaProducer{
+		cl: client,
+	}, nil
+}
+
+func (kp *KafkaProducer) Close() {
+	kp.cl.Close()
+}
+
+// Publish event without waiting for broker, failures are only logged.
+// The record outlives the request, so it must not be cancelled with the request context
+func (kp *KafkaProducer) PublishBookingCreated(ctx context.Context, event booking_domain.BookingCreatedEvent) {
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		zap.S().Errorln("Failed to marshal Booking Created event: ", err)
+		return
+	}
+
+	record := &kgo.Record{
+		Topic: consts.BOOKING_CREATED_TOPIC,
+		Key:   []byte(event.BookingId),
+		Value: value,
+	}
+
+	kp.cl.Produce(context.WithoutCancel(ctx), record, func(r *kgo.Record, err error) {
+		if err != nil {
+			zap.S().Errorln("Failed to produce Booking Created event: ", err)
+		}
+	})
+}
+
+func (kp *KafkaProducer) PublishBookingStatusChanged(ctx context.Context, event booking_domain.BookingStatusChangedEvent) {
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		zap.S().Errorln("Failed to marshal Booking Status Changed event: ", err)
+		return
+	}
+
+	record := &kgo.Record{
+		Topic: consts.BOOKING_STATUS_CHANGED_TOPIC,
+		Key:   []byte(event.BookingId),
+		Value: value,
+	}
+
+	kp.cl.Produce(context.WithoutCancel(ctx), record, func(r *kgo.Record, err error) {
+		if err != nil {
+			zap.S().Errorln("Failed to produce Booking Status Changed event: ", err)
+		}
+	})
+}
+
+func (kp *KafkaProducer) PublishBookingTransfer(ctx context.Context, event booking_domain.BookingTransferEvent) {
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		zap.S().Errorln("Failed to marshal Booking Transfer event: ", err)
+		return
+	}
+
+	record := &kgo.Record{
+		Topic: consts.BOOKING_TRANSFER_TOPIC,
+		Key:   []byte(event.BookingId),
+		Value: value,
+	}
+
+	kp.cl.Produce(context.WithoutCancel(ctx), record, func(r *kgo.Record, err error) {
+		if err != nil {
+			zap.S().Errorln("Failed to produce Booking Transfer event: ", err)
+		}
+	})
+}
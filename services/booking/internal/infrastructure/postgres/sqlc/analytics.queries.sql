@@ -0,0 +1,40 @@
+-- name: GetRoomNightsSoldByPeriod :many
+-- Split every stay into nights inside the range, revenue of a night is the booking total spread over its nights.
+-- Night n of a stay earns total * (n + 1) / nights - total * n / nights, so the nights of a stay add up to its total
+SELECT
+    date_trunc(@granularity::text, night)::date AS period_start,
+    b.hotel_id,
+    b.room_type_id,
+    COUNT(*)::int AS room_nights_sold,
+    COALESCE(SUM(
+        b.total::bigint * (night::date - b.check_in + 1) / GREATEST(b.check_out - b.check_in, 1)
+        - b.total::bigint * (night::date - b.check_in) / GREATEST(b.check_out - b.check_in, 1)
+    ), 0)::bigint AS room_revenue
+FROM generate_series(@start_date::date, @end_date::date - 1, '1 day') AS night
+JOIN bookings b ON
+    b.check_in <= night
+    AND b.check_out > night
+WHERE
+    b.hotel_id = ANY(@hotel_ids::uuid[])
+    AND b.status <> 'NO_SHOW'
+    AND b.deleted_at IS NULL
+GROUP BY period_start, b.hotel_id, b.room_type_id
+ORDER BY period_start, b.hotel_id, b.room_type_id;
+
+-- name: GetBookingPaceByPeriod :many
+-- Bookings picked up in each period of the range, by the date they were made
+SELECT
+    date_trunc(@granularity::text, b.create_at)::date AS period_start,
+    b.hotel_id,
+    b.room_type_id,
+    COUNT(*)::int AS bookings_created,
+    COALESCE(SUM(b.check_out - b.check_in), 0)::int AS room_nights_booked,
+    COALESCE(SUM(b.total), 0)::bigint AS booked_revenue
+FROM bookings b
+WHERE
+    b.hotel_id = ANY(@hotel_ids::uuid[])
+    AND b.deleted_at IS NULL
+    AND b.create_at >= @start_date::date
+    AND b.create_at < @end_date::date
+GROUP BY period_start, b.hotel_id, b.room_type_id
+ORDER BY period_start, b.hotel_id, b.room_type_id;
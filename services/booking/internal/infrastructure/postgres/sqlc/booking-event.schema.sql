@@ -0,0 +1,78 @@
+CREATE TYPE BOOKING_EVENT_TYPE AS ENUM ('CREATED', 'UPDATED', 'STATUS_CHANGED', 'CANCELLED', 'TRANSFERRED');
+
+-- Append-only history of bookings, kept after the booking is purged
+CREATE TABLE booking_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    event_type BOOKING_EVENT_TYPE NOT NULL,
+    -- Who made the change, from x-actor gRPC metadata or 'system'
+    actor TEXT NOT NULL DEFAULT 'system',
+    -- Changed fields as {"field": {"old": ..., "new": ...}}
+    changes JSONB NOT NULL DEFAULT '{}',
+    reason TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX booking_events_booking_id_idx ON booking_events (booking_id, create_at);
+
+CREATE FUNCTION prevent_booking_event_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'booking_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER booking_events_append_only
+BEFORE UPDATE OR DELETE ON booking_events
+FOR EACH ROW EXECUTE FUNCTION prevent_booking_event_change();
+
+-- Actor and reason are set per transaction with set_config('booking.actor' / 'booking.reason')
+CREATE FUNCTION record_booking_event() RETURNS TRIGGER AS $$
+DECLARE
+    new_event_type BOOKING_EVENT_TYPE;
+    changed_fields JSONB;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        new_event_type := 'CREATED';
+        SELECT jsonb_object_agg(n.key, jsonb_build_object('old', NULL, 'new', n.value))
+        INTO changed_fields
+        FROM jsonb_each(to_jsonb(NEW)) n
+        WHERE n.key NOT IN ('create_at', 'updated_at');
+    ELSE
+        SELECT jsonb_object_agg(n.key, jsonb_build_object('old', o.value, 'new', n.value))
+        INTO changed_fields
+        FROM jsonb_each(to_jsonb(NEW)) n
+        JOIN jsonb_each(to_jsonb(OLD)) o ON o.key = n.key
+        WHERE n.value IS DISTINCT FROM o.value
+            AND n.key NOT IN ('create_at', 'updated_at');
+
+        IF changed_fields IS NULL THEN
+            RETURN NEW;
+        END IF;
+
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            new_event_type := 'CANCELLED';
+        ELSIF OLD.status IS DISTINCT FROM NEW.status THEN
+            new_event_type := 'STATUS_CHANGED';
+        ELSIF OLD.user_id IS DISTINCT FROM NEW.user_id THEN
+            new_event_type := 'TRANSFERRED';
+        ELSE
+            new_event_type := 'UPDATED';
+        END IF;
+    END IF;
+
+    INSERT INTO booking_events (booking_id, event_type, actor, changes, reason)
+    VALUES (
+        NEW.id,
+        new_event_type,
+        COALESCE(NULLIF(current_setting('booking.actor', true), ''), 'system'),
+        COALESCE(changed_fields, '{}'),
+        NULLIF(current_setting('booking.reason', true), '')
+    );
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bookings_record_event
+AFTER INSERT OR UPDATE ON bookings
+FOR EACH ROW EXECUTE FUNCTION record_booking_event();
@@ -0,0 +1,49 @@
+-- name: CreateBookingTransfer :one
+INSERT INTO booking_transfers
+(
+    booking_id,
+    from_user_id,
+    to_user_id,
+    requested_by
+)
+VALUES
+(
+    @booking_id::uuid,
+    @from_user_id::uuid,
+    @to_user_id::uuid,
+    @requested_by::text
+)
+RETURNING *;
+
+-- name: GetBookingTransferById :one
+SELECT *
+FROM booking_transfers
+WHERE id = @id::uuid;
+
+-- name: CancelPendingBookingTransfer :execrows
+UPDATE booking_transfers
+SET
+    status = 'CANCELLED',
+    responded_at = CURRENT_TIMESTAMP
+WHERE booking_id = @booking_id::uuid AND status = 'PENDING';
+
+-- name: RespondBookingTransfer :one
+-- Only a pending transfer can be answered, once
+UPDATE booking_transfers
+SET
+    status = @status::BOOKING_TRANSFER_STATUS,
+    responded_at = CURRENT_TIMESTAMP
+WHERE id = @id::uuid AND status = 'PENDING'
+RETURNING *;
+
+-- name: TransferBookingOwner :execrows
+-- The booking must still belong to the sender and the guest has not arrived yet
+UPDATE bookings
+SET
+    user_id = @to_user_id::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = @id::uuid
+    AND user_id = @from_user_id::uuid
+    AND status = 'BOOKED'
+    AND deleted_at IS NULL;
@@ -0,0 +1,19 @@
+CREATE TYPE BOOKING_TRANSFER_STATUS AS ENUM ('PENDING', 'ACCEPTED', 'DECLINED', 'CANCELLED');
+
+-- Booking moved to another user account, the booking changes owner when the recipient accepts
+CREATE TABLE booking_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    from_user_id UUID NOT NULL,
+    to_user_id UUID NOT NULL,
+    status BOOKING_TRANSFER_STATUS NOT NULL DEFAULT 'PENDING',
+    -- Owner of the booking or an admin, from x-actor gRPC metadata
+    requested_by TEXT NOT NULL,
+    responded_at TIMESTAMP,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE
+);
+
+-- A new transfer cancels the pending one of the booking
+CREATE UNIQUE INDEX booking_transfers_pending_idx ON booking_transfers (booking_id) WHERE status = 'PENDING';
+CREATE INDEX booking_transfers_to_user_id_idx ON booking_transfers (to_user_id, status);
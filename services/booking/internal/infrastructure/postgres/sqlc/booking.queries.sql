@@ -1,47 +1,83 @@
 -- name: GetBookingById :one
-SELECT * FROM bookings WHERE id = $1;
+SELECT * FROM bookings WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetBookingByConfirmationCode :one
+SELECT * FROM bookings WHERE confirmation_code = upper(@confirmation_code::text) AND deleted_at IS NULL;
 
 -- name: GetBookingsByRoomId :many
-SELECT * FROM bookings WHERE room_id = $1;
+SELECT * FROM bookings WHERE room_id = $1 AND deleted_at IS NULL;
 
--- name: GetBookingsByUserId :many
-SELECT 
-    * 
-FROM bookings b
-WHERE 
-    b.user_id = @user_id::uuid
-    AND 
-    (
-        @check_date_start::date IS NULL
-        OR @check_date_end::date IS NULL
-        OR b.check_in BETWEEN @check_date_start AND @check_date_start
-    )
-ORDER BY b.check_in
-LIMIT @size::int
-OFFSET @number_of_offset::int;
+-- name: GetBookingIdsByHotelId :many
+-- Stays of the hotel that are not over yet and can still be cancelled, finished stays are kept as records
+SELECT id
+FROM bookings
+WHERE
+    hotel_id = $1
+    AND status = 'BOOKED'
+    AND check_out > CURRENT_DATE
+    AND deleted_at IS NULL;
+
+-- name: GetCompanyBilledTotals :many
+-- Total of not cancelled bookings per company they are billed to
+SELECT
+    company_id,
+    SUM(total)::int AS total
+FROM bookings
+WHERE
+    id = ANY(@booking_ids::uuid[])
+    AND company_id IS NOT NULL
+    AND deleted_at IS NULL
+GROUP BY company_id;
+
+-- name: GetCompanyBookingsTotal :one
+-- Total of the bookings billed to the company checking out within [start_date, end_date)
+SELECT COALESCE(SUM(total), 0)::bigint AS total
+FROM bookings
+WHERE
+    company_id = @company_id::uuid
+    AND deleted_at IS NULL
+    AND check_out >= @start_date::date
+    AND check_out < @end_date::date;
 
 -- name: GetNumberOfOccupiedRooms :many
 SELECT 
     room_type_id,
-    COUNT(DISTINCT room_id) AS number_of_occupied_rooms
-FROM bookings 
+    -- Unassigned (overbooked) bookings hold inventory without a room
+    (COUNT(DISTINCT room_id) + COUNT(*) FILTER (WHERE room_id IS NULL))::bigint AS number_of_occupied_rooms
+FROM (
+    SELECT hotel_id, room_type_id, room_id, check_in, check_out
+    FROM bookings
+    WHERE deleted_at IS NULL
+    UNION ALL
+    -- Rooms blocked by imported iCal feeds
+    SELECT hotel_id, room_type_id, room_id, start_date, end_date
+    FROM room_blocks
+) occupied
 WHERE
     room_type_id = ANY(@room_type_ids::uuid[])
     -- AND ( date_trunc('day', @new_check_in::date) < date_trunc('day', check_out) AND date_trunc('day', @new_check_out::date) > date_trunc('day', check_in) );
     -- AND (@new_check_in::date < check_out::date AND @new_check_out::date > check_in::date)
-    AND daterange(check_in, check_out, '[]') && daterange(@check_in::date, @check_out::date, '[]')
+    AND daterange(check_in, check_out, '[)') && daterange(@check_in::date, @check_out::date, '[)')
 GROUP BY room_type_id;
 
 -- name: GetNumberOfOccupiedRoomsByHotelIds :many
 SELECT
     room_type_id,
-    COUNT(DISTINCT room_id) AS number_of_occupied_rooms
-FROM bookings 
+    (COUNT(DISTINCT room_id) + COUNT(*) FILTER (WHERE room_id IS NULL))::bigint AS number_of_occupied_rooms
+FROM (
+    SELECT hotel_id, room_type_id, room_id, check_in, check_out
+    FROM bookings
+    WHERE deleted_at IS NULL
+    UNION ALL
+    -- Rooms blocked by imported iCal feeds
+    SELECT hotel_id, room_type_id, room_id, start_date, end_date
+    FROM room_blocks
+) occupied
 WHERE
     hotel_id = ANY(@hotel_ids::uuid[])
     -- AND ( date_trunc('day', @new_check_in::date) < date_trunc('day', check_out) AND date_trunc('day', @new_check_out::date) > date_trunc('day', check_in) );
     -- AND (@new_check_in::date < check_out::date AND @new_check_out::date > check_in::date)
-    AND daterange(check_in, check_out, '[]') && daterange(@check_in::date, @check_out::date, '[]')
+    AND daterange(check_in, check_out, '[)') && daterange(@check_in::date, @check_out::date, '[)')
 GROUP BY room_type_id;
 
 -- name: GetUnavailableRoomsByRoomTypeId :many
@@ -49,29 +85,133 @@ SELECT room_id
 FROM bookings
 WHERE 
     room_type_id = @room_type_id::uuid
-    AND daterange(check_in, check_out, '[]') && daterange(@check_in::date, @check_out::date, '[]');
-
--- name: CreateBooking :exec
-INSERT INTO bookings (
-    check_in,
-    check_out,
-    total,
-    status,
-    room_type_id,
-    user_id,
-    room_id
-) VALUES (
-    @check_in::date,
-    @check_out::date,
-    @total::int,
-    @status::BOOKING_STATUS,
-    @room_type_id::uuid,
-    @user_id::uuid,
-    @room_id::uuid
-);
-
--- name: DeleteBookingById :exec
-DELETE FROM bookings WHERE id = $1;
-
--- name: DeleteBookingsByIds :exec
-DELETE FROM bookings WHERE ids = ANY(@booking_ids::uuid[]);
\ No newline at end of file
+    AND room_id IS NOT NULL
+    AND deleted_at IS NULL
+    AND daterange(check_in, check_out, '[)') && daterange(@check_in::date, @check_out::date, '[)')
+UNION
+SELECT room_id
+FROM room_blocks
+WHERE
+    room_type_id = @room_type_id::uuid
+    AND daterange(start_date, end_date, '[)') && daterange(@check_in::date, @check_out::date, '[)');
+
+-- name: GetAdjacentBookedRoomsByRoomTypeId :many
+-- Rooms that have a booking ending on check in or starting on check out of the new stay
+SELECT
+    room_id,
+    bool_or(check_out = @check_in::date)::boolean AS touches_check_in,
+    bool_or(check_in = @check_out::date)::boolean AS touches_check_out
+FROM bookings
+WHERE
+    room_type_id = @room_type_id::uuid
+    AND room_id IS NOT NULL
+    AND deleted_at IS NULL
+    AND (check_out = @check_in::date OR check_in = @check_out::date)
+GROUP BY room_id;
+
+-- name: GetMaxUnassignedBookingsPerNight :one
+SELECT COALESCE(MAX(n.number_of_unassigned_bookings), 0)::int AS max_unassigned_bookings
+FROM (
+    SELECT
+        night,
+        COUNT(b.id) AS number_of_unassigned_bookings
+    FROM generate_series(@check_in::date, @check_out::date - 1, '1 day') AS night
+    LEFT JOIN bookings b ON
+        b.room_type_id = @room_type_id::uuid
+        AND b.room_id IS NULL
+        AND b.deleted_at IS NULL
+        AND b.check_in <= night
+        AND b.check_out > night
+    GROUP BY night
+) n;
+
+-- name: LockRoomTypeOverbooking :exec
+SELECT pg_advisory_xact_lock(hashtextextended(@room_type_id::uuid::text, 0));
+
+-- name: GetOversoldNights :many
+SELECT
+    night::date AS night,
+    b.room_type_id,
+    COUNT(b.id) AS number_of_unassigned_bookings,
+    array_agg(b.id)::uuid[] AS booking_ids
+FROM generate_series(@start_date::date, @end_date::date - 1, '1 day') AS night
+JOIN bookings b ON
+    b.check_in <= night
+    AND b.check_out > night
+WHERE
+    b.hotel_id = @hotel_id::uuid
+    AND b.room_id IS NULL
+    AND b.deleted_at IS NULL
+GROUP BY night, b.room_type_id
+ORDER BY night, b.room_type_id;
+
+-- name: CountRoomConflicts :one
+-- Other bookings and iCal blocks of the room overlapping the stay
+SELECT COUNT(*)::int AS number_of_conflicts
+FROM (
+    SELECT id
+    FROM bookings
+    WHERE
+        room_id = @room_id::uuid
+        AND id <> @booking_id::uuid
+        AND deleted_at IS NULL
+        AND daterange(check_in, check_out, '[)') && daterange(@check_in::date, @check_out::date, '[)')
+    UNION ALL
+    SELECT id
+    FROM room_blocks
+    WHERE
+        room_id = @room_id::uuid
+        AND daterange(start_date, end_date, '[)') && daterange(@check_in::date, @check_out::date, '[)')
+) conflicts;
+
+-- name: DeleteBookingById :execrows
+-- Soft delete, the booking is kept until the retention period ends
+UPDATE bookings
+SET
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: UpdateBookingDates :execrows
+UPDATE bookings
+SET
+    check_in = @check_in::date,
+    check_out = @check_out::date,
+    total = @total::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id::uuid AND deleted_at IS NULL;
+
+-- name: DeleteBookingsByIds :execrows
+-- Only stays not started yet can be cancelled, guests in house are not refunded
+UPDATE bookings
+SET
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ANY(@booking_ids::uuid[])
+    AND status = 'BOOKED'
+    AND deleted_at IS NULL;
+
+-- name: PurgeDeletedBookings :execrows
+-- Hard delete bookings soft deleted before the retention cut off, their events are kept.
+-- Bookings with payments or posted room nights are financial records and are never purged
+DELETE FROM bookings b
+WHERE
+    b.deleted_at IS NOT NULL
+    AND b.deleted_at < @deleted_before::timestamp
+    AND NOT EXISTS (SELECT 1 FROM booking_payments p WHERE p.booking_id = b.id)
+    AND NOT EXISTS (SELECT 1 FROM room_night_revenues r WHERE r.booking_id = b.id);
+
+-- name: GetBookingOwnerById :one
+-- Cancelled bookings included, their history is still readable
+SELECT user_id, hotel_id
+FROM bookings
+WHERE id = @id::uuid;
+
+-- name: LockBookingsByIds :many
+-- Bookings being cancelled are locked until commit, so access is checked on the rows that are deleted
+SELECT id, user_id, hotel_id, status
+FROM bookings
+WHERE id = ANY(@booking_ids::uuid[]) AND deleted_at IS NULL
+ORDER BY id
+FOR UPDATE;
\ No newline at end of file
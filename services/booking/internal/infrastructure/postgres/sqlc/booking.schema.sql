@@ -1,4 +1,7 @@
-CREATE TYPE BOOKING_STATUS AS ENUM ('BOOKED', 'CHECK_IN' ,'PAID');
+-- UUID equality in the room overlap exclusion constraint
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TYPE BOOKING_STATUS AS ENUM ('BOOKED', 'CHECK_IN' ,'PAID', 'CHECK_OUT', 'NO_SHOW');
 
 CREATE TABLE bookings (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -8,8 +11,29 @@ CREATE TABLE bookings (
     status BOOKING_STATUS NOT NULL DEFAULT 'BOOKED',
     hotel_id UUID NOT NULL,
     room_type_id UUID NOT NULL,
-    user_id UUID NOT NULL,
-    room_id UUID NOT NULL,
+    -- Empty for reservations made on a channel
+    user_id UUID,
+    room_id UUID,
+    -- Requested room type when the guest got a complimentary upgrade
+    upgraded_from_room_type_id UUID,
+    -- Short code given to the guest to find the booking, generated by the service from a crypto random source
+    confirmation_code VARCHAR(16) NOT NULL UNIQUE,
+    -- DIRECT or the channel the booking was made on
+    source VARCHAR(32) NOT NULL DEFAULT 'DIRECT',
+    -- Company the booking is billed to, empty when the guest pays
+    company_id UUID,
+    -- Occupant of the room when it is not the booking user, all optional
+    guest_name VARCHAR(255),
+    guest_email VARCHAR(255),
+    guest_phone VARCHAR(32),
+    estimated_arrival_time TIME,
+    -- Soft deleted (cancelled) bookings are purged after the retention period
+    deleted_at TIMESTAMP,
     create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- A room is never booked twice for the same night, even by concurrent transactions
+    CONSTRAINT bookings_room_no_overlap EXCLUDE USING gist (
+        room_id WITH =,
+        daterange(check_in, check_out, '[)') WITH &&
+    ) WHERE (room_id IS NOT NULL AND deleted_at IS NULL)
 );
\ No newline at end of file
@@ -1,4 +1,7 @@
-CREATE TYPE BOOKING_STATUS AS ENUM ('BOOKED', 'CHECK_IN' ,'PAID');
+-- UUID equality in the room overlap exclusion constraint
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TYPE BOOKING_STATUS AS ENUM ('BOOKED', 'CHECK_IN' ,'PAID', 'CHECK_OUT', 'NO_SHOW');
 
 CREATE TABLE bookings (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -8,8 +11,297 @@ CREATE TABLE bookings (
     status BOOKING_STATUS NOT NULL DEFAULT 'BOOKED',
     hotel_id UUID NOT NULL,
     room_type_id UUID NOT NULL,
-    user_id UUID NOT NULL,
+    -- Empty for reservations made on a channel
+    user_id UUID,
+    room_id UUID,
+    -- Requested room type when the guest got a complimentary upgrade
+    upgraded_from_room_type_id UUID,
+    -- Short code given to the guest to find the booking, generated by the service from a crypto random source
+    confirmation_code VARCHAR(16) NOT NULL UNIQUE,
+    -- DIRECT or the channel the booking was made on
+    source VARCHAR(32) NOT NULL DEFAULT 'DIRECT',
+    -- Company the booking is billed to, empty when the guest pays
+    company_id UUID,
+    -- Occupant of the room when it is not the booking user, all optional
+    guest_name VARCHAR(255),
+    guest_email VARCHAR(255),
+    guest_phone VARCHAR(32),
+    estimated_arrival_time TIME,
+    -- Soft deleted (cancelled) bookings are purged after the retention period
+    deleted_at TIMESTAMP,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- A room is never booked twice for the same night, even by concurrent transactions
+    CONSTRAINT bookings_room_no_overlap EXCLUDE USING gist (
+        room_id WITH =,
+        daterange(check_in, check_out, '[)') WITH &&
+    ) WHERE (room_id IS NOT NULL AND deleted_at IS NULL)
+);
+
+-- Time of day each hotel closes its business date
+CREATE TABLE hotel_audit_settings (
+    hotel_id UUID PRIMARY KEY,
+    audit_time TIME NOT NULL DEFAULT '23:00',
+    last_audit_date DATE,
+    timezone TEXT NOT NULL DEFAULT 'UTC'
+);
+
+-- One room night revenue per booking and business date, so re-running audit does not post twice
+CREATE TABLE room_night_revenues (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    hotel_id UUID NOT NULL,
+    business_date DATE NOT NULL,
+    amount INT NOT NULL,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (booking_id, business_date),
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE night_audit_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hotel_id UUID NOT NULL,
+    business_date DATE NOT NULL,
+    no_shows INT NOT NULL DEFAULT 0,
+    check_outs INT NOT NULL DEFAULT 0,
+    room_nights INT NOT NULL DEFAULT 0,
+    room_revenue BIGINT NOT NULL DEFAULT 0,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (hotel_id, business_date)
+);
+
+CREATE TYPE BOOKING_EVENT_TYPE AS ENUM ('CREATED', 'UPDATED', 'STATUS_CHANGED', 'CANCELLED', 'TRANSFERRED');
+
+-- Append-only history of bookings, kept after the booking is purged
+CREATE TABLE booking_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    event_type BOOKING_EVENT_TYPE NOT NULL,
+    -- Who made the change, from x-actor gRPC metadata or 'system'
+    actor TEXT NOT NULL DEFAULT 'system',
+    -- Changed fields as {"field": {"old": ..., "new": ...}}
+    changes JSONB NOT NULL DEFAULT '{}',
+    reason TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX booking_events_booking_id_idx ON booking_events (booking_id, create_at);
+
+CREATE FUNCTION prevent_booking_event_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'booking_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER booking_events_append_only
+BEFORE UPDATE OR DELETE ON booking_events
+FOR EACH ROW EXECUTE FUNCTION prevent_booking_event_change();
+
+-- Actor and reason are set per transaction with set_config('booking.actor' / 'booking.reason')
+CREATE FUNCTION record_booking_event() RETURNS TRIGGER AS $$
+DECLARE
+    new_event_type BOOKING_EVENT_TYPE;
+    changed_fields JSONB;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        new_event_type := 'CREATED';
+        SELECT jsonb_object_agg(n.key, jsonb_build_object('old', NULL, 'new', n.value))
+        INTO changed_fields
+        FROM jsonb_each(to_jsonb(NEW)) n
+        WHERE n.key NOT IN ('create_at', 'updated_at');
+    ELSE
+        SELECT jsonb_object_agg(n.key, jsonb_build_object('old', o.value, 'new', n.value))
+        INTO changed_fields
+        FROM jsonb_each(to_jsonb(NEW)) n
+        JOIN jsonb_each(to_jsonb(OLD)) o ON o.key = n.key
+        WHERE n.value IS DISTINCT FROM o.value
+            AND n.key NOT IN ('create_at', 'updated_at');
+
+        IF changed_fields IS NULL THEN
+            RETURN NEW;
+        END IF;
+
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            new_event_type := 'CANCELLED';
+        ELSIF OLD.status IS DISTINCT FROM NEW.status THEN
+            new_event_type := 'STATUS_CHANGED';
+        ELSIF OLD.user_id IS DISTINCT FROM NEW.user_id THEN
+            new_event_type := 'TRANSFERRED';
+        ELSE
+            new_event_type := 'UPDATED';
+        END IF;
+    END IF;
+
+    INSERT INTO booking_events (booking_id, event_type, actor, changes, reason)
+    VALUES (
+        NEW.id,
+        new_event_type,
+        COALESCE(NULLIF(current_setting('booking.actor', true), ''), 'system'),
+        COALESCE(changed_fields, '{}'),
+        NULLIF(current_setting('booking.reason', true), '')
+    );
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bookings_record_event
+AFTER INSERT OR UPDATE ON bookings
+FOR EACH ROW EXECUTE FUNCTION record_booking_event();
+
+-- External iCalendar feeds imported per room
+CREATE TABLE ical_feeds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     room_id UUID NOT NULL,
+    room_type_id UUID NOT NULL,
+    hotel_id UUID NOT NULL,
+    url TEXT NOT NULL,
+    last_synced_at TIMESTAMP,
+    last_error TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (room_id, url)
+);
+
+-- Nights [start_date, end_date) a room is not available, one row per VEVENT UID of a feed
+CREATE TABLE room_blocks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    feed_id UUID NOT NULL,
+    room_id UUID NOT NULL,
+    room_type_id UUID NOT NULL,
+    hotel_id UUID NOT NULL,
+    uid TEXT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (feed_id, uid),
+    FOREIGN KEY (feed_id) REFERENCES ical_feeds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX room_blocks_room_id_idx ON room_blocks (room_id, start_date);
+
+CREATE TYPE CHANNEL_RESERVATION_STATUS AS ENUM ('ACCEPTED', 'REJECTED', 'CANCELLED');
+
+-- Rooms of a room type a channel may sell per night
+CREATE TABLE channel_allotments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    channel VARCHAR(32) NOT NULL,
+    hotel_id UUID NOT NULL,
+    room_type_id UUID NOT NULL,
+    allotment INT NOT NULL CHECK (allotment >= 0),
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (channel, room_type_id)
+);
+
+-- Reservations pulled from channels, a reservation is only processed once
+CREATE TABLE channel_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    channel VARCHAR(32) NOT NULL,
+    external_id VARCHAR(64) NOT NULL,
+    hotel_id UUID NOT NULL,
+    status CHANNEL_RESERVATION_STATUS NOT NULL,
+    booking_ids UUID[] NOT NULL DEFAULT '{}',
+    error TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (channel, external_id)
+);
+
+CREATE TYPE SPECIAL_REQUEST_TYPE AS ENUM ('HIGH_FLOOR', 'LOW_FLOOR', 'QUIET_ROOM', 'BABY_COT', 'EXTRA_BED', 'ACCESSIBLE_ROOM', 'EARLY_CHECK_IN', 'LATE_CHECK_IN', 'OTHER');
+
+-- Requests of the guest on a booking line, OTHER is free text in note
+CREATE TABLE booking_special_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    hotel_id UUID NOT NULL,
+    type SPECIAL_REQUEST_TYPE NOT NULL DEFAULT 'OTHER',
+    note TEXT NOT NULL DEFAULT '',
+    -- Hotel staff saw the request, acknowledged_by is the actor
+    acknowledged_at TIMESTAMP,
+    acknowledged_by TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE
+);
+
+CREATE INDEX booking_special_requests_booking_id_idx ON booking_special_requests (booking_id);
+
+CREATE TYPE BOOKING_TRANSFER_STATUS AS ENUM ('PENDING', 'ACCEPTED', 'DECLINED', 'CANCELLED');
+
+-- Booking moved to another user account, the booking changes owner when the recipient accepts
+CREATE TABLE booking_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    from_user_id UUID NOT NULL,
+    to_user_id UUID NOT NULL,
+    status BOOKING_TRANSFER_STATUS NOT NULL DEFAULT 'PENDING',
+    -- Owner of the booking or an admin, from x-actor gRPC metadata
+    requested_by TEXT NOT NULL,
+    responded_at TIMESTAMP,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE
+);
+
+-- A new transfer cancels the pending one of the booking
+CREATE UNIQUE INDEX booking_transfers_pending_idx ON booking_transfers (booking_id) WHERE status = 'PENDING';
+CREATE INDEX booking_transfers_to_user_id_idx ON booking_transfers (to_user_id, status);
+
+CREATE TYPE GIFT_CARD_STATUS AS ENUM ('ACTIVE', 'VOIDED');
+CREATE TYPE GIFT_CARD_TRANSACTION_TYPE AS ENUM ('ISSUE', 'REDEEM', 'REFUND', 'VOID');
+
+CREATE TABLE gift_cards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- XXXX-XXXX-XXXX-XXXX given to the buyer
+    code VARCHAR(19) NOT NULL UNIQUE,
+    initial_balance INT NOT NULL CHECK (initial_balance > 0),
+    -- Sum of the ledger, decremented with a conditional update so concurrent redemptions cannot overdraw
+    balance INT NOT NULL CHECK (balance >= 0),
+    status GIFT_CARD_STATUS NOT NULL DEFAULT 'ACTIVE',
+    expires_at TIMESTAMP NOT NULL,
+    issued_by TEXT NOT NULL,
     create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+);
+
+-- Append-only ledger of gift card balances, amount is the signed change of the balance
+CREATE TABLE gift_card_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gift_card_id UUID NOT NULL,
+    type GIFT_CARD_TRANSACTION_TYPE NOT NULL,
+    amount INT NOT NULL,
+    booking_id UUID,
+    actor TEXT NOT NULL DEFAULT 'system',
+    note TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (gift_card_id) REFERENCES gift_cards(id)
+);
+
+CREATE INDEX gift_card_transactions_gift_card_id_idx ON gift_card_transactions (gift_card_id, create_at);
+
+CREATE FUNCTION prevent_gift_card_transaction_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'gift_card_transactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER gift_card_transactions_append_only
+BEFORE UPDATE OR DELETE ON gift_card_transactions
+FOR EACH ROW EXECUTE FUNCTION prevent_gift_card_transaction_change();
+
+CREATE TYPE PAYMENT_METHOD AS ENUM ('GIFT_CARD');
+
+-- Payments against a booking, refunded when the booking is cancelled
+CREATE TABLE booking_payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    method PAYMENT_METHOD NOT NULL,
+    amount INT NOT NULL CHECK (amount > 0),
+    gift_card_id UUID,
+    refunded_at TIMESTAMP,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX booking_payments_booking_id_idx ON booking_payments (booking_id);
@@ -0,0 +1,108 @@
+-- name: UpsertChannelAllotment :one
+INSERT INTO channel_allotments
+(
+    channel,
+    hotel_id,
+    room_type_id,
+    allotment
+)
+VALUES
+(
+    @channel::text,
+    @hotel_id::uuid,
+    @room_type_id::uuid,
+    @allotment::int
+)
+ON CONFLICT (channel, room_type_id) DO UPDATE
+SET
+    allotment = EXCLUDED.allotment,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetChannelAllotmentsByHotelId :many
+SELECT *
+FROM channel_allotments
+WHERE hotel_id = @hotel_id::uuid
+ORDER BY channel, room_type_id;
+
+-- name: GetChannelAllotmentsByChannel :many
+SELECT *
+FROM channel_allotments
+WHERE channel = @channel::text
+ORDER BY hotel_id, room_type_id;
+
+-- name: GetChannelAllotment :one
+SELECT *
+FROM channel_allotments
+WHERE channel = @channel::text AND room_type_id = @room_type_id::uuid;
+
+-- name: DeleteChannelAllotmentById :execrows
+DELETE FROM channel_allotments WHERE id = @id::uuid AND hotel_id = @hotel_id::uuid;
+
+-- name: GetOccupiedRoomsPerNight :many
+-- Booked and blocked rooms of each room type on each night of the range
+SELECT
+    night::date AS night,
+    o.room_type_id,
+    (COUNT(DISTINCT o.room_id) + COUNT(*) FILTER (WHERE o.room_id IS NULL))::int AS number_of_occupied_rooms
+FROM generate_series(@start_date::date, @end_date::date - 1, '1 day') AS night
+JOIN (
+    SELECT room_type_id, room_id, check_in, check_out
+    FROM bookings
+    WHERE deleted_at IS NULL
+    UNION ALL
+    SELECT room_type_id, room_id, start_date, end_date
+    FROM room_blocks
+) o ON
+    o.check_in <= night
+    AND o.check_out > night
+WHERE o.room_type_id = ANY(@room_type_ids::uuid[])
+GROUP BY night, o.room_type_id;
+
+-- name: GetChannelRoomsSoldPerNight :many
+-- Rooms of each room type a channel sold on each night of the range
+SELECT
+    night::date AS night,
+    b.room_type_id,
+    COUNT(b.id)::int AS number_of_rooms_sold
+FROM generate_series(@start_date::date, @end_date::date - 1, '1 day') AS night
+JOIN bookings b ON
+    b.check_in <= night
+    AND b.check_out > night
+WHERE
+    b.source = @source::text
+    AND b.room_type_id = ANY(@room_type_ids::uuid[])
+    AND b.deleted_at IS NULL
+GROUP BY night, b.room_type_id;
+
+-- name: GetChannelReservation :one
+SELECT *
+FROM channel_reservations
+WHERE channel = @channel::text AND external_id = @external_id::text;
+
+-- name: CreateChannelReservation :exec
+INSERT INTO channel_reservations
+(
+    channel,
+    external_id,
+    hotel_id,
+    status,
+    booking_ids,
+    error
+)
+VALUES
+(
+    @channel::text,
+    @external_id::text,
+    @hotel_id::uuid,
+    @status::CHANNEL_RESERVATION_STATUS,
+    @booking_ids::uuid[],
+    sqlc.narg(error)::text
+);
+
+-- name: SetChannelReservationStatus :exec
+UPDATE channel_reservations
+SET
+    status = @status::CHANNEL_RESERVATION_STATUS,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id::uuid;
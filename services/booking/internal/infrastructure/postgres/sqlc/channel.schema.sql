@@ -0,0 +1,27 @@
+CREATE TYPE CHANNEL_RESERVATION_STATUS AS ENUM ('ACCEPTED', 'REJECTED', 'CANCELLED');
+
+-- Rooms of a room type a channel may sell per night
+CREATE TABLE channel_allotments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    channel VARCHAR(32) NOT NULL,
+    hotel_id UUID NOT NULL,
+    room_type_id UUID NOT NULL,
+    allotment INT NOT NULL CHECK (allotment >= 0),
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (channel, room_type_id)
+);
+
+-- Reservations pulled from channels, a reservation is only processed once
+CREATE TABLE channel_reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    channel VARCHAR(32) NOT NULL,
+    external_id VARCHAR(64) NOT NULL,
+    hotel_id UUID NOT NULL,
+    status CHANNEL_RESERVATION_STATUS NOT NULL,
+    booking_ids UUID[] NOT NULL DEFAULT '{}',
+    error TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (channel, external_id)
+);
@@ -0,0 +1,131 @@
+-- name: CreateGiftCard :one
+INSERT INTO gift_cards
+(
+    code,
+    initial_balance,
+    balance,
+    expires_at,
+    issued_by
+)
+VALUES
+(
+    @code::text,
+    @initial_balance::int,
+    @initial_balance::int,
+    @expires_at::timestamp,
+    @issued_by::text
+)
+RETURNING *;
+
+-- name: GetGiftCardById :one
+SELECT *
+FROM gift_cards
+WHERE id = @id::uuid;
+
+-- name: GetGiftCardByCode :one
+SELECT *
+FROM gift_cards
+WHERE code = upper(@code::text);
+
+-- name: LockGiftCardById :one
+SELECT *
+FROM gift_cards
+WHERE id = @id::uuid
+FOR UPDATE;
+
+-- name: RedeemGiftCard :one
+-- Decrement only an active, unexpired card with enough balance, so concurrent redemptions cannot overdraw
+UPDATE gift_cards
+SET
+    balance = balance - @amount::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = @id::uuid
+    AND status = 'ACTIVE'
+    AND expires_at > CURRENT_TIMESTAMP
+    AND balance >= @amount::int
+RETURNING *;
+
+-- name: CreditGiftCard :one
+-- Voided cards are not credited
+UPDATE gift_cards
+SET
+    balance = balance + @amount::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id::uuid AND status = 'ACTIVE'
+RETURNING *;
+
+-- name: VoidGiftCard :one
+UPDATE gift_cards
+SET
+    status = 'VOIDED',
+    balance = 0,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id::uuid AND status = 'ACTIVE'
+RETURNING *;
+
+-- name: CreateGiftCardTransaction :exec
+INSERT INTO gift_card_transactions
+(
+    gift_card_id,
+    type,
+    amount,
+    booking_id,
+    actor,
+    note
+)
+VALUES
+(
+    @gift_card_id::uuid,
+    @type::GIFT_CARD_TRANSACTION_TYPE,
+    @amount::int,
+    sqlc.narg(booking_id)::uuid,
+    @actor::text,
+    sqlc.narg(note)::text
+);
+
+-- name: LockBookingById :one
+-- Payments of a booking are made one at a time so the booking is not overpaid, finished stays are not locked
+SELECT *
+FROM bookings
+WHERE
+    id = @id::uuid
+    AND status NOT IN ('CHECK_OUT', 'NO_SHOW')
+    AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: CreateBookingPayment :exec
+INSERT INTO booking_payments
+(
+    booking_id,
+    method,
+    amount,
+    gift_card_id
+)
+VALUES
+(
+    @booking_id::uuid,
+    @method::PAYMENT_METHOD,
+    @amount::int,
+    sqlc.narg(gift_card_id)::uuid
+);
+
+-- name: GetBookingPaidTotal :one
+SELECT COALESCE(SUM(amount), 0)::int AS paid_total
+FROM booking_payments
+WHERE booking_id = @booking_id::uuid AND refunded_at IS NULL;
+
+-- name: RefundBookingPaymentsByBookingIds :many
+-- Payments of cancelled bookings, gift card payments go back to their card
+UPDATE booking_payments
+SET refunded_at = CURRENT_TIMESTAMP
+WHERE booking_id = ANY(@booking_ids::uuid[]) AND refunded_at IS NULL
+RETURNING *;
+
+-- name: SetBookingPaid :execrows
+-- Guest in house paid the whole stay
+UPDATE bookings
+SET
+    status = 'PAID',
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id::uuid AND status = 'CHECK_IN' AND deleted_at IS NULL;
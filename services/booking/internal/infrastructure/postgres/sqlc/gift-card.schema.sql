@@ -0,0 +1,57 @@
+CREATE TYPE GIFT_CARD_STATUS AS ENUM ('ACTIVE', 'VOIDED');
+CREATE TYPE GIFT_CARD_TRANSACTION_TYPE AS ENUM ('ISSUE', 'REDEEM', 'REFUND', 'VOID');
+
+CREATE TABLE gift_cards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- XXXX-XXXX-XXXX-XXXX given to the buyer
+    code VARCHAR(19) NOT NULL UNIQUE,
+    initial_balance INT NOT NULL CHECK (initial_balance > 0),
+    -- Sum of the ledger, decremented with a conditional update so concurrent redemptions cannot overdraw
+    balance INT NOT NULL CHECK (balance >= 0),
+    status GIFT_CARD_STATUS NOT NULL DEFAULT 'ACTIVE',
+    expires_at TIMESTAMP NOT NULL,
+    issued_by TEXT NOT NULL,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Append-only ledger of gift card balances, amount is the signed change of the balance
+CREATE TABLE gift_card_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    gift_card_id UUID NOT NULL,
+    type GIFT_CARD_TRANSACTION_TYPE NOT NULL,
+    amount INT NOT NULL,
+    booking_id UUID,
+    actor TEXT NOT NULL DEFAULT 'system',
+    note TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (gift_card_id) REFERENCES gift_cards(id)
+);
+
+CREATE INDEX gift_card_transactions_gift_card_id_idx ON gift_card_transactions (gift_card_id, create_at);
+
+CREATE FUNCTION prevent_gift_card_transaction_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'gift_card_transactions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER gift_card_transactions_append_only
+BEFORE UPDATE OR DELETE ON gift_card_transactions
+FOR EACH ROW EXECUTE FUNCTION prevent_gift_card_transaction_change();
+
+CREATE TYPE PAYMENT_METHOD AS ENUM ('GIFT_CARD');
+
+-- Payments against a booking, refunded when the booking is cancelled
+CREATE TABLE booking_payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    method PAYMENT_METHOD NOT NULL,
+    amount INT NOT NULL CHECK (amount > 0),
+    gift_card_id UUID,
+    refunded_at TIMESTAMP,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX booking_payments_booking_id_idx ON booking_payments (booking_id);
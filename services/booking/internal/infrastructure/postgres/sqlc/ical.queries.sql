@@ -0,0 +1,96 @@
+-- name: CreateIcalFeed :one
+INSERT INTO ical_feeds
+(
+    room_id,
+    room_type_id,
+    hotel_id,
+    url
+)
+VALUES
+(
+    @room_id::uuid,
+    @room_type_id::uuid,
+    @hotel_id::uuid,
+    @url::text
+)
+RETURNING *;
+
+-- name: GetIcalFeeds :many
+SELECT *
+FROM ical_feeds
+ORDER BY last_synced_at NULLS FIRST;
+
+-- name: GetIcalFeedsByRoomId :many
+SELECT *
+FROM ical_feeds
+WHERE room_id = @room_id::uuid
+ORDER BY create_at;
+
+-- name: DeleteIcalFeedById :execrows
+DELETE FROM ical_feeds WHERE id = @id::uuid;
+
+-- name: SetIcalFeedSyncResult :exec
+UPDATE ical_feeds
+SET
+    last_synced_at = CURRENT_TIMESTAMP,
+    last_error = sqlc.narg(last_error)::text
+WHERE id = @id::uuid;
+
+-- name: UpsertRoomBlock :exec
+INSERT INTO room_blocks
+(
+    feed_id,
+    room_id,
+    room_type_id,
+    hotel_id,
+    uid,
+    start_date,
+    end_date,
+    summary
+)
+VALUES
+(
+    @feed_id::uuid,
+    @room_id::uuid,
+    @room_type_id::uuid,
+    @hotel_id::uuid,
+    @uid::text,
+    @start_date::date,
+    @end_date::date,
+    @summary::text
+)
+ON CONFLICT (feed_id, uid) DO UPDATE
+SET
+    start_date = EXCLUDED.start_date,
+    end_date = EXCLUDED.end_date,
+    summary = EXCLUDED.summary,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteRoomBlocksNotInUids :execrows
+-- Events removed from the feed are no longer blocked
+DELETE FROM room_blocks
+WHERE
+    feed_id = @feed_id::uuid
+    AND NOT (uid = ANY(@uids::text[]));
+
+-- name: GetRoomBlocksByRoomId :many
+SELECT *
+FROM room_blocks
+WHERE
+    room_id = @room_id::uuid
+    AND end_date > @from_date::date
+ORDER BY start_date;
+
+-- name: GetBookedNightsByRoomId :many
+SELECT
+    id,
+    check_in,
+    check_out,
+    status
+FROM bookings
+WHERE
+    room_id = @room_id::uuid
+    AND deleted_at IS NULL
+    AND status <> 'NO_SHOW'
+    AND check_out > @from_date::date
+ORDER BY check_in;
@@ -0,0 +1,31 @@
+-- External iCalendar feeds imported per room
+CREATE TABLE ical_feeds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    room_id UUID NOT NULL,
+    room_type_id UUID NOT NULL,
+    hotel_id UUID NOT NULL,
+    url TEXT NOT NULL,
+    last_synced_at TIMESTAMP,
+    last_error TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (room_id, url)
+);
+
+-- Nights [start_date, end_date) a room is not available, one row per VEVENT UID of a feed
+CREATE TABLE room_blocks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    feed_id UUID NOT NULL,
+    room_id UUID NOT NULL,
+    room_type_id UUID NOT NULL,
+    hotel_id UUID NOT NULL,
+    uid TEXT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (feed_id, uid),
+    FOREIGN KEY (feed_id) REFERENCES ical_feeds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX room_blocks_room_id_idx ON room_blocks (room_id, start_date);
@@ -0,0 +1,127 @@
+-- name: GetHotelAuditSettings :many
+-- Hotels with open bookings but no setting are audited at the default time in UTC
+SELECT hotel_id, audit_time, last_audit_date, timezone
+FROM hotel_audit_settings
+UNION ALL
+SELECT DISTINCT
+    b.hotel_id,
+    '23:00'::time,
+    NULL::date,
+    'UTC'::text
+FROM bookings b
+WHERE
+    b.deleted_at IS NULL
+    AND b.status IN ('BOOKED', 'CHECK_IN', 'PAID')
+    AND NOT EXISTS (SELECT 1 FROM hotel_audit_settings s WHERE s.hotel_id = b.hotel_id);
+
+-- name: UpsertHotelAuditSetting :exec
+-- A NULL timezone keeps the current one, UTC for a new setting
+INSERT INTO hotel_audit_settings
+(
+    hotel_id,
+    audit_time,
+    timezone
+)
+VALUES
+(
+    @hotel_id::uuid,
+    @audit_time::time,
+    COALESCE(sqlc.narg(timezone)::text, 'UTC')
+)
+ON CONFLICT (hotel_id) DO UPDATE
+SET
+    audit_time = EXCLUDED.audit_time,
+    timezone = COALESCE(sqlc.narg(timezone)::text, hotel_audit_settings.timezone);
+
+-- name: SetHotelLastAuditDate :exec
+-- Hotels audited with the default setting get a row here.
+-- Manual audit of an older date must not move the last audit date back
+INSERT INTO hotel_audit_settings
+(
+    hotel_id,
+    last_audit_date
+)
+VALUES
+(
+    @hotel_id::uuid,
+    @last_audit_date::date
+)
+ON CONFLICT (hotel_id) DO UPDATE
+SET last_audit_date = GREATEST(hotel_audit_settings.last_audit_date, EXCLUDED.last_audit_date);
+
+-- name: MarkNoShowBookings :many
+UPDATE bookings
+SET
+    status = 'NO_SHOW',
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    hotel_id = @hotel_id::uuid
+    AND status = 'BOOKED'
+    AND deleted_at IS NULL
+    AND check_in <= @business_date::date
+RETURNING id, user_id, hotel_id, check_in, check_out, total, status;
+
+-- name: CheckOutPastDueBookings :many
+UPDATE bookings
+SET
+    status = 'CHECK_OUT',
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    hotel_id = @hotel_id::uuid
+    AND status IN ('CHECK_IN', 'PAID')
+    AND deleted_at IS NULL
+    AND check_out <= @business_date::date
+RETURNING id, user_id, hotel_id, check_in, check_out, total, status;
+
+-- name: PostRoomNightRevenues :execrows
+INSERT INTO room_night_revenues
+(
+    booking_id,
+    hotel_id,
+    business_date,
+    amount
+)
+SELECT
+    b.id,
+    b.hotel_id,
+    @business_date::date,
+    b.total / GREATEST(b.check_out - b.check_in, 1)
+FROM bookings b
+WHERE
+    b.hotel_id = @hotel_id::uuid
+    AND b.status IN ('CHECK_IN', 'PAID')
+    AND b.deleted_at IS NULL
+    AND b.check_in <= @business_date::date
+    AND b.check_out > @business_date::date
+ON CONFLICT (booking_id, business_date) DO NOTHING;
+
+-- name: UpsertNightAuditReport :one
+-- Status counts add up across re-runs (a re-run changes no status), revenue is recomputed from posted room nights
+INSERT INTO night_audit_reports
+(
+    hotel_id,
+    business_date,
+    no_shows,
+    check_outs,
+    room_nights,
+    room_revenue
+)
+SELECT
+    @hotel_id::uuid,
+    @business_date::date,
+    @no_shows::int,
+    @check_outs::int,
+    COUNT(r.id)::int,
+    COALESCE(SUM(r.amount), 0)::bigint
+FROM room_night_revenues r
+WHERE
+    r.hotel_id = @hotel_id::uuid
+    AND r.business_date = @business_date::date
+ON CONFLICT (hotel_id, business_date) DO UPDATE
+SET
+    no_shows = night_audit_reports.no_shows + EXCLUDED.no_shows,
+    check_outs = night_audit_reports.check_outs + EXCLUDED.check_outs,
+    room_nights = EXCLUDED.room_nights,
+    room_revenue = EXCLUDED.room_revenue,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
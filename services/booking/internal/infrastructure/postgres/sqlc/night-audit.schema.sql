@@ -0,0 +1,32 @@
+-- Time of day each hotel closes its business date, in the timezone of the hotel (IANA name)
+CREATE TABLE hotel_audit_settings (
+    hotel_id UUID PRIMARY KEY,
+    audit_time TIME NOT NULL DEFAULT '23:00',
+    last_audit_date DATE,
+    timezone TEXT NOT NULL DEFAULT 'UTC'
+);
+
+-- One room night revenue per booking and business date, so re-running audit does not post twice
+CREATE TABLE room_night_revenues (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    hotel_id UUID NOT NULL,
+    business_date DATE NOT NULL,
+    amount INT NOT NULL,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (booking_id, business_date),
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE night_audit_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hotel_id UUID NOT NULL,
+    business_date DATE NOT NULL,
+    no_shows INT NOT NULL DEFAULT 0,
+    check_outs INT NOT NULL DEFAULT 0,
+    room_nights INT NOT NULL DEFAULT 0,
+    room_revenue BIGINT NOT NULL DEFAULT 0,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (hotel_id, business_date)
+);
@@ -0,0 +1,56 @@
+-- name: CreateSpecialRequest :exec
+INSERT INTO booking_special_requests
+(
+    booking_id,
+    hotel_id,
+    type,
+    note
+)
+VALUES
+(
+    @booking_id::uuid,
+    @hotel_id::uuid,
+    @type::SPECIAL_REQUEST_TYPE,
+    @note::text
+);
+
+-- name: GetSpecialRequestsByBookingIds :many
+SELECT *
+FROM booking_special_requests
+WHERE booking_id = ANY(@booking_ids::uuid[])
+ORDER BY booking_id, create_at;
+
+-- name: GetSpecialRequestsByHotelId :many
+-- Requests of bookings checking in within [start_date, end_date), earliest arrival first
+SELECT
+    r.id,
+    r.booking_id,
+    r.type,
+    r.note,
+    r.acknowledged_at,
+    r.acknowledged_by,
+    r.create_at,
+    b.check_in,
+    b.check_out,
+    b.room_type_id,
+    b.room_id,
+    b.confirmation_code,
+    b.guest_name,
+    b.estimated_arrival_time
+FROM booking_special_requests r
+JOIN bookings b ON b.id = r.booking_id
+WHERE
+    r.hotel_id = @hotel_id::uuid
+    AND b.deleted_at IS NULL
+    AND b.check_in >= @start_date::date
+    AND b.check_in < @end_date::date
+    AND (NOT @only_unacknowledged::boolean OR r.acknowledged_at IS NULL)
+ORDER BY b.check_in, b.estimated_arrival_time NULLS LAST, r.create_at;
+
+-- name: AcknowledgeSpecialRequest :execrows
+-- Acknowledging again keeps the first acknowledgement
+UPDATE booking_special_requests
+SET
+    acknowledged_at = COALESCE(acknowledged_at, CURRENT_TIMESTAMP),
+    acknowledged_by = COALESCE(acknowledged_by, @acknowledged_by::text)
+WHERE id = @id::uuid AND hotel_id = @hotel_id::uuid;
@@ -0,0 +1,17 @@
+CREATE TYPE SPECIAL_REQUEST_TYPE AS ENUM ('HIGH_FLOOR', 'LOW_FLOOR', 'QUIET_ROOM', 'BABY_COT', 'EXTRA_BED', 'ACCESSIBLE_ROOM', 'EARLY_CHECK_IN', 'LATE_CHECK_IN', 'OTHER');
+
+-- Requests of the guest on a booking line, OTHER is free text in note
+CREATE TABLE booking_special_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    hotel_id UUID NOT NULL,
+    type SPECIAL_REQUEST_TYPE NOT NULL DEFAULT 'OTHER',
+    note TEXT NOT NULL DEFAULT '',
+    -- Hotel staff saw the request, acknowledged_by is the actor
+    acknowledged_at TIMESTAMP,
+    acknowledged_by TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE
+);
+
+CREATE INDEX booking_special_requests_booking_id_idx ON booking_special_requests (booking_id);
@@ -1,24 +1,35 @@
 package booking_repo_mapping
 
 import (
+	"time"
+
 	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
 	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func FromBookingRepoToBookingDomain(bookingRepo booking_repo.Booking) booking_domain.Booking {
 
 	return booking_domain.Booking{
-		Id:         bookingRepo.ID.String(),
-		CheckIn:    bookingRepo.CheckIn.Time,
-		CheckOut:   bookingRepo.CheckOut.Time,
-		Total:      int(bookingRepo.Total),
-		Status:     string(bookingRepo.Status),
-		HotelId:    bookingRepo.HotelID.String(),
-		RoomTypeId: bookingRepo.RoomTypeID.String(),
-		RoomId:     bookingRepo.RoomID.String(),
-		UserId:     bookingRepo.UserID.String(),
-		CreatedAt:  bookingRepo.CreateAt.Time,
-		UpdatedAt:  bookingRepo.UpdatedAt.Time,
+		Id:                     bookingRepo.ID.String(),
+		CheckIn:                bookingRepo.CheckIn.Time,
+		CheckOut:               bookingRepo.CheckOut.Time,
+		Total:                  int(bookingRepo.Total),
+		Status:                 string(bookingRepo.Status),
+		HotelId:                bookingRepo.HotelID.String(),
+		RoomTypeId:             bookingRepo.RoomTypeID.String(),
+		RoomId:                 bookingRepo.RoomID.String(),
+		UserId:                 bookingRepo.UserID.String(),
+		UpgradedFromRoomTypeId: bookingRepo.UpgradedFromRoomTypeID.String(),
+		ConfirmationCode:       bookingRepo.ConfirmationCode,
+		Source:                 bookingRepo.Source,
+		CompanyId:              bookingRepo.CompanyID.String(),
+		GuestName:              bookingRepo.GuestName.String,
+		GuestEmail:             bookingRepo.GuestEmail.String,
+		GuestPhone:             bookingRepo.GuestPhone.String,
+		EstimatedArrivalTime:   FromPgTimeToClock(bookingRepo.EstimatedArrivalTime),
+		CreatedAt:              bookingRepo.CreateAt.Time,
+		UpdatedAt:              bookingRepo.UpdatedAt.Time,
 	}
 }
 
@@ -32,3 +43,12 @@ func FromBookingsRepoToBookingsDomain(bookingsRepo []booking_repo.Booking) []boo
 
 	return bookings
 }
+
+// Time of day as HH:MM, empty when not set
+func FromPgTimeToClock(t pgtype.Time) string {
+	if !t.Valid {
+		return ""
+	}
+
+	return time.Time{}.Add(time.Duration(t.Microseconds) * time.Microsecond).Format("15:04")
+}
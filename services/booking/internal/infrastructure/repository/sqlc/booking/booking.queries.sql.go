@@ -11,69 +11,163 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createBooking = `-- name: CreateBooking :exec
-INSERT INTO bookings (
-    check_in,
-    check_out,
-    total,
-    status,
-    room_type_id,
-    user_id,
-    room_id
-) VALUES (
-    $1::date,
-    $2::date,
-    $3::int,
-    $4::BOOKING_STATUS,
-    $5::uuid,
-    $6::uuid,
-    $7::uuid
-)
+const countRoomConflicts = `-- name: CountRoomConflicts :one
+SELECT COUNT(*)::int AS number_of_conflicts
+FROM (
+    SELECT id
+    FROM bookings
+    WHERE
+        room_id = $1::uuid
+        AND id <> $2::uuid
+        AND deleted_at IS NULL
+        AND daterange(check_in, check_out, '[)') && daterange($3::date, $4::date, '[)')
+    UNION ALL
+    SELECT id
+    FROM room_blocks
+    WHERE
+        room_id = $1::uuid
+        AND daterange(start_date, end_date, '[)') && daterange($3::date, $4::date, '[)')
+) conflicts
 `
 
-type CreateBookingParams struct {
-	CheckIn    pgtype.Date   `json:"check_in"`
-	CheckOut   pgtype.Date   `json:"check_out"`
-	Total      int32         `json:"total"`
-	Status     BookingStatus `json:"status"`
-	RoomTypeID pgtype.UUID   `json:"room_type_id"`
-	UserID     pgtype.UUID   `json:"user_id"`
-	RoomID     pgtype.UUID   `json:"room_id"`
+type CountRoomConflictsParams struct {
+	RoomID    pgtype.UUID `json:"room_id"`
+	BookingID pgtype.UUID `json:"booking_id"`
+	CheckIn   pgtype.Date `json:"check_in"`
+	CheckOut  pgtype.Date `json:"check_out"`
 }
 
-func (q *Queries) CreateBooking(ctx context.Context, arg CreateBookingParams) error {
-	_, err := q.db.Exec(ctx, createBooking,
+// Other bookings and iCal blocks of the room overlapping the stay
+func (q *Queries) CountRoomConflicts(ctx context.Context, arg CountRoomConflictsParams) (int32, error) {
+	row := q.db.QueryRow(ctx, countRoomConflicts,
+		arg.RoomID,
+		arg.BookingID,
 		arg.CheckIn,
 		arg.CheckOut,
-		arg.Total,
-		arg.Status,
-		arg.RoomTypeID,
-		arg.UserID,
-		arg.RoomID,
 	)
-	return err
+	var number_of_conflicts int32
+	err := row.Scan(&number_of_conflicts)
+	return number_of_conflicts, err
 }
 
-const deleteBookingById = `-- name: DeleteBookingById :exec
-DELETE FROM bookings WHERE id = $1
+const deleteBookingById = `-- name: DeleteBookingById :execrows
+UPDATE bookings
+SET
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteBookingById(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteBookingById, id)
-	return err
+// Soft delete, the booking is kept until the retention period ends
+func (q *Queries) DeleteBookingById(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteBookingById, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const deleteBookingsByIds = `-- name: DeleteBookingsByIds :exec
-DELETE FROM bookings WHERE ids = ANY($1::uuid[])
+const deleteBookingsByIds = `-- name: DeleteBookingsByIds :execrows
+UPDATE bookings
+SET
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = ANY($1::uuid[])
+    AND status = 'BOOKED'
+    AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteBookingsByIds(ctx context.Context, bookingIds []pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteBookingsByIds, bookingIds)
-	return err
+// Only stays not started yet can be cancelled, guests in house are not refunded
+func (q *Queries) DeleteBookingsByIds(ctx context.Context, bookingIds []pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteBookingsByIds, bookingIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getAdjacentBookedRoomsByRoomTypeId = `-- name: GetAdjacentBookedRoomsByRoomTypeId :many
+SELECT
+    room_id,
+    bool_or(check_out = $1::date)::boolean AS touches_check_in,
+    bool_or(check_in = $2::date)::boolean AS touches_check_out
+FROM bookings
+WHERE
+    room_type_id = $3::uuid
+    AND room_id IS NOT NULL
+    AND deleted_at IS NULL
+    AND (check_out = $1::date OR check_in = $2::date)
+GROUP BY room_id
+`
+
+type GetAdjacentBookedRoomsByRoomTypeIdParams struct {
+	CheckIn    pgtype.Date `json:"check_in"`
+	CheckOut   pgtype.Date `json:"check_out"`
+	RoomTypeID pgtype.UUID `json:"room_type_id"`
+}
+
+type GetAdjacentBookedRoomsByRoomTypeIdRow struct {
+	RoomID          pgtype.UUID `json:"room_id"`
+	TouchesCheckIn  bool        `json:"touches_check_in"`
+	TouchesCheckOut bool        `json:"touches_check_out"`
+}
+
+// Rooms that have a booking ending on check in or starting on check out of the new stay
+func (q *Queries) GetAdjacentBookedRoomsByRoomTypeId(ctx context.Context, arg GetAdjacentBookedRoomsByRoomTypeIdParams) ([]GetAdjacentBookedRoomsByRoomTypeIdRow, error) {
+	rows, err := q.db.Query(ctx, getAdjacentBookedRoomsByRoomTypeId, arg.CheckIn, arg.CheckOut, arg.RoomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAdjacentBookedRoomsByRoomTypeIdRow
+	for rows.Next() {
+		var i GetAdjacentBookedRoomsByRoomTypeIdRow
+		if err := rows.Scan(&i.RoomID, &i.TouchesCheckIn, &i.TouchesCheckOut); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBookingByConfirmationCode = `-- name: GetBookingByConfirmationCode :one
+SELECT id, check_in, check_out, total, status, hotel_id, room_type_id, user_id, room_id, upgraded_from_room_type_id, confirmation_code, source, company_id, guest_name, guest_email, guest_phone, estimated_arrival_time, deleted_at, create_at, updated_at FROM bookings WHERE confirmation_code = upper($1::text) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetBookingByConfirmationCode(ctx context.Context, confirmationCode string) (Booking, error) {
+	row := q.db.QueryRow(ctx, getBookingByConfirmationCode, confirmationCode)
+	var i Booking
+	err := row.Scan(
+		&i.ID,
+		&i.CheckIn,
+		&i.CheckOut,
+		&i.Total,
+		&i.Status,
+		&i.HotelID,
+		&i.RoomTypeID,
+		&i.UserID,
+		&i.RoomID,
+		&i.UpgradedFromRoomTypeID,
+		&i.ConfirmationCode,
+		&i.Source,
+		&i.CompanyID,
+		&i.GuestName,
+		&i.GuestEmail,
+		&i.GuestPhone,
+		&i.EstimatedArrivalTime,
+		&i.DeletedAt,
+		&i.CreateAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
 const getBookingById = `-- name: GetBookingById :one
-SELECT id, check_in, check_out, total, status, hotel_id, room_type_id, user_id, room_id, create_at, updated_at FROM bookings WHERE id = $1
+SELECT id, check_in, check_out, total, status, hotel_id, room_type_id, user_id, room_id, upgraded_from_room_type_id, confirmation_code, source, company_id, guest_name, guest_email, guest_phone, estimated_arrival_time, deleted_at, create_at, updated_at FROM bookings WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetBookingById(ctx context.Context, id pgtype.UUID) (Booking, error) {
@@ -89,14 +183,73 @@ func (q *Queries) GetBookingById(ctx context.Context, id pgtype.UUID) (Booking,
 		&i.RoomTypeID,
 		&i.UserID,
 		&i.RoomID,
+		&i.UpgradedFromRoomTypeID,
+		&i.ConfirmationCode,
+		&i.Source,
+		&i.CompanyID,
+		&i.GuestName,
+		&i.GuestEmail,
+		&i.GuestPhone,
+		&i.EstimatedArrivalTime,
+		&i.DeletedAt,
 		&i.CreateAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const getBookingIdsByHotelId = `-- name: GetBookingIdsByHotelId :many
+SELECT id
+FROM bookings
+WHERE
+    hotel_id = $1
+    AND status = 'BOOKED'
+    AND check_out > CURRENT_DATE
+    AND deleted_at IS NULL
+`
+
+// Stays of the hotel that are not over yet and can still be cancelled, finished stays are kept as records
+func (q *Queries) GetBookingIdsByHotelId(ctx context.Context, hotelID pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, getBookingIdsByHotelId, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBookingOwnerById = `-- name: GetBookingOwnerById :one
+SELECT user_id, hotel_id
+FROM bookings
+WHERE id = $1::uuid
+`
+
+type GetBookingOwnerByIdRow struct {
+	UserID  pgtype.UUID `json:"user_id"`
+	HotelID pgtype.UUID `json:"hotel_id"`
+}
+
+// Cancelled bookings included, their history is still readable
+func (q *Queries) GetBookingOwnerById(ctx context.Context, id pgtype.UUID) (GetBookingOwnerByIdRow, error) {
+	row := q.db.QueryRow(ctx, getBookingOwnerById, id)
+	var i GetBookingOwnerByIdRow
+	err := row.Scan(&i.UserID, &i.HotelID)
+	return i, err
+}
+
 const getBookingsByRoomId = `-- name: GetBookingsByRoomId :many
-SELECT id, check_in, check_out, total, status, hotel_id, room_type_id, user_id, room_id, create_at, updated_at FROM bookings WHERE room_id = $1
+SELECT id, check_in, check_out, total, status, hotel_id, room_type_id, user_id, room_id, upgraded_from_room_type_id, confirmation_code, source, company_id, guest_name, guest_email, guest_phone, estimated_arrival_time, deleted_at, create_at, updated_at FROM bookings WHERE room_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetBookingsByRoomId(ctx context.Context, roomID pgtype.UUID) ([]Booking, error) {
@@ -118,6 +271,15 @@ func (q *Queries) GetBookingsByRoomId(ctx context.Context, roomID pgtype.UUID) (
 			&i.RoomTypeID,
 			&i.UserID,
 			&i.RoomID,
+			&i.UpgradedFromRoomTypeID,
+			&i.ConfirmationCode,
+			&i.Source,
+			&i.CompanyID,
+			&i.GuestName,
+			&i.GuestEmail,
+			&i.GuestPhone,
+			&i.EstimatedArrivalTime,
+			&i.DeletedAt,
 			&i.CreateAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -131,59 +293,34 @@ func (q *Queries) GetBookingsByRoomId(ctx context.Context, roomID pgtype.UUID) (
 	return items, nil
 }
 
-const getBookingsByUserId = `-- name: GetBookingsByUserId :many
-SELECT 
-    id, check_in, check_out, total, status, hotel_id, room_type_id, user_id, room_id, create_at, updated_at 
-FROM bookings b
-WHERE 
-    b.user_id = $1::uuid
-    AND 
-    (
-        $2::date IS NULL
-        OR $3::date IS NULL
-        OR b.check_in BETWEEN $2 AND $2
-    )
-ORDER BY b.check_in
-LIMIT $5::int
-OFFSET $4::int
+const getCompanyBilledTotals = `-- name: GetCompanyBilledTotals :many
+SELECT
+    company_id,
+    SUM(total)::int AS total
+FROM bookings
+WHERE
+    id = ANY($1::uuid[])
+    AND company_id IS NOT NULL
+    AND deleted_at IS NULL
+GROUP BY company_id
 `
 
-type GetBookingsByUserIdParams struct {
-	UserID         pgtype.UUID `json:"user_id"`
-	CheckDateStart pgtype.Date `json:"check_date_start"`
-	CheckDateEnd   pgtype.Date `json:"check_date_end"`
-	NumberOfOffset int32       `json:"number_of_offset"`
-	Size           int32       `json:"size"`
-}
-
-func (q *Queries) GetBookingsByUserId(ctx context.Context, arg GetBookingsByUserIdParams) ([]Booking, error) {
-	rows, err := q.db.Query(ctx, getBookingsByUserId,
-		arg.UserID,
-		arg.CheckDateStart,
-		arg.CheckDateEnd,
-		arg.NumberOfOffset,
-		arg.Size,
-	)
+type GetCompanyBilledTotalsRow struct {
+	CompanyID pgtype.UUID `json:"company_id"`
+	Total     int32       `json:"total"`
+}
+
+// Total of not cancelled bookings per company they are billed to
+func (q *Queries) GetCompanyBilledTotals(ctx context.Context, bookingIds []pgtype.UUID) ([]GetCompanyBilledTotalsRow, error) {
+	rows, err := q.db.Query(ctx, getCompanyBilledTotals, bookingIds)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Booking
+	var items []GetCompanyBilledTotalsRow
 	for rows.Next() {
-		var i Booking
-		if err := rows.Scan(
-			&i.ID,
-			&i.CheckIn,
-			&i.CheckOut,
-			&i.Total,
-			&i.Status,
-			&i.HotelID,
-			&i.RoomTypeID,
-			&i.UserID,
-			&i.RoomID,
-			&i.CreateAt,
-			&i.UpdatedAt,
-		); err != nil {
+		var i GetCompanyBilledTotalsRow
+		if err := rows.Scan(&i.CompanyID, &i.Total); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -194,16 +331,79 @@ func (q *Queries) GetBookingsByUserId(ctx context.Context, arg GetBookingsByUser
 	return items, nil
 }
 
+const getCompanyBookingsTotal = `-- name: GetCompanyBookingsTotal :one
+SELECT COALESCE(SUM(total), 0)::bigint AS total
+FROM bookings
+WHERE
+    company_id = $1::uuid
+    AND deleted_at IS NULL
+    AND check_out >= $2::date
+    AND check_out < $3::date
+`
+
+type GetCompanyBookingsTotalParams struct {
+	CompanyID pgtype.UUID `json:"company_id"`
+	StartDate pgtype.Date `json:"start_date"`
+	EndDate   pgtype.Date `json:"end_date"`
+}
+
+// Total of the bookings billed to the company checking out within [start_date, end_date)
+func (q *Queries) GetCompanyBookingsTotal(ctx context.Context, arg GetCompanyBookingsTotalParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getCompanyBookingsTotal, arg.CompanyID, arg.StartDate, arg.EndDate)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const getMaxUnassignedBookingsPerNight = `-- name: GetMaxUnassignedBookingsPerNight :one
+SELECT COALESCE(MAX(n.number_of_unassigned_bookings), 0)::int AS max_unassigned_bookings
+FROM (
+    SELECT
+        night,
+        COUNT(b.id) AS number_of_unassigned_bookings
+    FROM generate_series($1::date, $2::date - 1, '1 day') AS night
+    LEFT JOIN bookings b ON
+        b.room_type_id = $3::uuid
+        AND b.room_id IS NULL
+        AND b.deleted_at IS NULL
+        AND b.check_in <= night
+        AND b.check_out > night
+    GROUP BY night
+) n
+`
+
+type GetMaxUnassignedBookingsPerNightParams struct {
+	CheckIn    pgtype.Date `json:"check_in"`
+	CheckOut   pgtype.Date `json:"check_out"`
+	RoomTypeID pgtype.UUID `json:"room_type_id"`
+}
+
+func (q *Queries) GetMaxUnassignedBookingsPerNight(ctx context.Context, arg GetMaxUnassignedBookingsPerNightParams) (int32, error) {
+	row := q.db.QueryRow(ctx, getMaxUnassignedBookingsPerNight, arg.CheckIn, arg.CheckOut, arg.RoomTypeID)
+	var max_unassigned_bookings int32
+	err := row.Scan(&max_unassigned_bookings)
+	return max_unassigned_bookings, err
+}
+
 const getNumberOfOccupiedRooms = `-- name: GetNumberOfOccupiedRooms :many
 SELECT 
     room_type_id,
-    COUNT(DISTINCT room_id) AS number_of_occupied_rooms
-FROM bookings 
+    -- Unassigned (overbooked) bookings hold inventory without a room
+    (COUNT(DISTINCT room_id) + COUNT(*) FILTER (WHERE room_id IS NULL))::bigint AS number_of_occupied_rooms
+FROM (
+    SELECT hotel_id, room_type_id, room_id, check_in, check_out
+    FROM bookings
+    WHERE deleted_at IS NULL
+    UNION ALL
+    -- Rooms blocked by imported iCal feeds
+    SELECT hotel_id, room_type_id, room_id, start_date, end_date
+    FROM room_blocks
+) occupied
 WHERE
     room_type_id = ANY($1::uuid[])
     -- AND ( date_trunc('day', @new_check_in::date) < date_trunc('day', check_out) AND date_trunc('day', @new_check_out::date) > date_trunc('day', check_in) );
     -- AND (@new_check_in::date < check_out::date AND @new_check_out::date > check_in::date)
-    AND daterange(check_in, check_out, '[]') && daterange($2::date, $3::date, '[]')
+    AND daterange(check_in, check_out, '[)') && daterange($2::date, $3::date, '[)')
 GROUP BY room_type_id
 `
 
@@ -241,13 +441,21 @@ func (q *Queries) GetNumberOfOccupiedRooms(ctx context.Context, arg GetNumberOfO
 const getNumberOfOccupiedRoomsByHotelIds = `-- name: GetNumberOfOccupiedRoomsByHotelIds :many
 SELECT
     room_type_id,
-    COUNT(DISTINCT room_id) AS number_of_occupied_rooms
-FROM bookings 
+    (COUNT(DISTINCT room_id) + COUNT(*) FILTER (WHERE room_id IS NULL))::bigint AS number_of_occupied_rooms
+FROM (
+    SELECT hotel_id, room_type_id, room_id, check_in, check_out
+    FROM bookings
+    WHERE deleted_at IS NULL
+    UNION ALL
+    -- Rooms blocked by imported iCal feeds
+    SELECT hotel_id, room_type_id, room_id, start_date, end_date
+    FROM room_blocks
+) occupied
 WHERE
     hotel_id = ANY($1::uuid[])
     -- AND ( date_trunc('day', @new_check_in::date) < date_trunc('day', check_out) AND date_trunc('day', @new_check_out::date) > date_trunc('day', check_in) );
     -- AND (@new_check_in::date < check_out::date AND @new_check_out::date > check_in::date)
-    AND daterange(check_in, check_out, '[]') && daterange($2::date, $3::date, '[]')
+    AND daterange(check_in, check_out, '[)') && daterange($2::date, $3::date, '[)')
 GROUP BY room_type_id
 `
 
@@ -282,12 +490,76 @@ func (q *Queries) GetNumberOfOccupiedRoomsByHotelIds(ctx context.Context, arg Ge
 	return items, nil
 }
 
+const getOversoldNights = `-- name: GetOversoldNights :many
+SELECT
+    night::date AS night,
+    b.room_type_id,
+    COUNT(b.id) AS number_of_unassigned_bookings,
+    array_agg(b.id)::uuid[] AS booking_ids
+FROM generate_series($1::date, $2::date - 1, '1 day') AS night
+JOIN bookings b ON
+    b.check_in <= night
+    AND b.check_out > night
+WHERE
+    b.hotel_id = $3::uuid
+    AND b.room_id IS NULL
+    AND b.deleted_at IS NULL
+GROUP BY night, b.room_type_id
+ORDER BY night, b.room_type_id
+`
+
+type GetOversoldNightsParams struct {
+	StartDate pgtype.Date `json:"start_date"`
+	EndDate   pgtype.Date `json:"end_date"`
+	HotelID   pgtype.UUID `json:"hotel_id"`
+}
+
+type GetOversoldNightsRow struct {
+	Night                      pgtype.Date   `json:"night"`
+	RoomTypeID                 pgtype.UUID   `json:"room_type_id"`
+	NumberOfUnassignedBookings int64         `json:"number_of_unassigned_bookings"`
+	BookingIds                 []pgtype.UUID `json:"booking_ids"`
+}
+
+func (q *Queries) GetOversoldNights(ctx context.Context, arg GetOversoldNightsParams) ([]GetOversoldNightsRow, error) {
+	rows, err := q.db.Query(ctx, getOversoldNights, arg.StartDate, arg.EndDate, arg.HotelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOversoldNightsRow
+	for rows.Next() {
+		var i GetOversoldNightsRow
+		if err := rows.Scan(
+			&i.Night,
+			&i.RoomTypeID,
+			&i.NumberOfUnassignedBookings,
+			&i.BookingIds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUnavailableRoomsByRoomTypeId = `-- name: GetUnavailableRoomsByRoomTypeId :many
 SELECT room_id
 FROM bookings
 WHERE 
     room_type_id = $1::uuid
-    AND daterange(check_in, check_out, '[]') && daterange($2::date, $3::date, '[]')
+    AND room_id IS NOT NULL
+    AND deleted_at IS NULL
+    AND daterange(check_in, check_out, '[)') && daterange($2::date, $3::date, '[)')
+UNION
+SELECT room_id
+FROM room_blocks
+WHERE
+    room_type_id = $1::uuid
+    AND daterange(start_date, end_date, '[)') && daterange($2::date, $3::date, '[)')
 `
 
 type GetUnavailableRoomsByRoomTypeIdParams struct {
@@ -315,3 +587,102 @@ func (q *Queries) GetUnavailableRoomsByRoomTypeId(ctx context.Context, arg GetUn
 	}
 	return items, nil
 }
+
+const lockBookingsByIds = `-- name: LockBookingsByIds :many
+SELECT id, user_id, hotel_id, status
+FROM bookings
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+ORDER BY id
+FOR UPDATE
+`
+
+type LockBookingsByIdsRow struct {
+	ID      pgtype.UUID   `json:"id"`
+	UserID  pgtype.UUID   `json:"user_id"`
+	HotelID pgtype.UUID   `json:"hotel_id"`
+	Status  BookingStatus `json:"status"`
+}
+
+// Bookings being cancelled are locked until commit, so access is checked on the rows that are deleted
+func (q *Queries) LockBookingsByIds(ctx context.Context, bookingIds []pgtype.UUID) ([]LockBookingsByIdsRow, error) {
+	rows, err := q.db.Query(ctx, lockBookingsByIds, bookingIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockBookingsByIdsRow
+	for rows.Next() {
+		var i LockBookingsByIdsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.HotelID,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockRoomTypeOverbooking = `-- name: LockRoomTypeOverbooking :exec
+SELECT pg_advisory_xact_lock(hashtextextended($1::uuid::text, 0))
+`
+
+func (q *Queries) LockRoomTypeOverbooking(ctx context.Context, roomTypeID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, lockRoomTypeOverbooking, roomTypeID)
+	return err
+}
+
+const purgeDeletedBookings = `-- name: PurgeDeletedBookings :execrows
+DELETE FROM bookings b
+WHERE
+    b.deleted_at IS NOT NULL
+    AND b.deleted_at < $1::timestamp
+    AND NOT EXISTS (SELECT 1 FROM booking_payments p WHERE p.booking_id = b.id)
+    AND NOT EXISTS (SELECT 1 FROM room_night_revenues r WHERE r.booking_id = b.id)
+`
+
+// Hard delete bookings soft deleted before the retention cut off, their events are kept.
+// Bookings with payments or posted room nights are financial records and are never purged
+func (q *Queries) PurgeDeletedBookings(ctx context.Context, deletedBefore pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedBookings, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateBookingDates = `-- name: UpdateBookingDates :execrows
+UPDATE bookings
+SET
+    check_in = $1::date,
+    check_out = $2::date,
+    total = $3::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $4::uuid AND deleted_at IS NULL
+`
+
+type UpdateBookingDatesParams struct {
+	CheckIn  pgtype.Date `json:"check_in"`
+	CheckOut pgtype.Date `json:"check_out"`
+	Total    int32       `json:"total"`
+	ID       pgtype.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateBookingDates(ctx context.Context, arg UpdateBookingDatesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateBookingDates,
+		arg.CheckIn,
+		arg.CheckOut,
+		arg.Total,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -11,12 +11,59 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type BookingEventType string
+
+const (
+	BookingEventTypeCREATED       BookingEventType = "CREATED"
+	BookingEventTypeUPDATED       BookingEventType = "UPDATED"
+	BookingEventTypeSTATUSCHANGED BookingEventType = "STATUS_CHANGED"
+	BookingEventTypeCANCELLED     BookingEventType = "CANCELLED"
+	BookingEventTypeTRANSFERRED   BookingEventType = "TRANSFERRED"
+)
+
+func (e *BookingEventType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BookingEventType(s)
+	case string:
+		*e = BookingEventType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BookingEventType: %T", src)
+	}
+	return nil
+}
+
+type NullBookingEventType struct {
+	BookingEventType BookingEventType `json:"booking_event_type"`
+	Valid            bool             `json:"valid"` // Valid is true if BookingEventType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBookingEventType) Scan(value interface{}) error {
+	if value == nil {
+		ns.BookingEventType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BookingEventType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBookingEventType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BookingEventType), nil
+}
+
 type BookingStatus string
 
 const (
-	BookingStatusBOOKED  BookingStatus = "BOOKED"
-	BookingStatusCHECKIN BookingStatus = "CHECK_IN"
-	BookingStatusPAID    BookingStatus = "PAID"
+	BookingStatusBOOKED   BookingStatus = "BOOKED"
+	BookingStatusCHECKIN  BookingStatus = "CHECK_IN"
+	BookingStatusPAID     BookingStatus = "PAID"
+	BookingStatusCHECKOUT BookingStatus = "CHECK_OUT"
+	BookingStatusNOSHOW   BookingStatus = "NO_SHOW"
 )
 
 func (e *BookingStatus) Scan(src interface{}) error {
@@ -54,16 +101,428 @@ func (ns NullBookingStatus) Value() (driver.Value, error) {
 	return string(ns.BookingStatus), nil
 }
 
+type BookingTransferStatus string
+
+const (
+	BookingTransferStatusPENDING   BookingTransferStatus = "PENDING"
+	BookingTransferStatusACCEPTED  BookingTransferStatus = "ACCEPTED"
+	BookingTransferStatusDECLINED  BookingTransferStatus = "DECLINED"
+	BookingTransferStatusCANCELLED BookingTransferStatus = "CANCELLED"
+)
+
+func (e *BookingTransferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BookingTransferStatus(s)
+	case string:
+		*e = BookingTransferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BookingTransferStatus: %T", src)
+	}
+	return nil
+}
+
+type NullBookingTransferStatus struct {
+	BookingTransferStatus BookingTransferStatus `json:"booking_transfer_status"`
+	Valid                 bool                  `json:"valid"` // Valid is true if BookingTransferStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBookingTransferStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.BookingTransferStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BookingTransferStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBookingTransferStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BookingTransferStatus), nil
+}
+
+type ChannelReservationStatus string
+
+const (
+	ChannelReservationStatusACCEPTED  ChannelReservationStatus = "ACCEPTED"
+	ChannelReservationStatusREJECTED  ChannelReservationStatus = "REJECTED"
+	ChannelReservationStatusCANCELLED ChannelReservationStatus = "CANCELLED"
+)
+
+func (e *ChannelReservationStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ChannelReservationStatus(s)
+	case string:
+		*e = ChannelReservationStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ChannelReservationStatus: %T", src)
+	}
+	return nil
+}
+
+type NullChannelReservationStatus struct {
+	ChannelReservationStatus ChannelReservationStatus `json:"channel_reservation_status"`
+	Valid                    bool                     `json:"valid"` // Valid is true if ChannelReservationStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullChannelReservationStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ChannelReservationStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ChannelReservationStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullChannelReservationStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ChannelReservationStatus), nil
+}
+
+type GiftCardStatus string
+
+const (
+	GiftCardStatusACTIVE GiftCardStatus = "ACTIVE"
+	GiftCardStatusVOIDED GiftCardStatus = "VOIDED"
+)
+
+func (e *GiftCardStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = GiftCardStatus(s)
+	case string:
+		*e = GiftCardStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for GiftCardStatus: %T", src)
+	}
+	return nil
+}
+
+type NullGiftCardStatus struct {
+	GiftCardStatus GiftCardStatus `json:"gift_card_status"`
+	Valid          bool           `json:"valid"` // Valid is true if GiftCardStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullGiftCardStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.GiftCardStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.GiftCardStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullGiftCardStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.GiftCardStatus), nil
+}
+
+type GiftCardTransactionType string
+
+const (
+	GiftCardTransactionTypeISSUE  GiftCardTransactionType = "ISSUE"
+	GiftCardTransactionTypeREDEEM GiftCardTransactionType = "REDEEM"
+	GiftCardTransactionTypeREFUND GiftCardTransactionType = "REFUND"
+	GiftCardTransactionTypeVOID   GiftCardTransactionType = "VOID"
+)
+
+func (e *GiftCardTransactionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = GiftCardTransactionType(s)
+	case string:
+		*e = GiftCardTransactionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for GiftCardTransactionType: %T", src)
+	}
+	return nil
+}
+
+type NullGiftCardTransactionType struct {
+	GiftCardTransactionType GiftCardTransactionType `json:"gift_card_transaction_type"`
+	Valid                   bool                    `json:"valid"` // Valid is true if GiftCardTransactionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullGiftCardTransactionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.GiftCardTransactionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.GiftCardTransactionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullGiftCardTransactionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.GiftCardTransactionType), nil
+}
+
+type PaymentMethod string
+
+const (
+	PaymentMethodGIFTCARD PaymentMethod = "GIFT_CARD"
+)
+
+func (e *PaymentMethod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PaymentMethod(s)
+	case string:
+		*e = PaymentMethod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PaymentMethod: %T", src)
+	}
+	return nil
+}
+
+type NullPaymentMethod struct {
+	PaymentMethod PaymentMethod `json:"payment_method"`
+	Valid         bool          `json:"valid"` // Valid is true if PaymentMethod is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPaymentMethod) Scan(value interface{}) error {
+	if value == nil {
+		ns.PaymentMethod, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PaymentMethod.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPaymentMethod) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PaymentMethod), nil
+}
+
+type SpecialRequestType string
+
+const (
+	SpecialRequestTypeHIGHFLOOR      SpecialRequestType = "HIGH_FLOOR"
+	SpecialRequestTypeLOWFLOOR       SpecialRequestType = "LOW_FLOOR"
+	SpecialRequestTypeQUIETROOM      SpecialRequestType = "QUIET_ROOM"
+	SpecialRequestTypeBABYCOT        SpecialRequestType = "BABY_COT"
+	SpecialRequestTypeEXTRABED       SpecialRequestType = "EXTRA_BED"
+	SpecialRequestTypeACCESSIBLEROOM SpecialRequestType = "ACCESSIBLE_ROOM"
+	SpecialRequestTypeEARLYCHECKIN   SpecialRequestType = "EARLY_CHECK_IN"
+	SpecialRequestTypeLATECHECKIN    SpecialRequestType = "LATE_CHECK_IN"
+	SpecialRequestTypeOTHER          SpecialRequestType = "OTHER"
+)
+
+func (e *SpecialRequestType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SpecialRequestType(s)
+	case string:
+		*e = SpecialRequestType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SpecialRequestType: %T", src)
+	}
+	return nil
+}
+
+type NullSpecialRequestType struct {
+	SpecialRequestType SpecialRequestType `json:"special_request_type"`
+	Valid              bool               `json:"valid"` // Valid is true if SpecialRequestType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSpecialRequestType) Scan(value interface{}) error {
+	if value == nil {
+		ns.SpecialRequestType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SpecialRequestType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSpecialRequestType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SpecialRequestType), nil
+}
+
 type Booking struct {
+	ID                     pgtype.UUID      `json:"id"`
+	CheckIn                pgtype.Date      `json:"check_in"`
+	CheckOut               pgtype.Date      `json:"check_out"`
+	Total                  int32            `json:"total"`
+	Status                 BookingStatus    `json:"status"`
+	HotelID                pgtype.UUID      `json:"hotel_id"`
+	RoomTypeID             pgtype.UUID      `json:"room_type_id"`
+	UserID                 pgtype.UUID      `json:"user_id"`
+	RoomID                 pgtype.UUID      `json:"room_id"`
+	UpgradedFromRoomTypeID pgtype.UUID      `json:"upgraded_from_room_type_id"`
+	ConfirmationCode       string           `json:"confirmation_code"`
+	Source                 string           `json:"source"`
+	CompanyID              pgtype.UUID      `json:"company_id"`
+	GuestName              pgtype.Text      `json:"guest_name"`
+	GuestEmail             pgtype.Text      `json:"guest_email"`
+	GuestPhone             pgtype.Text      `json:"guest_phone"`
+	EstimatedArrivalTime   pgtype.Time      `json:"estimated_arrival_time"`
+	DeletedAt              pgtype.Timestamp `json:"deleted_at"`
+	CreateAt               pgtype.Timestamp `json:"create_at"`
+	UpdatedAt              pgtype.Timestamp `json:"updated_at"`
+}
+
+type BookingEvent struct {
+	ID        pgtype.UUID      `json:"id"`
+	BookingID pgtype.UUID      `json:"booking_id"`
+	EventType BookingEventType `json:"event_type"`
+	Actor     string           `json:"actor"`
+	Changes   []byte           `json:"changes"`
+	Reason    pgtype.Text      `json:"reason"`
+	CreateAt  pgtype.Timestamp `json:"create_at"`
+}
+
+type BookingPayment struct {
 	ID         pgtype.UUID      `json:"id"`
-	CheckIn    pgtype.Date      `json:"check_in"`
-	CheckOut   pgtype.Date      `json:"check_out"`
-	Total      int32            `json:"total"`
-	Status     BookingStatus    `json:"status"`
+	BookingID  pgtype.UUID      `json:"booking_id"`
+	Method     PaymentMethod    `json:"method"`
+	Amount     int32            `json:"amount"`
+	GiftCardID pgtype.UUID      `json:"gift_card_id"`
+	RefundedAt pgtype.Timestamp `json:"refunded_at"`
+	CreateAt   pgtype.Timestamp `json:"create_at"`
+}
+
+type BookingSpecialRequest struct {
+	ID             pgtype.UUID        `json:"id"`
+	BookingID      pgtype.UUID        `json:"booking_id"`
+	HotelID        pgtype.UUID        `json:"hotel_id"`
+	Type           SpecialRequestType `json:"type"`
+	Note           string             `json:"note"`
+	AcknowledgedAt pgtype.Timestamp   `json:"acknowledged_at"`
+	AcknowledgedBy pgtype.Text        `json:"acknowledged_by"`
+	CreateAt       pgtype.Timestamp   `json:"create_at"`
+}
+
+type BookingTransfer struct {
+	ID          pgtype.UUID           `json:"id"`
+	BookingID   pgtype.UUID           `json:"booking_id"`
+	FromUserID  pgtype.UUID           `json:"from_user_id"`
+	ToUserID    pgtype.UUID           `json:"to_user_id"`
+	Status      BookingTransferStatus `json:"status"`
+	RequestedBy string                `json:"requested_by"`
+	RespondedAt pgtype.Timestamp      `json:"responded_at"`
+	CreateAt    pgtype.Timestamp      `json:"create_at"`
+}
+
+type ChannelAllotment struct {
+	ID         pgtype.UUID      `json:"id"`
+	Channel    string           `json:"channel"`
 	HotelID    pgtype.UUID      `json:"hotel_id"`
 	RoomTypeID pgtype.UUID      `json:"room_type_id"`
-	UserID     pgtype.UUID      `json:"user_id"`
+	Allotment  int32            `json:"allotment"`
+	CreateAt   pgtype.Timestamp `json:"create_at"`
+	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+}
+
+type ChannelReservation struct {
+	ID         pgtype.UUID              `json:"id"`
+	Channel    string                   `json:"channel"`
+	ExternalID string                   `json:"external_id"`
+	HotelID    pgtype.UUID              `json:"hotel_id"`
+	Status     ChannelReservationStatus `json:"status"`
+	BookingIds []pgtype.UUID            `json:"booking_ids"`
+	Error      pgtype.Text              `json:"error"`
+	CreateAt   pgtype.Timestamp         `json:"create_at"`
+	UpdatedAt  pgtype.Timestamp         `json:"updated_at"`
+}
+
+type GiftCard struct {
+	ID             pgtype.UUID      `json:"id"`
+	Code           string           `json:"code"`
+	InitialBalance int32            `json:"initial_balance"`
+	Balance        int32            `json:"balance"`
+	Status         GiftCardStatus   `json:"status"`
+	ExpiresAt      pgtype.Timestamp `json:"expires_at"`
+	IssuedBy       string           `json:"issued_by"`
+	CreateAt       pgtype.Timestamp `json:"create_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
+type GiftCardTransaction struct {
+	ID         pgtype.UUID             `json:"id"`
+	GiftCardID pgtype.UUID             `json:"gift_card_id"`
+	Type       GiftCardTransactionType `json:"type"`
+	Amount     int32                   `json:"amount"`
+	BookingID  pgtype.UUID             `json:"booking_id"`
+	Actor      string                  `json:"actor"`
+	Note       pgtype.Text             `json:"note"`
+	CreateAt   pgtype.Timestamp        `json:"create_at"`
+}
+
+type HotelAuditSetting struct {
+	HotelID       pgtype.UUID `json:"hotel_id"`
+	AuditTime     pgtype.Time `json:"audit_time"`
+	LastAuditDate pgtype.Date `json:"last_audit_date"`
+	Timezone      string      `json:"timezone"`
+}
+
+type IcalFeed struct {
+	ID           pgtype.UUID      `json:"id"`
+	RoomID       pgtype.UUID      `json:"room_id"`
+	RoomTypeID   pgtype.UUID      `json:"room_type_id"`
+	HotelID      pgtype.UUID      `json:"hotel_id"`
+	Url          string           `json:"url"`
+	LastSyncedAt pgtype.Timestamp `json:"last_synced_at"`
+	LastError    pgtype.Text      `json:"last_error"`
+	CreateAt     pgtype.Timestamp `json:"create_at"`
+}
+
+type NightAuditReport struct {
+	ID           pgtype.UUID      `json:"id"`
+	HotelID      pgtype.UUID      `json:"hotel_id"`
+	BusinessDate pgtype.Date      `json:"business_date"`
+	NoShows      int32            `json:"no_shows"`
+	CheckOuts    int32            `json:"check_outs"`
+	RoomNights   int32            `json:"room_nights"`
+	RoomRevenue  int64            `json:"room_revenue"`
+	CreateAt     pgtype.Timestamp `json:"create_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+}
+
+type RoomBlock struct {
+	ID         pgtype.UUID      `json:"id"`
+	FeedID     pgtype.UUID      `json:"feed_id"`
 	RoomID     pgtype.UUID      `json:"room_id"`
+	RoomTypeID pgtype.UUID      `json:"room_type_id"`
+	HotelID    pgtype.UUID      `json:"hotel_id"`
+	Uid        string           `json:"uid"`
+	StartDate  pgtype.Date      `json:"start_date"`
+	EndDate    pgtype.Date      `json:"end_date"`
+	Summary    string           `json:"summary"`
 	CreateAt   pgtype.Timestamp `json:"create_at"`
 	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
 }
+
+type RoomNightRevenue struct {
+	ID           pgtype.UUID      `json:"id"`
+	BookingID    pgtype.UUID      `json:"booking_id"`
+	HotelID      pgtype.UUID      `json:"hotel_id"`
+	BusinessDate pgtype.Date      `json:"business_date"`
+	Amount       int32            `json:"amount"`
+	CreateAt     pgtype.Timestamp `json:"create_at"`
+}
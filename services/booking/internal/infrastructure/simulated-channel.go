@@ -0,0 +1,81 @@
+package booking_infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Channel backed by json files for local end to end tests:
+// reservations are read from <dir>/reservations.json on every pull,
+// pushed availability and rates are written to <dir>/availability.json and <dir>/rates.json
+type SimulatedChannel struct {
+	name string
+	dir  string
+}
+
+func NewSimulatedChannel(name string, dir string) (*SimulatedChannel, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &SimulatedChannel{
+		name: name,
+		dir:  dir,
+	}, nil
+}
+
+func (sc *SimulatedChannel) Name() string {
+	return sc.name
+}
+
+func (sc *SimulatedChannel) PushAvailability(ctx context.Context, availabilities []booking_domain.ChannelAvailability) error {
+	zap.S().Infof("Simulated channel %s: push availability of %d nights", sc.name, len(availabilities))
+
+	return sc.writeFile("availability.json", availabilities)
+}
+
+func (sc *SimulatedChannel) PushRates(ctx context.Context, rates []booking_domain.ChannelRate) error {
+	zap.S().Infof("Simulated channel %s: push %d rates", sc.name, len(rates))
+
+	return sc.writeFile("rates.json", rates)
+}
+
+func (sc *SimulatedChannel) PullReservations(ctx context.Context) ([]booking_domain.ChannelReservation, error) {
+
+	data, err := os.ReadFile(filepath.Join(sc.dir, "reservations.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var reservations []booking_domain.ChannelReservation
+	if err := json.Unmarshal(data, &reservations); err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// Write to a temp file then rename so readers never see a partial file
+func (sc *SimulatedChannel) writeFile(name string, value any) error {
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(sc.dir, name)
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
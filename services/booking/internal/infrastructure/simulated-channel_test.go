@@ -0,0 +1,84 @@
+package booking_infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+)
+
+func TestSimulatedChannelPullReservations(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string // No reservations file when empty
+		want    int
+		wantErr bool
+	}{
+		{"nothing pulled yet", "", 0, false},
+		{"new and cancelled reservations", `[
+			{"external_id": "R1", "hotel_id": "h1", "room_type_id": "rt1", "number_of_rooms": 2, "check_in": "2026-03-05T00:00:00Z", "check_out": "2026-03-07T00:00:00Z", "total": 200, "guest_name": "Tran Van A"},
+			{"external_id": "R0", "hotel_id": "h1", "room_type_id": "rt1", "number_of_rooms": 1, "check_in": "2026-03-02T00:00:00Z", "check_out": "2026-03-03T00:00:00Z", "cancelled": true}
+		]`, 2, false},
+		{"malformed file", `{"external_id": `, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.content != "" {
+				if err := os.WriteFile(filepath.Join(dir, "reservations.json"), []byte(tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			channel, err := NewSimulatedChannel("SIMULATED", dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			reservations, err := channel.PullReservations(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PullReservations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(reservations) != tt.want {
+				t.Errorf("PullReservations() = %d reservations, want %d", len(reservations), tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulatedChannelPushAvailability(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "channel")
+	channel, err := NewSimulatedChannel("SIMULATED", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	availabilities := []booking_domain.ChannelAvailability{
+		{HotelId: "h1", RoomTypeId: "rt1", Date: time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC), Available: 3},
+	}
+	if err := channel.PushAvailability(context.Background(), availabilities); err != nil {
+		t.Fatalf("PushAvailability() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "availability.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []booking_domain.ChannelAvailability
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Available != 3 || !got[0].Date.Equal(availabilities[0].Date) {
+		t.Errorf("PushAvailability() wrote %+v, want %+v", got, availabilities)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "availability.json.tmp")); !os.IsNotExist(err) {
+		t.Errorf("PushAvailability() left its temp file, stat error = %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+
+	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Occupancy, ADR, RevPAR and booking pace of a hotel, room counts come from hotel service
+func (bg *BookingGrpcHandler) GetHotelAnalytics(ctx context.Context, req *booking_pb.GetHotelAnalyticsRequest) (*booking_pb.GetHotelAnalyticsResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel ID khong hop le")
+	}
+
+	startDate, endDate, err := utils.ToPgDateRange(req.GetStartDate(), req.GetEndDate())
+	if err != nil {
+		zap.S().Info("Invalid date range: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Date Range khong hop le")
+	}
+
+	roomCounts := make(map[string]int, len(req.GetRoomCounts()))
+	for _, roomCount := range req.GetRoomCounts() {
+		roomCounts[roomCount.GetRoomTypeId()] = int(roomCount.GetNumberOfRooms())
+	}
+
+	periods, err := bg.service.GetHotelAnalytics(ctx, &booking_service.HotelAnalyticsParams{
+		HotelId:     hotelId,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Granularity: req.GetGranularity(),
+		RoomCounts:  roomCounts,
+	})
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Tham so thong ke khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc thong ke")
+	}
+
+	return &booking_pb.GetHotelAnalyticsResponse{
+		Periods: toAnalyticsPeriodsPb(periods),
+	}, nil
+}
+
+// Analytics of several hotels in one call, room counts carry the hotel of their room type
+func (bg *BookingGrpcHandler) GetHotelsAnalytics(ctx context.Context, req *booking_pb.GetHotelsAnalyticsRequest) (*booking_pb.GetHotelsAnalyticsResponse, error) {
+
+	hotelIds, err := utils.ToPgUuidArray(req.GetHotelIds())
+	if err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel ID khong hop le")
+	}
+
+	startDate, endDate, err := utils.ToPgDateRange(req.GetStartDate(), req.GetEndDate())
+	if err != nil {
+		zap.S().Info("Invalid date range: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Date Range khong hop le")
+	}
+
+	roomCounts := make(map[string]map[string]int, len(hotelIds))
+	for _, roomCount := range req.GetRoomCounts() {
+		if roomCounts[roomCount.GetHotelId()] == nil {
+			roomCounts[roomCount.GetHotelId()] = map[string]int{}
+		}
+		roomCounts[roomCount.GetHotelId()][roomCount.GetRoomTypeId()] = int(roomCount.GetNumberOfRooms())
+	}
+
+	periodsByHotel, err := bg.service.GetHotelsAnalytics(ctx, &booking_service.HotelsAnalyticsParams{
+		HotelIds:    hotelIds,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Granularity: req.GetGranularity(),
+		RoomCounts:  roomCounts,
+	})
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Tham so thong ke khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc thong ke")
+	}
+
+	hotels := make([]*booking_pb.HotelAnalytics, 0, len(hotelIds))
+	for _, hotelId := range hotelIds {
+		hotels = append(hotels, &booking_pb.HotelAnalytics{
+			HotelId: hotelId.String(),
+			Periods: toAnalyticsPeriodsPb(periodsByHotel[hotelId.String()]),
+		})
+	}
+
+	return &booking_pb.GetHotelsAnalyticsResponse{
+		Hotels: hotels,
+	}, nil
+}
+
+func toAnalyticsPeriodsPb(periods []booking_service.AnalyticsPeriod) []*booking_pb.AnalyticsPeriod {
+	results := make([]*booking_pb.AnalyticsPeriod, 0, len(periods))
+	for _, period := range periods {
+		results = append(results, &booking_pb.AnalyticsPeriod{
+			PeriodStart:         period.PeriodStart.Format("2006-01-02"),
+			RoomTypeId:          period.RoomTypeId,
+			RoomNightsAvailable: int32(period.RoomNightsAvailable),
+			RoomNightsSold:      int32(period.RoomNightsSold),
+			RoomRevenue:         period.RoomRevenue,
+			OccupancyRate:       period.OccupancyRate,
+			Adr:                 period.Adr,
+			RevPar:              period.RevPar,
+			BookingsCreated:     int32(period.BookingsCreated),
+			RoomNightsBooked:    int32(period.RoomNightsBooked),
+			BookedRevenue:       period.BookedRevenue,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,57 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// History of a booking from creation to cancellation
+func (bg *BookingGrpcHandler) GetBookingHistory(ctx context.Context, req *booking_pb.GetBookingHistoryRequest) (*booking_pb.GetBookingHistoryResponse, error) {
+
+	var bookingId pgtype.UUID
+	if err := bookingId.Scan(req.GetBookingId()); err != nil {
+		zap.S().Info("Invalid Booking UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Booking UUID khong hop le")
+	}
+
+	events, pageInfo, err := bg.service.GetBookingHistory(ctx, bookingId, requesterFromContext(ctx), utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Khong tim thay booking")
+		case errors.Is(err, booking_service.ErrBookingAccessDenied):
+			return nil, status.Error(codes.PermissionDenied, "Khong co quyen xem lich su dat phong")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc lich su dat phong")
+	}
+
+	results := make([]*booking_pb.BookingEvent, 0, len(events))
+	for _, event := range events {
+		results = append(results, &booking_pb.BookingEvent{
+			Id:        event.ID.String(),
+			BookingId: event.BookingID.String(),
+			EventType: string(event.EventType),
+			Actor:     event.Actor,
+			Changes:   string(event.Changes),
+			Reason:    event.Reason.String,
+			CreateAt:  event.CreateAt.Time.Format(time.RFC3339),
+		})
+	}
+
+	return &booking_pb.GetBookingHistoryResponse{
+		Events: results,
+		Page:   utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
@@ -0,0 +1,109 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var searchableBookingStatuses = []booking_repo.BookingStatus{
+	booking_repo.BookingStatusBOOKED,
+	booking_repo.BookingStatusCHECKIN,
+	booking_repo.BookingStatusPAID,
+	booking_repo.BookingStatusCHECKOUT,
+	booking_repo.BookingStatusNOSHOW,
+}
+
+// Search bookings of a hotel for the front desk, or of every hotel in hotel_ids for chain managers
+func (bg *BookingGrpcHandler) SearchBookings(ctx context.Context, req *booking_pb.SearchBookingsRequest) (*booking_pb.SearchBookingsResponse, error) {
+
+	hotelIds := req.GetHotelIds()
+	if len(hotelIds) == 0 {
+		hotelIds = []string{req.GetHotelId()}
+	}
+
+	pgHotelIds, err := utils.ToPgUuidArray(hotelIds)
+	if err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel ID khong hop le")
+	}
+
+	params := &booking_service.SearchBookingsParams{
+		HotelIds:         pgHotelIds,
+		ConfirmationCode: req.GetConfirmationCode(),
+		Page:             utils.ToPageParams(req.GetPage()),
+	}
+
+	dates := []struct {
+		value string
+		dest  *pgtype.Date
+	}{
+		{req.GetCheckInFrom(), &params.CheckInFrom},
+		{req.GetCheckInTo(), &params.CheckInTo},
+		{req.GetCheckOutFrom(), &params.CheckOutFrom},
+		{req.GetCheckOutTo(), &params.CheckOutTo},
+	}
+	for _, date := range dates {
+		if date.value == "" {
+			continue
+		}
+		if err := date.dest.Scan(date.value); err != nil {
+			zap.S().Info("Invalid date format: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Invalid date format")
+		}
+	}
+
+	if req.GetStatus() != "" {
+		bookingStatus := booking_repo.BookingStatus(strings.ToUpper(req.GetStatus()))
+		if !slices.Contains(searchableBookingStatuses, bookingStatus) {
+			zap.S().Info("Invalid booking status: ", req.GetStatus())
+			return nil, status.Error(codes.InvalidArgument, "Trang thai dat phong khong hop le")
+		}
+		params.Status = string(bookingStatus)
+	}
+
+	if req.GetFilterByUserIds() {
+		userIds, err := utils.ToPgUuidArray(req.GetUserIds())
+		if err != nil {
+			zap.S().Info("Invalid User UUID: ", err)
+			return nil, status.Error(codes.InvalidArgument, "User ID khong hop le")
+		}
+		params.UserIds = append([]pgtype.UUID{}, userIds...)
+	}
+
+	if req.GetRoomId() != "" {
+		if err := params.RoomId.Scan(req.GetRoomId()); err != nil {
+			zap.S().Info("Invalid Room UUID: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Room ID khong hop le")
+		}
+	}
+
+	bookings, pageInfo, err := bg.service.SearchBookings(ctx, params)
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Tham so tim kiem khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong tim duoc dat phong")
+	}
+
+	results := make([]*booking_pb.Booking, 0, len(bookings))
+	for _, booking := range bookings {
+		results = append(results, toBookingPb(booking))
+	}
+
+	return &booking_pb.SearchBookingsResponse{
+		Bookings: results,
+		Page:     utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
@@ -0,0 +1,134 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Offer the booking to another user, by its owner or an admin
+func (bg *BookingGrpcHandler) TransferBooking(ctx context.Context, req *booking_pb.TransferBookingRequest) (*booking_pb.BookingTransfer, error) {
+
+	var bookingId pgtype.UUID
+	if err := bookingId.Scan(req.GetBookingId()); err != nil {
+		zap.S().Info("Invalid Booking UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Booking UUID khong hop le")
+	}
+
+	var targetUserId pgtype.UUID
+	if err := targetUserId.Scan(req.GetTargetUserId()); err != nil {
+		zap.S().Info("Invalid Target User UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "User UUID khong hop le")
+	}
+
+	// Admins may transfer without owning the booking
+	requester := requesterFromContext(ctx)
+	if !requester.UserId.Valid && !requester.IsAdmin {
+		return nil, status.Error(codes.Unauthenticated, "Phien dang nhap khong hop le")
+	}
+
+	transfer, err := bg.service.TransferBooking(ctx, bookingId, targetUserId, requester.UserId, requester.IsAdmin)
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Nguoi nhan khong hop le")
+		}
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay booking")
+		}
+		if errors.Is(err, booking_service.ErrTransferNotAllowed) {
+			return nil, status.Error(codes.PermissionDenied, "Khong co quyen chuyen booking")
+		}
+		if errors.Is(err, booking_service.ErrBookingNotTransferable) {
+			return nil, status.Error(codes.FailedPrecondition, "Booking khong the chuyen")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong chuyen duoc booking")
+	}
+
+	return toBookingTransferPb(*transfer), nil
+}
+
+// Recipient accepts or declines a pending transfer
+func (bg *BookingGrpcHandler) RespondBookingTransfer(ctx context.Context, req *booking_pb.RespondBookingTransferRequest) (*booking_pb.BookingTransfer, error) {
+
+	var transferId pgtype.UUID
+	if err := transferId.Scan(req.GetTransferId()); err != nil {
+		zap.S().Info("Invalid Booking Transfer UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Transfer UUID khong hop le")
+	}
+
+	userId := requesterFromContext(ctx).UserId
+	if !userId.Valid {
+		return nil, status.Error(codes.Unauthenticated, "Phien dang nhap khong hop le")
+	}
+
+	transfer, err := bg.service.RespondBookingTransfer(ctx, transferId, userId, req.GetAccept())
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay yeu cau chuyen booking")
+		}
+		if errors.Is(err, booking_service.ErrTransferNotAllowed) {
+			return nil, status.Error(codes.PermissionDenied, "Khong co quyen tra loi yeu cau chuyen booking")
+		}
+		if errors.Is(err, booking_service.ErrBookingNotTransferable) {
+			return nil, status.Error(codes.FailedPrecondition, "Yeu cau chuyen booking khong con hieu luc")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong tra loi duoc yeu cau chuyen booking")
+	}
+
+	return toBookingTransferPb(*transfer), nil
+}
+
+func (bg *BookingGrpcHandler) GetPendingBookingTransfers(ctx context.Context, req *booking_pb.GetPendingBookingTransfersRequest) (*booking_pb.GetPendingBookingTransfersResponse, error) {
+
+	userId := requesterFromContext(ctx).UserId
+	if !userId.Valid {
+		return nil, status.Error(codes.Unauthenticated, "Phien dang nhap khong hop le")
+	}
+
+	transfers, pageInfo, err := bg.service.GetPendingBookingTransfers(ctx, userId, utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc yeu cau chuyen booking")
+	}
+
+	results := make([]*booking_pb.BookingTransfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		results = append(results, toBookingTransferPb(transfer))
+	}
+
+	return &booking_pb.GetPendingBookingTransfersResponse{
+		Transfers: results,
+		Page:      utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
+
+func toBookingTransferPb(transfer booking_repo.BookingTransfer) *booking_pb.BookingTransfer {
+
+	respondedAt := ""
+	if transfer.RespondedAt.Valid {
+		respondedAt = transfer.RespondedAt.Time.Format(time.RFC3339)
+	}
+
+	return &booking_pb.BookingTransfer{
+		Id:          transfer.ID.String(),
+		BookingId:   transfer.BookingID.String(),
+		FromUserId:  transfer.FromUserID.String(),
+		ToUserId:    transfer.ToUserID.String(),
+		Status:      string(transfer.Status),
+		RequestedBy: transfer.RequestedBy,
+		RespondedAt: respondedAt,
+		CreateAt:    transfer.CreateAt.Time.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,103 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+
+	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Set the rooms of a room type a channel may sell per night
+func (bg *BookingGrpcHandler) SetChannelAllotment(ctx context.Context, req *booking_pb.SetChannelAllotmentRequest) (*booking_pb.ChannelAllotment, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	var roomTypeId pgtype.UUID
+	if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+		zap.S().Info("Invalid Room Type UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room Type UUID khong hop le")
+	}
+
+	allotment, err := bg.service.SetChannelAllotment(ctx, &booking_service.SetChannelAllotmentParams{
+		Channel:    req.GetChannel(),
+		HotelId:    hotelId,
+		RoomTypeId: roomTypeId,
+		Allotment:  int(req.GetAllotment()),
+	})
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Allotment khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc allotment")
+	}
+
+	return toChannelAllotmentPb(*allotment), nil
+}
+
+func (bg *BookingGrpcHandler) GetChannelAllotmentsByHotelId(ctx context.Context, req *booking_pb.GetChannelAllotmentsByHotelIdRequest) (*booking_pb.GetChannelAllotmentsByHotelIdResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	allotments, err := bg.service.GetChannelAllotmentsByHotelId(ctx, hotelId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc allotment")
+	}
+
+	results := make([]*booking_pb.ChannelAllotment, 0, len(allotments))
+	for _, allotment := range allotments {
+		results = append(results, toChannelAllotmentPb(allotment))
+	}
+
+	return &booking_pb.GetChannelAllotmentsByHotelIdResponse{
+		Allotments: results,
+	}, nil
+}
+
+func (bg *BookingGrpcHandler) DeleteChannelAllotmentById(ctx context.Context, req *booking_pb.DeleteChannelAllotmentByIdRequest) (*booking_pb.Empty, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid Allotment UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Allotment UUID khong hop le")
+	}
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	if err := bg.service.DeleteChannelAllotmentById(ctx, id, hotelId); err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay allotment")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong xoa duoc allotment")
+	}
+
+	return &booking_pb.Empty{}, nil
+}
+
+func toChannelAllotmentPb(allotment booking_repo.ChannelAllotment) *booking_pb.ChannelAllotment {
+	return &booking_pb.ChannelAllotment{
+		Id:         allotment.ID.String(),
+		Channel:    allotment.Channel,
+		HotelId:    allotment.HotelID.String(),
+		RoomTypeId: allotment.RoomTypeID.String(),
+		Allotment:  allotment.Allotment,
+	}
+}
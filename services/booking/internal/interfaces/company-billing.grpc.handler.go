@@ -0,0 +1,54 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (bg *BookingGrpcHandler) GetCompanyBookings(ctx context.Context, req *booking_pb.GetCompanyBookingsRequest) (*booking_pb.GetCompanyBookingsResponse, error) {
+
+	var companyId pgtype.UUID
+	if err := companyId.Scan(req.GetCompanyId()); err != nil {
+		zap.S().Info("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	var startDate pgtype.Date
+	if err := startDate.Scan(req.GetStartDate()); err != nil {
+		zap.S().Info("Invalid Start date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Start date khong hop le")
+	}
+
+	var endDate pgtype.Date
+	if err := endDate.Scan(req.GetEndDate()); err != nil {
+		zap.S().Info("Invalid End date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "End date khong hop le")
+	}
+
+	bookings, total, pageInfo, err := bg.service.GetCompanyBookings(ctx, companyId, startDate, endDate, utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Khoang thoi gian hoac trang khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc dat phong cua cong ty")
+	}
+
+	results := make([]*booking_pb.Booking, 0, len(bookings))
+	for _, booking := range bookings {
+		results = append(results, toBookingPb(booking))
+	}
+
+	return &booking_pb.GetCompanyBookingsResponse{
+		Bookings: results,
+		Total:    int32(total),
+		Page:     utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
@@ -0,0 +1,169 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (bg *BookingGrpcHandler) IssueGiftCard(ctx context.Context, req *booking_pb.IssueGiftCardRequest) (*booking_pb.GiftCard, error) {
+
+	var expiresAt pgtype.Timestamp
+	if req.GetExpiresAt() != "" {
+		expiresDate, err := time.Parse("2006-01-02", req.GetExpiresAt())
+		if err != nil {
+			zap.S().Info("Invalid Expires At date: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Ngay het han khong hop le")
+		}
+		expiresAt = pgtype.Timestamp{Time: expiresDate, Valid: true}
+	}
+
+	giftCard, err := bg.service.IssueGiftCard(ctx, int(req.GetAmount()), expiresAt)
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "So tien hoac ngay het han khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong phat hanh duoc the qua tang")
+	}
+
+	return toGiftCardPb(*giftCard), nil
+}
+
+func (bg *BookingGrpcHandler) VoidGiftCard(ctx context.Context, req *booking_pb.VoidGiftCardRequest) (*booking_pb.GiftCard, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid Gift Card UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Gift Card UUID khong hop le")
+	}
+
+	giftCard, err := bg.service.VoidGiftCard(ctx, id, optionalText(req.GetNote()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay the qua tang")
+		}
+		if errors.Is(err, booking_service.ErrGiftCardNotUsable) {
+			return nil, status.Error(codes.FailedPrecondition, "The qua tang da bi huy")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong huy duoc the qua tang")
+	}
+
+	return toGiftCardPb(*giftCard), nil
+}
+
+// Balance of a card for whoever holds its code
+func (bg *BookingGrpcHandler) GetGiftCardByCode(ctx context.Context, req *booking_pb.GetGiftCardByCodeRequest) (*booking_pb.GiftCard, error) {
+
+	giftCard, err := bg.service.GetGiftCardByCode(ctx, req.GetCode())
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay the qua tang")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc the qua tang")
+	}
+
+	return toGiftCardPb(*giftCard), nil
+}
+
+func (bg *BookingGrpcHandler) GetGiftCardTransactions(ctx context.Context, req *booking_pb.GetGiftCardTransactionsRequest) (*booking_pb.GetGiftCardTransactionsResponse, error) {
+
+	var giftCardId pgtype.UUID
+	if err := giftCardId.Scan(req.GetGiftCardId()); err != nil {
+		zap.S().Info("Invalid Gift Card UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Gift Card UUID khong hop le")
+	}
+
+	transactions, pageInfo, err := bg.service.GetGiftCardTransactions(ctx, giftCardId, utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc lich su the qua tang")
+	}
+
+	results := make([]*booking_pb.GiftCardTransaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		bookingId := ""
+		if transaction.BookingID.Valid {
+			bookingId = transaction.BookingID.String()
+		}
+
+		results = append(results, &booking_pb.GiftCardTransaction{
+			Id:         transaction.ID.String(),
+			GiftCardId: transaction.GiftCardID.String(),
+			Type:       string(transaction.Type),
+			Amount:     transaction.Amount,
+			BookingId:  bookingId,
+			Actor:      transaction.Actor,
+			Note:       transaction.Note.String,
+			CreateAt:   transaction.CreateAt.Time.Format(time.RFC3339),
+		})
+	}
+
+	return &booking_pb.GetGiftCardTransactionsResponse{
+		Transactions: results,
+		Page:         utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
+
+func (bg *BookingGrpcHandler) PayBookingWithGiftCard(ctx context.Context, req *booking_pb.PayBookingWithGiftCardRequest) (*booking_pb.PayBookingWithGiftCardResponse, error) {
+
+	var bookingId pgtype.UUID
+	if err := bookingId.Scan(req.GetBookingId()); err != nil {
+		zap.S().Info("Invalid Booking UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Booking UUID khong hop le")
+	}
+
+	result, err := bg.service.PayBookingWithGiftCard(ctx, bookingId, req.GetGiftCardCode(), int(req.GetAmount()), requesterFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "So tien khong hop le hoac booking da thanh toan")
+		}
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay booking")
+		}
+		if errors.Is(err, booking_service.ErrGiftCardNotUsable) {
+			return nil, status.Error(codes.FailedPrecondition, "The qua tang khong hop le, het han hoac het so du")
+		}
+		if errors.Is(err, booking_service.ErrBookingFinished) {
+			return nil, status.Error(codes.FailedPrecondition, "Dat phong da ket thuc, khong the thanh toan")
+		}
+		if errors.Is(err, booking_service.ErrCompanyBilledBooking) {
+			return nil, status.Error(codes.FailedPrecondition, "Dat phong duoc tinh cho cong ty, khong the thanh toan bang the qua tang")
+		}
+		if errors.Is(err, booking_service.ErrBookingAccessDenied) {
+			return nil, status.Error(codes.PermissionDenied, "Khong co quyen thanh toan booking")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong thanh toan duoc bang the qua tang")
+	}
+
+	return &booking_pb.PayBookingWithGiftCardResponse{
+		Paid:            int32(result.Paid),
+		Outstanding:     int32(result.Outstanding),
+		GiftCardBalance: int32(result.GiftCardBalance),
+		BookingStatus:   result.BookingStatus,
+	}, nil
+}
+
+func toGiftCardPb(giftCard booking_repo.GiftCard) *booking_pb.GiftCard {
+	return &booking_pb.GiftCard{
+		Id:             giftCard.ID.String(),
+		Code:           giftCard.Code,
+		InitialBalance: giftCard.InitialBalance,
+		Balance:        giftCard.Balance,
+		Status:         string(giftCard.Status),
+		ExpiresAt:      giftCard.ExpiresAt.Time.Format(time.RFC3339),
+		IssuedBy:       giftCard.IssuedBy,
+		CreateAt:       giftCard.CreateAt.Time.Format(time.RFC3339),
+	}
+}
@@ -2,9 +2,12 @@ package booking_handler
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	booking_domain "github.com/098765432m/grpc-kafka/booking/internal/domain"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
 	"github.com/098765432m/grpc-kafka/common/utils"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -24,67 +27,89 @@ func NewBookingGrpcHandler(service *booking_service.BookingService) *BookingGrpc
 	}
 }
 
-func (bg *BookingGrpcHandler) CreateBookings(ctx context.Context, req *booking_pb.NewBookingParam) (*booking_pb.Empty, error) {
+// Assign rooms and create bookings of a direct booking
+func (bg *BookingGrpcHandler) BookRooms(ctx context.Context, req *booking_pb.BookRoomsRequest) (*booking_pb.BookRoomsResponse, error) {
 
-	zap.L().Info("Create Bookings", zap.Any("Req", req.NewBookings))
-
-	newBookings := []booking_service.NewBooking{}
-	for _, param := range req.NewBookings {
-		zap.L().Info("New Booking: ", zap.Any("CheckIn", param.CheckIn), zap.Any("CheckOut", param.CheckOut), zap.Any("Total", param.Total), zap.Any("RoomTypeId", param.RoomTypeId), zap.Any("UserId", param.UserId), zap.Any("RoomId", param.RoomId))
-
-		// Chech are dates valid
-		var checkIn pgtype.Date
-		if err := checkIn.Scan(param.CheckIn); err != nil {
-			zap.S().Info("Invalid Check In date format on create bookings: ", err)
-			return nil, status.Error(codes.InvalidArgument, "Invalid Check In date format")
-		}
+	var checkIn pgtype.Date
+	if err := checkIn.Scan(req.GetCheckIn()); err != nil {
+		zap.S().Info("Invalid Check In date format on book rooms: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Check In khong hop le")
+	}
 
-		var checkOut pgtype.Date
-		if err := checkOut.Scan(param.CheckOut); err != nil {
-			zap.S().Info("Invalid Check Out date format on create bookings: ", err)
-			return nil, status.Error(codes.InvalidArgument, "Invalid Check Out date format")
-		}
+	var checkOut pgtype.Date
+	if err := checkOut.Scan(req.GetCheckOut()); err != nil {
+		zap.S().Info("Invalid Check Out date format on book rooms: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Check Out khong hop le")
+	}
 
-		// check In < Check Out
-		if checkIn.Time.After(checkOut.Time) {
-			zap.S().Info("Check In date must be before Check Out date")
-			return nil, status.Error(codes.InvalidArgument, "Check In date must be before Check Out date")
+	// Empty for guests booking without an account
+	var userId pgtype.UUID
+	if req.GetUserId() != "" {
+		if err := userId.Scan(req.GetUserId()); err != nil {
+			zap.S().Info("Invalid User UUID on book rooms: ", err)
+			return nil, status.Error(codes.InvalidArgument, "User UUID khong hop le")
 		}
+	}
 
-		// Check are UUIDs valid
+	rooms := make([]booking_service.RoomRequest, 0, len(req.GetRooms()))
+	for _, room := range req.GetRooms() {
 		var roomTypeId pgtype.UUID
-		if err := roomTypeId.Scan(param.RoomTypeId); err != nil {
-			zap.S().Info("Invalid Room Type UUID on create bookings: ", err)
-			return nil, status.Error(codes.InvalidArgument, "Invalid Room Type Id")
+		if err := roomTypeId.Scan(room.GetRoomTypeId()); err != nil {
+			zap.S().Info("Invalid Room Type UUID on book rooms: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Room Type UUID khong hop le")
 		}
 
-		var userId pgtype.UUID
-		if err := userId.Scan(param.UserId); err != nil {
-			zap.S().Info("Invalid User UUID on create bookings: ", err)
-			return nil, status.Error(codes.InvalidArgument, "Invalid User UUID")
-		}
-		var roomId pgtype.UUID
-		if err := roomId.Scan(param.RoomId); err != nil {
-			zap.S().Info("Invalid Room UUID on create bookings: ", err)
-			return nil, status.Error(codes.InvalidArgument, "Invalid Room Id")
+		guests, err := toBookingGuests(room.GetGuests())
+		if err != nil {
+			zap.S().Info("Invalid guests on book rooms: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Thong tin khach khong hop le")
 		}
 
-		newBookings = append(newBookings, booking_service.NewBooking{
-			CheckIn:    checkIn,
-			CheckOut:   checkOut,
-			Total:      int(param.Total),
-			RoomTypeId: roomTypeId,
-			UserId:     userId,
-			RoomId:     roomId,
+		rooms = append(rooms, booking_service.RoomRequest{
+			RoomTypeId:    roomTypeId,
+			NumberOfRooms: int(room.GetNumberOfRooms()),
+			Guests:        guests,
 		})
 	}
 
-	err := bg.service.CreateBookings(ctx, newBookings)
+	bookingIds, err := bg.service.BookRooms(ctx, &booking_service.BookRoomsParams{
+		CheckIn:            checkIn,
+		CheckOut:           checkOut,
+		Total:              int(req.GetTotal()),
+		UserId:             userId,
+		Rooms:              rooms,
+		AssignmentStrategy: req.GetAssignmentStrategy(),
+		AllowUpgrade:       req.GetAllowUpgrade(),
+		RedeemPoints:       int(req.GetRedeemPoints()),
+	})
 	if err != nil {
+		if errors.Is(err, booking_service.ErrNoRoomsAvailable) {
+			return nil, status.Error(codes.ResourceExhausted, "Khong con phong trong")
+		}
+		if errors.Is(err, booking_service.ErrHotelNotPublished) {
+			return nil, status.Error(codes.FailedPrecondition, "Khach san chua nhan dat phong")
+		}
+		if errors.Is(err, booking_service.ErrCreditLimitExceeded) {
+			return nil, status.Error(codes.FailedPrecondition, "Vuot qua han muc tin dung cua cong ty")
+		}
+		if errors.Is(err, booking_service.ErrNoCompanyRate) {
+			return nil, status.Error(codes.FailedPrecondition, "Cong ty chua co gia thoa thuan cho loai phong nay")
+		}
+		if errors.Is(err, booking_service.ErrInsufficientPoints) {
+			return nil, status.Error(codes.FailedPrecondition, "Khong du diem tich luy")
+		}
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Request khong hop le")
+		}
+		if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+			return nil, status.Error(codes.InvalidArgument, "Loai phong khong ton tai")
+		}
 		return nil, status.Error(codes.Internal, "Loi khong dat phong duoc")
 	}
 
-	return &booking_pb.Empty{}, nil
+	return &booking_pb.BookRoomsResponse{
+		BookingIds: utils.ToPgUuidString(bookingIds),
+	}, nil
 }
 
 func (bg *BookingGrpcHandler) DeleteBookingById(ctx context.Context, req *booking_pb.DeleteBookingByIdRequest) (*booking_pb.Empty, error) {
@@ -95,8 +120,16 @@ func (bg *BookingGrpcHandler) DeleteBookingById(ctx context.Context, req *bookin
 		return nil, status.Error(codes.InvalidArgument, "Booking UUID khong hop le")
 	}
 
-	err := bg.service.DeleteBookingById(ctx, id)
+	err := bg.service.DeleteBookingById(ctx, id, req.GetReason(), requesterFromContext(ctx))
 	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Khong tim thay booking")
+		case errors.Is(err, booking_service.ErrBookingAccessDenied):
+			return nil, status.Error(codes.PermissionDenied, "Khong co quyen huy booking")
+		case errors.Is(err, booking_service.ErrBookingNotCancellable):
+			return nil, status.Error(codes.FailedPrecondition, "Chi huy duoc booking chua nhan phong hoac da thanh toan")
+		}
 		return nil, status.Error(codes.Internal, "Loi khong xoa duoc booking")
 	}
 
@@ -111,14 +144,38 @@ func (bg *BookingGrpcHandler) DeleteBookingsByIds(ctx context.Context, req *book
 		return nil, status.Error(codes.InvalidArgument, "Booking UUID khong hop le")
 	}
 
-	err = bg.service.DeleteBookingsByIds(ctx, ids)
+	err = bg.service.DeleteBookingsByIds(ctx, ids, req.GetReason(), requesterFromContext(ctx))
 	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Khong tim thay booking")
+		case errors.Is(err, booking_service.ErrBookingAccessDenied):
+			return nil, status.Error(codes.PermissionDenied, "Khong co quyen huy booking")
+		case errors.Is(err, booking_service.ErrBookingNotCancellable):
+			return nil, status.Error(codes.FailedPrecondition, "Chi huy duoc booking chua nhan phong hoac da thanh toan")
+		}
 		return nil, status.Error(codes.Internal, "Loi khong xoa duoc booking")
 	}
 
 	return &booking_pb.Empty{}, nil
 }
 
+// Called by the hotel service before the hotel is deleted
+func (bg *BookingGrpcHandler) DeleteBookingsByHotelId(ctx context.Context, req *booking_pb.DeleteBookingsByHotelIdRequest) (*booking_pb.Empty, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID")
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	if err := bg.service.DeleteBookingsByHotelId(ctx, hotelId, req.GetReason()); err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong xoa duoc booking cua khach san")
+	}
+
+	return &booking_pb.Empty{}, nil
+}
+
 // Get Bookings List By UserId in range of booked date
 func (bg *BookingGrpcHandler) GetBookingsByUserId(ctx context.Context, req *booking_pb.GetBookingsByUserIdRequest) (*booking_pb.GetBookingsByUserIdResponse, error) {
 	var userId pgtype.UUID
@@ -133,38 +190,92 @@ func (bg *BookingGrpcHandler) GetBookingsByUserId(ctx context.Context, req *book
 		return nil, status.Error(codes.InvalidArgument, "Date Range khong hop le")
 	}
 
-	bookings, err := bg.service.GetBookingsByUserId(ctx, &booking_service.GetBookingsByUserIdParams{
+	bookings, pageInfo, err := bg.service.GetBookingsByUserId(ctx, &booking_service.GetBookingsByUserIdParams{
 		UserId:         userId,
 		CheckDateStart: checkInDate,
 		CheckDateEnd:   checkOutDate,
-		Size:           int(req.GetSize()),
-		Offset:         int(req.GetOffset()),
+		Page:           utils.ToPageParams(req.GetPage()),
 	})
 	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
 		return nil, status.Error(codes.Internal, "Loi khong lay duoc danh sach dat phong")
 	}
 
 	results := make([]*booking_pb.Booking, 0, len(bookings))
 	for _, booking := range bookings {
-		results = append(results, &booking_pb.Booking{
-			Id:         booking.Id,
-			UserId:     booking.UserId,
-			CheckIn:    booking.CheckIn.Format("2006-01-02"),
-			CheckOut:   booking.CheckOut.Format("2006-01-02"),
-			Total:      int32(booking.Total),
-			HotelId:    booking.HotelId,
-			RoomTypeId: booking.RoomTypeId,
-			RoomId:     booking.RoomId,
-			Status:     string(booking.Status),
-		})
+		results = append(results, toBookingPb(booking))
 	}
 
 	return &booking_pb.GetBookingsByUserIdResponse{
 		Bookings: results,
+		Page:     utils.ToPageResponsePb(pageInfo),
 	}, nil
 
 }
 
+func (bg *BookingGrpcHandler) GetBookingByConfirmationCode(ctx context.Context, req *booking_pb.GetBookingByConfirmationCodeRequest) (*booking_pb.Booking, error) {
+
+	if req.GetConfirmationCode() == "" {
+		zap.S().Infoln("Confirmation code is empty")
+		return nil, status.Error(codes.InvalidArgument, "Ma xac nhan khong hop le")
+	}
+
+	booking, err := bg.service.GetBookingByConfirmationCode(ctx, req.GetConfirmationCode())
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay booking")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc booking")
+	}
+
+	return toBookingPb(*booking), nil
+}
+
+func (bg *BookingGrpcHandler) ChangeBookingDates(ctx context.Context, req *booking_pb.ChangeBookingDatesRequest) (*booking_pb.Booking, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetBookingId()); err != nil {
+		zap.S().Infoln("Invalid Booking UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Booking UUID khong hop le")
+	}
+
+	var checkIn pgtype.Date
+	if err := checkIn.Scan(req.GetCheckIn()); err != nil {
+		zap.S().Infoln("Invalid Check In date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Check In khong hop le")
+	}
+
+	var checkOut pgtype.Date
+	if err := checkOut.Scan(req.GetCheckOut()); err != nil {
+		zap.S().Infoln("Invalid Check Out date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Check Out khong hop le")
+	}
+
+	booking, err := bg.service.ChangeBookingDates(ctx, id, checkIn, checkOut, req.GetReason())
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Request khong hop le")
+		}
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay booking")
+		}
+		if errors.Is(err, booking_service.ErrNoRoomsAvailable) {
+			return nil, status.Error(codes.ResourceExhausted, "Phong khong con trong vao ngay moi")
+		}
+		if errors.Is(err, booking_service.ErrCreditLimitExceeded) {
+			return nil, status.Error(codes.FailedPrecondition, "Vuot qua han muc tin dung cua cong ty")
+		}
+		if errors.Is(err, booking_service.ErrBookingFinished) {
+			return nil, status.Error(codes.FailedPrecondition, "Dat phong da ket thuc, khong the doi ngay")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong doi duoc ngay dat phong")
+	}
+
+	return toBookingPb(*booking), nil
+}
+
 // Return {roomTypeId, Number of occupied rooms in that room type}
 func (bg *BookingGrpcHandler) GetNumberOfOccupiedRooms(ctx context.Context, req *booking_pb.GetNumberOfOccupiedRoomsRequest) (*booking_pb.GetNumberOfOccupiedRoomsResponse, error) {
 	// Check Are room type Ids valid
@@ -281,3 +392,154 @@ func (bg *BookingGrpcHandler) GetUnavailableRoomsByRoomTypeId(ctx context.Contex
 	}, nil
 
 }
+
+// Return the highest number of overbooked bookings on a single night of the stay
+func (bg *BookingGrpcHandler) GetMaxUnassignedBookingsPerNight(ctx context.Context, req *booking_pb.GetMaxUnassignedBookingsPerNightRequest) (*booking_pb.GetMaxUnassignedBookingsPerNightResponse, error) {
+
+	var roomTypeId pgtype.UUID
+	if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+		zap.S().Info("Invalid Room Type UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room Type ID khong hop le")
+	}
+
+	var checkInDate pgtype.Date
+	if err := checkInDate.Scan(req.GetCheckIn()); err != nil {
+		zap.S().Info("Invalid date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid date format")
+	}
+
+	var checkOutDate pgtype.Date
+	if err := checkOutDate.Scan(req.GetCheckOut()); err != nil {
+		zap.S().Info("Invalid date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid date format")
+	}
+
+	if !checkInDate.Time.Before(checkOutDate.Time) {
+		zap.S().Info("Invalid date - Check In is not before Check out")
+		return nil, status.Error(codes.InvalidArgument, "Invalid check in check out")
+	}
+
+	result, err := bg.service.GetMaxUnassignedBookingsPerNight(ctx, roomTypeId, checkInDate, checkOutDate)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	return &booking_pb.GetMaxUnassignedBookingsPerNightResponse{
+		MaxUnassignedBookings: int32(result),
+	}, nil
+}
+
+// Return nights of a hotel that have bookings without assigned room
+func (bg *BookingGrpcHandler) GetOversoldNights(ctx context.Context, req *booking_pb.GetOversoldNightsRequest) (*booking_pb.GetOversoldNightsResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel ID khong hop le")
+	}
+
+	var startDate pgtype.Date
+	if err := startDate.Scan(req.GetStartDate()); err != nil {
+		zap.S().Info("Invalid date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid date format")
+	}
+
+	var endDate pgtype.Date
+	if err := endDate.Scan(req.GetEndDate()); err != nil {
+		zap.S().Info("Invalid date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid date format")
+	}
+
+	if !startDate.Time.Before(endDate.Time) {
+		zap.S().Info("Invalid date - Start date is not before End date")
+		return nil, status.Error(codes.InvalidArgument, "Date Range khong hop le")
+	}
+
+	results, err := bg.service.GetOversoldNights(ctx, hotelId, startDate, endDate)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc danh sach dem overbooking")
+	}
+
+	oversoldNights := make([]*booking_pb.OversoldNight, 0, len(results))
+	for _, result := range results {
+		bookingIds := make([]string, 0, len(result.BookingIds))
+		for _, bookingId := range result.BookingIds {
+			bookingIds = append(bookingIds, bookingId.String())
+		}
+
+		oversoldNights = append(oversoldNights, &booking_pb.OversoldNight{
+			Night:                      result.Night.Time.Format("2006-01-02"),
+			RoomTypeId:                 result.RoomTypeID.String(),
+			NumberOfUnassignedBookings: int32(result.NumberOfUnassignedBookings),
+			BookingIds:                 bookingIds,
+		})
+	}
+
+	return &booking_pb.GetOversoldNightsResponse{
+		OversoldNights: oversoldNights,
+	}, nil
+}
+
+// Return rooms that have a booking ending on check in or starting on check out, used to assign rooms without leaving gaps
+func (bg *BookingGrpcHandler) GetAdjacentBookedRoomsByRoomTypeId(ctx context.Context, req *booking_pb.GetAdjacentBookedRoomsByRoomTypeIdRequest) (*booking_pb.GetAdjacentBookedRoomsByRoomTypeIdResponse, error) {
+
+	var roomTypeId pgtype.UUID
+	if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+		zap.S().Info("Invalid Room Type UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room Type ID khong hop le")
+	}
+
+	var checkInDate pgtype.Date
+	if err := checkInDate.Scan(req.GetCheckIn()); err != nil {
+		zap.S().Info("Invalid date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid date format")
+	}
+
+	var checkOutDate pgtype.Date
+	if err := checkOutDate.Scan(req.GetCheckOut()); err != nil {
+		zap.S().Info("Invalid date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid date format")
+	}
+
+	results, err := bg.service.GetAdjacentBookedRoomsByRoomTypeId(ctx, roomTypeId, checkInDate, checkOutDate)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc danh sach phong ke can")
+	}
+
+	rooms := make([]*booking_pb.AdjacentBookedRoom, 0, len(results))
+	for _, result := range results {
+		rooms = append(rooms, &booking_pb.AdjacentBookedRoom{
+			RoomId:          result.RoomID.String(),
+			TouchesCheckIn:  result.TouchesCheckIn,
+			TouchesCheckOut: result.TouchesCheckOut,
+		})
+	}
+
+	return &booking_pb.GetAdjacentBookedRoomsByRoomTypeIdResponse{
+		Rooms: rooms,
+	}, nil
+}
+
+func toBookingPb(booking booking_domain.Booking) *booking_pb.Booking {
+	return &booking_pb.Booking{
+		Id:                   booking.Id,
+		UserId:               booking.UserId,
+		CheckIn:              booking.CheckIn.Format("2006-01-02"),
+		CheckOut:             booking.CheckOut.Format("2006-01-02"),
+		Total:                int32(booking.Total),
+		HotelId:              booking.HotelId,
+		RoomTypeId:           booking.RoomTypeId,
+		RoomId:               booking.RoomId,
+		Status:               string(booking.Status),
+		ConfirmationCode:     booking.ConfirmationCode,
+		Source:               booking.Source,
+		CompanyId:            booking.CompanyId,
+		GuestName:            booking.GuestName,
+		GuestEmail:           booking.GuestEmail,
+		GuestPhone:           booking.GuestPhone,
+		EstimatedArrivalTime: booking.EstimatedArrivalTime,
+
+		IsUpgraded:             booking.UpgradedFromRoomTypeId != "",
+		UpgradedFromRoomTypeId: booking.UpgradedFromRoomTypeId,
+	}
+}
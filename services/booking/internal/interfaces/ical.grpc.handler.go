@@ -0,0 +1,150 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Token to put in the exported .ics url of a room
+func (bg *BookingGrpcHandler) GetRoomIcalToken(ctx context.Context, req *booking_pb.GetRoomIcalTokenRequest) (*booking_pb.GetRoomIcalTokenResponse, error) {
+
+	var roomId pgtype.UUID
+	if err := roomId.Scan(req.GetRoomId()); err != nil {
+		zap.S().Info("Invalid Room UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room UUID khong hop le")
+	}
+
+	token, err := bg.service.GetRoomIcalToken(roomId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong tao duoc iCal token")
+	}
+
+	return &booking_pb.GetRoomIcalTokenResponse{
+		Token: token,
+	}, nil
+}
+
+// Booked and blocked nights of a room as iCalendar
+func (bg *BookingGrpcHandler) ExportRoomIcal(ctx context.Context, req *booking_pb.ExportRoomIcalRequest) (*booking_pb.ExportRoomIcalResponse, error) {
+
+	var roomId pgtype.UUID
+	if err := roomId.Scan(req.GetRoomId()); err != nil {
+		zap.S().Info("Invalid Room UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room UUID khong hop le")
+	}
+
+	calendar, err := bg.service.ExportRoomIcal(ctx, roomId, req.GetToken())
+	if err != nil {
+		if errors.Is(err, booking_service.ErrInvalidIcalToken) {
+			return nil, status.Error(codes.PermissionDenied, "iCal token khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong xuat duoc lich iCal")
+	}
+
+	return &booking_pb.ExportRoomIcalResponse{
+		Calendar: calendar,
+	}, nil
+}
+
+func (bg *BookingGrpcHandler) CreateIcalFeed(ctx context.Context, req *booking_pb.CreateIcalFeedRequest) (*booking_pb.IcalFeed, error) {
+
+	var roomId, roomTypeId, hotelId pgtype.UUID
+	if err := roomId.Scan(req.GetRoomId()); err != nil {
+		zap.S().Info("Invalid Room UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room UUID khong hop le")
+	}
+
+	if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+		zap.S().Info("Invalid Room Type UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room Type UUID khong hop le")
+	}
+
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	feed, err := bg.service.CreateIcalFeed(ctx, &booking_service.CreateIcalFeedParams{
+		RoomId:     roomId,
+		RoomTypeId: roomTypeId,
+		HotelId:    hotelId,
+		Url:        req.GetUrl(),
+	})
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "iCal url khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong tao duoc iCal feed")
+	}
+
+	return toIcalFeedPb(*feed), nil
+}
+
+func (bg *BookingGrpcHandler) GetIcalFeedsByRoomId(ctx context.Context, req *booking_pb.GetIcalFeedsByRoomIdRequest) (*booking_pb.GetIcalFeedsByRoomIdResponse, error) {
+
+	var roomId pgtype.UUID
+	if err := roomId.Scan(req.GetRoomId()); err != nil {
+		zap.S().Info("Invalid Room UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room UUID khong hop le")
+	}
+
+	feeds, err := bg.service.GetIcalFeedsByRoomId(ctx, roomId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc iCal feed")
+	}
+
+	results := make([]*booking_pb.IcalFeed, 0, len(feeds))
+	for _, feed := range feeds {
+		results = append(results, toIcalFeedPb(feed))
+	}
+
+	return &booking_pb.GetIcalFeedsByRoomIdResponse{
+		Feeds: results,
+	}, nil
+}
+
+func (bg *BookingGrpcHandler) DeleteIcalFeedById(ctx context.Context, req *booking_pb.DeleteIcalFeedByIdRequest) (*booking_pb.Empty, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid iCal Feed UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "iCal Feed UUID khong hop le")
+	}
+
+	if err := bg.service.DeleteIcalFeedById(ctx, id); err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay iCal feed")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong xoa duoc iCal feed")
+	}
+
+	return &booking_pb.Empty{}, nil
+}
+
+func toIcalFeedPb(feed booking_repo.IcalFeed) *booking_pb.IcalFeed {
+
+	lastSyncedAt := ""
+	if feed.LastSyncedAt.Valid {
+		lastSyncedAt = feed.LastSyncedAt.Time.Format(time.RFC3339)
+	}
+
+	return &booking_pb.IcalFeed{
+		Id:           feed.ID.String(),
+		RoomId:       feed.RoomID.String(),
+		RoomTypeId:   feed.RoomTypeID.String(),
+		HotelId:      feed.HotelID.String(),
+		Url:          feed.Url,
+		LastSyncedAt: lastSyncedAt,
+		LastError:    feed.LastError.String,
+	}
+}
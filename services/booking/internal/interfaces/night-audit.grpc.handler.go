@@ -0,0 +1,120 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (bg *BookingGrpcHandler) SetHotelAuditTime(ctx context.Context, req *booking_pb.SetHotelAuditTimeRequest) (*booking_pb.Empty, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel ID khong hop le")
+	}
+
+	auditTime, err := time.Parse("15:04", req.GetAuditTime())
+	if err != nil {
+		zap.S().Info("Invalid audit time format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Gio audit khong hop le")
+	}
+
+	err = bg.service.SetHotelAuditTime(ctx, hotelId, pgtype.Time{
+		Microseconds: time.Duration(auditTime.Hour()*int(time.Hour) + auditTime.Minute()*int(time.Minute)).Microseconds(),
+		Valid:        true,
+	}, req.GetTimezone())
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Mui gio khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc gio audit")
+	}
+
+	return &booking_pb.Empty{}, nil
+}
+
+// Manually run the night audit of a hotel for a business date
+func (bg *BookingGrpcHandler) RunNightAudit(ctx context.Context, req *booking_pb.RunNightAuditRequest) (*booking_pb.RunNightAuditResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel ID khong hop le")
+	}
+
+	var businessDate pgtype.Date
+	if err := businessDate.Scan(req.GetBusinessDate()); err != nil {
+		zap.S().Info("Invalid date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid date format")
+	}
+
+	report, err := bg.service.RunNightAudit(ctx, hotelId, businessDate)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong chay duoc night audit")
+	}
+
+	return &booking_pb.RunNightAuditResponse{
+		Report: toNightAuditReportPb(*report),
+	}, nil
+}
+
+func (bg *BookingGrpcHandler) GetNightAuditReports(ctx context.Context, req *booking_pb.GetNightAuditReportsRequest) (*booking_pb.GetNightAuditReportsResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel ID khong hop le")
+	}
+
+	var startDate pgtype.Date
+	if err := startDate.Scan(req.GetStartDate()); err != nil {
+		zap.S().Info("Invalid date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid date format")
+	}
+
+	var endDate pgtype.Date
+	if err := endDate.Scan(req.GetEndDate()); err != nil {
+		zap.S().Info("Invalid date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid date format")
+	}
+
+	reports, pageInfo, err := bg.service.GetNightAuditReports(ctx, hotelId, startDate, endDate, utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc bao cao night audit")
+	}
+
+	results := make([]*booking_pb.NightAuditReport, 0, len(reports))
+	for _, report := range reports {
+		results = append(results, toNightAuditReportPb(report))
+	}
+
+	return &booking_pb.GetNightAuditReportsResponse{
+		Reports: results,
+		Page:    utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
+
+func toNightAuditReportPb(report booking_repo.NightAuditReport) *booking_pb.NightAuditReport {
+	return &booking_pb.NightAuditReport{
+		Id:           report.ID.String(),
+		HotelId:      report.HotelID.String(),
+		BusinessDate: report.BusinessDate.Time.Format("2006-01-02"),
+		NoShows:      report.NoShows,
+		CheckOuts:    report.CheckOuts,
+		RoomNights:   report.RoomNights,
+		RoomRevenue:  report.RoomRevenue,
+	}
+}
@@ -0,0 +1,60 @@
+package booking_handler
+
+import (
+	"context"
+
+	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/model"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Signed in user of the JWT in the authorization metadata and guest of the manage booking token,
+// anonymous when there is none or they are invalid. Never nil, a nil requester skips the access check
+func requesterFromContext(ctx context.Context) *booking_service.BookingRequester {
+	requester := &booking_service.BookingRequester{}
+
+	if tokenStr := utils.UserTokenFromContext(ctx); tokenStr != "" {
+		claims, err := common_middleware.ParseUserClaims(tokenStr)
+		if err != nil {
+			zap.S().Infoln("Invalid JWT of requester: ", err)
+		} else {
+			requester = toBookingRequester(claims)
+		}
+	}
+
+	if tokenStr := utils.ManageBookingTokenFromContext(ctx); tokenStr != "" {
+		bookingId, err := common_middleware.ParseManageBookingToken(tokenStr)
+		if err != nil {
+			zap.S().Infoln("Invalid manage booking token of requester: ", err)
+		} else if err := requester.ManageBookingId.Scan(bookingId); err != nil {
+			zap.S().Infoln("Invalid Booking UUID of manage booking token: ", err)
+		}
+	}
+
+	return requester
+}
+
+func toBookingRequester(claims *common_middleware.UserClaims) *booking_service.BookingRequester {
+	requester := &booking_service.BookingRequester{
+		IsAdmin: claims.Role == model.ADMIN_ROLE,
+	}
+
+	if err := requester.UserId.Scan(claims.UserId); err != nil {
+		zap.S().Infoln("Invalid User UUID of requester: ", err)
+	}
+
+	// Managers access the bookings of their hotel
+	if claims.Role == model.MANAGER_ROLE && claims.HotelId != "" {
+		var hotelId pgtype.UUID
+		if err := hotelId.Scan(claims.HotelId); err != nil {
+			zap.S().Infoln("Invalid Hotel UUID of requester: ", err)
+		} else {
+			requester.HotelIds = []pgtype.UUID{hotelId}
+		}
+	}
+
+	return requester
+}
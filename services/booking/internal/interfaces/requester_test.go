@@ -0,0 +1,101 @@
+package booking_handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/model"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	requesterUserId  = "6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b"
+	requesterHotelId = "7a2d3c8f-4e5b-4f60-9bac-1d2e3f4a5b6c"
+	managedBookingId = "8b3e4d9a-5f6c-4a71-8cbd-2e3f4a5b6c7d"
+)
+
+func signUserToken(t *testing.T, secret string, role string, hotelId string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":      requesterUserId,
+		"role":     role,
+		"hotel_id": hotelId,
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestRequesterFromContext(t *testing.T) {
+	viper.Set("JWT_SECRET_KEY", "jwt-test-secret")
+	viper.Set("MANAGE_BOOKING_SECRET_KEY", "manage-booking-test-secret")
+	t.Cleanup(func() {
+		viper.Set("JWT_SECRET_KEY", "")
+		viper.Set("MANAGE_BOOKING_SECRET_KEY", "")
+	})
+
+	manageToken, err := common_middleware.NewManageBookingToken(managedBookingId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		userToken       string
+		manageToken     string
+		wantUser        bool
+		wantAdmin       bool
+		wantHotels      int
+		wantManageToken bool
+	}{
+		{"no token is anonymous", "", "", false, false, 0, false},
+		{"guest", signUserToken(t, "jwt-test-secret", model.GUEST_ROLE, ""), "", true, false, 0, false},
+		{"admin", signUserToken(t, "jwt-test-secret", model.ADMIN_ROLE, ""), "", true, true, 0, false},
+		{"manager of a hotel", signUserToken(t, "jwt-test-secret", model.MANAGER_ROLE, requesterHotelId), "", true, false, 1, false},
+		// Only managers are given the hotel of their token
+		{"guest with a hotel", signUserToken(t, "jwt-test-secret", model.GUEST_ROLE, requesterHotelId), "", true, false, 0, false},
+		{"token signed with another key", signUserToken(t, "other-secret", model.ADMIN_ROLE, ""), "", false, false, 0, false},
+		{"guest with a manage booking token", "", manageToken, false, false, 0, true},
+		// A user JWT is not a manage booking token
+		{"user token sent as manage booking token", "", signUserToken(t, "manage-booking-test-secret", model.GUEST_ROLE, ""), false, false, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.MD{}
+			if tt.userToken != "" {
+				md.Set(utils.AUTHORIZATION_METADATA_KEY, "Bearer "+tt.userToken)
+			}
+			if tt.manageToken != "" {
+				md.Set(utils.MANAGE_BOOKING_TOKEN_METADATA_KEY, tt.manageToken)
+			}
+
+			requester := requesterFromContext(metadata.NewIncomingContext(context.Background(), md))
+
+			if requester == nil {
+				t.Fatal("requesterFromContext() = nil, a nil requester skips the access check")
+			}
+			if requester.UserId.Valid != tt.wantUser {
+				t.Errorf("requesterFromContext() user = %v, want user %v", requester.UserId, tt.wantUser)
+			}
+			if requester.IsAdmin != tt.wantAdmin {
+				t.Errorf("requesterFromContext() admin = %v, want %v", requester.IsAdmin, tt.wantAdmin)
+			}
+			if len(requester.HotelIds) != tt.wantHotels {
+				t.Errorf("requesterFromContext() hotels = %v, want %d", requester.HotelIds, tt.wantHotels)
+			}
+			if requester.ManageBookingId.Valid != tt.wantManageToken {
+				t.Errorf("requesterFromContext() manage booking = %v, want booking %v", requester.ManageBookingId, tt.wantManageToken)
+			}
+		})
+	}
+}
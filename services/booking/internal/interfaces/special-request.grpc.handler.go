@@ -0,0 +1,151 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	booking_service "github.com/098765432m/grpc-kafka/booking/internal/application"
+	booking_repo_mapping "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (bg *BookingGrpcHandler) GetSpecialRequestsByHotelId(ctx context.Context, req *booking_pb.GetSpecialRequestsByHotelIdRequest) (*booking_pb.GetSpecialRequestsByHotelIdResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	var startDate pgtype.Date
+	if err := startDate.Scan(req.GetStartDate()); err != nil {
+		zap.S().Info("Invalid Start date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Start date khong hop le")
+	}
+
+	var endDate pgtype.Date
+	if err := endDate.Scan(req.GetEndDate()); err != nil {
+		zap.S().Info("Invalid End date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "End date khong hop le")
+	}
+
+	specialRequests, err := bg.service.GetSpecialRequestsByHotelId(ctx, &booking_service.GetSpecialRequestsByHotelIdParams{
+		HotelId:            hotelId,
+		StartDate:          startDate,
+		EndDate:            endDate,
+		OnlyUnacknowledged: req.GetOnlyUnacknowledged(),
+	})
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Khoang thoi gian khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc yeu cau dac biet")
+	}
+
+	results := make([]*booking_pb.SpecialRequest, 0, len(specialRequests))
+	for _, specialRequest := range specialRequests {
+		results = append(results, toSpecialRequestPb(specialRequest))
+	}
+
+	return &booking_pb.GetSpecialRequestsByHotelIdResponse{
+		SpecialRequests: results,
+	}, nil
+}
+
+func (bg *BookingGrpcHandler) AcknowledgeSpecialRequest(ctx context.Context, req *booking_pb.AcknowledgeSpecialRequestRequest) (*booking_pb.Empty, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid Special Request UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Special Request UUID khong hop le")
+	}
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	if err := bg.service.AcknowledgeSpecialRequest(ctx, id, hotelId); err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay yeu cau dac biet")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong xac nhan duoc yeu cau dac biet")
+	}
+
+	return &booking_pb.Empty{}, nil
+}
+
+// Guests of a booked room type from request, all fields are optional
+func toBookingGuests(guestsPb []*booking_pb.BookingGuest) ([]booking_service.BookingGuest, error) {
+
+	guests := make([]booking_service.BookingGuest, 0, len(guestsPb))
+	for _, guestPb := range guestsPb {
+		guest := booking_service.BookingGuest{
+			Name:  optionalText(guestPb.GetName()),
+			Email: optionalText(guestPb.GetEmail()),
+			Phone: optionalText(guestPb.GetPhone()),
+		}
+
+		if guestPb.GetEstimatedArrivalTime() != "" {
+			arrivalTime, err := time.Parse("15:04", guestPb.GetEstimatedArrivalTime())
+			if err != nil {
+				return nil, err
+			}
+
+			guest.EstimatedArrivalTime = pgtype.Time{
+				Microseconds: time.Duration(arrivalTime.Hour()*int(time.Hour) + arrivalTime.Minute()*int(time.Minute)).Microseconds(),
+				Valid:        true,
+			}
+		}
+
+		for _, specialRequest := range guestPb.GetSpecialRequests() {
+			guest.SpecialRequests = append(guest.SpecialRequests, booking_service.SpecialRequest{
+				Type: booking_repo.SpecialRequestType(strings.ToUpper(specialRequest.GetType())),
+				Note: strings.TrimSpace(specialRequest.GetNote()),
+			})
+		}
+
+		guests = append(guests, guest)
+	}
+
+	return guests, nil
+}
+
+func optionalText(value string) pgtype.Text {
+	value = strings.TrimSpace(value)
+	return pgtype.Text{String: value, Valid: value != ""}
+}
+
+func toSpecialRequestPb(specialRequest booking_repo.GetSpecialRequestsByHotelIdRow) *booking_pb.SpecialRequest {
+
+	acknowledgedAt := ""
+	if specialRequest.AcknowledgedAt.Valid {
+		acknowledgedAt = specialRequest.AcknowledgedAt.Time.Format(time.RFC3339)
+	}
+
+	return &booking_pb.SpecialRequest{
+		Id:                   specialRequest.ID.String(),
+		BookingId:            specialRequest.BookingID.String(),
+		Type:                 string(specialRequest.Type),
+		Note:                 specialRequest.Note,
+		IsAcknowledged:       specialRequest.AcknowledgedAt.Valid,
+		AcknowledgedAt:       acknowledgedAt,
+		AcknowledgedBy:       specialRequest.AcknowledgedBy.String,
+		CheckIn:              specialRequest.CheckIn.Time.Format("2006-01-02"),
+		CheckOut:             specialRequest.CheckOut.Time.Format("2006-01-02"),
+		RoomTypeId:           specialRequest.RoomTypeID.String(),
+		RoomId:               specialRequest.RoomID.String(),
+		ConfirmationCode:     specialRequest.ConfirmationCode,
+		GuestName:            specialRequest.GuestName.String,
+		EstimatedArrivalTime: booking_repo_mapping.FromPgTimeToClock(specialRequest.EstimatedArrivalTime),
+	}
+}
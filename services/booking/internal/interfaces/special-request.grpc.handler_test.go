@@ -0,0 +1,79 @@
+package booking_handler
+
+import (
+	"testing"
+
+	booking_repo_mapping "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository"
+	booking_repo "github.com/098765432m/grpc-kafka/booking/internal/infrastructure/repository/sqlc/booking"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+)
+
+func TestToBookingGuests(t *testing.T) {
+	tests := []struct {
+		name            string
+		guest           *booking_pb.BookingGuest
+		wantName        string
+		wantEmailValid  bool
+		wantArrival     string
+		wantRequestType booking_repo.SpecialRequestType
+		wantRequestNote string
+		wantErr         bool
+	}{
+		{
+			name:            "occupant with requests",
+			guest:           &booking_pb.BookingGuest{Name: " Tran Van A ", Email: "a@example.com", EstimatedArrivalTime: "21:30", SpecialRequests: []*booking_pb.SpecialRequestParam{{Type: "high_floor", Note: "  away from the lift "}}},
+			wantName:        "Tran Van A",
+			wantEmailValid:  true,
+			wantArrival:     "21:30",
+			wantRequestType: booking_repo.SpecialRequestTypeHIGHFLOOR,
+			wantRequestNote: "away from the lift",
+		},
+		{
+			name:  "every field is optional",
+			guest: &booking_pb.BookingGuest{Email: "   "},
+		},
+		{
+			name:    "arrival time is not HH:MM",
+			guest:   &booking_pb.BookingGuest{EstimatedArrivalTime: "9pm"},
+			wantErr: true,
+		},
+		{
+			name:    "arrival time out of the day",
+			guest:   &booking_pb.BookingGuest{EstimatedArrivalTime: "25:00"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guests, err := toBookingGuests([]*booking_pb.BookingGuest{tt.guest})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("toBookingGuests() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			guest := guests[0]
+			if guest.Name.String != tt.wantName || guest.Name.Valid != (tt.wantName != "") {
+				t.Errorf("toBookingGuests() name = %+v, want %q", guest.Name, tt.wantName)
+			}
+			if guest.Email.Valid != tt.wantEmailValid {
+				t.Errorf("toBookingGuests() email = %+v, want valid %v", guest.Email, tt.wantEmailValid)
+			}
+			if got := booking_repo_mapping.FromPgTimeToClock(guest.EstimatedArrivalTime); got != tt.wantArrival {
+				t.Errorf("toBookingGuests() arrival time = %q, want %q", got, tt.wantArrival)
+			}
+
+			if tt.wantRequestType == "" {
+				if len(guest.SpecialRequests) != 0 {
+					t.Errorf("toBookingGuests() special requests = %+v, want none", guest.SpecialRequests)
+				}
+				return
+			}
+			if len(guest.SpecialRequests) != 1 || guest.SpecialRequests[0].Type != tt.wantRequestType || guest.SpecialRequests[0].Note != tt.wantRequestNote {
+				t.Errorf("toBookingGuests() special requests = %+v, want %s %q", guest.SpecialRequests, tt.wantRequestType, tt.wantRequestNote)
+			}
+		})
+	}
+}
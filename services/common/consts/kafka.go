@@ -0,0 +1,12 @@
+package consts
+
+// Booking topics
+const BOOKING_CREATED_TOPIC = "hotel.booking.created"
+const BOOKING_STATUS_CHANGED_TOPIC = "hotel.booking.status-changed"
+const BOOKING_TRANSFER_TOPIC = "hotel.booking.transfer"
+
+// Booking status changes the loyalty service failed to handle
+const LOYALTY_BOOKING_STATUS_CHANGED_DLQ_TOPIC = "hotel.loyalty.booking-status-changed.dlq"
+
+// Hotel topics
+const HOTEL_STATUS_CHANGED_TOPIC = "hotel.hotel.status-changed"
@@ -30,3 +30,6 @@ const BOOKING_GRPC_PORT = 50056
 // Rating port
 const RATING_HTTP_PORT = 3107
 const RATING_GRPC_PORT = 50057
+
+// Loyalty port
+const LOYALTY_GRPC_PORT = 50060
@@ -0,0 +1,220 @@
+package common_middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/model"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Keys of the signed in user in gin context
+const (
+	AUTH_USER_ID_KEY  = "auth_user_id"
+	AUTH_ROLE_KEY     = "auth_role"
+	AUTH_HOTEL_ID_KEY = "auth_hotel_id"
+	AUTH_CHAIN_ID_KEY = "auth_chain_id"
+	AUTH_TOKEN_KEY    = "auth_token" // verified JWT, sent on to services that check the user themselves
+)
+
+// Verify JWT from the "user" cookie or Authorization Bearer header and put its claims in gin context
+func AuthMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tokenStr := getUserToken(ctx)
+		if tokenStr == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, utils.ErrorApiResponse("Chua dang nhap"))
+			return
+		}
+
+		if err := setUserClaims(ctx, tokenStr); err != nil {
+			zap.S().Infoln("Invalid JWT: ", err)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, utils.ErrorApiResponse("Phien dang nhap khong hop le"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Same as AuthMiddleware but guests without a valid JWT can still pass
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if tokenStr := getUserToken(ctx); tokenStr != "" {
+			if err := setUserClaims(ctx, tokenStr); err != nil {
+				zap.S().Infoln("Invalid JWT: ", err)
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+// Only ADMIN, MANAGER of the hotel in ":id" param, CHAIN_MANAGER of its chain or the user who created its draft can pass,
+// the hotel service is asked when the JWT alone does not allow it. Must be used after AuthMiddleware
+func RequireHotelManager(hotelClient hotel_pb.HotelServiceClient) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := ctx.GetString(AUTH_ROLE_KEY)
+		if role == model.ADMIN_ROLE {
+			ctx.Next()
+			return
+		}
+
+		if role != model.MANAGER_ROLE && role != model.CHAIN_MANAGER_ROLE {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, utils.ErrorApiResponse("Khong co quyen truy cap"))
+			return
+		}
+
+		if hotelId := ctx.GetString(AUTH_HOTEL_ID_KEY); hotelId != "" && hotelId == ctx.Param("id") {
+			ctx.Next()
+			return
+		}
+
+		result, err := hotelClient.CanManageHotel(utils.WithUserToken(ctx, ctx.GetString(AUTH_TOKEN_KEY)), &hotel_pb.CanManageHotelRequest{
+			HotelId: ctx.Param("id"),
+		})
+		if err != nil {
+			switch status.Code(err) {
+			case codes.InvalidArgument, codes.NotFound:
+				ctx.AbortWithStatusJSON(http.StatusNotFound, utils.ErrorApiResponse("Khach san khong ton tai"))
+				return
+			}
+
+			zap.S().Errorln("Failed to check Hotel manager: ", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, utils.ErrorApiResponse("Loi he thong khi kiem tra quyen truy cap"))
+			return
+		}
+
+		if !result.GetAllowed() {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, utils.ErrorApiResponse("Khong co quyen truy cap khach san nay"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Only CHAIN_MANAGER of the chain in ":id" param or ADMIN can pass, must be used after AuthMiddleware
+func RequireChainManager() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := ctx.GetString(AUTH_ROLE_KEY)
+		if role == model.ADMIN_ROLE {
+			ctx.Next()
+			return
+		}
+
+		if role != model.CHAIN_MANAGER_ROLE {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, utils.ErrorApiResponse("Khong co quyen truy cap"))
+			return
+		}
+
+		chainId := ctx.GetString(AUTH_CHAIN_ID_KEY)
+		if chainId == "" || chainId != ctx.Param("id") {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, utils.ErrorApiResponse("Khong co quyen truy cap chuoi khach san nay"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Only ADMIN can pass, must be used after AuthMiddleware
+func RequireAdmin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetString(AUTH_ROLE_KEY) != model.ADMIN_ROLE {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, utils.ErrorApiResponse("Khong co quyen truy cap"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Only users with one of roles can pass, must be used after AuthMiddleware
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !slices.Contains(roles, ctx.GetString(AUTH_ROLE_KEY)) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, utils.ErrorApiResponse("Khong co quyen truy cap"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func getUserToken(ctx *gin.Context) string {
+	tokenStr, err := ctx.Cookie("user")
+	if err != nil || tokenStr == "" {
+		tokenStr = strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	}
+
+	return tokenStr
+}
+
+func setUserClaims(ctx *gin.Context, tokenStr string) error {
+	claims, err := ParseUserClaims(tokenStr)
+	if err != nil {
+		return err
+	}
+
+	ctx.Set(AUTH_USER_ID_KEY, claims.UserId)
+	ctx.Set(AUTH_ROLE_KEY, claims.Role)
+	ctx.Set(AUTH_HOTEL_ID_KEY, claims.HotelId)
+	ctx.Set(AUTH_CHAIN_ID_KEY, claims.ChainId)
+	ctx.Set(AUTH_TOKEN_KEY, tokenStr)
+
+	return nil
+}
+
+// Signed in user of a JWT
+type UserClaims struct {
+	UserId  string
+	Role    string
+	HotelId string // hotel of a MANAGER
+	ChainId string // chain of a CHAIN_MANAGER
+}
+
+// Verify JWT of the signed in user, for services called with the token outside of the gateway
+func ParseUserClaims(tokenStr string) (*UserClaims, error) {
+	claims, err := parseUserToken(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	userId, _ := claims.GetSubject()
+	role, _ := claims["role"].(string)
+	hotelId, _ := claims["hotel_id"].(string)
+	chainId, _ := claims["chain_id"].(string)
+
+	return &UserClaims{
+		UserId:  userId,
+		Role:    role,
+		HotelId: hotelId,
+		ChainId: chainId,
+	}, nil
+}
+
+func parseUserToken(tokenStr string) (jwt.MapClaims, error) {
+	secretKey := viper.GetString("JWT_SECRET_KEY")
+	if secretKey == "" {
+		return nil, errors.New("JWT_SECRET_KEY is not set")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
+		return []byte(secretKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,90 @@
+package common_middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/model"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Hotel client answering only CanManageHotel, any other call panics
+type fakeHotelClient struct {
+	hotel_pb.HotelServiceClient
+	allowed bool
+	err     error
+	calls   int
+	token   string
+}
+
+func (fc *fakeHotelClient) CanManageHotel(ctx context.Context, in *hotel_pb.CanManageHotelRequest, opts ...grpc.CallOption) (*hotel_pb.CanManageHotelResponse, error) {
+	fc.calls++
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		if values := md.Get(utils.AUTHORIZATION_METADATA_KEY); len(values) > 0 {
+			fc.token = values[0]
+		}
+	}
+
+	if fc.err != nil {
+		return nil, fc.err
+	}
+	return &hotel_pb.CanManageHotelResponse{Allowed: fc.allowed}, nil
+}
+
+func TestRequireHotelManager(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		role       string
+		hotelId    string
+		client     *fakeHotelClient
+		wantStatus int
+		wantCalls  int
+	}{
+		{"admin", model.ADMIN_ROLE, "", &fakeHotelClient{}, http.StatusOK, 0},
+		{"manager of the hotel", model.MANAGER_ROLE, "hotel-1", &fakeHotelClient{}, http.StatusOK, 0},
+		{"guest", model.GUEST_ROLE, "hotel-1", &fakeHotelClient{allowed: true}, http.StatusForbidden, 0},
+		{"creator of the draft", model.MANAGER_ROLE, "", &fakeHotelClient{allowed: true}, http.StatusOK, 1},
+		{"manager of another hotel", model.MANAGER_ROLE, "hotel-2", &fakeHotelClient{}, http.StatusForbidden, 1},
+		{"chain manager of the hotel", model.CHAIN_MANAGER_ROLE, "", &fakeHotelClient{allowed: true}, http.StatusOK, 1},
+		{"chain manager of another chain", model.CHAIN_MANAGER_ROLE, "", &fakeHotelClient{}, http.StatusForbidden, 1},
+		{"missing hotel", model.MANAGER_ROLE, "", &fakeHotelClient{err: status.Error(codes.NotFound, "not found")}, http.StatusNotFound, 1},
+		{"hotel service down", model.MANAGER_ROLE, "", &fakeHotelClient{err: status.Error(codes.Unavailable, "down")}, http.StatusInternalServerError, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/hotels/:id", func(ctx *gin.Context) {
+				ctx.Set(AUTH_USER_ID_KEY, "user-1")
+				ctx.Set(AUTH_ROLE_KEY, tt.role)
+				ctx.Set(AUTH_HOTEL_ID_KEY, tt.hotelId)
+				ctx.Set(AUTH_TOKEN_KEY, "token-1")
+			}, RequireHotelManager(tt.client), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/hotels/hotel-1", nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("RequireHotelManager() status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.client.calls != tt.wantCalls {
+				t.Errorf("CanManageHotel() calls = %d, want %d", tt.client.calls, tt.wantCalls)
+			}
+			if tt.client.calls > 0 && tt.client.token != "Bearer token-1" {
+				t.Errorf("CanManageHotel() token = %q, want the JWT of the user", tt.client.token)
+			}
+		})
+	}
+}
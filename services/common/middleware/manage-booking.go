@@ -0,0 +1,92 @@
+package common_middleware
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Keys of the booking a manage booking token was issued for and of the verified token in gin context
+const (
+	MANAGE_BOOKING_ID_KEY    = "manage_booking_id"
+	MANAGE_BOOKING_TOKEN_KEY = "manage_booking_token" // sent on to the booking service that checks it again
+)
+
+const (
+	MANAGE_BOOKING_TOKEN_HEADER = "X-Manage-Token"
+	MANAGE_BOOKING_TOKEN_TTL    = 30 * time.Minute
+	manageBookingScope          = "manage_booking"
+)
+
+// Sign a short lived token that lets a guest cancel or modify one booking without an account
+func NewManageBookingToken(bookingId string) (string, error) {
+	secretKey := viper.GetString("MANAGE_BOOKING_SECRET_KEY")
+	if secretKey == "" {
+		return "", errors.New("MANAGE_BOOKING_SECRET_KEY is not set")
+	}
+
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   bookingId,
+		"scope": manageBookingScope,
+		"exp":   time.Now().Add(MANAGE_BOOKING_TOKEN_TTL).Unix(),
+	})
+
+	return t.SignedString([]byte(secretKey))
+}
+
+// Verify manage booking token from X-Manage-Token header and put its booking id in gin context
+func ManageBookingMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tokenStr := ctx.GetHeader(MANAGE_BOOKING_TOKEN_HEADER)
+		if tokenStr == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, utils.ErrorApiResponse("Thieu ma quan ly dat phong"))
+			return
+		}
+
+		bookingId, err := ParseManageBookingToken(tokenStr)
+		if err != nil {
+			zap.S().Infoln("Invalid manage booking token: ", err)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, utils.ErrorApiResponse("Ma quan ly dat phong khong hop le"))
+			return
+		}
+
+		ctx.Set(MANAGE_BOOKING_ID_KEY, bookingId)
+		ctx.Set(MANAGE_BOOKING_TOKEN_KEY, tokenStr)
+
+		ctx.Next()
+	}
+}
+
+// Booking id of a valid manage booking token
+func ParseManageBookingToken(tokenStr string) (string, error) {
+	secretKey := viper.GetString("MANAGE_BOOKING_SECRET_KEY")
+	if secretKey == "" {
+		return "", errors.New("MANAGE_BOOKING_SECRET_KEY is not set")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
+		return []byte(secretKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return "", err
+	}
+
+	// User JWTs are signed with another key, still never accept a token of another scope
+	if scope, _ := claims["scope"].(string); scope != manageBookingScope {
+		return "", errors.New("token is not a manage booking token")
+	}
+
+	bookingId, err := claims.GetSubject()
+	if err != nil || bookingId == "" {
+		return "", errors.New("token has no booking id")
+	}
+
+	return bookingId, nil
+}
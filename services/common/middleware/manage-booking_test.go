@@ -0,0 +1,120 @@
+package common_middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+)
+
+const testManageBookingSecret = "manage-booking-test-secret"
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestParseManageBookingToken(t *testing.T) {
+	viper.Set("MANAGE_BOOKING_SECRET_KEY", testManageBookingSecret)
+	t.Cleanup(func() { viper.Set("MANAGE_BOOKING_SECRET_KEY", "") })
+
+	issued, err := NewManageBookingToken("booking-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := []byte(testManageBookingSecret)
+	exp := time.Now().Add(time.Minute).Unix()
+
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr bool
+	}{
+		{"issued token", issued, "booking-1", false},
+		{"expired", signTestToken(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "booking-1", "scope": manageBookingScope, "exp": time.Now().Add(-time.Minute).Unix()}), "", true},
+		{"no expiry", signTestToken(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "booking-1", "scope": manageBookingScope}), "", true},
+		{"signed with another key", signTestToken(t, jwt.SigningMethodHS256, []byte("another-secret"), jwt.MapClaims{"sub": "booking-1", "scope": manageBookingScope, "exp": exp}), "", true},
+		{"another signing method", signTestToken(t, jwt.SigningMethodHS512, secret, jwt.MapClaims{"sub": "booking-1", "scope": manageBookingScope, "exp": exp}), "", true},
+		{"user token of the same key", signTestToken(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "user-1", "role": "ADMIN", "exp": exp}), "", true},
+		{"no booking", signTestToken(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"scope": manageBookingScope, "exp": exp}), "", true},
+		{"tampered", issued + "x", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseManageBookingToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseManageBookingToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseManageBookingToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewManageBookingTokenWithoutSecret(t *testing.T) {
+	viper.Set("MANAGE_BOOKING_SECRET_KEY", "")
+
+	if _, err := NewManageBookingToken("booking-1"); err == nil {
+		t.Error("NewManageBookingToken() without secret error = nil, want an error")
+	}
+}
+
+func TestManageBookingMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	viper.Set("MANAGE_BOOKING_SECRET_KEY", testManageBookingSecret)
+	t.Cleanup(func() { viper.Set("MANAGE_BOOKING_SECRET_KEY", "") })
+
+	issued, err := NewManageBookingToken("booking-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		wantCode      int
+		wantBookingId string
+	}{
+		{"valid token", issued, http.StatusOK, "booking-1"},
+		{"no token", "", http.StatusUnauthorized, ""},
+		{"invalid token", "not-a-token", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookingId := ""
+			router := gin.New()
+			router.POST("/bookings/manage/cancel", ManageBookingMiddleware(), func(ctx *gin.Context) {
+				bookingId = ctx.GetString(MANAGE_BOOKING_ID_KEY)
+				ctx.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/bookings/manage/cancel", nil)
+			if tt.token != "" {
+				req.Header.Set(MANAGE_BOOKING_TOKEN_HEADER, tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantCode {
+				t.Errorf("ManageBookingMiddleware() status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+			if bookingId != tt.wantBookingId {
+				t.Errorf("ManageBookingMiddleware() booking = %q, want %q", bookingId, tt.wantBookingId)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package common_middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Counts requests of a key in its current window, returns the count and the time left in the window
+type rateLimitStore interface {
+	increment(ctx context.Context, key string, window time.Duration) (int, time.Duration, error)
+}
+
+// Redis client shared by all rate limited routes of the process
+var rateLimitRedisClient = sync.OnceValue(func() *redis.Client {
+	redisUrl := viper.GetString("REDIS_URL")
+
+	// redis://host:port or a plain host:port address
+	options, err := redis.ParseURL(redisUrl)
+	if err != nil {
+		options = &redis.Options{Addr: redisUrl}
+	}
+
+	return redis.NewClient(options)
+})
+
+// Allow at most limit requests per client IP and route in each window, the rest get 429.
+// Counts are kept in Redis so every gateway instance shares them, without REDIS_URL they are per process.
+// The client IP is only read from forwarding headers of the proxies trusted by the router
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	var store rateLimitStore
+	if viper.GetString("REDIS_URL") != "" {
+		store = &redisRateLimitStore{client: rateLimitRedisClient()}
+	} else {
+		zap.S().Warnln("REDIS_URL is not set, rate limits are counted per gateway instance")
+		store = newMemoryRateLimitStore()
+	}
+
+	return rateLimit(store, limit, window)
+}
+
+func rateLimit(store rateLimitStore, limit int, window time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		clientIp := ctx.ClientIP()
+
+		requests, retryAfter, err := store.increment(ctx, "rate-limit:"+ctx.FullPath()+":"+clientIp, window)
+		if err != nil {
+			// The route stays available when the counter store is down
+			zap.S().Errorln("Failed to count rate limited request: ", err)
+			ctx.Next()
+			return
+		}
+
+		if requests > limit {
+			zap.S().Infoln("Rate limit exceeded by client: ", clientIp)
+			ctx.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, utils.ErrorApiResponse("Qua nhieu yeu cau, vui long thu lai sau"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+type redisRateLimitStore struct {
+	client *redis.Client
+}
+
+// Fixed window, the key expires when the window that its first request opened ends
+func (rs *redisRateLimitStore) increment(ctx context.Context, key string, window time.Duration) (int, time.Duration, error) {
+
+	pipe := rs.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, window)
+	ttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, err
+	}
+
+	return int(incr.Val()), max(ttl.Val(), 0), nil
+}
+
+type rateLimitWindow struct {
+	start    time.Time
+	requests int
+}
+
+type memoryRateLimitStore struct {
+	mu          sync.Mutex
+	windows     map[string]*rateLimitWindow
+	lastCleanup time.Time
+}
+
+func newMemoryRateLimitStore() *memoryRateLimitStore {
+	return &memoryRateLimitStore{
+		windows:     map[string]*rateLimitWindow{},
+		lastCleanup: time.Now(),
+	}
+}
+
+func (ms *memoryRateLimitStore) increment(_ context.Context, key string, window time.Duration) (int, time.Duration, error) {
+	now := time.Now()
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	// Drop ended windows so the map does not grow with every client ever seen
+	if now.Sub(ms.lastCleanup) > window {
+		for k, w := range ms.windows {
+			if now.Sub(w.start) > window {
+				delete(ms.windows, k)
+			}
+		}
+		ms.lastCleanup = now
+	}
+
+	w, ok := ms.windows[key]
+	if !ok || now.Sub(w.start) > window {
+		w = &rateLimitWindow{start: now}
+		ms.windows[key] = w
+	}
+	w.requests++
+
+	return w.requests, w.start.Add(window).Sub(now), nil
+}
@@ -0,0 +1,106 @@
+package common_middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Store failing every count, like Redis being down
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) increment(context.Context, string, time.Duration) (int, time.Duration, error) {
+	return 0, 0, errors.New("redis is down")
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type request struct {
+		ip       string
+		wantCode int
+	}
+
+	tests := []struct {
+		name     string
+		store    rateLimitStore
+		requests []request
+	}{
+		{
+			name:  "limit per client",
+			store: newMemoryRateLimitStore(),
+			requests: []request{
+				{"10.0.0.1", http.StatusOK},
+				{"10.0.0.1", http.StatusOK},
+				{"10.0.0.1", http.StatusTooManyRequests},
+				// Another client has its own count
+				{"10.0.0.2", http.StatusOK},
+				{"10.0.0.1", http.StatusTooManyRequests},
+			},
+		},
+		{
+			name:  "route stays available when the store is down",
+			store: failingRateLimitStore{},
+			requests: []request{
+				{"10.0.0.1", http.StatusOK},
+				{"10.0.0.1", http.StatusOK},
+				{"10.0.0.1", http.StatusOK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/bookings/lookup", rateLimit(tt.store, 2, time.Minute), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			for i, r := range tt.requests {
+				req := httptest.NewRequest(http.MethodPost, "/bookings/lookup", nil)
+				req.RemoteAddr = r.ip + ":40000"
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, req)
+
+				if recorder.Code != r.wantCode {
+					t.Errorf("request %d from %s status = %d, want %d", i, r.ip, recorder.Code, r.wantCode)
+				}
+				if r.wantCode == http.StatusTooManyRequests && recorder.Header().Get("Retry-After") == "" {
+					t.Errorf("request %d from %s has no Retry-After", i, r.ip)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitIgnoresUntrustedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	if err := router.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	router.POST("/bookings/lookup", rateLimit(newMemoryRateLimitStore(), 1, time.Minute), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	// A client rotating X-Forwarded-For is still counted by its own address
+	codes := []int{}
+	for _, forwardedFor := range []string{"1.1.1.1", "2.2.2.2"} {
+		req := httptest.NewRequest(http.MethodPost, "/bookings/lookup", nil)
+		req.RemoteAddr = "10.0.0.1:40000"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		codes = append(codes, recorder.Code)
+	}
+
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("rate limit status = %v, want [%d %d]", codes, http.StatusOK, http.StatusTooManyRequests)
+	}
+}
@@ -3,3 +3,5 @@ package model
 const ADMIN_ROLE = "ADMIN"
 const STAFF_ROLE = "STAFF"
 const GUEST_ROLE = "GUEST"
+const MANAGER_ROLE = "MANAGER"
+const CHAIN_MANAGER_ROLE = "CHAIN_MANAGER"
@@ -1,13 +1,25 @@
 package utils
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 )
 
+// gRPC metadata key of who made the request, used for audit history
+const ACTOR_METADATA_KEY = "x-actor"
+
+// gRPC metadata key of the JWT of the signed in user, for calls made outside of the gateway
+const AUTHORIZATION_METADATA_KEY = "authorization"
+
+// gRPC metadata key of the manage booking token of a guest without an account
+const MANAGE_BOOKING_TOKEN_METADATA_KEY = "x-manage-token"
+
 func NewGrpcClient(addr string) *grpc.ClientConn {
 
 	conn, err := grpc.NewClient(fmt.Sprintf("localhost:%s", addr), grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -17,3 +29,75 @@ func NewGrpcClient(addr string) *grpc.ClientConn {
 
 	return conn
 }
+
+// Send actor to the called service
+func WithActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, ACTOR_METADATA_KEY, actor)
+}
+
+// Actor sent by the caller, empty when not set
+func ActorFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(ACTOR_METADATA_KEY)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// Send the JWT of the signed in user to the called service
+func WithUserToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, AUTHORIZATION_METADATA_KEY, "Bearer "+token)
+}
+
+// JWT sent by the caller, empty when not set
+func UserTokenFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(AUTHORIZATION_METADATA_KEY)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return strings.TrimPrefix(values[0], "Bearer ")
+}
+
+// Send the manage booking token of a guest to the called service
+func WithManageBookingToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, MANAGE_BOOKING_TOKEN_METADATA_KEY, token)
+}
+
+// Manage booking token sent by the caller, empty when not set
+func ManageBookingTokenFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(MANAGE_BOOKING_TOKEN_METADATA_KEY)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
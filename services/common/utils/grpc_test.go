@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata sent by the caller as the called service receives it
+func receivedContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestActorMetadata(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor string
+		want  string
+	}{
+		{"user", "user:6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b", "user:6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b"},
+		{"guest with a manage booking token", "guest:6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b", "guest:6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b"},
+		{"no actor", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := receivedContext(WithActor(context.Background(), tt.actor))
+
+			if got := ActorFromContext(ctx); got != tt.want {
+				t.Errorf("ActorFromContext() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := ActorFromContext(context.Background()); got != "" {
+		t.Errorf("ActorFromContext() without metadata = %q, want empty", got)
+	}
+}
+
+func TestManageBookingTokenMetadata(t *testing.T) {
+	ctx := receivedContext(WithManageBookingToken(context.Background(), "manage-token"))
+	if got := ManageBookingTokenFromContext(ctx); got != "manage-token" {
+		t.Errorf("ManageBookingTokenFromContext() = %q, want %q", got, "manage-token")
+	}
+
+	// Guests without a token send nothing, not an empty value
+	ctx = receivedContext(WithManageBookingToken(context.Background(), ""))
+	if got := ManageBookingTokenFromContext(ctx); got != "" {
+		t.Errorf("ManageBookingTokenFromContext() without token = %q, want empty", got)
+	}
+}
@@ -0,0 +1,302 @@
+package utils
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/pagination_pb"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const DEFAULT_PAGE_SIZE = 20
+const MAX_PAGE_SIZE = 100
+
+// Field a list can be sorted by, Cast is the SQL type of its values in cursors
+type SortColumn struct {
+	Column      string // column or expression
+	Cast        string
+	DefaultDesc bool
+}
+
+// Page asked by the client
+type PageParams struct {
+	Size          int    // DEFAULT_PAGE_SIZE when 0, at most MAX_PAGE_SIZE
+	Cursor        string // NextCursor of the previous page
+	SortBy        string // default sort of the list when empty
+	SortDirection string // asc, desc, default of the sort field when empty
+}
+
+// Keyset page of a list sorted by a field then by id
+type Page struct {
+	Size     int
+	SortBy   string
+	Sort     SortColumn
+	Desc     bool
+	idColumn string
+	after    *pageCursor
+}
+
+// Cursor of the next page ("" on the last page) and rows of every page together
+type PageInfo struct {
+	NextCursor string
+	TotalCount int64
+}
+
+// Pool, connection or transaction running the page queries
+type PageQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type pageCursor struct {
+	sortValue string
+	id        string
+}
+
+// First page with the default sort when page is nil
+func ToPageParams(page *pagination_pb.PageRequest) PageParams {
+	return PageParams{
+		Size:          int(page.GetPageSize()),
+		Cursor:        page.GetCursor(),
+		SortBy:        page.GetSortBy(),
+		SortDirection: page.GetSortDirection(),
+	}
+}
+
+func ToPageResponsePb(info *PageInfo) *pagination_pb.PageResponse {
+	return &pagination_pb.PageResponse{
+		NextCursor: info.NextCursor,
+		TotalCount: info.TotalCount,
+	}
+}
+
+// ErrBadRequest when the sort is unknown or the cursor was not made for this sort
+func NewPage(params PageParams, sortColumns map[string]SortColumn, defaultSortBy string, idColumn string) (*Page, error) {
+
+	sortBy := strings.ToLower(strings.TrimSpace(params.SortBy))
+	if sortBy == "" {
+		sortBy = defaultSortBy
+	}
+
+	sort, ok := sortColumns[sortBy]
+	if !ok {
+		return nil, common_error.ErrBadRequest
+	}
+
+	desc := sort.DefaultDesc
+	switch strings.ToLower(strings.TrimSpace(params.SortDirection)) {
+	case "":
+	case "asc":
+		desc = false
+	case "desc":
+		desc = true
+	default:
+		return nil, common_error.ErrBadRequest
+	}
+
+	if params.Size < 0 {
+		return nil, common_error.ErrBadRequest
+	}
+	size := params.Size
+	if size == 0 {
+		size = DEFAULT_PAGE_SIZE
+	}
+
+	page := &Page{
+		Size:     min(size, MAX_PAGE_SIZE),
+		SortBy:   sortBy,
+		Sort:     sort,
+		Desc:     desc,
+		idColumn: idColumn,
+	}
+
+	if params.Cursor != "" {
+		after, err := page.decodeCursor(params.Cursor)
+		if err != nil {
+			return nil, common_error.ErrBadRequest
+		}
+		page.after = after
+	}
+
+	return page, nil
+}
+
+// Condition to continue after the last row of the previous page, false on the first page.
+// Format has %d placeholders like Conditions.Add
+func (p *Page) After() (string, []any, bool) {
+	if p.after == nil {
+		return "", nil, false
+	}
+
+	comparator := ">"
+	if p.Desc {
+		comparator = "<"
+	}
+
+	format := fmt.Sprintf("(%s, %s) %s ($%%d::%s, $%%d::uuid)", p.Sort.Column, p.idColumn, comparator, p.Sort.Cast)
+	return format, []any{p.after.sortValue, p.after.id}, true
+}
+
+func (p *Page) OrderBy() string {
+	order := "ASC"
+	if p.Desc {
+		order = "DESC"
+	}
+
+	return fmt.Sprintf("%s %s, %s %s", p.Sort.Column, order, p.idColumn, order)
+}
+
+// One more row than the page to know if there is a next page
+func (p *Page) Limit() int {
+	return p.Size + 1
+}
+
+// Count the rows of every page then get the rows of the page.
+// From is the FROM clause with its joins, columns are read by scan and key returns the sort value and the id of a row
+func QueryPage[T any](ctx context.Context, db PageQuerier, page *Page, columns string, from string, conditions *Conditions, scan func(pgx.Rows) (T, error), key func(T) (any, pgtype.UUID)) ([]T, *PageInfo, error) {
+
+	var totalCount int64
+	countStmt := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", from, conditions.String())
+	if err := db.QueryRow(ctx, countStmt, conditions.Args...).Scan(&totalCount); err != nil {
+		return nil, nil, err
+	}
+
+	if format, values, ok := page.After(); ok {
+		conditions.Add(format, values...)
+	}
+
+	stmt := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT %s",
+		columns, from, conditions.String(), page.OrderBy(), conditions.Arg(page.Limit()))
+
+	rows, err := db.Query(ctx, stmt, conditions.Args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	items := []T{}
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	items, nextCursor := PageRows(page, items, key)
+
+	return items, &PageInfo{
+		NextCursor: nextCursor,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// Rows of the page and the cursor of the next page ("" on the last page), rows are fetched with Limit.
+// key returns the sort value and the id of a row
+func PageRows[T any](p *Page, rows []T, key func(T) (any, pgtype.UUID)) ([]T, string) {
+	if len(rows) <= p.Size {
+		return rows, ""
+	}
+
+	rows = rows[:p.Size]
+	sortValue, id := key(rows[len(rows)-1])
+
+	return rows, p.encodeCursor(PageCursorValue(sortValue), id.String())
+}
+
+// Text of a sort value in cursors, read back by the Cast of its sort column
+func PageCursorValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case pgtype.Text:
+		return v.String
+	case pgtype.Date:
+		return v.Time.Format("2006-01-02")
+	case pgtype.Timestamp:
+		return v.Time.Format(time.RFC3339Nano)
+	case pgtype.Timestamptz:
+		return v.Time.Format(time.RFC3339Nano)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Cursor is "<sort by>|<direction>|<id>|<sort value>" in base64, a cursor only continues the sort it was made for
+func (p *Page) encodeCursor(sortValue string, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join([]string{p.SortBy, p.direction(), id, sortValue}, "|")))
+}
+
+func (p *Page) decodeCursor(cursor string) (*pageCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(string(decoded), "|", 4)
+	if len(parts) != 4 || parts[0] != p.SortBy || parts[1] != p.direction() {
+		return nil, fmt.Errorf("cursor is not of this sort")
+	}
+
+	var id pgtype.UUID
+	if err := id.Scan(parts[2]); err != nil {
+		return nil, err
+	}
+
+	return &pageCursor{
+		sortValue: parts[3],
+		id:        parts[2],
+	}, nil
+}
+
+func (p *Page) direction() string {
+	if p.Desc {
+		return "desc"
+	}
+	return "asc"
+}
+
+// WHERE of a dynamic query, values are numbered $1, $2... in the order they are added
+type Conditions struct {
+	conditions []string
+	Args       []any
+}
+
+// Format has a %d placeholder for each value, e.g. "hotel_id = $%d"
+func (c *Conditions) Add(format string, values ...any) {
+	placeholders := make([]any, 0, len(values))
+	for _, value := range values {
+		c.Args = append(c.Args, value)
+		placeholders = append(placeholders, len(c.Args))
+	}
+
+	c.conditions = append(c.conditions, fmt.Sprintf(format, placeholders...))
+}
+
+// Placeholder of a value used outside the conditions, e.g. LIMIT
+func (c *Conditions) Arg(value any) string {
+	c.Args = append(c.Args, value)
+	return fmt.Sprintf("$%d", len(c.Args))
+}
+
+// Conditions joined by AND, TRUE when there is none
+func (c *Conditions) String() string {
+	if len(c.conditions) == 0 {
+		return "TRUE"
+	}
+
+	return strings.Join(c.conditions, " AND ")
+}
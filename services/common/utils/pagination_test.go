@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var testSortColumns = map[string]SortColumn{
+	"name":       {Column: "h.name", Cast: "text"},
+	"created_at": {Column: "h.created_at", Cast: "timestamp", DefaultDesc: true},
+}
+
+type testRow struct {
+	name string
+	id   pgtype.UUID
+}
+
+func testRowKey(row testRow) (any, pgtype.UUID) {
+	return row.name, row.id
+}
+
+func testUUID(t *testing.T, value string) pgtype.UUID {
+	t.Helper()
+
+	var id pgtype.UUID
+	if err := id.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestNewPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   PageParams
+		wantSort string
+		wantDesc bool
+		wantSize int
+		wantErr  bool
+	}{
+		{name: "defaults", params: PageParams{}, wantSort: "name", wantSize: DEFAULT_PAGE_SIZE},
+		{name: "default direction of the sort", params: PageParams{SortBy: " Created_At "}, wantSort: "created_at", wantDesc: true, wantSize: DEFAULT_PAGE_SIZE},
+		{name: "direction is case insensitive", params: PageParams{SortBy: "created_at", SortDirection: "ASC"}, wantSort: "created_at", wantSize: DEFAULT_PAGE_SIZE},
+		{name: "size is capped", params: PageParams{Size: 1000}, wantSort: "name", wantSize: MAX_PAGE_SIZE},
+		{name: "unknown sort", params: PageParams{SortBy: "price"}, wantErr: true},
+		{name: "unknown direction", params: PageParams{SortDirection: "up"}, wantErr: true},
+		{name: "negative size", params: PageParams{Size: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := NewPage(tt.params, testSortColumns, "name", "h.id")
+			if tt.wantErr {
+				if !errors.Is(err, common_error.ErrBadRequest) {
+					t.Fatalf("NewPage() error = %v, want ErrBadRequest", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewPage() error = %v", err)
+			}
+
+			if page.SortBy != tt.wantSort || page.Desc != tt.wantDesc || page.Size != tt.wantSize {
+				t.Errorf("NewPage() = (%q, %v, %d), want (%q, %v, %d)", page.SortBy, page.Desc, page.Size, tt.wantSort, tt.wantDesc, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestPageCursorRoundTrip(t *testing.T) {
+	page, err := NewPage(PageParams{Size: 2}, testSortColumns, "name", "h.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, ok := page.After(); ok {
+		t.Errorf("After() of the first page should be empty")
+	}
+
+	lastId := testUUID(t, "6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b")
+	rows := []testRow{
+		{name: "A", id: testUUID(t, "00000000-0000-0000-0000-000000000001")},
+		{name: "B|C", id: lastId},
+		{name: "D", id: testUUID(t, "00000000-0000-0000-0000-000000000003")},
+	}
+
+	pageRows, cursor := PageRows(page, rows, testRowKey)
+	if len(pageRows) != 2 || cursor == "" {
+		t.Fatalf("PageRows() = %d rows and cursor %q, want 2 rows and a cursor", len(pageRows), cursor)
+	}
+
+	next, err := NewPage(PageParams{Size: 2, Cursor: cursor}, testSortColumns, "name", "h.id")
+	if err != nil {
+		t.Fatalf("NewPage() of the next page error = %v", err)
+	}
+
+	format, values, ok := next.After()
+	if !ok {
+		t.Fatalf("After() of the next page should not be empty")
+	}
+	if format != "(h.name, h.id) > ($%d::text, $%d::uuid)" {
+		t.Errorf("After() = %q", format)
+	}
+	if !slices.Equal(values, []any{"B|C", lastId.String()}) {
+		t.Errorf("After() values = %v, want [B|C %s]", values, lastId.String())
+	}
+
+	// Last page has no cursor
+	if _, cursor := PageRows(page, rows[:2], testRowKey); cursor != "" {
+		t.Errorf("PageRows() of the last page cursor = %q, want empty", cursor)
+	}
+}
+
+func TestNewPageBadCursor(t *testing.T) {
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"missing parts", encode("name|asc|6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b")},
+		{"other sort", encode("created_at|asc|6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b|A")},
+		{"other direction", encode("name|desc|6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b|A")},
+		{"invalid id", encode("name|asc|not-a-uuid|A")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPage(PageParams{Cursor: tt.cursor}, testSortColumns, "name", "h.id")
+			if !errors.Is(err, common_error.ErrBadRequest) {
+				t.Errorf("NewPage() error = %v, want ErrBadRequest", err)
+			}
+		})
+	}
+}
+
+func TestPageSql(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      PageParams
+		wantOrderBy string
+		wantAfter   string
+	}{
+		{
+			name:        "ascending",
+			params:      PageParams{SortBy: "name"},
+			wantOrderBy: "h.name ASC, h.id ASC",
+			wantAfter:   "(h.name, h.id) > ($%d::text, $%d::uuid)",
+		},
+		{
+			name:        "descending",
+			params:      PageParams{SortBy: "created_at"},
+			wantOrderBy: "h.created_at DESC, h.id DESC",
+			wantAfter:   "(h.created_at, h.id) < ($%d::timestamp, $%d::uuid)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := NewPage(tt.params, testSortColumns, "name", "h.id")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := page.OrderBy(); got != tt.wantOrderBy {
+				t.Errorf("OrderBy() = %q, want %q", got, tt.wantOrderBy)
+			}
+			if got := page.Limit(); got != DEFAULT_PAGE_SIZE+1 {
+				t.Errorf("Limit() = %d, want %d", got, DEFAULT_PAGE_SIZE+1)
+			}
+
+			page.after = &pageCursor{sortValue: "A", id: "6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b"}
+			if format, _, _ := page.After(); format != tt.wantAfter {
+				t.Errorf("After() = %q, want %q", format, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestConditions(t *testing.T) {
+	var empty Conditions
+	if got := empty.String(); got != "TRUE" {
+		t.Errorf("String() of no condition = %q, want TRUE", got)
+	}
+
+	var conditions Conditions
+	conditions.Add("hotel_id = $%d", "hotel-1")
+	conditions.Add("check_in BETWEEN $%d AND $%d", "2026-01-01", "2026-01-31")
+	limit := conditions.Arg(21)
+
+	if got := conditions.String(); got != "hotel_id = $1 AND check_in BETWEEN $2 AND $3" {
+		t.Errorf("String() = %q", got)
+	}
+	if limit != "$4" {
+		t.Errorf("Arg() = %q, want $4", limit)
+	}
+	if !slices.Equal(conditions.Args, []any{"hotel-1", "2026-01-01", "2026-01-31", 21}) {
+		t.Errorf("Args = %v", conditions.Args)
+	}
+}
+
+func TestPageCursorValue(t *testing.T) {
+	at := time.Date(2026, 3, 4, 5, 6, 7, 8, time.UTC)
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"string", "Hotel", "Hotel"},
+		{"text", pgtype.Text{String: "Hotel", Valid: true}, "Hotel"},
+		{"date", pgtype.Date{Time: at, Valid: true}, "2026-03-04"},
+		{"timestamp", pgtype.Timestamp{Time: at, Valid: true}, "2026-03-04T05:06:07.000000008Z"},
+		{"float", 1.5, "1.5"},
+		{"int", int32(42), "42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PageCursorValue(tt.value); got != tt.want {
+				t.Errorf("PageCursorValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
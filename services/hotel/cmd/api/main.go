@@ -4,17 +4,26 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/098765432m/grpc-kafka/common/consts"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/image_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/room_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
 	"github.com/098765432m/grpc-kafka/common/utils"
+	amenity_service "github.com/098765432m/grpc-kafka/hotel/internal/application/amenity"
 	hotel_service "github.com/098765432m/grpc-kafka/hotel/internal/application/hotel"
+	hotel_policy_service "github.com/098765432m/grpc-kafka/hotel/internal/application/hotel-policy"
+	inventory_service "github.com/098765432m/grpc-kafka/hotel/internal/application/inventory"
 	room_service "github.com/098765432m/grpc-kafka/hotel/internal/application/room"
 	room_type_service "github.com/098765432m/grpc-kafka/hotel/internal/application/room-type"
+	hotel_infrastructure "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure"
+	amenity_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/amenity"
 	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
+	hotel_policy_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel-policy"
+	inventory_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/inventory"
 	room_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/room"
 	room_type_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/room-type"
 	hotel_handler "github.com/098765432m/grpc-kafka/hotel/internal/interfaces/hotel"
@@ -45,17 +54,35 @@ func main() {
 	imageConn := utils.NewGrpcClient(fmt.Sprintf(":%d", consts.IMAGE_GRPC_PORT))
 	imageClient := image_pb.NewImageServiceClient(imageConn)
 
+	bookingConn := utils.NewGrpcClient(fmt.Sprintf(":%d", consts.BOOKING_GRPC_PORT))
+	bookingClient := booking_pb.NewBookingServiceClient(bookingConn)
+
+	producer, err := hotel_infrastructure.NewKafkaProducer(strings.Split(viper.GetString("KAFKA_BROKERS"), ","))
+	if err != nil {
+		zap.S().Fatalln("Failed to create kafka producer: ", err)
+	}
+	defer producer.Close()
+
+	// No geocoding provider yet, addresses are matched against known city centres
+	geocoder := hotel_infrastructure.NewStaticGeocoder(hotel_infrastructure.VIETNAM_CITY_LOCATIONS)
+
 	hotelRepo := hotel_repo.New(db)
 	roomTypeRepo := room_type_repo.New(db)
 	roomRepo := room_repo.New(db)
+	amenityRepo := amenity_repo.New(db)
+	hotelPolicyRepo := hotel_policy_repo.New(db)
+	inventoryRepo := inventory_repo.New(db)
 
 	// 3. Application
-	hotelService := hotel_service.NewHotelService(hotelRepo, imageClient)
-	roomTypeService := room_type_service.NewRoomTypeService(roomTypeRepo)
-	roomService := room_service.NewRoomService(roomRepo)
+	hotelService := hotel_service.NewHotelService(db, hotelRepo, imageClient, bookingClient, geocoder, producer)
+	roomTypeService := room_type_service.NewRoomTypeService(db, roomTypeRepo)
+	roomService := room_service.NewRoomService(db, roomRepo)
+	amenityService := amenity_service.NewAmenityService(db, amenityRepo)
+	hotelPolicyService := hotel_policy_service.NewHotelPolicyService(db, hotelPolicyRepo)
+	inventoryService := inventory_service.NewInventoryService(db, inventoryRepo)
 
 	// 4. Server
-	hotelHandler := hotel_handler.NewHotelGrpcHandler(hotelService, roomTypeService, roomService)
+	hotelHandler := hotel_handler.NewHotelGrpcHandler(hotelService, roomTypeService, roomService, amenityService, hotelPolicyService, inventoryService)
 	roomTypeHandler := room_type_handler.NewRoomTypeGrpcHandler(roomTypeService)
 	roomHandler := room_handler.NewRoomGrpcHandler(roomService)
 
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/098765432m/grpc-kafka/common/consts"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	"go.uber.org/zap"
+)
+
+// Import room types and rooms of a hotel from a CSV or JSON file:
+//
+//	go run ./hotel/cmd/import-inventory -hotel <hotel id> -token <JWT> -file rooms.csv -dry-run
+//
+// The token is the JWT of an ADMIN or of the MANAGER of the hotel
+func main() {
+	zap.ReplaceGlobals(zap.Must(zap.NewDevelopment()))
+
+	hotelId := flag.String("hotel", "", "id of the hotel")
+	token := flag.String("token", "", "JWT of an ADMIN or of the MANAGER of the hotel")
+	file := flag.String("file", "", "CSV or JSON file of room types and rooms")
+	format := flag.String("format", "", "csv or json, from the file extension when empty")
+	dryRun := flag.Bool("dry-run", false, "validate and report without writing")
+	port := flag.Int("port", consts.HOTEL_GRPC_PORT, "gRPC port of the hotel service")
+	batchSize := flag.Int("batch", 500, "room types and rooms per streamed message")
+	flag.Parse()
+
+	if *hotelId == "" || *token == "" || *file == "" || *batchSize <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		zap.S().Fatalln("Failed to open inventory file: ", err)
+	}
+	defer f.Close()
+
+	var data *inventory
+	switch *format {
+	case "csv":
+		data, err = parseCsv(f)
+	case "json":
+		data, err = parseJson(f)
+	default:
+		zap.S().Fatalln("Unknown inventory format: ", *format)
+	}
+	if err != nil {
+		zap.S().Fatalln("Failed to parse inventory file: ", err)
+	}
+
+	conn := utils.NewGrpcClient(strconv.Itoa(*port))
+	defer conn.Close()
+
+	client := hotel_pb.NewHotelServiceClient(conn)
+
+	stream, err := client.ImportInventory(utils.WithUserToken(context.Background(), *token))
+	if err != nil {
+		zap.S().Fatalln("Failed to open inventory import stream: ", err)
+	}
+
+	// Room types first so a batch never holds a room before its room type
+	for _, batch := range toBatches(data, *batchSize) {
+		batch.HotelId = *hotelId
+		batch.DryRun = *dryRun
+		if err := stream.Send(batch); err != nil {
+			// The server closed the stream, its error is returned by CloseAndRecv
+			break
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		zap.S().Fatalln("Failed to import inventory: ", err)
+	}
+
+	printReport(res)
+
+	if len(res.GetErrors()) > 0 {
+		os.Exit(1)
+	}
+}
+
+func toBatches(data *inventory, batchSize int) []*hotel_pb.ImportInventoryRequest {
+
+	var batches []*hotel_pb.ImportInventoryRequest
+	current := &hotel_pb.ImportInventoryRequest{}
+	size := 0
+
+	flush := func() {
+		batches = append(batches, current)
+		current = &hotel_pb.ImportInventoryRequest{}
+		size = 0
+	}
+
+	for _, roomType := range data.roomTypes {
+		current.RoomTypes = append(current.RoomTypes, roomType)
+		if size++; size == batchSize {
+			flush()
+		}
+	}
+
+	for _, room := range data.rooms {
+		current.Rooms = append(current.Rooms, room)
+		if size++; size == batchSize {
+			flush()
+		}
+	}
+
+	// An empty file is still sent so the server reports it
+	if size > 0 || len(batches) == 0 {
+		flush()
+	}
+
+	return batches
+}
+
+func printReport(res *hotel_pb.ImportInventoryResponse) {
+
+	for _, rowError := range res.GetErrors() {
+		fmt.Printf("%s row %d, %s: %s\n", rowError.GetKind(), rowError.GetRow(), rowError.GetField(), rowError.GetMessage())
+	}
+
+	switch {
+	case len(res.GetErrors()) > 0:
+		fmt.Printf("%d invalid rows, nothing imported\n", len(res.GetErrors()))
+	case res.GetDryRun():
+		fmt.Println("Dry run, nothing imported")
+	}
+
+	fmt.Printf("Room types: %d created, %d updated\n", res.GetRoomTypesCreated(), res.GetRoomTypesUpdated())
+	fmt.Printf("Rooms: %d created, %d updated\n", res.GetRoomsCreated(), res.GetRoomsUpdated())
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+)
+
+const (
+	KIND_ROOM_TYPE = "room_type"
+	KIND_ROOM      = "room"
+)
+
+type inventory struct {
+	roomTypes []*hotel_pb.InventoryRoomType
+	rooms     []*hotel_pb.InventoryRoom
+}
+
+// JSON document, row of an entry is its 1-based index in its list
+type inventoryJson struct {
+	RoomTypes []struct {
+		Name           string `json:"name"`
+		Price          int32  `json:"price"`
+		AreaSqm        int32  `json:"area_sqm"`
+		View           string `json:"view"`
+		SmokingAllowed bool   `json:"smoking_allowed"`
+		MaxOccupancy   int32  `json:"max_occupancy"`
+		Description    string `json:"description"`
+	} `json:"room_types"`
+	Rooms []struct {
+		Name     string `json:"name"`
+		RoomType string `json:"room_type"`
+		Status   string `json:"status"`
+	} `json:"rooms"`
+}
+
+func parseJson(r io.Reader) (*inventory, error) {
+
+	var doc inventoryJson
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	result := &inventory{}
+	for i, rt := range doc.RoomTypes {
+		result.roomTypes = append(result.roomTypes, &hotel_pb.InventoryRoomType{
+			Row:            int32(i + 1),
+			Name:           rt.Name,
+			Price:          rt.Price,
+			AreaSqm:        rt.AreaSqm,
+			View:           rt.View,
+			SmokingAllowed: rt.SmokingAllowed,
+			MaxOccupancy:   rt.MaxOccupancy,
+			Description:    rt.Description,
+		})
+	}
+
+	for i, room := range doc.Rooms {
+		result.rooms = append(result.rooms, &hotel_pb.InventoryRoom{
+			Row:      int32(i + 1),
+			Name:     room.Name,
+			RoomType: room.RoomType,
+			Status:   room.Status,
+		})
+	}
+
+	return result, nil
+}
+
+// CSV with a header line, kind is room_type or room and each kind reads its own columns:
+// kind,name,room_type,price,area_sqm,view,smoking_allowed,max_occupancy,description,status.
+// Row of an entry is its line in the file
+func parseCsv(r io.Reader) (*inventory, error) {
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := columns["kind"]; !ok {
+		return nil, errors.New("CSV header has no kind column")
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("CSV header has no name column")
+	}
+
+	result := &inventory{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		switch strings.ToLower(value("kind")) {
+		case KIND_ROOM_TYPE:
+			price, err := parseInt(value("price"))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid price: %w", line, err)
+			}
+			areaSqm, err := parseInt(value("area_sqm"))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid area_sqm: %w", line, err)
+			}
+			maxOccupancy, err := parseInt(value("max_occupancy"))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid max_occupancy: %w", line, err)
+			}
+			smokingAllowed := false
+			if value("smoking_allowed") != "" {
+				smokingAllowed, err = strconv.ParseBool(value("smoking_allowed"))
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid smoking_allowed: %w", line, err)
+				}
+			}
+
+			result.roomTypes = append(result.roomTypes, &hotel_pb.InventoryRoomType{
+				Row:            int32(line),
+				Name:           value("name"),
+				Price:          price,
+				AreaSqm:        areaSqm,
+				View:           value("view"),
+				SmokingAllowed: smokingAllowed,
+				MaxOccupancy:   maxOccupancy,
+				Description:    value("description"),
+			})
+		case KIND_ROOM:
+			result.rooms = append(result.rooms, &hotel_pb.InventoryRoom{
+				Row:      int32(line),
+				Name:     value("name"),
+				RoomType: value("room_type"),
+				Status:   value("status"),
+			})
+		default:
+			return nil, fmt.Errorf("line %d: kind must be %s or %s", line, KIND_ROOM_TYPE, KIND_ROOM)
+		}
+	}
+
+	return result, nil
+}
+
+// Empty cells are 0
+func parseInt(s string) (int32, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseInt(s, 10, 32)
+	return int32(n), err
+}
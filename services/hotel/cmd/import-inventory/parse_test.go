@@ -0,0 +1,152 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseCsv(t *testing.T) {
+	tests := []struct {
+		name          string
+		csv           string
+		wantRoomTypes []*hotel_pb.InventoryRoomType
+		wantRooms     []*hotel_pb.InventoryRoom
+		wantErr       bool
+	}{
+		{
+			name: "room types and rooms",
+			csv: "kind,name,room_type,price,area_sqm,view,smoking_allowed,max_occupancy,description,status\n" +
+				"room_type,Deluxe,,1200000,32,sea,true,3,\"Sea view, balcony\",\n" +
+				"room,101,Deluxe,,,,,,,MAINTAINED\n" +
+				"ROOM, 102 ,Deluxe,,,,,,,\n",
+			wantRoomTypes: []*hotel_pb.InventoryRoomType{
+				{Row: 2, Name: "Deluxe", Price: 1200000, AreaSqm: 32, View: "sea", SmokingAllowed: true, MaxOccupancy: 3, Description: "Sea view, balcony"},
+			},
+			wantRooms: []*hotel_pb.InventoryRoom{
+				{Row: 3, Name: "101", RoomType: "Deluxe", Status: "MAINTAINED"},
+				{Row: 4, Name: "102", RoomType: "Deluxe"},
+			},
+		},
+		{
+			name: "columns in any order and missing optional columns",
+			csv: "Name,Kind,Price\n" +
+				"Standard,room_type,500000\n",
+			wantRoomTypes: []*hotel_pb.InventoryRoomType{
+				{Row: 2, Name: "Standard", Price: 500000},
+			},
+		},
+		{name: "empty file", csv: "", wantErr: true},
+		{name: "no kind column", csv: "name,price\nDeluxe,100\n", wantErr: true},
+		{name: "no name column", csv: "kind,price\nroom_type,100\n", wantErr: true},
+		{name: "unknown kind", csv: "kind,name\nsuite,Deluxe\n", wantErr: true},
+		{name: "invalid price", csv: "kind,name,price\nroom_type,Deluxe,cheap\n", wantErr: true},
+		{name: "invalid smoking allowed", csv: "kind,name,price,smoking_allowed\nroom_type,Deluxe,100,maybe\n", wantErr: true},
+		{name: "price out of range", csv: "kind,name,price\nroom_type,Deluxe,99999999999\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCsv(strings.NewReader(tt.csv))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCsv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			assertInventory(t, got, tt.wantRoomTypes, tt.wantRooms)
+		})
+	}
+}
+
+func TestParseJson(t *testing.T) {
+	tests := []struct {
+		name          string
+		json          string
+		wantRoomTypes []*hotel_pb.InventoryRoomType
+		wantRooms     []*hotel_pb.InventoryRoom
+		wantErr       bool
+	}{
+		{
+			name: "room types and rooms",
+			json: `{
+				"room_types": [{"name": "Deluxe", "price": 1200000, "area_sqm": 32, "view": "SEA", "max_occupancy": 3}],
+				"rooms": [{"name": "101", "room_type": "Deluxe"}, {"name": "102", "room_type": "Deluxe", "status": "MAINTAINED"}]
+			}`,
+			wantRoomTypes: []*hotel_pb.InventoryRoomType{
+				{Row: 1, Name: "Deluxe", Price: 1200000, AreaSqm: 32, View: "SEA", MaxOccupancy: 3},
+			},
+			wantRooms: []*hotel_pb.InventoryRoom{
+				{Row: 1, Name: "101", RoomType: "Deluxe"},
+				{Row: 2, Name: "102", RoomType: "Deluxe", Status: "MAINTAINED"},
+			},
+		},
+		{name: "empty document", json: `{}`},
+		{name: "unknown field", json: `{"rooms": [{"name": "101", "type": "Deluxe"}]}`, wantErr: true},
+		{name: "wrong type", json: `{"room_types": [{"name": "Deluxe", "price": "cheap"}]}`, wantErr: true},
+		{name: "not JSON", json: `kind,name`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJson(strings.NewReader(tt.json))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJson() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			assertInventory(t, got, tt.wantRoomTypes, tt.wantRooms)
+		})
+	}
+}
+
+func TestToBatches(t *testing.T) {
+	data := &inventory{
+		roomTypes: []*hotel_pb.InventoryRoomType{{Name: "Deluxe"}, {Name: "Standard"}},
+		rooms:     []*hotel_pb.InventoryRoom{{Name: "101"}, {Name: "102"}, {Name: "103"}},
+	}
+
+	batches := toBatches(data, 2)
+	if len(batches) != 3 {
+		t.Fatalf("toBatches() = %d batches, want 3", len(batches))
+	}
+
+	// Room types come before the rooms and every batch is full but the last
+	sizes := [][2]int{{2, 0}, {0, 2}, {0, 1}}
+	for i, batch := range batches {
+		if len(batch.GetRoomTypes()) != sizes[i][0] || len(batch.GetRooms()) != sizes[i][1] {
+			t.Errorf("batch %d has %d room types and %d rooms, want %d and %d", i, len(batch.GetRoomTypes()), len(batch.GetRooms()), sizes[i][0], sizes[i][1])
+		}
+	}
+
+	if empty := toBatches(&inventory{}, 2); len(empty) != 1 {
+		t.Errorf("toBatches() of an empty inventory = %d batches, want 1", len(empty))
+	}
+}
+
+func assertInventory(t *testing.T, got *inventory, wantRoomTypes []*hotel_pb.InventoryRoomType, wantRooms []*hotel_pb.InventoryRoom) {
+	t.Helper()
+
+	if len(got.roomTypes) != len(wantRoomTypes) {
+		t.Fatalf("room types = %v, want %v", got.roomTypes, wantRoomTypes)
+	}
+	for i := range wantRoomTypes {
+		if !proto.Equal(got.roomTypes[i], wantRoomTypes[i]) {
+			t.Errorf("room type %d = %v, want %v", i, got.roomTypes[i], wantRoomTypes[i])
+		}
+	}
+
+	if len(got.rooms) != len(wantRooms) {
+		t.Fatalf("rooms = %v, want %v", got.rooms, wantRooms)
+	}
+	for i := range wantRooms {
+		if !proto.Equal(got.rooms[i], wantRooms[i]) {
+			t.Errorf("room %d = %v, want %v", i, got.rooms[i], wantRooms[i])
+		}
+	}
+}
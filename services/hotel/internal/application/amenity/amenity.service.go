@@ -0,0 +1,418 @@
+package amenity_service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
+	amenity_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/amenity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+var amenityCodePattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+var amenityCategories = []amenity_repo.AmenityCategory{
+	amenity_repo.AmenityCategoryGENERAL,
+	amenity_repo.AmenityCategoryINTERNET,
+	amenity_repo.AmenityCategoryPARKING,
+	amenity_repo.AmenityCategoryWELLNESS,
+	amenity_repo.AmenityCategoryFOODANDDRINK,
+	amenity_repo.AmenityCategoryROOM,
+	amenity_repo.AmenityCategoryACCESSIBILITY,
+}
+
+type AmenityService struct {
+	conn *pgxpool.Pool
+	repo *amenity_repo.Queries
+}
+
+func NewAmenityService(conn *pgxpool.Pool, repo *amenity_repo.Queries) *AmenityService {
+	return &AmenityService{
+		conn: conn,
+		repo: repo,
+	}
+}
+
+// Catalog entry to create or update, Labels replace all labels of the amenity
+type AmenityParams struct {
+	Code     string
+	Category string // GENERAL when empty
+	Labels   map[string]string
+}
+
+var amenitySortColumns = map[string]utils.SortColumn{
+	"code": {Column: "code", Cast: "text"},
+}
+
+// Amenity catalog, of one category when category is not empty. ErrBadRequest when the category or the page is invalid
+func (as *AmenityService) GetAmenities(ctx context.Context, category string, pageParams utils.PageParams) ([]hotel_domain.Amenity, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, amenitySortColumns, "code", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of Amenities: ", pageParams)
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	if category != "" {
+		parsed, err := parseAmenityCategory(category)
+		if err != nil {
+			return nil, nil, err
+		}
+		conditions.Add("category = $%d", parsed)
+	}
+
+	amenities, pageInfo, err := utils.QueryPage(ctx, as.conn, page, "id, code, category", "amenities", conditions,
+		func(rows pgx.Rows) (amenity_repo.Amenity, error) {
+			var a amenity_repo.Amenity
+			err := rows.Scan(&a.ID, &a.Code, &a.Category)
+			return a, err
+		},
+		func(a amenity_repo.Amenity) (any, pgtype.UUID) {
+			return a.Code, a.ID
+		})
+	if err != nil {
+		zap.S().Errorln("Failed to get Amenities: ", err)
+		return nil, nil, err
+	}
+
+	results, err := as.withLabels(ctx, amenities)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return results, pageInfo, nil
+}
+
+func (as *AmenityService) GetAmenityById(ctx context.Context, id pgtype.UUID) (*hotel_domain.Amenity, error) {
+
+	amenity, err := as.repo.GetAmenityById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Amenity not found")
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to get Amenity by id: ", err)
+		return nil, err
+	}
+
+	return as.withLabel(ctx, amenity)
+}
+
+// ErrDuplicateRecord when the code is already in the catalog
+func (as *AmenityService) CreateAmenity(ctx context.Context, params *AmenityParams) (*hotel_domain.Amenity, error) {
+
+	code, category, err := validateAmenity(params)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := as.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin create amenity transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := as.repo.WithTx(tx)
+
+	amenity, err := qtx.CreateAmenity(ctx, amenity_repo.CreateAmenityParams{
+		Code:     code,
+		Category: category,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Info("Duplicated Amenity code: ", code)
+			return nil, common_error.ErrDuplicateRecord
+		}
+
+		zap.S().Errorln("Failed to create Amenity: ", err)
+		return nil, err
+	}
+
+	if err := setAmenityLabels(ctx, qtx, amenity.ID, params.Labels); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit create amenity transaction: ", err)
+		return nil, err
+	}
+
+	return as.withLabel(ctx, amenity)
+}
+
+// Hotels and room types keep the amenity, only the catalog entry changes
+func (as *AmenityService) UpdateAmenityById(ctx context.Context, id pgtype.UUID, params *AmenityParams) (*hotel_domain.Amenity, error) {
+
+	code, category, err := validateAmenity(params)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := as.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin update amenity transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := as.repo.WithTx(tx)
+
+	amenity, err := qtx.UpdateAmenityById(ctx, amenity_repo.UpdateAmenityByIdParams{
+		Code:     code,
+		Category: category,
+		ID:       id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Amenity not found to update")
+			return nil, common_error.ErrNoRows
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Info("Duplicated Amenity code: ", code)
+			return nil, common_error.ErrDuplicateRecord
+		}
+
+		zap.S().Errorln("Failed to update Amenity by id: ", err)
+		return nil, err
+	}
+
+	if err := setAmenityLabels(ctx, qtx, amenity.ID, params.Labels); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit update amenity transaction: ", err)
+		return nil, err
+	}
+
+	return as.withLabel(ctx, amenity)
+}
+
+// The amenity is removed from every hotel and room type offering it
+func (as *AmenityService) DeleteAmenityById(ctx context.Context, id pgtype.UUID) error {
+
+	deleted, err := as.repo.DeleteAmenityById(ctx, id)
+	if err != nil {
+		zap.S().Errorln("Failed to delete Amenity by id: ", err)
+		return err
+	}
+
+	if deleted == 0 {
+		zap.S().Infoln("Amenity not found to delete")
+		return common_error.ErrNoRows
+	}
+
+	return nil
+}
+
+func (as *AmenityService) GetHotelAmenities(ctx context.Context, hotelId pgtype.UUID) ([]hotel_domain.Amenity, error) {
+
+	amenities, err := as.repo.GetAmenitiesByHotelId(ctx, hotelId)
+	if err != nil {
+		zap.S().Errorln("Failed to get Amenities by Hotel id: ", err)
+		return nil, err
+	}
+
+	return as.withLabels(ctx, amenities)
+}
+
+// Amenities of the hotel become exactly the given codes.
+// ErrBadRequest when a code is not in the catalog, ErrNoRows when the hotel does not exist
+func (as *AmenityService) SetHotelAmenities(ctx context.Context, hotelId pgtype.UUID, codes []string) ([]hotel_domain.Amenity, error) {
+
+	amenityIds, err := as.amenityIdsByCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = as.repo.SetHotelAmenities(ctx, amenity_repo.SetHotelAmenitiesParams{
+		HotelID:    hotelId,
+		AmenityIds: amenityIds,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			zap.S().Infoln("Hotel not found to set amenities")
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to set Hotel Amenities: ", err)
+		return nil, err
+	}
+
+	return as.GetHotelAmenities(ctx, hotelId)
+}
+
+func (as *AmenityService) GetRoomTypeAmenities(ctx context.Context, roomTypeId pgtype.UUID) ([]hotel_domain.Amenity, error) {
+
+	amenities, err := as.repo.GetAmenitiesByRoomTypeId(ctx, roomTypeId)
+	if err != nil {
+		zap.S().Errorln("Failed to get Amenities by Room Type id: ", err)
+		return nil, err
+	}
+
+	return as.withLabels(ctx, amenities)
+}
+
+// Amenities of the room type become exactly the given codes.
+// ErrBadRequest when a code is not in the catalog, ErrNoRows when the room type is not one of the hotel
+func (as *AmenityService) SetRoomTypeAmenities(ctx context.Context, hotelId pgtype.UUID, roomTypeId pgtype.UUID, codes []string) ([]hotel_domain.Amenity, error) {
+
+	roomTypeHotelId, err := as.repo.GetHotelIdByRoomTypeId(ctx, roomTypeId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Room Type not found to set amenities")
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to get Hotel id by Room Type id: ", err)
+		return nil, err
+	}
+
+	if roomTypeHotelId != hotelId {
+		zap.S().Infoln("Room Type is not one of the Hotel: ", roomTypeId.String())
+		return nil, common_error.ErrNoRows
+	}
+
+	amenityIds, err := as.amenityIdsByCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = as.repo.SetRoomTypeAmenities(ctx, amenity_repo.SetRoomTypeAmenitiesParams{
+		RoomTypeID: roomTypeId,
+		AmenityIds: amenityIds,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to set Room Type Amenities: ", err)
+		return nil, err
+	}
+
+	return as.GetRoomTypeAmenities(ctx, roomTypeId)
+}
+
+// Ids of the catalog amenities, ErrBadRequest when one of the codes is unknown
+func (as *AmenityService) amenityIdsByCodes(ctx context.Context, codes []string) ([]pgtype.UUID, error) {
+
+	codes = hotel_domain.NormalizeAmenityCodes(codes)
+
+	amenities, err := as.repo.GetAmenitiesByCodes(ctx, codes)
+	if err != nil {
+		zap.S().Errorln("Failed to get Amenities by codes: ", err)
+		return nil, err
+	}
+
+	if len(amenities) != len(codes) {
+		zap.S().Infoln("Unknown amenity codes: ", codes)
+		return nil, common_error.ErrBadRequest
+	}
+
+	amenityIds := make([]pgtype.UUID, 0, len(amenities))
+	for _, amenity := range amenities {
+		amenityIds = append(amenityIds, amenity.ID)
+	}
+
+	return amenityIds, nil
+}
+
+func (as *AmenityService) withLabels(ctx context.Context, amenities []amenity_repo.Amenity) ([]hotel_domain.Amenity, error) {
+
+	amenityIds := make([]pgtype.UUID, 0, len(amenities))
+	for _, amenity := range amenities {
+		amenityIds = append(amenityIds, amenity.ID)
+	}
+
+	labels, err := as.repo.GetAmenityLabelsByAmenityIds(ctx, amenityIds)
+	if err != nil {
+		zap.S().Errorln("Failed to get Amenity Labels: ", err)
+		return nil, err
+	}
+
+	return hotel_repo_mapping.FromAmenitiesRepoToAmenitiesDomain(amenities, labels), nil
+}
+
+func (as *AmenityService) withLabel(ctx context.Context, amenity amenity_repo.Amenity) (*hotel_domain.Amenity, error) {
+
+	amenities, err := as.withLabels(ctx, []amenity_repo.Amenity{amenity})
+	if err != nil {
+		return nil, err
+	}
+
+	return &amenities[0], nil
+}
+
+func setAmenityLabels(ctx context.Context, qtx *amenity_repo.Queries, amenityId pgtype.UUID, labels map[string]string) error {
+
+	locales := make([]string, 0, len(labels))
+	texts := make([]string, 0, len(labels))
+	for locale, label := range labels {
+		locales = append(locales, locale)
+		texts = append(texts, label)
+	}
+
+	if err := qtx.SetAmenityLabels(ctx, amenity_repo.SetAmenityLabelsParams{
+		AmenityID: amenityId,
+		Locales:   locales,
+		Labels:    texts,
+	}); err != nil {
+		zap.S().Errorln("Failed to set Amenity Labels: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// Normalized code and category of the amenity, labels need a locale of at most 10 characters and a text
+func validateAmenity(params *AmenityParams) (string, amenity_repo.AmenityCategory, error) {
+
+	code := strings.ToLower(strings.TrimSpace(params.Code))
+	if !amenityCodePattern.MatchString(code) {
+		zap.S().Infoln("Invalid Amenity code: ", params.Code)
+		return "", "", common_error.ErrBadRequest
+	}
+
+	category := amenity_repo.AmenityCategoryGENERAL
+	if params.Category != "" {
+		parsed, err := parseAmenityCategory(params.Category)
+		if err != nil {
+			return "", "", err
+		}
+		category = parsed
+	}
+
+	for locale, label := range params.Labels {
+		if locale == "" || len(locale) > 10 || strings.TrimSpace(label) == "" || len([]rune(label)) > 255 {
+			zap.S().Infoln("Invalid Amenity label: ", locale)
+			return "", "", common_error.ErrBadRequest
+		}
+	}
+
+	return code, category, nil
+}
+
+func parseAmenityCategory(category string) (amenity_repo.AmenityCategory, error) {
+
+	parsed := amenity_repo.AmenityCategory(strings.ToUpper(strings.TrimSpace(category)))
+	if !slices.Contains(amenityCategories, parsed) {
+		zap.S().Infoln("Invalid Amenity category: ", category)
+		return "", common_error.ErrBadRequest
+	}
+
+	return parsed, nil
+}
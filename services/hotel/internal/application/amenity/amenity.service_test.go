@@ -0,0 +1,50 @@
+package amenity_service
+
+import (
+	"strings"
+	"testing"
+
+	amenity_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/amenity"
+)
+
+func TestValidateAmenity(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       AmenityParams
+		wantCode     string
+		wantCategory amenity_repo.AmenityCategory
+		wantErr      bool
+	}{
+		{
+			name:         "code is normalized and category defaults to general",
+			params:       AmenityParams{Code: " WiFi_Free "},
+			wantCode:     "wifi_free",
+			wantCategory: amenity_repo.AmenityCategoryGENERAL,
+		},
+		{
+			name:         "category is case insensitive",
+			params:       AmenityParams{Code: "pool", Category: "wellness", Labels: map[string]string{"vi": "Ho boi", "en": "Pool"}},
+			wantCode:     "pool",
+			wantCategory: amenity_repo.AmenityCategoryWELLNESS,
+		},
+		{name: "empty code", params: AmenityParams{Code: " "}, wantErr: true},
+		{name: "code with spaces", params: AmenityParams{Code: "free wifi"}, wantErr: true},
+		{name: "code too long", params: AmenityParams{Code: strings.Repeat("a", 51)}, wantErr: true},
+		{name: "unknown category", params: AmenityParams{Code: "spa", Category: "BEAUTY"}, wantErr: true},
+		{name: "empty locale", params: AmenityParams{Code: "spa", Labels: map[string]string{"": "Spa"}}, wantErr: true},
+		{name: "locale too long", params: AmenityParams{Code: "spa", Labels: map[string]string{"vi-VN-extra": "Spa"}}, wantErr: true},
+		{name: "blank label", params: AmenityParams{Code: "spa", Labels: map[string]string{"vi": "  "}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, category, err := validateAmenity(&tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateAmenity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if code != tt.wantCode || category != tt.wantCategory {
+				t.Errorf("validateAmenity() = (%q, %q), want (%q, %q)", code, category, tt.wantCode, tt.wantCategory)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package hotel_policy_service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
+	hotel_policy_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel-policy"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const MAX_HOUSE_RULES_LENGTH = 5000
+
+var paymentMethods = []string{"CASH", "CREDIT_CARD", "DEBIT_CARD", "BANK_TRANSFER", "E_WALLET"}
+
+type HotelPolicyService struct {
+	conn *pgxpool.Pool
+	repo *hotel_policy_repo.Queries
+}
+
+func NewHotelPolicyService(conn *pgxpool.Pool, repo *hotel_policy_repo.Queries) *HotelPolicyService {
+	return &HotelPolicyService{
+		conn: conn,
+		repo: repo,
+	}
+}
+
+// Whole policy document of a hotel, HouseRules replace all house rules of the hotel.
+// Clock times are HH:MM
+type HotelPolicyParams struct {
+	CheckInFrom        string
+	CheckInUntil       string // empty when guests can arrive at any time after CheckInFrom
+	CheckOutUntil      string
+	PetsAllowed        bool
+	PetFee             int
+	ChildrenAllowed    bool
+	ChildrenFreeMaxAge *int
+	ExtraBedAvailable  bool
+	ExtraBedFee        int
+	PaymentMethods     []string
+	HouseRules         map[string]string // locale -> house rules
+}
+
+// ErrNoRows when the hotel has no policy yet
+func (hps *HotelPolicyService) GetHotelPolicy(ctx context.Context, hotelId pgtype.UUID) (*hotel_domain.HotelPolicy, error) {
+
+	policy, err := hps.repo.GetHotelPolicyByHotelId(ctx, hotelId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Hotel Policy not found")
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to get Hotel Policy: ", err)
+		return nil, err
+	}
+
+	texts, err := hps.repo.GetHotelPolicyTextsByHotelId(ctx, hotelId)
+	if err != nil {
+		zap.S().Errorln("Failed to get Hotel Policy texts: ", err)
+		return nil, err
+	}
+
+	result := hotel_repo_mapping.FromHotelPolicyRepoToHotelPolicyDomain(policy, texts)
+	return &result, nil
+}
+
+// Create or replace the policy of the hotel, ErrNoRows when the hotel does not exist
+func (hps *HotelPolicyService) SetHotelPolicy(ctx context.Context, hotelId pgtype.UUID, params *HotelPolicyParams) (*hotel_domain.HotelPolicy, error) {
+
+	policyParams, err := validateHotelPolicy(hotelId, params)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := hps.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin set hotel policy transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := hps.repo.WithTx(tx)
+
+	if _, err := qtx.UpsertHotelPolicy(ctx, *policyParams); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			zap.S().Infoln("Hotel not found for policy")
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to upsert Hotel Policy: ", err)
+		return nil, err
+	}
+
+	locales := make([]string, 0, len(params.HouseRules))
+	houseRules := make([]string, 0, len(params.HouseRules))
+	for locale, rules := range params.HouseRules {
+		locales = append(locales, locale)
+		houseRules = append(houseRules, strings.TrimSpace(rules))
+	}
+
+	if err := qtx.SetHotelPolicyTexts(ctx, hotel_policy_repo.SetHotelPolicyTextsParams{
+		HotelID:    hotelId,
+		Locales:    locales,
+		HouseRules: houseRules,
+	}); err != nil {
+		zap.S().Errorln("Failed to set Hotel Policy texts: ", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit set hotel policy transaction: ", err)
+		return nil, err
+	}
+
+	return hps.GetHotelPolicy(ctx, hotelId)
+}
+
+func validateHotelPolicy(hotelId pgtype.UUID, params *HotelPolicyParams) (*hotel_policy_repo.UpsertHotelPolicyParams, error) {
+
+	checkInFrom, err := parseClock(params.CheckInFrom)
+	if err != nil || !checkInFrom.Valid {
+		zap.S().Infoln("Invalid check in time: ", params.CheckInFrom)
+		return nil, common_error.ErrBadRequest
+	}
+
+	// Latest check in may be past midnight, so it is not compared with the earliest one
+	checkInUntil, err := parseClock(params.CheckInUntil)
+	if err != nil {
+		zap.S().Infoln("Invalid latest check in time: ", params.CheckInUntil)
+		return nil, common_error.ErrBadRequest
+	}
+
+	checkOutUntil, err := parseClock(params.CheckOutUntil)
+	if err != nil || !checkOutUntil.Valid {
+		zap.S().Infoln("Invalid check out time: ", params.CheckOutUntil)
+		return nil, common_error.ErrBadRequest
+	}
+
+	if params.PetFee < 0 || params.ExtraBedFee < 0 || (params.ChildrenFreeMaxAge != nil && *params.ChildrenFreeMaxAge < 0) {
+		zap.S().Infoln("Invalid Hotel Policy fee or age")
+		return nil, common_error.ErrBadRequest
+	}
+
+	methods := make([]string, 0, len(params.PaymentMethods))
+	for _, method := range params.PaymentMethods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if !slices.Contains(paymentMethods, method) {
+			zap.S().Infoln("Invalid payment method: ", method)
+			return nil, common_error.ErrBadRequest
+		}
+		if !slices.Contains(methods, method) {
+			methods = append(methods, method)
+		}
+	}
+
+	for locale, rules := range params.HouseRules {
+		if locale == "" || len(locale) > 10 || strings.TrimSpace(rules) == "" || len([]rune(rules)) > MAX_HOUSE_RULES_LENGTH {
+			zap.S().Infoln("Invalid house rules: ", locale)
+			return nil, common_error.ErrBadRequest
+		}
+	}
+
+	var childrenFreeMaxAge pgtype.Int4
+	if params.ChildrenFreeMaxAge != nil {
+		childrenFreeMaxAge = pgtype.Int4{Int32: int32(*params.ChildrenFreeMaxAge), Valid: true}
+	}
+
+	return &hotel_policy_repo.UpsertHotelPolicyParams{
+		HotelID:            hotelId,
+		CheckInFrom:        checkInFrom,
+		CheckInUntil:       checkInUntil,
+		CheckOutUntil:      checkOutUntil,
+		PetsAllowed:        params.PetsAllowed,
+		PetFee:             int32(params.PetFee),
+		ChildrenAllowed:    params.ChildrenAllowed,
+		ChildrenFreeMaxAge: childrenFreeMaxAge,
+		ExtraBedAvailable:  params.ExtraBedAvailable,
+		ExtraBedFee:        int32(params.ExtraBedFee),
+		PaymentMethods:     methods,
+	}, nil
+}
+
+// HH:MM to a time of day, NULL when clock is empty
+func parseClock(clock string) (pgtype.Time, error) {
+	if clock == "" {
+		return pgtype.Time{}, nil
+	}
+
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return pgtype.Time{}, err
+	}
+
+	return pgtype.Time{
+		Microseconds: time.Duration(parsed.Hour()*int(time.Hour) + parsed.Minute()*int(time.Minute)).Microseconds(),
+		Valid:        true,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package hotel_policy_service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		clock   string
+		want    pgtype.Time
+		wantErr bool
+	}{
+		{"14:00", pgtype.Time{Microseconds: 14 * 3600 * 1_000_000, Valid: true}, false},
+		{"00:30", pgtype.Time{Microseconds: 30 * 60 * 1_000_000, Valid: true}, false},
+		{"", pgtype.Time{}, false},
+		{"24:00", pgtype.Time{}, true},
+		{"2pm", pgtype.Time{}, true},
+		{"14:00:00", pgtype.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.clock, func(t *testing.T) {
+			got, err := parseClock(tt.clock)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseClock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseClock() = %+v, want %+v", got, tt.want)
+			}
+
+			// Stored times are shown as they were entered
+			if !tt.wantErr {
+				if clock := hotel_repo_mapping.FromPgTimeToClock(got); clock != tt.clock {
+					t.Errorf("FromPgTimeToClock() = %q, want %q", clock, tt.clock)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateHotelPolicy(t *testing.T) {
+	hotelId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	age := func(years int) *int { return &years }
+	policy := func(change func(*HotelPolicyParams)) HotelPolicyParams {
+		params := HotelPolicyParams{CheckInFrom: "14:00", CheckOutUntil: "12:00"}
+		change(&params)
+		return params
+	}
+
+	tests := []struct {
+		name        string
+		params      HotelPolicyParams
+		wantErr     bool
+		wantMethods []string
+	}{
+		{"check in and check out times only", policy(func(p *HotelPolicyParams) {}), false, []string{}},
+		// Latest check in may be after midnight
+		{"late check in", policy(func(p *HotelPolicyParams) { p.CheckInUntil = "02:00" }), false, []string{}},
+		{"payment methods are normalized once", policy(func(p *HotelPolicyParams) { p.PaymentMethods = []string{" cash", "CASH", "e_wallet"} }), false, []string{"CASH", "E_WALLET"}},
+		{"house rules per locale", policy(func(p *HotelPolicyParams) {
+			p.HouseRules = map[string]string{"vi": "Khong hut thuoc", "en": "No smoking"}
+		}), false, []string{}},
+		{"children free until an age", policy(func(p *HotelPolicyParams) { p.ChildrenAllowed, p.ChildrenFreeMaxAge = true, age(6) }), false, []string{}},
+		{"missing check in", policy(func(p *HotelPolicyParams) { p.CheckInFrom = "" }), true, nil},
+		{"missing check out", policy(func(p *HotelPolicyParams) { p.CheckOutUntil = "" }), true, nil},
+		{"invalid latest check in", policy(func(p *HotelPolicyParams) { p.CheckInUntil = "25:00" }), true, nil},
+		{"negative pet fee", policy(func(p *HotelPolicyParams) { p.PetsAllowed, p.PetFee = true, -1 }), true, nil},
+		{"negative extra bed fee", policy(func(p *HotelPolicyParams) { p.ExtraBedFee = -100 }), true, nil},
+		{"negative children age", policy(func(p *HotelPolicyParams) { p.ChildrenFreeMaxAge = age(-1) }), true, nil},
+		{"unknown payment method", policy(func(p *HotelPolicyParams) { p.PaymentMethods = []string{"CHEQUE"} }), true, nil},
+		{"house rules without locale", policy(func(p *HotelPolicyParams) { p.HouseRules = map[string]string{"": "No smoking"} }), true, nil},
+		{"blank house rules", policy(func(p *HotelPolicyParams) { p.HouseRules = map[string]string{"en": "  "} }), true, nil},
+		{"house rules too long", policy(func(p *HotelPolicyParams) {
+			p.HouseRules = map[string]string{"en": strings.Repeat("a", MAX_HOUSE_RULES_LENGTH+1)}
+		}), true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateHotelPolicy(hotelId, &tt.params)
+			if tt.wantErr {
+				if !errors.Is(err, common_error.ErrBadRequest) {
+					t.Errorf("validateHotelPolicy() error = %v, want %v", err, common_error.ErrBadRequest)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("validateHotelPolicy() error = %v", err)
+			}
+			if got.HotelID != hotelId {
+				t.Errorf("validateHotelPolicy() hotel = %v, want %v", got.HotelID, hotelId)
+			}
+			if !slices.Equal(got.PaymentMethods, tt.wantMethods) {
+				t.Errorf("validateHotelPolicy() payment methods = %v, want %v", got.PaymentMethods, tt.wantMethods)
+			}
+			if got.ChildrenFreeMaxAge.Valid != (tt.params.ChildrenFreeMaxAge != nil) {
+				t.Errorf("validateHotelPolicy() children free max age = %+v, want set %v", got.ChildrenFreeMaxAge, tt.params.ChildrenFreeMaxAge != nil)
+			}
+		})
+	}
+}
+
+func TestSetHotelPolicyInvalid(t *testing.T) {
+	hps := &HotelPolicyService{}
+
+	// Nothing is written for an invalid policy
+	_, err := hps.SetHotelPolicy(context.Background(), pgtype.UUID{Bytes: [16]byte{1}, Valid: true}, &HotelPolicyParams{CheckInFrom: "14:00"})
+	if !errors.Is(err, common_error.ErrBadRequest) {
+		t.Errorf("SetHotelPolicy() error = %v, want %v", err, common_error.ErrBadRequest)
+	}
+}
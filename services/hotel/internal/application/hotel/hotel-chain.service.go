@@ -0,0 +1,225 @@
+package hotel_service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
+	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const hotelChainColumns = `id, name, brand, description, create_at`
+
+var hotelChainSortColumns = map[string]utils.SortColumn{
+	"name": {Column: "name", Cast: "text"},
+}
+
+// Brand and Description are optional
+type HotelChainParams struct {
+	Name        string
+	Brand       string
+	Description string
+}
+
+func (params *HotelChainParams) toPg() (name pgtype.Text, brand pgtype.Text, description pgtype.Text, err error) {
+	name = pgtype.Text{String: strings.TrimSpace(params.Name)}
+	if name.String == "" {
+		zap.S().Infoln("Hotel Chain name is required")
+		return name, brand, description, common_error.ErrBadRequest
+	}
+	name.Valid = true
+
+	brand = pgtype.Text{String: strings.TrimSpace(params.Brand)}
+	brand.Valid = brand.String != ""
+
+	description = pgtype.Text{String: strings.TrimSpace(params.Description)}
+	description.Valid = description.String != ""
+
+	return name, brand, description, nil
+}
+
+func (hs *HotelService) GetHotelChains(ctx context.Context, pageParams utils.PageParams) ([]hotel_domain.HotelChain, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, hotelChainSortColumns, "name", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of Hotel Chains: ", pageParams)
+		return nil, nil, err
+	}
+
+	chains, pageInfo, err := utils.QueryPage(ctx, hs.conn, page, hotelChainColumns, "hotel_chains", &utils.Conditions{}, scanHotelChain,
+		func(c hotel_repo.HotelChain) (any, pgtype.UUID) { return c.Name, c.ID })
+	if err != nil {
+		zap.S().Errorln("Failed to get Hotel Chains: ", err)
+		return nil, nil, err
+	}
+
+	results := make([]hotel_domain.HotelChain, 0, len(chains))
+	for _, chain := range chains {
+		results = append(results, hotel_repo_mapping.FromHotelChainRepoToHotelChainDomain(chain))
+	}
+
+	return results, pageInfo, nil
+}
+
+func (hs *HotelService) GetHotelChainById(ctx context.Context, id pgtype.UUID) (*hotel_domain.HotelChain, error) {
+
+	chain, err := hs.repo.GetHotelChainById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Hotel Chain not found")
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to get Hotel Chain by id: ", err)
+		return nil, err
+	}
+
+	result := hotel_repo_mapping.FromHotelChainRepoToHotelChainDomain(chain)
+
+	return &result, nil
+}
+
+// ErrBadRequest without a name, ErrDuplicateRecord when the name is taken
+func (hs *HotelService) CreateHotelChain(ctx context.Context, params *HotelChainParams) (*hotel_domain.HotelChain, error) {
+
+	name, brand, description, err := params.toPg()
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := hs.repo.CreateHotelChain(ctx, hotel_repo.CreateHotelChainParams{
+		Name:        name,
+		Brand:       brand,
+		Description: description,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Info("Duplicated Hotel Chain: ", err)
+			return nil, common_error.ErrDuplicateRecord
+		}
+
+		zap.S().Errorln("Failed to create Hotel Chain: ", err)
+		return nil, err
+	}
+
+	result := hotel_repo_mapping.FromHotelChainRepoToHotelChainDomain(chain)
+
+	return &result, nil
+}
+
+func (hs *HotelService) UpdateHotelChainById(ctx context.Context, id pgtype.UUID, params *HotelChainParams) (*hotel_domain.HotelChain, error) {
+
+	name, brand, description, err := params.toPg()
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := hs.repo.UpdateHotelChainById(ctx, hotel_repo.UpdateHotelChainByIdParams{
+		Name:        name,
+		Brand:       brand,
+		Description: description,
+		ID:          id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Hotel Chain not found to update")
+			return nil, common_error.ErrNoRows
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Info("Duplicated Hotel Chain: ", err)
+			return nil, common_error.ErrDuplicateRecord
+		}
+
+		zap.S().Errorln("Failed to update Hotel Chain by id: ", err)
+		return nil, err
+	}
+
+	result := hotel_repo_mapping.FromHotelChainRepoToHotelChainDomain(chain)
+
+	return &result, nil
+}
+
+// Hotels of the deleted chain become independent
+func (hs *HotelService) DeleteHotelChainById(ctx context.Context, id pgtype.UUID) error {
+
+	rows, err := hs.repo.DeleteHotelChainById(ctx, id)
+	if err != nil {
+		zap.S().Errorln("Failed to delete Hotel Chain by id: ", err)
+		return err
+	}
+
+	if rows == 0 {
+		zap.S().Infoln("No Hotel Chain to delete")
+		return common_error.ErrNoRows
+	}
+
+	return nil
+}
+
+// Move the hotel to the chain, an invalid chainId makes it independent.
+// ErrNoRows when the hotel or the chain does not exist
+func (hs *HotelService) SetHotelChain(ctx context.Context, hotelId pgtype.UUID, chainId pgtype.UUID) (*hotel_domain.Hotel, error) {
+
+	hotel, err := hs.repo.SetHotelChain(ctx, hotel_repo.SetHotelChainParams{
+		ChainID: chainId,
+		ID:      hotelId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Hotel not found to set chain")
+			return nil, common_error.ErrNoRows
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			zap.S().Infoln("Hotel Chain not found for hotel")
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to set Hotel Chain: ", err)
+		return nil, err
+	}
+
+	result := hotel_repo_mapping.FromHotelRepoToHotelDomain(hotel)
+
+	return &result, nil
+}
+
+// Every hotel of the chain whatever its publication status, ErrNoRows when the chain does not exist
+func (hs *HotelService) GetHotelsByChainId(ctx context.Context, chainId pgtype.UUID) ([]hotel_domain.Hotel, error) {
+
+	if _, err := hs.GetHotelChainById(ctx, chainId); err != nil {
+		return nil, err
+	}
+
+	hotels, err := hs.repo.GetHotelsByChainId(ctx, chainId)
+	if err != nil {
+		zap.S().Errorln("Failed to get Hotels by chain id: ", err)
+		return nil, err
+	}
+
+	return hotel_repo_mapping.FromHotelsRepoToHotelsDomain(hotels), nil
+}
+
+func scanHotelChain(rows pgx.Rows) (hotel_repo.HotelChain, error) {
+	var c hotel_repo.HotelChain
+	err := rows.Scan(
+		&c.ID,
+		&c.Name,
+		&c.Brand,
+		&c.Description,
+		&c.CreateAt,
+	)
+	return c, err
+}
@@ -0,0 +1,31 @@
+package hotel_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+)
+
+func TestGetHotelChainsInvalidPage(t *testing.T) {
+	tests := []struct {
+		name       string
+		pageParams utils.PageParams
+	}{
+		{"unknown sort", utils.PageParams{SortBy: "brand"}},
+		{"unknown sort direction", utils.PageParams{SortDirection: "up"}},
+		{"invalid cursor", utils.PageParams{Cursor: "not-a-cursor"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &HotelService{}
+
+			if _, _, err := hs.GetHotelChains(context.Background(), tt.pageParams); !errors.Is(err, common_error.ErrBadRequest) {
+				t.Errorf("GetHotelChains() error = %v, want %v", err, common_error.ErrBadRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package hotel_service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/098765432m/grpc-kafka/common/consts"
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var roomViews = []hotel_repo.RoomView{
+	hotel_repo.RoomViewNONE,
+	hotel_repo.RoomViewCITY,
+	hotel_repo.RoomViewSEA,
+	hotel_repo.RoomViewGARDEN,
+	hotel_repo.RoomViewMOUNTAIN,
+	hotel_repo.RoomViewPOOL,
+	hotel_repo.RoomViewRIVER,
+}
+
+// Attributes a room type of the hotel must have, zero values do not filter
+type RoomTypeFilter struct {
+	MinAreaSqm     int
+	View           string
+	SmokingAllowed *bool
+	Guests         int // room type must sleep at least this many guests
+}
+
+func (f RoomTypeFilter) viewParam() (pgtype.Text, error) {
+	if f.View == "" {
+		return pgtype.Text{}, nil
+	}
+
+	view := hotel_repo.RoomView(strings.ToUpper(strings.TrimSpace(f.View)))
+	if !slices.Contains(roomViews, view) {
+		zap.S().Infoln("Invalid Room Type view: ", f.View)
+		return pgtype.Text{}, common_error.ErrBadRequest
+	}
+
+	return pgtype.Text{String: string(view), Valid: true}, nil
+}
+
+var filteredHotelsSortColumns = map[string]utils.SortColumn{
+	"distance": {Column: "distance_km", Cast: "float8"},
+	"price":    {Column: "min_price", Cast: "int"},
+	"name":     {Column: "name", Cast: "text"},
+}
+
+type FilterHotelsParams struct {
+	HotelName      string // words of the name
+	Address        string // words of the address or city
+	CheckIn        pgtype.Date
+	CheckOut       pgtype.Date
+	MinPrice       int // 0 or less does not filter
+	MaxPrice       int
+	GeoSearch      GeoSearchParams
+	AmenityCodes   []string // the hotel or the room type must offer every one of them
+	RoomTypeFilter RoomTypeFilter
+	Page           utils.PageParams // sort by distance (default with a geo search), price, name (default otherwise)
+}
+
+// Published hotels with a room type matching every filter that still has a free room from check in to check out.
+// Room types are filtered first, their free rooms are counted with one call to the booking service,
+// then the hotels of the free room types are paged so the total count is the number of matching hotels.
+// ErrBadRequest when a filter or the page is invalid
+func (hs *HotelService) FilterHotels(ctx context.Context, params *FilterHotelsParams) ([]hotel_domain.FilteredHotel, *utils.PageInfo, error) {
+
+	if !params.CheckIn.Valid || !params.CheckOut.Valid || !params.CheckIn.Time.Before(params.CheckOut.Time) {
+		zap.S().Infoln("Check in must be before Check out")
+		return nil, nil, common_error.ErrBadRequest
+	}
+
+	area, err := toGeoSearchArea(params.GeoSearch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defaultSortBy := "name"
+	if area != nil {
+		defaultSortBy = "distance"
+	}
+
+	page, err := utils.NewPage(params.Page, filteredHotelsSortColumns, defaultSortBy, "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of filtered hotels: ", params.Page)
+		return nil, nil, err
+	}
+
+	roomTypeIds, err := hs.getFreeRoomTypeIds(ctx, params, area)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	distanceKm := "0::float8"
+	if area != nil {
+		distanceKm = fmt.Sprintf("(earth_distance(ll_to_earth(h.latitude, h.longitude), %s) / 1000)::float8", area.centerSql(conditions))
+	}
+
+	// Min price and distance are computed once per hotel so pages can continue after them
+	filtered := fmt.Sprintf(`(
+		SELECT h.id, h.name, h.address, MIN(rt.price)::int AS min_price, %s AS distance_km
+		FROM hotels h JOIN room_types rt ON rt.hotel_id = h.id
+		WHERE rt.id = ANY(%s::uuid[])
+		GROUP BY h.id
+	) AS filtered`, distanceKm, conditions.Arg(roomTypeIds))
+
+	hotels, pageInfo, err := utils.QueryPage(ctx, hs.conn, page, "id, name, address, min_price, distance_km", filtered, conditions,
+		func(rows pgx.Rows) (filteredHotelRow, error) {
+			var h filteredHotelRow
+			err := rows.Scan(&h.id, &h.name, &h.address, &h.minPrice, &h.distanceKm)
+			return h, err
+		},
+		func(h filteredHotelRow) (any, pgtype.UUID) {
+			switch page.SortBy {
+			case "distance":
+				return h.distanceKm, h.id
+			case "price":
+				return h.minPrice, h.id
+			default:
+				return h.name, h.id
+			}
+		})
+	if err != nil {
+		zap.S().Errorln("Failed to Filter Hotels: ", err)
+		return nil, nil, err
+	}
+
+	results := make([]hotel_domain.FilteredHotel, 0, len(hotels))
+	for _, h := range hotels {
+		results = append(results, hotel_domain.FilteredHotel{
+			Id:         h.id.String(),
+			Name:       h.name,
+			Address:    h.address.String,
+			MinPrice:   h.minPrice,
+			DistanceKm: h.distanceKm,
+		})
+	}
+
+	return results, pageInfo, nil
+}
+
+type filteredHotelRow struct {
+	id         pgtype.UUID
+	name       string
+	address    pgtype.Text
+	minPrice   int32
+	distanceKm float64
+}
+
+// Room types matching the filters with fewer booked or blocked rooms than rooms out of maintenance
+func (hs *HotelService) getFreeRoomTypeIds(ctx context.Context, params *FilterHotelsParams, area *geoSearchArea) ([]pgtype.UUID, error) {
+
+	view, err := params.RoomTypeFilter.viewParam()
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	conditions.Add("h.status = 'PUBLISHED'")
+	conditions.Add("rt.price > 0")
+
+	if addressQuery := toPrefixTsQuery(params.Address, ADDRESS_SEARCH_WEIGHT); addressQuery.Valid {
+		conditions.Add("d.search_vector @@ to_tsquery('simple', immutable_unaccent($%d::text))", addressQuery)
+	}
+	if nameQuery := toPrefixTsQuery(params.HotelName, NAME_SEARCH_WEIGHT); nameQuery.Valid {
+		conditions.Add("d.search_vector @@ to_tsquery('simple', immutable_unaccent($%d::text))", nameQuery)
+	}
+	if params.MinPrice > 0 {
+		conditions.Add("rt.price >= $%d", params.MinPrice)
+	}
+	if params.MaxPrice > 0 {
+		conditions.Add("rt.price <= $%d", params.MaxPrice)
+	}
+	if area != nil {
+		conditions.Add(area.inAreaSql(conditions, area.centerSql(conditions)))
+	}
+	if amenityCodes := hotel_domain.NormalizeAmenityCodes(params.AmenityCodes); len(amenityCodes) > 0 {
+		codes := conditions.Arg(amenityCodes)
+		conditions.Add(fmt.Sprintf(`(
+			SELECT COUNT(*)
+			FROM amenities a
+			WHERE
+				a.code = ANY(%[1]s::varchar[])
+				AND (
+					EXISTS (SELECT 1 FROM hotel_amenities ha WHERE ha.hotel_id = h.id AND ha.amenity_id = a.id)
+					OR EXISTS (SELECT 1 FROM room_type_amenities rta WHERE rta.room_type_id = rt.id AND rta.amenity_id = a.id)
+				)
+		) = cardinality(%[1]s::varchar[])`, codes))
+	}
+	if params.RoomTypeFilter.MinAreaSqm > 0 {
+		conditions.Add("rt.area_sqm >= $%d", params.RoomTypeFilter.MinAreaSqm)
+	}
+	if view.Valid {
+		conditions.Add("rt.view::text = $%d", view.String)
+	}
+	if params.RoomTypeFilter.SmokingAllowed != nil {
+		conditions.Add("rt.smoking_allowed = $%d", *params.RoomTypeFilter.SmokingAllowed)
+	}
+	if params.RoomTypeFilter.Guests > 0 {
+		conditions.Add("rt.max_occupancy >= $%d", params.RoomTypeFilter.Guests)
+	}
+
+	stmt := fmt.Sprintf(`SELECT rt.id, COUNT(r.id)::int
+		FROM hotels h
+			JOIN hotel_search_documents d ON d.hotel_id = h.id
+			JOIN room_types rt ON rt.hotel_id = h.id
+			JOIN rooms r ON r.room_type_id = rt.id AND r.status != 'MAINTAINED'
+		WHERE %s
+		GROUP BY rt.id`, conditions.String())
+
+	rows, err := hs.conn.Query(ctx, stmt, conditions.Args...)
+	if err != nil {
+		zap.S().Errorln("Failed to get Room Types to filter Hotels: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	numberOfRooms := map[string]int32{}
+	roomTypeIds := []string{}
+	for rows.Next() {
+		var roomTypeId pgtype.UUID
+		var rooms int32
+		if err := rows.Scan(&roomTypeId, &rooms); err != nil {
+			return nil, err
+		}
+		numberOfRooms[roomTypeId.String()] = rooms
+		roomTypeIds = append(roomTypeIds, roomTypeId.String())
+	}
+	if err := rows.Err(); err != nil {
+		zap.S().Errorln("Failed to read Room Types to filter Hotels: ", err)
+		return nil, err
+	}
+
+	if len(roomTypeIds) == 0 {
+		return []pgtype.UUID{}, nil
+	}
+
+	occupied, err := hs.bookingClient.GetNumberOfOccupiedRooms(ctx, &booking_pb.GetNumberOfOccupiedRoomsRequest{
+		RoomTypeIds: roomTypeIds,
+		CheckIn:     params.CheckIn.Time.Format(consts.DATE_FORMAT),
+		CheckOut:    params.CheckOut.Time.Format(consts.DATE_FORMAT),
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get Number of Occupied Rooms: ", err)
+		return nil, err
+	}
+
+	for _, result := range occupied.GetResults() {
+		numberOfRooms[result.GetRoomTypeId()] -= result.GetNumberOfOccupiedRooms()
+	}
+
+	return freeRoomTypeIds(roomTypeIds, numberOfRooms), nil
+}
+
+// Room types in the order of roomTypeIds with at least one free room left
+func freeRoomTypeIds(roomTypeIds []string, freeRooms map[string]int32) []pgtype.UUID {
+	results := make([]pgtype.UUID, 0, len(roomTypeIds))
+	for _, roomTypeId := range roomTypeIds {
+		if freeRooms[roomTypeId] <= 0 {
+			continue
+		}
+
+		var id pgtype.UUID
+		if err := id.Scan(roomTypeId); err == nil {
+			results = append(results, id)
+		}
+	}
+
+	return results
+}
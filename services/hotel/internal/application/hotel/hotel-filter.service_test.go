@@ -0,0 +1,54 @@
+package hotel_service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFreeRoomTypeIds(t *testing.T) {
+	const (
+		free     = "00000000-0000-0000-0000-000000000001"
+		full     = "00000000-0000-0000-0000-000000000002"
+		overbook = "00000000-0000-0000-0000-000000000003"
+		unknown  = "00000000-0000-0000-0000-000000000004"
+		lastFree = "00000000-0000-0000-0000-000000000005"
+	)
+
+	tests := []struct {
+		name        string
+		roomTypeIds []string
+		freeRooms   map[string]int32
+		want        []string
+	}{
+		{
+			name:        "no room type",
+			roomTypeIds: []string{},
+			want:        []string{},
+		},
+		{
+			name:        "keeps the order of room types with a free room",
+			roomTypeIds: []string{lastFree, full, free, overbook, unknown},
+			freeRooms:   map[string]int32{free: 3, full: 0, overbook: -1, lastFree: 1},
+			want:        []string{lastFree, free},
+		},
+		{
+			name:        "skips invalid ids",
+			roomTypeIds: []string{"not-a-uuid", free},
+			freeRooms:   map[string]int32{"not-a-uuid": 2, free: 2},
+			want:        []string{free},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, id := range freeRoomTypeIds(tt.roomTypeIds, tt.freeRooms) {
+				got = append(got, id.String())
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("freeRoomTypeIds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
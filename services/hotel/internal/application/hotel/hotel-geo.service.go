@@ -0,0 +1,262 @@
+package hotel_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
+	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const MAX_GEO_SEARCH_RADIUS_KM = 500
+
+// Radius of the earth used by earthdistance, so distances computed here match the database
+const EARTH_RADIUS_METERS = 6378168
+
+// Search around Center within RadiusKm, inside BoundingBox, or both.
+// A box without center is searched from its middle, a box without radius is searched up to its farthest corner
+type GeoSearchParams struct {
+	Center      *hotel_domain.GeoPoint
+	RadiusKm    float64
+	BoundingBox *hotel_domain.GeoBoundingBox
+}
+
+// Circle the GiST index can answer, narrowed down to the bounding box when there is one
+type geoSearchArea struct {
+	center       hotel_domain.GeoPoint
+	radiusMeters float64
+	box          *hotel_domain.GeoBoundingBox
+}
+
+var nearbyHotelsSortColumns = map[string]utils.SortColumn{
+	"distance": {Column: "distance_km", Cast: "float8"},
+	"name":     {Column: "name", Cast: "text"},
+}
+
+// Published hotels of the search area, nearest first by default.
+// ErrBadRequest when the area or the page is invalid
+func (hs *HotelService) SearchHotelsByLocation(ctx context.Context, params GeoSearchParams, pageParams utils.PageParams) ([]hotel_domain.NearbyHotel, *utils.PageInfo, error) {
+
+	area, err := toGeoSearchArea(params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if area == nil {
+		zap.S().Infoln("Geo search needs a point or a bounding box")
+		return nil, nil, common_error.ErrBadRequest
+	}
+
+	page, err := utils.NewPage(pageParams, nearbyHotelsSortColumns, "distance", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of nearby hotels: ", pageParams)
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	center := area.centerSql(conditions)
+	inArea := area.inAreaSql(conditions, center)
+
+	// Distance is computed once per hotel so pages can continue after it
+	nearby := fmt.Sprintf(`(
+		SELECT
+			h.*,
+			(earth_distance(ll_to_earth(h.latitude, h.longitude), %s) / 1000)::float8 AS distance_km
+		FROM hotels h
+		WHERE h.status = 'PUBLISHED' AND %s
+	) AS nearby`, center, inArea)
+
+	type nearbyHotel struct {
+		hotel      hotel_repo.Hotel
+		distanceKm float64
+	}
+
+	rows, pageInfo, err := utils.QueryPage(ctx, hs.conn, page, hotelColumns+", distance_km", nearby, conditions,
+		func(rows pgx.Rows) (nearbyHotel, error) {
+			var h nearbyHotel
+			err := rows.Scan(
+				&h.hotel.ID,
+				&h.hotel.Name,
+				&h.hotel.Address,
+				&h.hotel.Latitude,
+				&h.hotel.Longitude,
+				&h.hotel.City,
+				&h.hotel.Description,
+				&h.hotel.Status,
+				&h.hotel.CreatedBy,
+				&h.hotel.ChainID,
+				&h.distanceKm,
+			)
+			return h, err
+		},
+		func(h nearbyHotel) (any, pgtype.UUID) {
+			if page.SortBy == "name" {
+				return h.hotel.Name, h.hotel.ID
+			}
+			return h.distanceKm, h.hotel.ID
+		})
+	if err != nil {
+		zap.S().Errorln("Failed to search Hotels by location: ", err)
+		return nil, nil, err
+	}
+
+	results := make([]hotel_domain.NearbyHotel, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, hotel_domain.NearbyHotel{
+			Hotel:      hotel_repo_mapping.FromHotelRepoToHotelDomain(row.hotel),
+			DistanceKm: row.distanceKm,
+		})
+	}
+
+	return results, pageInfo, nil
+}
+
+// Coordinates of the hotel, when none are given the stored ones of current (nil for a new hotel) are kept
+// while its address and city are unchanged, otherwise address and city are geocoded.
+// An address the geocoder does not know leaves the hotel without location
+func (hs *HotelService) resolveLocation(ctx context.Context, current *hotel_repo.Hotel, address pgtype.Text, city pgtype.Text, latitude pgtype.Float8, longitude pgtype.Float8) (pgtype.Float8, pgtype.Float8) {
+	if latitude.Valid && longitude.Valid {
+		return latitude, longitude
+	}
+
+	if current != nil && current.Latitude.Valid && current.Longitude.Valid &&
+		sameLocationText(current.Address, address) && sameLocationText(current.City, city) {
+		return current.Latitude, current.Longitude
+	}
+
+	fullAddress := address.String
+	if city.Valid && city.String != "" {
+		fullAddress += ", " + city.String
+	}
+
+	point, err := hs.geocoder.Geocode(ctx, fullAddress)
+	if err != nil {
+		if !errors.Is(err, hotel_domain.ErrAddressNotFound) {
+			zap.S().Errorln("Failed to geocode address: ", err)
+		}
+		zap.S().Infoln("Hotel is saved without location: ", fullAddress)
+		return pgtype.Float8{}, pgtype.Float8{}
+	}
+
+	return pgtype.Float8{Float64: point.Latitude, Valid: true}, pgtype.Float8{Float64: point.Longitude, Valid: true}
+}
+
+// NULL and blank are the same empty location part
+func sameLocationText(a pgtype.Text, b pgtype.Text) bool {
+	return strings.EqualFold(strings.TrimSpace(a.String), strings.TrimSpace(b.String))
+}
+
+// nil area when neither point nor box is given
+func toGeoSearchArea(params GeoSearchParams) (*geoSearchArea, error) {
+	if params.Center == nil && params.BoundingBox == nil {
+		return nil, nil
+	}
+
+	if params.RadiusKm < 0 || params.RadiusKm > MAX_GEO_SEARCH_RADIUS_KM {
+		zap.S().Infoln("Geo search radius out of range: ", params.RadiusKm)
+		return nil, common_error.ErrBadRequest
+	}
+
+	box := params.BoundingBox
+	if box != nil {
+		if !isValidGeoPoint(box.MinLatitude, box.MinLongitude) || !isValidGeoPoint(box.MaxLatitude, box.MaxLongitude) ||
+			box.MinLatitude > box.MaxLatitude || box.MinLongitude > box.MaxLongitude {
+			zap.S().Infoln("Invalid bounding box: ", *box)
+			return nil, common_error.ErrBadRequest
+		}
+	}
+
+	var center hotel_domain.GeoPoint
+	if params.Center != nil {
+		if !isValidGeoPoint(params.Center.Latitude, params.Center.Longitude) {
+			zap.S().Infoln("Invalid geo search point: ", *params.Center)
+			return nil, common_error.ErrBadRequest
+		}
+		center = *params.Center
+	} else {
+		center = hotel_domain.GeoPoint{
+			Latitude:  (box.MinLatitude + box.MaxLatitude) / 2,
+			Longitude: (box.MinLongitude + box.MaxLongitude) / 2,
+		}
+	}
+
+	radiusMeters := params.RadiusKm * 1000
+	if radiusMeters == 0 {
+		if box == nil {
+			zap.S().Infoln("Geo search around a point needs a radius")
+			return nil, common_error.ErrBadRequest
+		}
+		radiusMeters = farthestCornerMeters(center, *box)
+	}
+
+	return &geoSearchArea{
+		center:       center,
+		radiusMeters: radiusMeters,
+		box:          box,
+	}, nil
+}
+
+// Center of the area as an earth point, its values are added to conditions
+func (area *geoSearchArea) centerSql(conditions *utils.Conditions) string {
+	return fmt.Sprintf("ll_to_earth(%s::float8, %s::float8)", conditions.Arg(area.center.Latitude), conditions.Arg(area.center.Longitude))
+}
+
+// Condition keeping the hotels h inside the area, its values are added to conditions.
+// earth_box is answered by the GiST index, earth_distance drops the corners of the box
+func (area *geoSearchArea) inAreaSql(conditions *utils.Conditions, center string) string {
+	radiusMeters := conditions.Arg(area.radiusMeters)
+
+	inArea := fmt.Sprintf(`earth_box(%[1]s, %[2]s::float8) @> ll_to_earth(h.latitude, h.longitude)
+			AND earth_distance(ll_to_earth(h.latitude, h.longitude), %[1]s) <= %[2]s::float8`, center, radiusMeters)
+	if area.box != nil {
+		inArea += fmt.Sprintf(`
+			AND h.latitude BETWEEN %s::float8 AND %s::float8
+			AND h.longitude BETWEEN %s::float8 AND %s::float8`,
+			conditions.Arg(area.box.MinLatitude), conditions.Arg(area.box.MaxLatitude),
+			conditions.Arg(area.box.MinLongitude), conditions.Arg(area.box.MaxLongitude))
+	}
+
+	return inArea
+}
+
+func isValidGeoPoint(latitude float64, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// Radius around center that covers the whole box, with a little slack for rounding
+func farthestCornerMeters(center hotel_domain.GeoPoint, box hotel_domain.GeoBoundingBox) float64 {
+	corners := []hotel_domain.GeoPoint{
+		{Latitude: box.MinLatitude, Longitude: box.MinLongitude},
+		{Latitude: box.MinLatitude, Longitude: box.MaxLongitude},
+		{Latitude: box.MaxLatitude, Longitude: box.MinLongitude},
+		{Latitude: box.MaxLatitude, Longitude: box.MaxLongitude},
+	}
+
+	farthest := 0.0
+	for _, corner := range corners {
+		farthest = math.Max(farthest, distanceMeters(center, corner))
+	}
+
+	return farthest*1.01 + 1
+}
+
+// Great circle distance (haversine)
+func distanceMeters(a hotel_domain.GeoPoint, b hotel_domain.GeoPoint) float64 {
+	latitudeA := a.Latitude * math.Pi / 180
+	latitudeB := b.Latitude * math.Pi / 180
+	deltaLatitude := latitudeB - latitudeA
+	deltaLongitude := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Pow(math.Sin(deltaLatitude/2), 2) + math.Cos(latitudeA)*math.Cos(latitudeB)*math.Pow(math.Sin(deltaLongitude/2), 2)
+
+	return 2 * EARTH_RADIUS_METERS * math.Asin(math.Min(1, math.Sqrt(h)))
+}
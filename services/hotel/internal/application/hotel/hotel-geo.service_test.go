@@ -0,0 +1,138 @@
+package hotel_service
+
+import (
+	"context"
+	"testing"
+
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Answers every address with the same point and counts the lookups
+type countingGeocoder struct {
+	point hotel_domain.GeoPoint
+	err   error
+	calls int
+}
+
+func (cg *countingGeocoder) Geocode(ctx context.Context, address string) (hotel_domain.GeoPoint, error) {
+	cg.calls++
+	return cg.point, cg.err
+}
+
+func geoText(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func geoFloat(f float64) pgtype.Float8 {
+	return pgtype.Float8{Float64: f, Valid: true}
+}
+
+func TestResolveLocation(t *testing.T) {
+	stored := &hotel_repo.Hotel{
+		Address:   geoText("1 Le Loi"),
+		City:      geoText("Ho Chi Minh"),
+		Latitude:  geoFloat(10.77),
+		Longitude: geoFloat(106.70),
+	}
+	geocoded := hotel_domain.GeoPoint{Latitude: 21.02, Longitude: 105.85}
+
+	tests := []struct {
+		name          string
+		current       *hotel_repo.Hotel
+		address       string
+		city          string
+		latitude      pgtype.Float8
+		longitude     pgtype.Float8
+		geocodeErr    error
+		wantLatitude  pgtype.Float8
+		wantLongitude pgtype.Float8
+		wantGeocodes  int
+	}{
+		{
+			name:          "given coordinates win",
+			current:       stored,
+			address:       "2 Hang Bai",
+			city:          "Ha Noi",
+			latitude:      geoFloat(1),
+			longitude:     geoFloat(2),
+			wantLatitude:  geoFloat(1),
+			wantLongitude: geoFloat(2),
+		},
+		{
+			name:          "new hotel is geocoded",
+			address:       "1 Le Loi",
+			city:          "Ho Chi Minh",
+			wantLatitude:  geoFloat(geocoded.Latitude),
+			wantLongitude: geoFloat(geocoded.Longitude),
+			wantGeocodes:  1,
+		},
+		{
+			name:          "unchanged address keeps stored coordinates",
+			current:       stored,
+			address:       "1 Le Loi",
+			city:          "Ho Chi Minh",
+			wantLatitude:  stored.Latitude,
+			wantLongitude: stored.Longitude,
+		},
+		{
+			name:          "case and spaces are not a change",
+			current:       stored,
+			address:       " 1 le loi ",
+			city:          "HO CHI MINH",
+			wantLatitude:  stored.Latitude,
+			wantLongitude: stored.Longitude,
+		},
+		{
+			name:          "changed address is geocoded",
+			current:       stored,
+			address:       "2 Hang Bai",
+			city:          "Ho Chi Minh",
+			wantLatitude:  geoFloat(geocoded.Latitude),
+			wantLongitude: geoFloat(geocoded.Longitude),
+			wantGeocodes:  1,
+		},
+		{
+			name:          "changed city is geocoded",
+			current:       stored,
+			address:       "1 Le Loi",
+			city:          "Ha Noi",
+			wantLatitude:  geoFloat(geocoded.Latitude),
+			wantLongitude: geoFloat(geocoded.Longitude),
+			wantGeocodes:  1,
+		},
+		{
+			name:          "stored hotel without location is geocoded",
+			current:       &hotel_repo.Hotel{Address: geoText("1 Le Loi"), City: geoText("Ho Chi Minh")},
+			address:       "1 Le Loi",
+			city:          "Ho Chi Minh",
+			wantLatitude:  geoFloat(geocoded.Latitude),
+			wantLongitude: geoFloat(geocoded.Longitude),
+			wantGeocodes:  1,
+		},
+		{
+			name:         "unknown address leaves no location",
+			current:      stored,
+			address:      "somewhere",
+			city:         "",
+			geocodeErr:   hotel_domain.ErrAddressNotFound,
+			wantGeocodes: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			geocoder := &countingGeocoder{point: geocoded, err: tt.geocodeErr}
+			hs := &HotelService{geocoder: geocoder}
+
+			latitude, longitude := hs.resolveLocation(context.Background(), tt.current, geoText(tt.address), geoText(tt.city), tt.latitude, tt.longitude)
+			if latitude != tt.wantLatitude || longitude != tt.wantLongitude {
+				t.Errorf("resolveLocation() = (%v, %v), want (%v, %v)", latitude, longitude, tt.wantLatitude, tt.wantLongitude)
+			}
+			if geocoder.calls != tt.wantGeocodes {
+				t.Errorf("resolveLocation() geocoded %d times, want %d", geocoder.calls, tt.wantGeocodes)
+			}
+		})
+	}
+}
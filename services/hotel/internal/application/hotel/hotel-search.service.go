@@ -0,0 +1,187 @@
+package hotel_service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
+	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Weights of the hotel search document, see hotel_search_documents
+const (
+	NAME_SEARCH_WEIGHT    = "A"
+	ADDRESS_SEARCH_WEIGHT = "B" // address and city
+)
+
+var rankedHotelsSortColumns = map[string]utils.SortColumn{
+	"rank": {Column: "rank", Cast: "real", DefaultDesc: true},
+	"name": {Column: "name", Cast: "text"},
+}
+
+// Hotels matching the search text over name, address, city and description, best match first by default.
+// Web search syntax is accepted: "quoted phrase", or, -word. ErrBadRequest when the text is empty or the page is invalid
+func (hs *HotelService) SearchHotels(ctx context.Context, query string, pageParams utils.PageParams) ([]hotel_domain.RankedHotel, *utils.PageInfo, error) {
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		zap.S().Infoln("Search text is empty")
+		return nil, nil, common_error.ErrBadRequest
+	}
+
+	page, err := utils.NewPage(pageParams, rankedHotelsSortColumns, "rank", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of searched hotels: ", pageParams)
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	tsQuery := fmt.Sprintf("websearch_to_tsquery('simple', immutable_unaccent(%s::text))", conditions.Arg(query))
+
+	// Rank is computed once per hotel so pages can continue after it
+	ranked := fmt.Sprintf(`(
+		SELECT h.*, ts_rank(d.search_vector, %[1]s)::real AS rank
+		FROM hotels h JOIN hotel_search_documents d ON d.hotel_id = h.id
+		WHERE d.search_vector @@ %[1]s AND h.status = 'PUBLISHED'
+	) AS ranked`, tsQuery)
+
+	rows, pageInfo, err := utils.QueryPage(ctx, hs.conn, page, hotelColumns+", rank", ranked, conditions, scanRankedHotel, rankedHotelSortKey(page))
+	if err != nil {
+		zap.S().Errorln("Failed to search Hotels: ", err)
+		return nil, nil, err
+	}
+
+	results := make([]hotel_domain.RankedHotel, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, hotel_domain.RankedHotel{
+			Hotel: hotel_repo_mapping.FromHotelRepoToHotelDomain(row.hotel),
+			Rank:  row.rank,
+		})
+	}
+
+	return results, pageInfo, nil
+}
+
+// Hotels and cities for the search box, every typed word is matched as a prefix.
+// The page is a page of the hotels, at most a page size of cities is suggested
+func (hs *HotelService) AutocompleteHotels(ctx context.Context, prefix string, pageParams utils.PageParams) (*hotel_domain.HotelAutocomplete, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, rankedHotelsSortColumns, "rank", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of autocompleted hotels: ", pageParams)
+		return nil, nil, err
+	}
+
+	result := &hotel_domain.HotelAutocomplete{
+		Hotels: []hotel_domain.Hotel{},
+		Cities: []string{},
+	}
+
+	prefixQuery := toPrefixTsQuery(prefix, "")
+	if !prefixQuery.Valid {
+		return result, &utils.PageInfo{}, nil
+	}
+
+	conditions := &utils.Conditions{}
+	tsQuery := fmt.Sprintf("to_tsquery('simple', immutable_unaccent(%s::text))", conditions.Arg(prefixQuery))
+
+	ranked := fmt.Sprintf(`(
+		SELECT h.*, ts_rank(d.search_vector, %[1]s)::real AS rank
+		FROM hotels h JOIN hotel_search_documents d ON d.hotel_id = h.id
+		WHERE d.search_vector @@ %[1]s AND h.status = 'PUBLISHED'
+	) AS ranked`, tsQuery)
+
+	hotels, pageInfo, err := utils.QueryPage(ctx, hs.conn, page, hotelColumns+", rank", ranked, conditions, scanRankedHotel, rankedHotelSortKey(page))
+	if err != nil {
+		zap.S().Errorln("Failed to autocomplete Hotels: ", err)
+		return nil, nil, err
+	}
+
+	for _, hotel := range hotels {
+		result.Hotels = append(result.Hotels, hotel_domain.Hotel{
+			Id:   hotel.hotel.ID.String(),
+			Name: hotel.hotel.Name,
+			City: hotel.hotel.City.String,
+		})
+	}
+
+	cities, err := hs.repo.AutocompleteCities(ctx, hotel_repo.AutocompleteCitiesParams{
+		Prefix:     pgtype.Text{String: escapeLikePattern(strings.TrimSpace(prefix)), Valid: true},
+		MaxResults: int32(page.Size),
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to autocomplete cities: ", err)
+		return nil, nil, err
+	}
+
+	for _, city := range cities {
+		result.Cities = append(result.Cities, city.String)
+	}
+
+	return result, pageInfo, nil
+}
+
+type rankedHotelRow struct {
+	hotel hotel_repo.Hotel
+	rank  float32
+}
+
+func scanRankedHotel(rows pgx.Rows) (rankedHotelRow, error) {
+	var h rankedHotelRow
+	err := rows.Scan(
+		&h.hotel.ID,
+		&h.hotel.Name,
+		&h.hotel.Address,
+		&h.hotel.Latitude,
+		&h.hotel.Longitude,
+		&h.hotel.City,
+		&h.hotel.Description,
+		&h.hotel.Status,
+		&h.hotel.CreatedBy,
+		&h.hotel.ChainID,
+		&h.rank,
+	)
+	return h, err
+}
+
+// Sort value and id of a ranked hotel for the cursor of the next page
+func rankedHotelSortKey(page *utils.Page) func(rankedHotelRow) (any, pgtype.UUID) {
+	return func(h rankedHotelRow) (any, pgtype.UUID) {
+		if page.SortBy == "name" {
+			return h.hotel.Name, h.hotel.ID
+		}
+		return h.rank, h.hotel.ID
+	}
+}
+
+// Words of free text as a tsquery where every word is a prefix, e.g. "Hồ Chí" is 'hồ:* & chí:*'.
+// Only letters and digits are kept so user input cannot add tsquery operators.
+// weights restricts the words to those parts of the search document, empty matches everywhere
+func toPrefixTsQuery(text string, weights string) pgtype.Text {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(words) == 0 {
+		return pgtype.Text{}
+	}
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, word+":*"+weights)
+	}
+
+	return pgtype.Text{String: strings.Join(terms, " & "), Valid: true}
+}
+
+func escapeLikePattern(pattern string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(pattern)
+}
@@ -0,0 +1,123 @@
+package hotel_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestToPrefixTsQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		weights string
+		want    pgtype.Text
+	}{
+		{"every word is a prefix", "Hồ Chí", "", pgtype.Text{String: "hồ:* & chí:*", Valid: true}},
+		{"restricted to weights", "da nang", ADDRESS_SEARCH_WEIGHT, pgtype.Text{String: "da:*B & nang:*B", Valid: true}},
+		{"digits are kept", "Quan 1", NAME_SEARCH_WEIGHT, pgtype.Text{String: "quan:*A & 1:*A", Valid: true}},
+		// Operators typed by the user never reach the tsquery
+		{"tsquery operators are dropped", "sea & !view | (pool):*", "", pgtype.Text{String: "sea:* & view:* & pool:*", Valid: true}},
+		{"no words", "  - & ", "", pgtype.Text{}},
+		{"empty text", "", NAME_SEARCH_WEIGHT, pgtype.Text{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toPrefixTsQuery(tt.text, tt.weights); got != tt.want {
+				t.Errorf("toPrefixTsQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeLikePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"Hue", "Hue"},
+		{"100%", `100\%`},
+		{"ha_noi", `ha\_noi`},
+		{`a\b`, `a\\b`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := escapeLikePattern(tt.pattern); got != tt.want {
+				t.Errorf("escapeLikePattern() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankedHotelSortKey(t *testing.T) {
+	row := rankedHotelRow{
+		hotel: hotel_repo.Hotel{ID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}, Name: "Hotel"},
+		rank:  0.5,
+	}
+
+	tests := []struct {
+		sortBy string
+		want   any
+	}{
+		{"rank", float32(0.5)},
+		{"name", "Hotel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			got, id := rankedHotelSortKey(&utils.Page{SortBy: tt.sortBy})(row)
+			if got != tt.want || id != row.hotel.ID {
+				t.Errorf("rankedHotelSortKey() = %v, %v, want %v, %v", got, id, tt.want, row.hotel.ID)
+			}
+		})
+	}
+}
+
+func TestSearchHotelsInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		pageParams utils.PageParams
+	}{
+		{"empty text", "   ", utils.PageParams{}},
+		{"unknown sort", "hue", utils.PageParams{SortBy: "price"}},
+		{"unknown sort direction", "hue", utils.PageParams{SortDirection: "up"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &HotelService{}
+
+			if _, _, err := hs.SearchHotels(context.Background(), tt.query, tt.pageParams); !errors.Is(err, common_error.ErrBadRequest) {
+				t.Errorf("SearchHotels() error = %v, want %v", err, common_error.ErrBadRequest)
+			}
+		})
+	}
+}
+
+func TestAutocompleteHotelsWithoutWords(t *testing.T) {
+	hs := &HotelService{}
+
+	// Nothing typed yet is answered without querying
+	result, pageInfo, err := hs.AutocompleteHotels(context.Background(), " -- ", utils.PageParams{})
+	if err != nil {
+		t.Fatalf("AutocompleteHotels() error = %v", err)
+	}
+	if result.Hotels == nil || result.Cities == nil || len(result.Hotels) != 0 || len(result.Cities) != 0 {
+		t.Errorf("AutocompleteHotels() = %+v, want empty lists", result)
+	}
+	if pageInfo == nil || pageInfo.NextCursor != "" {
+		t.Errorf("AutocompleteHotels() page = %+v, want a last page", pageInfo)
+	}
+
+	if _, _, err := hs.AutocompleteHotels(context.Background(), "hue", utils.PageParams{SortBy: "price"}); !errors.Is(err, common_error.ErrBadRequest) {
+		t.Errorf("AutocompleteHotels() error = %v, want %v", err, common_error.ErrBadRequest)
+	}
+}
@@ -0,0 +1,203 @@
+package hotel_service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
+	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var ErrHotelStatusChangeNotAllowed = errors.New("not allowed to change hotel status")
+var ErrInvalidHotelStatusTransition = errors.New("invalid hotel status transition")
+var ErrHotelAccessDenied = errors.New("not allowed to access hotel")
+var ErrHotelReviewNotAllowed = errors.New("only admins review hotels")
+
+var hotelStatuses = []hotel_repo.HotelStatus{
+	hotel_repo.HotelStatusDRAFT,
+	hotel_repo.HotelStatusSUBMITTED,
+	hotel_repo.HotelStatusAPPROVED,
+	hotel_repo.HotelStatusREJECTED,
+	hotel_repo.HotelStatusPUBLISHED,
+}
+
+// Draft or rejected hotel goes to the review queue of ADMIN.
+// Only the user who created the hotel or an admin can submit it
+func (hs *HotelService) SubmitHotel(ctx context.Context, id pgtype.UUID, comment string, requester hotel_domain.HotelRequester) (*hotel_domain.Hotel, error) {
+	return hs.changeHotelStatus(ctx, id, hotel_repo.HotelStatusSUBMITTED, comment, createdByOrAdmin(requester), hotel_repo.HotelStatusDRAFT, hotel_repo.HotelStatusREJECTED)
+}
+
+// ADMIN accepts a submitted hotel, it is listed once published. ErrHotelReviewNotAllowed for other requesters
+func (hs *HotelService) ApproveHotel(ctx context.Context, id pgtype.UUID, comment string, requester hotel_domain.HotelRequester) (*hotel_domain.Hotel, error) {
+	if !requester.IsAdmin() {
+		zap.S().Infoln("Only admins approve Hotels")
+		return nil, ErrHotelReviewNotAllowed
+	}
+
+	return hs.changeHotelStatus(ctx, id, hotel_repo.HotelStatusAPPROVED, comment, nil, hotel_repo.HotelStatusSUBMITTED)
+}
+
+// ADMIN sends a submitted hotel back to its manager, ErrBadRequest without comment.
+// ErrHotelReviewNotAllowed for other requesters
+func (hs *HotelService) RejectHotel(ctx context.Context, id pgtype.UUID, comment string, requester hotel_domain.HotelRequester) (*hotel_domain.Hotel, error) {
+	if !requester.IsAdmin() {
+		zap.S().Infoln("Only admins reject Hotels")
+		return nil, ErrHotelReviewNotAllowed
+	}
+
+	if strings.TrimSpace(comment) == "" {
+		zap.S().Infoln("Rejecting a Hotel needs a comment")
+		return nil, common_error.ErrBadRequest
+	}
+
+	return hs.changeHotelStatus(ctx, id, hotel_repo.HotelStatusREJECTED, comment, nil, hotel_repo.HotelStatusSUBMITTED)
+}
+
+// Approved hotel is shown in public listing and search.
+// Only the user who created the hotel or an admin can publish it
+func (hs *HotelService) PublishHotel(ctx context.Context, id pgtype.UUID, requester hotel_domain.HotelRequester) (*hotel_domain.Hotel, error) {
+	return hs.changeHotelStatus(ctx, id, hotel_repo.HotelStatusPUBLISHED, "", createdByOrAdmin(requester), hotel_repo.HotelStatusAPPROVED)
+}
+
+// Submitting and publishing is left to the user who created the hotel, managers of other hotels cannot
+func createdByOrAdmin(requester hotel_domain.HotelRequester) func(hotel_repo.Hotel) bool {
+	return func(hotel hotel_repo.Hotel) bool {
+		return requester.IsAdmin() || (requester.UserId != "" && hotel.CreatedBy.Valid && hotel.CreatedBy.String() == requester.UserId)
+	}
+}
+
+// Transitions of the hotel, oldest first.
+// ErrHotelAccessDenied when requester cannot manage the hotel
+func (hs *HotelService) GetHotelStatusHistory(ctx context.Context, id pgtype.UUID, requester hotel_domain.HotelRequester) ([]hotel_domain.HotelStatusEvent, error) {
+
+	hotel, err := hs.GetHotelById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !requester.CanManage(*hotel) {
+		zap.S().Infoln("Not allowed to get status history of Hotel")
+		return nil, ErrHotelAccessDenied
+	}
+
+	events, err := hs.repo.GetHotelStatusEventsByHotelId(ctx, id)
+	if err != nil {
+		zap.S().Errorln("Failed to get Hotel Status Events: ", err)
+		return nil, err
+	}
+
+	result := make([]hotel_domain.HotelStatusEvent, 0, len(events))
+	for _, event := range events {
+		result = append(result, hotel_repo_mapping.FromHotelStatusEventRepoToHotelStatusEventDomain(event))
+	}
+
+	return result, nil
+}
+
+// Move the hotel to status and record the transition in one transaction, then publish it.
+// allowed is nil when the requester was already checked.
+// ErrNoRows when the hotel does not exist, ErrInvalidHotelStatusTransition when it is not in one of fromStatuses
+func (hs *HotelService) changeHotelStatus(ctx context.Context, id pgtype.UUID, status hotel_repo.HotelStatus, comment string, allowed func(hotel_repo.Hotel) bool, fromStatuses ...hotel_repo.HotelStatus) (*hotel_domain.Hotel, error) {
+
+	tx, err := hs.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin hotel status transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := hs.repo.WithTx(tx)
+
+	current, err := qtx.GetHotelById(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("No Hotel to change status")
+		return nil, common_error.ErrNoRows
+	}
+	if err != nil {
+		zap.S().Errorln("Cannot get Hotel by id: ", err)
+		return nil, err
+	}
+
+	if allowed != nil && !allowed(current) {
+		zap.S().Infoln("Not allowed to change status of Hotel to: ", status)
+		return nil, ErrHotelStatusChangeNotAllowed
+	}
+
+	if !slices.Contains(fromStatuses, current.Status) {
+		zap.S().Infoln("Hotel cannot go from ", current.Status, " to ", status)
+		return nil, ErrInvalidHotelStatusTransition
+	}
+
+	from := make([]string, 0, len(fromStatuses))
+	for _, fromStatus := range fromStatuses {
+		from = append(from, string(fromStatus))
+	}
+
+	// Status is checked again by the update so a concurrent transition is not overwritten
+	hotel, err := qtx.UpdateHotelStatus(ctx, hotel_repo.UpdateHotelStatusParams{
+		ToStatus:     status,
+		ID:           id,
+		FromStatuses: from,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		zap.S().Infoln("Hotel status changed concurrently")
+		return nil, ErrInvalidHotelStatusTransition
+	}
+	if err != nil {
+		zap.S().Errorln("Failed to update Hotel status: ", err)
+		return nil, err
+	}
+
+	comment = strings.TrimSpace(comment)
+	event, err := qtx.CreateHotelStatusEvent(ctx, hotel_repo.CreateHotelStatusEventParams{
+		HotelID:    id,
+		FromStatus: current.Status,
+		ToStatus:   status,
+		Actor:      pgtype.Text{String: hotelStatusActor(ctx), Valid: true},
+		Comment:    pgtype.Text{String: comment, Valid: comment != ""},
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to record Hotel Status Event: ", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit hotel status transaction: ", err)
+		return nil, err
+	}
+
+	// Notify other services after the transition is saved
+	if hs.producer != nil {
+		hs.producer.PublishHotelStatusChanged(ctx, hotel_domain.HotelStatusChangedEvent{
+			HotelId:    hotel.ID.String(),
+			Name:       hotel.Name,
+			FromStatus: string(event.FromStatus),
+			ToStatus:   string(event.ToStatus),
+			Actor:      event.Actor.String,
+			Comment:    event.Comment.String,
+			CreatedBy:  hotel.CreatedBy.String(),
+			ChangedAt:  event.CreateAt.Time,
+		})
+	}
+
+	result := hotel_repo_mapping.FromHotelRepoToHotelDomain(hotel)
+
+	return &result, nil
+}
+
+func hotelStatusActor(ctx context.Context) string {
+	actor := utils.ActorFromContext(ctx)
+	if actor == "" {
+		actor = "system"
+	}
+
+	return actor
+}
@@ -0,0 +1,66 @@
+package hotel_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/098765432m/grpc-kafka/common/model"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestCreatedByOrAdmin(t *testing.T) {
+	creatorId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	hotel := hotel_repo.Hotel{ID: pgtype.UUID{Bytes: [16]byte{2}, Valid: true}, CreatedBy: creatorId}
+
+	tests := []struct {
+		name      string
+		requester hotel_domain.HotelRequester
+		hotel     hotel_repo.Hotel
+		want      bool
+	}{
+		{"creator of the hotel", hotel_domain.HotelRequester{UserId: creatorId.String(), Role: model.MANAGER_ROLE}, hotel, true},
+		{"admin", hotel_domain.HotelRequester{UserId: "admin-1", Role: model.ADMIN_ROLE}, hotel, true},
+		// Managing a hotel does not make a user its creator
+		{"manager of the hotel", hotel_domain.HotelRequester{UserId: "user-2", Role: model.MANAGER_ROLE, HotelId: hotel.ID.String()}, hotel, false},
+		{"other user", hotel_domain.HotelRequester{UserId: "user-3", Role: model.MANAGER_ROLE}, hotel, false},
+		{"anonymous", hotel_domain.HotelRequester{}, hotel, false},
+		{"anonymous and hotel without creator", hotel_domain.HotelRequester{}, hotel_repo.Hotel{ID: hotel.ID}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := createdByOrAdmin(tt.requester)(tt.hotel); got != tt.want {
+				t.Errorf("createdByOrAdmin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewHotelRequiresAdmin(t *testing.T) {
+	hotelId := pgtype.UUID{Bytes: [16]byte{2}, Valid: true}
+
+	tests := []struct {
+		name      string
+		requester hotel_domain.HotelRequester
+	}{
+		{"manager", hotel_domain.HotelRequester{UserId: "user-2", Role: model.MANAGER_ROLE, HotelId: hotelId.String()}},
+		{"chain manager", hotel_domain.HotelRequester{UserId: "user-4", Role: model.CHAIN_MANAGER_ROLE, ChainId: "chain-1"}},
+		{"anonymous", hotel_domain.HotelRequester{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &HotelService{}
+
+			if _, err := hs.ApproveHotel(context.Background(), hotelId, "ok", tt.requester); !errors.Is(err, ErrHotelReviewNotAllowed) {
+				t.Errorf("ApproveHotel() error = %v, want %v", err, ErrHotelReviewNotAllowed)
+			}
+			if _, err := hs.RejectHotel(context.Background(), hotelId, "missing photos", tt.requester); !errors.Is(err, ErrHotelReviewNotAllowed) {
+				t.Errorf("RejectHotel() error = %v, want %v", err, ErrHotelReviewNotAllowed)
+			}
+		})
+	}
+}
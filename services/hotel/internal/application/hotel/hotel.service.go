@@ -3,39 +3,86 @@ package hotel_service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 
 	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/booking_pb"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/image_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/pagination_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
 	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_infrastructure "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure"
 	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
 	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
+const hotelColumns = `id, name, address, latitude, longitude, city, description, status, created_by, chain_id`
+
+var hotelSortColumns = map[string]utils.SortColumn{
+	"name": {Column: "name", Cast: "text"},
+}
+
+// Best match first, hotels of the same relevance by name
+var hotelsByAddressSortColumns = map[string]utils.SortColumn{
+	"relevance": {Column: "relevance", Cast: "real", DefaultDesc: true},
+	"name":      {Column: "name", Cast: "text"},
+}
+
 type HotelService struct {
-	repo        *hotel_repo.Queries
-	imageClient image_pb.ImageServiceClient
+	conn          *pgxpool.Pool
+	repo          *hotel_repo.Queries
+	imageClient   image_pb.ImageServiceClient
+	bookingClient booking_pb.BookingServiceClient
+	geocoder      hotel_domain.Geocoder
+	producer      *hotel_infrastructure.KafkaProducer
 }
 
-func NewHotelService(repo *hotel_repo.Queries, imageClient image_pb.ImageServiceClient) *HotelService {
+func NewHotelService(conn *pgxpool.Pool, repo *hotel_repo.Queries, imageClient image_pb.ImageServiceClient, bookingClient booking_pb.BookingServiceClient, geocoder hotel_domain.Geocoder, producer *hotel_infrastructure.KafkaProducer) *HotelService {
 	return &HotelService{
-		repo:        repo,
-		imageClient: imageClient,
+		conn:          conn,
+		repo:          repo,
+		imageClient:   imageClient,
+		bookingClient: bookingClient,
+		geocoder:      geocoder,
+		producer:      producer,
 	}
 }
 
-func (hs *HotelService) GetAll(ctx context.Context) ([]hotel_domain.Hotel, error) {
-	hotels, err := hs.repo.GetAll(ctx)
+// Hotels in the publication status, PUBLISHED when status is empty.
+// ErrBadRequest when the status or the page is invalid
+func (hs *HotelService) GetAll(ctx context.Context, status string, pageParams utils.PageParams) ([]hotel_domain.Hotel, *utils.PageInfo, error) {
+	if status == "" {
+		status = hotel_domain.HOTEL_STATUS_PUBLISHED
+	}
+	if !slices.Contains(hotelStatuses, hotel_repo.HotelStatus(status)) {
+		zap.S().Infoln("Invalid hotel status: ", status)
+		return nil, nil, common_error.ErrBadRequest
+	}
+
+	page, err := utils.NewPage(pageParams, hotelSortColumns, "name", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of hotels: ", pageParams)
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	conditions.Add("status = $%d::hotel_status", status)
+
+	hotels, pageInfo, err := utils.QueryPage(ctx, hs.conn, page, hotelColumns, "hotels", conditions, scanHotel,
+		func(h hotel_repo.Hotel) (any, pgtype.UUID) { return h.Name, h.ID })
 	if err != nil {
 		zap.S().Error("Failed to get all hotels: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	result := hotel_repo_mapping.FromHotelsRepoToHotelsDomain(hotels)
 
-	return result, nil
+	return result, pageInfo, nil
 }
 
 func (hs *HotelService) GetHotelById(ctx context.Context, id pgtype.UUID) (*hotel_domain.Hotel, error) {
@@ -55,70 +102,240 @@ func (hs *HotelService) GetHotelById(ctx context.Context, id pgtype.UUID) (*hote
 	return &result, nil
 }
 
-func (hs *HotelService) CreateHotel(ctx context.Context, newHotel *hotel_repo.CreateHotelParams) error {
-	err := hs.repo.CreateHotel(ctx, hotel_repo.CreateHotelParams{
-		Name:    newHotel.Name,
-		Address: newHotel.Address,
+// Hotel as shown to requester, an unpublished hotel is ErrNoRows for the users who cannot manage it
+func (hs *HotelService) GetVisibleHotelById(ctx context.Context, id pgtype.UUID, requester hotel_domain.HotelRequester) (*hotel_domain.Hotel, error) {
+
+	hotel, err := hs.GetHotelById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !requester.CanView(*hotel) {
+		zap.S().Infoln("Hotel is not published for the requester")
+		return nil, common_error.ErrNoRows
+	}
+
+	return hotel, nil
+}
+
+// Whether requester can manage the hotel, ErrNoRows when it does not exist
+func (hs *HotelService) CanManageHotel(ctx context.Context, id pgtype.UUID, requester hotel_domain.HotelRequester) (bool, error) {
+
+	hotel, err := hs.GetHotelById(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	return requester.CanManage(*hotel), nil
+}
+
+// New hotels are drafts of CreatedBy, address is geocoded when no coordinates are given
+func (hs *HotelService) CreateHotel(ctx context.Context, newHotel *hotel_repo.CreateHotelParams) (*hotel_domain.Hotel, error) {
+	latitude, longitude := hs.resolveLocation(ctx, nil, newHotel.Address, newHotel.City, newHotel.Latitude, newHotel.Longitude)
+
+	hotel, err := hs.repo.CreateHotel(ctx, hotel_repo.CreateHotelParams{
+		Name:        newHotel.Name,
+		Address:     newHotel.Address,
+		City:        newHotel.City,
+		Description: newHotel.Description,
+		Latitude:    latitude,
+		Longitude:   longitude,
+		CreatedBy:   newHotel.CreatedBy,
 	})
 	if err != nil {
 		zap.S().Error("Failed to create hotel: ", err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	result := hotel_repo_mapping.FromHotelRepoToHotelDomain(hotel)
+
+	return &result, nil
 }
 
-func (hs *HotelService) GetHotelsByAddress(ctx context.Context, address pgtype.Text, hotelName pgtype.Text) ([]pgtype.UUID, error) {
-	hotelIds, err := hs.repo.GetHotelsByAddress(ctx, hotel_repo.GetHotelsByAddressParams{
-		Address:   address,
-		HotelName: hotelName,
-	})
+// Published hotels only, words of the address are looked up in address and city, words of the name in the name only.
+// ErrBadRequest when the page is invalid
+func (hs *HotelService) GetHotelsByAddress(ctx context.Context, address pgtype.Text, hotelName pgtype.Text, pageParams utils.PageParams) ([]pgtype.UUID, *utils.PageInfo, error) {
+	page, err := utils.NewPage(pageParams, hotelsByAddressSortColumns, "relevance", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of hotels by address: ", pageParams)
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	addressQuery := conditions.Arg(toPrefixTsQuery(address.String, ADDRESS_SEARCH_WEIGHT))
+	nameQuery := conditions.Arg(toPrefixTsQuery(hotelName.String, NAME_SEARCH_WEIGHT))
+
+	// Relevance is computed once per hotel so pages can continue after it
+	matched := fmt.Sprintf(`(
+		SELECT
+			h.id,
+			h.name,
+			(COALESCE(ts_rank(d.search_vector, to_tsquery('simple', immutable_unaccent(%[1]s::text))), 0)
+			+ COALESCE(ts_rank(d.search_vector, to_tsquery('simple', immutable_unaccent(%[2]s::text))), 0))::real AS relevance
+		FROM hotels h JOIN hotel_search_documents d ON d.hotel_id = h.id
+		WHERE
+			h.status = 'PUBLISHED'
+			AND (%[1]s::text IS NULL OR d.search_vector @@ to_tsquery('simple', immutable_unaccent(%[1]s::text)))
+			AND (%[2]s::text IS NULL OR d.search_vector @@ to_tsquery('simple', immutable_unaccent(%[2]s::text)))
+	) AS matched`, addressQuery, nameQuery)
+
+	type matchedHotel struct {
+		id        pgtype.UUID
+		name      string
+		relevance float32
+	}
+
+	hotels, pageInfo, err := utils.QueryPage(ctx, hs.conn, page, "id, name, relevance", matched, conditions,
+		func(rows pgx.Rows) (matchedHotel, error) {
+			var h matchedHotel
+			err := rows.Scan(&h.id, &h.name, &h.relevance)
+			return h, err
+		},
+		func(h matchedHotel) (any, pgtype.UUID) {
+			if page.SortBy == "name" {
+				return h.name, h.id
+			}
+			return h.relevance, h.id
+		})
 	if err != nil {
 		zap.S().Error("Failed to get Hotels By Address: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
-	return hotelIds, nil
+	hotelIds := make([]pgtype.UUID, 0, len(hotels))
+	for _, h := range hotels {
+		hotelIds = append(hotelIds, h.id)
+	}
+
+	return hotelIds, pageInfo, nil
 }
 
-func (hs *HotelService) FilterHotels(ctx context.Context, roomTypeIds []pgtype.UUID, minPrice pgtype.Int4, maxPrice pgtype.Int4) ([]hotel_repo.FilterHotelsRow, error) {
-	result, err := hs.repo.FilterHotels(ctx, hotel_repo.FilterHotelsParams{
-		RoomTypeIds: roomTypeIds,
-		MinPrice:    minPrice,
-		MaxPrice:    maxPrice,
+// Without coordinates the stored location is kept, address is geocoded again only when it or the city changed
+func (hs *HotelService) UpdateHotelById(ctx context.Context, hotelParam *hotel_repo.UpdateHotelByIdParams) (*hotel_domain.Hotel, error) {
+	current, err := hs.repo.GetHotelById(ctx, hotelParam.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to get hotel to update: ", err)
+		return nil, err
+	}
+
+	latitude, longitude := hs.resolveLocation(ctx, &current, hotelParam.Address, hotelParam.City, hotelParam.Latitude, hotelParam.Longitude)
+
+	hotel, err := hs.repo.UpdateHotelById(ctx, hotel_repo.UpdateHotelByIdParams{
+		ID:          hotelParam.ID,
+		Name:        hotelParam.Name,
+		Address:     hotelParam.Address,
+		City:        hotelParam.City,
+		Description: hotelParam.Description,
+		Latitude:    latitude,
+		Longitude:   longitude,
 	})
-	zap.S().Infoln("Filter service")
-	zap.S().Infoln(result)
 	if err != nil {
-		zap.S().Errorln("Failed to Filter Hotels: ", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to update hotel: ", err)
 		return nil, err
 	}
 
-	return result, nil
+	result := hotel_repo_mapping.FromHotelRepoToHotelDomain(hotel)
+
+	return &result, nil
 }
 
-func (hs *HotelService) UpdateHotelById(ctx context.Context, hotelParam *hotel_repo.UpdateHotelByIdParams) error {
+// Upcoming bookings and images of the hotel are deleted first so a failure keeps the hotel and can be retried
+func (hs *HotelService) DeleteHotelById(ctx context.Context, id pgtype.UUID) error {
+
+	if _, err := hs.GetHotelById(ctx, id); err != nil {
+		return err
+	}
 
-	err := hs.repo.UpdateHotelById(ctx, hotel_repo.UpdateHotelByIdParams{
-		ID:      hotelParam.ID,
-		Name:    hotelParam.Name,
-		Address: hotelParam.Address,
+	// Forward the caller so the booking history shows who deleted the hotel
+	_, err := hs.bookingClient.DeleteBookingsByHotelId(utils.WithActor(ctx, utils.ActorFromContext(ctx)), &booking_pb.DeleteBookingsByHotelIdRequest{
+		HotelId: id.String(),
+		Reason:  "hotel deleted",
 	})
+	if err != nil {
+		zap.S().Errorln("Failed to delete bookings of hotel: ", err)
+		return err
+	}
+
+	// Images go before the hotel, a failure is returned so the deletion can be retried
+	if err := hs.deleteHotelImages(ctx, id); err != nil {
+		return err
+	}
 
+	deleted, err := hs.repo.DeleteHotelById(ctx, id)
 	if err != nil {
+		zap.S().Errorln("Failed to delete hotel: ", err)
 		return err
 	}
 
+	if deleted == 0 {
+		return common_error.ErrNoRows
+	}
+
 	return nil
 }
 
-func (hs *HotelService) DeleteHotelById(ctx context.Context, id pgtype.UUID) error {
+// Delete every image of the hotel being deleted
+func (hs *HotelService) deleteHotelImages(ctx context.Context, id pgtype.UUID) error {
 
-	err := hs.repo.DeleteHotelById(ctx, id)
-	if err != nil {
-		zap.S().Errorln("Failed to delete hotel")
+	imageIds := []string{}
+	for cursor := ""; ; {
+		images, err := hs.imageClient.GetImagesByHotelId(ctx, &image_pb.GetImagesByHotelIdRequest{
+			HotelId: id.String(),
+			Page: &pagination_pb.PageRequest{
+				PageSize: utils.MAX_PAGE_SIZE,
+				Cursor:   cursor,
+			},
+		})
+		if err != nil {
+			zap.S().Errorln("Failed to get images of deleted hotel: ", err)
+			return err
+		}
+
+		for _, image := range images.GetImages() {
+			imageIds = append(imageIds, image.GetId())
+		}
+
+		cursor = images.GetPage().GetNextCursor()
+		if cursor == "" {
+			break
+		}
+	}
+
+	if len(imageIds) == 0 {
+		return nil
+	}
+
+	if _, err := hs.imageClient.DeleteImagesByIds(ctx, &image_pb.DeleteImagesByIdsRequest{
+		Ids: imageIds,
+	}); err != nil {
+		zap.S().Errorln("Failed to delete images of deleted hotel: ", err)
 		return err
 	}
 
 	return nil
 }
+
+func scanHotel(rows pgx.Rows) (hotel_repo.Hotel, error) {
+	var h hotel_repo.Hotel
+	err := rows.Scan(
+		&h.ID,
+		&h.Name,
+		&h.Address,
+		&h.Latitude,
+		&h.Longitude,
+		&h.City,
+		&h.Description,
+		&h.Status,
+		&h.CreatedBy,
+		&h.ChainID,
+	)
+	return h, err
+}
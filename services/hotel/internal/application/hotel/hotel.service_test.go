@@ -0,0 +1,86 @@
+package hotel_service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/098765432m/grpc-kafka/common/gen-proto/image_pb"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/pagination_pb"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc"
+)
+
+// Image client returning the pages of images in order, any other call panics
+type fakeImageClient struct {
+	image_pb.ImageServiceClient
+	pages      [][]string
+	getErr     error
+	deleteErr  error
+	deletedIds []string
+}
+
+func (fc *fakeImageClient) GetImagesByHotelId(ctx context.Context, in *image_pb.GetImagesByHotelIdRequest, opts ...grpc.CallOption) (*image_pb.GetImagesByHotelIdResponse, error) {
+	if fc.getErr != nil {
+		return nil, fc.getErr
+	}
+
+	page := 0
+	if in.GetPage().GetCursor() != "" {
+		page, _ = strconv.Atoi(in.GetPage().GetCursor())
+	}
+
+	result := &image_pb.GetImagesByHotelIdResponse{Page: &pagination_pb.PageResponse{}}
+	if page < len(fc.pages) {
+		for _, id := range fc.pages[page] {
+			result.Images = append(result.Images, &image_pb.HotelImage{Id: id})
+		}
+	}
+	if page+1 < len(fc.pages) {
+		result.Page.NextCursor = strconv.Itoa(page + 1)
+	}
+
+	return result, nil
+}
+
+func (fc *fakeImageClient) DeleteImagesByIds(ctx context.Context, in *image_pb.DeleteImagesByIdsRequest, opts ...grpc.CallOption) (*image_pb.DeleteImagesByIdsResponse, error) {
+	if fc.deleteErr != nil {
+		return nil, fc.deleteErr
+	}
+
+	fc.deletedIds = append(fc.deletedIds, in.GetIds()...)
+	return &image_pb.DeleteImagesByIdsResponse{}, nil
+}
+
+func TestDeleteHotelImages(t *testing.T) {
+	hotelId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	errImage := errors.New("image service down")
+
+	tests := []struct {
+		name        string
+		client      *fakeImageClient
+		wantDeleted []string
+		wantErr     error
+	}{
+		{"hotel without images", &fakeImageClient{}, nil, nil},
+		{"every page is deleted", &fakeImageClient{pages: [][]string{{"image-1", "image-2"}, {"image-3"}}}, []string{"image-1", "image-2", "image-3"}, nil},
+		// The hotel is kept so the deletion can be retried
+		{"images cannot be listed", &fakeImageClient{getErr: errImage}, nil, errImage},
+		{"images cannot be deleted", &fakeImageClient{pages: [][]string{{"image-1"}}, deleteErr: errImage}, nil, errImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := &HotelService{imageClient: tt.client}
+
+			if err := hs.deleteHotelImages(context.Background(), hotelId); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("deleteHotelImages() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(tt.client.deletedIds, tt.wantDeleted) {
+				t.Errorf("deleteHotelImages() deleted %v, want %v", tt.client.deletedIds, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package inventory_service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	room_type_service "github.com/098765432m/grpc-kafka/hotel/internal/application/room-type"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	inventory_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/inventory"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// Room types and rooms together, one import runs in one transaction
+const MAX_INVENTORY_IMPORT_ROWS = 5000
+
+var ErrInventoryImportNotAllowed = errors.New("not allowed to import inventory of the hotel")
+
+const (
+	ROW_KIND_ROOM_TYPE = "room_type"
+	ROW_KIND_ROOM      = "room"
+)
+
+var roomViews = []inventory_repo.RoomView{
+	inventory_repo.RoomViewNONE,
+	inventory_repo.RoomViewCITY,
+	inventory_repo.RoomViewSEA,
+	inventory_repo.RoomViewGARDEN,
+	inventory_repo.RoomViewMOUNTAIN,
+	inventory_repo.RoomViewPOOL,
+	inventory_repo.RoomViewRIVER,
+}
+
+var roomStatuses = []inventory_repo.RoomStatus{
+	inventory_repo.RoomStatusAVAILABLE,
+	inventory_repo.RoomStatusMAINTAINED,
+}
+
+type InventoryService struct {
+	conn *pgxpool.Pool
+	repo *inventory_repo.Queries
+}
+
+func NewInventoryService(conn *pgxpool.Pool, repo *inventory_repo.Queries) *InventoryService {
+	return &InventoryService{
+		conn: conn,
+		repo: repo,
+	}
+}
+
+// Room type of the import, matched with the hotel room types by name.
+// Row is the position in the source file, the index in the import when 0
+type InventoryRoomTypeRow struct {
+	Row            int
+	Name           string
+	Price          int
+	AreaSqm        int    // 0 when unknown
+	View           string // NONE when empty
+	SmokingAllowed bool
+	MaxOccupancy   int // DEFAULT_MAX_OCCUPANCY when 0
+	Description    string
+}
+
+// Room of the import, matched with the hotel rooms by name.
+// RoomTypeName is a room type of the import or an existing room type of the hotel
+type InventoryRoomRow struct {
+	Row          int
+	Name         string
+	RoomTypeName string
+	Status       string // AVAILABLE for a new room and unchanged for an existing one when empty
+}
+
+// Validate every row and upsert room types then rooms of the hotel in one transaction.
+// Nothing is written when a row is invalid or on dry run, the report has the row errors.
+// ErrNoRows when the hotel does not exist, ErrBadRequest when the import is empty or too large,
+// ErrInventoryImportNotAllowed when requester cannot manage the hotel
+func (is *InventoryService) ImportInventory(ctx context.Context, requester hotel_domain.HotelRequester, hotelId pgtype.UUID, dryRun bool, roomTypes []InventoryRoomTypeRow, rooms []InventoryRoomRow) (*hotel_domain.InventoryImportReport, error) {
+
+	total := len(roomTypes) + len(rooms)
+	if total == 0 || total > MAX_INVENTORY_IMPORT_ROWS {
+		zap.S().Infoln("Invalid inventory import size: ", total)
+		return nil, common_error.ErrBadRequest
+	}
+
+	hotel, err := is.repo.GetInventoryHotelById(ctx, hotelId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Hotel not found")
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to get Hotel: ", err)
+		return nil, err
+	}
+
+	if !requester.CanManage(hotel_domain.Hotel{Id: hotel.ID.String(), CreatedBy: hotel.CreatedBy.String(), ChainId: hotel.ChainID.String()}) {
+		zap.S().Infoln("User is not allowed to import inventory of Hotel: ", requester.UserId)
+		return nil, ErrInventoryImportNotAllowed
+	}
+
+	existingRoomTypes, err := is.repo.GetRoomTypeKeysByHotelId(ctx, hotelId)
+	if err != nil {
+		zap.S().Errorln("Failed to get Room Types of Hotel: ", err)
+		return nil, err
+	}
+
+	existingRoomNames, err := is.repo.GetRoomNamesByHotelId(ctx, hotelId)
+	if err != nil {
+		zap.S().Errorln("Failed to get Rooms of Hotel: ", err)
+		return nil, err
+	}
+
+	roomTypeIds := make(map[string]pgtype.UUID, len(existingRoomTypes))
+	for _, roomType := range existingRoomTypes {
+		roomTypeIds[roomType.Name] = roomType.ID
+	}
+
+	report := &hotel_domain.InventoryImportReport{
+		DryRun: dryRun,
+		Errors: []hotel_domain.InventoryRowError{},
+	}
+
+	// Rooms of an invalid room type row are not reported again
+	importedRoomTypeNames := make(map[string]bool, len(roomTypes))
+	for _, roomType := range roomTypes {
+		importedRoomTypeNames[strings.TrimSpace(roomType.Name)] = true
+	}
+
+	roomTypeParams := validateRoomTypeRows(hotelId, roomTypes, report)
+	roomParams, roomTypeNames := validateRoomRows(hotelId, rooms, importedRoomTypeNames, roomTypeIds, report)
+
+	if len(report.Errors) > 0 {
+		zap.S().Infoln("Inventory import has invalid rows: ", len(report.Errors))
+		return report, nil
+	}
+
+	if dryRun {
+		for _, name := range roomTypeParams.Names {
+			if _, ok := roomTypeIds[name]; ok {
+				report.RoomTypesUpdated++
+			} else {
+				report.RoomTypesCreated++
+			}
+		}
+		existingRooms := make(map[string]bool, len(existingRoomNames))
+		for _, name := range existingRoomNames {
+			existingRooms[name] = true
+		}
+		for _, name := range roomParams.Names {
+			if existingRooms[name] {
+				report.RoomsUpdated++
+			} else {
+				report.RoomsCreated++
+			}
+		}
+
+		return report, nil
+	}
+
+	tx, err := is.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin inventory import transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := is.repo.WithTx(tx)
+
+	if len(roomTypeParams.Names) > 0 {
+		upserted, err := qtx.UpsertRoomTypes(ctx, *roomTypeParams)
+		if err != nil {
+			zap.S().Errorln("Failed to upsert Room Types: ", err)
+			return nil, err
+		}
+
+		for _, roomType := range upserted {
+			roomTypeIds[roomType.Name] = roomType.ID
+			if roomType.Inserted {
+				report.RoomTypesCreated++
+			} else {
+				report.RoomTypesUpdated++
+			}
+		}
+	}
+
+	if len(roomParams.Names) > 0 {
+		// Room types of the import only have an id once upserted
+		for _, name := range roomTypeNames {
+			roomParams.RoomTypeIds = append(roomParams.RoomTypeIds, roomTypeIds[name])
+		}
+
+		upserted, err := qtx.UpsertRooms(ctx, *roomParams)
+		if err != nil {
+			zap.S().Errorln("Failed to upsert Rooms: ", err)
+			return nil, err
+		}
+
+		for _, inserted := range upserted {
+			if inserted {
+				report.RoomsCreated++
+			} else {
+				report.RoomsUpdated++
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit inventory import transaction: ", err)
+		return nil, err
+	}
+
+	report.Applied = true
+	return report, nil
+}
+
+func validateRoomTypeRows(hotelId pgtype.UUID, rows []InventoryRoomTypeRow, report *hotel_domain.InventoryImportReport) *inventory_repo.UpsertRoomTypesParams {
+
+	// Non nil slices, unnest of a nil array is NULL
+	params := &inventory_repo.UpsertRoomTypesParams{
+		HotelID:        hotelId,
+		Names:          make([]string, 0, len(rows)),
+		Prices:         make([]int32, 0, len(rows)),
+		AreaSqms:       make([]int32, 0, len(rows)),
+		Views:          make([]string, 0, len(rows)),
+		SmokingAllowed: make([]bool, 0, len(rows)),
+		MaxOccupancies: make([]int32, 0, len(rows)),
+		Descriptions:   make([]string, 0, len(rows)),
+	}
+	seen := make(map[string]bool, len(rows))
+
+	for i, r := range rows {
+		row := r.Row
+		if row == 0 {
+			row = i + 1
+		}
+		errorCount := len(report.Errors)
+
+		name := strings.TrimSpace(r.Name)
+		switch {
+		case name == "" || len([]rune(name)) > 255:
+			addRowError(report, ROW_KIND_ROOM_TYPE, row, "name", "Ten loai phong khong hop le")
+		case seen[name]:
+			addRowError(report, ROW_KIND_ROOM_TYPE, row, "name", "Ten loai phong bi trung trong file")
+		}
+		seen[name] = true
+
+		if r.Price <= 0 {
+			addRowError(report, ROW_KIND_ROOM_TYPE, row, "price", "Gia loai phong phai lon hon 0")
+		}
+
+		if r.AreaSqm < 0 {
+			addRowError(report, ROW_KIND_ROOM_TYPE, row, "area_sqm", "Dien tich khong hop le")
+		}
+
+		view := inventory_repo.RoomViewNONE
+		if strings.TrimSpace(r.View) != "" {
+			view = inventory_repo.RoomView(strings.ToUpper(strings.TrimSpace(r.View)))
+			if !slices.Contains(roomViews, view) {
+				addRowError(report, ROW_KIND_ROOM_TYPE, row, "view", "Huong nhin khong hop le")
+			}
+		}
+
+		maxOccupancy := r.MaxOccupancy
+		if maxOccupancy == 0 {
+			maxOccupancy = room_type_service.DEFAULT_MAX_OCCUPANCY
+		}
+		if maxOccupancy < 0 {
+			addRowError(report, ROW_KIND_ROOM_TYPE, row, "max_occupancy", "So khach toi da khong hop le")
+		}
+
+		if len(report.Errors) > errorCount {
+			continue
+		}
+
+		params.Names = append(params.Names, name)
+		params.Prices = append(params.Prices, int32(r.Price))
+		params.AreaSqms = append(params.AreaSqms, int32(r.AreaSqm))
+		params.Views = append(params.Views, string(view))
+		params.SmokingAllowed = append(params.SmokingAllowed, r.SmokingAllowed)
+		params.MaxOccupancies = append(params.MaxOccupancies, int32(maxOccupancy))
+		params.Descriptions = append(params.Descriptions, strings.TrimSpace(r.Description))
+	}
+
+	return params
+}
+
+// Rooms to upsert with the room type name of each room, ids are resolved after the room types are upserted
+func validateRoomRows(hotelId pgtype.UUID, rows []InventoryRoomRow, importedRoomTypeNames map[string]bool, existingRoomTypeIds map[string]pgtype.UUID, report *hotel_domain.InventoryImportReport) (*inventory_repo.UpsertRoomsParams, []string) {
+
+	params := &inventory_repo.UpsertRoomsParams{
+		HotelID:     hotelId,
+		Names:       make([]string, 0, len(rows)),
+		RoomTypeIds: make([]pgtype.UUID, 0, len(rows)),
+		Statuses:    make([]string, 0, len(rows)),
+	}
+	roomTypeNames := make([]string, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+
+	for i, r := range rows {
+		row := r.Row
+		if row == 0 {
+			row = i + 1
+		}
+		errorCount := len(report.Errors)
+
+		name := strings.TrimSpace(r.Name)
+		switch {
+		case name == "" || len([]rune(name)) > 255:
+			addRowError(report, ROW_KIND_ROOM, row, "name", "Ten phong khong hop le")
+		case seen[name]:
+			addRowError(report, ROW_KIND_ROOM, row, "name", "Ten phong bi trung trong file")
+		}
+		seen[name] = true
+
+		roomTypeName := strings.TrimSpace(r.RoomTypeName)
+		if _, ok := existingRoomTypeIds[roomTypeName]; !ok && !importedRoomTypeNames[roomTypeName] {
+			addRowError(report, ROW_KIND_ROOM, row, "room_type", "Loai phong khong ton tai")
+		}
+
+		// Empty status is resolved by the upsert
+		roomStatus := inventory_repo.RoomStatus("")
+		if strings.TrimSpace(r.Status) != "" {
+			roomStatus = inventory_repo.RoomStatus(strings.ToUpper(strings.TrimSpace(r.Status)))
+			if !slices.Contains(roomStatuses, roomStatus) {
+				addRowError(report, ROW_KIND_ROOM, row, "status", "Trang thai phong khong hop le")
+			}
+		}
+
+		if len(report.Errors) > errorCount {
+			continue
+		}
+
+		params.Names = append(params.Names, name)
+		params.Statuses = append(params.Statuses, string(roomStatus))
+		roomTypeNames = append(roomTypeNames, roomTypeName)
+	}
+
+	return params, roomTypeNames
+}
+
+func addRowError(report *hotel_domain.InventoryImportReport, kind string, row int, field string, message string) {
+	report.Errors = append(report.Errors, hotel_domain.InventoryRowError{
+		Kind:    kind,
+		Row:     row,
+		Field:   field,
+		Message: message,
+	})
+}
@@ -0,0 +1,149 @@
+package inventory_service
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Fields of the row errors, in report order
+func rowErrorFields(report *hotel_domain.InventoryImportReport) []string {
+	fields := make([]string, 0, len(report.Errors))
+	for _, rowError := range report.Errors {
+		fields = append(fields, rowError.Field)
+	}
+
+	return fields
+}
+
+func TestValidateRoomTypeRows(t *testing.T) {
+	tests := []struct {
+		name          string
+		rows          []InventoryRoomTypeRow
+		wantNames     []string
+		wantViews     []string
+		wantOccupancy []int32
+		wantErrors    []string
+		wantErrorRows []int
+	}{
+		{
+			name: "defaults are filled",
+			rows: []InventoryRoomTypeRow{
+				{Name: " Deluxe ", Price: 100, View: "sea", MaxOccupancy: 3},
+				{Name: "Standard", Price: 50},
+			},
+			wantNames:     []string{"Deluxe", "Standard"},
+			wantViews:     []string{"SEA", "NONE"},
+			wantOccupancy: []int32{3, 2},
+			wantErrors:    []string{},
+			wantErrorRows: []int{},
+		},
+		{
+			name: "every invalid field is reported",
+			rows: []InventoryRoomTypeRow{
+				{Row: 5, Name: "", Price: 0, AreaSqm: -1, View: "moon", MaxOccupancy: -2},
+			},
+			wantNames:     []string{},
+			wantViews:     []string{},
+			wantOccupancy: []int32{},
+			wantErrors:    []string{"name", "price", "area_sqm", "view", "max_occupancy"},
+			wantErrorRows: []int{5, 5, 5, 5, 5},
+		},
+		{
+			name: "duplicate and too long names",
+			rows: []InventoryRoomTypeRow{
+				{Name: "Deluxe", Price: 100},
+				{Name: "Deluxe ", Price: 100},
+				{Name: strings.Repeat("a", 256), Price: 100},
+			},
+			wantNames:     []string{"Deluxe"},
+			wantViews:     []string{"NONE"},
+			wantOccupancy: []int32{2},
+			wantErrors:    []string{"name", "name"},
+			wantErrorRows: []int{2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &hotel_domain.InventoryImportReport{}
+			params := validateRoomTypeRows(pgtype.UUID{}, tt.rows, report)
+
+			if !slices.Equal(params.Names, tt.wantNames) || !slices.Equal(params.Views, tt.wantViews) || !slices.Equal(params.MaxOccupancies, tt.wantOccupancy) {
+				t.Errorf("validateRoomTypeRows() = %v %v %v, want %v %v %v", params.Names, params.Views, params.MaxOccupancies, tt.wantNames, tt.wantViews, tt.wantOccupancy)
+			}
+
+			if got := rowErrorFields(report); !slices.Equal(got, tt.wantErrors) {
+				t.Errorf("validateRoomTypeRows() errors = %v, want %v", got, tt.wantErrors)
+			}
+			errorRows := make([]int, 0, len(report.Errors))
+			for _, rowError := range report.Errors {
+				if rowError.Kind != ROW_KIND_ROOM_TYPE {
+					t.Errorf("error %+v is not a room type error", rowError)
+				}
+				errorRows = append(errorRows, rowError.Row)
+			}
+			if !slices.Equal(errorRows, tt.wantErrorRows) {
+				t.Errorf("validateRoomTypeRows() error rows = %v, want %v", errorRows, tt.wantErrorRows)
+			}
+		})
+	}
+}
+
+func TestValidateRoomRows(t *testing.T) {
+	existingRoomTypeIds := map[string]pgtype.UUID{"Standard": {Valid: true}}
+	importedRoomTypeNames := map[string]bool{"Deluxe": true}
+
+	tests := []struct {
+		name              string
+		rows              []InventoryRoomRow
+		wantNames         []string
+		wantStatuses      []string
+		wantRoomTypeNames []string
+		wantErrors        []string
+	}{
+		{
+			name: "room types of the import and of the hotel",
+			rows: []InventoryRoomRow{
+				{Name: "101", RoomTypeName: "Deluxe", Status: "maintained"},
+				{Name: " 102 ", RoomTypeName: " Standard "},
+			},
+			wantNames:         []string{"101", "102"},
+			wantStatuses:      []string{"MAINTAINED", ""},
+			wantRoomTypeNames: []string{"Deluxe", "Standard"},
+			wantErrors:        []string{},
+		},
+		{
+			name: "invalid rows are left out",
+			rows: []InventoryRoomRow{
+				{Name: "101", RoomTypeName: "Suite"},
+				{Name: "102", RoomTypeName: "Deluxe", Status: "BROKEN"},
+				{Name: "", RoomTypeName: "Deluxe"},
+				{Name: "103", RoomTypeName: "Deluxe"},
+				{Name: "103", RoomTypeName: "Deluxe"},
+			},
+			wantNames:         []string{"103"},
+			wantStatuses:      []string{""},
+			wantRoomTypeNames: []string{"Deluxe"},
+			wantErrors:        []string{"room_type", "status", "name", "name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &hotel_domain.InventoryImportReport{}
+			params, roomTypeNames := validateRoomRows(pgtype.UUID{}, tt.rows, importedRoomTypeNames, existingRoomTypeIds, report)
+
+			if !slices.Equal(params.Names, tt.wantNames) || !slices.Equal(params.Statuses, tt.wantStatuses) || !slices.Equal(roomTypeNames, tt.wantRoomTypeNames) {
+				t.Errorf("validateRoomRows() = %v %v %v, want %v %v %v", params.Names, params.Statuses, roomTypeNames, tt.wantNames, tt.wantStatuses, tt.wantRoomTypeNames)
+			}
+
+			if got := rowErrorFields(report); !slices.Equal(got, tt.wantErrors) {
+				t.Errorf("validateRoomRows() errors = %v, want %v", got, tt.wantErrors)
+			}
+		})
+	}
+}
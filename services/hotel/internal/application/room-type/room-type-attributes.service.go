@@ -0,0 +1,212 @@
+package room_type_service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
+	room_type_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/room-type"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const DEFAULT_MAX_OCCUPANCY = 2
+
+var roomViews = []room_type_repo.RoomView{
+	room_type_repo.RoomViewNONE,
+	room_type_repo.RoomViewCITY,
+	room_type_repo.RoomViewSEA,
+	room_type_repo.RoomViewGARDEN,
+	room_type_repo.RoomViewMOUNTAIN,
+	room_type_repo.RoomViewPOOL,
+	room_type_repo.RoomViewRIVER,
+}
+
+var bedTypes = []room_type_repo.BedType{
+	room_type_repo.BedTypeSINGLE,
+	room_type_repo.BedTypeDOUBLE,
+	room_type_repo.BedTypeQUEEN,
+	room_type_repo.BedTypeKING,
+	room_type_repo.BedTypeSOFABED,
+	room_type_repo.BedTypeBUNK,
+}
+
+// Room type to create or update, Beds replace all beds of the room type
+type RoomTypeParams struct {
+	Name           string
+	Price          int
+	AreaSqm        int    // 0 when unknown
+	View           string // NONE when empty
+	SmokingAllowed bool
+	MaxOccupancy   int // DEFAULT_MAX_OCCUPANCY when 0
+	Description    string
+	Beds           []hotel_domain.RoomTypeBed
+}
+
+type roomTypeAttributes struct {
+	name         string
+	areaSqm      pgtype.Int4
+	view         room_type_repo.RoomView
+	maxOccupancy int32
+	description  pgtype.Text
+}
+
+// ErrNoRows when the room type is not one of the hotel, ErrDuplicateRecord when another room type has the name
+func (rts *RoomTypeService) UpdateRoomTypeById(ctx context.Context, hotelId pgtype.UUID, id pgtype.UUID, params *RoomTypeParams) (*hotel_domain.RoomType, error) {
+
+	attributes, err := validateRoomType(params)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := rts.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin update room type transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := rts.repo.WithTx(tx)
+
+	_, err = qtx.UpdateRoomTypeById(ctx, room_type_repo.UpdateRoomTypeByIdParams{
+		Name:           attributes.name,
+		Price:          int32(params.Price),
+		AreaSqm:        attributes.areaSqm,
+		View:           attributes.view,
+		SmokingAllowed: params.SmokingAllowed,
+		MaxOccupancy:   attributes.maxOccupancy,
+		Description:    attributes.description,
+		ID:             id,
+		HotelID:        hotelId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Room Type not found in hotel")
+			return nil, common_error.ErrNoRows
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Info("Duplicated Room Type name: ", attributes.name)
+			return nil, common_error.ErrDuplicateRecord
+		}
+
+		zap.S().Errorln("Failed to update Room Type: ", err)
+		return nil, err
+	}
+
+	if err := setRoomTypeBeds(ctx, qtx, id, params.Beds); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit update room type transaction: ", err)
+		return nil, err
+	}
+
+	return rts.GetRoomTypeById(ctx, id)
+}
+
+func (rts *RoomTypeService) withBeds(ctx context.Context, roomTypes []hotel_domain.RoomType) ([]hotel_domain.RoomType, error) {
+
+	roomTypeIds := make([]pgtype.UUID, 0, len(roomTypes))
+	for _, roomType := range roomTypes {
+		var roomTypeId pgtype.UUID
+		if err := roomTypeId.Scan(roomType.Id); err != nil {
+			return nil, err
+		}
+		roomTypeIds = append(roomTypeIds, roomTypeId)
+	}
+
+	beds, err := rts.repo.GetRoomTypeBedsByRoomTypeIds(ctx, roomTypeIds)
+	if err != nil {
+		zap.S().Errorln("Failed to get Room Type beds: ", err)
+		return nil, err
+	}
+
+	bedsByRoomTypeId := hotel_repo_mapping.FromRoomTypeBedsRepoToRoomTypeBedsDomain(beds)
+	for i := range roomTypes {
+		if roomTypeBeds, ok := bedsByRoomTypeId[roomTypes[i].Id]; ok {
+			roomTypes[i].Beds = roomTypeBeds
+		}
+	}
+
+	return roomTypes, nil
+}
+
+func setRoomTypeBeds(ctx context.Context, qtx *room_type_repo.Queries, roomTypeId pgtype.UUID, beds []hotel_domain.RoomTypeBed) error {
+
+	// Non nil slices, a nil array would remove nothing
+	types := make([]string, 0, len(beds))
+	quantities := make([]int32, 0, len(beds))
+	for _, bed := range beds {
+		types = append(types, strings.ToUpper(strings.TrimSpace(bed.BedType)))
+		quantities = append(quantities, int32(bed.Quantity))
+	}
+
+	err := qtx.SetRoomTypeBeds(ctx, room_type_repo.SetRoomTypeBedsParams{
+		RoomTypeID: roomTypeId,
+		BedTypes:   types,
+		Quantities: quantities,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to set Room Type beds: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func validateRoomType(params *RoomTypeParams) (*roomTypeAttributes, error) {
+
+	name := strings.TrimSpace(params.Name)
+	if name == "" || len([]rune(name)) > 255 || params.Price <= 0 {
+		zap.S().Infoln("Invalid Room Type name or price")
+		return nil, common_error.ErrBadRequest
+	}
+
+	if params.AreaSqm < 0 || params.MaxOccupancy < 0 {
+		zap.S().Infoln("Invalid Room Type area or occupancy")
+		return nil, common_error.ErrBadRequest
+	}
+
+	view := room_type_repo.RoomViewNONE
+	if params.View != "" {
+		view = room_type_repo.RoomView(strings.ToUpper(strings.TrimSpace(params.View)))
+		if !slices.Contains(roomViews, view) {
+			zap.S().Infoln("Invalid Room Type view: ", params.View)
+			return nil, common_error.ErrBadRequest
+		}
+	}
+
+	maxOccupancy := params.MaxOccupancy
+	if maxOccupancy == 0 {
+		maxOccupancy = DEFAULT_MAX_OCCUPANCY
+	}
+
+	seen := make([]room_type_repo.BedType, 0, len(params.Beds))
+	for _, bed := range params.Beds {
+		bedType := room_type_repo.BedType(strings.ToUpper(strings.TrimSpace(bed.BedType)))
+		if !slices.Contains(bedTypes, bedType) || bed.Quantity <= 0 || slices.Contains(seen, bedType) {
+			zap.S().Infoln("Invalid Room Type bed: ", bed.BedType)
+			return nil, common_error.ErrBadRequest
+		}
+		seen = append(seen, bedType)
+	}
+
+	description := strings.TrimSpace(params.Description)
+
+	return &roomTypeAttributes{
+		name:         name,
+		areaSqm:      pgtype.Int4{Int32: int32(params.AreaSqm), Valid: params.AreaSqm > 0},
+		view:         view,
+		maxOccupancy: int32(maxOccupancy),
+		description:  pgtype.Text{String: description, Valid: description != ""},
+	}, nil
+}
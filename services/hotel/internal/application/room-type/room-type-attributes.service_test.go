@@ -0,0 +1,85 @@
+package room_type_service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	room_type_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/room-type"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestValidateRoomType(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  RoomTypeParams
+		want    *roomTypeAttributes
+		wantErr bool
+	}{
+		{
+			name:   "defaults of a room type with name and price only",
+			params: RoomTypeParams{Name: " Deluxe ", Price: 500},
+			want:   &roomTypeAttributes{name: "Deluxe", view: room_type_repo.RoomViewNONE, maxOccupancy: DEFAULT_MAX_OCCUPANCY},
+		},
+		{
+			name: "every attribute",
+			params: RoomTypeParams{
+				Name: "Suite", Price: 900, AreaSqm: 45, View: " sea ", MaxOccupancy: 4, Description: " Ocean suite ",
+				Beds: []hotel_domain.RoomTypeBed{{BedType: "king", Quantity: 1}, {BedType: "SOFA_BED", Quantity: 1}},
+			},
+			want: &roomTypeAttributes{
+				name:         "Suite",
+				areaSqm:      pgtype.Int4{Int32: 45, Valid: true},
+				view:         room_type_repo.RoomViewSEA,
+				maxOccupancy: 4,
+				description:  pgtype.Text{String: "Ocean suite", Valid: true},
+			},
+		},
+		{name: "blank name", params: RoomTypeParams{Name: "  ", Price: 500}, wantErr: true},
+		{name: "name too long", params: RoomTypeParams{Name: strings.Repeat("a", 256), Price: 500}, wantErr: true},
+		{name: "no price", params: RoomTypeParams{Name: "Deluxe"}, wantErr: true},
+		{name: "negative area", params: RoomTypeParams{Name: "Deluxe", Price: 500, AreaSqm: -1}, wantErr: true},
+		{name: "negative occupancy", params: RoomTypeParams{Name: "Deluxe", Price: 500, MaxOccupancy: -2}, wantErr: true},
+		{name: "unknown view", params: RoomTypeParams{Name: "Deluxe", Price: 500, View: "LAKE"}, wantErr: true},
+		{name: "unknown bed", params: RoomTypeParams{Name: "Deluxe", Price: 500, Beds: []hotel_domain.RoomTypeBed{{BedType: "WATERBED", Quantity: 1}}}, wantErr: true},
+		{name: "bed without quantity", params: RoomTypeParams{Name: "Deluxe", Price: 500, Beds: []hotel_domain.RoomTypeBed{{BedType: "SINGLE"}}}, wantErr: true},
+		// Quantities of one bed type go in a single entry
+		{name: "same bed twice", params: RoomTypeParams{Name: "Deluxe", Price: 500, Beds: []hotel_domain.RoomTypeBed{{BedType: "single", Quantity: 1}, {BedType: "SINGLE", Quantity: 1}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateRoomType(&tt.params)
+			if tt.wantErr {
+				if !errors.Is(err, common_error.ErrBadRequest) {
+					t.Errorf("validateRoomType() error = %v, want %v", err, common_error.ErrBadRequest)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("validateRoomType() error = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("validateRoomType() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRoomTypeInvalid(t *testing.T) {
+	rts := &RoomTypeService{}
+	hotelId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	invalid := &RoomTypeParams{Name: "Deluxe", Price: 500, View: "LAKE"}
+
+	// Nothing is written for an invalid room type
+	if _, err := rts.CreateRoomType(context.Background(), hotelId, invalid); !errors.Is(err, common_error.ErrBadRequest) {
+		t.Errorf("CreateRoomType() error = %v, want %v", err, common_error.ErrBadRequest)
+	}
+	if _, err := rts.UpdateRoomTypeById(context.Background(), hotelId, pgtype.UUID{Bytes: [16]byte{2}, Valid: true}, invalid); !errors.Is(err, common_error.ErrBadRequest) {
+		t.Errorf("UpdateRoomTypeById() error = %v, want %v", err, common_error.ErrBadRequest)
+	}
+}
@@ -2,20 +2,43 @@ package room_type_service
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
 	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
 	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
 	room_type_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/room-type"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
+// Number of rooms is a subquery so the page count is the number of room types
+const hotelRoomTypeColumns = `rt.id, rt.name, rt.price, rt.hotel_id, rt.area_sqm, rt.view, rt.smoking_allowed, rt.max_occupancy, rt.description,
+	(SELECT COUNT(*) FROM rooms r WHERE r.room_type_id = rt.id) AS number_of_rooms`
+
+var roomTypeSortColumns = map[string]utils.SortColumn{
+	"name":  {Column: "rt.name", Cast: "text"},
+	"price": {Column: "rt.price", Cast: "int"},
+}
+
+type hotelRoomTypeRow struct {
+	roomType      room_type_repo.RoomType
+	numberOfRooms int64
+}
+
 type RoomTypeService struct {
+	conn *pgxpool.Pool
 	repo *room_type_repo.Queries
 }
 
-func NewRoomTypeService(repo *room_type_repo.Queries) *RoomTypeService {
+func NewRoomTypeService(conn *pgxpool.Pool, repo *room_type_repo.Queries) *RoomTypeService {
 	return &RoomTypeService{
+		conn: conn,
 		repo: repo,
 	}
 }
@@ -27,30 +50,169 @@ func (rts *RoomTypeService) GetRoomTypeById(ctx context.Context, id pgtype.UUID)
 		return nil, err
 	}
 
-	result := hotel_repo_mapping.FromRoomTypeRepoToRoomTypeDomain(roomType)
+	roomTypes, err := rts.withBeds(ctx, []hotel_domain.RoomType{hotel_repo_mapping.FromRoomTypeRepoToRoomTypeDomain(roomType)})
+	if err != nil {
+		return nil, err
+	}
 
-	return &result, nil
+	return &roomTypes[0], nil
 }
 
-func (rts *RoomTypeService) GetRoomTypesByHotelId(ctx context.Context, hotelId pgtype.UUID) ([]room_type_repo.GetRoomTypesByHotelIdRow, error) {
-	roomTypes, err := rts.repo.GetRoomTypesByHotelId(ctx, hotelId)
+// ErrBadRequest when the page is invalid
+func (rts *RoomTypeService) GetRoomTypesByHotelId(ctx context.Context, hotelId pgtype.UUID, pageParams utils.PageParams) ([]hotel_domain.HotelRoomType, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, roomTypeSortColumns, "name", "rt.id")
 	if err != nil {
-		zap.S().Errorln("Cannot get Room Type By id")
+		zap.S().Infoln("Invalid page of room types: ", pageParams)
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	conditions.Add("rt.hotel_id = $%d", hotelId)
+
+	rows, pageInfo, err := utils.QueryPage(ctx, rts.conn, page, hotelRoomTypeColumns, "room_types rt", conditions,
+		scanHotelRoomTypeRow,
+		func(r hotelRoomTypeRow) (any, pgtype.UUID) {
+			if page.SortBy == "price" {
+				return r.roomType.Price, r.roomType.ID
+			}
+			return r.roomType.Name, r.roomType.ID
+		})
+	if err != nil {
+		zap.S().Errorln("Cannot get Room Types by Hotel Id: ", err)
+		return nil, nil, err
+	}
+
+	roomTypes, err := rts.withHotelRoomTypeBeds(ctx, rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return roomTypes, pageInfo, nil
+}
+
+// Every room type of the hotels ordered by hotel then name, without paging
+func (rts *RoomTypeService) GetRoomTypesByHotelIds(ctx context.Context, hotelIds []pgtype.UUID) ([]hotel_domain.HotelRoomType, error) {
+
+	if len(hotelIds) == 0 {
+		return []hotel_domain.HotelRoomType{}, nil
+	}
+
+	pgRows, err := rts.conn.Query(ctx, "SELECT "+hotelRoomTypeColumns+" FROM room_types rt WHERE rt.hotel_id = ANY($1::uuid[]) ORDER BY rt.hotel_id, rt.name, rt.id", hotelIds)
+	if err != nil {
+		zap.S().Errorln("Cannot get Room Types by Hotel Ids: ", err)
+		return nil, err
+	}
+	defer pgRows.Close()
+
+	rows := []hotelRoomTypeRow{}
+	for pgRows.Next() {
+		row, err := scanHotelRoomTypeRow(pgRows)
+		if err != nil {
+			zap.S().Errorln("Cannot scan Room Type by Hotel Ids: ", err)
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	if err := pgRows.Err(); err != nil {
+		zap.S().Errorln("Cannot get Room Types by Hotel Ids: ", err)
+		return nil, err
+	}
+
+	return rts.withHotelRoomTypeBeds(ctx, rows)
+}
+
+func scanHotelRoomTypeRow(rows pgx.Rows) (hotelRoomTypeRow, error) {
+	var r hotelRoomTypeRow
+	err := rows.Scan(
+		&r.roomType.ID,
+		&r.roomType.Name,
+		&r.roomType.Price,
+		&r.roomType.HotelID,
+		&r.roomType.AreaSqm,
+		&r.roomType.View,
+		&r.roomType.SmokingAllowed,
+		&r.roomType.MaxOccupancy,
+		&r.roomType.Description,
+		&r.numberOfRooms,
+	)
+	return r, err
+}
+
+// Room types of the rows with their beds
+func (rts *RoomTypeService) withHotelRoomTypeBeds(ctx context.Context, rows []hotelRoomTypeRow) ([]hotel_domain.HotelRoomType, error) {
+	roomTypeIds := make([]pgtype.UUID, 0, len(rows))
+	for _, row := range rows {
+		roomTypeIds = append(roomTypeIds, row.roomType.ID)
+	}
+
+	beds, err := rts.repo.GetRoomTypeBedsByRoomTypeIds(ctx, roomTypeIds)
+	if err != nil {
+		zap.S().Errorln("Failed to get Room Type beds: ", err)
 		return nil, err
 	}
+	bedsByRoomTypeId := hotel_repo_mapping.FromRoomTypeBedsRepoToRoomTypeBedsDomain(beds)
+
+	roomTypes := make([]hotel_domain.HotelRoomType, 0, len(rows))
+	for _, row := range rows {
+		roomType := hotel_repo_mapping.FromHotelRoomTypeRepoToHotelRoomTypeDomain(row.roomType, row.numberOfRooms)
+		if roomTypeBeds, ok := bedsByRoomTypeId[roomType.Id]; ok {
+			roomType.Beds = roomTypeBeds
+		}
+		roomTypes = append(roomTypes, roomType)
+	}
 
 	return roomTypes, nil
 }
 
-func (rts *RoomTypeService) CreateRoomType(ctx context.Context, newRoomType *room_type_repo.CreateRoomTypeParams) error {
+// Room type is created with its beds, ErrDuplicateRecord when the hotel already has a room type of the same name
+func (rts *RoomTypeService) CreateRoomType(ctx context.Context, hotelId pgtype.UUID, params *RoomTypeParams) (*hotel_domain.RoomType, error) {
+
+	attributes, err := validateRoomType(params)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := rts.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin create room type transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := rts.repo.WithTx(tx)
 
-	err := rts.repo.CreateRoomType(ctx, *newRoomType)
+	roomType, err := qtx.CreateRoomType(ctx, room_type_repo.CreateRoomTypeParams{
+		Name:           attributes.name,
+		Price:          int32(params.Price),
+		HotelID:        hotelId,
+		AreaSqm:        attributes.areaSqm,
+		View:           attributes.view,
+		SmokingAllowed: params.SmokingAllowed,
+		MaxOccupancy:   attributes.maxOccupancy,
+		Description:    attributes.description,
+	})
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Info("Duplicated Room Type name: ", attributes.name)
+			return nil, common_error.ErrDuplicateRecord
+		}
+
 		zap.S().Errorln("Failed to create Room Type: ", err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	if err := setRoomTypeBeds(ctx, qtx, roomType.ID, params.Beds); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit create room type transaction: ", err)
+		return nil, err
+	}
+
+	return rts.GetRoomTypeById(ctx, roomType.ID)
 }
 
 func (rts *RoomTypeService) DeleteRoomTypeById(ctx context.Context, id pgtype.UUID) error {
@@ -63,3 +225,150 @@ func (rts *RoomTypeService) DeleteRoomTypeById(ctx context.Context, id pgtype.UU
 
 	return nil
 }
+
+func (rts *RoomTypeService) CreateOverbookingLimit(ctx context.Context, newLimit *room_type_repo.CreateOverbookingLimitParams) error {
+
+	created, err := rts.repo.CreateOverbookingLimit(ctx, *newLimit)
+	if err != nil {
+		zap.S().Errorln("Failed to create Overbooking Limit: ", err)
+		return err
+	}
+
+	// Room type does not belong to the hotel
+	if created == 0 {
+		zap.S().Infoln("Room Type not found in Hotel to create Overbooking Limit")
+		return common_error.ErrNoRows
+	}
+
+	return nil
+}
+
+func (rts *RoomTypeService) GetOverbookingLimitsByRoomTypeId(ctx context.Context, roomTypeId pgtype.UUID) ([]hotel_domain.OverbookingLimit, error) {
+
+	limits, err := rts.repo.GetOverbookingLimitsByRoomTypeId(ctx, roomTypeId)
+	if err != nil {
+		zap.S().Errorln("Failed to get Overbooking Limits by Room Type id: ", err)
+		return nil, err
+	}
+
+	return hotel_repo_mapping.FromOverbookingLimitsRepoToOverbookingLimitsDomain(limits), nil
+}
+
+func (rts *RoomTypeService) DeleteOverbookingLimitById(ctx context.Context, hotelId pgtype.UUID, id pgtype.UUID) error {
+
+	deleted, err := rts.repo.DeleteOverbookingLimitById(ctx, room_type_repo.DeleteOverbookingLimitByIdParams{
+		ID:      id,
+		HotelID: hotelId,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to delete Overbooking Limit by id: ", err)
+		return err
+	}
+
+	if deleted == 0 {
+		zap.S().Infoln("Overbooking Limit not found in Hotel to delete")
+		return common_error.ErrNoRows
+	}
+
+	return nil
+}
+
+// Return number of rooms that can be sold beyond the physical inventory on every night in range of time
+// A night without any limit allows no overbooking
+func (rts *RoomTypeService) GetOverbookingAllowance(ctx context.Context, roomTypeId pgtype.UUID, checkIn pgtype.Date, checkOut pgtype.Date) (int, error) {
+
+	limits, err := rts.repo.GetOverbookingLimitsInRange(ctx, room_type_repo.GetOverbookingLimitsInRangeParams{
+		RoomTypeID: roomTypeId,
+		CheckIn:    checkIn,
+		CheckOut:   checkOut,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get Overbooking Limits in range: ", err)
+		return 0, err
+	}
+
+	if len(limits) == 0 {
+		return 0, nil
+	}
+
+	totalRooms, err := rts.repo.CountRoomsByRoomTypeId(ctx, roomTypeId)
+	if err != nil {
+		zap.S().Errorln("Failed to count Rooms by Room Type id: ", err)
+		return 0, err
+	}
+
+	return overbookingAllowance(limits, int(totalRooms), checkIn.Time, checkOut.Time), nil
+}
+
+// Rooms that can be oversold on every night from checkIn to checkOut (exclusive) under the limits
+func overbookingAllowance(limits []room_type_repo.RoomTypeOverbookingLimit, totalRooms int, checkIn time.Time, checkOut time.Time) int {
+	allowance := -1
+	for night := checkIn; night.Before(checkOut); night = night.AddDate(0, 0, 1) {
+
+		// Take the most generous limit that covers this night
+		nightAllowance := 0
+		for _, limit := range limits {
+			if night.Before(limit.StartDate.Time) || !night.Before(limit.EndDate.Time) {
+				continue
+			}
+
+			if value := overbookingLimitValue(limit, totalRooms); value > nightAllowance {
+				nightAllowance = value
+			}
+		}
+
+		// The whole stay has to fit, so the tightest night wins
+		if allowance == -1 || nightAllowance < allowance {
+			allowance = nightAllowance
+		}
+	}
+
+	if allowance < 0 {
+		return 0
+	}
+
+	return allowance
+}
+
+func overbookingLimitValue(limit room_type_repo.RoomTypeOverbookingLimit, totalRooms int) int {
+	switch limit.LimitType {
+	case room_type_repo.OverbookingLimitTypePERCENT:
+		return totalRooms * int(limit.LimitValue) / 100
+	default:
+		return int(limit.LimitValue)
+	}
+}
+
+// Set position of room type in the upgrade ladder, 0 removes it from the ladder
+// ErrNoRows when the room type is not in the hotel
+func (rts *RoomTypeService) SetRoomTypeUpgradeRank(ctx context.Context, hotelId pgtype.UUID, id pgtype.UUID, upgradeRank int) error {
+
+	updated, err := rts.repo.SetRoomTypeUpgradeRank(ctx, room_type_repo.SetRoomTypeUpgradeRankParams{
+		UpgradeRank: pgtype.Int4{Int32: int32(upgradeRank), Valid: upgradeRank > 0},
+		ID:          id,
+		HotelID:     hotelId,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to set Room Type upgrade rank: ", err)
+		return err
+	}
+
+	if updated == 0 {
+		zap.S().Infoln("Room Type not found in Hotel to set upgrade rank")
+		return common_error.ErrNoRows
+	}
+
+	return nil
+}
+
+// Return higher room types of the same hotel, ordered from the nearest upgrade
+func (rts *RoomTypeService) GetUpgradeRoomTypesByRoomTypeId(ctx context.Context, roomTypeId pgtype.UUID) ([]hotel_domain.RoomType, error) {
+
+	roomTypes, err := rts.repo.GetUpgradeRoomTypesByRoomTypeId(ctx, roomTypeId)
+	if err != nil {
+		zap.S().Errorln("Failed to get upgrade Room Types: ", err)
+		return nil, err
+	}
+
+	return rts.withBeds(ctx, hotel_repo_mapping.FromRoomTypesRepoToRoomTypesDomain(roomTypes))
+}
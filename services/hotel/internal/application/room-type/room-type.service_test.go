@@ -0,0 +1,49 @@
+package room_type_service
+
+import (
+	"testing"
+	"time"
+
+	room_type_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/room-type"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestOverbookingAllowance(t *testing.T) {
+	date := func(day int) time.Time {
+		return time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC)
+	}
+	limit := func(start int, end int, limitType room_type_repo.OverbookingLimitType, value int32) room_type_repo.RoomTypeOverbookingLimit {
+		return room_type_repo.RoomTypeOverbookingLimit{
+			StartDate:  pgtype.Date{Time: date(start), Valid: true},
+			EndDate:    pgtype.Date{Time: date(end), Valid: true},
+			LimitType:  limitType,
+			LimitValue: value,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		limits     []room_type_repo.RoomTypeOverbookingLimit
+		totalRooms int
+		checkIn    int
+		checkOut   int
+		want       int
+	}{
+		{"no limit", nil, 10, 1, 3, 0},
+		{"fixed limit over the stay", []room_type_repo.RoomTypeOverbookingLimit{limit(1, 10, room_type_repo.OverbookingLimitTypeABSOLUTE, 2)}, 10, 2, 5, 2},
+		{"percent of the rooms rounds down", []room_type_repo.RoomTypeOverbookingLimit{limit(1, 10, room_type_repo.OverbookingLimitTypePERCENT, 15)}, 10, 2, 5, 1},
+		{"end date is exclusive", []room_type_repo.RoomTypeOverbookingLimit{limit(1, 3, room_type_repo.OverbookingLimitTypeABSOLUTE, 2)}, 10, 2, 4, 0},
+		{"night without limit allows none", []room_type_repo.RoomTypeOverbookingLimit{limit(1, 2, room_type_repo.OverbookingLimitTypeABSOLUTE, 2), limit(3, 5, room_type_repo.OverbookingLimitTypeABSOLUTE, 2)}, 10, 1, 4, 0},
+		{"most generous limit of a night", []room_type_repo.RoomTypeOverbookingLimit{limit(1, 10, room_type_repo.OverbookingLimitTypeABSOLUTE, 1), limit(1, 10, room_type_repo.OverbookingLimitTypePERCENT, 30)}, 10, 1, 3, 3},
+		{"tightest night of the stay", []room_type_repo.RoomTypeOverbookingLimit{limit(1, 3, room_type_repo.OverbookingLimitTypeABSOLUTE, 4), limit(3, 6, room_type_repo.OverbookingLimitTypeABSOLUTE, 1)}, 10, 1, 5, 1},
+		{"empty stay", []room_type_repo.RoomTypeOverbookingLimit{limit(1, 10, room_type_repo.OverbookingLimitTypeABSOLUTE, 2)}, 10, 3, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overbookingAllowance(tt.limits, tt.totalRooms, date(tt.checkIn), date(tt.checkOut)); got != tt.want {
+				t.Errorf("overbookingAllowance() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package room_service
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	BEST_FIT_STRATEGY         = "BEST_FIT"
+	GUEST_PREFERENCE_STRATEGY = "GUEST_PREFERENCE"
+)
+
+// Remain room that can be assigned to the new stay
+type RoomCandidate struct {
+	Id   pgtype.UUID
+	Name string
+	// Room has a booking that ends on check in / starts on check out of the new stay
+	TouchesCheckIn  bool
+	TouchesCheckOut bool
+}
+
+// Number of booked nights that touch the new stay (0, 1 or 2)
+func (rc RoomCandidate) adjacencyScore() int {
+	score := 0
+	if rc.TouchesCheckIn {
+		score++
+	}
+	if rc.TouchesCheckOut {
+		score++
+	}
+
+	return score
+}
+
+// Choose which remain rooms are assigned to a booking
+type RoomAssignmentStrategy interface {
+	AssignRooms(candidates []RoomCandidate, numberOfRooms int) []pgtype.UUID
+}
+
+// Prefer rooms whose booked nights touch the new stay, so the stay fills a gap instead of splitting a free range
+type BestFitStrategy struct{}
+
+func (BestFitStrategy) AssignRooms(candidates []RoomCandidate, numberOfRooms int) []pgtype.UUID {
+	sorted := slices.Clone(candidates)
+
+	// Stable to keep the name order of rooms with the same score
+	slices.SortStableFunc(sorted, func(a, b RoomCandidate) int {
+		return b.adjacencyScore() - a.adjacencyScore()
+	})
+
+	return takeRoomIds(sorted, numberOfRooms)
+}
+
+// Keep a group on the same floor in rooms next to each other.
+// Floor and position are read from the room name (e.g. "305" is floor 3), rooms without number are used last
+type GuestPreferenceStrategy struct{}
+
+type numberedRoom struct {
+	candidate RoomCandidate
+	floor     int
+	number    int
+}
+
+func (GuestPreferenceStrategy) AssignRooms(candidates []RoomCandidate, numberOfRooms int) []pgtype.UUID {
+	if numberOfRooms <= 1 {
+		return BestFitStrategy{}.AssignRooms(candidates, numberOfRooms)
+	}
+
+	numberedRooms := make([]numberedRoom, 0, len(candidates))
+	for _, candidate := range candidates {
+		number, ok := parseRoomNumber(candidate.Name)
+		if !ok {
+			continue
+		}
+
+		numberedRooms = append(numberedRooms, numberedRoom{
+			candidate: candidate,
+			floor:     number / 100,
+			number:    number,
+		})
+	}
+
+	slices.SortFunc(numberedRooms, func(a, b numberedRoom) int {
+		if a.floor != b.floor {
+			return a.floor - b.floor
+		}
+		return a.number - b.number
+	})
+
+	// Find the window of rooms on one floor with the smallest spread, tie break by adjacency score
+	bestStart := -1
+	bestSpread, bestScore := 0, 0
+	for start := 0; start+numberOfRooms <= len(numberedRooms); start++ {
+		first := numberedRooms[start]
+		last := numberedRooms[start+numberOfRooms-1]
+		if first.floor != last.floor {
+			continue
+		}
+
+		spread := last.number - first.number
+		score := 0
+		for _, room := range numberedRooms[start : start+numberOfRooms] {
+			score += room.candidate.adjacencyScore()
+		}
+
+		if bestStart == -1 || spread < bestSpread || (spread == bestSpread && score > bestScore) {
+			bestStart, bestSpread, bestScore = start, spread, score
+		}
+	}
+
+	// No floor has enough rooms for the group
+	if bestStart == -1 {
+		return BestFitStrategy{}.AssignRooms(candidates, numberOfRooms)
+	}
+
+	roomIds := make([]pgtype.UUID, 0, numberOfRooms)
+	for _, room := range numberedRooms[bestStart : bestStart+numberOfRooms] {
+		roomIds = append(roomIds, room.candidate.Id)
+	}
+
+	return roomIds
+}
+
+// Return the first number in room name
+func parseRoomNumber(name string) (int, bool) {
+	start := strings.IndexFunc(name, unicode.IsDigit)
+	if start == -1 {
+		return 0, false
+	}
+
+	end := start
+	for end < len(name) && unicode.IsDigit(rune(name[end])) {
+		end++
+	}
+
+	number, err := strconv.Atoi(name[start:end])
+	if err != nil {
+		return 0, false
+	}
+
+	return number, true
+}
+
+// First numberOfRooms candidates, fewer when there are not enough and none for a negative number
+func takeRoomIds(candidates []RoomCandidate, numberOfRooms int) []pgtype.UUID {
+	numberOfRooms = max(min(numberOfRooms, len(candidates)), 0)
+
+	roomIds := make([]pgtype.UUID, 0, numberOfRooms)
+	for _, candidate := range candidates[:numberOfRooms] {
+		roomIds = append(roomIds, candidate.Id)
+	}
+
+	return roomIds
+}
@@ -0,0 +1,258 @@
+package room_service
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func roomId(n int) pgtype.UUID {
+	var id pgtype.UUID
+	id.Bytes[14] = byte(n >> 8)
+	id.Bytes[15] = byte(n)
+	id.Valid = true
+	return id
+}
+
+func candidate(n int, name string, touchesCheckIn bool, touchesCheckOut bool) RoomCandidate {
+	return RoomCandidate{
+		Id:              roomId(n),
+		Name:            name,
+		TouchesCheckIn:  touchesCheckIn,
+		TouchesCheckOut: touchesCheckOut,
+	}
+}
+
+func TestBestFitStrategy(t *testing.T) {
+	candidates := []RoomCandidate{
+		candidate(1, "101", false, false),
+		candidate(2, "102", true, false),
+		candidate(3, "103", true, true),
+		candidate(4, "104", false, true),
+	}
+
+	tests := []struct {
+		name          string
+		numberOfRooms int
+		want          []pgtype.UUID
+	}{
+		{"fills the tightest gap first", 1, []pgtype.UUID{roomId(3)}},
+		{"keeps name order on equal score", 3, []pgtype.UUID{roomId(3), roomId(2), roomId(4)}},
+		{"returns every candidate when short", 10, []pgtype.UUID{roomId(3), roomId(2), roomId(4), roomId(1)}},
+		{"returns nothing for zero rooms", 0, []pgtype.UUID{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BestFitStrategy{}.AssignRooms(candidates, tt.numberOfRooms)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AssignRooms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestFitStrategyKeepsCandidates(t *testing.T) {
+	candidates := []RoomCandidate{
+		candidate(1, "101", false, false),
+		candidate(2, "102", true, true),
+	}
+	original := slices.Clone(candidates)
+
+	BestFitStrategy{}.AssignRooms(candidates, 1)
+
+	if !slices.Equal(candidates, original) {
+		t.Errorf("AssignRooms() reordered candidates to %v", candidates)
+	}
+}
+
+func TestGuestPreferenceStrategy(t *testing.T) {
+	tests := []struct {
+		name          string
+		candidates    []RoomCandidate
+		numberOfRooms int
+		want          []pgtype.UUID
+	}{
+		{
+			name: "keeps the group on one floor",
+			candidates: []RoomCandidate{
+				candidate(1, "101", false, false),
+				candidate(2, "201", false, false),
+				candidate(3, "202", false, false),
+				candidate(4, "105", false, false),
+			},
+			numberOfRooms: 2,
+			want:          []pgtype.UUID{roomId(2), roomId(3)},
+		},
+		{
+			name: "chooses the smallest spread",
+			candidates: []RoomCandidate{
+				candidate(1, "301", false, false),
+				candidate(2, "305", false, false),
+				candidate(3, "306", false, false),
+				candidate(4, "310", false, false),
+			},
+			numberOfRooms: 2,
+			want:          []pgtype.UUID{roomId(2), roomId(3)},
+		},
+		{
+			name: "breaks spread ties by adjacency",
+			candidates: []RoomCandidate{
+				candidate(1, "101", false, false),
+				candidate(2, "102", false, false),
+				candidate(3, "201", true, false),
+				candidate(4, "202", false, true),
+			},
+			numberOfRooms: 2,
+			want:          []pgtype.UUID{roomId(3), roomId(4)},
+		},
+		{
+			name: "reads the number inside the room name",
+			candidates: []RoomCandidate{
+				candidate(1, "Suite A-401", false, false),
+				candidate(2, "Suite A-402", false, false),
+				candidate(3, "Garden", false, false),
+			},
+			numberOfRooms: 2,
+			want:          []pgtype.UUID{roomId(1), roomId(2)},
+		},
+		{
+			name: "falls back to best fit when no floor is big enough",
+			candidates: []RoomCandidate{
+				candidate(1, "101", false, false),
+				candidate(2, "201", true, true),
+				candidate(3, "Garden", false, false),
+			},
+			numberOfRooms: 2,
+			want:          []pgtype.UUID{roomId(2), roomId(1)},
+		},
+		{
+			name: "uses best fit for a single room",
+			candidates: []RoomCandidate{
+				candidate(1, "101", false, false),
+				candidate(2, "102", true, false),
+			},
+			numberOfRooms: 1,
+			want:          []pgtype.UUID{roomId(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GuestPreferenceStrategy{}.AssignRooms(tt.candidates, tt.numberOfRooms)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AssignRooms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRoomNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   int
+		wantOk bool
+	}{
+		{"305", 305, true},
+		{"Room 1204", 1204, true},
+		{"B12-3", 12, true},
+		{"Penthouse", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRoomNumber(tt.name)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("parseRoomNumber(%q) = %d, %v, want %d, %v", tt.name, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestTakeRoomIds(t *testing.T) {
+	candidates := []RoomCandidate{
+		candidate(1, "101", false, false),
+		candidate(2, "102", false, false),
+		candidate(3, "103", false, false),
+	}
+
+	tests := []struct {
+		name          string
+		numberOfRooms int
+		want          []pgtype.UUID
+	}{
+		{"some of the candidates", 2, []pgtype.UUID{roomId(1), roomId(2)}},
+		{"more than the candidates", 5, []pgtype.UUID{roomId(1), roomId(2), roomId(3)}},
+		{"none", 0, []pgtype.UUID{}},
+		{"negative number", -1, []pgtype.UUID{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := takeRoomIds(candidates, tt.numberOfRooms); !slices.Equal(got, tt.want) {
+				t.Errorf("takeRoomIds(%d) = %v, want %v", tt.numberOfRooms, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterAssignmentStrategyConcurrently(t *testing.T) {
+	rs := NewRoomService(nil, nil)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			rs.RegisterAssignmentStrategy(fmt.Sprintf("CUSTOM_%d", i), BestFitStrategy{})
+		}()
+		go func() {
+			defer wg.Done()
+			if _, ok := rs.getAssignmentStrategy(BEST_FIT_STRATEGY); !ok {
+				t.Error("BEST_FIT strategy is not registered")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, ok := rs.getAssignmentStrategy("CUSTOM_49"); !ok {
+		t.Error("registered strategy is not found")
+	}
+}
+
+// Floors of 20 rooms, every third room touches a booked night
+func benchmarkCandidates(numberOfCandidates int) []RoomCandidate {
+	candidates := make([]RoomCandidate, 0, numberOfCandidates)
+	for i := range numberOfCandidates {
+		floor, position := i/20+1, i%20+1
+		candidates = append(candidates, candidate(i, fmt.Sprintf("%d%02d", floor, position), i%3 == 0, i%5 == 0))
+	}
+
+	return candidates
+}
+
+func BenchmarkBestFitStrategy(b *testing.B) {
+	for _, numberOfCandidates := range []int{10, 100, 1000} {
+		candidates := benchmarkCandidates(numberOfCandidates)
+		b.Run(fmt.Sprintf("candidates=%d", numberOfCandidates), func(b *testing.B) {
+			for b.Loop() {
+				BestFitStrategy{}.AssignRooms(candidates, 5)
+			}
+		})
+	}
+}
+
+func BenchmarkGuestPreferenceStrategy(b *testing.B) {
+	for _, numberOfCandidates := range []int{10, 100, 1000} {
+		candidates := benchmarkCandidates(numberOfCandidates)
+		b.Run(fmt.Sprintf("candidates=%d", numberOfCandidates), func(b *testing.B) {
+			for b.Loop() {
+				GuestPreferenceStrategy{}.AssignRooms(candidates, 5)
+			}
+		})
+	}
+}
@@ -3,52 +3,101 @@ package room_service
 import (
 	"context"
 	"errors"
+	"sync"
 
 	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
 	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
 	hotel_repo_mapping "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository"
 	room_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/room"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
+const roomColumns = `id, name, status, room_type_id, hotel_id`
+
+var roomSortColumns = map[string]utils.SortColumn{
+	"name": {Column: "name", Cast: "text"},
+}
+
 type RoomService struct {
+	conn *pgxpool.Pool
 	repo *room_repo.Queries
+	// Guards strategies, a strategy can be registered while rooms are being assigned
+	strategiesMu sync.RWMutex
+	strategies   map[string]RoomAssignmentStrategy
 }
 
-func NewRoomService(repo *room_repo.Queries) *RoomService {
+func NewRoomService(conn *pgxpool.Pool, repo *room_repo.Queries) *RoomService {
 	return &RoomService{
+		conn: conn,
 		repo: repo,
+		strategies: map[string]RoomAssignmentStrategy{
+			BEST_FIT_STRATEGY:         BestFitStrategy{},
+			GUEST_PREFERENCE_STRATEGY: GuestPreferenceStrategy{},
+		},
 	}
 }
 
-func (rs *RoomService) GetRoomsByHotelId(ctx context.Context, hotelId pgtype.UUID) ([]hotel_domain.Room, error) {
+// Add or replace a room assignment strategy by name
+func (rs *RoomService) RegisterAssignmentStrategy(name string, strategy RoomAssignmentStrategy) {
+	rs.strategiesMu.Lock()
+	defer rs.strategiesMu.Unlock()
 
-	rooms, err := rs.repo.GetRoomsByHotelId(ctx, hotelId)
-	if err != nil {
+	rs.strategies[name] = strategy
+}
 
-		zap.S().Errorln("Cannot get Rooms by Hotel Id: ", err)
-		return nil, err
-	}
+func (rs *RoomService) getAssignmentStrategy(name string) (RoomAssignmentStrategy, bool) {
+	rs.strategiesMu.RLock()
+	defer rs.strategiesMu.RUnlock()
+
+	strategy, ok := rs.strategies[name]
+	return strategy, ok
+}
 
-	result := hotel_repo_mapping.FromRoomsRepoToRoomsDomain(rooms)
+// ErrBadRequest when the page is invalid
+func (rs *RoomService) GetRoomsByHotelId(ctx context.Context, hotelId pgtype.UUID, pageParams utils.PageParams) ([]hotel_domain.Room, *utils.PageInfo, error) {
 
-	return result, nil
+	conditions := &utils.Conditions{}
+	conditions.Add("hotel_id = $%d", hotelId)
+
+	return rs.getRoomsPage(ctx, conditions, pageParams)
 }
 
-func (rs *RoomService) GetRoomsByRoomTypeId(ctx context.Context, roomTypeId pgtype.UUID) ([]hotel_domain.Room, error) {
+// ErrBadRequest when the page is invalid
+func (rs *RoomService) GetRoomsByRoomTypeId(ctx context.Context, roomTypeId pgtype.UUID, pageParams utils.PageParams) ([]hotel_domain.Room, *utils.PageInfo, error) {
 
-	rooms, err := rs.repo.GetRoomsByRoomTypeId(ctx, roomTypeId)
-	if err != nil {
+	conditions := &utils.Conditions{}
+	conditions.Add("room_type_id = $%d", roomTypeId)
 
-		zap.S().Errorln("Cannot get Rooms by Hotel Id: ", err)
-		return nil, err
+	return rs.getRoomsPage(ctx, conditions, pageParams)
+}
+
+func (rs *RoomService) getRoomsPage(ctx context.Context, conditions *utils.Conditions, pageParams utils.PageParams) ([]hotel_domain.Room, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, roomSortColumns, "name", "id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of rooms: ", pageParams)
+		return nil, nil, err
 	}
 
-	result := hotel_repo_mapping.FromRoomsRepoToRoomsDomain(rooms)
+	rooms, pageInfo, err := utils.QueryPage(ctx, rs.conn, page, roomColumns, "rooms", conditions,
+		func(rows pgx.Rows) (room_repo.Room, error) {
+			var r room_repo.Room
+			err := rows.Scan(&r.ID, &r.Name, &r.Status, &r.RoomTypeID, &r.HotelID)
+			return r, err
+		},
+		func(r room_repo.Room) (any, pgtype.UUID) { return r.Name, r.ID })
+	if err != nil {
+		zap.S().Errorln("Cannot get Rooms: ", err)
+		return nil, nil, err
+	}
 
-	return result, nil
+	return hotel_repo_mapping.FromRoomsRepoToRoomsDomain(rooms), pageInfo, nil
 }
 
 func (rs *RoomService) GetRoomsById(ctx context.Context, id pgtype.UUID) (*hotel_domain.Room, error) {
@@ -70,11 +119,18 @@ func (rs *RoomService) GetRoomsById(ctx context.Context, id pgtype.UUID) (*hotel
 
 }
 
+// ErrDuplicateRecord when the hotel already has a room of the same name
 func (rs *RoomService) CreateRoom(ctx context.Context, newRoom *room_repo.CreateRoomParams) error {
 
 	err := rs.repo.CreateRoom(ctx, *newRoom)
 	if err != nil {
-		zap.S().Errorln("Cannot create Room")
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Info("Duplicated Room name: ", newRoom.Name)
+			return common_error.ErrDuplicateRecord
+		}
+
+		zap.S().Errorln("Cannot create Room: ", err)
 		return err
 	}
 
@@ -124,23 +180,67 @@ func (rs *RoomService) GetListOfAvailableRoomsByRoomTypeId(ctx context.Context,
 	return roomIds, nil
 }
 
-func (rs *RoomService) GetListOfRemainRooms(ctx context.Context, roomTypeId pgtype.UUID, bookedRoomIds []pgtype.UUID, numberOfRooms int) ([]pgtype.UUID, error) {
+type AdjacentRoom struct {
+	RoomId          pgtype.UUID
+	TouchesCheckIn  bool
+	TouchesCheckOut bool
+}
 
-	zap.L().Info("Request: ", zap.Any("RoomType", roomTypeId), zap.Any("Booked RoomIds", bookedRoomIds), zap.Any("Number of Rooms", numberOfRooms))
+type GetListOfRemainRoomsParams struct {
+	RoomTypeId    pgtype.UUID
+	BookedRoomIds []pgtype.UUID
+	NumberOfRooms int
+	// Return whatever rooms remain instead of failing, so the caller can overbook the rest
+	AllowPartial       bool
+	AdjacentRooms      []AdjacentRoom
+	AssignmentStrategy string // Empty uses BEST_FIT
+}
 
-	roomIds, err := rs.repo.GetListOfRemainRooms(ctx, room_repo.GetListOfRemainRoomsParams{
-		RoomTypeID:    roomTypeId,
-		BookedRoomIds: bookedRoomIds,
-		NumberOfRooms: int32(numberOfRooms),
+func (rs *RoomService) GetListOfRemainRooms(ctx context.Context, params *GetListOfRemainRoomsParams) ([]pgtype.UUID, error) {
+
+	zap.L().Info("Request: ", zap.Any("RoomType", params.RoomTypeId), zap.Any("Booked RoomIds", params.BookedRoomIds), zap.Any("Number of Rooms", params.NumberOfRooms))
+
+	strategyName := params.AssignmentStrategy
+	if strategyName == "" {
+		strategyName = BEST_FIT_STRATEGY
+	}
+
+	strategy, ok := rs.getAssignmentStrategy(strategyName)
+	if !ok {
+		zap.S().Infoln("Room assignment strategy not found: ", strategyName)
+		return nil, common_error.ErrBadRequest
+	}
+
+	rooms, err := rs.repo.GetListOfRemainRooms(ctx, room_repo.GetListOfRemainRoomsParams{
+		RoomTypeID:    params.RoomTypeId,
+		BookedRoomIds: params.BookedRoomIds,
 	})
 	if err != nil {
 		zap.S().Errorln("Failed to get list of remain rooms by: ", err)
 		return nil, err
 	}
 
+	adjacentRoomsMap := make(map[pgtype.UUID]AdjacentRoom, len(params.AdjacentRooms))
+	for _, adjacentRoom := range params.AdjacentRooms {
+		adjacentRoomsMap[adjacentRoom.RoomId] = adjacentRoom
+	}
+
+	candidates := make([]RoomCandidate, 0, len(rooms))
+	for _, room := range rooms {
+		adjacentRoom := adjacentRoomsMap[room.ID]
+		candidates = append(candidates, RoomCandidate{
+			Id:              room.ID,
+			Name:            room.Name,
+			TouchesCheckIn:  adjacentRoom.TouchesCheckIn,
+			TouchesCheckOut: adjacentRoom.TouchesCheckOut,
+		})
+	}
+
+	roomIds := strategy.AssignRooms(candidates, params.NumberOfRooms)
+
 	zap.S().Infoln("Remain Rooms: ", roomIds)
 
-	if len(roomIds) < numberOfRooms {
+	if len(roomIds) < params.NumberOfRooms && !params.AllowPartial {
 		zap.S().Infoln("There is not enough rooms AVAILABLE.")
 		return nil, common_error.ErrNoRows
 	}
@@ -1,16 +1,219 @@
 package hotel_domain
 
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/098765432m/grpc-kafka/common/model"
+)
+
+var ErrAddressNotFound = errors.New("address could not be geocoded")
+
 type Hotel struct {
-	Id      string
-	Name    string
-	Address string
+	Id          string
+	Name        string
+	Address     string
+	City        string
+	Description string
+	Location    *GeoPoint // nil when the address could not be geocoded
+	Status      string    // DRAFT, SUBMITTED, APPROVED, REJECTED or PUBLISHED
+	CreatedBy   string    // id of the user who created the draft, empty for hotels created before the workflow
+	ChainId     string    // empty for independent hotels
+}
+
+// Signed in user calling the hotel service outside of the gateway
+type HotelRequester struct {
+	UserId  string
+	Role    string
+	HotelId string // hotel of a MANAGER
+	ChainId string // chain of a CHAIN_MANAGER
+}
+
+// Role of the JWT is ADMIN
+func (r HotelRequester) IsAdmin() bool {
+	return r.Role == model.ADMIN_ROLE
+}
+
+// ADMIN, the MANAGER of the hotel, the CHAIN_MANAGER of its chain or the user who created its draft
+func (r HotelRequester) CanManage(hotel Hotel) bool {
+	if r.UserId != "" && r.UserId == hotel.CreatedBy {
+		return true
+	}
+
+	switch r.Role {
+	case model.ADMIN_ROLE:
+		return true
+	case model.MANAGER_ROLE:
+		return r.HotelId != "" && r.HotelId == hotel.Id
+	case model.CHAIN_MANAGER_ROLE:
+		return r.ChainId != "" && r.ChainId == hotel.ChainId
+	default:
+		return false
+	}
+}
+
+// Published hotels are shown to everyone, other hotels only to the users who can manage them
+func (r HotelRequester) CanView(hotel Hotel) bool {
+	return hotel.Status == HOTEL_STATUS_PUBLISHED || r.CanManage(hotel)
+}
+
+// Chain or brand owning several hotels, managed by CHAIN_MANAGER users
+type HotelChain struct {
+	Id          string
+	Name        string
+	Brand       string // brand shown to guests, empty when it is the chain name
+	Description string
+	CreateAt    time.Time
+}
+
+// Publication workflow, only PUBLISHED hotels are shown in public listing and search
+const (
+	HOTEL_STATUS_DRAFT     = "DRAFT"
+	HOTEL_STATUS_SUBMITTED = "SUBMITTED"
+	HOTEL_STATUS_APPROVED  = "APPROVED"
+	HOTEL_STATUS_REJECTED  = "REJECTED"
+	HOTEL_STATUS_PUBLISHED = "PUBLISHED"
+)
+
+// Transition of the publication workflow, kept in the hotel history
+type HotelStatusEvent struct {
+	Id         string
+	HotelId    string
+	FromStatus string
+	ToStatus   string
+	Actor      string
+	Comment    string // review comment of ADMIN
+	CreateAt   time.Time
+}
+
+// Published on every transition so the manager who created the hotel can be notified
+type HotelStatusChangedEvent struct {
+	HotelId    string    `json:"hotel_id"`
+	Name       string    `json:"name"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	Comment    string    `json:"comment"`
+	CreatedBy  string    `json:"created_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type GeoBoundingBox struct {
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+}
+
+// Hotel found by a geo search with its distance to the searched point
+type NearbyHotel struct {
+	Hotel
+	DistanceKm float64
+}
+
+// Hotel with a free room type matching a filter, MinPrice is the lowest price of those room types
+type FilteredHotel struct {
+	Id         string
+	Name       string
+	Address    string
+	MinPrice   int32
+	DistanceKm float64 // 0 without a geo search
+}
+
+// Hotel found by a full text search, higher rank is a better match
+type RankedHotel struct {
+	Hotel
+	Rank float32
+}
+
+// Suggestions for the search box while the user types
+type HotelAutocomplete struct {
+	Hotels []Hotel // only id, name and city are set
+	Cities []string
+}
+
+// Turn the free text address of a hotel into coordinates, ErrAddressNotFound when the address is unknown
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (GeoPoint, error)
+}
+
+// Entry of the amenity catalog, Code is the stable key clients filter with (wifi, pool, parking...)
+type Amenity struct {
+	Id       string
+	Code     string
+	Category string
+	Labels   map[string]string // locale -> label
+}
+
+// Lower cased, trimmed and distinct amenity codes, empty codes are dropped
+func NormalizeAmenityCodes(codes []string) []string {
+	normalized := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code != "" && !slices.Contains(normalized, code) {
+			normalized = append(normalized, code)
+		}
+	}
+
+	return normalized
+}
+
+// Policy document of a hotel, clock times are HH:MM local to the hotel
+type HotelPolicy struct {
+	HotelId            string
+	CheckInFrom        string
+	CheckInUntil       string // empty when guests can arrive at any time after CheckInFrom
+	CheckOutUntil      string
+	PetsAllowed        bool
+	PetFee             int
+	ChildrenAllowed    bool
+	ChildrenFreeMaxAge *int // nil when every child pays
+	ExtraBedAvailable  bool
+	ExtraBedFee        int
+	PaymentMethods     []string
+	HouseRules         map[string]string // locale -> house rules
 }
 
 type RoomType struct {
-	Id      string
-	Name    string
-	Price   int
-	HotelId string
+	Id             string
+	Name           string
+	Price          int
+	HotelId        string
+	UpgradeRank    int // 0 is not in the upgrade ladder
+	AreaSqm        int // 0 when unknown
+	View           string
+	SmokingAllowed bool
+	MaxOccupancy   int
+	Description    string
+	Beds           []RoomTypeBed
+}
+
+type RoomTypeBed struct {
+	BedType  string
+	Quantity int
+}
+
+// Room type of a hotel listing with its number of rooms
+type HotelRoomType struct {
+	RoomType
+	NumberOfRooms int
+}
+
+type OverbookingLimit struct {
+	Id         string
+	RoomTypeId string
+	StartDate  time.Time
+	EndDate    time.Time
+	LimitType  string
+	LimitValue int
 }
 
 type Room struct {
@@ -20,3 +223,21 @@ type Room struct {
 	RoomTypeId string
 	HotelId    string
 }
+
+// Outcome of an inventory import, nothing is written when Errors is not empty
+type InventoryImportReport struct {
+	DryRun           bool
+	Applied          bool
+	RoomTypesCreated int
+	RoomTypesUpdated int
+	RoomsCreated     int
+	RoomsUpdated     int
+	Errors           []InventoryRowError
+}
+
+type InventoryRowError struct {
+	Kind    string // room_type or room
+	Row     int    // row number given by the client
+	Field   string
+	Message string
+}
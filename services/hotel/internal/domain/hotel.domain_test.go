@@ -0,0 +1,66 @@
+package hotel_domain
+
+import (
+	"testing"
+
+	"github.com/098765432m/grpc-kafka/common/model"
+)
+
+func TestHotelRequesterCanManage(t *testing.T) {
+	hotel := Hotel{Id: "hotel-1", CreatedBy: "user-1", ChainId: "chain-1"}
+
+	tests := []struct {
+		name      string
+		requester HotelRequester
+		want      bool
+	}{
+		{"admin", HotelRequester{UserId: "admin-1", Role: model.ADMIN_ROLE}, true},
+		{"manager of the hotel", HotelRequester{UserId: "user-2", Role: model.MANAGER_ROLE, HotelId: "hotel-1"}, true},
+		{"manager of another hotel", HotelRequester{UserId: "user-2", Role: model.MANAGER_ROLE, HotelId: "hotel-2"}, false},
+		{"manager without hotel", HotelRequester{UserId: "user-2", Role: model.MANAGER_ROLE}, false},
+		{"chain manager of the hotel", HotelRequester{UserId: "user-4", Role: model.CHAIN_MANAGER_ROLE, ChainId: "chain-1"}, true},
+		{"chain manager of another chain", HotelRequester{UserId: "user-4", Role: model.CHAIN_MANAGER_ROLE, ChainId: "chain-2"}, false},
+		{"chain manager without chain", HotelRequester{UserId: "user-4", Role: model.CHAIN_MANAGER_ROLE}, false},
+		{"guest", HotelRequester{UserId: "user-3", Role: model.GUEST_ROLE, HotelId: "hotel-1"}, false},
+		{"guest with the chain", HotelRequester{UserId: "user-3", Role: model.GUEST_ROLE, ChainId: "chain-1"}, false},
+		{"creator of the draft", HotelRequester{UserId: "user-1", Role: model.MANAGER_ROLE}, true},
+		{"anonymous", HotelRequester{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.requester.CanManage(hotel); got != tt.want {
+				t.Errorf("CanManage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHotelRequesterCanView(t *testing.T) {
+	manager := HotelRequester{UserId: "user-2", Role: model.MANAGER_ROLE, HotelId: "hotel-1"}
+	guest := HotelRequester{UserId: "user-3", Role: model.GUEST_ROLE}
+
+	tests := []struct {
+		name      string
+		status    string
+		requester HotelRequester
+		want      bool
+	}{
+		{"published to anonymous", HOTEL_STATUS_PUBLISHED, HotelRequester{}, true},
+		{"published to guest", HOTEL_STATUS_PUBLISHED, guest, true},
+		{"draft to guest", HOTEL_STATUS_DRAFT, guest, false},
+		{"approved to anonymous", HOTEL_STATUS_APPROVED, HotelRequester{}, false},
+		{"submitted to manager", HOTEL_STATUS_SUBMITTED, manager, true},
+		{"draft to creator", HOTEL_STATUS_DRAFT, HotelRequester{UserId: "user-1", Role: model.MANAGER_ROLE}, true},
+		{"rejected to admin", HOTEL_STATUS_REJECTED, HotelRequester{UserId: "admin-1", Role: model.ADMIN_ROLE}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hotel := Hotel{Id: "hotel-1", Status: tt.status, CreatedBy: "user-1"}
+			if got := tt.requester.CanView(hotel); got != tt.want {
+				t.Errorf("CanView() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
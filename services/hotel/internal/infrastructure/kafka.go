@@ -0,0 +1,57 @@
+package hotel_infrastructure
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/098765432m/grpc-kafka/common/consts"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+)
+
+type KafkaProducer struct {
+	cl *kgo.Client
+}
+
+func NewKafkaProducer(brokers []string) (*KafkaProducer, error) {
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(brokers...),
+	}
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KafkaProducer{
+		cl: client,
+	}, nil
+}
+
+func (kp *KafkaProducer) Close() {
+	kp.cl.Close()
+}
+
+// Publish event without waiting for broker, it is still sent once the request is done. Failures are only logged
+func (kp *KafkaProducer) PublishHotelStatusChanged(ctx context.Context, event hotel_domain.HotelStatusChangedEvent) {
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		zap.S().Errorln("Failed to marshal Hotel Status Changed event: ", err)
+		return
+	}
+
+	record := &kgo.Record{
+		Topic: consts.HOTEL_STATUS_CHANGED_TOPIC,
+		Key:   []byte(event.HotelId),
+		Value: value,
+	}
+
+	kp.cl.Produce(context.WithoutCancel(ctx), record, func(r *kgo.Record, err error) {
+		if err != nil {
+			zap.S().Errorln("Failed to produce Hotel Status Changed event: ", err)
+		}
+	})
+}
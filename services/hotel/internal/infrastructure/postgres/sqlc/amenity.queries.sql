@@ -0,0 +1,87 @@
+-- name: GetAmenityById :one
+SELECT *
+FROM amenities
+WHERE id = $1;
+
+-- name: GetAmenitiesByCodes :many
+SELECT *
+FROM amenities
+WHERE code = ANY(@codes::varchar[])
+ORDER BY category, code;
+
+-- name: CreateAmenity :one
+INSERT INTO amenities
+(
+    code,
+    category
+)
+VALUES
+(
+    @code::varchar,
+    @category::amenity_category
+)
+RETURNING *;
+
+-- name: UpdateAmenityById :one
+UPDATE amenities
+SET
+    code = @code::varchar,
+    category = @category::amenity_category
+WHERE id = @id::uuid
+RETURNING *;
+
+-- name: DeleteAmenityById :execrows
+DELETE FROM amenities WHERE id = $1;
+
+-- name: GetAmenityLabelsByAmenityIds :many
+SELECT *
+FROM amenity_labels
+WHERE amenity_id = ANY(@amenity_ids::uuid[])
+ORDER BY amenity_id, locale;
+
+-- name: SetAmenityLabels :exec
+-- Labels of the amenity become exactly the given ones, locales[i] is the locale of labels[i]
+WITH removed AS (
+    DELETE FROM amenity_labels
+    WHERE amenity_id = @amenity_id::uuid AND locale <> ALL(@locales::varchar[])
+)
+INSERT INTO amenity_labels (amenity_id, locale, label)
+SELECT @amenity_id::uuid, unnest(@locales::varchar[]), unnest(@labels::varchar[])
+ON CONFLICT (amenity_id, locale) DO UPDATE SET label = EXCLUDED.label;
+
+-- name: GetAmenitiesByHotelId :many
+SELECT a.*
+FROM amenities a JOIN hotel_amenities ha ON a.id = ha.amenity_id
+WHERE ha.hotel_id = $1
+ORDER BY a.category, a.code;
+
+-- name: GetAmenitiesByRoomTypeId :many
+SELECT a.*
+FROM amenities a JOIN room_type_amenities rta ON a.id = rta.amenity_id
+WHERE rta.room_type_id = $1
+ORDER BY a.category, a.code;
+
+-- name: SetHotelAmenities :exec
+-- Amenities of the hotel become exactly the given ones
+WITH removed AS (
+    DELETE FROM hotel_amenities
+    WHERE hotel_id = @hotel_id::uuid AND amenity_id <> ALL(@amenity_ids::uuid[])
+)
+INSERT INTO hotel_amenities (hotel_id, amenity_id)
+SELECT @hotel_id::uuid, unnest(@amenity_ids::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: SetRoomTypeAmenities :exec
+-- Amenities of the room type become exactly the given ones
+WITH removed AS (
+    DELETE FROM room_type_amenities
+    WHERE room_type_id = @room_type_id::uuid AND amenity_id <> ALL(@amenity_ids::uuid[])
+)
+INSERT INTO room_type_amenities (room_type_id, amenity_id)
+SELECT @room_type_id::uuid, unnest(@amenity_ids::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: GetHotelIdByRoomTypeId :one
+SELECT hotel_id
+FROM room_types
+WHERE id = $1;
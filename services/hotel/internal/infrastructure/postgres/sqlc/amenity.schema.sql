@@ -0,0 +1,33 @@
+CREATE TYPE amenity_category AS ENUM ('GENERAL', 'INTERNET', 'PARKING', 'WELLNESS', 'FOOD_AND_DRINK', 'ROOM', 'ACCESSIBILITY');
+
+-- Catalog managed by admins, code is the stable key clients filter with (wifi, pool, parking...)
+CREATE TABLE amenities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(50) NOT NULL UNIQUE CHECK (code ~ '^[a-z0-9_]+$'),
+    category amenity_category NOT NULL DEFAULT 'GENERAL'
+);
+
+-- Display name of an amenity per locale (vi, en...)
+CREATE TABLE amenity_labels (
+    amenity_id UUID NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    PRIMARY KEY (amenity_id, locale),
+    FOREIGN KEY (amenity_id) REFERENCES amenities(id) ON DELETE CASCADE
+);
+
+CREATE TABLE hotel_amenities (
+    hotel_id UUID NOT NULL,
+    amenity_id UUID NOT NULL,
+    PRIMARY KEY (hotel_id, amenity_id),
+    FOREIGN KEY (hotel_id) REFERENCES hotels(id) ON DELETE CASCADE,
+    FOREIGN KEY (amenity_id) REFERENCES amenities(id) ON DELETE CASCADE
+);
+
+CREATE TABLE room_type_amenities (
+    room_type_id UUID NOT NULL,
+    amenity_id UUID NOT NULL,
+    PRIMARY KEY (room_type_id, amenity_id),
+    FOREIGN KEY (room_type_id) REFERENCES room_types(id) ON DELETE CASCADE,
+    FOREIGN KEY (amenity_id) REFERENCES amenities(id) ON DELETE CASCADE
+);
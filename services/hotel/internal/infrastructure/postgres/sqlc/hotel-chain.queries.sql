@@ -0,0 +1,39 @@
+-- name: GetHotelChainById :one
+SELECT *
+FROM hotel_chains
+WHERE id = $1;
+
+-- name: CreateHotelChain :one
+INSERT INTO hotel_chains (name, brand, description)
+VALUES (
+    @name::text,
+    sqlc.narg('brand')::text,
+    sqlc.narg('description')::text
+)
+RETURNING *;
+
+-- name: UpdateHotelChainById :one
+UPDATE hotel_chains
+SET
+    name = @name::text,
+    brand = sqlc.narg('brand')::text,
+    description = sqlc.narg('description')::text
+WHERE id = @id::uuid
+RETURNING *;
+
+-- name: DeleteHotelChainById :execrows
+DELETE FROM hotel_chains WHERE id = $1;
+
+-- name: SetHotelChain :one
+-- chain_id NULL removes the hotel from its chain
+UPDATE hotels
+SET chain_id = sqlc.narg('chain_id')::uuid
+WHERE id = @id::uuid
+RETURNING *;
+
+-- name: GetHotelsByChainId :many
+-- Hotels of the chain in every publication status, chain managers also see their drafts
+SELECT *
+FROM hotels
+WHERE chain_id = $1
+ORDER BY name;
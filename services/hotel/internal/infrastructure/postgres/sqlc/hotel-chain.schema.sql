@@ -0,0 +1,14 @@
+-- Chain or brand owning several hotels, CHAIN_MANAGER users manage every hotel of their chain
+CREATE TABLE hotel_chains (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    -- Brand shown to guests, the chain name when NULL
+    brand VARCHAR(255),
+    description TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Independent hotels have no chain, hotels are kept when their chain is deleted
+ALTER TABLE hotels ADD COLUMN chain_id UUID REFERENCES hotel_chains(id) ON DELETE SET NULL;
+
+CREATE INDEX hotels_chain_id_idx ON hotels (chain_id);
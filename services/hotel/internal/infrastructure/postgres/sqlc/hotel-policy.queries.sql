@@ -0,0 +1,54 @@
+-- name: GetHotelPolicyByHotelId :one
+SELECT * FROM hotel_policies WHERE hotel_id = $1;
+
+-- name: UpsertHotelPolicy :one
+INSERT INTO hotel_policies (
+    hotel_id,
+    check_in_from,
+    check_in_until,
+    check_out_until,
+    pets_allowed,
+    pet_fee,
+    children_allowed,
+    children_free_max_age,
+    extra_bed_available,
+    extra_bed_fee,
+    payment_methods
+) VALUES (
+    @hotel_id::uuid,
+    @check_in_from::time,
+    sqlc.narg('check_in_until')::time,
+    @check_out_until::time,
+    @pets_allowed::boolean,
+    @pet_fee::int,
+    @children_allowed::boolean,
+    sqlc.narg('children_free_max_age')::int,
+    @extra_bed_available::boolean,
+    @extra_bed_fee::int,
+    @payment_methods::varchar[]
+)
+ON CONFLICT (hotel_id) DO UPDATE SET
+    check_in_from = EXCLUDED.check_in_from,
+    check_in_until = EXCLUDED.check_in_until,
+    check_out_until = EXCLUDED.check_out_until,
+    pets_allowed = EXCLUDED.pets_allowed,
+    pet_fee = EXCLUDED.pet_fee,
+    children_allowed = EXCLUDED.children_allowed,
+    children_free_max_age = EXCLUDED.children_free_max_age,
+    extra_bed_available = EXCLUDED.extra_bed_available,
+    extra_bed_fee = EXCLUDED.extra_bed_fee,
+    payment_methods = EXCLUDED.payment_methods
+RETURNING *;
+
+-- name: GetHotelPolicyTextsByHotelId :many
+SELECT * FROM hotel_policy_texts WHERE hotel_id = $1 ORDER BY locale;
+
+-- name: SetHotelPolicyTexts :exec
+-- House rules of the hotel become exactly the given ones, locales[i] is the locale of house_rules[i]
+WITH removed AS (
+    DELETE FROM hotel_policy_texts
+    WHERE hotel_id = @hotel_id::uuid AND locale <> ALL(@locales::varchar[])
+)
+INSERT INTO hotel_policy_texts (hotel_id, locale, house_rules)
+SELECT @hotel_id::uuid, unnest(@locales::varchar[]), unnest(@house_rules::text[])
+ON CONFLICT (hotel_id, locale) DO UPDATE SET house_rules = EXCLUDED.house_rules;
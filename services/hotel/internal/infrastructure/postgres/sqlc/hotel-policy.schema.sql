@@ -0,0 +1,29 @@
+-- One policy document per hotel, times are local to the hotel
+CREATE TABLE hotel_policies (
+    hotel_id UUID PRIMARY KEY,
+    check_in_from TIME NOT NULL DEFAULT '14:00',
+    -- NULL when guests can arrive at any time after check_in_from
+    check_in_until TIME,
+    check_out_until TIME NOT NULL DEFAULT '12:00',
+    pets_allowed BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Per pet per night, 0 when pets stay free
+    pet_fee INT NOT NULL DEFAULT 0 CHECK (pet_fee >= 0),
+    children_allowed BOOLEAN NOT NULL DEFAULT TRUE,
+    -- Children up to this age stay free using existing beds, NULL when every child pays
+    children_free_max_age INT CHECK (children_free_max_age >= 0),
+    extra_bed_available BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Per extra bed per night
+    extra_bed_fee INT NOT NULL DEFAULT 0 CHECK (extra_bed_fee >= 0),
+    payment_methods VARCHAR(30)[] NOT NULL DEFAULT '{}'
+        CHECK (payment_methods <@ ARRAY['CASH', 'CREDIT_CARD', 'DEBIT_CARD', 'BANK_TRANSFER', 'E_WALLET']::VARCHAR(30)[]),
+    FOREIGN KEY (hotel_id) REFERENCES hotels(id) ON DELETE CASCADE
+);
+
+-- House rules of the policy per locale (vi, en...)
+CREATE TABLE hotel_policy_texts (
+    hotel_id UUID NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    house_rules TEXT NOT NULL,
+    PRIMARY KEY (hotel_id, locale),
+    FOREIGN KEY (hotel_id) REFERENCES hotel_policies(hotel_id) ON DELETE CASCADE
+);
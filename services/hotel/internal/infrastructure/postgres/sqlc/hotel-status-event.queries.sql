@@ -0,0 +1,16 @@
+-- name: CreateHotelStatusEvent :one
+INSERT INTO hotel_status_events (hotel_id, from_status, to_status, actor, comment)
+VALUES (
+    @hotel_id::uuid,
+    @from_status::hotel_status,
+    @to_status::hotel_status,
+    @actor::text,
+    sqlc.narg('comment')::text
+)
+RETURNING *;
+
+-- name: GetHotelStatusEventsByHotelId :many
+SELECT *
+FROM hotel_status_events
+WHERE hotel_id = @hotel_id::uuid
+ORDER BY create_at, id;
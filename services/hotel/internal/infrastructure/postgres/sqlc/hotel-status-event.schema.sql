@@ -0,0 +1,24 @@
+-- Append-only history of the publication workflow, kept after the hotel is deleted
+CREATE TABLE hotel_status_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hotel_id UUID NOT NULL,
+    from_status hotel_status NOT NULL,
+    to_status hotel_status NOT NULL,
+    -- Who made the change, from x-actor gRPC metadata or 'system'
+    actor TEXT NOT NULL DEFAULT 'system',
+    -- Review comment of ADMIN, required when a hotel is rejected
+    comment TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX hotel_status_events_hotel_id_idx ON hotel_status_events (hotel_id, create_at);
+
+CREATE FUNCTION prevent_hotel_status_event_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'hotel_status_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER hotel_status_events_append_only
+BEFORE UPDATE OR DELETE ON hotel_status_events
+FOR EACH ROW EXECUTE FUNCTION prevent_hotel_status_event_change();
@@ -3,53 +3,47 @@ SELECT *
 FROM hotels 
 WHERE id = $1;
 
--- name: CreateHotel :exec
-INSERT INTO hotels (name, address) VALUES (@name::text, @address::text);
+-- name: CreateHotel :one
+-- New hotels are drafts until ADMIN approves them and they are published
+INSERT INTO hotels (name, address, city, description, latitude, longitude, created_by)
+VALUES (
+    @name::text,
+    @address::text,
+    sqlc.narg('city')::text,
+    sqlc.narg('description')::text,
+    sqlc.narg('latitude')::float8,
+    sqlc.narg('longitude')::float8,
+    sqlc.narg('created_by')::uuid
+)
+RETURNING *;
 
--- name: GetAll :many
-SELECT * 
+-- name: AutocompleteCities :many
+-- Cities of published hotels starting with the prefix, LIKE wildcards of the prefix are escaped by the service
+SELECT DISTINCT city
 FROM hotels
-LIMIT 20;
-
--- name: GetHotelsByAddress :many
-SELECT h.id
-FROM hotels h
-WHERE 
-    (
-        @address::text IS NULL
-        OR unaccent(h.address) ILIKE unaccent('%' || @address::text || '%')
-    ) 
-    AND 
-    (
-        @hotel_name::text IS NULL
-        OR unaccent(h.name) ILIKE unaccent( '%' || @hotel_name::text || '%')
-    )
-LIMIT 20;
-
--- name: FilterHotels :many
-SELECT 
-    h.id,
-    h.name,
-    h.address,
-    MIN(rt.price) AS min_price
-FROM hotels h LEFT JOIN room_types rt ON h.id = rt.hotel_id
-WHERE 
-    rt.id = ANY(@room_type_ids::uuid[])
-    AND 
-    (
-        sqlc.narg('min_price')::int IS NULL
-        OR sqlc.narg('max_price')::int IS NULL
-        OR rt.price BETWEEN @min_price AND @max_price
-    )
-GROUP BY h.id
-HAVING MIN(rt.price) > 0;
+WHERE lower(immutable_unaccent(city)) LIKE lower(immutable_unaccent(@prefix::text)) || '%'
+    AND status = 'PUBLISHED'
+ORDER BY city
+LIMIT @max_results::int;
 
--- name: UpdateHotelById :exec
+-- name: UpdateHotelById :one
 UPDATE hotels
 SET 
     name = @name::text,
-    address = @address::text
-WHERE id = @id::uuid;
+    address = @address::text,
+    city = sqlc.narg('city')::text,
+    description = sqlc.narg('description')::text,
+    latitude = sqlc.narg('latitude')::float8,
+    longitude = sqlc.narg('longitude')::float8
+WHERE id = @id::uuid
+RETURNING *;
+
+-- name: UpdateHotelStatus :one
+-- Only moves a hotel still in one of from_statuses, no rows when it is in another status
+UPDATE hotels
+SET status = @to_status::hotel_status
+WHERE id = @id::uuid AND status::text = ANY(@from_statuses::varchar[])
+RETURNING *;
 
--- name: DeleteHotelById :exec
+-- name: DeleteHotelById :execrows
 DELETE FROM hotels WHERE id = $1;
\ No newline at end of file
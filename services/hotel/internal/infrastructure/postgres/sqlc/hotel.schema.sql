@@ -1,5 +1,67 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+-- unaccent is only STABLE because its dictionary could change, pinning the dictionary lets indexes use it
+CREATE FUNCTION immutable_unaccent(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$;
+
+-- Publication workflow of a hotel: managers submit drafts, ADMIN approves or rejects them
+-- and only PUBLISHED hotels are shown in public listing and search
+CREATE TYPE hotel_status AS ENUM ('DRAFT', 'SUBMITTED', 'APPROVED', 'REJECTED', 'PUBLISHED');
+
 CREATE TABLE hotels (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name VARCHAR(255) NOT NULL,
-    address TEXT NOT NULL
-);
\ No newline at end of file
+    address TEXT NOT NULL,
+    -- NULL when the address could not be geocoded, the hotel is then left out of geo searches
+    latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    CHECK ((latitude IS NULL) = (longitude IS NULL)),
+    city VARCHAR(255),
+    description TEXT,
+    status hotel_status NOT NULL DEFAULT 'DRAFT',
+    -- User who created the draft, NULL for hotels created before the workflow
+    created_by UUID
+);
+
+-- Public listings only read PUBLISHED hotels, the review queue reads SUBMITTED ones
+CREATE INDEX hotels_status_idx ON hotels (status);
+
+-- Radius searches look up earth_box(...) @> ll_to_earth(latitude, longitude)
+CREATE INDEX hotels_location_idx ON hotels USING gist (ll_to_earth(latitude, longitude));
+
+-- City suggestions of the search box look up lower(immutable_unaccent(city)) LIKE 'prefix%'
+CREATE INDEX hotels_city_prefix_idx ON hotels (lower(immutable_unaccent(city)) text_pattern_ops);
+
+-- Full text document of each hotel, kept in sync by the trigger below.
+-- 'simple' config: there is no Vietnamese stemmer, words are only lower cased and unaccented.
+-- Weights: A name, B city and address, C description
+CREATE TABLE hotel_search_documents (
+    hotel_id UUID PRIMARY KEY REFERENCES hotels(id) ON DELETE CASCADE,
+    search_vector TSVECTOR NOT NULL
+);
+
+CREATE INDEX hotel_search_documents_vector_idx ON hotel_search_documents USING gin (search_vector);
+
+CREATE FUNCTION refresh_hotel_search_document() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    INSERT INTO hotel_search_documents (hotel_id, search_vector)
+    VALUES (
+        NEW.id,
+        setweight(to_tsvector('simple', immutable_unaccent(NEW.name)), 'A')
+        || setweight(to_tsvector('simple', immutable_unaccent(coalesce(NEW.city, ''))), 'B')
+        || setweight(to_tsvector('simple', immutable_unaccent(coalesce(NEW.address, ''))), 'B')
+        || setweight(to_tsvector('simple', immutable_unaccent(coalesce(NEW.description, ''))), 'C')
+    )
+    ON CONFLICT (hotel_id) DO UPDATE SET search_vector = EXCLUDED.search_vector;
+
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER hotels_search_document
+AFTER INSERT OR UPDATE OF name, address, city, description ON hotels
+FOR EACH ROW EXECUTE FUNCTION refresh_hotel_search_document();
\ No newline at end of file
@@ -1,30 +1,142 @@
 -- Enable unaccent
 CREATE EXTENSION IF NOT EXISTS unaccent;
 
+-- unaccent is only STABLE because its dictionary could change, pinning the dictionary lets indexes use it
+CREATE FUNCTION immutable_unaccent(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$;
+
+-- Distance between hotels and a point, earthdistance needs cube
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+-- Publication workflow of a hotel: managers submit drafts, ADMIN approves or rejects them
+-- and only PUBLISHED hotels are shown in public listing and search
+CREATE TYPE hotel_status AS ENUM ('DRAFT', 'SUBMITTED', 'APPROVED', 'REJECTED', 'PUBLISHED');
+
 CREATE TABLE hotels (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name VARCHAR(255) NOT NULL,
-    address TEXT NOT NULL
+    address TEXT NOT NULL,
+    -- NULL when the address could not be geocoded, the hotel is then left out of geo searches
+    latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    CHECK ((latitude IS NULL) = (longitude IS NULL)),
+    city VARCHAR(255),
+    description TEXT,
+    status hotel_status NOT NULL DEFAULT 'DRAFT',
+    -- User who created the draft, NULL for hotels created before the workflow
+    created_by UUID
+);
+
+-- Public listings only read PUBLISHED hotels, the review queue reads SUBMITTED ones
+CREATE INDEX hotels_status_idx ON hotels (status);
+
+-- Radius searches look up earth_box(...) @> ll_to_earth(latitude, longitude)
+CREATE INDEX hotels_location_idx ON hotels USING gist (ll_to_earth(latitude, longitude));
+
+-- City suggestions of the search box look up lower(immutable_unaccent(city)) LIKE 'prefix%'
+CREATE INDEX hotels_city_prefix_idx ON hotels (lower(immutable_unaccent(city)) text_pattern_ops);
+
+-- Full text document of each hotel, kept in sync by the trigger below.
+-- 'simple' config: there is no Vietnamese stemmer, words are only lower cased and unaccented.
+-- Weights: A name, B city and address, C description
+CREATE TABLE hotel_search_documents (
+    hotel_id UUID PRIMARY KEY REFERENCES hotels(id) ON DELETE CASCADE,
+    search_vector TSVECTOR NOT NULL
+);
+
+CREATE INDEX hotel_search_documents_vector_idx ON hotel_search_documents USING gin (search_vector);
+
+CREATE FUNCTION refresh_hotel_search_document() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    INSERT INTO hotel_search_documents (hotel_id, search_vector)
+    VALUES (
+        NEW.id,
+        setweight(to_tsvector('simple', immutable_unaccent(NEW.name)), 'A')
+        || setweight(to_tsvector('simple', immutable_unaccent(coalesce(NEW.city, ''))), 'B')
+        || setweight(to_tsvector('simple', immutable_unaccent(coalesce(NEW.address, ''))), 'B')
+        || setweight(to_tsvector('simple', immutable_unaccent(coalesce(NEW.description, ''))), 'C')
+    )
+    ON CONFLICT (hotel_id) DO UPDATE SET search_vector = EXCLUDED.search_vector;
+
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER hotels_search_document
+AFTER INSERT OR UPDATE OF name, address, city, description ON hotels
+FOR EACH ROW EXECUTE FUNCTION refresh_hotel_search_document();
+
+INSERT INTO hotels (id, name, address, latitude, longitude, city, description, status) VALUES
+('3868a0b9-eadb-471b-8f7b-7547cc837fb2', 'Hotel Cali', 'Hồ Chí Minh', 10.7769, 106.7009, 'Hồ Chí Minh', 'Khách sạn gần chợ Bến Thành', 'PUBLISHED'),
+('a312ff75-0695-4a50-bdea-4049972e99b8', 'Hotel Lisa', 'Hồ Chí Minh', 10.7626, 106.6602, 'Hồ Chí Minh', 'Khách sạn yên tĩnh ở quận 5', 'PUBLISHED'),
+('d51d6cee-55a5-443f-be8b-82a12fe2283a', 'Hotel Fifteen', 'Cần Thơ', 10.0452, 105.7469, 'Cần Thơ', 'Khách sạn gần bến Ninh Kiều', 'PUBLISHED');
+
+-- Chain or brand owning several hotels, CHAIN_MANAGER users manage every hotel of their chain
+CREATE TABLE hotel_chains (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    -- Brand shown to guests, the chain name when NULL
+    brand VARCHAR(255),
+    description TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
-INSERT INTO hotels (id, name, address) VALUES
-('3868a0b9-eadb-471b-8f7b-7547cc837fb2', 'Hotel Cali', 'Hồ Chí Minh'),
-('a312ff75-0695-4a50-bdea-4049972e99b8', 'Hotel Lisa', 'Hồ Chí Minh'),
-('d51d6cee-55a5-443f-be8b-82a12fe2283a', 'Hotel Fifteen', 'Cần Thơ');
+-- Independent hotels have no chain, hotels are kept when their chain is deleted
+ALTER TABLE hotels ADD COLUMN chain_id UUID REFERENCES hotel_chains(id) ON DELETE SET NULL;
+
+CREATE INDEX hotels_chain_id_idx ON hotels (chain_id);
+
+INSERT INTO hotel_chains (id, name, brand, description) VALUES
+('6f1c2b8e-4a0d-4c3e-9b57-2d8e1f0a7c31', 'Cali Group', 'Cali', 'Chuỗi khách sạn tại Hồ Chí Minh');
+
+UPDATE hotels SET chain_id = '6f1c2b8e-4a0d-4c3e-9b57-2d8e1f0a7c31'
+WHERE id IN ('3868a0b9-eadb-471b-8f7b-7547cc837fb2', 'a312ff75-0695-4a50-bdea-4049972e99b8');
+
+CREATE TYPE room_view AS ENUM ('NONE', 'CITY', 'SEA', 'GARDEN', 'MOUNTAIN', 'POOL', 'RIVER');
 
 CREATE TABLE room_types (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name VARCHAR(255) NOT NULL,
     price INT NOT NULL,
     hotel_id UUID NOT NULL,
+    -- Position in the hotel upgrade ladder, higher is better. NULL is not upgradable
+    upgrade_rank INT,
+    -- Descriptive attributes shown on room cards, beds are in room_type_beds
+    area_sqm INT CHECK (area_sqm > 0),
+    view room_view NOT NULL DEFAULT 'NONE',
+    smoking_allowed BOOLEAN NOT NULL DEFAULT FALSE,
+    max_occupancy INT NOT NULL DEFAULT 2 CHECK (max_occupancy > 0),
+    description TEXT,
+    -- Natural key of inventory imports
+    UNIQUE (hotel_id, name),
     FOREIGN KEY (hotel_id) REFERENCES hotels(id) ON DELETE CASCADE
 );
 
-INSERT INTO room_types (id, name, price, hotel_id) VALUES
-('91e67b8c-1aba-44bd-a8c3-015da7350ee5', 'Phong don', '80000', '3868a0b9-eadb-471b-8f7b-7547cc837fb2'),
-('b1a9e960-caef-4da8-9b12-0b467bf74244', 'Phong doi', '120000', '3868a0b9-eadb-471b-8f7b-7547cc837fb2'),
-('ba5f1d28-f156-493a-bb16-7a5c212728b2', 'Phong don', '65000', 'a312ff75-0695-4a50-bdea-4049972e99b8'),
-('75a56031-674c-461d-9b3c-1fce1ad8dec2', 'Phong doi', '115000', 'a312ff75-0695-4a50-bdea-4049972e99b8');
+INSERT INTO room_types (id, name, price, hotel_id, area_sqm, view, smoking_allowed, max_occupancy, description) VALUES
+('91e67b8c-1aba-44bd-a8c3-015da7350ee5', 'Phong don', '80000', '3868a0b9-eadb-471b-8f7b-7547cc837fb2', 18, 'CITY', FALSE, 1, 'Phòng đơn hướng phố'),
+('b1a9e960-caef-4da8-9b12-0b467bf74244', 'Phong doi', '120000', '3868a0b9-eadb-471b-8f7b-7547cc837fb2', 28, 'CITY', FALSE, 2, 'Phòng đôi có ban công'),
+('ba5f1d28-f156-493a-bb16-7a5c212728b2', 'Phong don', '65000', 'a312ff75-0695-4a50-bdea-4049972e99b8', 16, 'NONE', TRUE, 1, NULL),
+('75a56031-674c-461d-9b3c-1fce1ad8dec2', 'Phong doi', '115000', 'a312ff75-0695-4a50-bdea-4049972e99b8', 25, 'GARDEN', FALSE, 3, 'Phòng đôi nhìn ra vườn');
+
+CREATE TYPE bed_type AS ENUM ('SINGLE', 'DOUBLE', 'QUEEN', 'KING', 'SOFA_BED', 'BUNK');
+
+CREATE TABLE room_type_beds (
+    room_type_id UUID NOT NULL,
+    bed_type bed_type NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (room_type_id, bed_type),
+    FOREIGN KEY (room_type_id) REFERENCES room_types(id) ON DELETE CASCADE
+);
+
+INSERT INTO room_type_beds (room_type_id, bed_type, quantity) VALUES
+('91e67b8c-1aba-44bd-a8c3-015da7350ee5', 'SINGLE', 1),
+('b1a9e960-caef-4da8-9b12-0b467bf74244', 'QUEEN', 1),
+('ba5f1d28-f156-493a-bb16-7a5c212728b2', 'SINGLE', 1),
+('75a56031-674c-461d-9b3c-1fce1ad8dec2', 'DOUBLE', 1),
+('75a56031-674c-461d-9b3c-1fce1ad8dec2', 'SINGLE', 1);
 
 CREATE TYPE room_status AS ENUM ('AVAILABLE', 'MAINTAINED');
 
@@ -34,6 +146,8 @@ CREATE TABLE rooms (
     status room_status,
     room_type_id UUID NOT NULL,
     hotel_id UUID NOT NULL,
+    -- Natural key of inventory imports
+    UNIQUE (hotel_id, name),
     FOREIGN KEY (hotel_id) REFERENCES hotels(id) ON DELETE CASCADE,
     FOREIGN KEY (room_type_id) REFERENCES room_types(id) ON DELETE CASCADE
 );
@@ -42,4 +156,145 @@ INSERT INTO rooms (id, name, status, room_type_id, hotel_id) VALUES
 ('9754c143-fdf3-4209-a7d2-66eac786cb77', '100', 'AVAILABLE', '91e67b8c-1aba-44bd-a8c3-015da7350ee5', '3868a0b9-eadb-471b-8f7b-7547cc837fb2'),
 ('824110e0-517c-4cfb-a3b9-489fe19cef1d', '101', 'AVAILABLE', '91e67b8c-1aba-44bd-a8c3-015da7350ee5', '3868a0b9-eadb-471b-8f7b-7547cc837fb2'),
 ('5b739ee4-8ac1-468b-9e89-5207f5e801d8', '102', 'AVAILABLE', 'b1a9e960-caef-4da8-9b12-0b467bf74244', '3868a0b9-eadb-471b-8f7b-7547cc837fb2'),
-('070091cd-ac8c-48c2-9218-77fff85115d8', '100', 'AVAILABLE', 'ba5f1d28-f156-493a-bb16-7a5c212728b2', 'a312ff75-0695-4a50-bdea-4049972e99b8');
\ No newline at end of file
+('070091cd-ac8c-48c2-9218-77fff85115d8', '100', 'AVAILABLE', 'ba5f1d28-f156-493a-bb16-7a5c212728b2', 'a312ff75-0695-4a50-bdea-4049972e99b8');
+
+CREATE TYPE overbooking_limit_type AS ENUM ('ABSOLUTE', 'PERCENT');
+
+CREATE TABLE room_type_overbooking_limits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    room_type_id UUID NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    limit_type overbooking_limit_type NOT NULL DEFAULT 'ABSOLUTE',
+    limit_value INT NOT NULL CHECK (limit_value >= 0),
+    CHECK (start_date < end_date),
+    FOREIGN KEY (room_type_id) REFERENCES room_types(id) ON DELETE CASCADE
+);
+
+CREATE TYPE amenity_category AS ENUM ('GENERAL', 'INTERNET', 'PARKING', 'WELLNESS', 'FOOD_AND_DRINK', 'ROOM', 'ACCESSIBILITY');
+
+-- Catalog managed by admins, code is the stable key clients filter with (wifi, pool, parking...)
+CREATE TABLE amenities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(50) NOT NULL UNIQUE CHECK (code ~ '^[a-z0-9_]+$'),
+    category amenity_category NOT NULL DEFAULT 'GENERAL'
+);
+
+-- Display name of an amenity per locale (vi, en...)
+CREATE TABLE amenity_labels (
+    amenity_id UUID NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    PRIMARY KEY (amenity_id, locale),
+    FOREIGN KEY (amenity_id) REFERENCES amenities(id) ON DELETE CASCADE
+);
+
+INSERT INTO amenities (id, code, category) VALUES
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a01', 'wifi', 'INTERNET'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a02', 'parking', 'PARKING'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a03', 'pool', 'WELLNESS'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a04', 'restaurant', 'FOOD_AND_DRINK'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a05', 'air_conditioning', 'ROOM');
+
+INSERT INTO amenity_labels (amenity_id, locale, label) VALUES
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a01', 'vi', 'Wi-Fi miễn phí'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a01', 'en', 'Free Wi-Fi'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a02', 'vi', 'Bãi đỗ xe'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a02', 'en', 'Parking'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a03', 'vi', 'Hồ bơi'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a03', 'en', 'Swimming pool'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a04', 'vi', 'Nhà hàng'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a04', 'en', 'Restaurant'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a05', 'vi', 'Điều hòa'),
+('5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a05', 'en', 'Air conditioning');
+
+CREATE TABLE hotel_amenities (
+    hotel_id UUID NOT NULL,
+    amenity_id UUID NOT NULL,
+    PRIMARY KEY (hotel_id, amenity_id),
+    FOREIGN KEY (hotel_id) REFERENCES hotels(id) ON DELETE CASCADE,
+    FOREIGN KEY (amenity_id) REFERENCES amenities(id) ON DELETE CASCADE
+);
+
+CREATE TABLE room_type_amenities (
+    room_type_id UUID NOT NULL,
+    amenity_id UUID NOT NULL,
+    PRIMARY KEY (room_type_id, amenity_id),
+    FOREIGN KEY (room_type_id) REFERENCES room_types(id) ON DELETE CASCADE,
+    FOREIGN KEY (amenity_id) REFERENCES amenities(id) ON DELETE CASCADE
+);
+
+INSERT INTO hotel_amenities (hotel_id, amenity_id) VALUES
+('3868a0b9-eadb-471b-8f7b-7547cc837fb2', '5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a01'),
+('3868a0b9-eadb-471b-8f7b-7547cc837fb2', '5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a03'),
+('3868a0b9-eadb-471b-8f7b-7547cc837fb2', '5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a04'),
+('a312ff75-0695-4a50-bdea-4049972e99b8', '5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a01'),
+('a312ff75-0695-4a50-bdea-4049972e99b8', '5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a02');
+
+INSERT INTO room_type_amenities (room_type_id, amenity_id) VALUES
+('b1a9e960-caef-4da8-9b12-0b467bf74244', '5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a05'),
+('75a56031-674c-461d-9b3c-1fce1ad8dec2', '5f0f6c39-2f63-4d43-9a0e-1c9b7c1d2a05');
+
+-- One policy document per hotel, times are local to the hotel
+CREATE TABLE hotel_policies (
+    hotel_id UUID PRIMARY KEY,
+    check_in_from TIME NOT NULL DEFAULT '14:00',
+    -- NULL when guests can arrive at any time after check_in_from
+    check_in_until TIME,
+    check_out_until TIME NOT NULL DEFAULT '12:00',
+    pets_allowed BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Per pet per night, 0 when pets stay free
+    pet_fee INT NOT NULL DEFAULT 0 CHECK (pet_fee >= 0),
+    children_allowed BOOLEAN NOT NULL DEFAULT TRUE,
+    -- Children up to this age stay free using existing beds, NULL when every child pays
+    children_free_max_age INT CHECK (children_free_max_age >= 0),
+    extra_bed_available BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Per extra bed per night
+    extra_bed_fee INT NOT NULL DEFAULT 0 CHECK (extra_bed_fee >= 0),
+    payment_methods VARCHAR(30)[] NOT NULL DEFAULT '{}'
+        CHECK (payment_methods <@ ARRAY['CASH', 'CREDIT_CARD', 'DEBIT_CARD', 'BANK_TRANSFER', 'E_WALLET']::VARCHAR(30)[]),
+    FOREIGN KEY (hotel_id) REFERENCES hotels(id) ON DELETE CASCADE
+);
+
+-- House rules of the policy per locale (vi, en...)
+CREATE TABLE hotel_policy_texts (
+    hotel_id UUID NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    house_rules TEXT NOT NULL,
+    PRIMARY KEY (hotel_id, locale),
+    FOREIGN KEY (hotel_id) REFERENCES hotel_policies(hotel_id) ON DELETE CASCADE
+);
+
+INSERT INTO hotel_policies (hotel_id, check_in_from, check_in_until, check_out_until, pets_allowed, pet_fee, children_allowed, children_free_max_age, extra_bed_available, extra_bed_fee, payment_methods) VALUES
+('3868a0b9-eadb-471b-8f7b-7547cc837fb2', '14:00', '23:00', '12:00', FALSE, 0, TRUE, 6, TRUE, 300000, '{CASH,CREDIT_CARD,BANK_TRANSFER}'),
+('a312ff75-0695-4a50-bdea-4049972e99b8', '13:00', NULL, '11:00', TRUE, 150000, TRUE, NULL, FALSE, 0, '{CASH,E_WALLET}');
+
+INSERT INTO hotel_policy_texts (hotel_id, locale, house_rules) VALUES
+('3868a0b9-eadb-471b-8f7b-7547cc837fb2', 'vi', 'Không hút thuốc trong phòng. Giữ yên lặng sau 22:00.'),
+('3868a0b9-eadb-471b-8f7b-7547cc837fb2', 'en', 'No smoking in rooms. Quiet hours after 22:00.'),
+('a312ff75-0695-4a50-bdea-4049972e99b8', 'vi', 'Thú cưng phải được xích khi ở khu vực chung.');
+
+-- Append-only history of the publication workflow, kept after the hotel is deleted
+CREATE TABLE hotel_status_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hotel_id UUID NOT NULL,
+    from_status hotel_status NOT NULL,
+    to_status hotel_status NOT NULL,
+    -- Who made the change, from x-actor gRPC metadata or 'system'
+    actor TEXT NOT NULL DEFAULT 'system',
+    -- Review comment of ADMIN, required when a hotel is rejected
+    comment TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX hotel_status_events_hotel_id_idx ON hotel_status_events (hotel_id, create_at);
+
+CREATE FUNCTION prevent_hotel_status_event_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'hotel_status_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER hotel_status_events_append_only
+BEFORE UPDATE OR DELETE ON hotel_status_events
+FOR EACH ROW EXECUTE FUNCTION prevent_hotel_status_event_change();
@@ -0,0 +1,64 @@
+-- name: GetInventoryHotelById :one
+SELECT id, created_by, chain_id
+FROM hotels
+WHERE id = $1;
+
+-- name: GetRoomTypeKeysByHotelId :many
+SELECT id, name
+FROM room_types
+WHERE hotel_id = $1;
+
+-- name: GetRoomNamesByHotelId :many
+SELECT name
+FROM rooms
+WHERE hotel_id = $1;
+
+-- name: UpsertRoomTypes :many
+-- Room types are matched by hotel and name, names[i] has prices[i]...
+-- area 0 and empty description are stored as NULL
+INSERT INTO room_types (hotel_id, name, price, area_sqm, view, smoking_allowed, max_occupancy, description)
+SELECT
+    @hotel_id::uuid,
+    t.name,
+    t.price,
+    NULLIF(t.area_sqm, 0),
+    t.view::room_view,
+    t.smoking_allowed,
+    t.max_occupancy,
+    NULLIF(t.description, '')
+FROM unnest(
+    @names::text[],
+    @prices::int[],
+    @area_sqms::int[],
+    @views::text[],
+    @smoking_allowed::boolean[],
+    @max_occupancies::int[],
+    @descriptions::text[]
+) AS t(name, price, area_sqm, view, smoking_allowed, max_occupancy, description)
+ON CONFLICT (hotel_id, name) DO UPDATE SET
+    price = EXCLUDED.price,
+    area_sqm = EXCLUDED.area_sqm,
+    view = EXCLUDED.view,
+    smoking_allowed = EXCLUDED.smoking_allowed,
+    max_occupancy = EXCLUDED.max_occupancy,
+    description = EXCLUDED.description
+RETURNING id, name, (xmax = 0)::boolean AS inserted;
+
+-- name: UpsertRooms :many
+-- Rooms are matched by hotel and name, a matched room is moved to its new room type.
+-- An empty status is AVAILABLE for a new room and keeps the status of a matched room
+WITH input AS (
+    SELECT t.name, t.room_type_id, NULLIF(t.status, '')::room_status AS status
+    FROM unnest(
+        @names::text[],
+        @room_type_ids::uuid[],
+        @statuses::text[]
+    ) AS t(name, room_type_id, status)
+)
+INSERT INTO rooms (hotel_id, name, room_type_id, status)
+SELECT @hotel_id::uuid, input.name, input.room_type_id, COALESCE(input.status, 'AVAILABLE')
+FROM input
+ON CONFLICT (hotel_id, name) DO UPDATE SET
+    room_type_id = EXCLUDED.room_type_id,
+    status = COALESCE((SELECT input.status FROM input WHERE input.name = EXCLUDED.name), rooms.status)
+RETURNING (xmax = 0)::boolean AS inserted;
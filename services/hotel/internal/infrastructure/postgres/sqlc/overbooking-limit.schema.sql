@@ -0,0 +1,12 @@
+CREATE TYPE overbooking_limit_type AS ENUM ('ABSOLUTE', 'PERCENT');
+
+CREATE TABLE room_type_overbooking_limits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    room_type_id UUID NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    limit_type overbooking_limit_type NOT NULL DEFAULT 'ABSOLUTE',
+    limit_value INT NOT NULL CHECK (limit_value >= 0),
+    CHECK (start_date < end_date),
+    FOREIGN KEY (room_type_id) REFERENCES room_types(id) ON DELETE CASCADE
+);
@@ -1,35 +1,125 @@
--- name: GetRoomTypesByHotelId :many
-SELECT 
-    rt.id,
-    rt.name,
-    rt.price,
-    rt.hotel_id,
-    COUNT(r.id) AS number_of_rooms 
-FROM room_types rt LEFT JOIN rooms r ON rt.id = r.room_type_id
-WHERE rt.hotel_id = $1
-GROUP BY rt.id
-ORDER BY rt.name
-LIMIT 10;
-
 -- name: GetRoomTypeById :one
 SELECT *
 FROM room_types
 WHERE id = $1;
 
--- name: CreateRoomType :exec
+-- name: CreateRoomType :one
 INSERT INTO room_types
 (
     name,
     price,
-    hotel_id
+    hotel_id,
+    area_sqm,
+    view,
+    smoking_allowed,
+    max_occupancy,
+    description
 )
 VALUES
 (
     @name::text,
     @price::int,
-    @hotel_id::uuid
-);
+    @hotel_id::uuid,
+    sqlc.narg(area_sqm)::int,
+    @view::room_view,
+    @smoking_allowed::boolean,
+    @max_occupancy::int,
+    sqlc.narg(description)::text
+)
+RETURNING *;
+
+-- name: UpdateRoomTypeById :one
+-- Only a room type of the hotel is updated
+UPDATE room_types
+SET
+    name = @name::text,
+    price = @price::int,
+    area_sqm = sqlc.narg(area_sqm)::int,
+    view = @view::room_view,
+    smoking_allowed = @smoking_allowed::boolean,
+    max_occupancy = @max_occupancy::int,
+    description = sqlc.narg(description)::text
+WHERE id = @id::uuid AND hotel_id = @hotel_id::uuid
+RETURNING *;
+
+-- name: GetRoomTypeBedsByRoomTypeIds :many
+SELECT *
+FROM room_type_beds
+WHERE room_type_id = ANY(@room_type_ids::uuid[])
+ORDER BY room_type_id, bed_type;
+
+-- name: SetRoomTypeBeds :exec
+-- Beds of the room type become exactly the given ones, bed_types[i] has quantities[i] beds
+WITH removed AS (
+    DELETE FROM room_type_beds
+    WHERE room_type_id = @room_type_id::uuid AND bed_type::text <> ALL(@bed_types::text[])
+)
+INSERT INTO room_type_beds (room_type_id, bed_type, quantity)
+SELECT @room_type_id::uuid, unnest(@bed_types::text[])::bed_type, unnest(@quantities::int[])
+ON CONFLICT (room_type_id, bed_type) DO UPDATE SET quantity = EXCLUDED.quantity;
 
 -- name: DeleteRoomTypeById :exec
 DELETE FROM room_types
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: CountRoomsByRoomTypeId :one
+SELECT COUNT(r.id) AS total_rooms
+FROM rooms r
+WHERE 
+    r.room_type_id = @room_type_id::uuid
+    AND r.status != 'MAINTAINED';
+
+-- name: CreateOverbookingLimit :execrows
+INSERT INTO room_type_overbooking_limits
+(
+    room_type_id,
+    start_date,
+    end_date,
+    limit_type,
+    limit_value
+)
+SELECT
+    rt.id,
+    @start_date::date,
+    @end_date::date,
+    @limit_type::overbooking_limit_type,
+    @limit_value::int
+FROM room_types rt
+WHERE 
+    rt.id = @room_type_id::uuid
+    AND rt.hotel_id = @hotel_id::uuid;
+
+-- name: GetOverbookingLimitsByRoomTypeId :many
+SELECT *
+FROM room_type_overbooking_limits
+WHERE room_type_id = $1
+ORDER BY start_date;
+
+-- name: GetOverbookingLimitsInRange :many
+SELECT *
+FROM room_type_overbooking_limits
+WHERE 
+    room_type_id = @room_type_id::uuid
+    AND daterange(start_date, end_date, '[)') && daterange(@check_in::date, @check_out::date, '[)')
+ORDER BY start_date;
+
+-- name: DeleteOverbookingLimitById :execrows
+DELETE FROM room_type_overbooking_limits ol
+USING room_types rt
+WHERE 
+    ol.id = @id::uuid
+    AND ol.room_type_id = rt.id
+    AND rt.hotel_id = @hotel_id::uuid;
+
+-- name: SetRoomTypeUpgradeRank :execrows
+UPDATE room_types
+SET upgrade_rank = sqlc.narg(upgrade_rank)::int
+WHERE id = @id::uuid AND hotel_id = @hotel_id::uuid;
+
+-- name: GetUpgradeRoomTypesByRoomTypeId :many
+SELECT rt.*
+FROM room_types rt JOIN room_types origin ON rt.hotel_id = origin.hotel_id
+WHERE 
+    origin.id = @room_type_id::uuid
+    AND rt.upgrade_rank > origin.upgrade_rank
+ORDER BY rt.upgrade_rank;
@@ -1,7 +1,29 @@
+CREATE TYPE room_view AS ENUM ('NONE', 'CITY', 'SEA', 'GARDEN', 'MOUNTAIN', 'POOL', 'RIVER');
+
 CREATE TABLE room_types (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name VARCHAR(255) NOT NULL,
     price INT NOT NULL,
     hotel_id UUID NOT NULL,
+    -- Position in the hotel upgrade ladder, higher is better. NULL is not upgradable
+    upgrade_rank INT,
+    -- Descriptive attributes shown on room cards, beds are in room_type_beds
+    area_sqm INT CHECK (area_sqm > 0),
+    view room_view NOT NULL DEFAULT 'NONE',
+    smoking_allowed BOOLEAN NOT NULL DEFAULT FALSE,
+    max_occupancy INT NOT NULL DEFAULT 2 CHECK (max_occupancy > 0),
+    description TEXT,
+    -- Natural key of inventory imports
+    UNIQUE (hotel_id, name),
     FOREIGN KEY (hotel_id) REFERENCES hotels(id) ON DELETE CASCADE
-);
\ No newline at end of file
+);
+
+CREATE TYPE bed_type AS ENUM ('SINGLE', 'DOUBLE', 'QUEEN', 'KING', 'SOFA_BED', 'BUNK');
+
+CREATE TABLE room_type_beds (
+    room_type_id UUID NOT NULL,
+    bed_type bed_type NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (room_type_id, bed_type),
+    FOREIGN KEY (room_type_id) REFERENCES room_types(id) ON DELETE CASCADE
+);
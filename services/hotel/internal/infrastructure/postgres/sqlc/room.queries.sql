@@ -1,26 +1,13 @@
--- name: GetRoomsByHotelId :many
-SELECT *
-FROM rooms r
-WHERE r.hotel_id = $1
-ORDER BY r.name
-LIMIT 20;
-
--- name: GetRoomsByRoomTypeId :many
-SELECT *
-FROM rooms r
-WHERE r.room_type_id = $1
-ORDER BY r.name
-LIMIT 20;
-
 -- name: GetNumberOfRoomsPerRoomTypeByHotelIds :many
 SELECT 
     r.room_type_id,
+    r.hotel_id,
     COUNT(r.id) AS total_rooms
 FROM rooms r
 WHERE 
     r.hotel_id = ANY(@hotel_ids::uuid[])
     AND r.status != 'MAINTAINED'
-GROUP BY r.room_type_id;
+GROUP BY r.room_type_id, r.hotel_id;
 
 -- name: GetListOfAvailableRoomsByRoomTypeId :many
 SELECT r.id
@@ -31,7 +18,7 @@ ORDER BY r.name
 LIMIT @number_of_rooms::int;
 
 -- name: GetListOfRemainRooms :many
-SELECT id
+SELECT id, name
 FROM rooms
 WHERE
     room_type_id = @room_type_id::uuid
@@ -39,8 +26,7 @@ WHERE
         @booked_room_ids::uuid[] IS NULL
         OR id <> ALL(@booked_room_ids::uuid[])
     )
-ORDER BY name ASC
-LIMIT @number_of_rooms::int;
+ORDER BY name ASC;
 
 -- name: GetRoomsById :one
 SELECT *
@@ -58,7 +44,7 @@ INSERT INTO rooms
 VALUES
 (
     @name::text,
-    @status::text,
+    @status::room_status,
     @hotel_id::uuid,
     @room_type_id::uuid
 );
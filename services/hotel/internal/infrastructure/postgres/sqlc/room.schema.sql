@@ -6,6 +6,8 @@ CREATE TABLE rooms (
     status room_status,
     room_type_id UUID NOT NULL,
     hotel_id UUID NOT NULL,
+    -- Natural key of inventory imports
+    UNIQUE (hotel_id, name),
     FOREIGN KEY (hotel_id) REFERENCES hotels(id) ON DELETE CASCADE,
     FOREIGN KEY (room_type_id) REFERENCES room_types(id) ON DELETE CASCADE
 );
\ No newline at end of file
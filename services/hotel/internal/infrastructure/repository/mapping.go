@@ -1,18 +1,62 @@
 package hotel_repo_mapping
 
 import (
+	"time"
+
 	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	amenity_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/amenity"
 	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
+	hotel_policy_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel-policy"
 	room_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/room"
 	room_type_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/room-type"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func FromHotelRepoToHotelDomain(hotelRepo hotel_repo.Hotel) hotel_domain.Hotel {
 
 	return hotel_domain.Hotel{
-		Id:      hotelRepo.ID.String(),
-		Name:    hotelRepo.Name,
-		Address: hotelRepo.Address.String,
+		Id:          hotelRepo.ID.String(),
+		Name:        hotelRepo.Name,
+		Address:     hotelRepo.Address.String,
+		City:        hotelRepo.City.String,
+		Description: hotelRepo.Description.String,
+		Location:    FromPgLocationToGeoPoint(hotelRepo.Latitude, hotelRepo.Longitude),
+		Status:      string(hotelRepo.Status),
+		CreatedBy:   hotelRepo.CreatedBy.String(),
+		ChainId:     hotelRepo.ChainID.String(),
+	}
+}
+
+func FromHotelChainRepoToHotelChainDomain(chainRepo hotel_repo.HotelChain) hotel_domain.HotelChain {
+	return hotel_domain.HotelChain{
+		Id:          chainRepo.ID.String(),
+		Name:        chainRepo.Name,
+		Brand:       chainRepo.Brand.String,
+		Description: chainRepo.Description.String,
+		CreateAt:    chainRepo.CreateAt.Time,
+	}
+}
+
+func FromHotelStatusEventRepoToHotelStatusEventDomain(eventRepo hotel_repo.HotelStatusEvent) hotel_domain.HotelStatusEvent {
+	return hotel_domain.HotelStatusEvent{
+		Id:         eventRepo.ID.String(),
+		HotelId:    eventRepo.HotelID.String(),
+		FromStatus: string(eventRepo.FromStatus),
+		ToStatus:   string(eventRepo.ToStatus),
+		Actor:      eventRepo.Actor.String,
+		Comment:    eventRepo.Comment.String,
+		CreateAt:   eventRepo.CreateAt.Time,
+	}
+}
+
+func FromPgLocationToGeoPoint(latitude pgtype.Float8, longitude pgtype.Float8) *hotel_domain.GeoPoint {
+	if !latitude.Valid || !longitude.Valid {
+		return nil
+	}
+
+	return &hotel_domain.GeoPoint{
+		Latitude:  latitude.Float64,
+		Longitude: longitude.Float64,
 	}
 }
 
@@ -29,13 +73,53 @@ func FromHotelsRepoToHotelsDomain(hotelsRepo []hotel_repo.Hotel) []hotel_domain.
 
 func FromRoomTypeRepoToRoomTypeDomain(roomTypeRepo room_type_repo.RoomType) hotel_domain.RoomType {
 	return hotel_domain.RoomType{
-		Id:      roomTypeRepo.ID.String(),
-		Name:    roomTypeRepo.Name,
-		Price:   int(roomTypeRepo.Price),
-		HotelId: roomTypeRepo.HotelID.String(),
+		Id:             roomTypeRepo.ID.String(),
+		Name:           roomTypeRepo.Name,
+		Price:          int(roomTypeRepo.Price),
+		HotelId:        roomTypeRepo.HotelID.String(),
+		UpgradeRank:    int(roomTypeRepo.UpgradeRank.Int32),
+		AreaSqm:        int(roomTypeRepo.AreaSqm.Int32),
+		View:           string(roomTypeRepo.View),
+		SmokingAllowed: roomTypeRepo.SmokingAllowed,
+		MaxOccupancy:   int(roomTypeRepo.MaxOccupancy),
+		Description:    roomTypeRepo.Description.String,
+		Beds:           []hotel_domain.RoomTypeBed{},
 	}
 }
 
+func FromHotelRoomTypeRepoToHotelRoomTypeDomain(roomTypeRepo room_type_repo.RoomType, numberOfRooms int64) hotel_domain.HotelRoomType {
+	return hotel_domain.HotelRoomType{
+		RoomType: hotel_domain.RoomType{
+			Id:             roomTypeRepo.ID.String(),
+			Name:           roomTypeRepo.Name,
+			Price:          int(roomTypeRepo.Price),
+			HotelId:        roomTypeRepo.HotelID.String(),
+			AreaSqm:        int(roomTypeRepo.AreaSqm.Int32),
+			View:           string(roomTypeRepo.View),
+			SmokingAllowed: roomTypeRepo.SmokingAllowed,
+			MaxOccupancy:   int(roomTypeRepo.MaxOccupancy),
+			Description:    roomTypeRepo.Description.String,
+			Beds:           []hotel_domain.RoomTypeBed{},
+		},
+		NumberOfRooms: int(numberOfRooms),
+	}
+}
+
+// Beds grouped by room type id
+func FromRoomTypeBedsRepoToRoomTypeBedsDomain(bedsRepo []room_type_repo.RoomTypeBed) map[string][]hotel_domain.RoomTypeBed {
+	beds := make(map[string][]hotel_domain.RoomTypeBed)
+
+	for _, b := range bedsRepo {
+		roomTypeId := b.RoomTypeID.String()
+		beds[roomTypeId] = append(beds[roomTypeId], hotel_domain.RoomTypeBed{
+			BedType:  string(b.BedType),
+			Quantity: int(b.Quantity),
+		})
+	}
+
+	return beds
+}
+
 func FromRoomTypesRepoToRoomTypesDomain(roomTypesRepo []room_type_repo.RoomType) []hotel_domain.RoomType {
 	roomTypes := make([]hotel_domain.RoomType, 0, len(roomTypesRepo))
 
@@ -46,6 +130,27 @@ func FromRoomTypesRepoToRoomTypesDomain(roomTypesRepo []room_type_repo.RoomType)
 	return roomTypes
 }
 
+func FromOverbookingLimitRepoToOverbookingLimitDomain(limitRepo room_type_repo.RoomTypeOverbookingLimit) hotel_domain.OverbookingLimit {
+	return hotel_domain.OverbookingLimit{
+		Id:         limitRepo.ID.String(),
+		RoomTypeId: limitRepo.RoomTypeID.String(),
+		StartDate:  limitRepo.StartDate.Time,
+		EndDate:    limitRepo.EndDate.Time,
+		LimitType:  string(limitRepo.LimitType),
+		LimitValue: int(limitRepo.LimitValue),
+	}
+}
+
+func FromOverbookingLimitsRepoToOverbookingLimitsDomain(limitsRepo []room_type_repo.RoomTypeOverbookingLimit) []hotel_domain.OverbookingLimit {
+	limits := make([]hotel_domain.OverbookingLimit, 0, len(limitsRepo))
+
+	for _, l := range limitsRepo {
+		limits = append(limits, FromOverbookingLimitRepoToOverbookingLimitDomain(l))
+	}
+
+	return limits
+}
+
 func FromRoomRepoToRoomDomain(roomRepo room_repo.Room) hotel_domain.Room {
 
 	return hotel_domain.Room{
@@ -66,3 +171,76 @@ func FromRoomsRepoToRoomsDomain(roomsRepo []room_repo.Room) []hotel_domain.Room
 
 	return rooms
 }
+
+// Amenities with their labels, labels of other amenities are ignored
+func FromAmenitiesRepoToAmenitiesDomain(amenitiesRepo []amenity_repo.Amenity, labelsRepo []amenity_repo.AmenityLabel) []hotel_domain.Amenity {
+
+	labelsByAmenityId := make(map[string]map[string]string, len(amenitiesRepo))
+	for _, l := range labelsRepo {
+		amenityId := l.AmenityID.String()
+		if labelsByAmenityId[amenityId] == nil {
+			labelsByAmenityId[amenityId] = make(map[string]string)
+		}
+		labelsByAmenityId[amenityId][l.Locale] = l.Label
+	}
+
+	amenities := make([]hotel_domain.Amenity, 0, len(amenitiesRepo))
+	for _, a := range amenitiesRepo {
+		labels := labelsByAmenityId[a.ID.String()]
+		if labels == nil {
+			labels = map[string]string{}
+		}
+
+		amenities = append(amenities, hotel_domain.Amenity{
+			Id:       a.ID.String(),
+			Code:     a.Code,
+			Category: string(a.Category),
+			Labels:   labels,
+		})
+	}
+
+	return amenities
+}
+
+func FromHotelPolicyRepoToHotelPolicyDomain(policyRepo hotel_policy_repo.HotelPolicy, textsRepo []hotel_policy_repo.HotelPolicyText) hotel_domain.HotelPolicy {
+
+	houseRules := make(map[string]string, len(textsRepo))
+	for _, t := range textsRepo {
+		houseRules[t.Locale] = t.HouseRules
+	}
+
+	var childrenFreeMaxAge *int
+	if policyRepo.ChildrenFreeMaxAge.Valid {
+		age := int(policyRepo.ChildrenFreeMaxAge.Int32)
+		childrenFreeMaxAge = &age
+	}
+
+	paymentMethods := policyRepo.PaymentMethods
+	if paymentMethods == nil {
+		paymentMethods = []string{}
+	}
+
+	return hotel_domain.HotelPolicy{
+		HotelId:            policyRepo.HotelID.String(),
+		CheckInFrom:        FromPgTimeToClock(policyRepo.CheckInFrom),
+		CheckInUntil:       FromPgTimeToClock(policyRepo.CheckInUntil),
+		CheckOutUntil:      FromPgTimeToClock(policyRepo.CheckOutUntil),
+		PetsAllowed:        policyRepo.PetsAllowed,
+		PetFee:             int(policyRepo.PetFee),
+		ChildrenAllowed:    policyRepo.ChildrenAllowed,
+		ChildrenFreeMaxAge: childrenFreeMaxAge,
+		ExtraBedAvailable:  policyRepo.ExtraBedAvailable,
+		ExtraBedFee:        int(policyRepo.ExtraBedFee),
+		PaymentMethods:     paymentMethods,
+		HouseRules:         houseRules,
+	}
+}
+
+// HH:MM of a time of day, empty when the time is NULL
+func FromPgTimeToClock(t pgtype.Time) string {
+	if !t.Valid {
+		return ""
+	}
+
+	return time.Time{}.Add(time.Duration(t.Microseconds) * time.Microsecond).Format("15:04")
+}
@@ -2,7 +2,7 @@
 // versions:
 //   sqlc v1.29.0
 
-package booking_repo
+package amenity_repo
 
 import (
 	"context"
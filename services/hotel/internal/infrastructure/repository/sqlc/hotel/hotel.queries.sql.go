@@ -11,79 +11,34 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createHotel = `-- name: CreateHotel :exec
-INSERT INTO hotels (name, address) VALUES ($1::text, $2::text)
-`
-
-type CreateHotelParams struct {
-	Name    pgtype.Text `json:"name"`
-	Address pgtype.Text `json:"address"`
-}
-
-func (q *Queries) CreateHotel(ctx context.Context, arg CreateHotelParams) error {
-	_, err := q.db.Exec(ctx, createHotel, arg.Name, arg.Address)
-	return err
-}
-
-const deleteHotelById = `-- name: DeleteHotelById :exec
-DELETE FROM hotels WHERE id = $1
-`
-
-func (q *Queries) DeleteHotelById(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteHotelById, id)
-	return err
-}
-
-const filterHotels = `-- name: FilterHotels :many
-SELECT 
-    h.id,
-    h.name,
-    h.address,
-    MIN(rt.price) AS min_price
-FROM hotels h LEFT JOIN room_types rt ON h.id = rt.hotel_id
-WHERE 
-    rt.id = ANY($1::uuid[])
-    AND 
-    (
-        $2::int IS NULL
-        OR $3::int IS NULL
-        OR rt.price BETWEEN $2 AND $3
-    )
-GROUP BY h.id
-HAVING MIN(rt.price) > 0
+const autocompleteCities = `-- name: AutocompleteCities :many
+SELECT DISTINCT city
+FROM hotels
+WHERE lower(immutable_unaccent(city)) LIKE lower(immutable_unaccent($1::text)) || '%'
+    AND status = 'PUBLISHED'
+ORDER BY city
+LIMIT $2::int
 `
 
-type FilterHotelsParams struct {
-	RoomTypeIds []pgtype.UUID `json:"room_type_ids"`
-	MinPrice    pgtype.Int4   `json:"min_price"`
-	MaxPrice    pgtype.Int4   `json:"max_price"`
-}
-
-type FilterHotelsRow struct {
-	ID       pgtype.UUID `json:"id"`
-	Name     string      `json:"name"`
-	Address  pgtype.Text `json:"address"`
-	MinPrice interface{} `json:"min_price"`
+type AutocompleteCitiesParams struct {
+	Prefix     pgtype.Text `json:"prefix"`
+	MaxResults int32       `json:"max_results"`
 }
 
-func (q *Queries) FilterHotels(ctx context.Context, arg FilterHotelsParams) ([]FilterHotelsRow, error) {
-	rows, err := q.db.Query(ctx, filterHotels, arg.RoomTypeIds, arg.MinPrice, arg.MaxPrice)
+// Cities of published hotels starting with the prefix, LIKE wildcards of the prefix are escaped by the service
+func (q *Queries) AutocompleteCities(ctx context.Context, arg AutocompleteCitiesParams) ([]pgtype.Text, error) {
+	rows, err := q.db.Query(ctx, autocompleteCities, arg.Prefix, arg.MaxResults)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []FilterHotelsRow
+	var items []pgtype.Text
 	for rows.Next() {
-		var i FilterHotelsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Address,
-			&i.MinPrice,
-		); err != nil {
+		var city pgtype.Text
+		if err := rows.Scan(&city); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, city)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -91,34 +46,71 @@ func (q *Queries) FilterHotels(ctx context.Context, arg FilterHotelsParams) ([]F
 	return items, nil
 }
 
-const getAll = `-- name: GetAll :many
-SELECT id, name, address 
-FROM hotels
-LIMIT 20
+const createHotel = `-- name: CreateHotel :one
+INSERT INTO hotels (name, address, city, description, latitude, longitude, created_by)
+VALUES (
+    $1::text,
+    $2::text,
+    $3::text,
+    $4::text,
+    $5::float8,
+    $6::float8,
+    $7::uuid
+)
+RETURNING id, name, address, latitude, longitude, city, description, status, created_by, chain_id
 `
 
-func (q *Queries) GetAll(ctx context.Context) ([]Hotel, error) {
-	rows, err := q.db.Query(ctx, getAll)
+type CreateHotelParams struct {
+	Name        pgtype.Text   `json:"name"`
+	Address     pgtype.Text   `json:"address"`
+	City        pgtype.Text   `json:"city"`
+	Description pgtype.Text   `json:"description"`
+	Latitude    pgtype.Float8 `json:"latitude"`
+	Longitude   pgtype.Float8 `json:"longitude"`
+	CreatedBy   pgtype.UUID   `json:"created_by"`
+}
+
+// New hotels are drafts until ADMIN approves them and they are published
+func (q *Queries) CreateHotel(ctx context.Context, arg CreateHotelParams) (Hotel, error) {
+	row := q.db.QueryRow(ctx, createHotel,
+		arg.Name,
+		arg.Address,
+		arg.City,
+		arg.Description,
+		arg.Latitude,
+		arg.Longitude,
+		arg.CreatedBy,
+	)
+	var i Hotel
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.Latitude,
+		&i.Longitude,
+		&i.City,
+		&i.Description,
+		&i.Status,
+		&i.CreatedBy,
+		&i.ChainID,
+	)
+	return i, err
+}
+
+const deleteHotelById = `-- name: DeleteHotelById :execrows
+DELETE FROM hotels WHERE id = $1
+`
+
+func (q *Queries) DeleteHotelById(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteHotelById, id)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Hotel
-	for rows.Next() {
-		var i Hotel
-		if err := rows.Scan(&i.ID, &i.Name, &i.Address); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected(), nil
 }
 
 const getHotelById = `-- name: GetHotelById :one
-SELECT id, name, address 
+SELECT id, name, address, latitude, longitude, city, description, status, created_by, chain_id 
 FROM hotels 
 WHERE id = $1
 `
@@ -126,66 +118,98 @@ WHERE id = $1
 func (q *Queries) GetHotelById(ctx context.Context, id pgtype.UUID) (Hotel, error) {
 	row := q.db.QueryRow(ctx, getHotelById, id)
 	var i Hotel
-	err := row.Scan(&i.ID, &i.Name, &i.Address)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.Latitude,
+		&i.Longitude,
+		&i.City,
+		&i.Description,
+		&i.Status,
+		&i.CreatedBy,
+		&i.ChainID,
+	)
 	return i, err
 }
 
-const getHotelsByAddress = `-- name: GetHotelsByAddress :many
-SELECT h.id
-FROM hotels h
-WHERE 
-    (
-        $1::text IS NULL
-        OR unaccent(h.address) ILIKE unaccent('%' || $1::text || '%')
-    ) 
-    AND 
-    (
-        $2::text IS NULL
-        OR unaccent(h.name) ILIKE unaccent( '%' || $2::text || '%')
-    )
-LIMIT 20
+const updateHotelById = `-- name: UpdateHotelById :one
+UPDATE hotels
+SET 
+    name = $1::text,
+    address = $2::text,
+    city = $3::text,
+    description = $4::text,
+    latitude = $5::float8,
+    longitude = $6::float8
+WHERE id = $7::uuid
+RETURNING id, name, address, latitude, longitude, city, description, status, created_by, chain_id
 `
 
-type GetHotelsByAddressParams struct {
-	Address   pgtype.Text `json:"address"`
-	HotelName pgtype.Text `json:"hotel_name"`
+type UpdateHotelByIdParams struct {
+	Name        pgtype.Text   `json:"name"`
+	Address     pgtype.Text   `json:"address"`
+	City        pgtype.Text   `json:"city"`
+	Description pgtype.Text   `json:"description"`
+	Latitude    pgtype.Float8 `json:"latitude"`
+	Longitude   pgtype.Float8 `json:"longitude"`
+	ID          pgtype.UUID   `json:"id"`
 }
 
-func (q *Queries) GetHotelsByAddress(ctx context.Context, arg GetHotelsByAddressParams) ([]pgtype.UUID, error) {
-	rows, err := q.db.Query(ctx, getHotelsByAddress, arg.Address, arg.HotelName)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []pgtype.UUID
-	for rows.Next() {
-		var id pgtype.UUID
-		if err := rows.Scan(&id); err != nil {
-			return nil, err
-		}
-		items = append(items, id)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) UpdateHotelById(ctx context.Context, arg UpdateHotelByIdParams) (Hotel, error) {
+	row := q.db.QueryRow(ctx, updateHotelById,
+		arg.Name,
+		arg.Address,
+		arg.City,
+		arg.Description,
+		arg.Latitude,
+		arg.Longitude,
+		arg.ID,
+	)
+	var i Hotel
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.Latitude,
+		&i.Longitude,
+		&i.City,
+		&i.Description,
+		&i.Status,
+		&i.CreatedBy,
+		&i.ChainID,
+	)
+	return i, err
 }
 
-const updateHotelById = `-- name: UpdateHotelById :exec
+const updateHotelStatus = `-- name: UpdateHotelStatus :one
 UPDATE hotels
-SET 
-    name = $1::text,
-    address = $2::text
-WHERE id = $3::uuid
+SET status = $1::hotel_status
+WHERE id = $2::uuid AND status::text = ANY($3::varchar[])
+RETURNING id, name, address, latitude, longitude, city, description, status, created_by, chain_id
 `
 
-type UpdateHotelByIdParams struct {
-	Name    pgtype.Text `json:"name"`
-	Address pgtype.Text `json:"address"`
-	ID      pgtype.UUID `json:"id"`
+type UpdateHotelStatusParams struct {
+	ToStatus     HotelStatus `json:"to_status"`
+	ID           pgtype.UUID `json:"id"`
+	FromStatuses []string    `json:"from_statuses"`
 }
 
-func (q *Queries) UpdateHotelById(ctx context.Context, arg UpdateHotelByIdParams) error {
-	_, err := q.db.Exec(ctx, updateHotelById, arg.Name, arg.Address, arg.ID)
-	return err
+// Only moves a hotel still in one of from_statuses, no rows when it is in another status
+func (q *Queries) UpdateHotelStatus(ctx context.Context, arg UpdateHotelStatusParams) (Hotel, error) {
+	row := q.db.QueryRow(ctx, updateHotelStatus, arg.ToStatus, arg.ID, arg.FromStatuses)
+	var i Hotel
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Address,
+		&i.Latitude,
+		&i.Longitude,
+		&i.City,
+		&i.Description,
+		&i.Status,
+		&i.CreatedBy,
+		&i.ChainID,
+	)
+	return i, err
 }
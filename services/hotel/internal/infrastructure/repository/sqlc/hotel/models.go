@@ -11,6 +11,144 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AmenityCategory string
+
+const (
+	AmenityCategoryGENERAL       AmenityCategory = "GENERAL"
+	AmenityCategoryINTERNET      AmenityCategory = "INTERNET"
+	AmenityCategoryPARKING       AmenityCategory = "PARKING"
+	AmenityCategoryWELLNESS      AmenityCategory = "WELLNESS"
+	AmenityCategoryFOODANDDRINK  AmenityCategory = "FOOD_AND_DRINK"
+	AmenityCategoryROOM          AmenityCategory = "ROOM"
+	AmenityCategoryACCESSIBILITY AmenityCategory = "ACCESSIBILITY"
+)
+
+func (e *AmenityCategory) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AmenityCategory(s)
+	case string:
+		*e = AmenityCategory(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AmenityCategory: %T", src)
+	}
+	return nil
+}
+
+type NullAmenityCategory struct {
+	AmenityCategory AmenityCategory `json:"amenity_category"`
+	Valid           bool            `json:"valid"` // Valid is true if AmenityCategory is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAmenityCategory) Scan(value interface{}) error {
+	if value == nil {
+		ns.AmenityCategory, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AmenityCategory.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAmenityCategory) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AmenityCategory), nil
+}
+
+type BedType string
+
+const (
+	BedTypeSINGLE  BedType = "SINGLE"
+	BedTypeDOUBLE  BedType = "DOUBLE"
+	BedTypeQUEEN   BedType = "QUEEN"
+	BedTypeKING    BedType = "KING"
+	BedTypeSOFABED BedType = "SOFA_BED"
+	BedTypeBUNK    BedType = "BUNK"
+)
+
+func (e *BedType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BedType(s)
+	case string:
+		*e = BedType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BedType: %T", src)
+	}
+	return nil
+}
+
+type NullBedType struct {
+	BedType BedType `json:"bed_type"`
+	Valid   bool    `json:"valid"` // Valid is true if BedType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBedType) Scan(value interface{}) error {
+	if value == nil {
+		ns.BedType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BedType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBedType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BedType), nil
+}
+
+type HotelStatus string
+
+const (
+	HotelStatusDRAFT     HotelStatus = "DRAFT"
+	HotelStatusSUBMITTED HotelStatus = "SUBMITTED"
+	HotelStatusAPPROVED  HotelStatus = "APPROVED"
+	HotelStatusREJECTED  HotelStatus = "REJECTED"
+	HotelStatusPUBLISHED HotelStatus = "PUBLISHED"
+)
+
+func (e *HotelStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = HotelStatus(s)
+	case string:
+		*e = HotelStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for HotelStatus: %T", src)
+	}
+	return nil
+}
+
+type NullHotelStatus struct {
+	HotelStatus HotelStatus `json:"hotel_status"`
+	Valid       bool        `json:"valid"` // Valid is true if HotelStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullHotelStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.HotelStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.HotelStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullHotelStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.HotelStatus), nil
+}
+
 type RoomStatus string
 
 const (
@@ -53,10 +191,104 @@ func (ns NullRoomStatus) Value() (driver.Value, error) {
 	return string(ns.RoomStatus), nil
 }
 
+type RoomView string
+
+const (
+	RoomViewNONE     RoomView = "NONE"
+	RoomViewCITY     RoomView = "CITY"
+	RoomViewSEA      RoomView = "SEA"
+	RoomViewGARDEN   RoomView = "GARDEN"
+	RoomViewMOUNTAIN RoomView = "MOUNTAIN"
+	RoomViewPOOL     RoomView = "POOL"
+	RoomViewRIVER    RoomView = "RIVER"
+)
+
+func (e *RoomView) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RoomView(s)
+	case string:
+		*e = RoomView(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RoomView: %T", src)
+	}
+	return nil
+}
+
+type NullRoomView struct {
+	RoomView RoomView `json:"room_view"`
+	Valid    bool     `json:"valid"` // Valid is true if RoomView is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRoomView) Scan(value interface{}) error {
+	if value == nil {
+		ns.RoomView, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RoomView.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRoomView) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.RoomView), nil
+}
+
+type Amenity struct {
+	ID       pgtype.UUID     `json:"id"`
+	Code     string          `json:"code"`
+	Category AmenityCategory `json:"category"`
+}
+
+type AmenityLabel struct {
+	AmenityID pgtype.UUID `json:"amenity_id"`
+	Locale    string      `json:"locale"`
+	Label     string      `json:"label"`
+}
+
 type Hotel struct {
-	ID      pgtype.UUID `json:"id"`
-	Name    string      `json:"name"`
-	Address pgtype.Text `json:"address"`
+	ID          pgtype.UUID   `json:"id"`
+	Name        string        `json:"name"`
+	Address     pgtype.Text   `json:"address"`
+	Latitude    pgtype.Float8 `json:"latitude"`
+	Longitude   pgtype.Float8 `json:"longitude"`
+	City        pgtype.Text   `json:"city"`
+	Description pgtype.Text   `json:"description"`
+	Status      HotelStatus   `json:"status"`
+	CreatedBy   pgtype.UUID   `json:"created_by"`
+	ChainID     pgtype.UUID   `json:"chain_id"`
+}
+
+type HotelAmenity struct {
+	HotelID   pgtype.UUID `json:"hotel_id"`
+	AmenityID pgtype.UUID `json:"amenity_id"`
+}
+
+type HotelChain struct {
+	ID          pgtype.UUID      `json:"id"`
+	Name        string           `json:"name"`
+	Brand       pgtype.Text      `json:"brand"`
+	Description pgtype.Text      `json:"description"`
+	CreateAt    pgtype.Timestamp `json:"create_at"`
+}
+
+type HotelSearchDocument struct {
+	HotelID      pgtype.UUID `json:"hotel_id"`
+	SearchVector interface{} `json:"search_vector"`
+}
+
+type HotelStatusEvent struct {
+	ID         pgtype.UUID      `json:"id"`
+	HotelID    pgtype.UUID      `json:"hotel_id"`
+	FromStatus HotelStatus      `json:"from_status"`
+	ToStatus   HotelStatus      `json:"to_status"`
+	Actor      pgtype.Text      `json:"actor"`
+	Comment    pgtype.Text      `json:"comment"`
+	CreateAt   pgtype.Timestamp `json:"create_at"`
 }
 
 type Room struct {
@@ -68,8 +300,25 @@ type Room struct {
 }
 
 type RoomType struct {
-	ID      pgtype.UUID `json:"id"`
-	Name    string      `json:"name"`
-	Price   int32       `json:"price"`
-	HotelID pgtype.UUID `json:"hotel_id"`
+	ID             pgtype.UUID `json:"id"`
+	Name           string      `json:"name"`
+	Price          int32       `json:"price"`
+	HotelID        pgtype.UUID `json:"hotel_id"`
+	UpgradeRank    pgtype.Int4 `json:"upgrade_rank"`
+	AreaSqm        pgtype.Int4 `json:"area_sqm"`
+	View           RoomView    `json:"view"`
+	SmokingAllowed bool        `json:"smoking_allowed"`
+	MaxOccupancy   int32       `json:"max_occupancy"`
+	Description    pgtype.Text `json:"description"`
+}
+
+type RoomTypeAmenity struct {
+	RoomTypeID pgtype.UUID `json:"room_type_id"`
+	AmenityID  pgtype.UUID `json:"amenity_id"`
+}
+
+type RoomTypeBed struct {
+	RoomTypeID pgtype.UUID `json:"room_type_id"`
+	BedType    BedType     `json:"bed_type"`
+	Quantity   int32       `json:"quantity"`
 }
@@ -11,6 +11,94 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type BedType string
+
+const (
+	BedTypeSINGLE  BedType = "SINGLE"
+	BedTypeDOUBLE  BedType = "DOUBLE"
+	BedTypeQUEEN   BedType = "QUEEN"
+	BedTypeKING    BedType = "KING"
+	BedTypeSOFABED BedType = "SOFA_BED"
+	BedTypeBUNK    BedType = "BUNK"
+)
+
+func (e *BedType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BedType(s)
+	case string:
+		*e = BedType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BedType: %T", src)
+	}
+	return nil
+}
+
+type NullBedType struct {
+	BedType BedType `json:"bed_type"`
+	Valid   bool    `json:"valid"` // Valid is true if BedType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBedType) Scan(value interface{}) error {
+	if value == nil {
+		ns.BedType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BedType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBedType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BedType), nil
+}
+
+type OverbookingLimitType string
+
+const (
+	OverbookingLimitTypeABSOLUTE OverbookingLimitType = "ABSOLUTE"
+	OverbookingLimitTypePERCENT  OverbookingLimitType = "PERCENT"
+)
+
+func (e *OverbookingLimitType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OverbookingLimitType(s)
+	case string:
+		*e = OverbookingLimitType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OverbookingLimitType: %T", src)
+	}
+	return nil
+}
+
+type NullOverbookingLimitType struct {
+	OverbookingLimitType OverbookingLimitType `json:"overbooking_limit_type"`
+	Valid                bool                 `json:"valid"` // Valid is true if OverbookingLimitType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOverbookingLimitType) Scan(value interface{}) error {
+	if value == nil {
+		ns.OverbookingLimitType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OverbookingLimitType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOverbookingLimitType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OverbookingLimitType), nil
+}
+
 type RoomStatus string
 
 const (
@@ -53,6 +141,53 @@ func (ns NullRoomStatus) Value() (driver.Value, error) {
 	return string(ns.RoomStatus), nil
 }
 
+type RoomView string
+
+const (
+	RoomViewNONE     RoomView = "NONE"
+	RoomViewCITY     RoomView = "CITY"
+	RoomViewSEA      RoomView = "SEA"
+	RoomViewGARDEN   RoomView = "GARDEN"
+	RoomViewMOUNTAIN RoomView = "MOUNTAIN"
+	RoomViewPOOL     RoomView = "POOL"
+	RoomViewRIVER    RoomView = "RIVER"
+)
+
+func (e *RoomView) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RoomView(s)
+	case string:
+		*e = RoomView(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RoomView: %T", src)
+	}
+	return nil
+}
+
+type NullRoomView struct {
+	RoomView RoomView `json:"room_view"`
+	Valid    bool     `json:"valid"` // Valid is true if RoomView is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRoomView) Scan(value interface{}) error {
+	if value == nil {
+		ns.RoomView, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RoomView.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRoomView) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.RoomView), nil
+}
+
 type Room struct {
 	ID         pgtype.UUID    `json:"id"`
 	Name       string         `json:"name"`
@@ -62,8 +197,29 @@ type Room struct {
 }
 
 type RoomType struct {
-	ID      pgtype.UUID `json:"id"`
-	Name    string      `json:"name"`
-	Price   int32       `json:"price"`
-	HotelID pgtype.UUID `json:"hotel_id"`
+	ID             pgtype.UUID `json:"id"`
+	Name           string      `json:"name"`
+	Price          int32       `json:"price"`
+	HotelID        pgtype.UUID `json:"hotel_id"`
+	UpgradeRank    pgtype.Int4 `json:"upgrade_rank"`
+	AreaSqm        pgtype.Int4 `json:"area_sqm"`
+	View           RoomView    `json:"view"`
+	SmokingAllowed bool        `json:"smoking_allowed"`
+	MaxOccupancy   int32       `json:"max_occupancy"`
+	Description    pgtype.Text `json:"description"`
+}
+
+type RoomTypeBed struct {
+	RoomTypeID pgtype.UUID `json:"room_type_id"`
+	BedType    BedType     `json:"bed_type"`
+	Quantity   int32       `json:"quantity"`
+}
+
+type RoomTypeOverbookingLimit struct {
+	ID         pgtype.UUID          `json:"id"`
+	RoomTypeID pgtype.UUID          `json:"room_type_id"`
+	StartDate  pgtype.Date          `json:"start_date"`
+	EndDate    pgtype.Date          `json:"end_date"`
+	LimitType  OverbookingLimitType `json:"limit_type"`
+	LimitValue int32                `json:"limit_value"`
 }
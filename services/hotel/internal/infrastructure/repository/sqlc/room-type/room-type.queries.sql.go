@@ -11,30 +11,150 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createRoomType = `-- name: CreateRoomType :exec
+const countRoomsByRoomTypeId = `-- name: CountRoomsByRoomTypeId :one
+SELECT COUNT(r.id) AS total_rooms
+FROM rooms r
+WHERE 
+    r.room_type_id = $1::uuid
+    AND r.status != 'MAINTAINED'
+`
+
+func (q *Queries) CountRoomsByRoomTypeId(ctx context.Context, roomTypeID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countRoomsByRoomTypeId, roomTypeID)
+	var total_rooms int64
+	err := row.Scan(&total_rooms)
+	return total_rooms, err
+}
+
+const createOverbookingLimit = `-- name: CreateOverbookingLimit :execrows
+INSERT INTO room_type_overbooking_limits
+(
+    room_type_id,
+    start_date,
+    end_date,
+    limit_type,
+    limit_value
+)
+SELECT
+    rt.id,
+    $1::date,
+    $2::date,
+    $3::overbooking_limit_type,
+    $4::int
+FROM room_types rt
+WHERE 
+    rt.id = $5::uuid
+    AND rt.hotel_id = $6::uuid
+`
+
+type CreateOverbookingLimitParams struct {
+	StartDate  pgtype.Date          `json:"start_date"`
+	EndDate    pgtype.Date          `json:"end_date"`
+	LimitType  OverbookingLimitType `json:"limit_type"`
+	LimitValue int32                `json:"limit_value"`
+	RoomTypeID pgtype.UUID          `json:"room_type_id"`
+	HotelID    pgtype.UUID          `json:"hotel_id"`
+}
+
+func (q *Queries) CreateOverbookingLimit(ctx context.Context, arg CreateOverbookingLimitParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createOverbookingLimit,
+		arg.StartDate,
+		arg.EndDate,
+		arg.LimitType,
+		arg.LimitValue,
+		arg.RoomTypeID,
+		arg.HotelID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createRoomType = `-- name: CreateRoomType :one
 INSERT INTO room_types
 (
     name,
     price,
-    hotel_id
+    hotel_id,
+    area_sqm,
+    view,
+    smoking_allowed,
+    max_occupancy,
+    description
 )
 VALUES
 (
     $1::text,
     $2::int,
-    $3::uuid
+    $3::uuid,
+    $4::int,
+    $5::room_view,
+    $6::boolean,
+    $7::int,
+    $8::text
 )
+RETURNING id, name, price, hotel_id, upgrade_rank, area_sqm, view, smoking_allowed, max_occupancy, description
 `
 
 type CreateRoomTypeParams struct {
-	Name    string      `json:"name"`
-	Price   int32       `json:"price"`
+	Name           string      `json:"name"`
+	Price          int32       `json:"price"`
+	HotelID        pgtype.UUID `json:"hotel_id"`
+	AreaSqm        pgtype.Int4 `json:"area_sqm"`
+	View           RoomView    `json:"view"`
+	SmokingAllowed bool        `json:"smoking_allowed"`
+	MaxOccupancy   int32       `json:"max_occupancy"`
+	Description    pgtype.Text `json:"description"`
+}
+
+func (q *Queries) CreateRoomType(ctx context.Context, arg CreateRoomTypeParams) (RoomType, error) {
+	row := q.db.QueryRow(ctx, createRoomType,
+		arg.Name,
+		arg.Price,
+		arg.HotelID,
+		arg.AreaSqm,
+		arg.View,
+		arg.SmokingAllowed,
+		arg.MaxOccupancy,
+		arg.Description,
+	)
+	var i RoomType
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Price,
+		&i.HotelID,
+		&i.UpgradeRank,
+		&i.AreaSqm,
+		&i.View,
+		&i.SmokingAllowed,
+		&i.MaxOccupancy,
+		&i.Description,
+	)
+	return i, err
+}
+
+const deleteOverbookingLimitById = `-- name: DeleteOverbookingLimitById :execrows
+DELETE FROM room_type_overbooking_limits ol
+USING room_types rt
+WHERE 
+    ol.id = $1::uuid
+    AND ol.room_type_id = rt.id
+    AND rt.hotel_id = $2::uuid
+`
+
+type DeleteOverbookingLimitByIdParams struct {
+	ID      pgtype.UUID `json:"id"`
 	HotelID pgtype.UUID `json:"hotel_id"`
 }
 
-func (q *Queries) CreateRoomType(ctx context.Context, arg CreateRoomTypeParams) error {
-	_, err := q.db.Exec(ctx, createRoomType, arg.Name, arg.Price, arg.HotelID)
-	return err
+func (q *Queries) DeleteOverbookingLimitById(ctx context.Context, arg DeleteOverbookingLimitByIdParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOverbookingLimitById, arg.ID, arg.HotelID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const deleteRoomTypeById = `-- name: DeleteRoomTypeById :exec
@@ -47,8 +167,111 @@ func (q *Queries) DeleteRoomTypeById(ctx context.Context, id pgtype.UUID) error
 	return err
 }
 
+const getOverbookingLimitsByRoomTypeId = `-- name: GetOverbookingLimitsByRoomTypeId :many
+SELECT id, room_type_id, start_date, end_date, limit_type, limit_value
+FROM room_type_overbooking_limits
+WHERE room_type_id = $1
+ORDER BY start_date
+`
+
+func (q *Queries) GetOverbookingLimitsByRoomTypeId(ctx context.Context, roomTypeID pgtype.UUID) ([]RoomTypeOverbookingLimit, error) {
+	rows, err := q.db.Query(ctx, getOverbookingLimitsByRoomTypeId, roomTypeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomTypeOverbookingLimit
+	for rows.Next() {
+		var i RoomTypeOverbookingLimit
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomTypeID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.LimitType,
+			&i.LimitValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOverbookingLimitsInRange = `-- name: GetOverbookingLimitsInRange :many
+SELECT id, room_type_id, start_date, end_date, limit_type, limit_value
+FROM room_type_overbooking_limits
+WHERE 
+    room_type_id = $1::uuid
+    AND daterange(start_date, end_date, '[)') && daterange($2::date, $3::date, '[)')
+ORDER BY start_date
+`
+
+type GetOverbookingLimitsInRangeParams struct {
+	RoomTypeID pgtype.UUID `json:"room_type_id"`
+	CheckIn    pgtype.Date `json:"check_in"`
+	CheckOut   pgtype.Date `json:"check_out"`
+}
+
+func (q *Queries) GetOverbookingLimitsInRange(ctx context.Context, arg GetOverbookingLimitsInRangeParams) ([]RoomTypeOverbookingLimit, error) {
+	rows, err := q.db.Query(ctx, getOverbookingLimitsInRange, arg.RoomTypeID, arg.CheckIn, arg.CheckOut)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomTypeOverbookingLimit
+	for rows.Next() {
+		var i RoomTypeOverbookingLimit
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomTypeID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.LimitType,
+			&i.LimitValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoomTypeBedsByRoomTypeIds = `-- name: GetRoomTypeBedsByRoomTypeIds :many
+SELECT room_type_id, bed_type, quantity
+FROM room_type_beds
+WHERE room_type_id = ANY($1::uuid[])
+ORDER BY room_type_id, bed_type
+`
+
+func (q *Queries) GetRoomTypeBedsByRoomTypeIds(ctx context.Context, roomTypeIds []pgtype.UUID) ([]RoomTypeBed, error) {
+	rows, err := q.db.Query(ctx, getRoomTypeBedsByRoomTypeIds, roomTypeIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomTypeBed
+	for rows.Next() {
+		var i RoomTypeBed
+		if err := rows.Scan(&i.RoomTypeID, &i.BedType, &i.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRoomTypeById = `-- name: GetRoomTypeById :one
-SELECT id, name, price, hotel_id
+SELECT id, name, price, hotel_id, upgrade_rank, area_sqm, view, smoking_allowed, max_occupancy, description
 FROM room_types
 WHERE id = $1
 `
@@ -61,47 +284,45 @@ func (q *Queries) GetRoomTypeById(ctx context.Context, id pgtype.UUID) (RoomType
 		&i.Name,
 		&i.Price,
 		&i.HotelID,
+		&i.UpgradeRank,
+		&i.AreaSqm,
+		&i.View,
+		&i.SmokingAllowed,
+		&i.MaxOccupancy,
+		&i.Description,
 	)
 	return i, err
 }
 
-const getRoomTypesByHotelId = `-- name: GetRoomTypesByHotelId :many
-SELECT 
-    rt.id,
-    rt.name,
-    rt.price,
-    rt.hotel_id,
-    COUNT(r.id) AS number_of_rooms 
-FROM room_types rt LEFT JOIN rooms r ON rt.id = r.room_type_id
-WHERE rt.hotel_id = $1
-GROUP BY rt.id
-ORDER BY rt.name
-LIMIT 10
+const getUpgradeRoomTypesByRoomTypeId = `-- name: GetUpgradeRoomTypesByRoomTypeId :many
+SELECT rt.id, rt.name, rt.price, rt.hotel_id, rt.upgrade_rank, rt.area_sqm, rt.view, rt.smoking_allowed, rt.max_occupancy, rt.description
+FROM room_types rt JOIN room_types origin ON rt.hotel_id = origin.hotel_id
+WHERE 
+    origin.id = $1::uuid
+    AND rt.upgrade_rank > origin.upgrade_rank
+ORDER BY rt.upgrade_rank
 `
 
-type GetRoomTypesByHotelIdRow struct {
-	ID            pgtype.UUID `json:"id"`
-	Name          string      `json:"name"`
-	Price         int32       `json:"price"`
-	HotelID       pgtype.UUID `json:"hotel_id"`
-	NumberOfRooms int64       `json:"number_of_rooms"`
-}
-
-func (q *Queries) GetRoomTypesByHotelId(ctx context.Context, hotelID pgtype.UUID) ([]GetRoomTypesByHotelIdRow, error) {
-	rows, err := q.db.Query(ctx, getRoomTypesByHotelId, hotelID)
+func (q *Queries) GetUpgradeRoomTypesByRoomTypeId(ctx context.Context, roomTypeID pgtype.UUID) ([]RoomType, error) {
+	rows, err := q.db.Query(ctx, getUpgradeRoomTypesByRoomTypeId, roomTypeID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetRoomTypesByHotelIdRow
+	var items []RoomType
 	for rows.Next() {
-		var i GetRoomTypesByHotelIdRow
+		var i RoomType
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
 			&i.Price,
 			&i.HotelID,
-			&i.NumberOfRooms,
+			&i.UpgradeRank,
+			&i.AreaSqm,
+			&i.View,
+			&i.SmokingAllowed,
+			&i.MaxOccupancy,
+			&i.Description,
 		); err != nil {
 			return nil, err
 		}
@@ -112,3 +333,100 @@ func (q *Queries) GetRoomTypesByHotelId(ctx context.Context, hotelID pgtype.UUID
 	}
 	return items, nil
 }
+
+const setRoomTypeBeds = `-- name: SetRoomTypeBeds :exec
+WITH removed AS (
+    DELETE FROM room_type_beds
+    WHERE room_type_id = $1::uuid AND bed_type::text <> ALL($2::text[])
+)
+INSERT INTO room_type_beds (room_type_id, bed_type, quantity)
+SELECT $1::uuid, unnest($2::text[])::bed_type, unnest($3::int[])
+ON CONFLICT (room_type_id, bed_type) DO UPDATE SET quantity = EXCLUDED.quantity
+`
+
+type SetRoomTypeBedsParams struct {
+	RoomTypeID pgtype.UUID `json:"room_type_id"`
+	BedTypes   []string    `json:"bed_types"`
+	Quantities []int32     `json:"quantities"`
+}
+
+// Beds of the room type become exactly the given ones, bed_types[i] has quantities[i] beds
+func (q *Queries) SetRoomTypeBeds(ctx context.Context, arg SetRoomTypeBedsParams) error {
+	_, err := q.db.Exec(ctx, setRoomTypeBeds, arg.RoomTypeID, arg.BedTypes, arg.Quantities)
+	return err
+}
+
+const setRoomTypeUpgradeRank = `-- name: SetRoomTypeUpgradeRank :execrows
+UPDATE room_types
+SET upgrade_rank = $1::int
+WHERE id = $2::uuid AND hotel_id = $3::uuid
+`
+
+type SetRoomTypeUpgradeRankParams struct {
+	UpgradeRank pgtype.Int4 `json:"upgrade_rank"`
+	ID          pgtype.UUID `json:"id"`
+	HotelID     pgtype.UUID `json:"hotel_id"`
+}
+
+func (q *Queries) SetRoomTypeUpgradeRank(ctx context.Context, arg SetRoomTypeUpgradeRankParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setRoomTypeUpgradeRank, arg.UpgradeRank, arg.ID, arg.HotelID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateRoomTypeById = `-- name: UpdateRoomTypeById :one
+UPDATE room_types
+SET
+    name = $1::text,
+    price = $2::int,
+    area_sqm = $3::int,
+    view = $4::room_view,
+    smoking_allowed = $5::boolean,
+    max_occupancy = $6::int,
+    description = $7::text
+WHERE id = $8::uuid AND hotel_id = $9::uuid
+RETURNING id, name, price, hotel_id, upgrade_rank, area_sqm, view, smoking_allowed, max_occupancy, description
+`
+
+type UpdateRoomTypeByIdParams struct {
+	Name           string      `json:"name"`
+	Price          int32       `json:"price"`
+	AreaSqm        pgtype.Int4 `json:"area_sqm"`
+	View           RoomView    `json:"view"`
+	SmokingAllowed bool        `json:"smoking_allowed"`
+	MaxOccupancy   int32       `json:"max_occupancy"`
+	Description    pgtype.Text `json:"description"`
+	ID             pgtype.UUID `json:"id"`
+	HotelID        pgtype.UUID `json:"hotel_id"`
+}
+
+// Only a room type of the hotel is updated
+func (q *Queries) UpdateRoomTypeById(ctx context.Context, arg UpdateRoomTypeByIdParams) (RoomType, error) {
+	row := q.db.QueryRow(ctx, updateRoomTypeById,
+		arg.Name,
+		arg.Price,
+		arg.AreaSqm,
+		arg.View,
+		arg.SmokingAllowed,
+		arg.MaxOccupancy,
+		arg.Description,
+		arg.ID,
+		arg.HotelID,
+	)
+	var i RoomType
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Price,
+		&i.HotelID,
+		&i.UpgradeRank,
+		&i.AreaSqm,
+		&i.View,
+		&i.SmokingAllowed,
+		&i.MaxOccupancy,
+		&i.Description,
+	)
+	return i, err
+}
@@ -41,7 +41,7 @@ INSERT INTO rooms
 VALUES
 (
     $1::text,
-    $2::text,
+    $2::room_status,
     $3::uuid,
     $4::uuid
 )
@@ -49,7 +49,7 @@ VALUES
 
 type CreateRoomParams struct {
 	Name       string      `json:"name"`
-	Status     string      `json:"status"`
+	Status     RoomStatus  `json:"status"`
 	HotelID    pgtype.UUID `json:"hotel_id"`
 	RoomTypeID pgtype.UUID `json:"room_type_id"`
 }
@@ -109,7 +109,7 @@ func (q *Queries) GetListOfAvailableRoomsByRoomTypeId(ctx context.Context, arg G
 }
 
 const getListOfRemainRooms = `-- name: GetListOfRemainRooms :many
-SELECT id
+SELECT id, name
 FROM rooms
 WHERE
     room_type_id = $1::uuid
@@ -118,28 +118,31 @@ WHERE
         OR id <> ALL($2::uuid[])
     )
 ORDER BY name ASC
-LIMIT $3::int
 `
 
 type GetListOfRemainRoomsParams struct {
 	RoomTypeID    pgtype.UUID   `json:"room_type_id"`
 	BookedRoomIds []pgtype.UUID `json:"booked_room_ids"`
-	NumberOfRooms int32         `json:"number_of_rooms"`
 }
 
-func (q *Queries) GetListOfRemainRooms(ctx context.Context, arg GetListOfRemainRoomsParams) ([]pgtype.UUID, error) {
-	rows, err := q.db.Query(ctx, getListOfRemainRooms, arg.RoomTypeID, arg.BookedRoomIds, arg.NumberOfRooms)
+type GetListOfRemainRoomsRow struct {
+	ID   pgtype.UUID `json:"id"`
+	Name string      `json:"name"`
+}
+
+func (q *Queries) GetListOfRemainRooms(ctx context.Context, arg GetListOfRemainRoomsParams) ([]GetListOfRemainRoomsRow, error) {
+	rows, err := q.db.Query(ctx, getListOfRemainRooms, arg.RoomTypeID, arg.BookedRoomIds)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []pgtype.UUID
+	var items []GetListOfRemainRoomsRow
 	for rows.Next() {
-		var id pgtype.UUID
-		if err := rows.Scan(&id); err != nil {
+		var i GetListOfRemainRoomsRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
 			return nil, err
 		}
-		items = append(items, id)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -150,16 +153,18 @@ func (q *Queries) GetListOfRemainRooms(ctx context.Context, arg GetListOfRemainR
 const getNumberOfRoomsPerRoomTypeByHotelIds = `-- name: GetNumberOfRoomsPerRoomTypeByHotelIds :many
 SELECT 
     r.room_type_id,
+    r.hotel_id,
     COUNT(r.id) AS total_rooms
 FROM rooms r
 WHERE 
     r.hotel_id = ANY($1::uuid[])
     AND r.status != 'MAINTAINED'
-GROUP BY r.room_type_id
+GROUP BY r.room_type_id, r.hotel_id
 `
 
 type GetNumberOfRoomsPerRoomTypeByHotelIdsRow struct {
 	RoomTypeID pgtype.UUID `json:"room_type_id"`
+	HotelID    pgtype.UUID `json:"hotel_id"`
 	TotalRooms int64       `json:"total_rooms"`
 }
 
@@ -172,41 +177,7 @@ func (q *Queries) GetNumberOfRoomsPerRoomTypeByHotelIds(ctx context.Context, hot
 	var items []GetNumberOfRoomsPerRoomTypeByHotelIdsRow
 	for rows.Next() {
 		var i GetNumberOfRoomsPerRoomTypeByHotelIdsRow
-		if err := rows.Scan(&i.RoomTypeID, &i.TotalRooms); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getRoomsByHotelId = `-- name: GetRoomsByHotelId :many
-SELECT id, name, status, room_type_id, hotel_id
-FROM rooms r
-WHERE r.hotel_id = $1
-ORDER BY r.name
-LIMIT 20
-`
-
-func (q *Queries) GetRoomsByHotelId(ctx context.Context, hotelID pgtype.UUID) ([]Room, error) {
-	rows, err := q.db.Query(ctx, getRoomsByHotelId, hotelID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Room
-	for rows.Next() {
-		var i Room
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Status,
-			&i.RoomTypeID,
-			&i.HotelID,
-		); err != nil {
+		if err := rows.Scan(&i.RoomTypeID, &i.HotelID, &i.TotalRooms); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -235,37 +206,3 @@ func (q *Queries) GetRoomsById(ctx context.Context, id pgtype.UUID) (Room, error
 	)
 	return i, err
 }
-
-const getRoomsByRoomTypeId = `-- name: GetRoomsByRoomTypeId :many
-SELECT id, name, status, room_type_id, hotel_id
-FROM rooms r
-WHERE r.room_type_id = $1
-ORDER BY r.name
-LIMIT 20
-`
-
-func (q *Queries) GetRoomsByRoomTypeId(ctx context.Context, roomTypeID pgtype.UUID) ([]Room, error) {
-	rows, err := q.db.Query(ctx, getRoomsByRoomTypeId, roomTypeID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Room
-	for rows.Next() {
-		var i Room
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Status,
-			&i.RoomTypeID,
-			&i.HotelID,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
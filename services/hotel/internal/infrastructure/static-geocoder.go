@@ -0,0 +1,68 @@
+package hotel_infrastructure
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+)
+
+// City centres used when no geocoding provider is configured, names are lower case with and without accents
+var VIETNAM_CITY_LOCATIONS = map[string]hotel_domain.GeoPoint{
+	"hồ chí minh": {Latitude: 10.7769, Longitude: 106.7009},
+	"ho chi minh": {Latitude: 10.7769, Longitude: 106.7009},
+	"hà nội":      {Latitude: 21.0285, Longitude: 105.8542},
+	"ha noi":      {Latitude: 21.0285, Longitude: 105.8542},
+	"đà nẵng":     {Latitude: 16.0544, Longitude: 108.2022},
+	"da nang":     {Latitude: 16.0544, Longitude: 108.2022},
+	"cần thơ":     {Latitude: 10.0452, Longitude: 105.7469},
+	"can tho":     {Latitude: 10.0452, Longitude: 105.7469},
+	"nha trang":   {Latitude: 12.2388, Longitude: 109.1967},
+	"đà lạt":      {Latitude: 11.9404, Longitude: 108.4583},
+	"da lat":      {Latitude: 11.9404, Longitude: 108.4583},
+}
+
+// Geocoder answering from a fixed table of places, for tests and local runs.
+// An address matches the longest place name it contains, case is ignored
+type StaticGeocoder struct {
+	locations map[string]hotel_domain.GeoPoint
+	names     []string // longest first, so "ha noi" does not shadow a more precise place
+}
+
+func NewStaticGeocoder(locations map[string]hotel_domain.GeoPoint) *StaticGeocoder {
+	names := make([]string, 0, len(locations))
+	normalized := make(map[string]hotel_domain.GeoPoint, len(locations))
+	for name, point := range locations {
+		name = strings.ToLower(strings.TrimSpace(name))
+		normalized[name] = point
+		names = append(names, name)
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+		return strings.Compare(a, b)
+	})
+
+	return &StaticGeocoder{
+		locations: normalized,
+		names:     names,
+	}
+}
+
+func (sg *StaticGeocoder) Geocode(ctx context.Context, address string) (hotel_domain.GeoPoint, error) {
+	address = strings.ToLower(strings.TrimSpace(address))
+	if address == "" {
+		return hotel_domain.GeoPoint{}, hotel_domain.ErrAddressNotFound
+	}
+
+	for _, name := range sg.names {
+		if strings.Contains(address, name) {
+			return sg.locations[name], nil
+		}
+	}
+
+	return hotel_domain.GeoPoint{}, hotel_domain.ErrAddressNotFound
+}
@@ -0,0 +1,57 @@
+package hotel_infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+)
+
+func TestStaticGeocoder(t *testing.T) {
+	city := hotel_domain.GeoPoint{Latitude: 10, Longitude: 100}
+	district := hotel_domain.GeoPoint{Latitude: 11, Longitude: 101}
+
+	geocoder := NewStaticGeocoder(map[string]hotel_domain.GeoPoint{
+		"Ha Noi":            city,
+		" ba dinh, ha noi ": district,
+	})
+
+	tests := []struct {
+		address string
+		want    hotel_domain.GeoPoint
+		wantErr error
+	}{
+		{address: "12 Hang Bai, Ha Noi", want: city},
+		{address: "1 Hung Vuong, Ba Dinh, Ha Noi", want: district},
+		{address: "  HA NOI  ", want: city},
+		{address: "Da Nang", wantErr: hotel_domain.ErrAddressNotFound},
+		{address: "   ", wantErr: hotel_domain.ErrAddressNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			got, err := geocoder.Geocode(context.Background(), tt.address)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Geocode() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Geocode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVietnamCityLocations(t *testing.T) {
+	geocoder := NewStaticGeocoder(VIETNAM_CITY_LOCATIONS)
+
+	for _, address := range []string{"District 1, Hồ Chí Minh", "District 1, Ho Chi Minh"} {
+		got, err := geocoder.Geocode(context.Background(), address)
+		if err != nil {
+			t.Fatalf("Geocode(%q) error = %v", address, err)
+		}
+		if got != VIETNAM_CITY_LOCATIONS["ho chi minh"] {
+			t.Errorf("Geocode(%q) = %v, want %v", address, got, VIETNAM_CITY_LOCATIONS["ho chi minh"])
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package hotel_handler
+
+import (
+	"context"
+	"errors"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	amenity_service "github.com/098765432m/grpc-kafka/hotel/internal/application/amenity"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Amenity catalog, of one category when category is given
+func (hg *HotelGrpcHandler) GetAmenities(ctx context.Context, req *hotel_pb.GetAmenitiesRequest) (*hotel_pb.GetAmenitiesResponse, error) {
+
+	amenities, pageInfo, err := hg.amenityService.GetAmenities(ctx, req.GetCategory(), utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Loai tien ich hoac trang khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc danh sach tien ich")
+	}
+
+	return &hotel_pb.GetAmenitiesResponse{
+		Amenities: toAmenitiesPb(amenities),
+		Page:      utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) GetAmenityById(ctx context.Context, req *hotel_pb.GetAmenityByIdRequest) (*hotel_pb.GetAmenityByIdResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Amenity UUID")
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID tien ich")
+	}
+
+	amenity, err := hg.amenityService.GetAmenityById(ctx, id)
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Tien ich khong ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc tien ich")
+	}
+
+	return &hotel_pb.GetAmenityByIdResponse{
+		Amenity: toAmenityPb(*amenity),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) CreateAmenity(ctx context.Context, req *hotel_pb.CreateAmenityRequest) (*hotel_pb.CreateAmenityResponse, error) {
+
+	amenity, err := hg.amenityService.CreateAmenity(ctx, &amenity_service.AmenityParams{
+		Code:     req.GetCode(),
+		Category: req.GetCategory(),
+		Labels:   req.GetLabels(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Tien ich khong hop le")
+		case errors.Is(err, common_error.ErrDuplicateRecord):
+			return nil, status.Error(codes.AlreadyExists, "Ma tien ich da ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong tao duoc tien ich")
+	}
+
+	return &hotel_pb.CreateAmenityResponse{
+		Amenity: toAmenityPb(*amenity),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) UpdateAmenity(ctx context.Context, req *hotel_pb.UpdateAmenityRequest) (*hotel_pb.UpdateAmenityResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Amenity UUID")
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID tien ich")
+	}
+
+	amenity, err := hg.amenityService.UpdateAmenityById(ctx, id, &amenity_service.AmenityParams{
+		Code:     req.GetCode(),
+		Category: req.GetCategory(),
+		Labels:   req.GetLabels(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Tien ich khong hop le")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Tien ich khong ton tai")
+		case errors.Is(err, common_error.ErrDuplicateRecord):
+			return nil, status.Error(codes.AlreadyExists, "Ma tien ich da ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc tien ich")
+	}
+
+	return &hotel_pb.UpdateAmenityResponse{
+		Amenity: toAmenityPb(*amenity),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) DeleteAmenity(ctx context.Context, req *hotel_pb.DeleteAmenityRequest) (*hotel_pb.DeleteAmenityResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Amenity UUID")
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID tien ich")
+	}
+
+	if err := hg.amenityService.DeleteAmenityById(ctx, id); err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Tien ich khong ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong xoa duoc tien ich")
+	}
+
+	return &hotel_pb.DeleteAmenityResponse{}, nil
+}
+
+func (hg *HotelGrpcHandler) GetHotelAmenities(ctx context.Context, req *hotel_pb.GetHotelAmenitiesRequest) (*hotel_pb.GetHotelAmenitiesResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID")
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	amenities, err := hg.amenityService.GetHotelAmenities(ctx, hotelId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc tien ich cua khach san")
+	}
+
+	return &hotel_pb.GetHotelAmenitiesResponse{
+		Amenities: toAmenitiesPb(amenities),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) SetHotelAmenities(ctx context.Context, req *hotel_pb.SetHotelAmenitiesRequest) (*hotel_pb.SetHotelAmenitiesResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID")
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	amenities, err := hg.amenityService.SetHotelAmenities(ctx, hotelId, req.GetCodes())
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Ma tien ich khong ton tai")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Khach san khong ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc tien ich cua khach san")
+	}
+
+	return &hotel_pb.SetHotelAmenitiesResponse{
+		Amenities: toAmenitiesPb(amenities),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) GetRoomTypeAmenities(ctx context.Context, req *hotel_pb.GetRoomTypeAmenitiesRequest) (*hotel_pb.GetRoomTypeAmenitiesResponse, error) {
+
+	var roomTypeId pgtype.UUID
+	if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+		zap.S().Infoln("Invalid Room Type UUID")
+		return nil, status.Error(codes.InvalidArgument, "Room Type UUID khong hop le")
+	}
+
+	amenities, err := hg.amenityService.GetRoomTypeAmenities(ctx, roomTypeId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc tien ich cua loai phong")
+	}
+
+	return &hotel_pb.GetRoomTypeAmenitiesResponse{
+		Amenities: toAmenitiesPb(amenities),
+	}, nil
+}
+
+// Room type must be one of the hotel
+func (hg *HotelGrpcHandler) SetRoomTypeAmenities(ctx context.Context, req *hotel_pb.SetRoomTypeAmenitiesRequest) (*hotel_pb.SetRoomTypeAmenitiesResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID")
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	var roomTypeId pgtype.UUID
+	if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+		zap.S().Infoln("Invalid Room Type UUID")
+		return nil, status.Error(codes.InvalidArgument, "Room Type UUID khong hop le")
+	}
+
+	amenities, err := hg.amenityService.SetRoomTypeAmenities(ctx, hotelId, roomTypeId, req.GetCodes())
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Ma tien ich khong ton tai")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Loai phong khong ton tai trong khach san")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc tien ich cua loai phong")
+	}
+
+	return &hotel_pb.SetRoomTypeAmenitiesResponse{
+		Amenities: toAmenitiesPb(amenities),
+	}, nil
+}
+
+func toAmenityPb(amenity hotel_domain.Amenity) *hotel_pb.Amenity {
+	return &hotel_pb.Amenity{
+		Id:       amenity.Id,
+		Code:     amenity.Code,
+		Category: amenity.Category,
+		Labels:   amenity.Labels,
+	}
+}
+
+func toAmenitiesPb(amenities []hotel_domain.Amenity) []*hotel_pb.Amenity {
+	results := make([]*hotel_pb.Amenity, 0, len(amenities))
+	for _, amenity := range amenities {
+		results = append(results, toAmenityPb(amenity))
+	}
+
+	return results
+}
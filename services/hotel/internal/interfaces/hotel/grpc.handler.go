@@ -7,9 +7,13 @@ import (
 	common_error "github.com/098765432m/grpc-kafka/common/error"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
 	"github.com/098765432m/grpc-kafka/common/utils"
+	amenity_service "github.com/098765432m/grpc-kafka/hotel/internal/application/amenity"
 	hotel_service "github.com/098765432m/grpc-kafka/hotel/internal/application/hotel"
+	hotel_policy_service "github.com/098765432m/grpc-kafka/hotel/internal/application/hotel-policy"
+	inventory_service "github.com/098765432m/grpc-kafka/hotel/internal/application/inventory"
 	room_service "github.com/098765432m/grpc-kafka/hotel/internal/application/room"
 	room_type_service "github.com/098765432m/grpc-kafka/hotel/internal/application/room-type"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
 	hotel_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/hotel"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
@@ -22,39 +26,49 @@ type HotelGrpcHandler struct {
 	service         *hotel_service.HotelService
 	roomTypeService *room_type_service.RoomTypeService
 	roomService     *room_service.RoomService
+	amenityService  *amenity_service.AmenityService
+	// Policy of the hotel is returned with the hotel
+	hotelPolicyService *hotel_policy_service.HotelPolicyService
+	inventoryService   *inventory_service.InventoryService
 }
 
 func NewHotelGrpcHandler(
 	service *hotel_service.HotelService,
 	roomTypeService *room_type_service.RoomTypeService,
 	roomService *room_service.RoomService,
+	amenityService *amenity_service.AmenityService,
+	hotelPolicyService *hotel_policy_service.HotelPolicyService,
+	inventoryService *inventory_service.InventoryService,
 ) *HotelGrpcHandler {
 	return &HotelGrpcHandler{
-		service:         service,
-		roomTypeService: roomTypeService,
-		roomService:     roomService,
+		service:            service,
+		roomTypeService:    roomTypeService,
+		roomService:        roomService,
+		amenityService:     amenityService,
+		hotelPolicyService: hotelPolicyService,
+		inventoryService:   inventoryService,
 	}
 }
 
 func (hg *HotelGrpcHandler) GetAllHotels(ctx context.Context, req *hotel_pb.GetAllHotelsRequest) (*hotel_pb.GetAllHotelsResponse, error) {
-	hotels, err := hg.service.GetAll(ctx)
+	hotels, pageInfo, err := hg.service.GetAll(ctx, req.GetStatus(), utils.ToPageParams(req.GetPage()))
 	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang hoac trang thai khong hop le")
+		}
+
 		zap.S().Info("Failed to get all hotels: ", err)
 		return nil, status.Error(codes.Internal, "Loi khong lay duoc danh sach khach san")
 	}
 
 	var grpc_hotels []*hotel_pb.Hotel
 	for _, hotel := range hotels {
-		grpc_hotel := &hotel_pb.Hotel{
-			Id:   hotel.Id,
-			Name: hotel.Name,
-		}
-
-		grpc_hotels = append(grpc_hotels, grpc_hotel)
+		grpc_hotels = append(grpc_hotels, toHotelPb(hotel))
 	}
 
 	return &hotel_pb.GetAllHotelsResponse{
 		Hotels: grpc_hotels,
+		Page:   utils.ToPageResponsePb(pageInfo),
 	}, nil
 }
 
@@ -71,8 +85,11 @@ func (hg *HotelGrpcHandler) GetHotelsByAddress(ctx context.Context, req *hotel_p
 		return nil, status.Error(codes.InvalidArgument, "Ten khach san khong hop le")
 	}
 
-	hotelIds, err := hg.service.GetHotelsByAddress(ctx, address, hotelName)
+	hotelIds, pageInfo, err := hg.service.GetHotelsByAddress(ctx, address, hotelName, utils.ToPageParams(req.GetPage()))
 	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
 		return nil, status.Error(codes.Internal, "Loi khong lay duoc danh sach khach san")
 	}
 
@@ -80,6 +97,7 @@ func (hg *HotelGrpcHandler) GetHotelsByAddress(ctx context.Context, req *hotel_p
 
 	return &hotel_pb.GetHotelsByAddressResponse{
 		HotelIds: hotelIdsStr,
+		Page:     utils.ToPageResponsePb(pageInfo),
 	}, nil
 }
 
@@ -91,7 +109,7 @@ func (hg *HotelGrpcHandler) GetHotelById(ctx context.Context, req *hotel_pb.GetH
 		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
 	}
 
-	hotel, err := hg.service.GetHotelById(ctx, id)
+	hotel, err := hg.service.GetVisibleHotelById(ctx, id, requesterFromContext(ctx))
 	if err != nil {
 		switch {
 		case errors.Is(err, common_error.ErrNoRows):
@@ -102,72 +120,342 @@ func (hg *HotelGrpcHandler) GetHotelById(ctx context.Context, req *hotel_pb.GetH
 		return nil, err
 	}
 
+	// A hotel without policy yet is returned without it
+	var policyPb *hotel_pb.HotelPolicy
+	policy, err := hg.hotelPolicyService.GetHotelPolicy(ctx, id)
+	if err != nil && !errors.Is(err, common_error.ErrNoRows) {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc chinh sach khach san")
+	}
+	if policy != nil {
+		policyPb = toHotelPolicyPb(*policy)
+	}
+
 	return &hotel_pb.GetHotelByIdResponse{
-		Hotel: &hotel_pb.Hotel{
-			Id:      hotel.Id,
-			Name:    hotel.Name,
-			Address: hotel.Address,
-		},
+		Hotel:  toHotelPb(*hotel),
+		Policy: policyPb,
 	}, nil
 }
 
 func (hg *HotelGrpcHandler) CreateHotel(ctx context.Context, req *hotel_pb.CreateHotelRequest) (*hotel_pb.CreateHotelResponse, error) {
 	address, err := utils.ParsePgText(req.GetAddress())
-	if err != nil {
+	if err != nil || !address.Valid {
 		zap.S().Infoln("Address is an invalid format")
 		return nil, status.Error(codes.InvalidArgument, "Dia chi khong hop le")
 	}
 
 	hotelName, err := utils.ParsePgText(req.GetName())
-	if err != nil {
+	if err != nil || !hotelName.Valid {
 		zap.S().Infoln("Hotel Name is an invalid format")
 		return nil, status.Error(codes.InvalidArgument, "Ten khach san khong hop le")
 	}
 
-	err = hg.service.CreateHotel(ctx, &hotel_repo.CreateHotelParams{
-		Name:    hotelName,
-		Address: address,
+	// City and description are optional, empty ones are saved as NULL
+	city, err := utils.ParsePgText(req.GetCity())
+	if err != nil {
+		zap.S().Infoln("City is an invalid format")
+		return nil, status.Error(codes.InvalidArgument, "Thanh pho khong hop le")
+	}
+
+	description, err := utils.ParsePgText(req.GetDescription())
+	if err != nil {
+		zap.S().Infoln("Description is an invalid format")
+		return nil, status.Error(codes.InvalidArgument, "Mo ta khong hop le")
+	}
+
+	latitude, longitude, err := toPgLocation(req.GetLocation())
+	if err != nil {
+		zap.S().Infoln("Invalid Hotel location: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Toa do khach san khong hop le")
+	}
+
+	// Hotels created without user are drafts nobody but admins can submit
+	var createdBy pgtype.UUID
+	if req.GetCreatedBy() != "" {
+		if err := createdBy.Scan(req.GetCreatedBy()); err != nil {
+			zap.S().Infoln("Invalid Created By UUID")
+			return nil, status.Error(codes.InvalidArgument, "User UUID khong hop le")
+		}
+	}
+
+	hotel, err := hg.service.CreateHotel(ctx, &hotel_repo.CreateHotelParams{
+		Name:        hotelName,
+		Address:     address,
+		City:        city,
+		Description: description,
+		Latitude:    latitude,
+		Longitude:   longitude,
+		CreatedBy:   createdBy,
 	})
 	if err != nil {
-		return nil, err
+		return nil, status.Error(codes.Internal, "Loi khong tao duoc khach san")
 	}
-	return &hotel_pb.CreateHotelResponse{}, nil
+
+	return &hotel_pb.CreateHotelResponse{
+		Hotel: toHotelPb(*hotel),
+	}, nil
 }
 
-func (hg *HotelGrpcHandler) FilterHotels(ctx context.Context, req *hotel_pb.FilterHotelsRequest) (*hotel_pb.FilterHotelsResponse, error) {
+func (hg *HotelGrpcHandler) UpdateHotel(ctx context.Context, req *hotel_pb.UpdateHotelRequest) (*hotel_pb.UpdateHotelResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID")
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
 
-	roomTypeIds, err := utils.ToPgUuidArray(req.GetRoomTypeIds())
+	address, err := utils.ParsePgText(req.GetAddress())
+	if err != nil || !address.Valid {
+		zap.S().Infoln("Address is an invalid format")
+		return nil, status.Error(codes.InvalidArgument, "Dia chi khong hop le")
+	}
+
+	hotelName, err := utils.ParsePgText(req.GetName())
+	if err != nil || !hotelName.Valid {
+		zap.S().Infoln("Hotel Name is an invalid format")
+		return nil, status.Error(codes.InvalidArgument, "Ten khach san khong hop le")
+	}
+
+	// City and description are optional, empty ones are saved as NULL
+	city, err := utils.ParsePgText(req.GetCity())
 	if err != nil {
-		zap.S().Infoln("Invalid Room Type UUIDs")
-		return nil, status.Error(codes.InvalidArgument, "uuid room type khong hop le")
+		zap.S().Infoln("City is an invalid format")
+		return nil, status.Error(codes.InvalidArgument, "Thanh pho khong hop le")
 	}
 
-	zap.S().Infoln("Min Price REQ: ", req.GetMinPrice())
-	zap.S().Infoln("Max Price REQ: ", req.GetMaxPrice())
+	description, err := utils.ParsePgText(req.GetDescription())
+	if err != nil {
+		zap.S().Infoln("Description is an invalid format")
+		return nil, status.Error(codes.InvalidArgument, "Mo ta khong hop le")
+	}
 
-	minPrice := utils.ToPgInt4(int(req.GetMinPrice()))
-	maxPrice := utils.ToPgInt4(int(req.GetMaxPrice()))
+	latitude, longitude, err := toPgLocation(req.GetLocation())
+	if err != nil {
+		zap.S().Infoln("Invalid Hotel location: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Toa do khach san khong hop le")
+	}
 
-	zap.S().Infoln("Min Price: ", minPrice)
-	zap.S().Infoln("Max Price: ", maxPrice)
+	hotel, err := hg.service.UpdateHotelById(ctx, &hotel_repo.UpdateHotelByIdParams{
+		ID:          id,
+		Name:        hotelName,
+		Address:     address,
+		City:        city,
+		Description: description,
+		Latitude:    latitude,
+		Longitude:   longitude,
+	})
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khach san khong ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc khach san")
+	}
+
+	return &hotel_pb.UpdateHotelResponse{
+		Hotel: toHotelPb(*hotel),
+	}, nil
+}
+
+// Delete the hotel with its bookings and images
+func (hg *HotelGrpcHandler) DeleteHotel(ctx context.Context, req *hotel_pb.DeleteHotelRequest) (*hotel_pb.DeleteHotelResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID")
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	if err := hg.service.DeleteHotelById(ctx, id); err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khach san khong ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong xoa duoc khach san")
+	}
+
+	return &hotel_pb.DeleteHotelResponse{}, nil
+}
 
-	rows, err := hg.service.FilterHotels(ctx, roomTypeIds, minPrice, maxPrice)
+// Hotels with a free room type from check in to check out matching every filter
+func (hg *HotelGrpcHandler) FilterHotels(ctx context.Context, req *hotel_pb.FilterHotelsRequest) (*hotel_pb.FilterHotelsResponse, error) {
+
+	checkIn, checkOut, err := utils.ToPgDateRange(req.GetCheckIn(), req.GetCheckOut())
 	if err != nil {
+		zap.S().Infoln("Invalid Date Range: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Ngay nhan phong hoac tra phong khong hop le")
+	}
+
+	hotels, pageInfo, err := hg.service.FilterHotels(ctx, &hotel_service.FilterHotelsParams{
+		HotelName:    req.GetHotelName(),
+		Address:      req.GetAddress(),
+		CheckIn:      checkIn,
+		CheckOut:     checkOut,
+		MinPrice:     int(req.GetMinPrice()),
+		MaxPrice:     int(req.GetMaxPrice()),
+		GeoSearch:    toGeoSearchParams(req.GetCenter(), req.GetRadiusKm(), req.GetBoundingBox()),
+		AmenityCodes: req.GetAmenities(),
+		RoomTypeFilter: hotel_service.RoomTypeFilter{
+			MinAreaSqm:     int(req.GetMinAreaSqm()),
+			View:           req.GetView(),
+			SmokingAllowed: req.SmokingAllowed,
+			Guests:         int(req.GetGuests()),
+		},
+		Page: utils.ToPageParams(req.GetPage()),
+	})
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Dieu kien tim kiem khong hop le")
+		}
 		return nil, status.Error(codes.Internal, "Loi khong filter duoc Hotels")
 	}
 
-	results := make([]*hotel_pb.FilterHotelRow, 0, len(rows))
-	for _, row := range rows {
+	results := make([]*hotel_pb.FilterHotelRow, 0, len(hotels))
+	for _, hotel := range hotels {
 		results = append(results, &hotel_pb.FilterHotelRow{
-			HotelId:      row.ID.String(),
-			HotelName:    row.Name,
-			HotelAddress: row.Address.String,
-			MinPrice:     row.MinPrice.(int32), // assertion interface{} to int32
+			HotelId:      hotel.Id,
+			HotelName:    hotel.Name,
+			HotelAddress: hotel.Address,
+			MinPrice:     hotel.MinPrice,
+			DistanceKm:   hotel.DistanceKm,
 		})
 	}
 
 	return &hotel_pb.FilterHotelsResponse{
 		FilterHotelRows: results,
+		Page:            utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
+
+// Hotels around a point or inside a bounding box, nearest first
+func (hg *HotelGrpcHandler) SearchHotelsByLocation(ctx context.Context, req *hotel_pb.SearchHotelsByLocationRequest) (*hotel_pb.SearchHotelsByLocationResponse, error) {
+
+	hotels, pageInfo, err := hg.service.SearchHotelsByLocation(ctx, toGeoSearchParams(req.GetCenter(), req.GetRadiusKm(), req.GetBoundingBox()), utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Vi tri tim kiem khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong tim duoc khach san theo vi tri")
+	}
+
+	results := make([]*hotel_pb.NearbyHotel, 0, len(hotels))
+	for _, hotel := range hotels {
+		results = append(results, &hotel_pb.NearbyHotel{
+			Hotel:      toHotelPb(hotel.Hotel),
+			DistanceKm: hotel.DistanceKm,
+		})
+	}
+
+	return &hotel_pb.SearchHotelsByLocationResponse{
+		Hotels: results,
+		Page:   utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
+
+// Full text search for the hotel list, best match first
+func (hg *HotelGrpcHandler) SearchHotels(ctx context.Context, req *hotel_pb.SearchHotelsRequest) (*hotel_pb.SearchHotelsResponse, error) {
+
+	hotels, pageInfo, err := hg.service.SearchHotels(ctx, req.GetQuery(), utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Noi dung tim kiem hoac trang khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong tim kiem duoc khach san")
+	}
+
+	results := make([]*hotel_pb.RankedHotel, 0, len(hotels))
+	for _, hotel := range hotels {
+		results = append(results, &hotel_pb.RankedHotel{
+			Hotel: toHotelPb(hotel.Hotel),
+			Rank:  hotel.Rank,
+		})
+	}
+
+	return &hotel_pb.SearchHotelsResponse{
+		Hotels: results,
+		Page:   utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
+
+// Suggestions of the search box, called on every key stroke
+func (hg *HotelGrpcHandler) AutocompleteHotels(ctx context.Context, req *hotel_pb.AutocompleteHotelsRequest) (*hotel_pb.AutocompleteHotelsResponse, error) {
+
+	result, pageInfo, err := hg.service.AutocompleteHotels(ctx, req.GetPrefix(), utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong goi y duoc khach san")
+	}
+
+	hotels := make([]*hotel_pb.HotelSuggestion, 0, len(result.Hotels))
+	for _, hotel := range result.Hotels {
+		hotels = append(hotels, &hotel_pb.HotelSuggestion{
+			Id:   hotel.Id,
+			Name: hotel.Name,
+			City: hotel.City,
+		})
+	}
+
+	return &hotel_pb.AutocompleteHotelsResponse{
+		Hotels: hotels,
+		Cities: result.Cities,
+		Page:   utils.ToPageResponsePb(pageInfo),
 	}, nil
+}
+
+func toHotelPb(hotel hotel_domain.Hotel) *hotel_pb.Hotel {
+	var location *hotel_pb.GeoPoint
+	if hotel.Location != nil {
+		location = &hotel_pb.GeoPoint{
+			Latitude:  hotel.Location.Latitude,
+			Longitude: hotel.Location.Longitude,
+		}
+	}
+
+	return &hotel_pb.Hotel{
+		Id:          hotel.Id,
+		Name:        hotel.Name,
+		Address:     hotel.Address,
+		Location:    location,
+		City:        hotel.City,
+		Description: hotel.Description,
+		Status:      hotel.Status,
+		CreatedBy:   hotel.CreatedBy,
+		ChainId:     hotel.ChainId,
+	}
+}
+
+// Location is optional, a missing one is left to the geocoder
+func toPgLocation(location *hotel_pb.GeoPoint) (pgtype.Float8, pgtype.Float8, error) {
+	if location == nil {
+		return pgtype.Float8{}, pgtype.Float8{}, nil
+	}
+
+	if location.GetLatitude() < -90 || location.GetLatitude() > 90 || location.GetLongitude() < -180 || location.GetLongitude() > 180 {
+		return pgtype.Float8{}, pgtype.Float8{}, common_error.ErrBadRequest
+	}
+
+	return pgtype.Float8{Float64: location.GetLatitude(), Valid: true}, pgtype.Float8{Float64: location.GetLongitude(), Valid: true}, nil
+}
+
+func toGeoSearchParams(center *hotel_pb.GeoPoint, radiusKm float64, box *hotel_pb.GeoBoundingBox) hotel_service.GeoSearchParams {
+	params := hotel_service.GeoSearchParams{
+		RadiusKm: radiusKm,
+	}
+
+	if center != nil {
+		params.Center = &hotel_domain.GeoPoint{
+			Latitude:  center.GetLatitude(),
+			Longitude: center.GetLongitude(),
+		}
+	}
+
+	if box != nil {
+		params.BoundingBox = &hotel_domain.GeoBoundingBox{
+			MinLatitude:  box.GetMinLatitude(),
+			MaxLatitude:  box.GetMaxLatitude(),
+			MinLongitude: box.GetMinLongitude(),
+			MaxLongitude: box.GetMaxLongitude(),
+		}
+	}
 
+	return params
 }
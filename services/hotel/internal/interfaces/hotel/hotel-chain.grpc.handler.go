@@ -0,0 +1,195 @@
+package hotel_handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	hotel_service "github.com/098765432m/grpc-kafka/hotel/internal/application/hotel"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (hg *HotelGrpcHandler) GetHotelChains(ctx context.Context, req *hotel_pb.GetHotelChainsRequest) (*hotel_pb.GetHotelChainsResponse, error) {
+
+	chains, pageInfo, err := hg.service.GetHotelChains(ctx, utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc danh sach chuoi khach san")
+	}
+
+	chainsPb := make([]*hotel_pb.HotelChain, 0, len(chains))
+	for _, chain := range chains {
+		chainsPb = append(chainsPb, toHotelChainPb(chain))
+	}
+
+	return &hotel_pb.GetHotelChainsResponse{
+		Chains: chainsPb,
+		Page:   utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) GetHotelChainById(ctx context.Context, req *hotel_pb.GetHotelChainByIdRequest) (*hotel_pb.GetHotelChainByIdResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid Hotel Chain UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID chuoi khach san")
+	}
+
+	chain, err := hg.service.GetHotelChainById(ctx, id)
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Chuoi khach san khong ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc chuoi khach san")
+	}
+
+	return &hotel_pb.GetHotelChainByIdResponse{
+		Chain: toHotelChainPb(*chain),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) CreateHotelChain(ctx context.Context, req *hotel_pb.CreateHotelChainRequest) (*hotel_pb.CreateHotelChainResponse, error) {
+
+	chain, err := hg.service.CreateHotelChain(ctx, &hotel_service.HotelChainParams{
+		Name:        req.GetName(),
+		Brand:       req.GetBrand(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Ten chuoi khach san khong hop le")
+		case errors.Is(err, common_error.ErrDuplicateRecord):
+			return nil, status.Error(codes.AlreadyExists, "Chuoi khach san da ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong tao duoc chuoi khach san")
+	}
+
+	return &hotel_pb.CreateHotelChainResponse{
+		Chain: toHotelChainPb(*chain),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) UpdateHotelChain(ctx context.Context, req *hotel_pb.UpdateHotelChainRequest) (*hotel_pb.UpdateHotelChainResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid Hotel Chain UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID chuoi khach san")
+	}
+
+	chain, err := hg.service.UpdateHotelChainById(ctx, id, &hotel_service.HotelChainParams{
+		Name:        req.GetName(),
+		Brand:       req.GetBrand(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Ten chuoi khach san khong hop le")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Chuoi khach san khong ton tai")
+		case errors.Is(err, common_error.ErrDuplicateRecord):
+			return nil, status.Error(codes.AlreadyExists, "Chuoi khach san da ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc chuoi khach san")
+	}
+
+	return &hotel_pb.UpdateHotelChainResponse{
+		Chain: toHotelChainPb(*chain),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) DeleteHotelChain(ctx context.Context, req *hotel_pb.DeleteHotelChainRequest) (*hotel_pb.DeleteHotelChainResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid Hotel Chain UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID chuoi khach san")
+	}
+
+	if err := hg.service.DeleteHotelChainById(ctx, id); err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Chuoi khach san khong ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong xoa duoc chuoi khach san")
+	}
+
+	return &hotel_pb.DeleteHotelChainResponse{}, nil
+}
+
+// Empty chain_id removes the hotel from its chain
+func (hg *HotelGrpcHandler) SetHotelChain(ctx context.Context, req *hotel_pb.SetHotelChainRequest) (*hotel_pb.SetHotelChainResponse, error) {
+
+	var hotelId, chainId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	if req.GetChainId() != "" {
+		if err := chainId.Scan(req.GetChainId()); err != nil {
+			zap.S().Info("Invalid Hotel Chain UUID: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Loi UUID chuoi khach san")
+		}
+	}
+
+	hotel, err := hg.service.SetHotelChain(ctx, hotelId, chainId)
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khach san hoac chuoi khach san khong ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong gan duoc chuoi cho khach san")
+	}
+
+	return &hotel_pb.SetHotelChainResponse{
+		Hotel: toHotelPb(*hotel),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) GetHotelsByChainId(ctx context.Context, req *hotel_pb.GetHotelsByChainIdRequest) (*hotel_pb.GetHotelsByChainIdResponse, error) {
+
+	var chainId pgtype.UUID
+	if err := chainId.Scan(req.GetChainId()); err != nil {
+		zap.S().Info("Invalid Hotel Chain UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID chuoi khach san")
+	}
+
+	hotels, err := hg.service.GetHotelsByChainId(ctx, chainId)
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Chuoi khach san khong ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc khach san cua chuoi")
+	}
+
+	hotelsPb := make([]*hotel_pb.Hotel, 0, len(hotels))
+	for _, hotel := range hotels {
+		hotelsPb = append(hotelsPb, toHotelPb(hotel))
+	}
+
+	return &hotel_pb.GetHotelsByChainIdResponse{
+		Hotels: hotelsPb,
+	}, nil
+}
+
+func toHotelChainPb(chain hotel_domain.HotelChain) *hotel_pb.HotelChain {
+	return &hotel_pb.HotelChain{
+		Id:          chain.Id,
+		Name:        chain.Name,
+		Brand:       chain.Brand,
+		Description: chain.Description,
+		CreateAt:    chain.CreateAt.Format(time.RFC3339),
+	}
+}
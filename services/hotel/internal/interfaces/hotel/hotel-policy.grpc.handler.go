@@ -0,0 +1,106 @@
+package hotel_handler
+
+import (
+	"context"
+	"errors"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	hotel_policy_service "github.com/098765432m/grpc-kafka/hotel/internal/application/hotel-policy"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (hg *HotelGrpcHandler) GetHotelPolicy(ctx context.Context, req *hotel_pb.GetHotelPolicyRequest) (*hotel_pb.GetHotelPolicyResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID")
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	policy, err := hg.hotelPolicyService.GetHotelPolicy(ctx, hotelId)
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khach san chua co chinh sach")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc chinh sach khach san")
+	}
+
+	return &hotel_pb.GetHotelPolicyResponse{
+		Policy: toHotelPolicyPb(*policy),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) SetHotelPolicy(ctx context.Context, req *hotel_pb.SetHotelPolicyRequest) (*hotel_pb.SetHotelPolicyResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID")
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	policyPb := req.GetPolicy()
+	if policyPb == nil {
+		return nil, status.Error(codes.InvalidArgument, "Chinh sach khach san khong hop le")
+	}
+
+	var childrenFreeMaxAge *int
+	if policyPb.ChildrenFreeMaxAge != nil {
+		age := int(policyPb.GetChildrenFreeMaxAge())
+		childrenFreeMaxAge = &age
+	}
+
+	policy, err := hg.hotelPolicyService.SetHotelPolicy(ctx, hotelId, &hotel_policy_service.HotelPolicyParams{
+		CheckInFrom:        policyPb.GetCheckInFrom(),
+		CheckInUntil:       policyPb.GetCheckInUntil(),
+		CheckOutUntil:      policyPb.GetCheckOutUntil(),
+		PetsAllowed:        policyPb.GetPetsAllowed(),
+		PetFee:             int(policyPb.GetPetFee()),
+		ChildrenAllowed:    policyPb.GetChildrenAllowed(),
+		ChildrenFreeMaxAge: childrenFreeMaxAge,
+		ExtraBedAvailable:  policyPb.GetExtraBedAvailable(),
+		ExtraBedFee:        int(policyPb.GetExtraBedFee()),
+		PaymentMethods:     policyPb.GetPaymentMethods(),
+		HouseRules:         policyPb.GetHouseRules(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Chinh sach khach san khong hop le")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Khach san khong ton tai")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc chinh sach khach san")
+	}
+
+	return &hotel_pb.SetHotelPolicyResponse{
+		Policy: toHotelPolicyPb(*policy),
+	}, nil
+}
+
+func toHotelPolicyPb(policy hotel_domain.HotelPolicy) *hotel_pb.HotelPolicy {
+	var childrenFreeMaxAge *int32
+	if policy.ChildrenFreeMaxAge != nil {
+		age := int32(*policy.ChildrenFreeMaxAge)
+		childrenFreeMaxAge = &age
+	}
+
+	return &hotel_pb.HotelPolicy{
+		HotelId:            policy.HotelId,
+		CheckInFrom:        policy.CheckInFrom,
+		CheckInUntil:       policy.CheckInUntil,
+		CheckOutUntil:      policy.CheckOutUntil,
+		PetsAllowed:        policy.PetsAllowed,
+		PetFee:             int32(policy.PetFee),
+		ChildrenAllowed:    policy.ChildrenAllowed,
+		ChildrenFreeMaxAge: childrenFreeMaxAge,
+		ExtraBedAvailable:  policy.ExtraBedAvailable,
+		ExtraBedFee:        int32(policy.ExtraBedFee),
+		PaymentMethods:     policy.PaymentMethods,
+		HouseRules:         policy.HouseRules,
+	}
+}
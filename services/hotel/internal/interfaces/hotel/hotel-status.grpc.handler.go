@@ -0,0 +1,157 @@
+package hotel_handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	hotel_service "github.com/098765432m/grpc-kafka/hotel/internal/application/hotel"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (hg *HotelGrpcHandler) SubmitHotel(ctx context.Context, req *hotel_pb.SubmitHotelRequest) (*hotel_pb.SubmitHotelResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	hotel, err := hg.service.SubmitHotel(ctx, id, req.GetComment(), requesterFromContext(ctx))
+	if err != nil {
+		return nil, toHotelStatusError(err, "Loi khong gui duyet duoc khach san")
+	}
+
+	return &hotel_pb.SubmitHotelResponse{
+		Hotel: toHotelPb(*hotel),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) ApproveHotel(ctx context.Context, req *hotel_pb.ApproveHotelRequest) (*hotel_pb.ApproveHotelResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	hotel, err := hg.service.ApproveHotel(ctx, id, req.GetComment(), requesterFromContext(ctx))
+	if err != nil {
+		return nil, toHotelStatusError(err, "Loi khong duyet duoc khach san")
+	}
+
+	return &hotel_pb.ApproveHotelResponse{
+		Hotel: toHotelPb(*hotel),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) RejectHotel(ctx context.Context, req *hotel_pb.RejectHotelRequest) (*hotel_pb.RejectHotelResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	hotel, err := hg.service.RejectHotel(ctx, id, req.GetComment(), requesterFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Can nhap ly do tu choi")
+		}
+		return nil, toHotelStatusError(err, "Loi khong tu choi duoc khach san")
+	}
+
+	return &hotel_pb.RejectHotelResponse{
+		Hotel: toHotelPb(*hotel),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) PublishHotel(ctx context.Context, req *hotel_pb.PublishHotelRequest) (*hotel_pb.PublishHotelResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	hotel, err := hg.service.PublishHotel(ctx, id, requesterFromContext(ctx))
+	if err != nil {
+		return nil, toHotelStatusError(err, "Loi khong cong khai duoc khach san")
+	}
+
+	return &hotel_pb.PublishHotelResponse{
+		Hotel: toHotelPb(*hotel),
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) GetHotelStatusHistory(ctx context.Context, req *hotel_pb.GetHotelStatusHistoryRequest) (*hotel_pb.GetHotelStatusHistoryResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	events, err := hg.service.GetHotelStatusHistory(ctx, hotelId, requesterFromContext(ctx))
+	if err != nil {
+		return nil, toHotelStatusError(err, "Loi khong lay duoc lich su duyet khach san")
+	}
+
+	eventsPb := make([]*hotel_pb.HotelStatusEvent, 0, len(events))
+	for _, event := range events {
+		eventsPb = append(eventsPb, &hotel_pb.HotelStatusEvent{
+			Id:         event.Id,
+			HotelId:    event.HotelId,
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			Actor:      event.Actor,
+			Comment:    event.Comment,
+			CreateAt:   event.CreateAt.Format(time.RFC3339),
+		})
+	}
+
+	return &hotel_pb.GetHotelStatusHistoryResponse{
+		Events: eventsPb,
+	}, nil
+}
+
+func (hg *HotelGrpcHandler) CanManageHotel(ctx context.Context, req *hotel_pb.CanManageHotelRequest) (*hotel_pb.CanManageHotelResponse, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	allowed, err := hg.service.CanManageHotel(ctx, hotelId, requesterFromContext(ctx))
+	if err != nil {
+		return nil, toHotelStatusError(err, "Loi khong kiem tra duoc quyen quan ly khach san")
+	}
+
+	return &hotel_pb.CanManageHotelResponse{
+		Allowed: allowed,
+	}, nil
+}
+
+// Errors of the publication workflow, message is used for unexpected errors
+func toHotelStatusError(err error, message string) error {
+	switch {
+	case errors.Is(err, common_error.ErrNoRows):
+		return status.Error(codes.NotFound, "Khong tim thay khach san")
+	case errors.Is(err, hotel_service.ErrHotelStatusChangeNotAllowed):
+		return status.Error(codes.PermissionDenied, "Chi nguoi tao khach san moi duoc thuc hien")
+	case errors.Is(err, hotel_service.ErrHotelReviewNotAllowed):
+		return status.Error(codes.PermissionDenied, "Chi ADMIN moi duoc duyet khach san")
+	case errors.Is(err, hotel_service.ErrHotelAccessDenied):
+		return status.Error(codes.PermissionDenied, "Khong co quyen truy cap khach san nay")
+	case errors.Is(err, hotel_service.ErrInvalidHotelStatusTransition):
+		return status.Error(codes.FailedPrecondition, "Trang thai khach san khong cho phep thao tac nay")
+	default:
+		return status.Error(codes.Internal, message)
+	}
+}
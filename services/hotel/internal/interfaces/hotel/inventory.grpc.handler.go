@@ -0,0 +1,126 @@
+package hotel_handler
+
+import (
+	"errors"
+	"io"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/hotel_pb"
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	inventory_service "github.com/098765432m/grpc-kafka/hotel/internal/application/inventory"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Collect every batch of the stream then import them at once, nothing is written before the client closes the stream
+func (hg *HotelGrpcHandler) ImportInventory(stream grpc.ClientStreamingServer[hotel_pb.ImportInventoryRequest, hotel_pb.ImportInventoryResponse]) error {
+
+	// Called by the import CLI with the token of the user, not through the gateway
+	claims, err := common_middleware.ParseUserClaims(utils.UserTokenFromContext(stream.Context()))
+	if err != nil {
+		zap.S().Infoln("Invalid inventory import token: ", err)
+		return status.Error(codes.Unauthenticated, "Phien dang nhap khong hop le")
+	}
+
+	var hotelIdStr string
+	var dryRun bool
+	roomTypes := []inventory_service.InventoryRoomTypeRow{}
+	rooms := []inventory_service.InventoryRoomRow{}
+
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			if first {
+				return status.Error(codes.InvalidArgument, "Khong co du lieu nhap")
+			}
+			break
+		}
+		if err != nil {
+			zap.S().Infoln("Failed to receive inventory import batch: ", err)
+			return err
+		}
+
+		if first {
+			hotelIdStr = req.GetHotelId()
+			dryRun = req.GetDryRun()
+		} else if req.GetHotelId() != "" && req.GetHotelId() != hotelIdStr {
+			return status.Error(codes.InvalidArgument, "Khach san khong khop giua cac lan gui")
+		}
+
+		if len(roomTypes)+len(rooms)+len(req.GetRoomTypes())+len(req.GetRooms()) > inventory_service.MAX_INVENTORY_IMPORT_ROWS {
+			return status.Errorf(codes.InvalidArgument, "Vuot qua %d dong moi lan nhap", inventory_service.MAX_INVENTORY_IMPORT_ROWS)
+		}
+
+		for _, roomType := range req.GetRoomTypes() {
+			roomTypes = append(roomTypes, inventory_service.InventoryRoomTypeRow{
+				Row:            int(roomType.GetRow()),
+				Name:           roomType.GetName(),
+				Price:          int(roomType.GetPrice()),
+				AreaSqm:        int(roomType.GetAreaSqm()),
+				View:           roomType.GetView(),
+				SmokingAllowed: roomType.GetSmokingAllowed(),
+				MaxOccupancy:   int(roomType.GetMaxOccupancy()),
+				Description:    roomType.GetDescription(),
+			})
+		}
+
+		for _, room := range req.GetRooms() {
+			rooms = append(rooms, inventory_service.InventoryRoomRow{
+				Row:          int(room.GetRow()),
+				Name:         room.GetName(),
+				RoomTypeName: room.GetRoomType(),
+				Status:       room.GetStatus(),
+			})
+		}
+	}
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(hotelIdStr); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID")
+		return status.Error(codes.InvalidArgument, "Loi UUID khach san")
+	}
+
+	report, err := hg.inventoryService.ImportInventory(stream.Context(), toHotelRequester(claims), hotelId, dryRun, roomTypes, rooms)
+	if err != nil {
+		if errors.Is(err, inventory_service.ErrInventoryImportNotAllowed) {
+			return status.Error(codes.PermissionDenied, "Khong co quyen nhap phong cho khach san nay")
+		}
+		if errors.Is(err, common_error.ErrNoRows) {
+			return status.Error(codes.NotFound, "Khong tim thay khach san")
+		}
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return status.Error(codes.InvalidArgument, "Du lieu nhap khong hop le")
+		}
+		return status.Error(codes.Internal, "Loi khong nhap duoc phong va loai phong")
+	}
+
+	return stream.SendAndClose(toImportInventoryResponsePb(*report))
+}
+
+func toImportInventoryResponsePb(report hotel_domain.InventoryImportReport) *hotel_pb.ImportInventoryResponse {
+
+	errorsPb := make([]*hotel_pb.InventoryRowError, 0, len(report.Errors))
+	for _, rowError := range report.Errors {
+		errorsPb = append(errorsPb, &hotel_pb.InventoryRowError{
+			Kind:    rowError.Kind,
+			Row:     int32(rowError.Row),
+			Field:   rowError.Field,
+			Message: rowError.Message,
+		})
+	}
+
+	return &hotel_pb.ImportInventoryResponse{
+		DryRun:           report.DryRun,
+		Applied:          report.Applied,
+		RoomTypesCreated: int32(report.RoomTypesCreated),
+		RoomTypesUpdated: int32(report.RoomTypesUpdated),
+		RoomsCreated:     int32(report.RoomsCreated),
+		RoomsUpdated:     int32(report.RoomsUpdated),
+		Errors:           errorsPb,
+	}
+}
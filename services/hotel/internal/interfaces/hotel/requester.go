@@ -0,0 +1,35 @@
+package hotel_handler
+
+import (
+	"context"
+
+	common_middleware "github.com/098765432m/grpc-kafka/common/middleware"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Signed in user of the JWT in the authorization metadata, anonymous when there is none or it is invalid
+func requesterFromContext(ctx context.Context) hotel_domain.HotelRequester {
+	tokenStr := utils.UserTokenFromContext(ctx)
+	if tokenStr == "" {
+		return hotel_domain.HotelRequester{}
+	}
+
+	claims, err := common_middleware.ParseUserClaims(tokenStr)
+	if err != nil {
+		zap.S().Infoln("Invalid JWT of requester: ", err)
+		return hotel_domain.HotelRequester{}
+	}
+
+	return toHotelRequester(claims)
+}
+
+func toHotelRequester(claims *common_middleware.UserClaims) hotel_domain.HotelRequester {
+	return hotel_domain.HotelRequester{
+		UserId:  claims.UserId,
+		Role:    claims.Role,
+		HotelId: claims.HotelId,
+		ChainId: claims.ChainId,
+	}
+}
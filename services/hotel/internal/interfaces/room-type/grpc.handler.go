@@ -2,9 +2,13 @@ package room_type_handler
 
 import (
 	"context"
+	"errors"
 
+	common_error "github.com/098765432m/grpc-kafka/common/error"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/room_type_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
 	room_type_service "github.com/098765432m/grpc-kafka/hotel/internal/application/room-type"
+	hotel_domain "github.com/098765432m/grpc-kafka/hotel/internal/domain"
 	room_type_repo "github.com/098765432m/grpc-kafka/hotel/internal/infrastructure/repository/sqlc/room-type"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
@@ -36,12 +40,7 @@ func (rtg *RoomTypeGrpcHandler) GetRoomTypeById(ctx context.Context, req *room_t
 	}
 
 	return &room_type_pb.GetRoomTypeByIdResponse{
-		RoomType: &room_type_pb.RoomType{
-			Id:      roomType.Id,
-			Name:    roomType.Name,
-			Price:   uint32(roomType.Price),
-			HotelId: roomType.HotelId,
-		},
+		RoomType: toRoomTypePb(*roomType),
 	}, nil
 }
 
@@ -52,27 +51,59 @@ func (rtg *RoomTypeGrpcHandler) GetRoomTypesByHotelId(ctx context.Context, req *
 		return nil, err
 	}
 
-	roomTypes, err := rtg.service.GetRoomTypesByHotelId(ctx, hotelId)
+	roomTypes, pageInfo, err := rtg.service.GetRoomTypesByHotelId(ctx, hotelId, utils.ToPageParams(req.GetPage()))
 	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
 		return nil, err
 	}
 
+	return &room_type_pb.GetRoomTypesByHotelIdResponse{
+		RoomTypes: toHotelRoomTypesPb(roomTypes),
+		Page:      utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
+
+func (rtg *RoomTypeGrpcHandler) GetRoomTypesByHotelIds(ctx context.Context, req *room_type_pb.GetRoomTypesByHotelIdsRequest) (*room_type_pb.GetRoomTypesByHotelIdsResponse, error) {
+
+	hotelIds, err := utils.ToPgUuidArray(req.GetHotelIds())
+	if err != nil {
+		zap.S().Infoln("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel Id khong hop le")
+	}
+
+	roomTypes, err := rtg.service.GetRoomTypesByHotelIds(ctx, hotelIds)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc danh sach loai phong")
+	}
+
+	return &room_type_pb.GetRoomTypesByHotelIdsResponse{
+		RoomTypes: toHotelRoomTypesPb(roomTypes),
+	}, nil
+}
+
+func toHotelRoomTypesPb(roomTypes []hotel_domain.HotelRoomType) []*room_type_pb.GetRoomTypesByHotelIdRow {
 	var grpcRoomTypes []*room_type_pb.GetRoomTypesByHotelIdRow
 	for _, roomType := range roomTypes {
 		grpcRoomType := &room_type_pb.GetRoomTypesByHotelIdRow{
-			Id:            roomType.ID.String(),
-			Name:          roomType.Name,
-			Price:         uint32(roomType.Price),
-			HotelId:       roomType.HotelID.String(),
-			NumberOfRooms: uint32(roomType.NumberOfRooms),
+			Id:             roomType.Id,
+			Name:           roomType.Name,
+			Price:          uint32(roomType.Price),
+			HotelId:        roomType.HotelId,
+			NumberOfRooms:  uint32(roomType.NumberOfRooms),
+			AreaSqm:        int32(roomType.AreaSqm),
+			View:           roomType.View,
+			SmokingAllowed: roomType.SmokingAllowed,
+			MaxOccupancy:   int32(roomType.MaxOccupancy),
+			Description:    roomType.Description,
+			Beds:           toRoomTypeBedsPb(roomType.Beds),
 		}
 
 		grpcRoomTypes = append(grpcRoomTypes, grpcRoomType)
 	}
 
-	return &room_type_pb.GetRoomTypesByHotelIdResponse{
-		RoomTypes: grpcRoomTypes,
-	}, nil
+	return grpcRoomTypes
 }
 
 func (rtg *RoomTypeGrpcHandler) CreateRoomType(ctx context.Context, req *room_type_pb.CreateRoomTypeRequest) (*room_type_pb.CreateRoomTypeResponse, error) {
@@ -83,16 +114,71 @@ func (rtg *RoomTypeGrpcHandler) CreateRoomType(ctx context.Context, req *room_ty
 		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
 	}
 
-	err := rtg.service.CreateRoomType(ctx, &room_type_repo.CreateRoomTypeParams{
-		Name:    req.Name,
-		Price:   req.Price,
-		HotelID: hotelId,
+	roomType, err := rtg.service.CreateRoomType(ctx, hotelId, &room_type_service.RoomTypeParams{
+		Name:           req.GetName(),
+		Price:          int(req.GetPrice()),
+		AreaSqm:        int(req.GetAreaSqm()),
+		View:           req.GetView(),
+		SmokingAllowed: req.GetSmokingAllowed(),
+		MaxOccupancy:   int(req.GetMaxOccupancy()),
+		Description:    req.GetDescription(),
+		Beds:           fromRoomTypeBedsPb(req.GetBeds()),
 	})
 	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Thong tin loai phong khong hop le")
+		case errors.Is(err, common_error.ErrDuplicateRecord):
+			return nil, status.Error(codes.AlreadyExists, "Ten loai phong da ton tai trong khach san")
+		}
 		return nil, status.Error(codes.Internal, "Khong tao duoc loai phong")
 	}
 
-	return &room_type_pb.CreateRoomTypeResponse{}, nil
+	return &room_type_pb.CreateRoomTypeResponse{
+		RoomType: toRoomTypePb(*roomType),
+	}, nil
+}
+
+// Room type must be one of the hotel
+func (rtg *RoomTypeGrpcHandler) UpdateRoomType(ctx context.Context, req *room_type_pb.UpdateRoomTypeRequest) (*room_type_pb.UpdateRoomTypeResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Room Type UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room Type UUID khong hop le")
+	}
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	roomType, err := rtg.service.UpdateRoomTypeById(ctx, hotelId, id, &room_type_service.RoomTypeParams{
+		Name:           req.GetName(),
+		Price:          int(req.GetPrice()),
+		AreaSqm:        int(req.GetAreaSqm()),
+		View:           req.GetView(),
+		SmokingAllowed: req.GetSmokingAllowed(),
+		MaxOccupancy:   int(req.GetMaxOccupancy()),
+		Description:    req.GetDescription(),
+		Beds:           fromRoomTypeBedsPb(req.GetBeds()),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Thong tin loai phong khong hop le")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Loai phong khong ton tai trong khach san")
+		case errors.Is(err, common_error.ErrDuplicateRecord):
+			return nil, status.Error(codes.AlreadyExists, "Ten loai phong da ton tai trong khach san")
+		}
+		return nil, status.Error(codes.Internal, "Khong cap nhat duoc loai phong")
+	}
+
+	return &room_type_pb.UpdateRoomTypeResponse{
+		RoomType: toRoomTypePb(*roomType),
+	}, nil
 }
 
 // Delete Room By Id
@@ -112,3 +198,250 @@ func (rtg *RoomTypeGrpcHandler) DeleteRoomTypeById(ctx context.Context, req *roo
 
 	return &room_type_pb.DeleteRoomTypeByIdResponse{}, nil
 }
+
+// Create Overbooking Limit for Room Type in range of date
+func (rtg *RoomTypeGrpcHandler) CreateOverbookingLimit(ctx context.Context, req *room_type_pb.CreateOverbookingLimitRequest) (*room_type_pb.CreateOverbookingLimitResponse, error) {
+
+	var roomTypeId pgtype.UUID
+	if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+		zap.S().Infoln("Invalid Room Type UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room Type UUID khong hop le")
+	}
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	var startDate pgtype.Date
+	if err := startDate.Scan(req.GetStartDate()); err != nil {
+		zap.S().Infoln("Invalid Start date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Ngay bat dau khong hop le")
+	}
+
+	var endDate pgtype.Date
+	if err := endDate.Scan(req.GetEndDate()); err != nil {
+		zap.S().Infoln("Invalid End date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Ngay ket thuc khong hop le")
+	}
+
+	if !startDate.Time.Before(endDate.Time) {
+		zap.S().Infoln("Start date must be before End date")
+		return nil, status.Error(codes.InvalidArgument, "Ngay bat dau phai truoc ngay ket thuc")
+	}
+
+	limitType := room_type_repo.OverbookingLimitType(req.GetLimitType())
+	switch limitType {
+	case room_type_repo.OverbookingLimitTypeABSOLUTE, room_type_repo.OverbookingLimitTypePERCENT:
+	default:
+		zap.S().Infoln("Invalid Overbooking Limit type: ", req.GetLimitType())
+		return nil, status.Error(codes.InvalidArgument, "Loai gioi han khong hop le")
+	}
+
+	if req.GetLimitValue() < 0 {
+		zap.S().Infoln("Overbooking Limit value must not be negative")
+		return nil, status.Error(codes.InvalidArgument, "Gia tri gioi han khong hop le")
+	}
+
+	err := rtg.service.CreateOverbookingLimit(ctx, &room_type_repo.CreateOverbookingLimitParams{
+		StartDate:  startDate,
+		EndDate:    endDate,
+		LimitType:  limitType,
+		LimitValue: req.GetLimitValue(),
+		RoomTypeID: roomTypeId,
+		HotelID:    hotelId,
+	})
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Loai phong khong ton tai trong khach san")
+		}
+		return nil, status.Error(codes.Internal, "Khong tao duoc gioi han overbooking")
+	}
+
+	return &room_type_pb.CreateOverbookingLimitResponse{}, nil
+}
+
+func (rtg *RoomTypeGrpcHandler) GetOverbookingLimitsByRoomTypeId(ctx context.Context, req *room_type_pb.GetOverbookingLimitsByRoomTypeIdRequest) (*room_type_pb.GetOverbookingLimitsByRoomTypeIdResponse, error) {
+
+	var roomTypeId pgtype.UUID
+	if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+		zap.S().Infoln("Invalid Room Type UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room Type UUID khong hop le")
+	}
+
+	limits, err := rtg.service.GetOverbookingLimitsByRoomTypeId(ctx, roomTypeId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc danh sach gioi han overbooking")
+	}
+
+	results := make([]*room_type_pb.OverbookingLimit, 0, len(limits))
+	for _, limit := range limits {
+		results = append(results, &room_type_pb.OverbookingLimit{
+			Id:         limit.Id,
+			RoomTypeId: limit.RoomTypeId,
+			StartDate:  limit.StartDate.Format("2006-01-02"),
+			EndDate:    limit.EndDate.Format("2006-01-02"),
+			LimitType:  limit.LimitType,
+			LimitValue: int32(limit.LimitValue),
+		})
+	}
+
+	return &room_type_pb.GetOverbookingLimitsByRoomTypeIdResponse{
+		Limits: results,
+	}, nil
+}
+
+func (rtg *RoomTypeGrpcHandler) DeleteOverbookingLimitById(ctx context.Context, req *room_type_pb.DeleteOverbookingLimitByIdRequest) (*room_type_pb.DeleteOverbookingLimitByIdResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Overbooking Limit UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "UUID khong hop le")
+	}
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	err := rtg.service.DeleteOverbookingLimitById(ctx, hotelId, id)
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Gioi han overbooking khong ton tai trong khach san")
+		}
+		return nil, status.Error(codes.Internal, "Khong the xoa gioi han overbooking")
+	}
+
+	return &room_type_pb.DeleteOverbookingLimitByIdResponse{}, nil
+}
+
+// Return number of rooms that can be booked beyond the room count in range of time
+func (rtg *RoomTypeGrpcHandler) GetOverbookingAllowance(ctx context.Context, req *room_type_pb.GetOverbookingAllowanceRequest) (*room_type_pb.GetOverbookingAllowanceResponse, error) {
+
+	var roomTypeId pgtype.UUID
+	if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+		zap.S().Infoln("Invalid Room Type UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room Type UUID khong hop le")
+	}
+
+	var checkIn pgtype.Date
+	if err := checkIn.Scan(req.GetCheckIn()); err != nil {
+		zap.S().Infoln("Invalid Check In date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid Check In date format")
+	}
+
+	var checkOut pgtype.Date
+	if err := checkOut.Scan(req.GetCheckOut()); err != nil {
+		zap.S().Infoln("Invalid Check Out date format: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Invalid Check Out date format")
+	}
+
+	if checkIn.Time.After(checkOut.Time) {
+		zap.S().Infoln("Check In date must be before Check Out date")
+		return nil, status.Error(codes.InvalidArgument, "Check In date must be before Check Out date")
+	}
+
+	allowance, err := rtg.service.GetOverbookingAllowance(ctx, roomTypeId, checkIn, checkOut)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc gioi han overbooking")
+	}
+
+	return &room_type_pb.GetOverbookingAllowanceResponse{
+		Allowance: int32(allowance),
+	}, nil
+}
+
+func (rtg *RoomTypeGrpcHandler) SetRoomTypeUpgradeRank(ctx context.Context, req *room_type_pb.SetRoomTypeUpgradeRankRequest) (*room_type_pb.SetRoomTypeUpgradeRankResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Room Type UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room Type ID khong hop le")
+	}
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	if req.GetUpgradeRank() < 0 {
+		zap.S().Infoln("Invalid upgrade rank: ", req.GetUpgradeRank())
+		return nil, status.Error(codes.InvalidArgument, "Thu hang nang cap khong hop le")
+	}
+
+	if err := rtg.service.SetRoomTypeUpgradeRank(ctx, hotelId, id, int(req.GetUpgradeRank())); err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Loai phong khong ton tai trong khach san")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc thu hang nang cap")
+	}
+
+	return &room_type_pb.SetRoomTypeUpgradeRankResponse{}, nil
+}
+
+// Return upgrade ladder above the room type, nearest upgrade first
+func (rtg *RoomTypeGrpcHandler) GetUpgradeRoomTypesByRoomTypeId(ctx context.Context, req *room_type_pb.GetUpgradeRoomTypesByRoomTypeIdRequest) (*room_type_pb.GetUpgradeRoomTypesByRoomTypeIdResponse, error) {
+
+	var roomTypeId pgtype.UUID
+	if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+		zap.S().Infoln("Invalid Room Type UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Room Type ID khong hop le")
+	}
+
+	roomTypes, err := rtg.service.GetUpgradeRoomTypesByRoomTypeId(ctx, roomTypeId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc danh sach loai phong nang cap")
+	}
+
+	results := make([]*room_type_pb.RoomType, 0, len(roomTypes))
+	for _, roomType := range roomTypes {
+		results = append(results, toRoomTypePb(roomType))
+	}
+
+	return &room_type_pb.GetUpgradeRoomTypesByRoomTypeIdResponse{
+		RoomTypes: results,
+	}, nil
+}
+
+func toRoomTypePb(roomType hotel_domain.RoomType) *room_type_pb.RoomType {
+	return &room_type_pb.RoomType{
+		Id:             roomType.Id,
+		Name:           roomType.Name,
+		Price:          uint32(roomType.Price),
+		HotelId:        roomType.HotelId,
+		UpgradeRank:    int32(roomType.UpgradeRank),
+		AreaSqm:        int32(roomType.AreaSqm),
+		View:           roomType.View,
+		SmokingAllowed: roomType.SmokingAllowed,
+		MaxOccupancy:   int32(roomType.MaxOccupancy),
+		Description:    roomType.Description,
+		Beds:           toRoomTypeBedsPb(roomType.Beds),
+	}
+}
+
+func toRoomTypeBedsPb(beds []hotel_domain.RoomTypeBed) []*room_type_pb.RoomTypeBed {
+	results := make([]*room_type_pb.RoomTypeBed, 0, len(beds))
+	for _, bed := range beds {
+		results = append(results, &room_type_pb.RoomTypeBed{
+			BedType:  bed.BedType,
+			Quantity: int32(bed.Quantity),
+		})
+	}
+
+	return results
+}
+
+func fromRoomTypeBedsPb(beds []*room_type_pb.RoomTypeBed) []hotel_domain.RoomTypeBed {
+	results := make([]hotel_domain.RoomTypeBed, 0, len(beds))
+	for _, bed := range beds {
+		results = append(results, hotel_domain.RoomTypeBed{
+			BedType:  bed.GetBedType(),
+			Quantity: int(bed.GetQuantity()),
+		})
+	}
+
+	return results
+}
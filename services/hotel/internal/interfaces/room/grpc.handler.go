@@ -61,8 +61,11 @@ func (rg *RoomGrpcHandler) GetRoomsByRoomTypeId(ctx context.Context, req *room_p
 		return nil, status.Error(codes.InvalidArgument, "Loi Room type UUID")
 	}
 
-	rooms, err := rg.service.GetRoomsByRoomTypeId(ctx, roomTypeId)
+	rooms, pageInfo, err := rg.service.GetRoomsByRoomTypeId(ctx, roomTypeId, utils.ToPageParams(req.GetPage()))
 	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
 		return nil, status.Error(codes.Internal, "Loi khong lay Rooms bang RoomType id")
 	}
 
@@ -81,6 +84,7 @@ func (rg *RoomGrpcHandler) GetRoomsByRoomTypeId(ctx context.Context, req *room_p
 
 	return &room_pb.GetRoomsByRoomTypeIdResponse{
 		Rooms: roomsGrpcResult,
+		Page:  utils.ToPageResponsePb(pageInfo),
 	}, nil
 
 }
@@ -93,8 +97,11 @@ func (rg *RoomGrpcHandler) GetRoomsByHotelId(ctx context.Context, req *room_pb.G
 		return nil, status.Error(codes.InvalidArgument, "Loi Hotel UUID")
 	}
 
-	rooms, err := rg.service.GetRoomsByHotelId(ctx, hotelId)
+	rooms, pageInfo, err := rg.service.GetRoomsByHotelId(ctx, hotelId, utils.ToPageParams(req.GetPage()))
 	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
 		return nil, status.Error(codes.Internal, "Loi khong lay Rooms bang Hotel id")
 	}
 
@@ -113,11 +120,12 @@ func (rg *RoomGrpcHandler) GetRoomsByHotelId(ctx context.Context, req *room_pb.G
 
 	return &room_pb.GetRoomsByHotelIdResponse{
 		Rooms: roomsGrpcResult,
+		Page:  utils.ToPageResponsePb(pageInfo),
 	}, nil
 }
 
 // Get Number of rooms per RoomType by Hotel Ids
-// -> return [room_type_id, number_of_rooms]
+// -> return [room_type_id, hotel_id, number_of_rooms]
 func (rg *RoomGrpcHandler) GetNumberOfRoomsPerRoomTypeByHotelIds(ctx context.Context, req *room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsRequest) (*room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsResponse, error) {
 
 	// convert to UUIDs
@@ -136,6 +144,7 @@ func (rg *RoomGrpcHandler) GetNumberOfRoomsPerRoomTypeByHotelIds(ctx context.Con
 	for _, row := range rows {
 		result := &room_pb.GetNumberOfRoomsPerRoomTypeByHotelIdsRow{
 			RoomTypeId:    row.RoomTypeID.String(),
+			HotelId:       row.HotelID.String(),
 			NumberOfRooms: int32(row.TotalRooms),
 		}
 
@@ -155,6 +164,11 @@ func (rg *RoomGrpcHandler) GetListOfAvailableRoomsByRoomTypeId(ctx context.Conte
 		return nil, status.Error(codes.InvalidArgument, "Khong the lay danh sach phong")
 	}
 
+	if req.GetNumberOfRooms() < 1 {
+		zap.S().Infoln("Invalid number of rooms: ", req.GetNumberOfRooms())
+		return nil, status.Error(codes.InvalidArgument, "So luong phong phai lon hon 0")
+	}
+
 	roomIds, err := rg.service.GetListOfAvailableRoomsByRoomTypeId(ctx, roomTypeId, int(req.GetNumberOfRooms()))
 	if err != nil {
 		switch {
@@ -186,14 +200,45 @@ func (rs *RoomGrpcHandler) GetListOfRemainRooms(ctx context.Context, req *room_p
 		return nil, status.Error(codes.InvalidArgument, "")
 	}
 
+	if req.GetNumberOfRooms() < 1 {
+		zap.S().Infoln("Invalid number of rooms: ", req.GetNumberOfRooms())
+		return nil, status.Error(codes.InvalidArgument, "So luong phong phai lon hon 0")
+	}
+
 	bookedRoomIds, err := utils.ToPgUuidArray(req.GetBookedRoomIds())
 	if err != nil {
 		zap.S().Infoln(err)
 		return nil, status.Error(codes.InvalidArgument, "Loi UUID khong hop le")
 	}
 
-	roomIds, err := rs.service.GetListOfRemainRooms(ctx, roomTypeId, bookedRoomIds, int(req.NumberOfRooms))
+	adjacentRooms := make([]room_service.AdjacentRoom, 0, len(req.GetAdjacentRooms()))
+	for _, adjacentRoom := range req.GetAdjacentRooms() {
+		var roomId pgtype.UUID
+		if err := roomId.Scan(adjacentRoom.GetRoomId()); err != nil {
+			zap.S().Info("Invalid UUID format: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Loi UUID khong hop le")
+		}
+
+		adjacentRooms = append(adjacentRooms, room_service.AdjacentRoom{
+			RoomId:          roomId,
+			TouchesCheckIn:  adjacentRoom.GetTouchesCheckIn(),
+			TouchesCheckOut: adjacentRoom.GetTouchesCheckOut(),
+		})
+	}
+
+	roomIds, err := rs.service.GetListOfRemainRooms(ctx, &room_service.GetListOfRemainRoomsParams{
+		RoomTypeId:         roomTypeId,
+		BookedRoomIds:      bookedRoomIds,
+		NumberOfRooms:      int(req.GetNumberOfRooms()),
+		AllowPartial:       req.GetAllowPartial(),
+		AdjacentRooms:      adjacentRooms,
+		AssignmentStrategy: req.GetAssignmentStrategy(),
+	})
 	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Chien luoc xep phong khong hop le")
+		}
+
 		return nil, status.Error(codes.Internal, "Loi khong the lay danh sach phong trong")
 	}
 
@@ -220,19 +265,26 @@ func (rg *RoomGrpcHandler) CreateRoom(ctx context.Context, req *room_pb.CreateRo
 		return nil, status.Error(codes.InvalidArgument, "")
 	}
 
-	var roomStatus room_repo.NullRoomStatus
-	if err := roomStatus.Scan(req.GetStatus()); err != nil {
-		zap.S().Info("Invalid Room Status: ", err)
-		return nil, status.Error(codes.InvalidArgument, "")
+	// New rooms are AVAILABLE unless told otherwise
+	roomStatus := room_repo.RoomStatusAVAILABLE
+	if req.GetStatus() != "" {
+		roomStatus = room_repo.RoomStatus(req.GetStatus())
+		if roomStatus != room_repo.RoomStatusAVAILABLE && roomStatus != room_repo.RoomStatusMAINTAINED {
+			zap.S().Info("Invalid Room Status: ", req.GetStatus())
+			return nil, status.Error(codes.InvalidArgument, "Trang thai phong khong hop le")
+		}
 	}
 
 	err := rg.service.CreateRoom(ctx, &room_repo.CreateRoomParams{
 		Name:       req.Name,
-		Status:     string(roomStatus.RoomStatus),
+		Status:     roomStatus,
 		RoomTypeID: roomTypeId,
 		HotelID:    hotelId,
 	})
 	if err != nil {
+		if errors.Is(err, common_error.ErrDuplicateRecord) {
+			return nil, status.Error(codes.AlreadyExists, "Ten phong da ton tai trong khach san")
+		}
 		return nil, status.Error(codes.Internal, "Loi khong tao duoc phong")
 	}
 
@@ -39,7 +39,7 @@ func main() {
 	cloudinaryClient, _ := cloudinary.NewFromURL(cloudinaryUrl)
 
 	// 3. Application
-	service := image_service.NewImageService(repo, cloudinaryClient)
+	service := image_service.NewImageService(db, repo, cloudinaryClient)
 
 	// 4. Server
 	handler := image_handler.NewImageGrpcHandler(service)
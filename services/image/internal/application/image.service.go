@@ -6,21 +6,32 @@ import (
 	"sync"
 
 	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
 	image_repo "github.com/098765432m/grpc-kafka/image/internal/infrastructure/repository/sqlc/image"
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
+const imageColumns = `id, public_id, format, hotel_id, user_id, room_type_id`
+
+// Images have no upload time, pages follow the id
+var imageSortColumns = map[string]utils.SortColumn{
+	"id": {Column: "id", Cast: "uuid"},
+}
+
 type ImageService struct {
+	conn *pgxpool.Pool
 	repo *image_repo.Queries
 	cld  *cloudinary.Cloudinary
 }
 
-func NewImageService(repo *image_repo.Queries, cld *cloudinary.Cloudinary) *ImageService {
+func NewImageService(conn *pgxpool.Pool, repo *image_repo.Queries, cld *cloudinary.Cloudinary) *ImageService {
 	return &ImageService{
+		conn: conn,
 		repo: repo,
 		cld:  cld,
 	}
@@ -96,13 +107,13 @@ func (is *ImageService) UpdateImageById(ctx context.Context, id pgtype.UUID, pub
 	return nil
 }
 
-func (is *ImageService) GetImagesByHotelId(ctx context.Context, hotelId pgtype.UUID) ([]image_repo.Image, error) {
-	images, err := is.repo.GetImagesByHotelId(ctx, hotelId)
-	if err != nil {
-		return nil, err
-	}
+// ErrBadRequest when the page is invalid
+func (is *ImageService) GetImagesByHotelId(ctx context.Context, hotelId pgtype.UUID, pageParams utils.PageParams) ([]image_repo.Image, *utils.PageInfo, error) {
 
-	return images, nil
+	conditions := &utils.Conditions{}
+	conditions.Add("hotel_id = $%d", hotelId)
+
+	return is.getImagesPage(ctx, conditions, pageParams)
 }
 
 func (is *ImageService) GetImagesByHotelIds(ctx context.Context, hotelIds []pgtype.UUID) ([]image_repo.Image, error) {
@@ -141,19 +152,36 @@ func (is *ImageService) GetImageByUserId(ctx context.Context, userId pgtype.UUID
 	return &image, nil
 }
 
-func (is *ImageService) GetImagesByRoomTypeId(ctx context.Context, roomTypeId pgtype.UUID) ([]image_repo.Image, error) {
+// ErrBadRequest when the page is invalid
+func (is *ImageService) GetImagesByRoomTypeId(ctx context.Context, roomTypeId pgtype.UUID, pageParams utils.PageParams) ([]image_repo.Image, *utils.PageInfo, error) {
+
+	conditions := &utils.Conditions{}
+	conditions.Add("room_type_id = $%d", roomTypeId)
+
+	return is.getImagesPage(ctx, conditions, pageParams)
+}
 
-	images, err := is.repo.GetImagesByRoomTypeId(ctx, roomTypeId)
+func (is *ImageService) getImagesPage(ctx context.Context, conditions *utils.Conditions, pageParams utils.PageParams) ([]image_repo.Image, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, imageSortColumns, "id", "id")
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			zap.S().Errorln("Failed to get Image by User id: ", err)
-			return nil, common_error.ErrNoRows
-		}
-		zap.S().Errorln("Failed to get Images by Room Type id")
-		return nil, err
+		zap.S().Infoln("Invalid page of Images: ", pageParams)
+		return nil, nil, err
 	}
 
-	return images, nil
+	images, pageInfo, err := utils.QueryPage(ctx, is.conn, page, imageColumns, "images", conditions,
+		func(rows pgx.Rows) (image_repo.Image, error) {
+			var i image_repo.Image
+			err := rows.Scan(&i.ID, &i.PublicID, &i.Format, &i.HotelID, &i.UserID, &i.RoomTypeID)
+			return i, err
+		},
+		func(i image_repo.Image) (any, pgtype.UUID) { return i.ID, i.ID })
+	if err != nil {
+		zap.S().Errorln("Failed to get Images: ", err)
+		return nil, nil, err
+	}
+
+	return images, pageInfo, nil
 }
 
 func (is *ImageService) GetImagesByRoomTypeIds(ctx context.Context, roomTypeIds []pgtype.UUID) ([]image_repo.Image, error) {
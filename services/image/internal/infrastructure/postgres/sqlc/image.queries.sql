@@ -42,21 +42,11 @@ SET
 WHERE id = @id::uuid
 RETURNING id;
 
--- name: GetImagesByHotelId :many
-SELECT *
-FROM images
-WHERE hotel_id = $1;
-
 -- name: GetImageByUserId :one
 SELECT *
 FROM images
 WHERE user_id = $1;
 
--- name: GetImagesByRoomTypeId :many
-SELECT *
-FROM images
-WHERE room_type_id = $1;
-
 -- name: GetImagesByRoomTypeIds :many
 SELECT *
 FROM images
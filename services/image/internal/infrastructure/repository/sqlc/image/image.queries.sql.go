@@ -86,39 +86,6 @@ func (q *Queries) GetImageByUserId(ctx context.Context, userID pgtype.UUID) (Ima
 	return i, err
 }
 
-const getImagesByHotelId = `-- name: GetImagesByHotelId :many
-SELECT id, public_id, format, hotel_id, user_id, room_type_id
-FROM images
-WHERE hotel_id = $1
-`
-
-func (q *Queries) GetImagesByHotelId(ctx context.Context, hotelID pgtype.UUID) ([]Image, error) {
-	rows, err := q.db.Query(ctx, getImagesByHotelId, hotelID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Image
-	for rows.Next() {
-		var i Image
-		if err := rows.Scan(
-			&i.ID,
-			&i.PublicID,
-			&i.Format,
-			&i.HotelID,
-			&i.UserID,
-			&i.RoomTypeID,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const getImagesByHotelIds = `-- name: GetImagesByHotelIds :many
 SELECT id, public_id, format, hotel_id, user_id, room_type_id
 FROM images
@@ -152,39 +119,6 @@ func (q *Queries) GetImagesByHotelIds(ctx context.Context, hotelIds []pgtype.UUI
 	return items, nil
 }
 
-const getImagesByRoomTypeId = `-- name: GetImagesByRoomTypeId :many
-SELECT id, public_id, format, hotel_id, user_id, room_type_id
-FROM images
-WHERE room_type_id = $1
-`
-
-func (q *Queries) GetImagesByRoomTypeId(ctx context.Context, roomTypeID pgtype.UUID) ([]Image, error) {
-	rows, err := q.db.Query(ctx, getImagesByRoomTypeId, roomTypeID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Image
-	for rows.Next() {
-		var i Image
-		if err := rows.Scan(
-			&i.ID,
-			&i.PublicID,
-			&i.Format,
-			&i.HotelID,
-			&i.UserID,
-			&i.RoomTypeID,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const getImagesByRoomTypeIds = `-- name: GetImagesByRoomTypeIds :many
 SELECT id, public_id, format, hotel_id, user_id, room_type_id
 FROM images
@@ -6,6 +6,7 @@ import (
 
 	common_error "github.com/098765432m/grpc-kafka/common/error"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/image_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
 	image_service "github.com/098765432m/grpc-kafka/image/internal/application"
 	image_repo "github.com/098765432m/grpc-kafka/image/internal/infrastructure/repository/sqlc/image"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -78,10 +79,10 @@ func (ig *ImageGrpcHandler) GetImagesByHotelId(ctx context.Context, req *image_p
 		return nil, err
 	}
 
-	images, err := ig.service.GetImagesByHotelId(ctx, hotelId)
+	images, pageInfo, err := ig.service.GetImagesByHotelId(ctx, hotelId, utils.ToPageParams(req.GetPage()))
 	if err != nil {
-		if errors.Is(err, common_error.ErrNoRows) {
-			return nil, nil
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
 		}
 		return nil, err
 	}
@@ -106,6 +107,7 @@ func (ig *ImageGrpcHandler) GetImagesByHotelId(ctx context.Context, req *image_p
 
 	return &image_pb.GetImagesByHotelIdResponse{
 		Images: hotelImages,
+		Page:   utils.ToPageResponsePb(pageInfo),
 	}, nil
 }
 
@@ -150,8 +152,11 @@ func (ig *ImageGrpcHandler) GetImagesByRoomTypeId(ctx context.Context, req *imag
 		return nil, err
 	}
 
-	images, err := ig.service.GetImagesByRoomTypeId(ctx, roomTypeId)
+	images, pageInfo, err := ig.service.GetImagesByRoomTypeId(ctx, roomTypeId, utils.ToPageParams(req.GetPage()))
 	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
 		return nil, err
 	}
 
@@ -175,6 +180,7 @@ func (ig *ImageGrpcHandler) GetImagesByRoomTypeId(ctx context.Context, req *imag
 
 	return &image_pb.GetImagesByRoomTypeIdResponse{
 		Images: roomTypeImages,
+		Page:   utils.ToPageResponsePb(pageInfo),
 	}, nil
 }
 
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/098765432m/grpc-kafka/common/consts"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/loyalty_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	loyalty_service "github.com/098765432m/grpc-kafka/loyalty/internal/application"
+	loyalty_domain "github.com/098765432m/grpc-kafka/loyalty/internal/domain"
+	loyalty_infrastructure "github.com/098765432m/grpc-kafka/loyalty/internal/infrastructure"
+	loyalty_repo "github.com/098765432m/grpc-kafka/loyalty/internal/infrastructure/repository/sqlc/loyalty"
+	loyalty_handler "github.com/098765432m/grpc-kafka/loyalty/internal/interfaces/grpc"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	ctx := context.Background()
+
+	// 1. Config
+	utils.Init()
+
+	dsn := viper.GetString("DB_URL")
+
+	// 2. Infras
+	db, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		zap.S().Fatalln("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	repo := loyalty_repo.New(db)
+
+	// Points are credited from booking status changes
+	consumer, err := loyalty_infrastructure.NewKafkaConsumer(strings.Split(viper.GetString("KAFKA_BROKERS"), ","), consts.BOOKING_STATUS_CHANGED_TOPIC, "loyalty")
+	if err != nil {
+		zap.S().Fatalln("Failed to create kafka consumer: ", err)
+	}
+	defer consumer.Close()
+
+	// 3. Application
+	service := loyalty_service.NewLoyaltyService(db, repo)
+
+	go consumer.Consume(ctx, func(event loyalty_domain.BookingStatusChangedEvent) error {
+		return service.EarnPoints(ctx, event)
+	})
+	go service.StartPointsExpiry(ctx, time.Hour)
+
+	// 4. Server
+	handler := loyalty_handler.NewLoyaltyGrpcHandler(service)
+
+	// 5. Start Server
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", consts.LOYALTY_GRPC_PORT))
+	if err != nil {
+		zap.S().Fatalln("Failed to Start server on port: ", consts.LOYALTY_GRPC_PORT, err)
+	}
+
+	grpc := grpc.NewServer()
+
+	loyalty_pb.RegisterLoyaltyServiceServer(grpc, handler)
+
+	zap.S().Infof("Running grpc server on port %d, %v", consts.LOYALTY_GRPC_PORT, err)
+	if err := grpc.Serve(lis); err != nil {
+		zap.S().Fatal("Failed to start grpc server: ", err)
+	}
+}
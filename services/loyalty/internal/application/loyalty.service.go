@@ -0,0 +1,518 @@
+package loyalty_service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	loyalty_domain "github.com/098765432m/grpc-kafka/loyalty/internal/domain"
+	loyalty_repo "github.com/098765432m/grpc-kafka/loyalty/internal/infrastructure/repository/sqlc/loyalty"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+var ErrInsufficientPoints = errors.New("insufficient loyalty points")
+
+type LoyaltyService struct {
+	conn *pgxpool.Pool
+	repo *loyalty_repo.Queries
+}
+
+func NewLoyaltyService(conn *pgxpool.Pool, repo *loyalty_repo.Queries) *LoyaltyService {
+	return &LoyaltyService{
+		conn: conn,
+		repo: repo,
+	}
+}
+
+// Balance, tier and progress to the next tier of a member, members without postings have an empty account
+func (ls *LoyaltyService) GetLoyaltyAccount(ctx context.Context, userId pgtype.UUID) (*loyalty_domain.LoyaltyAccount, error) {
+
+	balance, err := ls.repo.GetMemberBalance(ctx, userId)
+	if err != nil {
+		zap.S().Errorln("Failed to get member balance: ", err)
+		return nil, err
+	}
+
+	nights, err := ls.getMemberNights(ctx, ls.repo, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tier, nextTier := loyalty_domain.TierOf(nights)
+
+	account := &loyalty_domain.LoyaltyAccount{
+		UserId:   userId.String(),
+		Balance:  int(balance),
+		Nights:   nights,
+		Tier:     tier,
+		NextTier: nextTier,
+	}
+	if nextTier != nil {
+		account.NightsToNextTier = nextTier.MinNights - nights
+	}
+
+	return account, nil
+}
+
+// Checked out nights in the rolling last 12 months
+func (ls *LoyaltyService) getMemberNights(ctx context.Context, repo *loyalty_repo.Queries, userId pgtype.UUID) (int, error) {
+
+	nights, err := repo.GetMemberNights(ctx, loyalty_repo.GetMemberNightsParams{
+		UserID: userId,
+		Since:  pgtype.Timestamp{Time: time.Now().AddDate(-1, 0, 0), Valid: true},
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get member nights: ", err)
+		return 0, err
+	}
+
+	return int(nights), nil
+}
+
+func (ls *LoyaltyService) GetLoyaltyTransactions(ctx context.Context, userId pgtype.UUID, size int, offset int) ([]loyalty_repo.GetLoyaltyTransactionsByUserIdRow, error) {
+
+	if size <= 0 {
+		size = 20
+	}
+
+	transactions, err := ls.repo.GetLoyaltyTransactionsByUserId(ctx, loyalty_repo.GetLoyaltyTransactionsByUserIdParams{
+		UserID:      userId,
+		Size:        int32(size),
+		OffsetCount: int32(offset),
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get loyalty transactions: ", err)
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// Credit points of a checked out stay, the stay is only credited once
+func (ls *LoyaltyService) EarnPoints(ctx context.Context, event loyalty_domain.BookingStatusChangedEvent) error {
+
+	// Channel reservations have no member to credit
+	if event.Status != "CHECK_OUT" || event.UserId == "" {
+		return nil
+	}
+
+	var userId, bookingId, hotelId pgtype.UUID
+	if err := userId.Scan(event.UserId); err != nil {
+		zap.S().Infoln("Invalid User UUID of booking event: ", err)
+		return common_error.ErrBadRequest
+	}
+	if err := bookingId.Scan(event.BookingId); err != nil {
+		zap.S().Infoln("Invalid Booking UUID of booking event: ", err)
+		return common_error.ErrBadRequest
+	}
+	if err := hotelId.Scan(event.HotelId); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID of booking event: ", err)
+		return common_error.ErrBadRequest
+	}
+
+	earnPercent, err := ls.GetHotelEarnPercent(ctx, hotelId)
+	if err != nil {
+		return err
+	}
+
+	tx, err := ls.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin earn points transaction: ", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := ls.repo.WithTx(tx)
+
+	// Bonus of the tier reached before this stay
+	nights, err := ls.getMemberNights(ctx, qtx, userId)
+	if err != nil {
+		return err
+	}
+	tier, _ := loyalty_domain.TierOf(nights)
+
+	points := earnedPoints(event.Total, earnPercent, tier)
+
+	// Stays too cheap to earn points still count their nights for the tier
+	_, err = ls.post(ctx, qtx, loyalty_repo.CreateLoyaltyTransactionParams{
+		Type:      loyalty_repo.LoyaltyTransactionTypeEARN,
+		UserID:    userId,
+		BookingID: bookingId,
+		HotelID:   hotelId,
+		Nights:    int32(event.CheckOut.Sub(event.CheckIn).Hours() / 24),
+		ExpiresAt: pgtype.Timestamp{Time: time.Now().Add(loyalty_domain.POINTS_VALIDITY), Valid: true},
+		Note:      "stay " + event.BookingId,
+	}, loyalty_repo.LoyaltyAccountTypeISSUED, points)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Infoln("Stay already earned points: ", event.BookingId)
+			return nil
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit earned points: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// Points of a stay at the earn percent of the hotel, raised by the bonus of the member tier
+func earnedPoints(total int, earnPercent int, tier loyalty_domain.Tier) int {
+	return total * earnPercent / 100 * (100 + tier.BonusEarnPercent) / 100
+}
+
+// Spend points as a discount on a booking, the points are given back when the booking is cancelled
+func (ls *LoyaltyService) RedeemPoints(ctx context.Context, userId pgtype.UUID, bookingId pgtype.UUID, points int, note string) (*loyalty_repo.LoyaltyTransaction, error) {
+
+	if points <= 0 {
+		zap.S().Infoln("Redeemed points must be positive")
+		return nil, common_error.ErrBadRequest
+	}
+
+	if !bookingId.Valid {
+		zap.S().Infoln("Redeemed points must pay a booking")
+		return nil, common_error.ErrBadRequest
+	}
+
+	tx, err := ls.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin redeem points transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := ls.repo.WithTx(tx)
+
+	if err := ls.checkBalance(ctx, qtx, userId, points); err != nil {
+		return nil, err
+	}
+
+	transaction, err := ls.post(ctx, qtx, loyalty_repo.CreateLoyaltyTransactionParams{
+		Type:      loyalty_repo.LoyaltyTransactionTypeREDEEM,
+		UserID:    userId,
+		BookingID: bookingId,
+		Note:      note,
+	}, loyalty_repo.LoyaltyAccountTypeREDEEMED, -points)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Infoln("Booking already redeemed points: ", bookingId.String())
+			return nil, common_error.ErrDuplicateRecord
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit redeemed points: ", err)
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// Give back points of a redemption, a redemption is only given back once
+func (ls *LoyaltyService) CancelRedemption(ctx context.Context, transactionId pgtype.UUID) error {
+
+	redemption, err := ls.repo.GetLoyaltyTransactionById(ctx, transactionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Redemption not found")
+			return common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to get loyalty transaction: ", err)
+		return err
+	}
+
+	if redemption.Type != loyalty_repo.LoyaltyTransactionTypeREDEEM {
+		zap.S().Infoln("Only redemptions can be cancelled")
+		return common_error.ErrBadRequest
+	}
+
+	return ls.reverseRedemption(ctx, redemption.ID, redemption.UserID, int(redemption.Points))
+}
+
+// Give back points spent on a cancelled booking
+func (ls *LoyaltyService) CancelBookingRedemption(ctx context.Context, bookingId pgtype.UUID) error {
+
+	redemption, err := ls.repo.GetRedemptionByBookingId(ctx, bookingId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Booking did not redeem points")
+			return common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to get redemption of booking: ", err)
+		return err
+	}
+
+	return ls.reverseRedemption(ctx, redemption.ID, redemption.UserID, int(redemption.Points))
+}
+
+// Post the opposite of the redeemed points, points is the negative amount of the redemption
+func (ls *LoyaltyService) reverseRedemption(ctx context.Context, redemptionId pgtype.UUID, userId pgtype.UUID, points int) error {
+
+	tx, err := ls.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin cancel redemption transaction: ", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = ls.post(ctx, ls.repo.WithTx(tx), loyalty_repo.CreateLoyaltyTransactionParams{
+		Type:                  loyalty_repo.LoyaltyTransactionTypeADJUST,
+		UserID:                userId,
+		ReversesTransactionID: redemptionId,
+		Note:                  "cancelled redemption",
+	}, loyalty_repo.LoyaltyAccountTypeREDEEMED, -points)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Infoln("Redemption already cancelled")
+			return nil
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit cancelled redemption: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// Manual correction by staff, a negative amount takes points away
+func (ls *LoyaltyService) AdjustPoints(ctx context.Context, userId pgtype.UUID, points int, note string) (*loyalty_repo.LoyaltyTransaction, error) {
+
+	if points == 0 || note == "" {
+		zap.S().Infoln("Adjustment must have points and a note")
+		return nil, common_error.ErrBadRequest
+	}
+
+	tx, err := ls.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin adjust points transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := ls.repo.WithTx(tx)
+
+	if points < 0 {
+		if err := ls.checkBalance(ctx, qtx, userId, -points); err != nil {
+			return nil, err
+		}
+	}
+
+	params := loyalty_repo.CreateLoyaltyTransactionParams{
+		Type:   loyalty_repo.LoyaltyTransactionTypeADJUST,
+		UserID: userId,
+		Note:   note,
+	}
+	// Points given by staff expire like earned points
+	if points > 0 {
+		params.ExpiresAt = pgtype.Timestamp{Time: time.Now().Add(loyalty_domain.POINTS_VALIDITY), Valid: true}
+	}
+
+	transaction, err := ls.post(ctx, qtx, params, loyalty_repo.LoyaltyAccountTypeADJUSTMENT, points)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit adjusted points: ", err)
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// Expire what is left of points earned more than 12 months ago
+func (ls *LoyaltyService) ExpirePoints(ctx context.Context, now time.Time) error {
+
+	expiringPoints, err := ls.repo.GetExpiringPoints(ctx, loyalty_repo.GetExpiringPointsParams{
+		Now: pgtype.Timestamp{Time: now, Valid: true},
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to get expiring points: ", err)
+		return err
+	}
+
+	for _, expiring := range expiringPoints {
+		if err := ls.expireMemberPoints(ctx, expiring.UserID, now); err != nil {
+			zap.S().Errorln("Failed to expire points of member: ", expiring.UserID.String(), err)
+			continue
+		}
+	}
+
+	return nil
+}
+
+// Points are counted again with the member account locked, so concurrent runs and redemptions
+// cannot expire the same points twice or expire points that were just spent
+func (ls *LoyaltyService) expireMemberPoints(ctx context.Context, userId pgtype.UUID, now time.Time) error {
+
+	tx, err := ls.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := ls.repo.WithTx(tx)
+
+	if _, err := qtx.UpsertMemberAccount(ctx, userId); err != nil {
+		return err
+	}
+
+	expiringPoints, err := qtx.GetExpiringPoints(ctx, loyalty_repo.GetExpiringPointsParams{
+		Now:    pgtype.Timestamp{Time: now, Valid: true},
+		UserID: userId,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Expired by another run in the meantime
+	if len(expiringPoints) == 0 {
+		return nil
+	}
+
+	if _, err := ls.post(ctx, qtx, loyalty_repo.CreateLoyaltyTransactionParams{
+		Type:   loyalty_repo.LoyaltyTransactionTypeEXPIRE,
+		UserID: userId,
+		Note:   "points expired",
+	}, loyalty_repo.LoyaltyAccountTypeEXPIRED, -int(expiringPoints[0].Points)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Expire points every interval
+func (ls *LoyaltyService) StartPointsExpiry(ctx context.Context, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := ls.ExpirePoints(ctx, now); err != nil {
+				zap.S().Errorln("Failed to expire points: ", err)
+			}
+		}
+	}
+}
+
+func (ls *LoyaltyService) SetHotelLoyaltySetting(ctx context.Context, hotelId pgtype.UUID, earnPercent int) (*loyalty_repo.HotelLoyaltySetting, error) {
+
+	if earnPercent < 0 || earnPercent > 100 {
+		zap.S().Infoln("Earn percent must be between 0 and 100")
+		return nil, common_error.ErrBadRequest
+	}
+
+	setting, err := ls.repo.UpsertHotelLoyaltySetting(ctx, loyalty_repo.UpsertHotelLoyaltySettingParams{
+		HotelID:     hotelId,
+		EarnPercent: int32(earnPercent),
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to set Hotel loyalty setting: ", err)
+		return nil, err
+	}
+
+	return &setting, nil
+}
+
+// Earn percent of the hotel, the default when the hotel has no setting
+func (ls *LoyaltyService) GetHotelEarnPercent(ctx context.Context, hotelId pgtype.UUID) (int, error) {
+
+	setting, err := ls.repo.GetHotelLoyaltySetting(ctx, hotelId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return loyalty_domain.DEFAULT_EARN_PERCENT, nil
+		}
+
+		zap.S().Errorln("Failed to get Hotel loyalty setting: ", err)
+		return 0, err
+	}
+
+	return int(setting.EarnPercent), nil
+}
+
+// Lock account of the member and make sure it holds the points
+func (ls *LoyaltyService) checkBalance(ctx context.Context, qtx *loyalty_repo.Queries, userId pgtype.UUID, points int) error {
+
+	if _, err := qtx.UpsertMemberAccount(ctx, userId); err != nil {
+		zap.S().Errorln("Failed to lock member account: ", err)
+		return err
+	}
+
+	balance, err := qtx.GetMemberBalance(ctx, userId)
+	if err != nil {
+		zap.S().Errorln("Failed to get member balance: ", err)
+		return err
+	}
+
+	if int(balance) < points {
+		zap.S().Infoln("Member does not have enough points")
+		return ErrInsufficientPoints
+	}
+
+	return nil
+}
+
+// Post a balanced transaction: amount to the member account and its opposite to the system account
+func (ls *LoyaltyService) post(ctx context.Context, qtx *loyalty_repo.Queries, params loyalty_repo.CreateLoyaltyTransactionParams, counterpart loyalty_repo.LoyaltyAccountType, amount int) (*loyalty_repo.LoyaltyTransaction, error) {
+
+	params.Actor = utils.ActorFromContext(ctx)
+	if params.Actor == "" {
+		params.Actor = "system"
+	}
+
+	transaction, err := qtx.CreateLoyaltyTransaction(ctx, params)
+	if err != nil {
+		zap.S().Errorln("Failed to create loyalty transaction: ", err)
+		return nil, err
+	}
+
+	if amount == 0 {
+		return &transaction, nil
+	}
+
+	memberAccountId, err := qtx.UpsertMemberAccount(ctx, params.UserID)
+	if err != nil {
+		zap.S().Errorln("Failed to get member account: ", err)
+		return nil, err
+	}
+
+	systemAccountId, err := qtx.GetSystemAccountId(ctx, counterpart)
+	if err != nil {
+		zap.S().Errorln("Failed to get system account: ", err)
+		return nil, err
+	}
+
+	for _, entry := range []loyalty_repo.CreateLoyaltyEntryParams{
+		{TransactionID: transaction.ID, AccountID: memberAccountId, Amount: int32(amount)},
+		{TransactionID: transaction.ID, AccountID: systemAccountId, Amount: int32(-amount)},
+	} {
+		if err := qtx.CreateLoyaltyEntry(ctx, entry); err != nil {
+			zap.S().Errorln("Failed to create loyalty entry: ", err)
+			return nil, err
+		}
+	}
+
+	return &transaction, nil
+}
@@ -0,0 +1,115 @@
+package loyalty_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	loyalty_domain "github.com/098765432m/grpc-kafka/loyalty/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestEarnedPoints(t *testing.T) {
+	member, _ := loyalty_domain.TierOf(0)
+	silver, _ := loyalty_domain.TierOf(10)
+	gold, _ := loyalty_domain.TierOf(25)
+
+	tests := []struct {
+		name        string
+		total       int
+		earnPercent int
+		tier        loyalty_domain.Tier
+		want        int
+	}{
+		{"default earn percent", 1000, loyalty_domain.DEFAULT_EARN_PERCENT, member, 50},
+		{"silver bonus", 1000, 5, silver, 62},
+		{"gold bonus", 1000, 5, gold, 75},
+		{"hotel earning nothing", 1000, 0, gold, 0},
+		{"stay too cheap to earn", 10, 5, member, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := earnedPoints(tt.total, tt.earnPercent, tt.tier); got != tt.want {
+				t.Errorf("earnedPoints() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEarnPointsSkipsOrRejects(t *testing.T) {
+	const validId = "6f1c2b7e-3d4a-4e5f-8a9b-0c1d2e3f4a5b"
+	checkIn := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		event   loyalty_domain.BookingStatusChangedEvent
+		wantErr error
+	}{
+		{"not checked out", loyalty_domain.BookingStatusChangedEvent{BookingId: validId, UserId: validId, HotelId: validId, Status: "CHECK_IN"}, nil},
+		{"channel reservation without member", loyalty_domain.BookingStatusChangedEvent{BookingId: validId, HotelId: validId, Status: "CHECK_OUT"}, nil},
+		{"invalid user", loyalty_domain.BookingStatusChangedEvent{BookingId: validId, UserId: "user-1", HotelId: validId, Status: "CHECK_OUT"}, common_error.ErrBadRequest},
+		{"invalid booking", loyalty_domain.BookingStatusChangedEvent{BookingId: "booking-1", UserId: validId, HotelId: validId, Status: "CHECK_OUT"}, common_error.ErrBadRequest},
+		{"invalid hotel", loyalty_domain.BookingStatusChangedEvent{BookingId: validId, UserId: validId, HotelId: "hotel-1", Status: "CHECK_OUT", CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2)}, common_error.ErrBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := &LoyaltyService{}
+
+			if err := ls.EarnPoints(context.Background(), tt.event); !errors.Is(err, tt.wantErr) {
+				t.Errorf("EarnPoints() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoyaltyInvalidRequests(t *testing.T) {
+	userId := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	bookingId := pgtype.UUID{Bytes: [16]byte{2}, Valid: true}
+	hotelId := pgtype.UUID{Bytes: [16]byte{3}, Valid: true}
+
+	tests := []struct {
+		name string
+		call func(ls *LoyaltyService) error
+	}{
+		{"redeem no points", func(ls *LoyaltyService) error {
+			_, err := ls.RedeemPoints(context.Background(), userId, bookingId, 0, "")
+			return err
+		}},
+		{"redeem negative points", func(ls *LoyaltyService) error {
+			_, err := ls.RedeemPoints(context.Background(), userId, bookingId, -10, "")
+			return err
+		}},
+		{"redeem without booking", func(ls *LoyaltyService) error {
+			_, err := ls.RedeemPoints(context.Background(), userId, pgtype.UUID{}, 10, "")
+			return err
+		}},
+		{"adjust no points", func(ls *LoyaltyService) error {
+			_, err := ls.AdjustPoints(context.Background(), userId, 0, "goodwill")
+			return err
+		}},
+		{"adjust without note", func(ls *LoyaltyService) error {
+			_, err := ls.AdjustPoints(context.Background(), userId, 100, "")
+			return err
+		}},
+		{"negative earn percent", func(ls *LoyaltyService) error {
+			_, err := ls.SetHotelLoyaltySetting(context.Background(), hotelId, -1)
+			return err
+		}},
+		{"earn percent over 100", func(ls *LoyaltyService) error {
+			_, err := ls.SetHotelLoyaltySetting(context.Background(), hotelId, 101)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(&LoyaltyService{}); !errors.Is(err, common_error.ErrBadRequest) {
+				t.Errorf("error = %v, want %v", err, common_error.ErrBadRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package loyalty_domain
+
+import "time"
+
+const (
+	MEMBER_TIER = "MEMBER"
+	SILVER_TIER = "SILVER"
+	GOLD_TIER   = "GOLD"
+)
+
+// Earned points can be spent for 12 months
+const POINTS_VALIDITY = 365 * 24 * time.Hour
+
+// Points earned per 100 of the stay total at hotels without a setting, one point is worth 1 of the price
+const DEFAULT_EARN_PERCENT = 5
+
+type Tier struct {
+	Name             string
+	MinNights        int // Checked out nights in the last 12 months
+	DiscountPercent  int // Off the price of direct bookings
+	BonusEarnPercent int // Extra points on top of the earn percent of the hotel
+}
+
+// Highest tier first
+var Tiers = []Tier{
+	{Name: GOLD_TIER, MinNights: 25, DiscountPercent: 10, BonusEarnPercent: 50},
+	{Name: SILVER_TIER, MinNights: 10, DiscountPercent: 5, BonusEarnPercent: 25},
+	{Name: MEMBER_TIER, MinNights: 0},
+}
+
+// Tier reached with the nights and the next one, next is nil on the highest tier
+func TierOf(nights int) (Tier, *Tier) {
+	for index, tier := range Tiers {
+		if nights >= tier.MinNights {
+			if index == 0 {
+				return tier, nil
+			}
+			return tier, &Tiers[index-1]
+		}
+	}
+
+	return Tiers[len(Tiers)-1], &Tiers[len(Tiers)-2]
+}
+
+type LoyaltyAccount struct {
+	UserId           string
+	Balance          int
+	Nights           int
+	Tier             Tier
+	NextTier         *Tier
+	NightsToNextTier int
+}
+
+// Consumed from booking service when the night audit changes status of a booking
+type BookingStatusChangedEvent struct {
+	BookingId string    `json:"booking_id"`
+	UserId    string    `json:"user_id"`
+	HotelId   string    `json:"hotel_id"`
+	CheckIn   time.Time `json:"check_in"`
+	CheckOut  time.Time `json:"check_out"`
+	Total     int       `json:"total"`
+	Status    string    `json:"status"`
+}
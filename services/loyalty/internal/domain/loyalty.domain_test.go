@@ -0,0 +1,36 @@
+package loyalty_domain
+
+import "testing"
+
+func TestTierOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		nights   int
+		want     string
+		wantNext string // Empty on the highest tier
+	}{
+		{"new member", 0, MEMBER_TIER, SILVER_TIER},
+		{"one night short of silver", 9, MEMBER_TIER, SILVER_TIER},
+		{"silver", 10, SILVER_TIER, GOLD_TIER},
+		{"one night short of gold", 24, SILVER_TIER, GOLD_TIER},
+		{"gold", 25, GOLD_TIER, ""},
+		{"well over gold", 120, GOLD_TIER, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier, next := TierOf(tt.nights)
+			if tier.Name != tt.want {
+				t.Errorf("TierOf(%d) = %s, want %s", tt.nights, tier.Name, tt.want)
+			}
+
+			nextName := ""
+			if next != nil {
+				nextName = next.Name
+			}
+			if nextName != tt.wantNext {
+				t.Errorf("TierOf(%d) next = %q, want %q", tt.nights, nextName, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package loyalty_infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/098765432m/grpc-kafka/common/consts"
+	loyalty_domain "github.com/098765432m/grpc-kafka/loyalty/internal/domain"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+)
+
+// Attempts of a record before it is parked on the dead letter topic
+const CONSUME_MAX_ATTEMPTS = 5
+
+// Wait before the first retry, doubled on each attempt
+const CONSUME_RETRY_BACKOFF = time.Second
+
+type KafkaConsumer struct {
+	cl *kgo.Client
+}
+
+func NewKafkaConsumer(brokers []string, topics, groupId string) (*KafkaConsumer, error) {
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(brokers...),
+		kgo.ConsumeTopics(topics),
+		kgo.ConsumerGroup(groupId),
+		kgo.DisableAutoCommit(),
+	}
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KafkaConsumer{
+		cl: client,
+	}, nil
+}
+
+func (kc *KafkaConsumer) Close() {
+	kc.cl.Close()
+}
+
+// Records are handled in order, a failed record is retried and then parked on the dead letter topic.
+// Its offset is only committed once it is handled or parked, so a failed record is never skipped by committing the ones after it
+func (kc *KafkaConsumer) Consume(ctx context.Context, handler func(loyalty_domain.BookingStatusChangedEvent) error) {
+
+	for {
+		fetches := kc.cl.PollFetches(ctx)
+		if fetches.IsClientClosed() || ctx.Err() != nil {
+			return
+		}
+
+		fetches.EachError(func(t string, p int32, err error) {
+			zap.S().Infof("fetch err topic %s partition %d: %v", t, p, err)
+		})
+
+		fetches.EachRecord(func(r *kgo.Record) {
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err := kc.handle(ctx, r, handler); err != nil {
+				zap.S().Errorln("Failed to handle booking status changed event, parking it: ", err)
+
+				if !kc.park(ctx, r, err) {
+					return
+				}
+			}
+
+			if err := kc.cl.CommitRecords(ctx, r); err != nil {
+				zap.S().Infof("Failed to commit record: %v", err)
+			}
+		})
+	}
+}
+
+func (kc *KafkaConsumer) handle(ctx context.Context, r *kgo.Record, handler func(loyalty_domain.BookingStatusChangedEvent) error) error {
+
+	// A record that cannot be read fails the same way on every attempt
+	var event loyalty_domain.BookingStatusChangedEvent
+	if err := json.Unmarshal(r.Value, &event); err != nil {
+		return err
+	}
+
+	backoff := CONSUME_RETRY_BACKOFF
+	var err error
+	for attempt := 1; attempt <= CONSUME_MAX_ATTEMPTS; attempt++ {
+		if err = handler(event); err == nil {
+			return nil
+		}
+
+		if attempt == CONSUME_MAX_ATTEMPTS || !sleep(ctx, backoff) {
+			break
+		}
+		zap.S().Infof("Failed to handle booking status changed event, attempt %d: %v", attempt, err)
+		backoff *= 2
+	}
+
+	return err
+}
+
+// Copy the record to the dead letter topic with the error, retried until it is written or the consumer stops.
+// Returns false when the consumer stopped first, the record is then not committed
+func (kc *KafkaConsumer) park(ctx context.Context, r *kgo.Record, handleErr error) bool {
+
+	deadLetter := &kgo.Record{
+		Topic: consts.LOYALTY_BOOKING_STATUS_CHANGED_DLQ_TOPIC,
+		Key:   r.Key,
+		Value: r.Value,
+		Headers: append(r.Headers,
+			kgo.RecordHeader{Key: "error", Value: []byte(handleErr.Error())},
+			kgo.RecordHeader{Key: "source-topic", Value: []byte(r.Topic)},
+		),
+	}
+
+	for {
+		err := kc.cl.ProduceSync(ctx, deadLetter).FirstErr()
+		if err == nil {
+			return true
+		}
+
+		zap.S().Errorln("Failed to park record on dead letter topic: ", err)
+		if !sleep(ctx, CONSUME_RETRY_BACKOFF) {
+			return false
+		}
+	}
+}
+
+// Returns false when the context ends first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,157 @@
+-- name: UpsertMemberAccount :one
+-- Create the account of the member on its first posting, the row stays locked until the transaction ends
+INSERT INTO loyalty_accounts
+(
+    type,
+    user_id
+)
+VALUES
+(
+    'MEMBER',
+    @user_id::uuid
+)
+ON CONFLICT (user_id) DO UPDATE
+SET user_id = EXCLUDED.user_id
+RETURNING id;
+
+-- name: GetSystemAccountId :one
+SELECT id
+FROM loyalty_accounts
+WHERE
+    type = @type::LOYALTY_ACCOUNT_TYPE
+    AND user_id IS NULL;
+
+-- name: CreateLoyaltyTransaction :one
+INSERT INTO loyalty_transactions
+(
+    type,
+    user_id,
+    booking_id,
+    hotel_id,
+    nights,
+    expires_at,
+    reverses_transaction_id,
+    note,
+    actor
+)
+VALUES
+(
+    @type::LOYALTY_TRANSACTION_TYPE,
+    @user_id::uuid,
+    sqlc.narg(booking_id)::uuid,
+    sqlc.narg(hotel_id)::uuid,
+    @nights::int,
+    sqlc.narg(expires_at)::timestamp,
+    sqlc.narg(reverses_transaction_id)::uuid,
+    @note::text,
+    @actor::text
+)
+RETURNING *;
+
+-- name: CreateLoyaltyEntry :exec
+INSERT INTO loyalty_entries
+(
+    transaction_id,
+    account_id,
+    amount
+)
+VALUES
+(
+    @transaction_id::uuid,
+    @account_id::uuid,
+    @amount::int
+);
+
+-- name: GetMemberBalance :one
+SELECT COALESCE(SUM(e.amount), 0)::int
+FROM loyalty_entries e
+JOIN loyalty_accounts a ON a.id = e.account_id
+WHERE a.user_id = @user_id::uuid;
+
+-- name: GetMemberNights :one
+-- Nights of stays checked out since the date, for the tier
+SELECT COALESCE(SUM(nights), 0)::int
+FROM loyalty_transactions
+WHERE
+    user_id = @user_id::uuid
+    AND type = 'EARN'
+    AND create_at >= @since::timestamp;
+
+-- name: GetLoyaltyTransactionById :one
+SELECT
+    t.*,
+    COALESCE(e.amount, 0)::int AS points
+FROM loyalty_transactions t
+LEFT JOIN loyalty_entries e ON e.transaction_id = t.id
+    AND e.account_id = (SELECT a.id FROM loyalty_accounts a WHERE a.user_id = t.user_id)
+WHERE t.id = @id::uuid;
+
+-- name: GetRedemptionByBookingId :one
+SELECT
+    t.*,
+    COALESCE(e.amount, 0)::int AS points
+FROM loyalty_transactions t
+LEFT JOIN loyalty_entries e ON e.transaction_id = t.id
+    AND e.account_id = (SELECT a.id FROM loyalty_accounts a WHERE a.user_id = t.user_id)
+WHERE
+    t.booking_id = @booking_id::uuid
+    AND t.type = 'REDEEM';
+
+-- name: GetLoyaltyTransactionsByUserId :many
+SELECT
+    t.*,
+    COALESCE(e.amount, 0)::int AS points
+FROM loyalty_transactions t
+LEFT JOIN loyalty_entries e ON e.transaction_id = t.id
+    AND e.account_id = (SELECT a.id FROM loyalty_accounts a WHERE a.user_id = t.user_id)
+WHERE t.user_id = @user_id::uuid
+ORDER BY t.create_at DESC
+LIMIT @size::int
+OFFSET @offset_count::int;
+
+-- name: GetExpiringPoints :many
+-- Points are spent oldest first, so what is left of points credited before now is expired, never more than the balance.
+-- Every debit (redeem, expire, negative adjustment) spends points, a cancelled redemption gives them back.
+-- Every member when user_id is null
+SELECT
+    user_id,
+    points
+FROM (
+    SELECT
+        a.user_id,
+        LEAST(
+            COALESCE(SUM(e.amount) FILTER (WHERE e.amount > 0 AND t.expires_at <= @now::timestamp), 0)
+                + COALESCE(SUM(e.amount) FILTER (WHERE e.amount < 0 OR t.reverses_transaction_id IS NOT NULL), 0),
+            COALESCE(SUM(e.amount), 0)
+        )::int AS points
+    FROM loyalty_accounts a
+    JOIN loyalty_entries e ON e.account_id = a.id
+    JOIN loyalty_transactions t ON t.id = e.transaction_id
+    WHERE
+        a.type = 'MEMBER'
+        AND (sqlc.narg(user_id)::uuid IS NULL OR a.user_id = sqlc.narg(user_id)::uuid)
+    GROUP BY a.user_id
+) expiring
+WHERE points > 0;
+
+-- name: UpsertHotelLoyaltySetting :one
+INSERT INTO hotel_loyalty_settings
+(
+    hotel_id,
+    earn_percent
+)
+VALUES
+(
+    @hotel_id::uuid,
+    @earn_percent::int
+)
+ON CONFLICT (hotel_id) DO UPDATE
+SET
+    earn_percent = EXCLUDED.earn_percent,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetHotelLoyaltySetting :one
+SELECT *
+FROM hotel_loyalty_settings
+WHERE hotel_id = @hotel_id::uuid;
@@ -0,0 +1,89 @@
+CREATE TYPE LOYALTY_ACCOUNT_TYPE AS ENUM ('MEMBER', 'ISSUED', 'REDEEMED', 'EXPIRED', 'ADJUSTMENT');
+
+CREATE TYPE LOYALTY_TRANSACTION_TYPE AS ENUM ('EARN', 'REDEEM', 'EXPIRE', 'ADJUST');
+
+-- Points of a member are held in its MEMBER account, the other accounts are the counterpart of every posting
+CREATE TABLE loyalty_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    type LOYALTY_ACCOUNT_TYPE NOT NULL,
+    user_id UUID UNIQUE,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((type = 'MEMBER') = (user_id IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX loyalty_accounts_system_type_idx ON loyalty_accounts (type) WHERE user_id IS NULL;
+
+INSERT INTO loyalty_accounts (type) VALUES ('ISSUED'), ('REDEEMED'), ('EXPIRED'), ('ADJUSTMENT');
+
+CREATE TABLE loyalty_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    type LOYALTY_TRANSACTION_TYPE NOT NULL,
+    user_id UUID NOT NULL,
+    booking_id UUID,
+    hotel_id UUID,
+    -- Nights of the checked out stay, counted for the tier
+    nights INT NOT NULL DEFAULT 0,
+    -- Earned points expire after this time
+    expires_at TIMESTAMP,
+    -- Redemption given back by this transaction
+    reverses_transaction_id UUID UNIQUE REFERENCES loyalty_transactions (id),
+    note TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT 'system',
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A stay earns points once even if its event is consumed again
+CREATE UNIQUE INDEX loyalty_transactions_earn_booking_idx ON loyalty_transactions (booking_id) WHERE type = 'EARN';
+
+-- Points spent on a booking are redeemed once and given back when the booking is cancelled
+CREATE UNIQUE INDEX loyalty_transactions_redeem_booking_idx ON loyalty_transactions (booking_id) WHERE type = 'REDEEM';
+
+CREATE INDEX loyalty_transactions_user_id_idx ON loyalty_transactions (user_id, create_at);
+
+-- Credit is positive and debit is negative
+CREATE TABLE loyalty_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES loyalty_transactions (id),
+    account_id UUID NOT NULL REFERENCES loyalty_accounts (id),
+    amount INT NOT NULL CHECK (amount <> 0)
+);
+
+CREATE INDEX loyalty_entries_account_id_idx ON loyalty_entries (account_id);
+
+CREATE INDEX loyalty_entries_transaction_id_idx ON loyalty_entries (transaction_id);
+
+-- Entries of a transaction must sum to zero when it commits
+CREATE FUNCTION check_loyalty_transaction_balance() RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM loyalty_entries WHERE transaction_id = NEW.transaction_id) <> 0 THEN
+        RAISE EXCEPTION 'loyalty transaction % is not balanced', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER loyalty_entries_balanced
+AFTER INSERT ON loyalty_entries
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE FUNCTION check_loyalty_transaction_balance();
+
+CREATE FUNCTION prevent_loyalty_ledger_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'loyalty ledger is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER loyalty_transactions_append_only
+BEFORE UPDATE OR DELETE ON loyalty_transactions
+FOR EACH ROW EXECUTE FUNCTION prevent_loyalty_ledger_change();
+
+CREATE TRIGGER loyalty_entries_append_only
+BEFORE UPDATE OR DELETE ON loyalty_entries
+FOR EACH ROW EXECUTE FUNCTION prevent_loyalty_ledger_change();
+
+-- Hotels without a setting earn the default percent
+CREATE TABLE hotel_loyalty_settings (
+    hotel_id UUID PRIMARY KEY,
+    earn_percent INT NOT NULL CHECK (earn_percent BETWEEN 0 AND 100),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
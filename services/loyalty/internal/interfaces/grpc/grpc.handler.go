@@ -0,0 +1,241 @@
+package loyalty_handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/loyalty_pb"
+	loyalty_service "github.com/098765432m/grpc-kafka/loyalty/internal/application"
+	loyalty_repo "github.com/098765432m/grpc-kafka/loyalty/internal/infrastructure/repository/sqlc/loyalty"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type LoyaltyGrpcHandler struct {
+	loyalty_pb.UnimplementedLoyaltyServiceServer
+	service *loyalty_service.LoyaltyService
+}
+
+func NewLoyaltyGrpcHandler(service *loyalty_service.LoyaltyService) *LoyaltyGrpcHandler {
+	return &LoyaltyGrpcHandler{
+		service: service,
+	}
+}
+
+func (lg *LoyaltyGrpcHandler) GetLoyaltyAccount(ctx context.Context, req *loyalty_pb.GetLoyaltyAccountRequest) (*loyalty_pb.LoyaltyAccount, error) {
+
+	var userId pgtype.UUID
+	if err := userId.Scan(req.GetUserId()); err != nil {
+		zap.S().Info("Invalid User UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "User UUID khong hop le")
+	}
+
+	account, err := lg.service.GetLoyaltyAccount(ctx, userId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc tai khoan tich diem")
+	}
+
+	result := &loyalty_pb.LoyaltyAccount{
+		UserId:           account.UserId,
+		Balance:          int32(account.Balance),
+		Tier:             account.Tier.Name,
+		Nights:           int32(account.Nights),
+		DiscountPercent:  int32(account.Tier.DiscountPercent),
+		BonusEarnPercent: int32(account.Tier.BonusEarnPercent),
+		NightsToNextTier: int32(account.NightsToNextTier),
+	}
+	if account.NextTier != nil {
+		result.NextTier = account.NextTier.Name
+	}
+
+	return result, nil
+}
+
+func (lg *LoyaltyGrpcHandler) GetLoyaltyTransactions(ctx context.Context, req *loyalty_pb.GetLoyaltyTransactionsRequest) (*loyalty_pb.GetLoyaltyTransactionsResponse, error) {
+
+	var userId pgtype.UUID
+	if err := userId.Scan(req.GetUserId()); err != nil {
+		zap.S().Info("Invalid User UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "User UUID khong hop le")
+	}
+
+	transactions, err := lg.service.GetLoyaltyTransactions(ctx, userId, int(req.GetSize()), int(req.GetOffset()))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc lich su tich diem")
+	}
+
+	results := make([]*loyalty_pb.LoyaltyTransaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		results = append(results, toLoyaltyTransactionPb(loyalty_repo.LoyaltyTransaction{
+			ID:        transaction.ID,
+			Type:      transaction.Type,
+			UserID:    transaction.UserID,
+			BookingID: transaction.BookingID,
+			HotelID:   transaction.HotelID,
+			Nights:    transaction.Nights,
+			ExpiresAt: transaction.ExpiresAt,
+			Note:      transaction.Note,
+			Actor:     transaction.Actor,
+			CreateAt:  transaction.CreateAt,
+		}, int(transaction.Points)))
+	}
+
+	return &loyalty_pb.GetLoyaltyTransactionsResponse{
+		Transactions: results,
+	}, nil
+}
+
+// ResourceExhausted when the member does not have enough points
+func (lg *LoyaltyGrpcHandler) RedeemPoints(ctx context.Context, req *loyalty_pb.RedeemPointsRequest) (*loyalty_pb.LoyaltyTransaction, error) {
+
+	var userId pgtype.UUID
+	if err := userId.Scan(req.GetUserId()); err != nil {
+		zap.S().Info("Invalid User UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "User UUID khong hop le")
+	}
+
+	var bookingId pgtype.UUID
+	if err := bookingId.Scan(req.GetBookingId()); err != nil {
+		zap.S().Info("Invalid Booking UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Booking UUID khong hop le")
+	}
+
+	transaction, err := lg.service.RedeemPoints(ctx, userId, bookingId, int(req.GetPoints()), req.GetNote())
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "So diem khong hop le")
+		}
+		if errors.Is(err, common_error.ErrDuplicateRecord) {
+			return nil, status.Error(codes.AlreadyExists, "Booking da doi diem")
+		}
+		if errors.Is(err, loyalty_service.ErrInsufficientPoints) {
+			return nil, status.Error(codes.ResourceExhausted, "Khong du diem")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong doi duoc diem")
+	}
+
+	return toLoyaltyTransactionPb(*transaction, -int(req.GetPoints())), nil
+}
+
+// NotFound when the booking did not redeem points
+func (lg *LoyaltyGrpcHandler) CancelRedemption(ctx context.Context, req *loyalty_pb.CancelRedemptionRequest) (*loyalty_pb.CancelRedemptionResponse, error) {
+
+	var err error
+	if req.GetBookingId() != "" {
+		var bookingId pgtype.UUID
+		if err := bookingId.Scan(req.GetBookingId()); err != nil {
+			zap.S().Info("Invalid Booking UUID: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Booking UUID khong hop le")
+		}
+
+		err = lg.service.CancelBookingRedemption(ctx, bookingId)
+	} else {
+		var transactionId pgtype.UUID
+		if err := transactionId.Scan(req.GetTransactionId()); err != nil {
+			zap.S().Info("Invalid Transaction UUID: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Transaction UUID khong hop le")
+		}
+
+		err = lg.service.CancelRedemption(ctx, transactionId)
+	}
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Khong tim thay giao dich doi diem")
+		}
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Giao dich khong phai doi diem")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong huy duoc giao dich doi diem")
+	}
+
+	return &loyalty_pb.CancelRedemptionResponse{}, nil
+}
+
+// ResourceExhausted when taking away more points than the balance
+func (lg *LoyaltyGrpcHandler) AdjustPoints(ctx context.Context, req *loyalty_pb.AdjustPointsRequest) (*loyalty_pb.LoyaltyTransaction, error) {
+
+	var userId pgtype.UUID
+	if err := userId.Scan(req.GetUserId()); err != nil {
+		zap.S().Info("Invalid User UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "User UUID khong hop le")
+	}
+
+	transaction, err := lg.service.AdjustPoints(ctx, userId, int(req.GetPoints()), req.GetNote())
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Dieu chinh diem khong hop le")
+		}
+		if errors.Is(err, loyalty_service.ErrInsufficientPoints) {
+			return nil, status.Error(codes.ResourceExhausted, "Khong du diem")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong dieu chinh duoc diem")
+	}
+
+	return toLoyaltyTransactionPb(*transaction, int(req.GetPoints())), nil
+}
+
+func (lg *LoyaltyGrpcHandler) SetHotelLoyaltySetting(ctx context.Context, req *loyalty_pb.SetHotelLoyaltySettingRequest) (*loyalty_pb.HotelLoyaltySetting, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	setting, err := lg.service.SetHotelLoyaltySetting(ctx, hotelId, int(req.GetEarnPercent()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Ti le tich diem khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc cai dat tich diem")
+	}
+
+	return &loyalty_pb.HotelLoyaltySetting{
+		HotelId:     setting.HotelID.String(),
+		EarnPercent: setting.EarnPercent,
+	}, nil
+}
+
+func (lg *LoyaltyGrpcHandler) GetHotelLoyaltySetting(ctx context.Context, req *loyalty_pb.GetHotelLoyaltySettingRequest) (*loyalty_pb.HotelLoyaltySetting, error) {
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Info("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	earnPercent, err := lg.service.GetHotelEarnPercent(ctx, hotelId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi khong lay duoc cai dat tich diem")
+	}
+
+	return &loyalty_pb.HotelLoyaltySetting{
+		HotelId:     hotelId.String(),
+		EarnPercent: int32(earnPercent),
+	}, nil
+}
+
+func toLoyaltyTransactionPb(transaction loyalty_repo.LoyaltyTransaction, points int) *loyalty_pb.LoyaltyTransaction {
+
+	expiresAt := ""
+	if transaction.ExpiresAt.Valid {
+		expiresAt = transaction.ExpiresAt.Time.Format(time.RFC3339)
+	}
+
+	return &loyalty_pb.LoyaltyTransaction{
+		Id:        transaction.ID.String(),
+		Type:      string(transaction.Type),
+		Points:    int32(points),
+		BookingId: transaction.BookingID.String(),
+		HotelId:   transaction.HotelID.String(),
+		Nights:    transaction.Nights,
+		ExpiresAt: expiresAt,
+		Note:      transaction.Note,
+		Actor:     transaction.Actor,
+		CreatedAt: transaction.CreateAt.Time.Format(time.RFC3339),
+	}
+}
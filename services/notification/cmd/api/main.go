@@ -2,39 +2,70 @@ package main
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"github.com/098765432m/grpc-kafka/common/consts"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	notification_appication "github.com/098765432m/grpc-kafka/notification/internal/appication"
+	notification_infrastructure "github.com/098765432m/grpc-kafka/notification/internal/infrastructure"
+	"github.com/spf13/viper"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
 	"go.uber.org/zap"
 )
 
 func main() {
-	zap.ReplaceGlobals(zap.Must(zap.NewDevelopment()))
-
-	seeds := []string{"localhost:????"}
+	ctx := context.Background()
 
 	// 1. Config
+	utils.Init()
+
+	seeds := strings.Split(viper.GetString("KAFKA_BROKERS"), ",")
+
 	kafkaClient, err := kgo.NewClient(
 		kgo.SeedBrokers(seeds...),
 	)
 	if err != nil {
 		panic(err)
 	}
+	defer kafkaClient.Close()
 
 	zap.S().Infoln("Kafka client connected")
 
 	adminClient := kadm.NewClient(kafkaClient)
 
 	topics := []string{
-		"hotel.booking.created",
+		consts.BOOKING_CREATED_TOPIC,
+		consts.BOOKING_STATUS_CHANGED_TOPIC,
+		consts.BOOKING_TRANSFER_TOPIC,
+		consts.LOYALTY_BOOKING_STATUS_CHANGED_DLQ_TOPIC,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	createCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	_, err = adminClient.CreateTopics(ctx, -1, -1, nil, topics...)
+	_, err = adminClient.CreateTopics(createCtx, -1, -1, nil, topics...)
 	if err != nil {
 		panic(err)
 	}
+
+	// 2. Infras
+	emailSender := notification_infrastructure.NewEmailSender(viper.GetString("RESEND_API_KEY"))
+
+	bookingCreatedConsumer, err := notification_infrastructure.NewKafkaConsumer(seeds, consts.BOOKING_CREATED_TOPIC, "notification-booking-created")
+	if err != nil {
+		zap.S().Fatalln("Failed to create booking created consumer: ", err)
+	}
+
+	bookingTransferConsumer, err := notification_infrastructure.NewKafkaConsumer(seeds, consts.BOOKING_TRANSFER_TOPIC, "notification-booking-transfer")
+	if err != nil {
+		zap.S().Fatalln("Failed to create booking transfer consumer: ", err)
+	}
+
+	// 3. Application
+	handler := notification_appication.NewNotificationHandler(emailSender)
+
+	go bookingCreatedConsumer.Consume(ctx, handler.HandleBookingCreated)
+	bookingTransferConsumer.ConsumeBookingTransfer(ctx, handler.HandleBookingTransfer)
 }
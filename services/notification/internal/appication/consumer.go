@@ -1,6 +1,9 @@
 package notification_appication
 
 import (
+	"html"
+	"strings"
+
 	notification_domain "github.com/098765432m/grpc-kafka/notification/internal/domain"
 	notification_infrastructure "github.com/098765432m/grpc-kafka/notification/internal/infrastructure"
 	"go.uber.org/zap"
@@ -20,9 +23,54 @@ func (nh *NotificationHandler) HandleBookingCreated(event notification_domain.Bo
 	subject := ""
 	body := ""
 
-	if err := nh.EmailSender.SendEmail(event.UserEmail, subject, body); err != nil {
+	// Channel reservations have no booking user, only the guest
+	email := event.UserEmail
+	if email == "" {
+		email = event.GuestEmail
+	}
+
+	if err := nh.EmailSender.SendEmail(email, subject, body); err != nil {
 		zap.S().Infoln("Failed to send email: ", err)
 	} else {
-		zap.S().Infof("Email send to %s\n", event.UserEmail)
+		zap.S().Infof("Email send to %s\n", email)
+	}
+}
+
+// Both the sender and the recipient of a booking transfer are notified
+func (nh *NotificationHandler) HandleBookingTransfer(event notification_domain.BookingTransferEvent) {
+	subject := "Booking " + event.ConfirmationCode + " transfer " + event.Status
+	body := bookingTransferEmailBody(event)
+
+	for _, email := range []string{event.FromUserEmail, event.ToUserEmail} {
+		if email == "" {
+			continue
+		}
+
+		if err := nh.EmailSender.SendEmail(email, subject, body); err != nil {
+			zap.S().Infoln("Failed to send email: ", err)
+		} else {
+			zap.S().Infof("Email send to %s\n", email)
+		}
 	}
 }
+
+// Same body for the sender and the recipient, it names both of them
+func bookingTransferEmailBody(event notification_domain.BookingTransferEvent) string {
+	code := html.EscapeString(event.ConfirmationCode)
+	from := html.EscapeString(event.FromUserEmail)
+	to := html.EscapeString(event.ToUserEmail)
+
+	var body strings.Builder
+	body.WriteString("<p>Booking <b>" + code + "</b> transfer from " + from + " to " + to + "</p>")
+
+	switch event.Status {
+	case "PENDING":
+		body.WriteString("<p>The transfer is waiting for " + to + " to accept or decline it in their account.</p>")
+	case "ACCEPTED":
+		body.WriteString("<p>The transfer was accepted, the booking now belongs to " + to + ".</p>")
+	case "DECLINED":
+		body.WriteString("<p>The transfer was declined, the booking stays with " + from + ".</p>")
+	}
+
+	return body.String()
+}
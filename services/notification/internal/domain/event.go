@@ -8,4 +8,45 @@ type BookingCreatedEvent struct {
 	HotelName string    `json:"hotel_name"`
 	CheckIn   time.Time `json:"check_in"`
 	CheckOut  time.Time `json:"check_out"`
+	// Guest got a higher room type than requested at the original price
+	IsUpgraded             bool   `json:"is_upgraded"`
+	UpgradedFromRoomTypeId string `json:"upgraded_from_room_type_id"`
+	// Occupant of the room when it is not the booking user
+	GuestName            string           `json:"guest_name"`
+	GuestEmail           string           `json:"guest_email"`
+	EstimatedArrivalTime string           `json:"estimated_arrival_time"`
+	SpecialRequests      []SpecialRequest `json:"special_requests"`
+	// Policy of the hotel to remind the guest of, nil when the hotel has none
+	HotelPolicy *HotelPolicy `json:"hotel_policy"`
+}
+
+type HotelPolicy struct {
+	CheckInFrom        string            `json:"check_in_from"`
+	CheckInUntil       string            `json:"check_in_until"`
+	CheckOutUntil      string            `json:"check_out_until"`
+	PetsAllowed        bool              `json:"pets_allowed"`
+	PetFee             int               `json:"pet_fee"`
+	ChildrenAllowed    bool              `json:"children_allowed"`
+	ChildrenFreeMaxAge *int              `json:"children_free_max_age"`
+	ExtraBedAvailable  bool              `json:"extra_bed_available"`
+	ExtraBedFee        int               `json:"extra_bed_fee"`
+	PaymentMethods     []string          `json:"payment_methods"`
+	HouseRules         map[string]string `json:"house_rules"`
+}
+
+type SpecialRequest struct {
+	Type string `json:"type"`
+	Note string `json:"note"`
+}
+
+// Status is PENDING when the transfer is requested, ACCEPTED or DECLINED when the recipient answers
+type BookingTransferEvent struct {
+	TransferId       string `json:"transfer_id"`
+	BookingId        string `json:"booking_id"`
+	ConfirmationCode string `json:"confirmation_code"`
+	FromUserId       string `json:"from_user_id"`
+	FromUserEmail    string `json:"from_user_email"`
+	ToUserId         string `json:"to_user_id"`
+	ToUserEmail      string `json:"to_user_email"`
+	Status           string `json:"status"`
 }
@@ -32,9 +32,18 @@ func NewKafkaConsumer(brokers []string, topics, groupId string) (*KafkaConsumer,
 }
 
 func (kc *KafkaConsumer) Consume(ctx context.Context, handler func(notification_domain.BookingCreatedEvent)) {
+	consume(ctx, kc.cl, handler)
+}
+
+func (kc *KafkaConsumer) ConsumeBookingTransfer(ctx context.Context, handler func(notification_domain.BookingTransferEvent)) {
+	consume(ctx, kc.cl, handler)
+}
+
+// Poll records of the subscribed topic and pass each decoded event to handler
+func consume[T any](ctx context.Context, cl *kgo.Client, handler func(T)) {
 
 	for {
-		fetches := kc.cl.PollFetches(ctx)
+		fetches := cl.PollFetches(ctx)
 		if fetches.IsClientClosed() {
 			return
 		}
@@ -44,7 +53,7 @@ func (kc *KafkaConsumer) Consume(ctx context.Context, handler func(notification_
 		})
 
 		fetches.EachRecord(func(r *kgo.Record) {
-			var event notification_domain.BookingCreatedEvent
+			var event T
 			if err := json.Unmarshal(r.Value, &event); err != nil {
 				zap.S().Errorf("Failed to unmarshal record: %v", err)
 				return
@@ -54,7 +63,7 @@ func (kc *KafkaConsumer) Consume(ctx context.Context, handler func(notification_
 			handler(event)
 
 			// commit offset
-			if err := kc.cl.CommitRecords(ctx, r); err != nil {
+			if err := cl.CommitRecords(ctx, r); err != nil {
 				zap.S().Infoln("Failed to commit record: %v", err)
 			}
 		})
@@ -46,7 +46,7 @@ func main() {
 	ratingRedis := rating_redis.NewRedisRatingCache(rdb)
 
 	// 3. Application
-	ratingService := rating_service.NewRatingService(repo, ratingRedis)
+	ratingService := rating_service.NewRatingService(db, repo, ratingRedis)
 
 	// 4. Server
 	ratingHandler := rating_handler.NewRatingGrpcHandler(ratingService)
@@ -3,56 +3,80 @@ package rating_service
 import (
 	"context"
 
+	"github.com/098765432m/grpc-kafka/common/utils"
 	rating_domain "github.com/098765432m/grpc-kafka/rating/internal/domain"
 	rating_redis "github.com/098765432m/grpc-kafka/rating/internal/infrastructure/redis"
 	rating_repo "github.com/098765432m/grpc-kafka/rating/internal/infrastructure/repository/sqlc/rating"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
+const ratingColumns = `id, score, hotel_id, user_id, comment, create_at`
+
+var ratingSortColumns = map[string]utils.SortColumn{
+	"create_at": {Column: "create_at", Cast: "timestamp", DefaultDesc: true},
+	"score":     {Column: "score", Cast: "int", DefaultDesc: true},
+}
+
 type RatingService struct {
+	conn  *pgxpool.Pool
 	repo  *rating_repo.Queries
 	redis *rating_redis.RedisRatingCache
 }
 
-func NewRatingService(repo *rating_repo.Queries, redis *rating_redis.RedisRatingCache) *RatingService {
+func NewRatingService(conn *pgxpool.Pool, repo *rating_repo.Queries, redis *rating_redis.RedisRatingCache) *RatingService {
 	return &RatingService{
+		conn:  conn,
 		repo:  repo,
 		redis: redis,
 	}
 }
 
-func (rs *RatingService) GetRatingsByHotelId(ctx context.Context, hotelId pgtype.UUID) ([]rating_domain.Rating, error) {
+// Newest first by default, ErrBadRequest when the page is invalid
+func (rs *RatingService) GetRatingsByHotelId(ctx context.Context, hotelId pgtype.UUID, pageParams utils.PageParams) ([]rating_domain.Rating, *utils.PageInfo, error) {
 
-	cache, err := rs.redis.GetRatingsByHotelId(ctx, hotelId.String())
+	page, err := utils.NewPage(pageParams, ratingSortColumns, "create_at", "id")
 	if err != nil {
-		zap.S().Errorln("Failed to get Redis Ratings by Hotel id: ", err)
-		return nil, err
+		zap.S().Infoln("Invalid page of Ratings: ", pageParams)
+		return nil, nil, err
 	}
 
-	if cache != nil {
-		return cache, nil
-	}
-
-	ratings, err := rs.repo.GetRatingsByHotel(ctx, hotelId)
+	conditions := &utils.Conditions{}
+	conditions.Add("hotel_id = $%d", hotelId)
+
+	ratings, pageInfo, err := utils.QueryPage(ctx, rs.conn, page, ratingColumns, "ratings", conditions,
+		func(rows pgx.Rows) (rating_repo.Rating, error) {
+			var r rating_repo.Rating
+			err := rows.Scan(&r.ID, &r.Score, &r.HotelID, &r.UserID, &r.Comment, &r.CreateAt)
+			return r, err
+		},
+		func(r rating_repo.Rating) (any, pgtype.UUID) {
+			if page.SortBy == "score" {
+				return r.Score, r.ID
+			}
+			return r.CreateAt, r.ID
+		})
 	if err != nil {
 		zap.S().Errorln("Failed to get Ratings by Hotel id: ", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	result := make([]rating_domain.Rating, 0, len(ratings))
 	for _, rating := range ratings {
 		result = append(result, rating_domain.Rating{
-			Id:      rating.ID.String(),
-			Score:   int(rating.Score),
-			HotelId: rating.HotelID.String(),
-			UserId:  rating.UserID.String(),
-			Comment: rating.Comment.String,
+			Id:       rating.ID.String(),
+			Score:    int(rating.Score),
+			HotelId:  rating.HotelID.String(),
+			UserId:   rating.UserID.String(),
+			Comment:  rating.Comment.String,
+			CreateAt: rating.CreateAt.Time,
 		})
 
 	}
 
-	return result, nil
+	return result, pageInfo, nil
 }
 
 func (rs *RatingService) CreateRating(ctx context.Context, newRating *rating_repo.CreateRatingParams) error {
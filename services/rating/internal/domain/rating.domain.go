@@ -1,9 +1,12 @@
 package rating_domain
 
+import "time"
+
 type Rating struct {
-	Id      string `json:"id"`
-	Score   int    `json:"score"`
-	UserId  string `json:"user_id"`
-	HotelId string `json:"hotel_id"`
-	Comment string `json:"comment,omitempty"`
+	Id       string    `json:"id"`
+	Score    int       `json:"score"`
+	UserId   string    `json:"user_id"`
+	HotelId  string    `json:"hotel_id"`
+	Comment  string    `json:"comment,omitempty"`
+	CreateAt time.Time `json:"create_at"`
 }
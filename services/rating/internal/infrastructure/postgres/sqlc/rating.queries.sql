@@ -1,8 +1,3 @@
--- name: GetRatingsByHotel :many
-SELECT *
-FROM ratings
-WHERE hotel_id = $1;
-
 -- name: CreateRating :exec
 INSERT INTO ratings
 (
@@ -3,5 +3,8 @@ CREATE TABLE ratings (
     score INT NOT NULL CHECK (score BETWEEN 1 AND 5),
     hotel_id UUID NOT NULL,
     user_id UUID NOT NULL,
-    comment TEXT
-);
\ No newline at end of file
+    comment TEXT,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX ratings_hotel_id_create_at_idx ON ratings (hotel_id, create_at);
\ No newline at end of file
@@ -9,9 +9,10 @@ import (
 )
 
 type Rating struct {
-	ID      pgtype.UUID `json:"id"`
-	Score   int32       `json:"score"`
-	HotelID pgtype.UUID `json:"hotel_id"`
-	UserID  pgtype.UUID `json:"user_id"`
-	Comment pgtype.Text `json:"comment"`
+	ID       pgtype.UUID      `json:"id"`
+	Score    int32            `json:"score"`
+	HotelID  pgtype.UUID      `json:"hotel_id"`
+	UserID   pgtype.UUID      `json:"user_id"`
+	Comment  pgtype.Text      `json:"comment"`
+	CreateAt pgtype.Timestamp `json:"create_at"`
 }
@@ -55,38 +55,6 @@ func (q *Queries) DeleteRating(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
-const getRatingsByHotel = `-- name: GetRatingsByHotel :many
-SELECT id, score, hotel_id, user_id, comment
-FROM ratings
-WHERE hotel_id = $1
-`
-
-func (q *Queries) GetRatingsByHotel(ctx context.Context, hotelID pgtype.UUID) ([]Rating, error) {
-	rows, err := q.db.Query(ctx, getRatingsByHotel, hotelID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Rating
-	for rows.Next() {
-		var i Rating
-		if err := rows.Scan(
-			&i.ID,
-			&i.Score,
-			&i.HotelID,
-			&i.UserID,
-			&i.Comment,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const updateRating = `-- name: UpdateRating :exec
 UPDATE ratings
 SET 
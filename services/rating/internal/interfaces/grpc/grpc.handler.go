@@ -2,8 +2,12 @@ package rating_handler
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	common_error "github.com/098765432m/grpc-kafka/common/error"
 	"github.com/098765432m/grpc-kafka/common/gen-proto/rating_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
 	rating_service "github.com/098765432m/grpc-kafka/rating/internal/application"
 	rating_repo "github.com/098765432m/grpc-kafka/rating/internal/infrastructure/repository/sqlc/rating"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -30,8 +34,11 @@ func (rg *RatingGrpcHandler) GetRatingsByHotelId(ctx context.Context, req *ratin
 		return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
 	}
 
-	ratings, err := rg.service.GetRatingsByHotelId(ctx, hotelId)
+	ratings, pageInfo, err := rg.service.GetRatingsByHotelId(ctx, hotelId, utils.ToPageParams(req.GetPage()))
 	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
 		zap.S().Errorln(err)
 		return nil, status.Error(codes.Internal, "Loi he thong")
 	}
@@ -39,17 +46,19 @@ func (rg *RatingGrpcHandler) GetRatingsByHotelId(ctx context.Context, req *ratin
 	var grpc_ratings []*rating_pb.Rating
 	for _, rating := range ratings {
 		grpc_rating := &rating_pb.Rating{
-			Id:      rating.Id,
-			Score:   int32(rating.Score),
-			HotelId: rating.HotelId,
-			UserId:  rating.UserId,
-			Comment: rating.Comment,
+			Id:       rating.Id,
+			Score:    int32(rating.Score),
+			HotelId:  rating.HotelId,
+			UserId:   rating.UserId,
+			Comment:  rating.Comment,
+			CreateAt: rating.CreateAt.Format(time.RFC3339),
 		}
 		grpc_ratings = append(grpc_ratings, grpc_rating)
 	}
 
 	return &rating_pb.GetRatingsByHotelIdRepsonse{
 		Ratings: grpc_ratings,
+		Page:    utils.ToPageResponsePb(pageInfo),
 	}, nil
 }
 
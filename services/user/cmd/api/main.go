@@ -35,7 +35,7 @@ func main() {
 	repo := user_repo.New(db)
 
 	// 3. Application
-	userService := user_service.NewUserService(repo)
+	userService := user_service.NewUserService(db, repo)
 
 	// 4. Server
 	userHandler := user_handler.NewUserGrpcHandler(userService)
@@ -0,0 +1,405 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	user_domain "github.com/098765432m/grpc-kafka/user/internal/domain"
+	user_repo "github.com/098765432m/grpc-kafka/user/internal/infrastructure/repository/sqlc/user"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+var ErrCreditLimitExceeded = errors.New("company credit limit exceeded")
+var ErrSettlementExceedsBalance = errors.New("settlement exceeds company outstanding balance")
+
+const companyMemberColumns = `u.id, u.username, u.email, u.full_name, m.create_at AS joined_at`
+
+type companyMemberRow struct {
+	id       pgtype.UUID
+	username string
+	email    string
+	fullName string
+	joinedAt pgtype.Timestamp
+}
+
+var companyMemberSortColumns = map[string]utils.SortColumn{
+	"joined_at": {Column: "m.create_at", Cast: "timestamp"},
+	"username":  {Column: "u.username", Cast: "text"},
+}
+
+type CompanyParams struct {
+	Name           string
+	BillingEmail   string
+	BillingAddress string
+	CreditLimit    int
+}
+
+func validateCompany(params *CompanyParams) error {
+	if strings.TrimSpace(params.Name) == "" {
+		zap.S().Infoln("Company name is empty")
+		return common_error.ErrBadRequest
+	}
+
+	if _, err := mail.ParseAddress(params.BillingEmail); err != nil {
+		zap.S().Infoln("Invalid company billing email: ", err)
+		return common_error.ErrBadRequest
+	}
+
+	if params.CreditLimit < 0 {
+		zap.S().Infoln("Credit limit must not be negative")
+		return common_error.ErrBadRequest
+	}
+
+	return nil
+}
+
+func (us *UserService) CreateCompany(ctx context.Context, params *CompanyParams) (*user_repo.Company, error) {
+
+	if err := validateCompany(params); err != nil {
+		return nil, err
+	}
+
+	company, err := us.repo.CreateCompany(ctx, user_repo.CreateCompanyParams{
+		Name:           strings.TrimSpace(params.Name),
+		BillingEmail:   params.BillingEmail,
+		BillingAddress: params.BillingAddress,
+		CreditLimit:    int32(params.CreditLimit),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Info("Duplicated Company: ", err)
+			return nil, common_error.ErrDuplicateRecord
+		}
+
+		zap.S().Errorln("Failed to create Company: ", err)
+		return nil, err
+	}
+
+	return &company, nil
+}
+
+func (us *UserService) GetCompanyById(ctx context.Context, id pgtype.UUID) (*user_repo.Company, error) {
+
+	company, err := us.repo.GetCompanyById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Company not found")
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to get Company by id: ", err)
+		return nil, err
+	}
+
+	return &company, nil
+}
+
+func (us *UserService) UpdateCompanyById(ctx context.Context, id pgtype.UUID, params *CompanyParams) (*user_repo.Company, error) {
+
+	if err := validateCompany(params); err != nil {
+		return nil, err
+	}
+
+	// Lowering the credit limit below outstanding balance only blocks new charges
+	company, err := us.repo.UpdateCompanyById(ctx, user_repo.UpdateCompanyByIdParams{
+		Name:           strings.TrimSpace(params.Name),
+		BillingEmail:   params.BillingEmail,
+		BillingAddress: params.BillingAddress,
+		CreditLimit:    int32(params.CreditLimit),
+		ID:             id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("Company not found to update")
+			return nil, common_error.ErrNoRows
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zap.S().Info("Duplicated Company: ", err)
+			return nil, common_error.ErrDuplicateRecord
+		}
+
+		zap.S().Errorln("Failed to update Company by id: ", err)
+		return nil, err
+	}
+
+	return &company, nil
+}
+
+// Company of the user with its negotiated rates, ErrNoRows when the user is not a member of any company
+func (us *UserService) GetCompanyByUserId(ctx context.Context, userId pgtype.UUID) (*user_repo.Company, []user_repo.CompanyRate, error) {
+
+	company, err := us.repo.GetCompanyByUserId(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to get Company by user id: ", err)
+		return nil, nil, err
+	}
+
+	rates, err := us.repo.GetCompanyRatesByCompanyId(ctx, company.ID)
+	if err != nil {
+		zap.S().Errorln("Failed to get Company rates: ", err)
+		return nil, nil, err
+	}
+
+	return &company, rates, nil
+}
+
+// Add amount to outstanding balance of the company, a negative amount releases credit
+func (us *UserService) ChargeCompanyCredit(ctx context.Context, companyId pgtype.UUID, amount int) error {
+
+	charged, err := us.repo.ChargeCompanyCredit(ctx, user_repo.ChargeCompanyCreditParams{
+		Amount: int32(amount),
+		ID:     companyId,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to charge Company credit: ", err)
+		return err
+	}
+
+	if charged == 0 {
+		if _, err := us.GetCompanyById(ctx, companyId); err != nil {
+			return err
+		}
+
+		zap.S().Infoln("Company credit limit exceeded")
+		return ErrCreditLimitExceeded
+	}
+
+	return nil
+}
+
+// Record a payment of the company and take it off the outstanding balance, which frees credit for new bookings
+func (us *UserService) SettleCompanyBalance(ctx context.Context, companyId pgtype.UUID, amount int, reference string) (*user_repo.Company, error) {
+
+	if amount <= 0 {
+		zap.S().Infoln("Settlement amount must be positive")
+		return nil, common_error.ErrBadRequest
+	}
+
+	tx, err := us.conn.Begin(ctx)
+	if err != nil {
+		zap.S().Errorln("Failed to begin settle company balance transaction: ", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := us.repo.WithTx(tx)
+
+	company, err := qtx.SettleCompanyBalance(ctx, user_repo.SettleCompanyBalanceParams{
+		Amount: int32(amount),
+		ID:     companyId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, err := us.GetCompanyById(ctx, companyId); err != nil {
+				return nil, err
+			}
+
+			zap.S().Infoln("Settlement is more than the Company outstanding balance")
+			return nil, ErrSettlementExceedsBalance
+		}
+
+		zap.S().Errorln("Failed to settle Company balance: ", err)
+		return nil, err
+	}
+
+	if _, err := qtx.CreateCompanySettlement(ctx, user_repo.CreateCompanySettlementParams{
+		CompanyID: companyId,
+		Amount:    int32(amount),
+		Reference: strings.TrimSpace(reference),
+	}); err != nil {
+		zap.S().Errorln("Failed to create Company settlement: ", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		zap.S().Errorln("Failed to commit Company settlement: ", err)
+		return nil, err
+	}
+
+	return &company, nil
+}
+
+func (us *UserService) AddCompanyMember(ctx context.Context, companyId pgtype.UUID, userId pgtype.UUID) error {
+
+	err := us.repo.AddCompanyMember(ctx, user_repo.AddCompanyMemberParams{
+		UserID:    userId,
+		CompanyID: companyId,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				zap.S().Info("User is already a member of a Company: ", err)
+				return common_error.ErrDuplicateRecord
+			case "23503":
+				zap.S().Info("User or Company not found: ", err)
+				return common_error.ErrNoRows
+			}
+		}
+
+		zap.S().Errorln("Failed to add Company member: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func (us *UserService) RemoveCompanyMember(ctx context.Context, companyId pgtype.UUID, userId pgtype.UUID) error {
+
+	removed, err := us.repo.RemoveCompanyMember(ctx, user_repo.RemoveCompanyMemberParams{
+		CompanyID: companyId,
+		UserID:    userId,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to remove Company member: ", err)
+		return err
+	}
+
+	if removed == 0 {
+		zap.S().Infoln("No Company member to remove")
+		return common_error.ErrNoRows
+	}
+
+	return nil
+}
+
+// ErrBadRequest when the page is invalid
+func (us *UserService) GetCompanyMembers(ctx context.Context, companyId pgtype.UUID, pageParams utils.PageParams) ([]user_domain.CompanyMember, *utils.PageInfo, error) {
+
+	page, err := utils.NewPage(pageParams, companyMemberSortColumns, "joined_at", "u.id")
+	if err != nil {
+		zap.S().Infoln("Invalid page of Company members: ", pageParams)
+		return nil, nil, err
+	}
+
+	conditions := &utils.Conditions{}
+	conditions.Add("m.company_id = $%d", companyId)
+
+	members, pageInfo, err := utils.QueryPage(ctx, us.conn, page, companyMemberColumns, "company_members m JOIN users u ON u.id = m.user_id", conditions,
+		func(rows pgx.Rows) (companyMemberRow, error) {
+			var m companyMemberRow
+			err := rows.Scan(&m.id, &m.username, &m.email, &m.fullName, &m.joinedAt)
+			return m, err
+		},
+		func(m companyMemberRow) (any, pgtype.UUID) {
+			if page.SortBy == "username" {
+				return m.username, m.id
+			}
+			return m.joinedAt, m.id
+		})
+	if err != nil {
+		zap.S().Errorln("Failed to get Company members: ", err)
+		return nil, nil, err
+	}
+
+	results := make([]user_domain.CompanyMember, 0, len(members))
+	for _, m := range members {
+		results = append(results, user_domain.CompanyMember{
+			UserId:   m.id.String(),
+			Username: m.username,
+			Email:    m.email,
+			FullName: m.fullName,
+			JoinedAt: m.joinedAt.Time,
+		})
+	}
+
+	return results, pageInfo, nil
+}
+
+type CreateCompanyRateParams struct {
+	CompanyId  pgtype.UUID
+	HotelId    pgtype.UUID
+	RoomTypeId pgtype.UUID // Invalid applies the rate to every room type of the hotel
+	RateType   user_repo.CompanyRateType
+	Value      int
+}
+
+func (us *UserService) CreateCompanyRate(ctx context.Context, params *CreateCompanyRateParams) (*user_repo.CompanyRate, error) {
+
+	switch params.RateType {
+	case user_repo.CompanyRateTypeFIXEDPRICE:
+		if params.Value < 0 {
+			zap.S().Infoln("Negotiated price must not be negative")
+			return nil, common_error.ErrBadRequest
+		}
+	case user_repo.CompanyRateTypeDISCOUNTPERCENT:
+		if params.Value < 0 || params.Value > 100 {
+			zap.S().Infoln("Discount percent must be between 0 and 100")
+			return nil, common_error.ErrBadRequest
+		}
+	default:
+		zap.S().Infoln("Invalid Company rate type: ", params.RateType)
+		return nil, common_error.ErrBadRequest
+	}
+
+	rate, err := us.repo.CreateCompanyRate(ctx, user_repo.CreateCompanyRateParams{
+		CompanyID:  params.CompanyId,
+		HotelID:    params.HotelId,
+		RoomTypeID: params.RoomTypeId,
+		RateType:   params.RateType,
+		Value:      int32(params.Value),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				zap.S().Info("Duplicated Company rate: ", err)
+				return nil, common_error.ErrDuplicateRecord
+			case "23503":
+				zap.S().Info("Company not found: ", err)
+				return nil, common_error.ErrNoRows
+			}
+		}
+
+		zap.S().Errorln("Failed to create Company rate: ", err)
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+func (us *UserService) GetCompanyRatesByCompanyId(ctx context.Context, companyId pgtype.UUID) ([]user_repo.CompanyRate, error) {
+
+	rates, err := us.repo.GetCompanyRatesByCompanyId(ctx, companyId)
+	if err != nil {
+		zap.S().Errorln("Failed to get Company rates: ", err)
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+func (us *UserService) DeleteCompanyRate(ctx context.Context, id pgtype.UUID, companyId pgtype.UUID) error {
+
+	deleted, err := us.repo.DeleteCompanyRate(ctx, user_repo.DeleteCompanyRateParams{
+		ID:        id,
+		CompanyID: companyId,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to delete Company rate: ", err)
+		return err
+	}
+
+	if deleted == 0 {
+		zap.S().Infoln("No Company rate to delete")
+		return common_error.ErrNoRows
+	}
+
+	return nil
+}
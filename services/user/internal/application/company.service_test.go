@@ -0,0 +1,71 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	user_repo "github.com/098765432m/grpc-kafka/user/internal/infrastructure/repository/sqlc/user"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestValidateCompany(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  CompanyParams
+		wantErr error
+	}{
+		{"valid", CompanyParams{Name: "Acme", BillingEmail: "billing@acme.example", CreditLimit: 1000}, nil},
+		{"no credit", CompanyParams{Name: "Acme", BillingEmail: "billing@acme.example"}, nil},
+		{"blank name", CompanyParams{Name: "  ", BillingEmail: "billing@acme.example"}, common_error.ErrBadRequest},
+		{"invalid billing email", CompanyParams{Name: "Acme", BillingEmail: "billing"}, common_error.ErrBadRequest},
+		{"negative credit limit", CompanyParams{Name: "Acme", BillingEmail: "billing@acme.example", CreditLimit: -1}, common_error.ErrBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateCompany(&tt.params); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateCompany() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateCompanyRateInvalid(t *testing.T) {
+	id := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+
+	tests := []struct {
+		name     string
+		rateType user_repo.CompanyRateType
+		value    int
+	}{
+		{"negative price", user_repo.CompanyRateTypeFIXEDPRICE, -1},
+		{"negative discount", user_repo.CompanyRateTypeDISCOUNTPERCENT, -5},
+		{"discount over 100 percent", user_repo.CompanyRateTypeDISCOUNTPERCENT, 101},
+		{"unknown rate type", user_repo.CompanyRateType("CASHBACK"), 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			us := &UserService{}
+
+			_, err := us.CreateCompanyRate(context.Background(), &CreateCompanyRateParams{CompanyId: id, HotelId: id, RateType: tt.rateType, Value: tt.value})
+			if !errors.Is(err, common_error.ErrBadRequest) {
+				t.Errorf("CreateCompanyRate() error = %v, want %v", err, common_error.ErrBadRequest)
+			}
+		})
+	}
+}
+
+func TestSettleCompanyBalanceInvalidAmount(t *testing.T) {
+	id := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+
+	for _, amount := range []int{0, -100} {
+		us := &UserService{}
+
+		if _, err := us.SettleCompanyBalance(context.Background(), id, amount, "INV-1"); !errors.Is(err, common_error.ErrBadRequest) {
+			t.Errorf("SettleCompanyBalance(%d) error = %v, want %v", amount, err, common_error.ErrBadRequest)
+		}
+	}
+}
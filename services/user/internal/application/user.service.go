@@ -14,34 +14,24 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService struct {
+	conn *pgxpool.Pool
 	repo *user_repo.Queries
 }
 
-func NewUserService(repo *user_repo.Queries) *UserService {
+func NewUserService(conn *pgxpool.Pool, repo *user_repo.Queries) *UserService {
 	return &UserService{
+		conn: conn,
 		repo: repo,
 	}
 }
 
-func (us *UserService) GetUsers(ctx context.Context) ([]user_domain.User, error) {
-
-	users, err := us.repo.GetUsers(ctx)
-	if err != nil {
-		zap.S().Errorln("Failed to get Users: ", err)
-		return nil, err
-	}
-
-	result := user_repo_mapping.FromUsersRepoToUsersDomain(users)
-
-	return result, nil
-}
-
 func (us *UserService) GetUserById(ctx context.Context, id pgtype.UUID) (*user_domain.User, error) {
 	user, err := us.repo.GetUserById(ctx, id)
 	if err != nil {
@@ -70,6 +60,31 @@ func (us *UserService) GetUsersByIds(ctx context.Context, ids []pgtype.UUID) ([]
 	return user_repo_mapping.FromUsersRepoToUsersDomain(users), nil
 }
 
+const SEARCH_USER_IDS_PAGE_SIZE = 500
+
+// Ids of users whose full name contains the keyword, one page after afterId (Invalid for the first page).
+// The returned cursor is Invalid on the last page
+func (us *UserService) SearchUserIdsByFullName(ctx context.Context, fullName string, afterId pgtype.UUID) ([]pgtype.UUID, pgtype.UUID, error) {
+
+	// One more row tells whether there is a next page
+	ids, err := us.repo.SearchUserIdsByFullName(ctx, user_repo.SearchUserIdsByFullNameParams{
+		FullName: fullName,
+		AfterID:  afterId,
+		PageSize: SEARCH_USER_IDS_PAGE_SIZE + 1,
+	})
+	if err != nil {
+		zap.S().Errorln("Failed to search Users by full name: ", err)
+		return nil, pgtype.UUID{}, err
+	}
+
+	if len(ids) <= SEARCH_USER_IDS_PAGE_SIZE {
+		return ids, pgtype.UUID{}, nil
+	}
+
+	ids = ids[:SEARCH_USER_IDS_PAGE_SIZE]
+	return ids, ids[len(ids)-1], nil
+}
+
 func (us *UserService) CreateUser(ctx context.Context, newUser *user_repo.CreateUserParams) error {
 
 	// Hashed new password
@@ -89,6 +104,7 @@ func (us *UserService) CreateUser(ctx context.Context, newUser *user_repo.Create
 		PhoneNumber: newUser.PhoneNumber,
 		Role:        newUser.Role,
 		HotelID:     newUser.HotelID,
+		ChainID:     newUser.ChainID,
 	})
 
 	if err != nil {
@@ -129,6 +145,45 @@ func (us *UserService) UpdateUserById(ctx context.Context, params *user_repo.Upd
 	return nil
 }
 
+// Role of the user with the hotel of a MANAGER or the chain of a CHAIN_MANAGER.
+// ErrBadRequest when the role is unknown or does not match the hotel and chain, ErrNoRows when the user does not exist
+func (us *UserService) SetUserRole(ctx context.Context, params user_repo.SetUserRoleParams) (*user_domain.User, error) {
+
+	if !isValidUserRole(params) {
+		zap.S().Infoln("Invalid role, hotel or chain of User: ", params.Role)
+		return nil, common_error.ErrBadRequest
+	}
+
+	user, err := us.repo.SetUserRole(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zap.S().Infoln("User is not existed to set role")
+			return nil, common_error.ErrNoRows
+		}
+
+		zap.S().Errorln("Failed to set role of User: ", err)
+		return nil, err
+	}
+
+	result := user_repo_mapping.FromUserRepoToUserDomain(user)
+
+	return &result, nil
+}
+
+// A MANAGER has a hotel, a CHAIN_MANAGER a chain, other roles have neither
+func isValidUserRole(params user_repo.SetUserRoleParams) bool {
+	switch params.Role {
+	case user_repo.RoleEnumMANAGER:
+		return params.HotelID.Valid && !params.ChainID.Valid
+	case user_repo.RoleEnumCHAINMANAGER:
+		return params.ChainID.Valid && !params.HotelID.Valid
+	case user_repo.RoleEnumGUEST, user_repo.RoleEnumADMIN:
+		return !params.HotelID.Valid && !params.ChainID.Valid
+	default:
+		return false
+	}
+}
+
 func (us *UserService) DeleteUserById(ctx context.Context, id pgtype.UUID) error {
 
 	const DEFAULT_ERR_MSG = "loi khong the tao tai khoan"
@@ -188,10 +243,12 @@ func (us *UserService) SignIn(ctx context.Context, username string, password str
 
 	// create jwt
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"iss":  "http://localhost:3102",
-		"sub":  checkUser.ID.String(),
-		"role": checkUser.Role,
-		"exp":  exp,
+		"iss":      "http://localhost:3102",
+		"sub":      checkUser.ID.String(),
+		"role":     checkUser.Role,
+		"hotel_id": checkUser.HotelID.String(),
+		"chain_id": checkUser.ChainID.String(),
+		"exp":      exp,
 	})
 
 	signedStr, err := t.SignedString([]byte(secretKey))
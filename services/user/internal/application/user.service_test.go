@@ -0,0 +1,41 @@
+package user_service
+
+import (
+	"testing"
+
+	user_repo "github.com/098765432m/grpc-kafka/user/internal/infrastructure/repository/sqlc/user"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestIsValidUserRole(t *testing.T) {
+	id := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+
+	tests := []struct {
+		name    string
+		role    user_repo.RoleEnum
+		hotelId pgtype.UUID
+		chainId pgtype.UUID
+		want    bool
+	}{
+		{"manager of a hotel", user_repo.RoleEnumMANAGER, id, pgtype.UUID{}, true},
+		{"manager without hotel", user_repo.RoleEnumMANAGER, pgtype.UUID{}, pgtype.UUID{}, false},
+		{"manager with a chain", user_repo.RoleEnumMANAGER, id, id, false},
+		{"chain manager of a chain", user_repo.RoleEnumCHAINMANAGER, pgtype.UUID{}, id, true},
+		{"chain manager without chain", user_repo.RoleEnumCHAINMANAGER, pgtype.UUID{}, pgtype.UUID{}, false},
+		{"chain manager with a hotel", user_repo.RoleEnumCHAINMANAGER, id, id, false},
+		{"guest", user_repo.RoleEnumGUEST, pgtype.UUID{}, pgtype.UUID{}, true},
+		{"guest with a hotel", user_repo.RoleEnumGUEST, id, pgtype.UUID{}, false},
+		{"admin", user_repo.RoleEnumADMIN, pgtype.UUID{}, pgtype.UUID{}, true},
+		{"admin with a chain", user_repo.RoleEnumADMIN, pgtype.UUID{}, id, false},
+		{"unknown role", user_repo.RoleEnum("OWNER"), pgtype.UUID{}, pgtype.UUID{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := isValidUserRole(user_repo.SetUserRoleParams{Role: tt.role, HotelID: tt.hotelId, ChainID: tt.chainId})
+			if got != tt.want {
+				t.Errorf("isValidUserRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package user_domain
 
+import "time"
+
 // TODO: Maybe create Role Type enum here
 
 type User struct {
@@ -12,4 +14,13 @@ type User struct {
 	FullName    string `json:"full_name"`
 	Role        string `json:"role"`
 	HotelId     string `json:"hotel_id,omitempty"`
+	ChainId     string `json:"chain_id,omitempty"`
+}
+
+type CompanyMember struct {
+	UserId   string    `json:"user_id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+	FullName string    `json:"full_name"`
+	JoinedAt time.Time `json:"joined_at"`
 }
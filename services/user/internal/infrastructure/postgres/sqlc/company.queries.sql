@@ -0,0 +1,92 @@
+-- name: CreateCompany :one
+INSERT INTO companies (
+    name,
+    billing_email,
+    billing_address,
+    credit_limit
+) VALUES (
+    @name::text,
+    @billing_email::text,
+    @billing_address::text,
+    @credit_limit::int
+)
+RETURNING *;
+
+-- name: GetCompanyById :one
+SELECT * FROM companies WHERE id = $1;
+
+-- name: GetCompanyByUserId :one
+SELECT * FROM companies
+WHERE id = (SELECT company_id FROM company_members WHERE user_id = @user_id::uuid);
+
+-- name: UpdateCompanyById :one
+UPDATE companies
+SET
+    name = @name::text,
+    billing_email = @billing_email::text,
+    billing_address = @billing_address::text,
+    credit_limit = @credit_limit::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id::uuid
+RETURNING *;
+
+-- name: ChargeCompanyCredit :execrows
+-- Negative amount releases credit, a charge over the credit limit updates nothing
+UPDATE companies
+SET
+    outstanding_balance = GREATEST(outstanding_balance + @amount::int, 0),
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = @id::uuid
+    AND (@amount::int <= 0 OR outstanding_balance + @amount::int <= credit_limit);
+
+-- name: SettleCompanyBalance :one
+-- A settlement over the outstanding balance updates nothing
+UPDATE companies
+SET
+    outstanding_balance = outstanding_balance - @amount::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = @id::uuid AND outstanding_balance >= @amount::int
+RETURNING *;
+
+-- name: CreateCompanySettlement :one
+INSERT INTO company_settlements (
+    company_id,
+    amount,
+    reference
+) VALUES (
+    @company_id::uuid,
+    @amount::int,
+    @reference::text
+)
+RETURNING *;
+
+-- name: AddCompanyMember :exec
+INSERT INTO company_members (user_id, company_id) VALUES (@user_id::uuid, @company_id::uuid);
+
+-- name: RemoveCompanyMember :execrows
+DELETE FROM company_members WHERE company_id = @company_id::uuid AND user_id = @user_id::uuid;
+
+-- name: CreateCompanyRate :one
+INSERT INTO company_rates (
+    company_id,
+    hotel_id,
+    room_type_id,
+    rate_type,
+    value
+) VALUES (
+    @company_id::uuid,
+    @hotel_id::uuid,
+    sqlc.narg(room_type_id)::uuid,
+    @rate_type::company_rate_type,
+    @value::int
+)
+RETURNING *;
+
+-- name: GetCompanyRatesByCompanyId :many
+SELECT * FROM company_rates
+WHERE company_id = @company_id::uuid
+ORDER BY hotel_id, room_type_id NULLS FIRST;
+
+-- name: DeleteCompanyRate :execrows
+DELETE FROM company_rates WHERE id = @id::uuid AND company_id = @company_id::uuid;
@@ -0,0 +1,51 @@
+CREATE TYPE company_rate_type AS ENUM (
+    'FIXED_PRICE',
+    'DISCOUNT_PERCENT'
+);
+
+-- B2B customer, bookings of its members are billed to the company
+CREATE TABLE companies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    billing_email VARCHAR(255) NOT NULL,
+    billing_address TEXT NOT NULL DEFAULT '',
+    -- Most the company may owe for billed bookings
+    credit_limit INT NOT NULL DEFAULT 0 CHECK (credit_limit >= 0),
+    -- Billed bookings not paid yet
+    outstanding_balance INT NOT NULL DEFAULT 0 CHECK (outstanding_balance >= 0),
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user is a member of at most one company
+CREATE TABLE company_members (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Negotiated nightly rate of a room type, or of every room type of the hotel when room_type_id is empty
+CREATE TABLE company_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    hotel_id UUID NOT NULL,
+    room_type_id UUID,
+    rate_type company_rate_type NOT NULL,
+    -- Nightly price for FIXED_PRICE, percent off the room type price for DISCOUNT_PERCENT
+    value INT NOT NULL CHECK (value >= 0),
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (rate_type <> 'DISCOUNT_PERCENT' OR value <= 100)
+);
+
+CREATE UNIQUE INDEX company_rates_room_type_idx ON company_rates (company_id, room_type_id) WHERE room_type_id IS NOT NULL;
+CREATE UNIQUE INDEX company_rates_hotel_idx ON company_rates (company_id, hotel_id) WHERE room_type_id IS NULL;
+
+-- Payment of billed bookings received from the company, reduces its outstanding balance
+CREATE TABLE company_settlements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL REFERENCES companies(id),
+    amount INT NOT NULL CHECK (amount > 0),
+    -- Bank transfer or invoice number
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
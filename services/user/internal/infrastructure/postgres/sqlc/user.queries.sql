@@ -1,8 +1,3 @@
--- name: GetUsers :many
-SELECT * FROM users
-ORDER BY hotel_id
-LIMIT 20;
-
 -- name: GetUserById :one
 SELECT * FROM users WHERE id = $1;
 
@@ -22,9 +17,21 @@ SELECT
     username,
     password,
     email,
-    role
+    role,
+    hotel_id,
+    chain_id
 FROM users WHERE username = $1;
 
+-- name: SearchUserIdsByFullName :many
+-- Keyset page ordered by id, a NULL after_id starts from the first user
+SELECT id
+FROM users
+WHERE
+    full_name ILIKE '%' || @full_name::text || '%'
+    AND (sqlc.narg(after_id)::uuid IS NULL OR id > sqlc.narg(after_id)::uuid)
+ORDER BY id
+LIMIT @page_size::int;
+
 -- name: CreateUser :exec
 INSERT INTO users (
     username,
@@ -34,7 +41,8 @@ INSERT INTO users (
     phone_number,
     full_name,
     role,
-    hotel_id
+    hotel_id,
+    chain_id
 ) VALUES (
     @username::text, 
     @password::text,
@@ -43,7 +51,8 @@ INSERT INTO users (
     @phone_number::text,
     @full_name::text,
     @role::role_enum,
-    @hotel_id::uuid
+    @hotel_id::uuid,
+    @chain_id::uuid
 );
 
 -- name: UpdateUserById :exec
@@ -54,10 +63,18 @@ SET
     address = @address::text,
     email = @email::text,
     phone_number = @phone_number::text,
-    full_name = @full_name::text,
-    role = @role::role_enum,
-    hotel_id = @hotel_id::uuid
+    full_name = @full_name::text
 WHERE id = @id::uuid;
 
+-- name: SetUserRole :one
+-- Role of the user with the hotel of a MANAGER or the chain of a CHAIN_MANAGER, set by ADMIN only
+UPDATE users
+SET
+    role = @role::role_enum,
+    hotel_id = @hotel_id::uuid,
+    chain_id = @chain_id::uuid
+WHERE id = @id::uuid
+RETURNING *;
+
 -- name: DeleteUserById :exec
 DELETE FROM users WHERE id = $1;
\ No newline at end of file
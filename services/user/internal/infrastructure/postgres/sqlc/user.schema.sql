@@ -1,7 +1,9 @@
 CREATE TYPE role_enum AS ENUM (
     'GUEST',
     'ADMIN',
-    'MANAGER'
+    'MANAGER',
+    -- Manages every hotel of the chain in chain_id
+    'CHAIN_MANAGER'
 );
 
 CREATE TABLE users (
@@ -13,5 +15,7 @@ CREATE TABLE users (
     phone_number VARCHAR(255) NOT NULL UNIQUE,
     full_name VARCHAR(255) NOT NULL,
     role role_enum NOT NULL DEFAULT 'GUEST',
-    hotel_id UUID DEFAULT NULL
+    hotel_id UUID DEFAULT NULL,
+    -- Hotel chain of a CHAIN_MANAGER, chains live in the hotel service
+    chain_id UUID DEFAULT NULL
 );
\ No newline at end of file
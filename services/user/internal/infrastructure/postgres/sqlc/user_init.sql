@@ -1,7 +1,9 @@
 CREATE TYPE role_enum AS ENUM (
     'GUEST',
     'ADMIN',
-    'MANAGER'
+    'MANAGER',
+    -- Manages every hotel of the chain in chain_id
+    'CHAIN_MANAGER'
 );
 
 CREATE TABLE users (
@@ -13,9 +15,67 @@ CREATE TABLE users (
     phone_number VARCHAR(255) NOT NULL UNIQUE,
     full_name VARCHAR(255) NOT NULL,
     role role_enum NOT NULL DEFAULT 'GUEST',
-    hotel_id TEXT DEFAULT NULL
+    hotel_id TEXT DEFAULT NULL,
+    -- Hotel chain of a CHAIN_MANAGER, chains live in the hotel service
+    chain_id UUID DEFAULT NULL
 );
 
 INSERT INTO users (id, username, password, address, email, phone_number, full_name, role, hotel_id) VALUES
 ('2d236bcf-15bb-43ac-a6d0-8105c14e902a','john_doe', '$2a$10$jCyE90CnRHDm4YiTN.6/beXQ5jfUUgVr.IPul0hOVyHaB38T9vktS', 'Ong Trang', 'jd@as.com', '1234567890', 'John Doe', 'GUEST', NULL),
-('395901b6-5dd5-44b0-885e-859c0bfc7dee','kim_lim', '$2a$10$jCyE90CnRHDm4YiTN.6/beXQ5jfUUgVr.IPul0hOVyHaB38T9vktS', 'Ong Trang', 'kl@as.com', '1902345678', 'Kim Lim', 'GUEST', NULL);
\ No newline at end of file
+('395901b6-5dd5-44b0-885e-859c0bfc7dee','kim_lim', '$2a$10$jCyE90CnRHDm4YiTN.6/beXQ5jfUUgVr.IPul0hOVyHaB38T9vktS', 'Ong Trang', 'kl@as.com', '1902345678', 'Kim Lim', 'GUEST', NULL);
+
+-- Manager of the Cali Group chain seeded in the hotel service
+INSERT INTO users (id, username, password, address, email, phone_number, full_name, role, chain_id) VALUES
+('8b0e4f1a-3c7d-4e29-a6b5-91d2c4f7e803','cali_group', '$2a$10$jCyE90CnRHDm4YiTN.6/beXQ5jfUUgVr.IPul0hOVyHaB38T9vktS', 'Hồ Chí Minh', 'cg@as.com', '1903456789', 'Cali Group', 'CHAIN_MANAGER', '6f1c2b8e-4a0d-4c3e-9b57-2d8e1f0a7c31');
+
+CREATE TYPE company_rate_type AS ENUM (
+    'FIXED_PRICE',
+    'DISCOUNT_PERCENT'
+);
+
+-- B2B customer, bookings of its members are billed to the company
+CREATE TABLE companies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    billing_email VARCHAR(255) NOT NULL,
+    billing_address TEXT NOT NULL DEFAULT '',
+    -- Most the company may owe for billed bookings
+    credit_limit INT NOT NULL DEFAULT 0 CHECK (credit_limit >= 0),
+    -- Billed bookings not paid yet
+    outstanding_balance INT NOT NULL DEFAULT 0 CHECK (outstanding_balance >= 0),
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user is a member of at most one company
+CREATE TABLE company_members (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Negotiated nightly rate of a room type, or of every room type of the hotel when room_type_id is empty
+CREATE TABLE company_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    hotel_id UUID NOT NULL,
+    room_type_id UUID,
+    rate_type company_rate_type NOT NULL,
+    -- Nightly price for FIXED_PRICE, percent off the room type price for DISCOUNT_PERCENT
+    value INT NOT NULL CHECK (value >= 0),
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (rate_type <> 'DISCOUNT_PERCENT' OR value <= 100)
+);
+
+CREATE UNIQUE INDEX company_rates_room_type_idx ON company_rates (company_id, room_type_id) WHERE room_type_id IS NOT NULL;
+CREATE UNIQUE INDEX company_rates_hotel_idx ON company_rates (company_id, hotel_id) WHERE room_type_id IS NULL;
+
+-- Payment of billed bookings received from the company, reduces its outstanding balance
+CREATE TABLE company_settlements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL REFERENCES companies(id),
+    amount INT NOT NULL CHECK (amount > 0),
+    -- Bank transfer or invoice number
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    create_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -17,6 +17,7 @@ func FromUserRepoToUserDomain(userRepo user_repo.User) user_domain.User {
 		Email:       userRepo.Email,
 		Role:        string(userRepo.Role),
 		HotelId:     userRepo.HotelID.String(),
+		ChainId:     userRepo.ChainID.String(),
 	}
 }
 
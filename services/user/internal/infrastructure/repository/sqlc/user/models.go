@@ -11,12 +11,55 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CompanyRateType string
+
+const (
+	CompanyRateTypeFIXEDPRICE      CompanyRateType = "FIXED_PRICE"
+	CompanyRateTypeDISCOUNTPERCENT CompanyRateType = "DISCOUNT_PERCENT"
+)
+
+func (e *CompanyRateType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CompanyRateType(s)
+	case string:
+		*e = CompanyRateType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CompanyRateType: %T", src)
+	}
+	return nil
+}
+
+type NullCompanyRateType struct {
+	CompanyRateType CompanyRateType `json:"company_rate_type"`
+	Valid           bool            `json:"valid"` // Valid is true if CompanyRateType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCompanyRateType) Scan(value interface{}) error {
+	if value == nil {
+		ns.CompanyRateType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CompanyRateType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCompanyRateType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CompanyRateType), nil
+}
+
 type RoleEnum string
 
 const (
-	RoleEnumGUEST   RoleEnum = "GUEST"
-	RoleEnumADMIN   RoleEnum = "ADMIN"
-	RoleEnumMANAGER RoleEnum = "MANAGER"
+	RoleEnumGUEST        RoleEnum = "GUEST"
+	RoleEnumADMIN        RoleEnum = "ADMIN"
+	RoleEnumMANAGER      RoleEnum = "MANAGER"
+	RoleEnumCHAINMANAGER RoleEnum = "CHAIN_MANAGER"
 )
 
 func (e *RoleEnum) Scan(src interface{}) error {
@@ -54,6 +97,41 @@ func (ns NullRoleEnum) Value() (driver.Value, error) {
 	return string(ns.RoleEnum), nil
 }
 
+type Company struct {
+	ID                 pgtype.UUID      `json:"id"`
+	Name               string           `json:"name"`
+	BillingEmail       string           `json:"billing_email"`
+	BillingAddress     string           `json:"billing_address"`
+	CreditLimit        int32            `json:"credit_limit"`
+	OutstandingBalance int32            `json:"outstanding_balance"`
+	CreateAt           pgtype.Timestamp `json:"create_at"`
+	UpdatedAt          pgtype.Timestamp `json:"updated_at"`
+}
+
+type CompanyMember struct {
+	UserID    pgtype.UUID      `json:"user_id"`
+	CompanyID pgtype.UUID      `json:"company_id"`
+	CreateAt  pgtype.Timestamp `json:"create_at"`
+}
+
+type CompanyRate struct {
+	ID         pgtype.UUID      `json:"id"`
+	CompanyID  pgtype.UUID      `json:"company_id"`
+	HotelID    pgtype.UUID      `json:"hotel_id"`
+	RoomTypeID pgtype.UUID      `json:"room_type_id"`
+	RateType   CompanyRateType  `json:"rate_type"`
+	Value      int32            `json:"value"`
+	CreateAt   pgtype.Timestamp `json:"create_at"`
+}
+
+type CompanySettlement struct {
+	ID        pgtype.UUID      `json:"id"`
+	CompanyID pgtype.UUID      `json:"company_id"`
+	Amount    int32            `json:"amount"`
+	Reference string           `json:"reference"`
+	CreateAt  pgtype.Timestamp `json:"create_at"`
+}
+
 type User struct {
 	ID          pgtype.UUID `json:"id"`
 	Username    string      `json:"username"`
@@ -64,4 +142,5 @@ type User struct {
 	FullName    string      `json:"full_name"`
 	Role        RoleEnum    `json:"role"`
 	HotelID     pgtype.UUID `json:"hotel_id"`
+	ChainID     pgtype.UUID `json:"chain_id"`
 }
@@ -17,7 +17,9 @@ SELECT
     username,
     password,
     email,
-    role
+    role,
+    hotel_id,
+    chain_id
 FROM users WHERE username = $1
 `
 
@@ -27,6 +29,8 @@ type CheckUserByUsernameRow struct {
 	Password string      `json:"password"`
 	Email    string      `json:"email"`
 	Role     RoleEnum    `json:"role"`
+	HotelID  pgtype.UUID `json:"hotel_id"`
+	ChainID  pgtype.UUID `json:"chain_id"`
 }
 
 func (q *Queries) CheckUserByUsername(ctx context.Context, username string) (CheckUserByUsernameRow, error) {
@@ -38,6 +42,8 @@ func (q *Queries) CheckUserByUsername(ctx context.Context, username string) (Che
 		&i.Password,
 		&i.Email,
 		&i.Role,
+		&i.HotelID,
+		&i.ChainID,
 	)
 	return i, err
 }
@@ -66,7 +72,8 @@ INSERT INTO users (
     phone_number,
     full_name,
     role,
-    hotel_id
+    hotel_id,
+    chain_id
 ) VALUES (
     $1::text, 
     $2::text,
@@ -75,7 +82,8 @@ INSERT INTO users (
     $5::text,
     $6::text,
     $7::role_enum,
-    $8::uuid
+    $8::uuid,
+    $9::uuid
 )
 `
 
@@ -88,6 +96,7 @@ type CreateUserParams struct {
 	FullName    string      `json:"full_name"`
 	Role        RoleEnum    `json:"role"`
 	HotelID     pgtype.UUID `json:"hotel_id"`
+	ChainID     pgtype.UUID `json:"chain_id"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {
@@ -100,6 +109,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {
 		arg.FullName,
 		arg.Role,
 		arg.HotelID,
+		arg.ChainID,
 	)
 	return err
 }
@@ -114,7 +124,7 @@ func (q *Queries) DeleteUserById(ctx context.Context, id pgtype.UUID) error {
 }
 
 const getUserById = `-- name: GetUserById :one
-SELECT id, username, password, address, email, phone_number, full_name, role, hotel_id FROM users WHERE id = $1
+SELECT id, username, password, address, email, phone_number, full_name, role, hotel_id, chain_id FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserById(ctx context.Context, id pgtype.UUID) (User, error) {
@@ -130,18 +140,17 @@ func (q *Queries) GetUserById(ctx context.Context, id pgtype.UUID) (User, error)
 		&i.FullName,
 		&i.Role,
 		&i.HotelID,
+		&i.ChainID,
 	)
 	return i, err
 }
 
-const getUsers = `-- name: GetUsers :many
-SELECT id, username, password, address, email, phone_number, full_name, role, hotel_id FROM users
-ORDER BY hotel_id
-LIMIT 20
+const getUsersByIds = `-- name: GetUsersByIds :many
+SELECT id, username, password, address, email, phone_number, full_name, role, hotel_id, chain_id FROM users WHERE id = ANY($1::uuid[])
 `
 
-func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.Query(ctx, getUsers)
+func (q *Queries) GetUsersByIds(ctx context.Context, ids []pgtype.UUID) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsersByIds, ids)
 	if err != nil {
 		return nil, err
 	}
@@ -159,6 +168,7 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 			&i.FullName,
 			&i.Role,
 			&i.HotelID,
+			&i.ChainID,
 		); err != nil {
 			return nil, err
 		}
@@ -170,33 +180,36 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
-const getUsersByIds = `-- name: GetUsersByIds :many
-SELECT id, username, password, address, email, phone_number, full_name, role, hotel_id FROM users WHERE id = ANY($1::uuid[])
+const searchUserIdsByFullName = `-- name: SearchUserIdsByFullName :many
+SELECT id
+FROM users
+WHERE
+    full_name ILIKE '%' || $1::text || '%'
+    AND ($2::uuid IS NULL OR id > $2::uuid)
+ORDER BY id
+LIMIT $3::int
 `
 
-func (q *Queries) GetUsersByIds(ctx context.Context, ids []pgtype.UUID) ([]User, error) {
-	rows, err := q.db.Query(ctx, getUsersByIds, ids)
+type SearchUserIdsByFullNameParams struct {
+	FullName string      `json:"full_name"`
+	AfterID  pgtype.UUID `json:"after_id"`
+	PageSize int32       `json:"page_size"`
+}
+
+// Keyset page ordered by id, a NULL after_id starts from the first user
+func (q *Queries) SearchUserIdsByFullName(ctx context.Context, arg SearchUserIdsByFullNameParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, searchUserIdsByFullName, arg.FullName, arg.AfterID, arg.PageSize)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []pgtype.UUID
 	for rows.Next() {
-		var i User
-		if err := rows.Scan(
-			&i.ID,
-			&i.Username,
-			&i.Password,
-			&i.Address,
-			&i.Email,
-			&i.PhoneNumber,
-			&i.FullName,
-			&i.Role,
-			&i.HotelID,
-		); err != nil {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, id)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -204,6 +217,47 @@ func (q *Queries) GetUsersByIds(ctx context.Context, ids []pgtype.UUID) ([]User,
 	return items, nil
 }
 
+const setUserRole = `-- name: SetUserRole :one
+UPDATE users
+SET
+    role = $1::role_enum,
+    hotel_id = $2::uuid,
+    chain_id = $3::uuid
+WHERE id = $4::uuid
+RETURNING id, username, password, address, email, phone_number, full_name, role, hotel_id, chain_id
+`
+
+type SetUserRoleParams struct {
+	Role    RoleEnum    `json:"role"`
+	HotelID pgtype.UUID `json:"hotel_id"`
+	ChainID pgtype.UUID `json:"chain_id"`
+	ID      pgtype.UUID `json:"id"`
+}
+
+// Role of the user with the hotel of a MANAGER or the chain of a CHAIN_MANAGER, set by ADMIN only
+func (q *Queries) SetUserRole(ctx context.Context, arg SetUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserRole,
+		arg.Role,
+		arg.HotelID,
+		arg.ChainID,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Password,
+		&i.Address,
+		&i.Email,
+		&i.PhoneNumber,
+		&i.FullName,
+		&i.Role,
+		&i.HotelID,
+		&i.ChainID,
+	)
+	return i, err
+}
+
 const updateUserById = `-- name: UpdateUserById :exec
 UPDATE users
 SET
@@ -212,10 +266,8 @@ SET
     address = $3::text,
     email = $4::text,
     phone_number = $5::text,
-    full_name = $6::text,
-    role = $7::role_enum,
-    hotel_id = $8::uuid
-WHERE id = $9::uuid
+    full_name = $6::text
+WHERE id = $7::uuid
 `
 
 type UpdateUserByIdParams struct {
@@ -225,8 +277,6 @@ type UpdateUserByIdParams struct {
 	Email       string      `json:"email"`
 	PhoneNumber string      `json:"phone_number"`
 	FullName    string      `json:"full_name"`
-	Role        RoleEnum    `json:"role"`
-	HotelID     pgtype.UUID `json:"hotel_id"`
 	ID          pgtype.UUID `json:"id"`
 }
 
@@ -238,8 +288,6 @@ func (q *Queries) UpdateUserById(ctx context.Context, arg UpdateUserByIdParams)
 		arg.Email,
 		arg.PhoneNumber,
 		arg.FullName,
-		arg.Role,
-		arg.HotelID,
 		arg.ID,
 	)
 	return err
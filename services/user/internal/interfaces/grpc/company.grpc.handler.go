@@ -0,0 +1,371 @@
+package user_handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	common_error "github.com/098765432m/grpc-kafka/common/error"
+	"github.com/098765432m/grpc-kafka/common/gen-proto/user_pb"
+	"github.com/098765432m/grpc-kafka/common/utils"
+	user_service "github.com/098765432m/grpc-kafka/user/internal/application"
+	user_repo "github.com/098765432m/grpc-kafka/user/internal/infrastructure/repository/sqlc/user"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (ug *UserGrpcHandler) CreateCompany(ctx context.Context, req *user_pb.CreateCompanyRequest) (*user_pb.Company, error) {
+
+	company, err := ug.service.CreateCompany(ctx, &user_service.CompanyParams{
+		Name:           req.GetName(),
+		BillingEmail:   req.GetBillingEmail(),
+		BillingAddress: req.GetBillingAddress(),
+		CreditLimit:    int(req.GetCreditLimit()),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Thong tin cong ty khong hop le")
+		case errors.Is(err, common_error.ErrDuplicateRecord):
+			return nil, status.Error(codes.AlreadyExists, "Cong ty da ton tai")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	return toCompanyPb(*company), nil
+}
+
+func (ug *UserGrpcHandler) GetCompanyById(ctx context.Context, req *user_pb.GetCompanyByIdRequest) (*user_pb.Company, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	company, err := ug.service.GetCompanyById(ctx, id)
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Cong ty khong ton tai")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	return toCompanyPb(*company), nil
+}
+
+func (ug *UserGrpcHandler) UpdateCompanyById(ctx context.Context, req *user_pb.UpdateCompanyByIdRequest) (*user_pb.Company, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	company, err := ug.service.UpdateCompanyById(ctx, id, &user_service.CompanyParams{
+		Name:           req.GetName(),
+		BillingEmail:   req.GetBillingEmail(),
+		BillingAddress: req.GetBillingAddress(),
+		CreditLimit:    int(req.GetCreditLimit()),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Thong tin cong ty khong hop le")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Cong ty khong ton tai")
+		case errors.Is(err, common_error.ErrDuplicateRecord):
+			return nil, status.Error(codes.AlreadyExists, "Cong ty da ton tai")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	return toCompanyPb(*company), nil
+}
+
+// NotFound when the user is not a member of any company
+func (ug *UserGrpcHandler) GetCompanyByUserId(ctx context.Context, req *user_pb.GetCompanyByUserIdRequest) (*user_pb.GetCompanyByUserIdResponse, error) {
+
+	var userId pgtype.UUID
+	if err := userId.Scan(req.GetUserId()); err != nil {
+		zap.S().Infoln("Invalid User UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "UUID khong hop le")
+	}
+
+	company, rates, err := ug.service.GetCompanyByUserId(ctx, userId)
+	if err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Tai khoan khong thuoc cong ty nao")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	results := make([]*user_pb.CompanyRate, 0, len(rates))
+	for _, rate := range rates {
+		results = append(results, toCompanyRatePb(rate))
+	}
+
+	return &user_pb.GetCompanyByUserIdResponse{
+		Company: toCompanyPb(*company),
+		Rates:   results,
+	}, nil
+}
+
+// ResourceExhausted when the charge is over the credit limit of the company
+func (ug *UserGrpcHandler) ChargeCompanyCredit(ctx context.Context, req *user_pb.ChargeCompanyCreditRequest) (*user_pb.ChargeCompanyCreditResponse, error) {
+
+	var companyId pgtype.UUID
+	if err := companyId.Scan(req.GetCompanyId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	if err := ug.service.ChargeCompanyCredit(ctx, companyId, int(req.GetAmount())); err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Cong ty khong ton tai")
+		case errors.Is(err, user_service.ErrCreditLimitExceeded):
+			return nil, status.Error(codes.ResourceExhausted, "Vuot qua han muc tin dung cua cong ty")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	return &user_pb.ChargeCompanyCreditResponse{}, nil
+}
+
+// FailedPrecondition when the amount is more than the outstanding balance
+func (ug *UserGrpcHandler) SettleCompanyBalance(ctx context.Context, req *user_pb.SettleCompanyBalanceRequest) (*user_pb.Company, error) {
+
+	var companyId pgtype.UUID
+	if err := companyId.Scan(req.GetCompanyId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	company, err := ug.service.SettleCompanyBalance(ctx, companyId, int(req.GetAmount()), req.GetReference())
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "So tien thanh toan khong hop le")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Cong ty khong ton tai")
+		case errors.Is(err, user_service.ErrSettlementExceedsBalance):
+			return nil, status.Error(codes.FailedPrecondition, "So tien thanh toan vuot qua du no cua cong ty")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	return toCompanyPb(*company), nil
+}
+
+func (ug *UserGrpcHandler) AddCompanyMember(ctx context.Context, req *user_pb.AddCompanyMemberRequest) (*user_pb.AddCompanyMemberResponse, error) {
+
+	var companyId pgtype.UUID
+	if err := companyId.Scan(req.GetCompanyId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	var userId pgtype.UUID
+	if err := userId.Scan(req.GetUserId()); err != nil {
+		zap.S().Infoln("Invalid User UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "UUID khong hop le")
+	}
+
+	if err := ug.service.AddCompanyMember(ctx, companyId, userId); err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Tai khoan hoac cong ty khong ton tai")
+		case errors.Is(err, common_error.ErrDuplicateRecord):
+			return nil, status.Error(codes.AlreadyExists, "Tai khoan da thuoc mot cong ty")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	return &user_pb.AddCompanyMemberResponse{}, nil
+}
+
+func (ug *UserGrpcHandler) RemoveCompanyMember(ctx context.Context, req *user_pb.RemoveCompanyMemberRequest) (*user_pb.RemoveCompanyMemberResponse, error) {
+
+	var companyId pgtype.UUID
+	if err := companyId.Scan(req.GetCompanyId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	var userId pgtype.UUID
+	if err := userId.Scan(req.GetUserId()); err != nil {
+		zap.S().Infoln("Invalid User UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "UUID khong hop le")
+	}
+
+	if err := ug.service.RemoveCompanyMember(ctx, companyId, userId); err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Tai khoan khong thuoc cong ty")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	return &user_pb.RemoveCompanyMemberResponse{}, nil
+}
+
+func (ug *UserGrpcHandler) GetCompanyMembers(ctx context.Context, req *user_pb.GetCompanyMembersRequest) (*user_pb.GetCompanyMembersResponse, error) {
+
+	var companyId pgtype.UUID
+	if err := companyId.Scan(req.GetCompanyId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	members, pageInfo, err := ug.service.GetCompanyMembers(ctx, companyId, utils.ToPageParams(req.GetPage()))
+	if err != nil {
+		if errors.Is(err, common_error.ErrBadRequest) {
+			return nil, status.Error(codes.InvalidArgument, "Trang khong hop le")
+		}
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	results := make([]*user_pb.CompanyMember, 0, len(members))
+	for _, member := range members {
+		results = append(results, &user_pb.CompanyMember{
+			UserId:   member.UserId,
+			Username: member.Username,
+			Email:    member.Email,
+			FullName: member.FullName,
+			JoinedAt: member.JoinedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &user_pb.GetCompanyMembersResponse{
+		Members: results,
+		Page:    utils.ToPageResponsePb(pageInfo),
+	}, nil
+}
+
+func (ug *UserGrpcHandler) CreateCompanyRate(ctx context.Context, req *user_pb.CreateCompanyRateRequest) (*user_pb.CompanyRate, error) {
+
+	var companyId pgtype.UUID
+	if err := companyId.Scan(req.GetCompanyId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	var hotelId pgtype.UUID
+	if err := hotelId.Scan(req.GetHotelId()); err != nil {
+		zap.S().Infoln("Invalid Hotel UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Hotel UUID khong hop le")
+	}
+
+	// Empty Room Type is a rate for the whole hotel
+	var roomTypeId pgtype.UUID
+	if req.GetRoomTypeId() != "" {
+		if err := roomTypeId.Scan(req.GetRoomTypeId()); err != nil {
+			zap.S().Infoln("Invalid Room Type UUID: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Room Type UUID khong hop le")
+		}
+	}
+
+	rate, err := ug.service.CreateCompanyRate(ctx, &user_service.CreateCompanyRateParams{
+		CompanyId:  companyId,
+		HotelId:    hotelId,
+		RoomTypeId: roomTypeId,
+		RateType:   user_repo.CompanyRateType(strings.ToUpper(req.GetRateType())),
+		Value:      int(req.GetValue()),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Gia thoa thuan khong hop le")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Cong ty khong ton tai")
+		case errors.Is(err, common_error.ErrDuplicateRecord):
+			return nil, status.Error(codes.AlreadyExists, "Gia thoa thuan da ton tai")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	return toCompanyRatePb(*rate), nil
+}
+
+func (ug *UserGrpcHandler) GetCompanyRatesByCompanyId(ctx context.Context, req *user_pb.GetCompanyRatesByCompanyIdRequest) (*user_pb.GetCompanyRatesByCompanyIdResponse, error) {
+
+	var companyId pgtype.UUID
+	if err := companyId.Scan(req.GetCompanyId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	rates, err := ug.service.GetCompanyRatesByCompanyId(ctx, companyId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	results := make([]*user_pb.CompanyRate, 0, len(rates))
+	for _, rate := range rates {
+		results = append(results, toCompanyRatePb(rate))
+	}
+
+	return &user_pb.GetCompanyRatesByCompanyIdResponse{
+		Rates: results,
+	}, nil
+}
+
+func (ug *UserGrpcHandler) DeleteCompanyRate(ctx context.Context, req *user_pb.DeleteCompanyRateRequest) (*user_pb.DeleteCompanyRateResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetId()); err != nil {
+		zap.S().Infoln("Invalid Company rate UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Rate UUID khong hop le")
+	}
+
+	var companyId pgtype.UUID
+	if err := companyId.Scan(req.GetCompanyId()); err != nil {
+		zap.S().Infoln("Invalid Company UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "Company UUID khong hop le")
+	}
+
+	if err := ug.service.DeleteCompanyRate(ctx, id, companyId); err != nil {
+		if errors.Is(err, common_error.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Gia thoa thuan khong ton tai")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	return &user_pb.DeleteCompanyRateResponse{}, nil
+}
+
+func toCompanyPb(company user_repo.Company) *user_pb.Company {
+	return &user_pb.Company{
+		Id:                 company.ID.String(),
+		Name:               company.Name,
+		BillingEmail:       company.BillingEmail,
+		BillingAddress:     company.BillingAddress,
+		CreditLimit:        company.CreditLimit,
+		OutstandingBalance: company.OutstandingBalance,
+	}
+}
+
+func toCompanyRatePb(rate user_repo.CompanyRate) *user_pb.CompanyRate {
+	return &user_pb.CompanyRate{
+		Id:         rate.ID.String(),
+		CompanyId:  rate.CompanyID.String(),
+		HotelId:    rate.HotelID.String(),
+		RoomTypeId: rate.RoomTypeID.String(),
+		RateType:   string(rate.RateType),
+		Value:      rate.Value,
+	}
+}
@@ -53,6 +53,7 @@ func (ug *UserGrpcHandler) GetUserById(ctx context.Context, req *user_pb.GetUser
 			PhoneNumber: user.PhoneNumber,
 			Role:        string(user.Role),
 			HotelId:     user.HotelId,
+			ChainId:     user.ChainId,
 		},
 	}, nil
 }
@@ -86,6 +87,7 @@ func (ug *UserGrpcHandler) GetUsersByIds(ctx context.Context, req *user_pb.GetUs
 			PhoneNumber: user.PhoneNumber,
 			FullName:    user.FullName,
 			HotelId:     user.HotelId,
+			ChainId:     user.ChainId,
 			Role:        string(user.Role),
 		}
 		usersGrpcResult = append(usersGrpcResult, userGrpcResult)
@@ -96,6 +98,40 @@ func (ug *UserGrpcHandler) GetUsersByIds(ctx context.Context, req *user_pb.GetUs
 	}, nil
 }
 
+func (ug *UserGrpcHandler) SearchUserIdsByFullName(ctx context.Context, req *user_pb.SearchUserIdsByFullNameRequest) (*user_pb.SearchUserIdsByFullNameResponse, error) {
+	if req.GetFullName() == "" {
+		zap.S().Info("Empty full name to search")
+		return nil, status.Error(codes.InvalidArgument, "Ten khach hang khong hop le")
+	}
+
+	var afterId pgtype.UUID
+	if req.GetAfterId() != "" {
+		if err := afterId.Scan(req.GetAfterId()); err != nil {
+			zap.S().Info("Invalid after id: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Cursor khong hop le")
+		}
+	}
+
+	ids, nextAfterId, err := ug.service.SearchUserIdsByFullName(ctx, req.GetFullName(), afterId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Loi he thong")
+	}
+
+	results := make([]string, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, id.String())
+	}
+
+	resp := &user_pb.SearchUserIdsByFullNameResponse{
+		Ids: results,
+	}
+	if nextAfterId.Valid {
+		resp.NextAfterId = nextAfterId.String()
+	}
+
+	return resp, nil
+}
+
 func (ug *UserGrpcHandler) CreateUser(ctx context.Context, req *user_pb.CreateUserRequest) (*user_pb.CreateUserResponse, error) {
 
 	var hotelId pgtype.UUID
@@ -106,6 +142,14 @@ func (ug *UserGrpcHandler) CreateUser(ctx context.Context, req *user_pb.CreateUs
 		}
 	}
 
+	var chainId pgtype.UUID
+	if req.GetChainId() != "" {
+		if err := chainId.Scan(req.GetChainId()); err != nil {
+			zap.S().Infoln("Invalid Hotel Chain UUID: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Loi UUID chuoi khach san")
+		}
+	}
+
 	err := ug.service.CreateUser(ctx, &user_repo.CreateUserParams{
 		Username:    req.Username,
 		Password:    req.Password,
@@ -114,6 +158,7 @@ func (ug *UserGrpcHandler) CreateUser(ctx context.Context, req *user_pb.CreateUs
 		FullName:    req.FullName,
 		Role:        user_repo.RoleEnum(req.Role),
 		HotelID:     hotelId,
+		ChainID:     chainId,
 	})
 	if err != nil {
 		switch {
@@ -127,39 +172,23 @@ func (ug *UserGrpcHandler) CreateUser(ctx context.Context, req *user_pb.CreateUs
 	return &user_pb.CreateUserResponse{}, nil
 }
 
+// Profile of the user only, role, hotel and chain are set by SetUserRole
 func (ug *UserGrpcHandler) UpdateUserById(ctx context.Context, req *user_pb.UpdateUserByIdRequest) (*user_pb.UpdateUserByIdResponse, error) {
 
 	var id pgtype.UUID
-	if req.GetUser().Id == "" {
-		if err := id.Scan(req.GetUser().Id); err != nil {
-			zap.S().Infoln("Invalid User UUID: ", err)
-			return nil, status.Error(codes.InvalidArgument, "Loi he thong")
-		}
-	}
-
-	var hotelId pgtype.UUID
-	if req.GetUser().GetHotelId() == "" {
-		if err := hotelId.Scan(req.GetUser().GetHotelId()); err != nil {
-			zap.S().Infoln("Invalid Hotel UUID: ", err)
-			return nil, status.Error(codes.InvalidArgument, "Loi he thong")
-		}
-	}
-
-	// Default Role if empty
-	var newRole user_repo.RoleEnum
-	if req.User.GetRole() == "" {
-		newRole = user_repo.RoleEnumGUEST
+	if err := id.Scan(req.GetUser().GetId()); err != nil {
+		zap.S().Infoln("Invalid User UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "User UUID khong hop le")
 	}
 
 	err := ug.service.UpdateUserById(ctx, &user_repo.UpdateUserByIdParams{
 		ID:          id,
 		Username:    req.User.Username,
 		Password:    req.User.Password,
+		Address:     req.User.Address,
 		Email:       req.User.Email,
 		PhoneNumber: req.User.PhoneNumber,
 		FullName:    req.User.FullName,
-		Role:        newRole,
-		HotelID:     hotelId,
 	})
 	if err != nil {
 		switch {
@@ -175,6 +204,62 @@ func (ug *UserGrpcHandler) UpdateUserById(ctx context.Context, req *user_pb.Upda
 	return &user_pb.UpdateUserByIdResponse{}, nil
 }
 
+func (ug *UserGrpcHandler) SetUserRole(ctx context.Context, req *user_pb.SetUserRoleRequest) (*user_pb.SetUserRoleResponse, error) {
+
+	var id pgtype.UUID
+	if err := id.Scan(req.GetUserId()); err != nil {
+		zap.S().Infoln("Invalid User UUID: ", err)
+		return nil, status.Error(codes.InvalidArgument, "User UUID khong hop le")
+	}
+
+	var hotelId pgtype.UUID
+	if req.GetHotelId() != "" {
+		if err := hotelId.Scan(req.GetHotelId()); err != nil {
+			zap.S().Infoln("Invalid Hotel UUID: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Loi UUID khach san")
+		}
+	}
+
+	var chainId pgtype.UUID
+	if req.GetChainId() != "" {
+		if err := chainId.Scan(req.GetChainId()); err != nil {
+			zap.S().Infoln("Invalid Hotel Chain UUID: ", err)
+			return nil, status.Error(codes.InvalidArgument, "Loi UUID chuoi khach san")
+		}
+	}
+
+	user, err := ug.service.SetUserRole(ctx, user_repo.SetUserRoleParams{
+		ID:      id,
+		Role:    user_repo.RoleEnum(req.GetRole()),
+		HotelID: hotelId,
+		ChainID: chainId,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, common_error.ErrBadRequest):
+			return nil, status.Error(codes.InvalidArgument, "Vai tro khong hop le voi khach san hoac chuoi khach san")
+		case errors.Is(err, common_error.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "Tai khoan khong ton tai")
+		}
+
+		return nil, status.Error(codes.Internal, "Loi khong cap nhat duoc vai tro")
+	}
+
+	return &user_pb.SetUserRoleResponse{
+		User: &user_pb.User{
+			Id:          user.Id,
+			Username:    user.Username,
+			Email:       user.Email,
+			PhoneNumber: user.PhoneNumber,
+			FullName:    user.FullName,
+			Address:     user.Address,
+			Role:        user.Role,
+			HotelId:     user.HotelId,
+			ChainId:     user.ChainId,
+		},
+	}, nil
+}
+
 func (ug *UserGrpcHandler) DeleteUserById(ctx context.Context, req *user_pb.DeleteUserByIdRequest) (*user_pb.DeleteUserByIdResponse, error) {
 	var id pgtype.UUID
 	if err := id.Scan(req.Id); err != nil {